@@ -25,6 +25,7 @@ import (
 
 	"github.com/cheggaaa/pb"
 	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/i18n"
 	"github.com/trinet2005/oss-mc/pkg/probe"
 )
 
@@ -102,7 +103,7 @@ func (c accountStat) String() string {
 	} else {
 		speedBox = speedBox + "/s"
 	}
-	message := fmt.Sprintf("Total: %s, Transferred: %s, Speed: %s", pb.Format(c.Total).To(pb.U_BYTES),
+	message := fmt.Sprintf(i18n.T("Total: %s, Transferred: %s, Speed: %s"), pb.Format(c.Total).To(pb.U_BYTES),
 		pb.Format(c.Transferred).To(pb.U_BYTES), speedBox)
 	return message
 }
@@ -0,0 +1,150 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+// activeHoursFlag restricts the transfers of a long running
+// command to a daily time window.
+var activeHoursFlag = cli.StringFlag{
+	Name:  "active-hours",
+	Usage: "only start transfers within a daily window of local time, e.g. '22:00-06:00'",
+}
+
+// activeHours is a daily time window, in minutes since midnight,
+// which may span midnight. A nil *activeHours is always active.
+type activeHours struct {
+	start, end int
+	paused     bool
+}
+
+// parseActiveHours parses a window such as '22:00-06:00', returning
+// nil if s is empty.
+func parseActiveHours(s string) (*activeHours, *probe.Error) {
+	if s == "" {
+		return nil, nil
+	}
+	bounds := strings.Split(s, "-")
+	if len(bounds) != 2 {
+		return nil, probe.NewError(errors.New("active hours should be like '22:00-06:00'")).Trace(s)
+	}
+	var minutes [2]int
+	for i, bound := range bounds {
+		t, e := time.Parse("15:04", strings.TrimSpace(bound))
+		if e != nil {
+			return nil, probe.NewError(e).Trace(s)
+		}
+		minutes[i] = t.Hour()*60 + t.Minute()
+	}
+	if minutes[0] == minutes[1] {
+		return nil, probe.NewError(errors.New("active hours should not start and end at the same time")).Trace(s)
+	}
+	return &activeHours{start: minutes[0], end: minutes[1]}, nil
+}
+
+func (a *activeHours) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", a.start/60, a.start%60, a.end/60, a.end%60)
+}
+
+// contains returns true if t is within the window.
+func (a *activeHours) contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	if a.start < a.end {
+		return minute >= a.start && minute < a.end
+	}
+	return minute >= a.start || minute < a.end
+}
+
+// next returns the next start of the window after t.
+func (a *activeHours) next(t time.Time) time.Time {
+	start := time.Date(t.Year(), t.Month(), t.Day(), a.start/60, a.start%60, 0, 0, t.Location())
+	if !start.After(t) {
+		start = time.Date(t.Year(), t.Month(), t.Day()+1, a.start/60, a.start%60, 0, 0, t.Location())
+	}
+	return start
+}
+
+// wait blocks outside of the window until it starts again or ctx is
+// canceled, so that no new transfer is started. Transfers already
+// started are not interrupted.
+func (a *activeHours) wait(ctx context.Context) {
+	if a == nil {
+		return
+	}
+	for {
+		now := time.Now()
+		if a.contains(now) {
+			if a.paused {
+				a.paused = false
+				printMsg(activeHoursMessage{Window: a.String(), Time: now})
+			}
+			return
+		}
+		next := a.next(now)
+		if !a.paused {
+			a.paused = true
+			printMsg(activeHoursMessage{Window: a.String(), Time: now, Paused: true, ResumeAt: &next})
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// activeHoursMessage is printed when transfers are paused outside of
+// the active hours, and when they resume.
+type activeHoursMessage struct {
+	Status   string     `json:"status"`
+	Window   string     `json:"activeHours"`
+	Time     time.Time  `json:"time"`
+	Paused   bool       `json:"paused"`
+	ResumeAt *time.Time `json:"resumeAt,omitempty"`
+}
+
+// String colorized active hours message
+func (a activeHoursMessage) String() string {
+	if a.Paused {
+		return console.Colorize("ActiveHours", fmt.Sprintf("Outside of active hours %s, paused until %s.",
+			a.Window, a.ResumeAt.Format(printDate)))
+	}
+	return console.Colorize("ActiveHours", fmt.Sprintf("Within active hours %s, resumed.", a.Window))
+}
+
+// JSON jsonified active hours message
+func (a activeHoursMessage) JSON() string {
+	a.Status = "success"
+	msgBytes, e := json.MarshalIndent(a, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(msgBytes)
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestActiveHours(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2023, 10, day, hour, minute, 0, 0, time.UTC)
+	}
+	testCases := []struct {
+		window   string
+		t        time.Time
+		contains bool
+		next     time.Time
+	}{
+		{"22:00-06:00", at(1, 23, 0), true, at(2, 22, 0)},
+		{"22:00-06:00", at(1, 3, 0), true, at(1, 22, 0)},
+		{"22:00-06:00", at(1, 6, 0), false, at(1, 22, 0)},
+		{"22:00-06:00", at(1, 12, 30), false, at(1, 22, 0)},
+		{"22:00-06:00", at(1, 22, 0), true, at(2, 22, 0)},
+		{"09:30-17:00", at(1, 9, 29), false, at(1, 9, 30)},
+		{"09:30-17:00", at(1, 16, 59), true, at(2, 9, 30)},
+		{"09:30-17:00", at(31, 18, 0), false, time.Date(2023, 11, 1, 9, 30, 0, 0, time.UTC)},
+	}
+
+	for i, testCase := range testCases {
+		a, err := parseActiveHours(testCase.window)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		if contains := a.contains(testCase.t); contains != testCase.contains {
+			t.Errorf("Test %d: expected contains %v, got %v", i+1, testCase.contains, contains)
+		}
+		if next := a.next(testCase.t); !next.Equal(testCase.next) {
+			t.Errorf("Test %d: expected next %v, got %v", i+1, testCase.next, next)
+		}
+	}
+
+	for i, window := range []string{"22:00", "22:00-22:00", "25:00-06:00", "22-06"} {
+		if _, err := parseActiveHours(window); err == nil {
+			t.Errorf("Test %d: expected an error for %q", i+1, window)
+		}
+	}
+}
@@ -0,0 +1,490 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-admin-go"
+	minio "github.com/trinet2005/oss-go-sdk"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+var adminDriveBurnInFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "size",
+		Usage: "total amount of data to write",
+		Value: "1GiB",
+	},
+	cli.StringFlag{
+		Name:  "object-size",
+		Usage: "size of each test object",
+		Value: "16MiB",
+	},
+	cli.IntFlag{
+		Name:  "concurrent",
+		Usage: "number of test objects written and read in parallel",
+		Value: 8,
+	},
+	cli.BoolFlag{
+		Name:  "keep",
+		Usage: "do not delete the test objects at the end",
+	},
+}
+
+var adminDriveBurnInCmd = cli.Command{
+	Name:         "burn-in",
+	Usage:        "fill the drives with test data and verify it before going to production",
+	Action:       mainAdminDriveBurnIn,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(adminDriveBurnInFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET
+
+TARGET:
+  An existing bucket of the deployment, with an optional prefix. e.g ALIAS/BUCKET/PREFIX
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Test objects of pseudo-random data are written through every server of the deployment
+  in turn, read back through the same server and verified with their SHA-256 checksum.
+  The errors and the latencies are reported per server, and the state of every drive
+  and the errors they reported during the burn-in are reported per drive. The command
+  exits with an error if any object could not be written or verified, or if a drive
+  is not healthy.
+
+EXAMPLES:
+  1. Write and verify 1GiB of test data in the bucket 'burn-in' of 'myminio'.
+     {{.Prompt}} {{.HelpName}} myminio/burn-in
+
+  2. Write and verify 100GiB in objects of 64MiB, 32 at a time.
+     {{.Prompt}} {{.HelpName}} --size 100GiB --object-size 64MiB --concurrent 32 myminio/burn-in
+
+  3. Keep the test objects at the end to inspect them.
+     {{.Prompt}} {{.HelpName}} --keep myminio/burn-in/run1
+`,
+}
+
+var errBurnInCorrupted = errors.New("checksum mismatch")
+
+// burnInReader returns the pseudo-random content of a test object, the
+// same content is returned for the same seed.
+func burnInReader(seed, size int64) io.Reader {
+	return io.LimitReader(rand.New(rand.NewSource(seed)), size)
+}
+
+// burnInVerify reads r until EOF and checks it matches the SHA-256
+// checksum of the data written.
+func burnInVerify(r io.Reader, sum []byte) error {
+	h := sha256.New()
+	if _, e := io.Copy(h, r); e != nil {
+		return e
+	}
+	if !bytes.Equal(h.Sum(nil), sum) {
+		return errBurnInCorrupted
+	}
+	return nil
+}
+
+// burnInResult is the outcome of writing and reading one test object.
+type burnInResult struct {
+	node     int
+	size     int64
+	write    time.Duration
+	read     time.Duration
+	writeErr error
+	readErr  error
+}
+
+// burnInNodeStats are the stats of the test objects written through a server.
+type burnInNodeStats struct {
+	Endpoint       string        `json:"endpoint"`
+	Objects        int           `json:"objects"`
+	Bytes          int64         `json:"bytes"`
+	WriteErrors    int           `json:"writeErrors"`
+	ReadErrors     int           `json:"readErrors"`
+	ChecksumErrors int           `json:"checksumErrors"`
+	AvgWrite       time.Duration `json:"avgWriteLatency"`
+	MaxWrite       time.Duration `json:"maxWriteLatency"`
+	AvgRead        time.Duration `json:"avgReadLatency"`
+	MaxRead        time.Duration `json:"maxReadLatency"`
+	LastError      string        `json:"lastError,omitempty"`
+
+	writes, reads         int
+	totalWrite, totalRead time.Duration
+}
+
+// add accounts the result of a test object.
+func (s *burnInNodeStats) add(r burnInResult) {
+	s.Objects++
+	if r.writeErr != nil {
+		s.WriteErrors++
+		s.LastError = r.writeErr.Error()
+		return
+	}
+	s.Bytes += r.size
+	s.writes++
+	s.totalWrite += r.write
+	s.AvgWrite = s.totalWrite / time.Duration(s.writes)
+	if r.write > s.MaxWrite {
+		s.MaxWrite = r.write
+	}
+	switch {
+	case errors.Is(r.readErr, errBurnInCorrupted):
+		s.ChecksumErrors++
+		s.LastError = r.readErr.Error()
+		return
+	case r.readErr != nil:
+		s.ReadErrors++
+		s.LastError = r.readErr.Error()
+		return
+	}
+	s.reads++
+	s.totalRead += r.read
+	s.AvgRead = s.totalRead / time.Duration(s.reads)
+	if r.read > s.MaxRead {
+		s.MaxRead = r.read
+	}
+}
+
+// failed returns true if a test object through the server failed.
+func (s burnInNodeStats) failed() bool {
+	return s.WriteErrors+s.ReadErrors+s.ChecksumErrors > 0
+}
+
+// burnInDriveStats is the state of a drive and the errors it reported
+// during the burn-in.
+type burnInDriveStats struct {
+	Endpoint string        `json:"endpoint"`
+	State    string        `json:"state"`
+	Errors   uint64        `json:"errors"`
+	Latency  time.Duration `json:"avgLatency"`
+}
+
+// failed returns true if the drive is not healthy.
+func (d burnInDriveStats) failed() bool {
+	return d.State != madmin.DriveStateOk || d.Errors > 0
+}
+
+// burnInDriveErrors returns the errors reported by a drive since it started.
+func burnInDriveErrors(disk madmin.Disk) uint64 {
+	if disk.Metrics == nil {
+		return 0
+	}
+	return disk.Metrics.TotalErrorsAvailability + disk.Metrics.TotalErrorsTimeout
+}
+
+// burnInDrives returns the drives of after with the errors they reported
+// since before, sorted by endpoint.
+func burnInDrives(before, after madmin.InfoMessage) []burnInDriveStats {
+	name := func(srv madmin.ServerProperties, disk madmin.Disk) string {
+		if disk.Endpoint != "" {
+			return disk.Endpoint
+		}
+		return srv.Endpoint + disk.DrivePath
+	}
+	prevErrors := make(map[string]uint64)
+	for _, srv := range before.Servers {
+		for _, disk := range srv.Disks {
+			prevErrors[name(srv, disk)] = burnInDriveErrors(disk)
+		}
+	}
+	var drives []burnInDriveStats
+	for _, srv := range after.Servers {
+		for _, disk := range srv.Disks {
+			d := burnInDriveStats{Endpoint: name(srv, disk), State: disk.State}
+			if errs := burnInDriveErrors(disk); errs > prevErrors[d.Endpoint] {
+				d.Errors = errs - prevErrors[d.Endpoint]
+			}
+			if disk.Metrics != nil {
+				var count, accTime uint64
+				for _, action := range disk.Metrics.LastMinute {
+					count += action.Count
+					accTime += action.AccTime
+				}
+				if count > 0 {
+					d.Latency = time.Duration(accTime / count)
+				}
+			}
+			drives = append(drives, d)
+		}
+	}
+	sort.Slice(drives, func(i, j int) bool { return drives[i].Endpoint < drives[j].Endpoint })
+	return drives
+}
+
+// burnInMessage reports the outcome of a burn-in.
+type burnInMessage struct {
+	Status   string             `json:"status"`
+	Target   string             `json:"target"`
+	Duration time.Duration      `json:"duration"`
+	Nodes    []burnInNodeStats  `json:"nodes"`
+	Drives   []burnInDriveStats `json:"drives"`
+}
+
+// failed returns true if a server or a drive failed the burn-in.
+func (m burnInMessage) failed() bool {
+	for _, n := range m.Nodes {
+		if n.failed() {
+			return true
+		}
+	}
+	for _, d := range m.Drives {
+		if d.failed() {
+			return true
+		}
+	}
+	return false
+}
+
+// String colorized burn-in message
+func (m burnInMessage) String() string {
+	var b strings.Builder
+	if m.failed() {
+		b.WriteString(console.Colorize("BurnInFail", "Burn-in of "+m.Target+" failed"))
+	} else {
+		b.WriteString(console.Colorize("BurnInOK", "Burn-in of "+m.Target+" passed"))
+	}
+	fmt.Fprintf(&b, " in %s\n\n", m.Duration.Round(time.Second))
+
+	fmt.Fprintf(&b, "%s\n", console.Colorize("BurnInHeader", fmt.Sprintf("%-30s %8s %10s %7s %7s %9s %10s %10s %10s %10s",
+		"SERVER", "OBJECTS", "DATA", "WRITE", "READ", "CHECKSUM", "AVG-WRITE", "MAX-WRITE", "AVG-READ", "MAX-READ")))
+	for _, n := range m.Nodes {
+		line := fmt.Sprintf("%-30s %8d %10s %7d %7d %9d %10s %10s %10s %10s", n.Endpoint, n.Objects,
+			humanize.IBytes(uint64(n.Bytes)), n.WriteErrors, n.ReadErrors, n.ChecksumErrors,
+			n.AvgWrite.Round(time.Millisecond), n.MaxWrite.Round(time.Millisecond),
+			n.AvgRead.Round(time.Millisecond), n.MaxRead.Round(time.Millisecond))
+		if n.failed() {
+			line = console.Colorize("BurnInFail", line) + "\n  " + n.LastError
+		}
+		fmt.Fprintln(&b, line)
+	}
+
+	if len(m.Drives) > 0 {
+		fmt.Fprintf(&b, "\n%s\n", console.Colorize("BurnInHeader", fmt.Sprintf("%-50s %-12s %8s %12s", "DRIVE", "STATE", "ERRORS", "AVG-LATENCY")))
+		for _, d := range m.Drives {
+			line := fmt.Sprintf("%-50s %-12s %8d %12s", d.Endpoint, d.State, d.Errors, d.Latency.Round(time.Microsecond))
+			if d.failed() {
+				line = console.Colorize("BurnInFail", line)
+			}
+			fmt.Fprintln(&b, line)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// JSON jsonified burn-in message
+func (m burnInMessage) JSON() string {
+	m.Status = "success"
+	if m.failed() {
+		m.Status = "error"
+	}
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// burnInNodeClients returns an S3 client for every server of info, with
+// the credentials of the alias.
+func burnInNodeClients(hostCfg *aliasConfigV10, info madmin.InfoMessage) ([]string, []*S3Client, *probe.Error) {
+	u, e := url.Parse(hostCfg.URL)
+	if e != nil {
+		return nil, nil, probe.NewError(e).Trace(hostCfg.URL)
+	}
+	var endpoints []string
+	for _, srv := range info.Servers {
+		endpoints = append(endpoints, srv.Endpoint)
+	}
+	if len(endpoints) == 0 {
+		endpoints = append(endpoints, u.Host)
+	}
+	sort.Strings(endpoints)
+
+	var clients []*S3Client
+	for _, endpoint := range endpoints {
+		nodeURL := *u
+		nodeURL.Host = endpoint
+		clnt, err := S3New(NewS3Config(nodeURL.String(), hostCfg))
+		if err != nil {
+			return nil, nil, err.Trace(nodeURL.String())
+		}
+		s3Clnt, ok := clnt.(*S3Client)
+		if !ok {
+			return nil, nil, errInvalidTarget(nodeURL.String())
+		}
+		clients = append(clients, s3Clnt)
+	}
+	return endpoints, clients, nil
+}
+
+// burnInObject writes a test object through clnt, reads it back through
+// the same client and verifies it, then deletes it unless keep is set.
+func burnInObject(ctx context.Context, clnt *S3Client, bucket, object string, seed, size int64, keep bool) (r burnInResult) {
+	r.size = size
+	h := sha256.New()
+	start := time.Now()
+	_, r.writeErr = clnt.api.PutObject(ctx, bucket, object, io.TeeReader(burnInReader(seed, size), h), size, minio.PutObjectOptions{})
+	r.write = time.Since(start)
+	if r.writeErr != nil {
+		return r
+	}
+
+	start = time.Now()
+	obj, e := clnt.api.GetObject(ctx, bucket, object, minio.GetObjectOptions{})
+	if e == nil {
+		e = burnInVerify(obj, h.Sum(nil))
+		obj.Close()
+	}
+	r.read, r.readErr = time.Since(start), e
+
+	if !keep {
+		e = clnt.api.RemoveObject(ctx, bucket, object, minio.RemoveObjectOptions{})
+		errorIf(probe.NewError(e).Trace(bucket, object), "Unable to remove test object.")
+	}
+	return r
+}
+
+// mainAdminDriveBurnIn is the handle for "mc admin drive burn-in" command.
+func mainAdminDriveBurnIn(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, 1)
+	}
+	aliasedURL := ctx.Args().Get(0)
+
+	totalSize, e := humanize.ParseBytes(ctx.String("size"))
+	fatalIf(probe.NewError(e).Trace(ctx.String("size")), "Unable to parse --size.")
+	objectSize, e := humanize.ParseBytes(ctx.String("object-size"))
+	fatalIf(probe.NewError(e).Trace(ctx.String("object-size")), "Unable to parse --object-size.")
+	if objectSize == 0 || totalSize < objectSize {
+		fatalIf(errInvalidArgument().Trace(ctx.String("size"), ctx.String("object-size")),
+			"--size must be larger than --object-size, which can't be zero.")
+	}
+	concurrent := ctx.Int("concurrent")
+	if concurrent <= 0 {
+		fatalIf(errInvalidArgument().Trace(ctx.String("concurrent")), "--concurrent must be positive.")
+	}
+	keep := ctx.Bool("keep")
+
+	console.SetColor("BurnInOK", color.New(color.FgGreen, color.Bold))
+	console.SetColor("BurnInFail", color.New(color.FgRed, color.Bold))
+	console.SetColor("BurnInHeader", color.New(color.Bold))
+
+	alias, path := url2Alias(aliasedURL)
+	hostCfg := mustGetHostConfig(alias)
+	if hostCfg == nil {
+		fatalIf(errInvalidAliasedURL(aliasedURL), "No such alias `"+alias+"` found.")
+	}
+	bucket, prefix, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	if bucket == "" {
+		fatalIf(errInvalidArgument().Trace(aliasedURL), "A bucket is required for the test objects.")
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	client, err := newAdminClient(aliasedURL)
+	fatalIf(err, "Unable to initialize admin connection.")
+
+	before, e := client.ServerInfo(globalContext)
+	fatalIf(probe.NewError(e).Trace(aliasedURL), "Unable to get the drives of the deployment.")
+
+	endpoints, clients, err := burnInNodeClients(hostCfg, before)
+	fatalIf(err, "Unable to initialize the connections to the servers.")
+
+	runID := randString(8, rand.NewSource(time.Now().UnixNano()), "")
+	baseSeed := time.Now().UnixNano()
+	objects := int64(totalSize / objectSize)
+
+	jobs := make(chan int64)
+	results := make(chan burnInResult)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrent; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				node := int(i % int64(len(clients)))
+				object := fmt.Sprintf("%smc-burn-in-%s/%08d", prefix, runID, i)
+				r := burnInObject(globalContext, clients[node], bucket, object, baseSeed+i, int64(objectSize), keep)
+				r.node = node
+				results <- r
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for i := int64(0); i < objects; i++ {
+			select {
+			case jobs <- i:
+			case <-globalContext.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	start := time.Now()
+	nodes := make([]burnInNodeStats, len(endpoints))
+	for i, endpoint := range endpoints {
+		nodes[i].Endpoint = endpoint
+	}
+	for r := range results {
+		nodes[r.node].add(r)
+	}
+
+	after, e := client.ServerInfo(globalContext)
+	fatalIf(probe.NewError(e).Trace(aliasedURL), "Unable to get the drives of the deployment.")
+
+	msg := burnInMessage{
+		Target:   aliasedURL,
+		Duration: time.Since(start),
+		Nodes:    nodes,
+		Drives:   burnInDrives(before, after),
+	}
+	printMsg(msg)
+	if msg.failed() {
+		return exitStatus(globalErrorExitStatus)
+	}
+	return nil
+}
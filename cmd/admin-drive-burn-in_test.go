@@ -0,0 +1,77 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestBurnInVerify(t *testing.T) {
+	data, _ := io.ReadAll(burnInReader(42, 1024))
+	if len(data) != 1024 {
+		t.Fatalf("expected 1024 bytes, got %d", len(data))
+	}
+	sum := sha256.Sum256(data)
+
+	testCases := []struct {
+		r      io.Reader
+		expect error
+	}{
+		{burnInReader(42, 1024), nil},
+		{bytes.NewReader(data), nil},
+		{burnInReader(43, 1024), errBurnInCorrupted},
+		{burnInReader(42, 1023), errBurnInCorrupted},
+	}
+	for i, testCase := range testCases {
+		if e := burnInVerify(testCase.r, sum[:]); !errors.Is(e, testCase.expect) {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.expect, e)
+		}
+	}
+}
+
+func TestBurnInNodeStats(t *testing.T) {
+	var s burnInNodeStats
+	s.add(burnInResult{size: 10, write: 2 * time.Second, read: time.Second})
+	s.add(burnInResult{size: 10, write: 4 * time.Second, read: 3 * time.Second})
+	s.add(burnInResult{size: 10, writeErr: errors.New("write failed")})
+	s.add(burnInResult{size: 10, write: 6 * time.Second, readErr: errBurnInCorrupted})
+
+	expect := burnInNodeStats{
+		Objects:        4,
+		Bytes:          30,
+		WriteErrors:    1,
+		ChecksumErrors: 1,
+		AvgWrite:       4 * time.Second,
+		MaxWrite:       6 * time.Second,
+		AvgRead:        2 * time.Second,
+		MaxRead:        3 * time.Second,
+		LastError:      errBurnInCorrupted.Error(),
+	}
+	s.writes, s.reads, s.totalWrite, s.totalRead = 0, 0, 0, 0
+	if s != expect {
+		t.Fatalf("expected %+v, got %+v", expect, s)
+	}
+	if !s.failed() {
+		t.Fatalf("expected the node to fail")
+	}
+}
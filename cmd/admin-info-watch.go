@@ -0,0 +1,258 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-admin-go"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+var adminInfoFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "watch, w",
+		Usage: "refresh the information on an interval, highlighting the changes",
+	},
+	cli.DurationFlag{
+		Name:  "interval",
+		Usage: "interval between two refreshes with --watch",
+		Value: 5 * time.Second,
+	},
+}
+
+// Number of changes listed below the refreshed information.
+const infoWatchHistory = 10
+
+// infoUnreachable is the state of a cluster which cannot be reached.
+const infoUnreachable = "unreachable"
+
+// infoDelta is a change of state of the cluster, a server or a drive.
+// An empty From is a new server or drive, an empty To a removed one.
+type infoDelta struct {
+	Time time.Time `json:"time"`
+	Kind string    `json:"kind"`
+	Name string    `json:"name"`
+	From string    `json:"from"`
+	To   string    `json:"to"`
+}
+
+// isHealthy returns true if the new state of the delta is a good one.
+func (d infoDelta) isHealthy() bool {
+	switch d.To {
+	case string(madmin.ItemOnline), madmin.DriveStateOk, madmin.DriveStateUnformatted:
+		return true
+	}
+	return false
+}
+
+func (d infoDelta) String() string {
+	from, to := d.From, d.To
+	if from == "" {
+		from = "absent"
+	}
+	if to == "" {
+		to = "removed"
+	}
+	theme := "InfoFail"
+	if d.isHealthy() {
+		theme = "Info"
+	}
+	return fmt.Sprintf("%s %s %s: %s", d.Time.Format("15:04:05"), d.Kind, d.Name,
+		console.Colorize(theme, from+" -> "+to))
+}
+
+// infoState returns the states of the servers and drives of a cluster,
+// by kind and name.
+func infoState(info madmin.InfoMessage) map[[2]string]string {
+	state := make(map[[2]string]string)
+	for _, srv := range info.Servers {
+		state[[2]string{"server", srv.Endpoint}] = srv.State
+		for _, disk := range srv.Disks {
+			name := disk.Endpoint
+			if name == "" {
+				name = srv.Endpoint + disk.DrivePath
+			}
+			state[[2]string{"drive", name}] = disk.State
+		}
+	}
+	return state
+}
+
+// infoDeltas returns the changes between two states, sorted by kind
+// and name.
+func infoDeltas(prev, cur map[[2]string]string, now time.Time) (deltas []infoDelta) {
+	for key, to := range cur {
+		if from := prev[key]; from != to {
+			deltas = append(deltas, infoDelta{Time: now, Kind: key[0], Name: key[1], From: from, To: to})
+		}
+	}
+	for key, from := range prev {
+		if _, ok := cur[key]; !ok {
+			deltas = append(deltas, infoDelta{Time: now, Kind: key[0], Name: key[1], From: from})
+		}
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		if deltas[i].Kind != deltas[j].Kind {
+			return deltas[i].Kind > deltas[j].Kind // servers first
+		}
+		return deltas[i].Name < deltas[j].Name
+	})
+	return deltas
+}
+
+// infoDeltaMessage is a change printed with --watch --json.
+type infoDeltaMessage struct {
+	Status string `json:"status"`
+	infoDelta
+}
+
+// String colorized info delta message
+func (m infoDeltaMessage) String() string {
+	return m.infoDelta.String()
+}
+
+// JSON jsonified info delta message
+func (m infoDeltaMessage) JSON() string {
+	m.Status = "success"
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(msgBytes)
+}
+
+// infoWatchMessage is the information refreshed with --watch, followed
+// by the last changes.
+type infoWatchMessage struct {
+	info     clusterStruct
+	interval time.Duration
+	updated  time.Time
+	changes  []infoDelta
+}
+
+// String colorized info watch message
+func (m infoWatchMessage) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Every %s, updated at %s\n\n", m.interval, m.updated.Format(printDate))
+	if m.info.Status == "error" {
+		b.WriteString(console.Colorize("InfoFail", "Unable to get server information: "+m.info.Error) + "\n")
+	} else {
+		b.WriteString(m.info.String() + "\n")
+	}
+	if len(m.changes) > 0 {
+		b.WriteString("\nChanges:\n")
+		for _, change := range m.changes {
+			b.WriteString("   " + change.String() + "\n")
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// JSON is not used, changes are printed one by one in JSON.
+func (m infoWatchMessage) JSON() string {
+	return m.info.JSON()
+}
+
+// getClusterInfo returns the information of the cluster, with the
+// error in its status.
+func getClusterInfo(ctx context.Context, client *madmin.AdminClient) clusterStruct {
+	var clusterInfo clusterStruct
+	// Fetch info of all servers (cluster or single server)
+	admInfo, e := client.ServerInfo(ctx)
+	if e != nil {
+		clusterInfo.Status = "error"
+		clusterInfo.Error = e.Error()
+	} else {
+		clusterInfo.Status = "success"
+		clusterInfo.Error = ""
+	}
+	clusterInfo.Info = admInfo
+	return clusterInfo
+}
+
+// watchAdminInfo refreshes the information of the cluster every
+// interval until interrupted. The full information is printed once
+// in JSON, followed by the changes.
+func watchAdminInfo(ctx context.Context, client *madmin.AdminClient, alias string, interval time.Duration) {
+	var (
+		prev    map[[2]string]string
+		changes []infoDelta
+		printed bool
+	)
+	console.SetColor("Info", color.New(color.FgGreen, color.Bold))
+	console.SetColor("InfoFail", color.New(color.FgRed, color.Bold))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		now := time.Now()
+		info := getClusterInfo(ctx, client)
+		cur := map[[2]string]string{{"cluster", alias}: string(madmin.ItemOnline)}
+		if info.Status == "error" {
+			cur[[2]string{"cluster", alias}] = infoUnreachable
+			// Unknown servers and drives, only the cluster changed.
+			for key, state := range prev {
+				if key[0] != "cluster" {
+					cur[key] = state
+				}
+			}
+		} else {
+			for key, state := range infoState(info.Info) {
+				cur[key] = state
+			}
+		}
+
+		var deltas []infoDelta
+		if prev != nil {
+			deltas = infoDeltas(prev, cur, now)
+		}
+		prev = cur
+
+		if globalJSON {
+			if !printed && info.Status != "error" {
+				printMsg(info)
+				printed = true
+			}
+			for _, delta := range deltas {
+				printMsg(infoDeltaMessage{infoDelta: delta})
+			}
+		} else {
+			changes = append(deltas, changes...)
+			if len(changes) > infoWatchHistory {
+				changes = changes[:infoWatchHistory]
+			}
+			// Clear the screen before refreshing.
+			fmt.Print("\033[H\033[2J")
+			printMsg(infoWatchMessage{info: info, interval: interval, updated: now, changes: changes})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
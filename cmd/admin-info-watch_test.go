@@ -0,0 +1,55 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestInfoDeltas(t *testing.T) {
+	now := time.Now()
+	prev := map[[2]string]string{
+		{"server", "node1:9000"}:   "online",
+		{"server", "node2:9000"}:   "online",
+		{"drive", "node1:9000/d1"}: "ok",
+		{"drive", "node1:9000/d2"}: "ok",
+		{"drive", "node2:9000/d1"}: "ok",
+	}
+	cur := map[[2]string]string{
+		{"server", "node1:9000"}:   "online",
+		{"server", "node2:9000"}:   "offline",
+		{"server", "node3:9000"}:   "online",
+		{"drive", "node1:9000/d1"}: "ok",
+		{"drive", "node1:9000/d2"}: "offline",
+	}
+
+	expected := []infoDelta{
+		{Time: now, Kind: "server", Name: "node2:9000", From: "online", To: "offline"},
+		{Time: now, Kind: "server", Name: "node3:9000", From: "", To: "online"},
+		{Time: now, Kind: "drive", Name: "node1:9000/d2", From: "ok", To: "offline"},
+		{Time: now, Kind: "drive", Name: "node2:9000/d1", From: "ok", To: ""},
+	}
+	if deltas := infoDeltas(prev, cur, now); !reflect.DeepEqual(deltas, expected) {
+		t.Errorf("Test 1: expected %v, got %v", expected, deltas)
+	}
+	if deltas := infoDeltas(cur, cur, now); len(deltas) != 0 {
+		t.Errorf("Test 2: expected no delta, got %v", deltas)
+	}
+}
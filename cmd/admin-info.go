@@ -42,12 +42,12 @@ var adminInfoCmd = cli.Command{
 	Action:       mainAdminInfo,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        globalFlags,
+	Flags:        append(adminInfoFlags, globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
 USAGE:
-  {{.HelpName}} TARGET
+  {{.HelpName}} [FLAGS] TARGET
 
 FLAGS:
   {{range .VisibleFlags}}{{.}}
@@ -55,6 +55,12 @@ FLAGS:
 EXAMPLES:
   1. Get server information of the 'play' MinIO server.
      {{.Prompt}} {{.HelpName}} play/
+
+  2. Keep the server information of 'myminio' on screen during a maintenance, refreshed every 10 seconds.
+     {{.Prompt}} {{.HelpName}} --watch --interval 10s myminio/
+
+  3. Print the server information of 'myminio' once, then every server or drive changing state, in JSON.
+     {{.Prompt}} {{.HelpName}} --watch --json myminio/
 `,
 }
 
@@ -320,6 +326,9 @@ func checkAdminInfoSyntax(ctx *cli.Context) {
 	if len(ctx.Args()) == 0 || len(ctx.Args()) > 1 {
 		showCommandHelpAndExit(ctx, 1) // last argument is exit code
 	}
+	if ctx.Bool("watch") && ctx.Duration("interval") <= 0 {
+		fatalIf(errInvalidArgument().Trace(ctx.String("interval")), "--interval should be a positive duration.")
+	}
 }
 
 func mainAdminInfo(ctx *cli.Context) error {
@@ -333,18 +342,13 @@ func mainAdminInfo(ctx *cli.Context) error {
 	client, err := newAdminClient(aliasedURL)
 	fatalIf(err, "Unable to initialize admin connection.")
 
-	var clusterInfo clusterStruct
-	// Fetch info of all servers (cluster or single server)
-	admInfo, e := client.ServerInfo(globalContext)
-	if e != nil {
-		clusterInfo.Status = "error"
-		clusterInfo.Error = e.Error()
-	} else {
-		clusterInfo.Status = "success"
-		clusterInfo.Error = ""
+	if ctx.Bool("watch") {
+		alias, _ := url2Alias(aliasedURL)
+		watchAdminInfo(globalContext, client, alias, ctx.Duration("interval"))
+		return nil
 	}
-	clusterInfo.Info = admInfo
-	printMsg(clusterInfo)
+
+	printMsg(getClusterInfo(globalContext, client))
 
 	return nil
 }
@@ -50,12 +50,14 @@ var adminCmdSubcommands = []cli.Command{
 	adminSpeedtestCmd,
 	adminProfileCmd,
 	adminScannerCmd,
+	adminMetricsCmd,
 	adminTopCmd,
 	adminTraceCmd,
 	adminConsoleCmd,
 	adminClusterCmd,
 	adminRebalanceCmd,
 	adminLogsCmd,
+	adminDriveCmd,
 }
 
 var adminCmd = cli.Command{
@@ -0,0 +1,187 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/trinet2005/oss-admin-go"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+// metricsTypes maps the values of 'admin metrics get --type'
+// to the realtime metrics types of the server.
+var metricsTypes = map[string]madmin.MetricType{
+	"scanner":     madmin.MetricsScanner,
+	"disk":        madmin.MetricsDisk,
+	"net":         madmin.MetricNet,
+	"batch-jobs":  madmin.MetricsBatchJobs,
+	"site-resync": madmin.MetricsSiteResync,
+}
+
+var adminMetricsGetFlags = []cli.Flag{
+	cli.StringSliceFlag{
+		Name:  "type",
+		Usage: "metrics to fetch: " + strings.Join(metricsTypeNames(), ", ") + " or all",
+	},
+	cli.StringFlag{
+		Name:  "nodes",
+		Usage: "fetch only from matching servers, comma separate multiple",
+	},
+	cli.BoolFlag{
+		Name:  "by-host",
+		Usage: "include the metrics of every server",
+	},
+	cli.BoolFlag{
+		Name:  "by-disk",
+		Usage: "include the metrics of every drive",
+	},
+	cli.DurationFlag{
+		Name:  "interval",
+		Usage: "duration over which rates are sampled",
+		Value: time.Second,
+	},
+}
+
+var adminMetricsGetCmd = cli.Command{
+	Name:            "get",
+	Usage:           "print a single snapshot of realtime metrics as JSON",
+	Action:          mainAdminMetricsGet,
+	OnUsageError:    onUsageError,
+	Before:          setGlobalsFromContext,
+	Flags:           append(adminMetricsGetFlags, globalFlags...),
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+NOTE:
+  Unlike 'mc admin scanner status' or 'mc support top', the metrics are
+  fetched once and printed as a single JSON document, to be collected
+  by a cron job for example.
+
+EXAMPLES:
+  1. Fetch the scanner metrics of a cluster.
+     {{.Prompt}} {{.HelpName}} --type scanner myminio/
+
+  2. Fetch the drive and network metrics of every server, sampled over 5 seconds.
+     {{.Prompt}} {{.HelpName}} --type disk --type net --by-host --interval 5s myminio/
+
+  3. Append all the metrics of a cluster to a file every minute from cron.
+     * * * * * mc admin metrics get --type all myminio/ >> /var/log/minio-metrics.json
+`,
+}
+
+// metricsTypeNames returns the sorted values accepted by --type.
+func metricsTypeNames() []string {
+	names := make([]string, 0, len(metricsTypes))
+	for name := range metricsTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseMetricsTypes combines the values of --type into a metrics type.
+func parseMetricsTypes(names []string) (madmin.MetricType, *probe.Error) {
+	var t madmin.MetricType
+	for _, name := range names {
+		for _, n := range strings.Split(name, ",") {
+			n = strings.ToLower(strings.TrimSpace(n))
+			if n == "all" {
+				for _, mt := range metricsTypes {
+					t |= mt
+				}
+				continue
+			}
+			mt, ok := metricsTypes[n]
+			if !ok {
+				return 0, errInvalidArgument().Trace(n)
+			}
+			t |= mt
+		}
+	}
+	return t, nil
+}
+
+// checkAdminMetricsGetSyntax - validate all the passed arguments
+func checkAdminMetricsGetSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	if len(ctx.StringSlice("type")) == 0 {
+		fatalIf(errInvalidArgument(), "At least one --type should be specified.")
+	}
+	if _, err := parseMetricsTypes(ctx.StringSlice("type")); err != nil {
+		fatalIf(err, "Unknown metrics type, valid values are "+strings.Join(metricsTypeNames(), ", ")+" and all.")
+	}
+	if ctx.Duration("interval") <= 0 {
+		fatalIf(errInvalidArgument().Trace(ctx.String("interval")), "--interval should be positive.")
+	}
+}
+
+// mainAdminMetricsGet is the handle for "mc admin metrics get" command.
+func mainAdminMetricsGet(ctx *cli.Context) error {
+	checkAdminMetricsGetSyntax(ctx)
+
+	aliasedURL := ctx.Args().Get(0)
+
+	// Create a new MinIO Admin Client
+	client, err := newAdminClient(aliasedURL)
+	fatalIf(err.Trace(aliasedURL), "Unable to initialize admin client.")
+
+	metricsType, _ := parseMetricsTypes(ctx.StringSlice("type"))
+	opts := madmin.MetricsOptions{
+		Type:     metricsType,
+		N:        1,
+		Interval: ctx.Duration("interval"),
+		ByHost:   ctx.Bool("by-host"),
+		ByDisk:   ctx.Bool("by-disk"),
+	}
+	if nodes := ctx.String("nodes"); nodes != "" {
+		opts.Hosts = strings.Split(nodes, ",")
+	}
+
+	ctxt, cancel := context.WithCancel(globalContext)
+	defer cancel()
+
+	var snapshot *madmin.RealtimeMetrics
+	e := client.Metrics(ctxt, opts, func(metrics madmin.RealtimeMetrics) {
+		snapshot = &metrics
+	})
+	if e != nil && !errors.Is(e, context.Canceled) {
+		fatalIf(probe.NewError(e).Trace(aliasedURL), "Unable to fetch metrics.")
+	}
+	if snapshot == nil {
+		fatalIf(errDummy().Trace(aliasedURL), "No metrics returned by the server.")
+	}
+
+	// Always JSON, the document is meant to be collected by tools.
+	printMsg(metricsMessage{RealtimeMetrics: *snapshot})
+	return nil
+}
@@ -0,0 +1,372 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/trinet2005/oss-admin-go"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+
+	json "github.com/minio/colorjson"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var adminPrometheusAlertsGenerateFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "job",
+		Usage: "prometheus job name scraping the cluster metrics",
+		Value: defaultJobName,
+	},
+	cli.StringFlag{
+		Name:  "dashboard",
+		Usage: "also write a grafana dashboard for the cluster to this file",
+	},
+}
+
+var adminPrometheusAlertsGenerateCmd = cli.Command{
+	Name:            "generate",
+	Usage:           "generates prometheus alert rules tuned to the cluster topology",
+	Action:          mainAdminPrometheusAlertsGenerate,
+	OnUsageError:    onUsageError,
+	Before:          setGlobalsFromContext,
+	Flags:           append(adminPrometheusAlertsGenerateFlags, globalFlags...),
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Read the node, pool and drive counts of the cluster and print a Prometheus
+  alert rules file whose thresholds match them: expected node and drive counts,
+  how many nodes and drives the erasure sets can lose before writes fail, and
+  free capacity. The rules expect the metrics scraped by the job generated with
+  'mc admin prometheus generate'.
+
+EXAMPLES:
+  1. Generate the alert rules for 'myminio'.
+     {{.Prompt}} {{.HelpName}} myminio > minio-alerts.yml
+
+  2. Generate the alert rules and a grafana dashboard for 'myminio'.
+     {{.Prompt}} {{.HelpName}} myminio --dashboard minio-dashboard.json > minio-alerts.yml
+
+  3. Generate the alert rules for a cluster scraped by the job 'minio-prod'.
+     {{.Prompt}} {{.HelpName}} myminio --job minio-prod > minio-alerts.yml
+`,
+}
+
+// alertPoolTopology is the layout of a single pool.
+type alertPoolTopology struct {
+	Index        int `json:"index"`
+	Nodes        int `json:"nodes"`
+	Sets         int `json:"sets"`
+	DrivesPerSet int `json:"drivesPerSet"`
+}
+
+// alertTopology is the layout of the cluster the alert thresholds are computed from.
+type alertTopology struct {
+	Nodes  int                 `json:"nodes"`
+	Drives int                 `json:"drives"`
+	Parity int                 `json:"parity"`
+	Pools  []alertPoolTopology `json:"pools"`
+}
+
+// getAlertTopology summarizes the server info of the cluster.
+func getAlertTopology(info madmin.InfoMessage) alertTopology {
+	topology := alertTopology{
+		Nodes:  len(info.Servers),
+		Parity: info.StandardParity(),
+	}
+	for _, srv := range info.Servers {
+		topology.Drives += len(srv.Disks)
+	}
+	for _, pool := range clusterSummaryInfo(info) {
+		topology.Pools = append(topology.Pools, alertPoolTopology{
+			Index:        pool.index,
+			Nodes:        len(pool.endpoints),
+			Sets:         pool.setsCount,
+			DrivesPerSet: pool.drivesPerSet,
+		})
+	}
+	sort.Slice(topology.Pools, func(i, j int) bool {
+		return topology.Pools[i].Index < topology.Pools[j].Index
+	})
+	return topology
+}
+
+// nodeTolerance returns how many nodes can go offline before an
+// erasure set loses more drives than its parity, assuming the drives
+// of every set are spread evenly across the nodes of its pool.
+func (t alertTopology) nodeTolerance() int {
+	tolerance := -1
+	for _, pool := range t.Pools {
+		if pool.Nodes == 0 || pool.DrivesPerSet == 0 {
+			continue
+		}
+		perNode := (pool.DrivesPerSet + pool.Nodes - 1) / pool.Nodes
+		if n := t.Parity / perNode; tolerance < 0 || n < tolerance {
+			tolerance = n
+		}
+	}
+	if tolerance < 0 {
+		return 0
+	}
+	return tolerance
+}
+
+// prometheusAlertRule is a single alerting rule.
+type prometheusAlertRule struct {
+	Alert       string            `yaml:"alert" json:"alert"`
+	Expr        string            `yaml:"expr" json:"expr"`
+	For         string            `yaml:"for,omitempty" json:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+}
+
+// prometheusAlertGroup is a named group of alerting rules.
+type prometheusAlertGroup struct {
+	Name  string                `yaml:"name" json:"name"`
+	Rules []prometheusAlertRule `yaml:"rules" json:"rules"`
+}
+
+// prometheusAlertRules is the container of a prometheus rules file.
+type prometheusAlertRules struct {
+	Groups []prometheusAlertGroup `yaml:"groups" json:"groups"`
+}
+
+// newAlertRule returns a rule with the given severity and annotations.
+func newAlertRule(name, expr, wait, severity, summary, description string) prometheusAlertRule {
+	return prometheusAlertRule{
+		Alert:  name,
+		Expr:   expr,
+		For:    wait,
+		Labels: map[string]string{"severity": severity},
+		Annotations: map[string]string{
+			"summary":     summary,
+			"description": description,
+		},
+	}
+}
+
+// generateAlertRules returns the alert rules for a cluster with the
+// given topology, scraped by the given job.
+func generateAlertRules(alias, job string, t alertTopology) prometheusAlertRules {
+	selector := fmt.Sprintf(`{job="%s"}`, job)
+	nodeTolerance := t.nodeTolerance()
+
+	rules := []prometheusAlertRule{
+		newAlertRule("MinIOTargetDown",
+			"up"+selector+" == 0", "1m", "critical",
+			"MinIO metrics are not being scraped",
+			"Prometheus cannot scrape {{ $labels.instance }} of job "+job+"."),
+		newAlertRule("MinIONodesOffline",
+			"minio_cluster_nodes_offline_total"+selector+" > 0", "5m", "warning",
+			"MinIO nodes are offline",
+			fmt.Sprintf("{{ $value }} of %d nodes are offline, up to %d can be lost before writes fail.", t.Nodes, nodeTolerance)),
+		newAlertRule("MinIONodesOfflineCritical",
+			fmt.Sprintf("minio_cluster_nodes_offline_total%s >= %d", selector, nodeTolerance+1), "1m", "critical",
+			"MinIO has lost more nodes than it tolerates",
+			fmt.Sprintf("{{ $value }} of %d nodes are offline, more than the %d the erasure sets tolerate.", t.Nodes, nodeTolerance)),
+		newAlertRule("MinIONodesMissing",
+			fmt.Sprintf("minio_cluster_nodes_online_total%s + minio_cluster_nodes_offline_total%s < %d", selector, selector, t.Nodes), "15m", "warning",
+			"MinIO reports fewer nodes than expected",
+			fmt.Sprintf("The cluster reports {{ $value }} nodes, %d were expected.", t.Nodes)),
+		newAlertRule("MinIODrivesOffline",
+			"minio_cluster_drive_offline_total"+selector+" > 0", "5m", "warning",
+			"MinIO drives are offline",
+			fmt.Sprintf("{{ $value }} of %d drives are offline.", t.Drives)),
+	}
+	if t.Parity > 0 {
+		rules = append(rules, newAlertRule("MinIODrivesOfflineCritical",
+			fmt.Sprintf("minio_cluster_drive_offline_total%s >= %d", selector, t.Parity), "1m", "critical",
+			"MinIO erasure sets may be at their parity limit",
+			fmt.Sprintf("{{ $value }} of %d drives are offline, an erasure set with %d offline drives cannot lose another one.", t.Drives, t.Parity)))
+	}
+	rules = append(rules,
+		newAlertRule("MinIODrivesMissing",
+			fmt.Sprintf("minio_cluster_drive_total%s < %d", selector, t.Drives), "15m", "warning",
+			"MinIO reports fewer drives than expected",
+			fmt.Sprintf("The cluster reports {{ $value }} drives, %d were expected.", t.Drives)),
+		newAlertRule("MinIOCapacityLow",
+			"minio_cluster_capacity_usable_free_bytes"+selector+" / minio_cluster_capacity_usable_total_bytes"+selector+" < 0.2", "30m", "warning",
+			"MinIO usable capacity is running low",
+			"Less than 20% of the usable capacity is free."),
+		newAlertRule("MinIOCapacityCritical",
+			"minio_cluster_capacity_usable_free_bytes"+selector+" / minio_cluster_capacity_usable_total_bytes"+selector+" < 0.05", "5m", "critical",
+			"MinIO usable capacity is almost exhausted",
+			"Less than 5% of the usable capacity is free."),
+	)
+
+	return prometheusAlertRules{
+		Groups: []prometheusAlertGroup{
+			{Name: "minio-" + alias, Rules: rules},
+		},
+	}
+}
+
+// grafanaPanel returns a dashboard panel plotting the given expressions.
+func grafanaPanel(id int, title, kind, unit string, x, y, w, h int, thresholds []interface{}, exprs ...string) map[string]interface{} {
+	targets := make([]map[string]interface{}, 0, len(exprs))
+	for i, expr := range exprs {
+		targets = append(targets, map[string]interface{}{
+			"refId":      string(rune('A' + i)),
+			"expr":       expr,
+			"datasource": map[string]string{"type": "prometheus", "uid": "${datasource}"},
+		})
+	}
+	defaults := map[string]interface{}{"unit": unit}
+	if thresholds != nil {
+		defaults["thresholds"] = map[string]interface{}{"mode": "absolute", "steps": thresholds}
+	}
+	return map[string]interface{}{
+		"id":          id,
+		"title":       title,
+		"type":        kind,
+		"gridPos":     map[string]int{"x": x, "y": y, "w": w, "h": h},
+		"datasource":  map[string]string{"type": "prometheus", "uid": "${datasource}"},
+		"fieldConfig": map[string]interface{}{"defaults": defaults},
+		"targets":     targets,
+	}
+}
+
+// grafanaThreshold is a single threshold step, a nil value is the base step.
+func grafanaThreshold(value interface{}, color string) map[string]interface{} {
+	return map[string]interface{}{"value": value, "color": color}
+}
+
+// generateGrafanaDashboard returns a grafana dashboard for a cluster
+// with the given topology, scraped by the given job.
+func generateGrafanaDashboard(alias, job string, t alertTopology) map[string]interface{} {
+	selector := fmt.Sprintf(`{job="%s"}`, job)
+	panels := []interface{}{
+		grafanaPanel(1, fmt.Sprintf("Nodes online (of %d)", t.Nodes), "stat", "none", 0, 0, 6, 4,
+			[]interface{}{grafanaThreshold(nil, "red"), grafanaThreshold(t.Nodes-t.nodeTolerance(), "orange"), grafanaThreshold(t.Nodes, "green")},
+			"minio_cluster_nodes_online_total"+selector),
+		grafanaPanel(2, fmt.Sprintf("Drives online (of %d)", t.Drives), "stat", "none", 6, 0, 6, 4,
+			[]interface{}{grafanaThreshold(nil, "red"), grafanaThreshold(t.Drives-t.Parity, "orange"), grafanaThreshold(t.Drives, "green")},
+			"minio_cluster_drive_online_total"+selector),
+		grafanaPanel(3, "Drives offline", "stat", "none", 12, 0, 6, 4,
+			[]interface{}{grafanaThreshold(nil, "green"), grafanaThreshold(1, "orange"), grafanaThreshold(t.Parity, "red")},
+			"minio_cluster_drive_offline_total"+selector),
+		grafanaPanel(4, "Usable capacity used", "gauge", "percentunit", 18, 0, 6, 4,
+			[]interface{}{grafanaThreshold(nil, "green"), grafanaThreshold(0.8, "orange"), grafanaThreshold(0.95, "red")},
+			"1 - minio_cluster_capacity_usable_free_bytes"+selector+" / minio_cluster_capacity_usable_total_bytes"+selector),
+		grafanaPanel(5, "S3 requests", "timeseries", "reqps", 0, 4, 12, 8, nil,
+			"sum by (api) (rate(minio_s3_requests_total"+selector+"[5m]))"),
+		grafanaPanel(6, "S3 errors", "timeseries", "reqps", 12, 4, 12, 8, nil,
+			"sum by (api) (rate(minio_s3_requests_errors_total"+selector+"[5m]))"),
+		grafanaPanel(7, "Traffic", "timeseries", "Bps", 0, 12, 12, 8, nil,
+			"sum(rate(minio_s3_traffic_received_bytes"+selector+"[5m]))",
+			"sum(rate(minio_s3_traffic_sent_bytes"+selector+"[5m]))"),
+		grafanaPanel(8, "Usable capacity", "timeseries", "bytes", 12, 12, 12, 8, nil,
+			"minio_cluster_capacity_usable_total_bytes"+selector,
+			"minio_cluster_capacity_usable_free_bytes"+selector),
+	}
+
+	return map[string]interface{}{
+		"title":         "MinIO " + alias,
+		"tags":          []string{"minio"},
+		"schemaVersion": 36,
+		"refresh":       "30s",
+		"time":          map[string]string{"from": "now-6h", "to": "now"},
+		"templating": map[string]interface{}{
+			"list": []interface{}{
+				map[string]interface{}{
+					"name":  "datasource",
+					"label": "Data source",
+					"type":  "datasource",
+					"query": "prometheus",
+				},
+			},
+		},
+		"panels": panels,
+	}
+}
+
+// alertsGenerateMessage container for the generated alert rules.
+type alertsGenerateMessage struct {
+	Status    string               `json:"status"`
+	Topology  alertTopology        `json:"topology"`
+	Rules     prometheusAlertRules `json:"rules"`
+	Dashboard string               `json:"dashboard,omitempty"`
+}
+
+// String colorized alert rules yaml.
+func (m alertsGenerateMessage) String() string {
+	b, e := yaml.Marshal(m.Rules)
+	fatalIf(probe.NewError(e), "Unable to generate Prometheus alert rules")
+
+	return console.Colorize("yaml", string(b))
+}
+
+// JSON jsonified alert rules.
+func (m alertsGenerateMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// mainAdminPrometheusAlertsGenerate is the handle for "mc admin prometheus alerts generate" sub-command.
+func mainAdminPrometheusAlertsGenerate(ctx *cli.Context) error {
+	console.SetColor("yaml", color.New(color.FgGreen))
+
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+
+	aliasedURL := ctx.Args().Get(0)
+	alias, _ := url2Alias(aliasedURL)
+	job := ctx.String("job")
+	if job == "" {
+		fatalIf(errInvalidArgument().Trace(job), "The job name cannot be empty.")
+	}
+
+	client, err := newAdminClient(aliasedURL)
+	fatalIf(err, "Unable to initialize admin connection.")
+
+	info, e := client.ServerInfo(globalContext)
+	fatalIf(probe.NewError(e).Trace(aliasedURL), "Unable to get the topology of the cluster.")
+
+	topology := getAlertTopology(info)
+	msg := alertsGenerateMessage{
+		Topology: topology,
+		Rules:    generateAlertRules(alias, job, topology),
+	}
+
+	if path := ctx.String("dashboard"); path != "" {
+		data, e := json.MarshalIndent(generateGrafanaDashboard(alias, job, topology), "", "  ")
+		fatalIf(probe.NewError(e), "Unable to marshal the dashboard into JSON.")
+		e = os.WriteFile(path, data, 0o644)
+		fatalIf(probe.NewError(e).Trace(path), "Unable to write the dashboard.")
+		msg.Dashboard = path
+	}
+
+	printMsg(msg)
+	return nil
+}
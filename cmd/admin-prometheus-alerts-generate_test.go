@@ -0,0 +1,86 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import "testing"
+
+func TestAlertTopologyNodeTolerance(t *testing.T) {
+	testCases := []struct {
+		topology  alertTopology
+		tolerance int
+	}{
+		// A single node holding every drive of the set.
+		{alertTopology{Parity: 2, Pools: []alertPoolTopology{{Nodes: 1, Sets: 1, DrivesPerSet: 4}}}, 0},
+		// One drive per node and set.
+		{alertTopology{Parity: 4, Pools: []alertPoolTopology{{Nodes: 16, Sets: 2, DrivesPerSet: 16}}}, 4},
+		// Two drives per node and set.
+		{alertTopology{Parity: 4, Pools: []alertPoolTopology{{Nodes: 8, Sets: 4, DrivesPerSet: 16}}}, 2},
+		// The smallest pool decides.
+		{alertTopology{Parity: 4, Pools: []alertPoolTopology{{Nodes: 16, DrivesPerSet: 16}, {Nodes: 4, DrivesPerSet: 16}}}, 1},
+		// No parity.
+		{alertTopology{Parity: 0, Pools: []alertPoolTopology{{Nodes: 4, DrivesPerSet: 4}}}, 0},
+		// No pools reported.
+		{alertTopology{Parity: 2}, 0},
+	}
+
+	for i, testCase := range testCases {
+		if tolerance := testCase.topology.nodeTolerance(); tolerance != testCase.tolerance {
+			t.Errorf("Test %d: expected tolerance %d, got %d", i+1, testCase.tolerance, tolerance)
+		}
+	}
+}
+
+func TestGenerateAlertRules(t *testing.T) {
+	topology := alertTopology{
+		Nodes:  8,
+		Drives: 64,
+		Parity: 4,
+		Pools:  []alertPoolTopology{{Nodes: 8, Sets: 4, DrivesPerSet: 16}},
+	}
+	rules := generateAlertRules("myminio", "minio-job", topology)
+	if len(rules.Groups) != 1 || rules.Groups[0].Name != "minio-myminio" {
+		t.Fatalf("expected a single group named minio-myminio, got %v", rules.Groups)
+	}
+
+	expected := map[string]string{
+		"MinIONodesOfflineCritical":  `minio_cluster_nodes_offline_total{job="minio-job"} >= 3`,
+		"MinIONodesMissing":          `minio_cluster_nodes_online_total{job="minio-job"} + minio_cluster_nodes_offline_total{job="minio-job"} < 8`,
+		"MinIODrivesOfflineCritical": `minio_cluster_drive_offline_total{job="minio-job"} >= 4`,
+		"MinIODrivesMissing":         `minio_cluster_drive_total{job="minio-job"} < 64`,
+	}
+	found := make(map[string]string)
+	for _, rule := range rules.Groups[0].Rules {
+		found[rule.Alert] = rule.Expr
+		if rule.Labels["severity"] == "" {
+			t.Errorf("%s: expected a severity label", rule.Alert)
+		}
+	}
+	for alert, expr := range expected {
+		if found[alert] != expr {
+			t.Errorf("%s: expected expression %q, got %q", alert, expr, found[alert])
+		}
+	}
+
+	// Without parity there is no drive loss to tolerate.
+	topology.Parity = 0
+	for _, rule := range generateAlertRules("myminio", "minio-job", topology).Groups[0].Rules {
+		if rule.Alert == "MinIODrivesOfflineCritical" {
+			t.Errorf("expected no MinIODrivesOfflineCritical rule without parity")
+		}
+	}
+}
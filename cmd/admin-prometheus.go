@@ -22,6 +22,7 @@ import "github.com/minio/cli"
 var adminPrometheusSubcommands = []cli.Command{
 	adminPrometheusGenerateCmd,
 	adminPrometheusMetricsCmd,
+	adminPrometheusAlertsCmd,
 }
 
 var adminPrometheusCmd = cli.Command{
@@ -28,13 +28,20 @@ import (
 	"github.com/trinet2005/oss-pkg/console"
 )
 
+var adminReplicateAddFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "list the buckets, policies, users and service accounts which would be created or overwritten on each peer",
+	},
+}
+
 var adminReplicateAddCmd = cli.Command{
 	Name:         "add",
 	Usage:        "add one or more sites for replication",
 	Action:       mainAdminReplicateAdd,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        globalFlags,
+	Flags:        append(adminReplicateAddFlags, globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -44,10 +51,16 @@ USAGE:
 FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}
+NOTE:
+  The buckets, policies, users and service accounts of ALIAS1 are replicated
+  to the other sites, overwriting existing entities of the same name.
 
 EXAMPLES:
   1. Add a site for cluster-level replication:
      {{.Prompt}} {{.HelpName}} minio1 minio2
+
+  2. Review the buckets and IAM entities of minio1 which would be created or overwritten on minio2 and minio3:
+     {{.Prompt}} {{.HelpName}} --dry-run minio1 minio2 minio3
 `,
 }
 
@@ -88,6 +101,12 @@ func mainAdminReplicateAdd(ctx *cli.Context) error {
 	args := ctx.Args()
 	aliasedURL := args.Get(0)
 
+	if ctx.Bool("dry-run") {
+		setSRPlanColors()
+		fatalIf(planSRAdd(globalContext, aliasedURL, args.Tail()), "Unable to plan adding sites for replication")
+		return nil
+	}
+
 	// Create a new MinIO Admin Client
 	client, err := newAdminClient(aliasedURL)
 	fatalIf(err, "Unable to initialize admin connection.")
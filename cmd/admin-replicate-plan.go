@@ -0,0 +1,288 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	gojson "encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+// Kinds of entities synced by site replication.
+const (
+	srEntityBucket     = "bucket"
+	srEntityPolicy     = "policy"
+	srEntityUser       = "user"
+	srEntityServiceAcc = "service-account"
+)
+
+// Actions of a site replication plan, printed by '--dry-run'.
+const (
+	srPlanCreate    = "create"
+	srPlanOverwrite = "overwrite"
+	srPlanUnchanged = "unchanged"
+	srPlanDetach    = "detach"
+)
+
+// srInventory is the set of entities of a site synced by site replication.
+type srInventory struct {
+	buckets     map[string]string
+	policies    map[string]string // compacted policy document
+	users       map[string]string // policy and status of the user
+	serviceAccs map[string]string // parent user of the service account
+}
+
+// getSRInventory lists the buckets and IAM entities of a site.
+func getSRInventory(ctx context.Context, aliasedURL string) (srInventory, *probe.Error) {
+	inv := srInventory{
+		buckets:     map[string]string{},
+		policies:    map[string]string{},
+		users:       map[string]string{},
+		serviceAccs: map[string]string{},
+	}
+
+	clnt, err := newClient(aliasedURL)
+	if err != nil {
+		return inv, err.Trace(aliasedURL)
+	}
+	buckets, err := clnt.ListBuckets(ctx)
+	if err != nil {
+		return inv, err.Trace(aliasedURL)
+	}
+	for _, bucket := range buckets {
+		inv.buckets[strings.Trim(bucket.URL.Path, "/")] = ""
+	}
+
+	admClient, err := newAdminClient(aliasedURL)
+	if err != nil {
+		return inv, err.Trace(aliasedURL)
+	}
+	policies, e := admClient.ListCannedPolicies(ctx)
+	if e != nil {
+		return inv, probe.NewError(e).Trace(aliasedURL)
+	}
+	for name, policy := range policies {
+		var buf bytes.Buffer
+		if e = gojson.Compact(&buf, policy); e != nil {
+			buf.Reset()
+			buf.Write(policy)
+		}
+		inv.policies[name] = buf.String()
+	}
+
+	users, e := admClient.ListUsers(ctx)
+	if e != nil {
+		return inv, probe.NewError(e).Trace(aliasedURL)
+	}
+	// The service accounts of the root user are listed with an empty user.
+	parents := []string{""}
+	for name, info := range users {
+		inv.users[name] = info.PolicyName + "/" + string(info.Status)
+		parents = append(parents, name)
+	}
+	for _, parent := range parents {
+		accounts, e := admClient.ListServiceAccounts(ctx, parent)
+		if e != nil {
+			return inv, probe.NewError(e).Trace(aliasedURL, parent)
+		}
+		for _, account := range accounts.Accounts {
+			inv.serviceAccs[account.AccessKey] = parent
+		}
+	}
+	return inv, nil
+}
+
+// srPlanMessage is one planned change of a site replication
+// change, printed as a single line of JSON in JSON mode.
+type srPlanMessage struct {
+	Status string `json:"status"`
+	Peer   string `json:"peer"`
+	Action string `json:"action"`
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+}
+
+// String colorized site replication plan message
+func (m srPlanMessage) String() string {
+	action := fmt.Sprintf("%-9s", m.Action)
+	if m.Action == srPlanOverwrite || m.Action == srPlanDetach {
+		action = console.Colorize("PlanWarning", action)
+	} else {
+		action = console.Colorize("Plan", action)
+	}
+	return fmt.Sprintf("%s %s %-15s `%s`", console.Colorize("PlanPeer", m.Peer+":"), action, m.Type, m.Name)
+}
+
+// JSON jsonified site replication plan message
+func (m srPlanMessage) JSON() string {
+	m.Status = "plan"
+	planMessageBytes, e := json.Marshal(m)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(planMessageBytes)
+}
+
+// srPlanSummaryMessage totals the planned changes of a peer.
+type srPlanSummaryMessage struct {
+	Status     string `json:"status"`
+	Peer       string `json:"peer"`
+	Creates    int    `json:"toCreate"`
+	Overwrites int    `json:"toOverwrite"`
+	Unchanged  int    `json:"unchanged"`
+}
+
+// String colorized site replication plan summary message
+func (m srPlanSummaryMessage) String() string {
+	return console.Colorize("PlanPeer", m.Peer+":") + console.Colorize("Plan",
+		fmt.Sprintf(" %d entities to create, %d to overwrite, %d unchanged.", m.Creates, m.Overwrites, m.Unchanged))
+}
+
+// JSON jsonified site replication plan summary message
+func (m srPlanSummaryMessage) JSON() string {
+	m.Status = "success"
+	summaryMessageBytes, e := json.Marshal(m)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(summaryMessageBytes)
+}
+
+// setSRPlanColors sets the colors of the site replication plan messages.
+func setSRPlanColors() {
+	console.SetColor("Plan", color.New(color.FgGreen))
+	console.SetColor("PlanWarning", color.New(color.FgYellow, color.Bold))
+	console.SetColor("PlanPeer", color.New(color.FgCyan))
+}
+
+// planSRAdd prints, for every peer, the entities of the source
+// site which site replication would create or overwrite on it.
+// Entities only present on a peer are left untouched.
+func planSRAdd(ctx context.Context, source string, peers []string) *probe.Error {
+	src, err := getSRInventory(ctx, source)
+	if err != nil {
+		return err
+	}
+	for _, peer := range peers {
+		dst, err := getSRInventory(ctx, peer)
+		if err != nil {
+			return err
+		}
+
+		summary := srPlanSummaryMessage{Peer: peer}
+		plan := func(entity, name string, exists, same bool) {
+			m := srPlanMessage{Peer: peer, Type: entity, Name: name}
+			switch {
+			case !exists:
+				m.Action = srPlanCreate
+				summary.Creates++
+			case same:
+				m.Action = srPlanUnchanged
+				summary.Unchanged++
+			default:
+				m.Action = srPlanOverwrite
+				summary.Overwrites++
+			}
+			// Only print what changes, unless in JSON mode.
+			if m.Action != srPlanUnchanged || globalJSON {
+				printMsg(m)
+			}
+		}
+
+		for _, name := range sortedKeys(src.buckets) {
+			// The configuration of an existing bucket is replaced.
+			_, ok := dst.buckets[name]
+			plan(srEntityBucket, name, ok, false)
+		}
+		for _, name := range sortedKeys(src.policies) {
+			policy, ok := dst.policies[name]
+			plan(srEntityPolicy, name, ok, policy == src.policies[name])
+		}
+		for _, name := range sortedKeys(src.users) {
+			user, ok := dst.users[name]
+			plan(srEntityUser, name, ok, user == src.users[name])
+		}
+		for _, name := range sortedKeys(src.serviceAccs) {
+			parent, ok := dst.serviceAccs[name]
+			plan(srEntityServiceAcc, name, ok, parent == src.serviceAccs[name])
+		}
+		printMsg(summary)
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of m in order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// planSRRemove prints the sites which would be removed from the site
+// replication of aliasedURL. Removal neither creates nor overwrites
+// entities, the removed sites keep theirs but stop syncing them.
+func planSRRemove(ctx context.Context, aliasedURL string, siteNames []string, removeAll bool) *probe.Error {
+	client, err := newAdminClient(aliasedURL)
+	if err != nil {
+		return err.Trace(aliasedURL)
+	}
+	info, e := client.SiteReplicationInfo(ctx)
+	if e != nil {
+		return probe.NewError(e).Trace(aliasedURL)
+	}
+	if !info.Enabled {
+		return probe.NewError(fmt.Errorf("site replication is not enabled on `%s`", aliasedURL))
+	}
+
+	remove := map[string]bool{}
+	for _, name := range siteNames {
+		remove[name] = true
+	}
+	known := map[string]bool{}
+	for _, site := range info.Sites {
+		known[site.Name] = true
+	}
+	for _, name := range siteNames {
+		if !known[name] {
+			return probe.NewError(fmt.Errorf("site `%s` is not part of site replication", name))
+		}
+	}
+
+	inv, err := getSRInventory(ctx, aliasedURL)
+	if err != nil {
+		return err
+	}
+	entities := len(inv.buckets) + len(inv.policies) + len(inv.users) + len(inv.serviceAccs)
+	for _, site := range info.Sites {
+		if !removeAll && !remove[site.Name] {
+			continue
+		}
+		printMsg(srPlanMessage{Peer: site.Name, Action: srPlanDetach, Type: "site", Name: site.Endpoint})
+		printMsg(srPlanSummaryMessage{Peer: site.Name, Unchanged: entities})
+	}
+	return nil
+}
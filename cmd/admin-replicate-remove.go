@@ -37,6 +37,10 @@ var adminReplicateRemoveFlags = []cli.Flag{
 		Name:  "force",
 		Usage: "force removal of site(s) from site replication configuration",
 	},
+	cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "list the site(s) which would be removed, without removing them",
+	},
 }
 
 var adminReplicateRemoveCmd = cli.Command{
@@ -64,6 +68,9 @@ EXAMPLES:
 
   2. Remove site replication for site with site names alpha, baker from active cluster minio2:
      {{.Prompt}} {{.HelpName}} minio2 alpha baker --force
+
+  3. Review which sites would be removed from site replication:
+     {{.Prompt}} {{.HelpName}} minio2 alpha baker --dry-run
 `,
 }
 
@@ -103,7 +110,7 @@ func checkAdminReplicateRemoveSyntax(ctx *cli.Context) {
 		fatalIf(errInvalidArgument().Trace(ctx.Args().Tail()...),
 			"Need at least two arguments to remove command.")
 	}
-	if !ctx.IsSet("force") {
+	if !ctx.IsSet("force") && !ctx.Bool("dry-run") {
 		fatalIf(errDummy().Trace(),
 			"Site removal requires --force flag. This operation is *IRREVERSIBLE*. Please review carefully before performing this *DANGEROUS* operation.")
 	}
@@ -119,6 +126,13 @@ func mainAdminReplicationRemoveStatus(ctx *cli.Context) error {
 	var rreq madmin.SRRemoveReq
 	rreq.SiteNames = append(rreq.SiteNames, args.Tail()...)
 	rreq.RemoveAll = ctx.Bool("all")
+
+	if ctx.Bool("dry-run") {
+		setSRPlanColors()
+		fatalIf(planSRRemove(globalContext, aliasedURL, rreq.SiteNames, rreq.RemoveAll), "Unable to plan removing sites from replication")
+		return nil
+	}
+
 	// Create a new MinIO Admin Client
 	client, err := newAdminClient(aliasedURL)
 	fatalIf(err, "Unable to initialize admin connection.")
@@ -99,7 +99,7 @@ func mainAdminReplicationResyncStatus(ctx *cli.Context) error {
 	ctxt, cancel := context.WithCancel(globalContext)
 	defer cancel()
 
-	ui := tea.NewProgram(initResyncMetricsUI(peer.DeploymentID))
+	ui := newUIProgram(initResyncMetricsUI(peer.DeploymentID))
 	go func() {
 		opts := madmin.MetricsOptions{
 			Type:    madmin.MetricsSiteResync,
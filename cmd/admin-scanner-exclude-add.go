@@ -0,0 +1,94 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"github.com/minio/cli"
+)
+
+var adminScannerExcludeAddCmd = cli.Command{
+	Name:         "add",
+	Usage:        "exclude buckets or prefixes from scanning",
+	Action:       mainAdminScannerExcludeAdd,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET BUCKET[/PREFIX] [BUCKET[/PREFIX]...]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Entries are validated before the server config is changed. Servers
+  without support for scanner exclusions are reported as such.
+
+EXAMPLES:
+  1. Exclude the bucket 'archive' from scanning on 'myminio'.
+     {{.Prompt}} {{.HelpName}} myminio archive
+
+  2. Exclude two prefixes of the bucket 'logs' from scanning on 'myminio'.
+     {{.Prompt}} {{.HelpName}} myminio logs/2022/ logs/2023/
+`,
+}
+
+// checkAdminScannerExcludeAddSyntax - validate all the passed arguments
+func checkAdminScannerExcludeAddSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) < 2 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+}
+
+// mainAdminScannerExcludeAdd is the handle for "mc admin scanner exclude add" command.
+func mainAdminScannerExcludeAdd(ctx *cli.Context) error {
+	checkAdminScannerExcludeAddSyntax(ctx)
+	setScannerExcludeColors()
+
+	args := ctx.Args()
+	aliasedURL := args.Get(0)
+
+	entries, err := normalizeScannerExcludes(args.Tail())
+	fatalIf(err, "Invalid scanner exclusion")
+
+	// Create a new MinIO Admin Client
+	client, err := newAdminClient(aliasedURL)
+	fatalIf(err, "Unable to initialize admin connection.")
+
+	current, err := getScannerExcludes(client)
+	fatalIf(err.Trace(aliasedURL), "Unable to get scanner exclusions")
+
+	merged, added := addScannerExcludes(current, entries)
+	var restart bool
+	if len(added) > 0 {
+		restart, err = setScannerExcludes(client, merged)
+		fatalIf(err.Trace(aliasedURL), "Unable to set scanner exclusions")
+	}
+
+	printMsg(scannerExcludeMessage{
+		Op:          "add",
+		Excludes:    merged,
+		Changed:     added,
+		Restart:     restart,
+		targetAlias: aliasedURL,
+	})
+
+	return nil
+}
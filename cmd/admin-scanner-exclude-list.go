@@ -0,0 +1,75 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"github.com/minio/cli"
+)
+
+var adminScannerExcludeListCmd = cli.Command{
+	Name:         "list",
+	ShortName:    "ls",
+	Usage:        "list buckets and prefixes skipped by the scanner",
+	Action:       mainAdminScannerExcludeList,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. List all scanner exclusions on 'myminio'.
+     {{.Prompt}} {{.HelpName}} myminio
+`,
+}
+
+// checkAdminScannerExcludeListSyntax - validate all the passed arguments
+func checkAdminScannerExcludeListSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+}
+
+// mainAdminScannerExcludeList is the handle for "mc admin scanner exclude list" command.
+func mainAdminScannerExcludeList(ctx *cli.Context) error {
+	checkAdminScannerExcludeListSyntax(ctx)
+	setScannerExcludeColors()
+
+	aliasedURL := ctx.Args().Get(0)
+
+	// Create a new MinIO Admin Client
+	client, err := newAdminClient(aliasedURL)
+	fatalIf(err, "Unable to initialize admin connection.")
+
+	excludes, err := getScannerExcludes(client)
+	fatalIf(err.Trace(aliasedURL), "Unable to get scanner exclusions")
+
+	printMsg(scannerExcludeMessage{
+		Op:          "list",
+		Excludes:    excludes,
+		targetAlias: aliasedURL,
+	})
+
+	return nil
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"github.com/minio/cli"
+)
+
+var adminScannerExcludeRemoveCmd = cli.Command{
+	Name:         "remove",
+	ShortName:    "rm",
+	Usage:        "resume scanning of excluded buckets or prefixes",
+	Action:       mainAdminScannerExcludeRemove,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET BUCKET[/PREFIX] [BUCKET[/PREFIX]...]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Resume scanning of the bucket 'archive' on 'myminio'.
+     {{.Prompt}} {{.HelpName}} myminio archive
+
+  2. Resume scanning of a prefix of the bucket 'logs' on 'myminio'.
+     {{.Prompt}} {{.HelpName}} myminio logs/2022/
+`,
+}
+
+// checkAdminScannerExcludeRemoveSyntax - validate all the passed arguments
+func checkAdminScannerExcludeRemoveSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) < 2 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+}
+
+// mainAdminScannerExcludeRemove is the handle for "mc admin scanner exclude remove" command.
+func mainAdminScannerExcludeRemove(ctx *cli.Context) error {
+	checkAdminScannerExcludeRemoveSyntax(ctx)
+	setScannerExcludeColors()
+
+	args := ctx.Args()
+	aliasedURL := args.Get(0)
+
+	entries, err := normalizeScannerExcludes(args.Tail())
+	fatalIf(err, "Invalid scanner exclusion")
+
+	// Create a new MinIO Admin Client
+	client, err := newAdminClient(aliasedURL)
+	fatalIf(err, "Unable to initialize admin connection.")
+
+	current, err := getScannerExcludes(client)
+	fatalIf(err.Trace(aliasedURL), "Unable to get scanner exclusions")
+
+	remaining, removed, missing := removeScannerExcludes(current, entries)
+	var restart bool
+	if len(removed) > 0 {
+		restart, err = setScannerExcludes(client, remaining)
+		fatalIf(err.Trace(aliasedURL), "Unable to set scanner exclusions")
+	}
+
+	printMsg(scannerExcludeMessage{
+		Op:          "remove",
+		Excludes:    remaining,
+		Changed:     removed,
+		Missing:     missing,
+		Restart:     restart,
+		targetAlias: aliasedURL,
+	})
+
+	return nil
+}
@@ -0,0 +1,261 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-admin-go"
+	"github.com/trinet2005/oss-go-sdk/pkg/s3utils"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+// scannerExcludeKey is the scanner sub-system config key holding the
+// comma separated list of excluded buckets and prefixes.
+const scannerExcludeKey = "exclude"
+
+var errScannerExcludeUnsupported = errors.New("scanner exclusions are not supported by this server")
+
+var adminScannerExcludeSubcommands = []cli.Command{
+	adminScannerExcludeListCmd,
+	adminScannerExcludeAddCmd,
+	adminScannerExcludeRemoveCmd,
+}
+
+var adminScannerExcludeCmd = cli.Command{
+	Name:            "exclude",
+	Usage:           "manage buckets and prefixes skipped by the scanner",
+	Action:          mainAdminScannerExclude,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	Subcommands:     adminScannerExcludeSubcommands,
+	HideHelpCommand: true,
+}
+
+// mainAdminScannerExclude is the handle for "mc admin scanner exclude" command.
+func mainAdminScannerExclude(ctx *cli.Context) error {
+	commandNotFound(ctx, adminScannerExcludeSubcommands)
+	return nil
+	// Sub-commands like "list", "add", "remove" have their own main.
+}
+
+// parseScannerExcludes splits the server side config value into entries.
+func parseScannerExcludes(value string) []string {
+	entries := []string{}
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// normalizeScannerExclude validates a BUCKET[/PREFIX] argument and
+// returns it in the form stored in the server config.
+func normalizeScannerExclude(arg string) (string, *probe.Error) {
+	entry := strings.TrimPrefix(strings.TrimSpace(arg), "/")
+	if entry == "" {
+		return "", probe.NewError(errors.New("empty bucket name"))
+	}
+	if strings.ContainsAny(entry, ",\"") {
+		return "", probe.NewError(fmt.Errorf("'%s' must not contain ',' or '\"'", arg))
+	}
+
+	bucket, prefix, _ := strings.Cut(entry, "/")
+	if e := s3utils.CheckValidBucketNameStrict(bucket); e != nil {
+		return "", probe.NewError(fmt.Errorf("'%s': %v", arg, e))
+	}
+	if prefix == "" {
+		return bucket, nil
+	}
+	for _, elem := range strings.Split(strings.TrimSuffix(prefix, "/"), "/") {
+		if elem == "" || elem == "." || elem == ".." {
+			return "", probe.NewError(fmt.Errorf("'%s' is not a valid prefix", arg))
+		}
+	}
+	return bucket + "/" + prefix, nil
+}
+
+// normalizeScannerExcludes validates all arguments, dropping duplicates.
+func normalizeScannerExcludes(args []string) ([]string, *probe.Error) {
+	seen := make(map[string]bool, len(args))
+	entries := make([]string, 0, len(args))
+	for _, arg := range args {
+		entry, err := normalizeScannerExclude(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[entry] {
+			seen[entry] = true
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// addScannerExcludes merges entries into current, returning the sorted
+// result and the entries that were not already present.
+func addScannerExcludes(current, entries []string) (merged, added []string) {
+	exists := make(map[string]bool, len(current))
+	for _, entry := range current {
+		exists[entry] = true
+	}
+	merged = append(merged, current...)
+	for _, entry := range entries {
+		if !exists[entry] {
+			exists[entry] = true
+			merged = append(merged, entry)
+			added = append(added, entry)
+		}
+	}
+	sort.Strings(merged)
+	return merged, added
+}
+
+// removeScannerExcludes drops entries from current, returning what is
+// left, what was removed and the entries that were not configured.
+func removeScannerExcludes(current, entries []string) (remaining, removed, missing []string) {
+	drop := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		drop[entry] = true
+	}
+	found := make(map[string]bool, len(entries))
+	for _, entry := range current {
+		if drop[entry] {
+			found[entry] = true
+			continue
+		}
+		remaining = append(remaining, entry)
+	}
+	for _, entry := range entries {
+		if found[entry] {
+			removed = append(removed, entry)
+		} else {
+			missing = append(missing, entry)
+		}
+	}
+	return remaining, removed, missing
+}
+
+// scannerExcludeConfig returns the config set input for entries.
+func scannerExcludeConfig(entries []string) string {
+	return fmt.Sprintf("%s %s=\"%s\"", madmin.ScannerSubSys, scannerExcludeKey, strings.Join(entries, ","))
+}
+
+// getScannerExcludes fetches the configured exclusions from the server.
+func getScannerExcludes(client *madmin.AdminClient) ([]string, *probe.Error) {
+	scfgs, e := getMinIOSubSysConfig(client, madmin.ScannerSubSys)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	if len(scfgs) == 0 {
+		return nil, probe.NewError(errScannerExcludeUnsupported)
+	}
+	value, found := scfgs[0].Lookup(scannerExcludeKey)
+	if !found {
+		return nil, probe.NewError(errScannerExcludeUnsupported)
+	}
+	return parseScannerExcludes(value), nil
+}
+
+// setScannerExcludes stores entries as the new list of exclusions.
+func setScannerExcludes(client *madmin.AdminClient, entries []string) (bool, *probe.Error) {
+	restart, e := client.SetConfigKV(globalContext, scannerExcludeConfig(entries))
+	if e != nil {
+		return false, probe.NewError(e)
+	}
+	return restart, nil
+}
+
+// scannerExcludeMessage container for scanner exclusion results.
+type scannerExcludeMessage struct {
+	Status   string   `json:"status"`
+	Op       string   `json:"op"`
+	Excludes []string `json:"excludes"`
+	Changed  []string `json:"changed,omitempty"`
+	Missing  []string `json:"missing,omitempty"`
+	Restart  bool     `json:"restart,omitempty"`
+
+	targetAlias string
+}
+
+// String colorized scanner exclusion message.
+func (m scannerExcludeMessage) String() string {
+	var msg strings.Builder
+	switch m.Op {
+	case "list":
+		if len(m.Excludes) == 0 {
+			return console.Colorize("ScannerExcludeMessage", "No scanner exclusions configured.")
+		}
+		for i, entry := range m.Excludes {
+			if i > 0 {
+				msg.WriteString("\n")
+			}
+			msg.WriteString(console.Colorize("ScannerExclude", entry))
+		}
+		return msg.String()
+	case "add":
+		if len(m.Changed) == 0 {
+			msg.WriteString(console.Colorize("ScannerExcludeMessage", "All given entries are already excluded."))
+		} else {
+			msg.WriteString(console.Colorize("ScannerExcludeMessage",
+				fmt.Sprintf("Excluded %s from scanning.", strings.Join(m.Changed, ", "))))
+		}
+	case "remove":
+		if len(m.Changed) > 0 {
+			msg.WriteString(console.Colorize("ScannerExcludeMessage",
+				fmt.Sprintf("Removed %s from scanner exclusions.", strings.Join(m.Changed, ", "))))
+		}
+		if len(m.Missing) > 0 {
+			if msg.Len() > 0 {
+				msg.WriteString("\n")
+			}
+			msg.WriteString(console.Colorize("ScannerExcludeMissing",
+				fmt.Sprintf("Not excluded: %s", strings.Join(m.Missing, ", "))))
+		}
+	}
+	if m.Restart {
+		suggestion := color.RedString("mc admin service restart %s", m.targetAlias)
+		msg.WriteString(console.Colorize("ScannerExcludeMessage",
+			fmt.Sprintf("\nPlease restart your server '%s'.", suggestion)))
+	}
+	return msg.String()
+}
+
+// JSON jsonified scanner exclusion message.
+func (m scannerExcludeMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// setScannerExcludeColors sets the colors used by the exclude sub-commands.
+func setScannerExcludeColors() {
+	console.SetColor("ScannerExclude", color.New(color.FgCyan, color.Bold))
+	console.SetColor("ScannerExcludeMessage", color.New(color.FgGreen))
+	console.SetColor("ScannerExcludeMissing", color.New(color.FgYellow))
+}
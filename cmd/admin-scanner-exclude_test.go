@@ -0,0 +1,81 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeScannerExclude(t *testing.T) {
+	testCases := []struct {
+		arg      string
+		expected string
+		success  bool
+	}{
+		{"archive", "archive", true},
+		{"/archive", "archive", true},
+		{"archive/", "archive", true},
+		{"logs/2023/", "logs/2023/", true},
+		{"logs/2023/01", "logs/2023/01", true},
+		{"", "", false},
+		{"Archive", "", false},
+		{"ab", "", false},
+		{"logs//2023", "", false},
+		{"logs/../2023", "", false},
+		{"logs/a,b", "", false},
+		{`logs/"a"`, "", false},
+	}
+
+	for i, testCase := range testCases {
+		entry, err := normalizeScannerExclude(testCase.arg)
+		if testCase.success != (err == nil) {
+			t.Fatalf("Test %d: expected success %v, got error %v", i+1, testCase.success, err)
+		}
+		if entry != testCase.expected {
+			t.Fatalf("Test %d: expected %q, got %q", i+1, testCase.expected, entry)
+		}
+	}
+}
+
+func TestScannerExcludeMerge(t *testing.T) {
+	current := parseScannerExcludes(" logs/2023/, archive,,")
+	if !reflect.DeepEqual(current, []string{"logs/2023/", "archive"}) {
+		t.Fatalf("Test 1: unexpected parsed entries %v", current)
+	}
+
+	merged, added := addScannerExcludes(current, []string{"archive", "backup"})
+	if !reflect.DeepEqual(merged, []string{"archive", "backup", "logs/2023/"}) {
+		t.Fatalf("Test 2: unexpected merged entries %v", merged)
+	}
+	if !reflect.DeepEqual(added, []string{"backup"}) {
+		t.Fatalf("Test 2: unexpected added entries %v", added)
+	}
+
+	remaining, removed, missing := removeScannerExcludes(merged, []string{"backup", "other"})
+	if !reflect.DeepEqual(remaining, []string{"archive", "logs/2023/"}) {
+		t.Fatalf("Test 3: unexpected remaining entries %v", remaining)
+	}
+	if !reflect.DeepEqual(removed, []string{"backup"}) || !reflect.DeepEqual(missing, []string{"other"}) {
+		t.Fatalf("Test 3: unexpected removed %v or missing %v entries", removed, missing)
+	}
+
+	if cfg := scannerExcludeConfig(remaining); cfg != `scanner exclude="archive,logs/2023/"` {
+		t.Fatalf("Test 4: unexpected config input %s", cfg)
+	}
+}
@@ -111,7 +111,7 @@ func mainAdminScannerInfo(ctx *cli.Context) error {
 		Hosts:    strings.Split(ctx.String("nodes"), ","),
 		ByHost:   false,
 	}
-	ui := tea.NewProgram(initScannerMetricsUI(ctx.Int("max-paths")))
+	ui := newUIProgram(initScannerMetricsUI(ctx.Int("max-paths")))
 	if globalJSON {
 		e := client.Metrics(ctxt, opts, func(metrics madmin.RealtimeMetrics) {
 			printMsg(metricsMessage{RealtimeMetrics: metrics})
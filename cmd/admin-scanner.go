@@ -22,6 +22,7 @@ import "github.com/minio/cli"
 var adminScannerSubcommands = []cli.Command{
 	adminScannerInfo,
 	adminScannerTraceCmd,
+	adminScannerExcludeCmd,
 }
 
 var adminScannerCmd = cli.Command{
@@ -0,0 +1,230 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/olekukonko/tablewriter"
+	"github.com/trinet2005/oss-admin-go"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+var adminTraceHeatmapFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "heatmap",
+		Usage: "aggregate the S3 calls of the trace window by 'bucket' or 'prefix' and show the hottest ones",
+	},
+	cli.IntFlag{
+		Name:  "depth",
+		Value: 1,
+		Usage: "number of folders of the prefixes aggregated by '--heatmap prefix'",
+	},
+	cli.DurationFlag{
+		Name:  "window",
+		Value: time.Minute,
+		Usage: "duration of the trace aggregated by --heatmap",
+	},
+	cli.IntFlag{
+		Name:  "top",
+		Value: 20,
+		Usage: "number of the hottest prefixes shown by --heatmap",
+	},
+}
+
+// traceHeatmapBarWidth is the width of the bar of the hottest prefix.
+const traceHeatmapBarWidth = 30
+
+// traceHeatmapEntry holds the S3 calls of a prefix.
+type traceHeatmapEntry struct {
+	Prefix   string        `json:"prefix"`
+	Calls    int64         `json:"calls"`
+	Errors   int64         `json:"errors"`
+	Rx       int64         `json:"rx"`
+	Tx       int64         `json:"tx"`
+	Duration time.Duration `json:"duration"`
+}
+
+// traceHeatmap aggregates S3 calls by bucket or by the first depth
+// folders of their objects.
+type traceHeatmap struct {
+	depth   int
+	calls   int64
+	entries map[string]*traceHeatmapEntry
+}
+
+func newTraceHeatmap(depth int) *traceHeatmap {
+	return &traceHeatmap{depth: depth, entries: make(map[string]*traceHeatmapEntry)}
+}
+
+// traceHeatmapPrefix returns the prefix of the request path aggregated
+// at depth folders, empty for calls on no bucket.
+func traceHeatmapPrefix(urlPath string, depth int) string {
+	parts := strings.Split(strings.Trim(urlPath, "/"), "/")
+	if parts[0] == "" {
+		return ""
+	}
+	// Keep the bucket and the folders, leaving out the object name.
+	folders := len(parts) - 2
+	if folders > depth {
+		folders = depth
+	}
+	if folders < 0 {
+		folders = 0
+	}
+	return strings.Join(parts[:folders+1], "/") + "/"
+}
+
+// add aggregates an S3 call.
+func (h *traceHeatmap) add(t madmin.TraceInfo) {
+	if t.TraceType != madmin.TraceS3 {
+		return
+	}
+	prefix := traceHeatmapPrefix(t.Path, h.depth)
+	if prefix == "" {
+		return
+	}
+	entry, ok := h.entries[prefix]
+	if !ok {
+		entry = &traceHeatmapEntry{Prefix: prefix}
+		h.entries[prefix] = entry
+	}
+	h.calls++
+	entry.Calls++
+	if t.HTTP != nil {
+		if t.HTTP.RespInfo.StatusCode >= http.StatusBadRequest {
+			entry.Errors++
+		}
+		entry.Rx += int64(t.HTTP.CallStats.InputBytes)
+		entry.Tx += int64(t.HTTP.CallStats.OutputBytes)
+	}
+	entry.Duration += t.Duration
+}
+
+// hottest returns the top prefixes by calls, then by bytes transferred.
+func (h *traceHeatmap) hottest(top int) []traceHeatmapEntry {
+	entries := make([]traceHeatmapEntry, 0, len(h.entries))
+	for _, entry := range h.entries {
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Calls != entries[j].Calls {
+			return entries[i].Calls > entries[j].Calls
+		}
+		if bi, bj := entries[i].Rx+entries[i].Tx, entries[j].Rx+entries[j].Tx; bi != bj {
+			return bi > bj
+		}
+		return entries[i].Prefix < entries[j].Prefix
+	})
+	if top > 0 && len(entries) > top {
+		entries = entries[:top]
+	}
+	return entries
+}
+
+// traceHeatmapMessage reports the hottest prefixes of a trace window.
+type traceHeatmapMessage struct {
+	Status   string              `json:"status"`
+	Window   string              `json:"window"`
+	Calls    int64               `json:"calls"`
+	Prefixes []traceHeatmapEntry `json:"prefixes"`
+}
+
+// String colorized trace heatmap message
+func (t traceHeatmapMessage) String() string {
+	if len(t.Prefixes) == 0 {
+		return "No S3 calls traced in " + t.Window
+	}
+
+	var s strings.Builder
+
+	// Set table header
+	table := tablewriter.NewWriter(&s)
+	table.SetAutoWrapText(false)
+	table.SetAutoFormatHeaders(true)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+	table.SetTablePadding("\t") // pad with tabs
+	table.SetNoWhiteSpace(true)
+
+	table.SetHeader([]string{"PREFIX", "CALLS", "SHARE", "ERRORS", "RX", "TX", "AVG DURATION", ""})
+	data := make([][]string, 0, len(t.Prefixes))
+
+	hottest := t.Prefixes[0].Calls
+	for _, entry := range t.Prefixes {
+		bar := int(entry.Calls * traceHeatmapBarWidth / hottest)
+		if bar == 0 {
+			bar = 1
+		}
+		data = append(data, []string{
+			entry.Prefix,
+			humanize.Comma(entry.Calls),
+			fmt.Sprintf("%.1f%%", float64(entry.Calls)*100/float64(t.Calls)),
+			humanize.Comma(entry.Errors),
+			humanize.IBytes(uint64(entry.Rx)),
+			humanize.IBytes(uint64(entry.Tx)),
+			(entry.Duration / time.Duration(entry.Calls)).Round(time.Microsecond).String(),
+			console.Colorize("Stat", strings.Repeat("█", bar)),
+		})
+	}
+
+	table.AppendBulk(data)
+	table.Render()
+
+	return fmt.Sprintf("%s S3 calls traced in %s:\n%s", humanize.Comma(t.Calls), t.Window, s.String())
+}
+
+// JSON jsonified trace heatmap message
+func (t traceHeatmapMessage) JSON() string {
+	t.Status = "success"
+	traceHeatmapMessageBytes, e := json.MarshalIndent(t, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(traceHeatmapMessageBytes)
+}
+
+// checkAdminTraceHeatmapSyntax validates the --heatmap flags.
+func checkAdminTraceHeatmapSyntax(ctx *cli.Context) {
+	switch ctx.String("heatmap") {
+	case "", "bucket", "prefix":
+	default:
+		fatalIf(errInvalidArgument().Trace(ctx.String("heatmap")), "--heatmap should be 'bucket' or 'prefix'.")
+	}
+	if ctx.Int("depth") < 1 {
+		fatalIf(errInvalidArgument().Trace(ctx.String("depth")), "--depth should be at least 1.")
+	}
+	if ctx.Duration("window") <= 0 {
+		fatalIf(errInvalidArgument().Trace(ctx.String("window")), "--window should be positive.")
+	}
+	if ctx.Int("top") < 1 {
+		fatalIf(errInvalidArgument().Trace(ctx.String("top")), "--top should be at least 1.")
+	}
+}
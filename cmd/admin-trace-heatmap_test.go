@@ -0,0 +1,42 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import "testing"
+
+func TestTraceHeatmapPrefix(t *testing.T) {
+	testCases := []struct {
+		path     string
+		depth    int
+		expected string
+	}{
+		{"/", 1, ""},
+		{"/bucket", 1, "bucket/"},
+		{"/bucket/", 1, "bucket/"},
+		{"/bucket/object", 1, "bucket/"},
+		{"/bucket/dir/object", 0, "bucket/"},
+		{"/bucket/dir/object", 1, "bucket/dir/"},
+		{"/bucket/dir/object", 2, "bucket/dir/"},
+		{"/bucket/a/b/c/object", 2, "bucket/a/b/"},
+	}
+	for i, testCase := range testCases {
+		if prefix := traceHeatmapPrefix(testCase.path, testCase.depth); prefix != testCase.expected {
+			t.Fatalf("Test %d: expected %q, got %q", i+1, testCase.expected, prefix)
+		}
+	}
+}
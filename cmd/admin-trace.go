@@ -163,7 +163,7 @@ var adminTraceCmd = cli.Command{
 	Action:          mainAdminTrace,
 	OnUsageError:    onUsageError,
 	Before:          setGlobalsFromContext,
-	Flags:           append(adminTraceFlags, globalFlags...),
+	Flags:           append(append(adminTraceFlags, adminTraceHeatmapFlags...), globalFlags...),
 	HideHelpCommand: true,
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
@@ -209,6 +209,9 @@ EXAMPLES:
   
   8. Show trace only for requests operations duration greater than 5ms
      {{.Prompt}} {{.HelpName}} --response-duration 5ms myminio
+
+  9. Show the 10 prefixes two folders deep with the most S3 calls during 5 minutes
+     {{.Prompt}} {{.HelpName}} --heatmap prefix --depth 2 --window 5m --top 10 myminio
 `,
 }
 
@@ -229,6 +232,7 @@ func checkAdminTraceSyntax(ctx *cli.Context) {
 	if ctx.Bool("all") && len(ctx.StringSlice("call")) > 0 {
 		fatalIf(errDummy().Trace(), "You cannot specify both --all and --call flags at the same time.")
 	}
+	checkAdminTraceHeatmapSyntax(ctx)
 }
 
 func printTrace(verbose bool, traceInfo madmin.ServiceTraceInfo) {
@@ -464,17 +468,44 @@ func mainAdminTrace(ctx *cli.Context) error {
 
 	mopts := matchingOpts(ctx)
 
+	var heatmap *traceHeatmap
+	if mode := ctx.String("heatmap"); mode != "" {
+		depth := ctx.Int("depth")
+		if mode == "bucket" {
+			depth = 0
+		}
+		heatmap = newTraceHeatmap(depth)
+		ctxt, cancel = context.WithTimeout(ctxt, ctx.Duration("window"))
+		defer cancel()
+	}
+
 	// Start listening on all trace activity.
 	traceCh := client.ServiceTrace(ctxt, opts)
 	for traceInfo := range traceCh {
 		if traceInfo.Err != nil {
+			if heatmap != nil && ctxt.Err() != nil {
+				// End of the heatmap window.
+				break
+			}
 			fatalIf(probe.NewError(traceInfo.Err), "Unable to listen to http trace")
 		}
-		if matchTrace(mopts, traceInfo) {
-			printTrace(verbose, traceInfo)
+		if !matchTrace(mopts, traceInfo) {
+			continue
 		}
+		if heatmap != nil {
+			heatmap.add(traceInfo.Trace)
+			continue
+		}
+		printTrace(verbose, traceInfo)
 	}
 
+	if heatmap != nil {
+		printMsg(traceHeatmapMessage{
+			Window:   ctx.Duration("window").String(),
+			Calls:    heatmap.calls,
+			Prefixes: heatmap.hottest(ctx.Int("top")),
+		})
+	}
 	return nil
 }
 
@@ -26,6 +26,7 @@ import (
 	"github.com/fatih/color"
 	"github.com/minio/cli"
 	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/i18n"
 	"github.com/trinet2005/oss-mc/pkg/probe"
 	"github.com/trinet2005/oss-pkg/console"
 )
@@ -116,12 +117,12 @@ func mainAdminServerUpdate(ctx *cli.Context) error {
 	autoConfirm := ctx.Bool("yes")
 
 	if isTerminal() && !autoConfirm {
-		fmt.Printf("You are about to upgrade *MinIO Server*, please confirm [y/N]: ")
+		fmt.Print(i18n.T("You are about to upgrade *MinIO Server*, please confirm [y/N]: "))
 		answer, e := bufio.NewReader(os.Stdin).ReadString('\n')
 		fatalIf(probe.NewError(e), "Unable to parse user input.")
 		answer = strings.TrimSpace(answer)
 		if answer = strings.ToLower(answer); answer != "y" && answer != "yes" {
-			fmt.Println("Upgrade aborted!")
+			fmt.Println(i18n.T("Upgrade aborted!"))
 			return nil
 		}
 	}
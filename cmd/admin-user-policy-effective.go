@@ -0,0 +1,253 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-admin-go"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+	"github.com/trinet2005/oss-pkg/policy"
+)
+
+var adminUserPolicyEffectiveCmd = cli.Command{
+	Name:         "effective",
+	Usage:        "show the actions a user is allowed on a bucket or prefix",
+	Action:       mainAdminUserPolicyEffective,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET USERNAME BUCKET[/PREFIX]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Evaluate GetObject, PutObject, DeleteObject and ListBucket for USERNAME on BUCKET[/PREFIX].
+  An action is allowed when the policies attached to the user and to its groups allow it, an
+  explicit deny in any of them wins, or when the bucket policy allows it for the user.
+
+EXAMPLES:
+  1. Show what user "foobar" is allowed on bucket "photos".
+     {{.Prompt}} {{.HelpName}} myminio foobar photos
+
+  2. Show what user "foobar" is allowed under the prefix "2023/" of bucket "photos".
+     {{.Prompt}} {{.HelpName}} myminio foobar photos/2023/
+`,
+}
+
+// userPolicyEffectiveActions are the actions evaluated, the most common ones.
+var userPolicyEffectiveActions = []policy.Action{
+	policy.GetObjectAction,
+	policy.PutObjectAction,
+	policy.DeleteObjectAction,
+	policy.ListBucketAction,
+}
+
+// userPolicyDecision is the decision for one action.
+type userPolicyDecision struct {
+	Action    string `json:"action"`
+	User      bool   `json:"user"`
+	Bucket    bool   `json:"bucket"`
+	Effective bool   `json:"allowed"`
+}
+
+// userPolicyEffectiveMessage is the decision table of a user on a resource.
+type userPolicyEffectiveMessage struct {
+	Status    string               `json:"status"`
+	User      string               `json:"user"`
+	Resource  string               `json:"resource"`
+	Policies  []string             `json:"policies"`
+	Decisions []userPolicyDecision `json:"decisions"`
+}
+
+func (u userPolicyEffectiveMessage) String() string {
+	decision := func(allowed bool) string {
+		if allowed {
+			return console.Colorize("PolicyAllow", fmt.Sprintf("%-8s", "allow"))
+		}
+		return console.Colorize("PolicyDeny", fmt.Sprintf("%-8s", "deny"))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "User:     %s\n", u.User)
+	fmt.Fprintf(&b, "Resource: %s\n", u.Resource)
+	fmt.Fprintf(&b, "Policies: %s\n\n", strings.Join(u.Policies, ", "))
+	fmt.Fprintf(&b, "%-20s %-8s %-8s %s\n", "ACTION", "USER", "BUCKET", "EFFECTIVE")
+	for _, d := range u.Decisions {
+		fmt.Fprintf(&b, "%-20s %s %s %s\n", d.Action, decision(d.User), decision(d.Bucket), decision(d.Effective))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (u userPolicyEffectiveMessage) JSON() string {
+	u.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(u, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// checkAdminUserPolicyEffectiveSyntax - validate all the passed arguments
+func checkAdminUserPolicyEffectiveSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 3 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+}
+
+// splitPolicyNames splits comma separated policy names, as attached to
+// users and groups.
+func splitPolicyNames(names string) []string {
+	var policies []string
+	for _, name := range strings.Split(names, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			policies = append(policies, name)
+		}
+	}
+	return policies
+}
+
+// evaluateUserPolicy returns the decision of user, member of groups, for
+// each of the common actions on bucket/prefix. bucketPolicy is nil when
+// the bucket has none.
+func evaluateUserPolicy(userPolicy policy.Policy, bucketPolicy *policy.BucketPolicy, user string, groups []string, bucket, prefix string) []userPolicyDecision {
+	conditionValues := map[string][]string{
+		"username":      {user},
+		"userid":        {user},
+		"principaltype": {"User"},
+	}
+
+	decisions := make([]userPolicyDecision, 0, len(userPolicyEffectiveActions))
+	for _, action := range userPolicyEffectiveActions {
+		objectName := prefix
+		values := conditionValues
+		if action == policy.ListBucketAction {
+			// Listing applies to the bucket, prefix is a condition.
+			objectName = ""
+			values = map[string][]string{"prefix": {prefix}}
+			for k, v := range conditionValues {
+				values[k] = v
+			}
+		}
+
+		d := userPolicyDecision{Action: string(action)}
+		d.User = userPolicy.IsAllowed(policy.Args{
+			AccountName:     user,
+			Groups:          groups,
+			Action:          action,
+			BucketName:      bucket,
+			ObjectName:      objectName,
+			ConditionValues: values,
+		})
+		if bucketPolicy != nil {
+			d.Bucket = bucketPolicy.IsAllowed(policy.BucketPolicyArgs{
+				AccountName:     user,
+				Groups:          groups,
+				Action:          action,
+				BucketName:      bucket,
+				ObjectName:      objectName,
+				ConditionValues: values,
+			})
+		}
+		d.Effective = d.User || d.Bucket
+		decisions = append(decisions, d)
+	}
+	return decisions
+}
+
+// mainAdminUserPolicyEffective is the handler for "mc admin user policy effective" command.
+func mainAdminUserPolicyEffective(ctx *cli.Context) error {
+	checkAdminUserPolicyEffectiveSyntax(ctx)
+
+	console.SetColor("PolicyAllow", color.New(color.FgGreen, color.Bold))
+	console.SetColor("PolicyDeny", color.New(color.FgRed, color.Bold))
+
+	args := ctx.Args()
+	aliasedURL := args.Get(0)
+	username := args.Get(1)
+	resource := strings.TrimPrefix(args.Get(2), "/")
+	bucket, prefix, _ := strings.Cut(resource, "/")
+	if bucket == "" {
+		fatalIf(errInvalidArgument().Trace(args.Get(2)), "Resource should be BUCKET or BUCKET/PREFIX.")
+	}
+
+	// Create a new MinIO Admin Client
+	client, err := newAdminClient(aliasedURL)
+	fatalIf(err, "Unable to initialize admin connection.")
+
+	user, e := client.GetUserInfo(globalContext, username)
+	fatalIf(probe.NewError(e).Trace(args...), "Unable to get user info")
+
+	// Policies attached to the user and to its groups are combined,
+	// so that an explicit deny in any of them wins.
+	policyNames := splitPolicyNames(user.PolicyName)
+	for _, group := range user.MemberOf {
+		gd, e := client.GetGroupDescription(globalContext, group)
+		fatalIf(probe.NewError(e).Trace(group), "Unable to fetch group info")
+		policyNames = append(policyNames, splitPolicyNames(gd.Policy)...)
+	}
+	policies := make([]policy.Policy, 0, len(policyNames))
+	seen := make(map[string]bool, len(policyNames))
+	names := []string{}
+	for _, name := range policyNames {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+		policies = append(policies, getUserPolicyDocument(client, name))
+	}
+
+	// The bucket policy, if any, may allow the user too.
+	var bucketPolicy *policy.BucketPolicy
+	bucketURL := urlJoinPath(cleanAlias(aliasedURL), bucket)
+	clnt, err := newClient(bucketURL)
+	fatalIf(err.Trace(bucketURL), "Unable to initialize `"+bucketURL+"`.")
+	_, policyJSON, err := clnt.GetAccess(globalContext)
+	fatalIf(err.Trace(bucketURL), "Unable to get the policy of `"+bucketURL+"`.")
+	if policyJSON != "" {
+		bucketPolicy, e = policy.ParseBucketPolicyConfig(strings.NewReader(policyJSON), bucket)
+		fatalIf(probe.NewError(e).Trace(bucketURL), "Unable to parse the policy of `"+bucketURL+"`.")
+	}
+
+	printMsg(userPolicyEffectiveMessage{
+		User:      username,
+		Resource:  resource,
+		Policies:  names,
+		Decisions: evaluateUserPolicy(policy.MergePolicies(policies...), bucketPolicy, username, user.MemberOf, bucket, prefix),
+	})
+	return nil
+}
+
+// getUserPolicyDocument returns the parsed policy document name.
+func getUserPolicyDocument(client *madmin.AdminClient, name string) policy.Policy {
+	pinfo, e := getPolicyInfo(client, name)
+	fatalIf(probe.NewError(e).Trace(name), "Unable to fetch policy document `"+name+"`.")
+	p, e := policy.ParseConfig(strings.NewReader(string(pinfo.Policy)))
+	fatalIf(probe.NewError(e).Trace(name), "Unable to parse policy document `"+name+"`.")
+	return *p
+}
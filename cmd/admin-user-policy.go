@@ -33,16 +33,24 @@ var adminUserPolicyCmd = cli.Command{
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
 	Flags:        globalFlags,
+	Subcommands: []cli.Command{
+		adminUserPolicyEffectiveCmd,
+	},
+	HideHelpCommand: true,
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 USAGE:
   {{.HelpName}} TARGET USERNAME
+  {{.HelpName}} effective TARGET USERNAME BUCKET[/PREFIX]
 FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}
 EXAMPLES:
   1. Display the policy document of a user "foobar" in JSON format.
      {{.Prompt}} {{.HelpName}} myminio foobar
+
+  2. Show what user "foobar" is allowed on bucket "photos", after combining its policies and the bucket policy.
+     {{.Prompt}} {{.HelpName}} effective myminio foobar photos
 `,
 }
 
@@ -0,0 +1,336 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	minio "github.com/trinet2005/oss-go-sdk"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+var aliasDoctorFlags = []cli.Flag{
+	cli.DurationFlag{
+		Name:  "timeout",
+		Value: 10 * time.Second,
+		Usage: "timeout of every network check",
+	},
+	cli.IntFlag{
+		Name:  "expiry-warn-days",
+		Value: 30,
+		Usage: "warn when the server certificate expires within this number of days",
+	},
+}
+
+var aliasDoctorCmd = cli.Command{
+	Name:            "doctor",
+	Usage:           "diagnose the connection to an alias step by step",
+	Action:          mainAliasDoctor,
+	Before:          setGlobalsFromContext,
+	Flags:           append(aliasDoctorFlags, globalFlags...),
+	HideHelpCommand: true,
+	OnUsageError:    onUsageError,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] ALIAS
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+CHECKS:
+  dns        resolve the host name of the alias URL
+  tcp        connect to the server port
+  tls        verify the certificate chain and its expiry (HTTPS only)
+  clock      compare the local clock with the server time, requests are
+             rejected beyond a skew of 15 minutes
+  signature  authenticate with the alias credentials
+  buckets    list the buckets with the alias credentials
+
+  Checks depending on a failed one are skipped.
+
+EXAMPLES:
+  1. Diagnose the connection to "myminio".
+     {{.Prompt}} {{.HelpName}} myminio
+
+  2. Diagnose the connection to "myminio" with a short timeout, as JSON lines.
+     {{.Prompt}} {{.HelpName}} --timeout 3s --json myminio
+`,
+}
+
+// Results of the checks of 'alias doctor'.
+const (
+	doctorPass = "pass"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+	doctorSkip = "skip"
+)
+
+// Colors of the results of 'alias doctor'.
+var doctorColors = map[string]string{
+	doctorPass: "DoctorPass",
+	doctorWarn: "DoctorWarn",
+	doctorFail: "DoctorFail",
+	doctorSkip: "DoctorSkip",
+}
+
+// Maximum clock skew accepted by S3 servers.
+const doctorMaxClockSkew = 15 * time.Minute
+
+// aliasDoctorMessage is the result of a single check.
+type aliasDoctorMessage struct {
+	Status   string `json:"status"`
+	Alias    string `json:"alias"`
+	Check    string `json:"check"`
+	Result   string `json:"result"`
+	Detail   string `json:"detail,omitempty"`
+	Duration string `json:"duration,omitempty"`
+}
+
+// String colorized alias doctor message
+func (m aliasDoctorMessage) String() string {
+	line := console.Colorize(doctorColors[m.Result], fmt.Sprintf("%-4s", strings.ToUpper(m.Result))) +
+		"  " + console.Colorize("DoctorCheck", fmt.Sprintf("%-9s", m.Check))
+	if m.Detail != "" {
+		line += "  " + m.Detail
+	}
+	if m.Duration != "" {
+		line += " (" + m.Duration + ")"
+	}
+	return line
+}
+
+// JSON jsonified alias doctor message
+func (m aliasDoctorMessage) JSON() string {
+	m.Status = "success"
+	if m.Result == doctorFail {
+		m.Status = "error"
+	}
+	msgBytes, e := json.Marshal(m)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(msgBytes)
+}
+
+// aliasDoctor runs the checks of an alias, each one depending
+// on the success of the previous ones.
+type aliasDoctor struct {
+	alias          string
+	hostCfg        *aliasConfigV10
+	endpoint       *url.URL
+	timeout        time.Duration
+	expiryWarnDays int
+	failed         bool
+}
+
+// run runs a check, skipping it after a failure, and prints its result.
+func (d *aliasDoctor) run(check string, fn func(ctx context.Context) (result, detail string)) {
+	msg := aliasDoctorMessage{Alias: d.alias, Check: check, Result: doctorSkip}
+	if !d.failed {
+		ctx, cancel := context.WithTimeout(globalContext, d.timeout)
+		start := time.Now()
+		msg.Result, msg.Detail = fn(ctx)
+		msg.Duration = time.Since(start).Round(time.Millisecond).String()
+		cancel()
+	}
+	if msg.Result == doctorFail {
+		d.failed = true
+	}
+	printMsg(msg)
+}
+
+// hostPort returns the address of the server.
+func (d *aliasDoctor) hostPort() string {
+	if port := d.endpoint.Port(); port != "" {
+		return d.endpoint.Host
+	}
+	if d.endpoint.Scheme == "https" {
+		return net.JoinHostPort(d.endpoint.Hostname(), "443")
+	}
+	return net.JoinHostPort(d.endpoint.Hostname(), "80")
+}
+
+func (d *aliasDoctor) checkDNS(ctx context.Context) (string, string) {
+	addrs, e := net.DefaultResolver.LookupHost(ctx, d.endpoint.Hostname())
+	if e != nil {
+		return doctorFail, e.Error()
+	}
+	return doctorPass, strings.Join(addrs, ", ")
+}
+
+func (d *aliasDoctor) checkTCP(ctx context.Context) (string, string) {
+	conn, e := (&net.Dialer{}).DialContext(ctx, "tcp", d.hostPort())
+	if e != nil {
+		return doctorFail, e.Error()
+	}
+	defer conn.Close()
+	return doctorPass, "connected to " + conn.RemoteAddr().String()
+}
+
+func (d *aliasDoctor) checkTLS(ctx context.Context) (string, string) {
+	if d.endpoint.Scheme != "https" {
+		return doctorSkip, "plain HTTP endpoint"
+	}
+	dialer := &tls.Dialer{Config: &tls.Config{
+		ServerName: d.endpoint.Hostname(),
+		RootCAs:    globalRootCAs,
+		MinVersion: tls.VersionTLS12,
+		// The chain is verified below, to report
+		// the certificate even when it is invalid.
+		InsecureSkipVerify: true,
+	}}
+	conn, e := dialer.DialContext(ctx, "tcp", d.hostPort())
+	if e != nil {
+		return doctorFail, e.Error()
+	}
+	defer conn.Close()
+
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return doctorFail, "no certificate sent by the server"
+	}
+	leaf := certs[0]
+	days := int(time.Until(leaf.NotAfter).Hours() / 24)
+	detail := fmt.Sprintf("%q issued by %q, expires in %d day(s)", leaf.Subject.CommonName, leaf.Issuer.CommonName, days)
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, e = leaf.Verify(x509.VerifyOptions{
+		DNSName:       d.endpoint.Hostname(),
+		Roots:         globalRootCAs,
+		Intermediates: intermediates,
+	})
+	switch {
+	case e != nil && globalInsecure:
+		return doctorWarn, detail + ", not verified (--insecure): " + e.Error()
+	case e != nil:
+		return doctorFail, detail + ": " + e.Error()
+	case days < d.expiryWarnDays:
+		return doctorWarn, detail
+	}
+	return doctorPass, detail
+}
+
+func (d *aliasDoctor) checkClock(ctx context.Context) (string, string) {
+	skew, err := measureClockSkew(ctx, d.endpoint.String(), d.timeout)
+	if err != nil {
+		return doctorFail, err.ToGoError().Error()
+	}
+	detail := describeClockSkew(skew)
+	switch {
+	case skew > doctorMaxClockSkew || skew < -doctorMaxClockSkew:
+		return doctorFail, detail
+	case skew > time.Minute || skew < -time.Minute:
+		return doctorWarn, detail
+	}
+	return doctorPass, detail
+}
+
+func (d *aliasDoctor) checkSignature(ctx context.Context) (string, string) {
+	stype, err := probeS3Signature(ctx, d.hostCfg.AccessKey, d.hostCfg.SecretKey, d.hostCfg.URL, nil)
+	if err != nil {
+		return doctorFail, err.ToGoError().Error()
+	}
+	if !strings.EqualFold(stype, d.hostCfg.API) {
+		return doctorWarn, fmt.Sprintf("server accepts %s but the alias is configured with %s", stype, d.hostCfg.API)
+	}
+	return doctorPass, "credentials accepted with " + stype
+}
+
+func (d *aliasDoctor) checkBuckets(ctx context.Context) (string, string) {
+	clnt, err := newClientFromAlias(d.alias, d.hostCfg.URL)
+	if err != nil {
+		return doctorFail, err.ToGoError().Error()
+	}
+	buckets, err := clnt.ListBuckets(ctx)
+	if err != nil {
+		if minio.ToErrorResponse(err.ToGoError()).Code == "AccessDenied" {
+			return doctorWarn, "credentials are valid but not allowed to list buckets"
+		}
+		return doctorFail, err.ToGoError().Error()
+	}
+	return doctorPass, fmt.Sprintf("%d bucket(s) visible", len(buckets))
+}
+
+// checkAliasDoctorSyntax - verifies input arguments to 'alias doctor'.
+func checkAliasDoctorSyntax(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fatalIf(errInvalidArgument().Trace(args...),
+			"Incorrect number of arguments for alias doctor command.")
+	}
+
+	alias := cleanAlias(args.Get(0))
+	if !isValidAlias(alias) {
+		fatalIf(errDummy().Trace(alias), "Invalid alias `"+alias+"`.")
+	}
+	if ctx.Duration("timeout") <= 0 {
+		fatalIf(errInvalidArgument().Trace(ctx.String("timeout")), "--timeout should be positive.")
+	}
+}
+
+// mainAliasDoctor is the handle for "mc alias doctor" command.
+func mainAliasDoctor(ctx *cli.Context) error {
+	checkAliasDoctorSyntax(ctx)
+
+	console.SetColor("DoctorPass", color.New(color.FgGreen, color.Bold))
+	console.SetColor("DoctorWarn", color.New(color.FgYellow, color.Bold))
+	console.SetColor("DoctorFail", color.New(color.FgRed, color.Bold))
+	console.SetColor("DoctorSkip", color.New(color.FgHiBlack))
+	console.SetColor("DoctorCheck", color.New(color.FgCyan))
+
+	alias := cleanAlias(ctx.Args().Get(0))
+	aliasMustExist(alias)
+	hostCfg := mustGetHostConfig(alias)
+
+	endpoint, e := url.Parse(hostCfg.URL)
+	fatalIf(probe.NewError(e).Trace(hostCfg.URL), "Unable to parse the URL of `"+alias+"`.")
+
+	d := &aliasDoctor{
+		alias:          alias,
+		hostCfg:        hostCfg,
+		endpoint:       endpoint,
+		timeout:        ctx.Duration("timeout"),
+		expiryWarnDays: ctx.Int("expiry-warn-days"),
+	}
+	d.run("dns", d.checkDNS)
+	d.run("tcp", d.checkTCP)
+	d.run("tls", d.checkTLS)
+	d.run("clock", d.checkClock)
+	d.run("signature", d.checkSignature)
+	d.run("buckets", d.checkBuckets)
+
+	if d.failed {
+		return exitStatus(globalErrorExitStatus)
+	}
+	return nil
+}
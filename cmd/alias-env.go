@@ -0,0 +1,225 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-ieproxy"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-go-sdk/pkg/credentials"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+var aliasEnvFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "shell",
+		Value: "sh",
+		Usage: "shell syntax of the exports, one of 'sh', 'fish' or 'powershell'",
+	},
+	cli.BoolFlag{
+		Name:  "temporary, t",
+		Usage: "export temporary credentials minted with STS AssumeRole instead of the alias credentials",
+	},
+	cli.DurationFlag{
+		Name:  "duration",
+		Value: time.Hour,
+		Usage: "validity of the temporary credentials, between 15m and 12h",
+	},
+}
+
+var aliasEnvCmd = cli.Command{
+	Name:  "env",
+	Usage: "print shell exports of the credentials of an alias",
+	Action: func(ctx *cli.Context) error {
+		return mainAliasEnv(ctx)
+	},
+	Before:          setGlobalsFromContext,
+	Flags:           append(aliasEnvFlags, globalFlags...),
+	HideHelpCommand: true,
+	OnUsageError:    onUsageError,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] ALIAS
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+NOTE:
+  The exports contain secrets, evaluate them instead of saving them to a file.
+
+EXAMPLES:
+  1. Export the credentials of "myminio" to the current shell.
+     {{.Prompt}} eval "$({{.HelpName}} myminio)"
+
+  2. Run a tool with temporary credentials of "myminio" valid for 15 minutes.
+     {{.Prompt}} (eval "$({{.HelpName}} --temporary --duration 15m myminio)" && aws s3 ls)
+
+  3. Export the credentials of "myminio" to a fish shell.
+     {{.Prompt}} {{.HelpName}} --shell fish myminio | source
+`,
+}
+
+// aliasEnvMessage container for alias env message
+type aliasEnvMessage struct {
+	shell        string
+	Status       string     `json:"status"`
+	Alias        string     `json:"alias"`
+	Endpoint     string     `json:"endpoint"`
+	AccessKey    string     `json:"accessKey"`
+	SecretKey    string     `json:"secretKey"`
+	SessionToken string     `json:"sessionToken,omitempty"`
+	Expiration   *time.Time `json:"expiration,omitempty"`
+}
+
+// envVars returns the variables to export in a stable order.
+func (a aliasEnvMessage) envVars() [][2]string {
+	vars := [][2]string{
+		{"AWS_ACCESS_KEY_ID", a.AccessKey},
+		{"AWS_SECRET_ACCESS_KEY", a.SecretKey},
+	}
+	if a.SessionToken != "" {
+		vars = append(vars, [2]string{"AWS_SESSION_TOKEN", a.SessionToken})
+	}
+	return append(vars, [2]string{"AWS_ENDPOINT_URL", a.Endpoint})
+}
+
+// String shell exports of the alias credentials
+func (a aliasEnvMessage) String() string {
+	var lines []string
+	for _, v := range a.envVars() {
+		lines = append(lines, shellExport(a.shell, v[0], v[1]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// JSON jsonified alias env message
+func (a aliasEnvMessage) JSON() string {
+	a.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(a, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// shellExport returns a statement setting the environment
+// variable name to value in the given shell syntax.
+func shellExport(shell, name, value string) string {
+	switch shell {
+	case "fish":
+		return fmt.Sprintf("set -gx %s '%s';", name, strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value))
+	case "powershell":
+		return fmt.Sprintf("$Env:%s = '%s'", name, strings.ReplaceAll(value, "'", "''"))
+	default:
+		return fmt.Sprintf("export %s='%s'", name, strings.ReplaceAll(value, "'", `'\''`))
+	}
+}
+
+// checkAliasEnvSyntax - verifies input arguments to 'alias env'.
+func checkAliasEnvSyntax(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fatalIf(errInvalidArgument().Trace(args...),
+			"Incorrect number of arguments for alias env command.")
+	}
+
+	alias := cleanAlias(args.Get(0))
+	if !isValidAlias(alias) {
+		fatalIf(errDummy().Trace(alias), "Invalid alias `"+alias+"`.")
+	}
+
+	switch ctx.String("shell") {
+	case "sh", "fish", "powershell":
+	default:
+		fatalIf(errInvalidArgument().Trace(ctx.String("shell")),
+			"Unsupported shell `"+ctx.String("shell")+"`, expected one of 'sh', 'fish' or 'powershell'.")
+	}
+
+	if ctx.IsSet("duration") && !ctx.Bool("temporary") {
+		fatalIf(errInvalidArgument().Trace(args...), "--duration requires --temporary.")
+	}
+	if d := ctx.Duration("duration"); d < 15*time.Minute || d > 12*time.Hour {
+		fatalIf(errInvalidArgument().Trace(d.String()), "--duration should be between 15m and 12h.")
+	}
+}
+
+// assumeRole mints temporary credentials for the alias with STS AssumeRole.
+func assumeRole(hostCfg *aliasConfigV10, duration time.Duration) (credentials.Value, *probe.Error) {
+	creds := credentials.New(&credentials.STSAssumeRole{
+		Client: &http.Client{
+			Transport: &http.Transport{
+				Proxy: ieproxy.GetProxyFunc(),
+				TLSClientConfig: &tls.Config{
+					RootCAs:            globalRootCAs,
+					InsecureSkipVerify: globalInsecure,
+					MinVersion:         tls.VersionTLS12,
+				},
+			},
+		},
+		STSEndpoint: hostCfg.URL,
+		Options: credentials.STSAssumeRoleOptions{
+			AccessKey:       hostCfg.AccessKey,
+			SecretKey:       hostCfg.SecretKey,
+			DurationSeconds: int(duration.Seconds()),
+		},
+	})
+	value, e := creds.Get()
+	if e != nil {
+		return value, probe.NewError(e)
+	}
+	return value, nil
+}
+
+// mainAliasEnv is the handle for "mc alias env" command.
+func mainAliasEnv(ctx *cli.Context) error {
+	checkAliasEnvSyntax(ctx)
+
+	alias := cleanAlias(ctx.Args().Get(0))
+	aliasMustExist(alias)
+	hostCfg := mustGetHostConfig(alias)
+
+	msg := aliasEnvMessage{
+		shell:        ctx.String("shell"),
+		Alias:        alias,
+		Endpoint:     hostCfg.URL,
+		AccessKey:    hostCfg.AccessKey,
+		SecretKey:    hostCfg.SecretKey,
+		SessionToken: hostCfg.SessionToken,
+	}
+
+	if ctx.Bool("temporary") {
+		duration := ctx.Duration("duration")
+		value, err := assumeRole(hostCfg, duration)
+		fatalIf(err.Trace(alias), "Unable to mint temporary credentials for `"+alias+"`.")
+		msg.AccessKey = value.AccessKeyID
+		msg.SecretKey = value.SecretAccessKey
+		msg.SessionToken = value.SessionToken
+		expiration := UTCNow().Add(duration)
+		msg.Expiration = &expiration
+	}
+
+	printMsg(msg)
+	return nil
+}
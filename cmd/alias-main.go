@@ -42,6 +42,8 @@ var aliasSubcommands = []cli.Command{
 	aliasListCmd,
 	aliasRemoveCmd,
 	aliasImportCmd,
+	aliasEnvCmd,
+	aliasDoctorCmd,
 }
 
 var aliasCmd = cli.Command{
@@ -72,6 +74,7 @@ type aliasMessage struct {
 	SecretKey   string `json:"secretKey,omitempty"`
 	API         string `json:"api,omitempty"`
 	Path        string `json:"path,omitempty"`
+	Protected   bool   `json:"protected,omitempty"`
 	// Deprecated field, replaced by Path
 	Lookup string `json:"lookup,omitempty"`
 }
@@ -83,20 +86,26 @@ func (h aliasMessage) String() string {
 	switch h.op {
 	case "list":
 		// Create a new pretty table with cols configuration
-		t := newPrettyRecord(2,
-			Row{"Alias", "Alias"},
-			Row{"URL", "URL"},
-			Row{"AccessKey", "AccessKey"},
-			Row{"SecretKey", "SecretKey"},
-			Row{"API", "API"},
-			Row{"Path", "Path"},
-		)
+		rows := []Row{
+			{"Alias", "Alias"},
+			{"URL", "URL"},
+			{"AccessKey", "AccessKey"},
+			{"SecretKey", "SecretKey"},
+			{"API", "API"},
+			{"Path", "Path"},
+		}
 		// Handle deprecated lookup
 		path := h.Path
 		if path == "" {
 			path = h.Lookup
 		}
-		return t.buildRecord(h.Alias, h.URL, h.AccessKey, h.SecretKey, h.API, path)
+		contents := []string{h.Alias, h.URL, h.AccessKey, h.SecretKey, h.API, path}
+		if h.Protected {
+			rows = append(rows, Row{"Protected", "Protected"})
+			contents = append(contents, "true")
+		}
+		t := newPrettyRecord(2, rows...)
+		return t.buildRecord(contents...)
 	case "remove":
 		return console.Colorize("AliasMessage", "Removed `"+h.Alias+"` successfully.")
 	case "add": // add is deprecated
@@ -50,6 +50,10 @@ var aliasSetFlags = []cli.Flag{
 		Name:  "api",
 		Usage: "API signature. Valid options are '[S3v4, S3v2]'",
 	},
+	cli.BoolFlag{
+		Name:  "protected",
+		Usage: "require the approval of a second operator for destructive commands on this alias",
+	},
 }
 
 var aliasSetCmd = cli.Command{
@@ -96,6 +100,11 @@ EXAMPLES:
      {{.Prompt}} echo -e "BKIKJAA5BMMU2RHO6IBB\nV8f1CwQqAcwo80UEIJEjc5gVQUSSx5ohQ9GSrr12" | \
                  {{.HelpName}} mys3 https://s3.amazonaws.com --api "s3v4" --path "off"
      {{.EnableHistory}}
+  6. Add MinIO service under "prod" alias, requiring a second operator to approve destructive commands
+     with 'mc approve'. For security reasons turn off bash history momentarily.
+     {{.DisableHistory}}
+     {{.Prompt}} {{.HelpName}} prod https://minio.example.com minio minio123 --protected
+     {{.EnableHistory}}
 `,
 }
 
@@ -348,6 +357,7 @@ func mainAliasSet(cli *cli.Context, deprecated bool) error {
 		SecretKey: s3Config.SecretKey,
 		API:       s3Config.Signature,
 		Path:      path,
+		Protected: cli.Bool("protected"),
 	}) // Add an alias with specified credentials.
 
 	msg.op = "set"
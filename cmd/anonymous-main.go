@@ -37,6 +37,10 @@ var anonymousFlags = []cli.Flag{
 		Name:  "recursive, r",
 		Usage: "list recursively",
 	},
+	cli.StringFlag{
+		Name:  "log",
+		Usage: "trace recorded with 'mc admin trace --json' replayed by simulate, defaults to STDIN",
+	},
 }
 
 // Manage anonymous access to buckets and objects.
@@ -56,6 +60,7 @@ USAGE:
   {{.HelpName}} [FLAGS] get TARGET
   {{.HelpName}} [FLAGS] get-json TARGET
   {{.HelpName}} [FLAGS] list TARGET
+  {{.HelpName}} [FLAGS] simulate TARGET FILE
 {{if .VisibleFlags}}
 FLAGS:
   {{range .VisibleFlags}}{{.}}
@@ -66,6 +71,11 @@ PERMISSION:
 FILE:
   A valid S3 anonymous JSON filepath.
 
+SIMULATE:
+  Replay the S3 requests on TARGET of a trace recorded with 'mc admin trace --json' against the
+  current anonymous policy and the policy of FILE, without applying it, and report the requests
+  which would be newly denied or newly allowed anonymously.
+
 EXAMPLES:
   1. Set bucket to "download" on Amazon S3 cloud storage.
      {{.Prompt}} {{.HelpName}} set download s3/mybucket
@@ -93,6 +103,10 @@ EXAMPLES:
 
   9. List public object URLs recursively.
      {{.Prompt}} {{.HelpName}} --recursive links s3/shared/
+
+  10. Check which recorded requests a tightened policy would deny before setting it.
+     {{.Prompt}} mc admin trace --json myminio > trace.json
+     {{.Prompt}} {{.HelpName}} --log trace.json simulate myminio/shared /path/to/anonymous.json
 `,
 }
 
@@ -224,6 +238,11 @@ func checkAnonymousSyntax(ctx *cli.Context) {
 		if argsLength != 2 {
 			showCommandHelpAndExit(ctx, 1)
 		}
+	case "simulate":
+		// Always expect a target and a policy file
+		if argsLength != 3 {
+			showCommandHelpAndExit(ctx, 1)
+		}
 	default:
 		showCommandHelpAndExit(ctx, 1)
 	}
@@ -477,6 +496,8 @@ func mainAnonymous(ctx *cli.Context) error {
 
 	// Additional command speific theme customization.
 	console.SetColor("Anonymous", color.New(color.FgGreen, color.Bold))
+	console.SetColor("AnonymousAllowed", color.New(color.FgYellow, color.Bold))
+	console.SetColor("AnonymousDenied", color.New(color.FgRed, color.Bold))
 
 	switch ctx.Args().First() {
 	case "set", "set-json", "get", "get-json":
@@ -491,6 +512,9 @@ func mainAnonymous(ctx *cli.Context) error {
 	case "links":
 		// anonymous links alias/bucket/prefix
 		runAnonymousLinksCmd(ctx.Args().Tail(), ctx.Bool("recursive"))
+	case "simulate":
+		// anonymous simulate alias/bucket path-to-anonymous-json-file
+		runAnonymousSimulateCmd(ctx.Args().Tail(), ctx.String("log"))
 	default:
 		// Shows command example and exit
 		showCommandHelpAndExit(ctx, 1)
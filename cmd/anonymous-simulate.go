@@ -0,0 +1,258 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	gojson "encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+	"github.com/trinet2005/oss-pkg/policy"
+)
+
+// anonymousSimulateActions maps the S3 APIs of a trace to the policy
+// actions authorizing them.
+var anonymousSimulateActions = map[string]policy.Action{
+	"GetObject":               policy.GetObjectAction,
+	"HeadObject":              policy.GetObjectAction,
+	"SelectObjectContent":     policy.GetObjectAction,
+	"PutObject":               policy.PutObjectAction,
+	"CopyObject":              policy.PutObjectAction,
+	"PostPolicyBucket":        policy.PutObjectAction,
+	"NewMultipartUpload":      policy.PutObjectAction,
+	"PutObjectPart":           policy.PutObjectAction,
+	"CopyObjectPart":          policy.PutObjectAction,
+	"CompleteMultipartUpload": policy.PutObjectAction,
+	"AbortMultipartUpload":    policy.AbortMultipartUploadAction,
+	"ListObjectParts":         policy.ListMultipartUploadPartsAction,
+	"DeleteObject":            policy.DeleteObjectAction,
+	"GetObjectTagging":        policy.GetObjectTaggingAction,
+	"PutObjectTagging":        policy.PutObjectTaggingAction,
+	"DeleteObjectTagging":     policy.DeleteObjectTaggingAction,
+	"ListObjectsV1":           policy.ListBucketAction,
+	"ListObjectsV2":           policy.ListBucketAction,
+	"ListObjectVersions":      policy.ListBucketVersionsAction,
+	"HeadBucket":              policy.ListBucketAction,
+	"GetBucketLocation":       policy.GetBucketLocationAction,
+	"ListMultipartUploads":    policy.ListBucketMultipartUploadsAction,
+	"DeleteMultipleObjects":   policy.DeleteObjectAction,
+}
+
+// anonymousSimulateRequest is an S3 request of a trace, as authorized
+// by a bucket policy.
+type anonymousSimulateRequest struct {
+	Action     policy.Action
+	BucketName string
+	ObjectName string
+	Conditions map[string][]string
+}
+
+// newAnonymousSimulateRequest returns the request of a trace entry on
+// bucket, false for entries of other buckets or of unknown APIs.
+func newAnonymousSimulateRequest(t shortTraceMsg, bucket string) (anonymousSimulateRequest, bool) {
+	api := strings.TrimPrefix(t.FuncName, "s3.")
+	action, ok := anonymousSimulateActions[api]
+	if !ok || api == t.FuncName {
+		return anonymousSimulateRequest{}, false
+	}
+	entryBucket, object, _ := strings.Cut(strings.TrimPrefix(t.Path, "/"), "/")
+	if entryBucket != bucket {
+		return anonymousSimulateRequest{}, false
+	}
+	r := anonymousSimulateRequest{
+		Action:     action,
+		BucketName: bucket,
+		ObjectName: object,
+		Conditions: map[string][]string{},
+	}
+	if host, _, e := net.SplitHostPort(t.Client); e == nil {
+		r.Conditions["SourceIp"] = []string{host}
+	} else if t.Client != "" {
+		r.Conditions["SourceIp"] = []string{t.Client}
+	}
+	if query, e := url.ParseQuery(t.Query); e == nil {
+		for _, key := range []string{"prefix", "delimiter", "max-keys"} {
+			if v, ok := query[key]; ok {
+				r.Conditions[key] = v
+			}
+		}
+	}
+	return r, true
+}
+
+// isAllowed returns true if p allows the request anonymously.
+func (r anonymousSimulateRequest) isAllowed(p *policy.BucketPolicy) bool {
+	if p == nil {
+		return false
+	}
+	return p.IsAllowed(policy.BucketPolicyArgs{
+		Action:          r.Action,
+		BucketName:      r.BucketName,
+		ObjectName:      r.ObjectName,
+		ConditionValues: r.Conditions,
+	})
+}
+
+// anonymousSimulateMessage reports a request whose authorization
+// changes with the proposed policy.
+type anonymousSimulateMessage struct {
+	Status   string    `json:"status"`
+	Time     time.Time `json:"time"`
+	Client   string    `json:"client"`
+	API      string    `json:"api"`
+	Path     string    `json:"path"`
+	Action   string    `json:"action"`
+	Current  string    `json:"current"`
+	Proposed string    `json:"proposed"`
+}
+
+// String colorized anonymous simulate message.
+func (m anonymousSimulateMessage) String() string {
+	theme, change := "AnonymousAllowed", "newly allowed"
+	if m.Proposed == "deny" {
+		theme, change = "AnonymousDenied", "newly denied"
+	}
+	return fmt.Sprintf("%s %s %s %s %s", m.Time.Format(printDate), console.Colorize(theme, fmt.Sprintf("%-13s", change)), m.Client, m.API, m.Path)
+}
+
+// JSON jsonified anonymous simulate message.
+func (m anonymousSimulateMessage) JSON() string {
+	m.Status = "success"
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// anonymousSimulateSummaryMessage reports the number of replayed requests.
+type anonymousSimulateSummaryMessage struct {
+	Status       string `json:"status"`
+	Bucket       string `json:"bucket"`
+	Replayed     int64  `json:"replayed"`
+	Skipped      int64  `json:"skipped"`
+	NewlyDenied  int64  `json:"newlyDenied"`
+	NewlyAllowed int64  `json:"newlyAllowed"`
+}
+
+// String colorized anonymous simulate summary message.
+func (m anonymousSimulateSummaryMessage) String() string {
+	return console.Colorize("Anonymous", fmt.Sprintf("Replayed %d request(s) on `%s`, skipped %d: %d newly denied, %d newly allowed.",
+		m.Replayed, m.Bucket, m.Skipped, m.NewlyDenied, m.NewlyAllowed))
+}
+
+// JSON jsonified anonymous simulate summary message.
+func (m anonymousSimulateSummaryMessage) JSON() string {
+	m.Status = "success"
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// anonymousDecision returns the name of a policy decision.
+func anonymousDecision(allowed bool) string {
+	if allowed {
+		return "allow"
+	}
+	return "deny"
+}
+
+// runAnonymousSimulateCmd replays the S3 requests of a trace recorded with
+// 'mc admin trace --json' against the current and the proposed policies of
+// a bucket, and prints the requests whose anonymous access changes.
+func runAnonymousSimulateCmd(args cli.Args, logFile string) {
+	ctx, cancelAnonymous := context.WithCancel(globalContext)
+	defer cancelAnonymous()
+
+	targetURL, policyFile := args.Get(0), args.Get(1)
+	clnt, err := newClient(targetURL)
+	fatalIf(err.Trace(targetURL), "Unable to initialize `"+targetURL+"`.")
+	targetClientURL := clnt.GetURL()
+	bucket, _ := url2BucketAndObject(&targetClientURL)
+	if targetClientURL.Type != objectStorage || bucket == "" {
+		fatalIf(errInvalidArgument().Trace(targetURL), "Policy simulation works only with buckets.")
+	}
+
+	var current *policy.BucketPolicy
+	_, currentJSON, err := clnt.GetAccess(ctx)
+	fatalIf(err.Trace(targetURL), "Unable to get the policy of `"+targetURL+"`.")
+	if currentJSON != "" {
+		var e error
+		current, e = policy.ParseBucketPolicyConfig(strings.NewReader(currentJSON), bucket)
+		fatalIf(probe.NewError(e).Trace(targetURL), "Unable to parse the policy of `"+targetURL+"`.")
+	}
+
+	policyReader, e := os.Open(policyFile)
+	fatalIf(probe.NewError(e).Trace(policyFile), "Unable to open the proposed policy.")
+	defer policyReader.Close()
+	proposed, e := policy.ParseBucketPolicyConfig(policyReader, bucket)
+	fatalIf(probe.NewError(e).Trace(policyFile), "Unable to parse the proposed policy.")
+
+	var logReader io.Reader = os.Stdin
+	if logFile != "" {
+		f, e := os.Open(logFile)
+		fatalIf(probe.NewError(e).Trace(logFile), "Unable to open the trace.")
+		defer f.Close()
+		logReader = f
+	}
+
+	summary := anonymousSimulateSummaryMessage{Bucket: targetURL}
+	dec := gojson.NewDecoder(logReader)
+	for {
+		var t shortTraceMsg
+		if e := dec.Decode(&t); e != nil {
+			if e == io.EOF {
+				break
+			}
+			fatalIf(probe.NewError(e).Trace(logFile), "Unable to read the trace, expected the output of 'mc admin trace --json'.")
+		}
+		r, ok := newAnonymousSimulateRequest(t, bucket)
+		if !ok {
+			summary.Skipped++
+			continue
+		}
+		summary.Replayed++
+		before, after := r.isAllowed(current), r.isAllowed(proposed)
+		if before == after {
+			continue
+		}
+		if after {
+			summary.NewlyAllowed++
+		} else {
+			summary.NewlyDenied++
+		}
+		printMsg(anonymousSimulateMessage{
+			Time:     t.Time,
+			Client:   t.Client,
+			API:      t.FuncName,
+			Path:     t.Path,
+			Action:   string(r.Action),
+			Current:  anonymousDecision(before),
+			Proposed: anonymousDecision(after),
+		})
+	}
+	printMsg(summary)
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/trinet2005/oss-pkg/policy"
+)
+
+func TestNewAnonymousSimulateRequest(t *testing.T) {
+	testCases := []struct {
+		trace    shortTraceMsg
+		expected anonymousSimulateRequest
+		ok       bool
+	}{
+		{
+			trace: shortTraceMsg{FuncName: "s3.GetObject", Path: "/shared/docs/a.pdf", Client: "10.0.0.1:4321"},
+			expected: anonymousSimulateRequest{
+				Action:     policy.GetObjectAction,
+				BucketName: "shared",
+				ObjectName: "docs/a.pdf",
+				Conditions: map[string][]string{"SourceIp": {"10.0.0.1"}},
+			},
+			ok: true,
+		},
+		{
+			trace: shortTraceMsg{FuncName: "s3.ListObjectsV2", Path: "/shared/", Query: "list-type=2&prefix=docs%2F", Client: "10.0.0.2"},
+			expected: anonymousSimulateRequest{
+				Action:     policy.ListBucketAction,
+				BucketName: "shared",
+				Conditions: map[string][]string{"SourceIp": {"10.0.0.2"}, "prefix": {"docs/"}},
+			},
+			ok: true,
+		},
+		{trace: shortTraceMsg{FuncName: "s3.GetObject", Path: "/other/a.pdf"}, ok: false},
+		{trace: shortTraceMsg{FuncName: "s3.PutBucketPolicy", Path: "/shared"}, ok: false},
+		{trace: shortTraceMsg{FuncName: "GetObject", Path: "/shared/a.pdf"}, ok: false},
+	}
+	for i, testCase := range testCases {
+		r, ok := newAnonymousSimulateRequest(testCase.trace, "shared")
+		if ok != testCase.ok {
+			t.Fatalf("Test %d: expected ok %v, got %v", i+1, testCase.ok, ok)
+		}
+		if ok && !reflect.DeepEqual(r, testCase.expected) {
+			t.Fatalf("Test %d: expected %+v, got %+v", i+1, testCase.expected, r)
+		}
+	}
+}
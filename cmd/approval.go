@@ -0,0 +1,463 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	gojson "encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+const (
+	// approvalRequestExpiry is how long a request can be approved and used.
+	approvalRequestExpiry = 24 * time.Hour
+
+	approvalKeyFile   = "approval.key"
+	approvalTrustFile = "approvers"
+	approvalUsedFile  = "approvals-used"
+)
+
+var (
+	errNoTrustedApprovers = errors.New("no trusted approvers are configured in the '" + approvalTrustFile + "' file")
+	errApprovalStdin      = errors.New("approvals only cover the command line arguments, pass the URLs of protected aliases as arguments")
+)
+
+var approvalFlag = cli.StringFlag{
+	Name:  "approval",
+	Usage: "approved request file allowing this command on a protected alias",
+}
+
+// approvalSignature is the signature of one operator over a request.
+type approvalSignature struct {
+	Operator  string    `json:"operator"`
+	PublicKey string    `json:"publicKey"`
+	Time      time.Time `json:"time"`
+	Signature string    `json:"signature"`
+}
+
+// approvalRequest describes a destructive command waiting for, or
+// carrying, the approval of a second operator.
+type approvalRequest struct {
+	Version   int                `json:"version"`
+	ID        string             `json:"id"`
+	Aliases   []string           `json:"aliases"`
+	Command   string             `json:"command"`
+	Args      []string           `json:"args"`
+	Created   time.Time          `json:"created"`
+	Expires   time.Time          `json:"expires"`
+	Requester approvalSignature  `json:"requester"`
+	Approver  *approvalSignature `json:"approver,omitempty"`
+}
+
+// payload returns the bytes signed by the requester.
+func (r approvalRequest) payload() []byte {
+	data, _ := gojson.Marshal(struct {
+		Version   int       `json:"version"`
+		ID        string    `json:"id"`
+		Aliases   []string  `json:"aliases"`
+		Command   string    `json:"command"`
+		Args      []string  `json:"args"`
+		Created   time.Time `json:"created"`
+		Expires   time.Time `json:"expires"`
+		Requester string    `json:"requester"`
+		PublicKey string    `json:"publicKey"`
+	}{r.Version, r.ID, r.Aliases, r.Command, r.Args, r.Created, r.Expires, r.Requester.Operator, r.Requester.PublicKey})
+	return data
+}
+
+// approvalPayload returns the bytes signed by the approver, binding
+// the approval to the exact request and its requester signature.
+func (r approvalRequest) approvalPayload(operator string, at time.Time) []byte {
+	data := append(r.payload(), r.Requester.Signature...)
+	return append(data, fmt.Sprintf("|%s|%s", operator, at.Format(time.RFC3339Nano))...)
+}
+
+// newApprovalSignature signs payload with key.
+func newApprovalSignature(key ed25519.PrivateKey, operator string, at time.Time, payload []byte) approvalSignature {
+	return approvalSignature{
+		Operator:  operator,
+		PublicKey: base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)),
+		Time:      at,
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(key, payload)),
+	}
+}
+
+// verify checks the signature over payload.
+func (s approvalSignature) verify(payload []byte) error {
+	pub, e := base64.StdEncoding.DecodeString(s.PublicKey)
+	if e != nil || len(pub) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid public key of %s", s.Operator)
+	}
+	sig, e := base64.StdEncoding.DecodeString(s.Signature)
+	if e != nil || !ed25519.Verify(ed25519.PublicKey(pub), payload, sig) {
+		return fmt.Errorf("invalid signature of %s", s.Operator)
+	}
+	return nil
+}
+
+// newApprovalRequest returns a request for command, signed by key.
+func newApprovalRequest(id string, aliases []string, command string, args []string, key ed25519.PrivateKey, operator string, now time.Time) approvalRequest {
+	now = now.UTC().Truncate(time.Second)
+	r := approvalRequest{
+		Version: 1,
+		ID:      id,
+		Aliases: aliases,
+		Command: command,
+		Args:    args,
+		Created: now,
+		Expires: now.Add(approvalRequestExpiry),
+		Requester: approvalSignature{
+			Operator:  operator,
+			PublicKey: base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)),
+			Time:      now,
+		},
+	}
+	r.Requester = newApprovalSignature(key, operator, now, r.payload())
+	return r
+}
+
+// approve adds the approval of the operator owning key to the request.
+func (r *approvalRequest) approve(key ed25519.PrivateKey, operator string, now time.Time) error {
+	if e := r.Requester.verify(r.payload()); e != nil {
+		return e
+	}
+	if r.Approver != nil {
+		return fmt.Errorf("request is already approved by %s", r.Approver.Operator)
+	}
+	if now.After(r.Expires) {
+		return errors.New("request has expired")
+	}
+	publicKey := base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))
+	if publicKey == r.Requester.PublicKey {
+		return errors.New("request must be approved by an operator other than the requester")
+	}
+	now = now.UTC().Truncate(time.Second)
+	approval := newApprovalSignature(key, operator, now, r.approvalPayload(operator, now))
+	r.Approver = &approval
+	return nil
+}
+
+// verify checks that the request is approved by one of the trusted
+// approvers, unexpired, issued by the operator owning publicKey and
+// allows running command with args. Without trusted approvers no
+// request is accepted, as any second key could approve it.
+func (r approvalRequest) verify(command string, args []string, publicKey string, trusted map[string]string, now time.Time) error {
+	if e := r.Requester.verify(r.payload()); e != nil {
+		return e
+	}
+	if r.Approver == nil {
+		return errors.New("request is not approved yet")
+	}
+	if e := r.Approver.verify(r.approvalPayload(r.Approver.Operator, r.Approver.Time)); e != nil {
+		return e
+	}
+	if r.Approver.PublicKey == r.Requester.PublicKey {
+		return errors.New("request is approved by its requester")
+	}
+	if len(trusted) == 0 {
+		return errNoTrustedApprovers
+	}
+	if _, ok := trusted[r.Approver.PublicKey]; !ok {
+		return fmt.Errorf("%s is not a trusted approver", r.Approver.Operator)
+	}
+	if now.After(r.Expires) {
+		return errors.New("request has expired")
+	}
+	if publicKey != r.Requester.PublicKey {
+		return fmt.Errorf("request was issued by %s and can only be used by them", r.Requester.Operator)
+	}
+	if r.Command != command || !reflect.DeepEqual(r.Args, args) {
+		return fmt.Errorf("request allows `%s` only", strings.TrimSpace(r.Command+" "+strings.Join(r.Args, " ")))
+	}
+	return nil
+}
+
+// approvalCommand returns name followed by the flags among names set
+// in ctx, so that a request cannot be used with a broader scope.
+func approvalCommand(ctx *cli.Context, name string, flags ...string) string {
+	command := []string{name}
+	for _, flag := range flags {
+		if !ctx.IsSet(flag) {
+			continue
+		}
+		if values := ctx.StringSlice(flag); len(values) > 0 {
+			for _, value := range values {
+				command = append(command, "--"+flag+"="+value)
+			}
+		} else if value := ctx.String(flag); value != "" && value != "true" {
+			command = append(command, "--"+flag+"="+value)
+		} else {
+			command = append(command, "--"+flag)
+		}
+	}
+	return strings.Join(command, " ")
+}
+
+// protectedAliases returns the protected aliases among urls.
+func protectedAliases(urls []string) (aliases []string) {
+	seen := make(map[string]bool)
+	for _, u := range urls {
+		alias, _ := url2Alias(u)
+		if seen[alias] {
+			continue
+		}
+		seen[alias] = true
+		if hostCfg := mustGetHostConfig(alias); hostCfg != nil && hostCfg.Protected {
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases
+}
+
+// checkStdinApproval refuses url, read from the standard input, if it is
+// on a protected alias as the approval of a command does not cover it.
+func checkStdinApproval(url string) *probe.Error {
+	if aliases := protectedAliases([]string{url}); len(aliases) > 0 {
+		return probe.NewError(errApprovalStdin).Trace(aliases...)
+	}
+	return nil
+}
+
+// getApprovalOperator returns the name identifying the local operator.
+func getApprovalOperator() string {
+	name := "unknown"
+	if u, e := user.Current(); e == nil {
+		name = u.Username
+	}
+	if host, e := os.Hostname(); e == nil {
+		name += "@" + host
+	}
+	return name
+}
+
+// loadApprovalKey returns the signing key of the local operator,
+// generating it on first use.
+func loadApprovalKey() (ed25519.PrivateKey, *probe.Error) {
+	path := filepath.Join(mustGetMcConfigDir(), approvalKeyFile)
+	data, e := os.ReadFile(path)
+	if e == nil {
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, probe.NewError(errors.New("no PEM data found")).Trace(path)
+		}
+		key, e := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if e != nil {
+			return nil, probe.NewError(e).Trace(path)
+		}
+		privateKey, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, probe.NewError(errors.New("not an ed25519 key")).Trace(path)
+		}
+		return privateKey, nil
+	}
+	if !os.IsNotExist(e) {
+		return nil, probe.NewError(e).Trace(path)
+	}
+
+	_, privateKey, e := ed25519.GenerateKey(rand.Reader)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	der, e := x509.MarshalPKCS8PrivateKey(privateKey)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	if e = os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); e != nil {
+		return nil, probe.NewError(e).Trace(path)
+	}
+	return privateKey, nil
+}
+
+// loadTrustedApprovers returns the public keys and names listed in the
+// approvers file, or nil when there is no such file.
+func loadTrustedApprovers() (map[string]string, *probe.Error) {
+	path := filepath.Join(mustGetMcConfigDir(), approvalTrustFile)
+	f, e := os.Open(path)
+	if e != nil {
+		if os.IsNotExist(e) {
+			return nil, nil
+		}
+		return nil, probe.NewError(e).Trace(path)
+	}
+	defer f.Close()
+
+	trusted := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, name, _ := strings.Cut(line, " ")
+		trusted[key] = strings.TrimSpace(name)
+	}
+	if e = scanner.Err(); e != nil {
+		return nil, probe.NewError(e).Trace(path)
+	}
+	return trusted, nil
+}
+
+// isApprovalUsed reports whether the request id was already used.
+func isApprovalUsed(id string) bool {
+	data, e := os.ReadFile(filepath.Join(mustGetMcConfigDir(), approvalUsedFile))
+	if e != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == id {
+			return true
+		}
+	}
+	return false
+}
+
+// markApprovalUsed records the request id so it cannot be replayed.
+func markApprovalUsed(id string) *probe.Error {
+	path := filepath.Join(mustGetMcConfigDir(), approvalUsedFile)
+	f, e := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if e != nil {
+		return probe.NewError(e).Trace(path)
+	}
+	defer f.Close()
+	if _, e = f.WriteString(id + "\n"); e != nil {
+		return probe.NewError(e).Trace(path)
+	}
+	return nil
+}
+
+// readApprovalRequest reads a request file.
+func readApprovalRequest(path string) (approvalRequest, *probe.Error) {
+	var r approvalRequest
+	data, e := os.ReadFile(path)
+	if e != nil {
+		return r, probe.NewError(e).Trace(path)
+	}
+	if e = gojson.Unmarshal(data, &r); e != nil {
+		return r, probe.NewError(e).Trace(path)
+	}
+	return r, nil
+}
+
+// writeApprovalRequest writes a request file.
+func writeApprovalRequest(path string, r approvalRequest) *probe.Error {
+	data, e := gojson.MarshalIndent(r, "", " ")
+	if e != nil {
+		return probe.NewError(e)
+	}
+	if e = os.WriteFile(path, data, 0o644); e != nil {
+		return probe.NewError(e).Trace(path)
+	}
+	return nil
+}
+
+// approvalRequiredMessage container for a newly written request.
+type approvalRequiredMessage struct {
+	Status  string   `json:"status"`
+	ID      string   `json:"id"`
+	File    string   `json:"file"`
+	Aliases []string `json:"aliases"`
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+}
+
+// String colorized approval required message.
+func (m approvalRequiredMessage) String() string {
+	return console.Colorize("ApprovalRequired", fmt.Sprintf("Approval required to run `%s` on protected alias `%s`.",
+		strings.Join(append([]string{m.Command}, m.Args...), " "), strings.Join(m.Aliases, "`, `"))) + "\n" +
+		fmt.Sprintf("Request written to `%s`, have another operator run `mc approve %s` and rerun this command with `--approval %s`.",
+			m.File, m.File, m.File)
+}
+
+// JSON jsonified approval required message.
+func (m approvalRequiredMessage) JSON() string {
+	m.Status = "pending"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// checkApproval enforces the four-eyes policy of protected aliases on
+// a destructive command. Without an approval file it writes a signed
+// request and returns false, with one it verifies the approval and
+// records it as used.
+func checkApproval(ctx *cli.Context, command string, args []string) bool {
+	aliases := protectedAliases(args)
+	if len(aliases) == 0 {
+		return true
+	}
+	console.SetColor("ApprovalRequired", color.New(color.FgYellow, color.Bold))
+
+	key, err := loadApprovalKey()
+	fatalIf(err, "Unable to load the approval key.")
+	trusted, err := loadTrustedApprovers()
+	fatalIf(err, "Unable to read the trusted approvers.")
+	if len(trusted) == 0 {
+		fatalIf(probe.NewError(errNoTrustedApprovers).Trace(aliases...),
+			"Unable to run `%s` on a protected alias.", command)
+	}
+
+	path := ctx.String("approval")
+	if path == "" {
+		buf := make([]byte, 8)
+		_, e := rand.Read(buf)
+		fatalIf(probe.NewError(e), "Unable to generate the approval request id.")
+		id := hex.EncodeToString(buf)
+		r := newApprovalRequest(id, aliases, command, args, key, getApprovalOperator(), time.Now())
+		file := "mc-approval-" + id + ".json"
+		fatalIf(writeApprovalRequest(file, r), "Unable to write the approval request.")
+		printMsg(approvalRequiredMessage{
+			ID:      id,
+			File:    file,
+			Aliases: aliases,
+			Command: command,
+			Args:    args,
+		})
+		return false
+	}
+
+	r, err := readApprovalRequest(path)
+	fatalIf(err, "Unable to read the approval request.")
+	publicKey := base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))
+	if e := r.verify(command, args, publicKey, trusted, time.Now()); e != nil {
+		fatalIf(probe.NewError(e).Trace(path), "Invalid approval.")
+	}
+	if isApprovalUsed(r.ID) {
+		fatalIf(errDummy().Trace(path), "Approval `"+r.ID+"` was already used.")
+	}
+	fatalIf(markApprovalUsed(r.ID), "Unable to record the approval as used.")
+	return true
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestApprovalRequest(t *testing.T) {
+	newKey := func() (ed25519.PrivateKey, string) {
+		_, key, e := ed25519.GenerateKey(rand.Reader)
+		if e != nil {
+			t.Fatal(e)
+		}
+		return key, base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))
+	}
+	requesterKey, requesterPub := newKey()
+	approverKey, approverPub := newKey()
+	otherKey, otherPub := newKey()
+
+	now := time.Now()
+	args := []string{"prod/bucket"}
+	newRequest := func() approvalRequest {
+		return newApprovalRequest("id", []string{"prod"}, "rb --force", args, requesterKey, "alice@host", now)
+	}
+
+	// A requester cannot approve its own request.
+	r := newRequest()
+	if e := r.approve(requesterKey, "alice@host", now); e == nil {
+		t.Fatalf("expected self approval to fail")
+	}
+	trusted := map[string]string{approverPub: "bob"}
+	if e := r.verify("rb --force", args, requesterPub, trusted, now); e == nil {
+		t.Fatalf("expected an unapproved request to fail verification")
+	}
+	if e := r.approve(approverKey, "bob@host", now); e != nil {
+		t.Fatalf("unexpected approval error: %v", e)
+	}
+	if e := r.approve(otherKey, "carol@host", now); e == nil {
+		t.Fatalf("expected a second approval to fail")
+	}
+
+	testCases := []struct {
+		modify    func(r *approvalRequest)
+		command   string
+		args      []string
+		publicKey string
+		trusted   map[string]string
+		at        time.Time
+		success   bool
+	}{
+		// Approved by a trusted approver, used by its requester.
+		{nil, "rb --force", args, requesterPub, trusted, now, true},
+		// No trusted approvers configured.
+		{nil, "rb --force", args, requesterPub, nil, now, false},
+		{nil, "rb --force", args, requesterPub, map[string]string{}, now, false},
+		// Approver not listed as trusted.
+		{nil, "rb --force", args, requesterPub, map[string]string{otherPub: "carol"}, now, false},
+		// Different command.
+		{nil, "rb --force --dangerous", args, requesterPub, trusted, now, false},
+		// Different targets.
+		{nil, "rb --force", []string{"prod/other"}, requesterPub, trusted, now, false},
+		// Used by another operator.
+		{nil, "rb --force", args, otherPub, trusted, now, false},
+		// Expired.
+		{nil, "rb --force", args, requesterPub, trusted, now.Add(approvalRequestExpiry + time.Minute), false},
+		// Tampered arguments.
+		{func(r *approvalRequest) { r.Args = []string{"prod"} }, "rb --force", []string{"prod"}, requesterPub, trusted, now, false},
+		// Tampered approver.
+		{func(r *approvalRequest) { r.Approver.Operator = "carol@host" }, "rb --force", args, requesterPub, trusted, now, false},
+	}
+
+	for i, testCase := range testCases {
+		request := r
+		approver := *r.Approver
+		request.Approver = &approver
+		request.Args = append([]string{}, r.Args...)
+		if testCase.modify != nil {
+			testCase.modify(&request)
+		}
+		e := request.verify(testCase.command, testCase.args, testCase.publicKey, testCase.trusted, testCase.at)
+		if testCase.success && e != nil {
+			t.Errorf("Test %d: expected success, got %v", i+1, e)
+		}
+		if !testCase.success && e == nil {
+			t.Errorf("Test %d: expected failure", i+1)
+		}
+	}
+}
+
+func TestCheckStdinApproval(t *testing.T) {
+	aliasToConfigMap["mc-test-protected"] = &aliasConfigV10{URL: "https://localhost:9000", Protected: true}
+	aliasToConfigMap["mc-test-unprotected"] = &aliasConfigV10{URL: "https://localhost:9000"}
+	defer delete(aliasToConfigMap, "mc-test-protected")
+	defer delete(aliasToConfigMap, "mc-test-unprotected")
+
+	testCases := []struct {
+		url     string
+		success bool
+	}{
+		{"mc-test-protected/bucket", false},
+		{"mc-test-protected/bucket/prefix/", false},
+		{"mc-test-unprotected/bucket", true},
+	}
+	for i, testCase := range testCases {
+		err := checkStdinApproval(testCase.url)
+		if (err == nil) != testCase.success {
+			t.Fatalf("Test %d: expected success %v, got %v", i+1, testCase.success, err)
+		}
+		if err != nil && !errors.Is(err.ToGoError(), errApprovalStdin) {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, errApprovalStdin, err)
+		}
+	}
+}
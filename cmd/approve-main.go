@@ -0,0 +1,173 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/i18n"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+var approveFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "yes, y",
+		Usage: "approve without prompting for confirmation",
+	},
+	cli.BoolFlag{
+		Name:  "show-key",
+		Usage: "print the public key identifying you as an approver",
+	},
+}
+
+var approveCmd = cli.Command{
+	Name:         "approve",
+	Usage:        "approve a destructive command requested on a protected alias",
+	Action:       mainApprove,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(approveFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] FILE
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Destructive commands on aliases set with 'mc alias set --protected', such as 'rb --force' and
+  'rm --recursive --versions', do not run right away. They write a request file signed by the
+  requesting operator instead, which another operator approves with this command. The requester
+  then reruns the command with '--approval FILE'.
+
+  Requests and approvals are signed with a key kept in the mc configuration directory of each
+  operator. The configuration directory of the requester must contain an 'approvers' file,
+  listing one public key (see --show-key) and an optional name per line: only those operators
+  are accepted as approvers, and protected aliases refuse destructive commands without it.
+  Requests expire after 24 hours and can only be used once.
+
+EXAMPLES:
+  1. Approve the removal requested in 'mc-approval-6f1c2e9a4b7d3c05.json'.
+     {{.Prompt}} {{.HelpName}} mc-approval-6f1c2e9a4b7d3c05.json
+
+  2. Approve a request without prompting for confirmation.
+     {{.Prompt}} {{.HelpName}} --yes mc-approval-6f1c2e9a4b7d3c05.json
+
+  3. Print your public key to add it to the 'approvers' file of the requesting operators.
+     {{.Prompt}} {{.HelpName}} --show-key
+`,
+}
+
+// approveMessage container for an approved request.
+type approveMessage struct {
+	Status    string    `json:"status"`
+	ID        string    `json:"id,omitempty"`
+	File      string    `json:"file,omitempty"`
+	Aliases   []string  `json:"aliases,omitempty"`
+	Command   string    `json:"command,omitempty"`
+	Args      []string  `json:"args,omitempty"`
+	Requester string    `json:"requester,omitempty"`
+	Approver  string    `json:"approver,omitempty"`
+	Expires   time.Time `json:"expires,omitempty"`
+	PublicKey string    `json:"publicKey,omitempty"`
+}
+
+// String colorized approve message.
+func (m approveMessage) String() string {
+	if m.ID == "" {
+		return m.PublicKey
+	}
+	return console.Colorize("Approved", fmt.Sprintf("Approved `%s` requested by %s, valid until %s.",
+		strings.Join(append([]string{m.Command}, m.Args...), " "), m.Requester, m.Expires.Local().Format(printDate)))
+}
+
+// JSON jsonified approve message.
+func (m approveMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// mainApprove is the handle for "mc approve" command.
+func mainApprove(ctx *cli.Context) error {
+	console.SetColor("Approved", color.New(color.FgGreen, color.Bold))
+	console.SetColor("ApprovalRequest", color.New(color.FgYellow))
+
+	key, err := loadApprovalKey()
+	fatalIf(err, "Unable to load the approval key.")
+
+	if ctx.Bool("show-key") {
+		if len(ctx.Args()) != 0 {
+			showCommandHelpAndExit(ctx, 1) // last argument is exit code
+		}
+		printMsg(approveMessage{
+			PublicKey: base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)),
+		})
+		return nil
+	}
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+
+	path := ctx.Args().Get(0)
+	r, err := readApprovalRequest(path)
+	fatalIf(err, "Unable to read the approval request.")
+
+	if isTerminal() && !globalJSON && !ctx.Bool("yes") {
+		console.Println(console.Colorize("ApprovalRequest", fmt.Sprintf("%s requests to run `%s` on protected alias `%s`.",
+			r.Requester.Operator, strings.Join(append([]string{r.Command}, r.Args...), " "), strings.Join(r.Aliases, "`, `"))))
+		fmt.Print(i18n.T("Approve this request? [y/N]: "))
+		answer, e := bufio.NewReader(os.Stdin).ReadString('\n')
+		fatalIf(probe.NewError(e), "Unable to parse user input.")
+		if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+			fmt.Println(i18n.T("Approval aborted!"))
+			return nil
+		}
+	}
+
+	operator := getApprovalOperator()
+	if e := r.approve(key, operator, time.Now()); e != nil {
+		fatalIf(probe.NewError(e).Trace(path), "Unable to approve the request.")
+	}
+	fatalIf(writeApprovalRequest(path, r), "Unable to write the approved request.")
+
+	printMsg(approveMessage{
+		ID:        r.ID,
+		File:      path,
+		Aliases:   r.Aliases,
+		Command:   r.Command,
+		Args:      r.Args,
+		Requester: r.Requester.Operator,
+		Approver:  operator,
+		Expires:   r.Expires,
+	})
+	return nil
+}
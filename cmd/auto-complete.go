@@ -235,9 +235,13 @@ var completeCmds = map[string]complete.Predictor{
 	"/mv":        complete.PredictOr(s3Completer, fsCompleter),
 	"/rm":        complete.PredictOr(s3Completer, fsCompleter),
 	"/rb":        complete.PredictOr(s3Complete{deepLevel: 2}, fsCompleter),
+	"/approve":   fsCompleter,
 	"/cat":       complete.PredictOr(s3Completer, fsCompleter),
 	"/head":      complete.PredictOr(s3Completer, fsCompleter),
+	"/get":       complete.PredictOr(s3Completer, fsCompleter),
+	"/put":       complete.PredictOr(s3Completer, fsCompleter),
 	"/diff":      complete.PredictOr(s3Completer, fsCompleter),
+	"/cmp":       complete.PredictOr(s3Completer, fsCompleter),
 	"/find":      complete.PredictOr(s3Completer, fsCompleter),
 	"/mirror":    complete.PredictOr(s3Completer, fsCompleter),
 	"/pipe":      complete.PredictOr(s3Completer, fsCompleter),
@@ -279,6 +283,7 @@ var completeCmds = map[string]complete.Predictor{
 	"/replicate/resync/start":  s3Complete{deepLevel: 3},
 	"/replicate/resync/status": s3Complete{deepLevel: 3},
 
+	"/tag/copy":   s3Completer,
 	"/tag/list":   s3Completer,
 	"/tag/remove": s3Completer,
 	"/tag/set":    s3Completer,
@@ -347,8 +352,11 @@ var completeCmds = map[string]complete.Predictor{
 	"/admin/top/locks": aliasCompleter,
 	"/admin/top/api":   aliasCompleter,
 
-	"/admin/scanner/status": aliasCompleter,
-	"/admin/scanner/trace":  aliasCompleter,
+	"/admin/scanner/status":         aliasCompleter,
+	"/admin/scanner/trace":          aliasCompleter,
+	"/admin/scanner/exclude/list":   aliasCompleter,
+	"/admin/scanner/exclude/add":    aliasCompleter,
+	"/admin/scanner/exclude/remove": aliasCompleter,
 
 	"/admin/service/stop":     aliasCompleter,
 	"/admin/service/restart":  aliasCompleter,
@@ -358,6 +366,8 @@ var completeCmds = map[string]complete.Predictor{
 	"/admin/prometheus/generate": aliasCompleter,
 	"/admin/prometheus/metrics":  aliasCompleter,
 
+	"/admin/metrics/get": aliasCompleter,
+
 	"/admin/profile/start": aliasCompleter,
 	"/admin/profile/stop":  aliasCompleter,
 
@@ -463,6 +473,10 @@ var completeCmds = map[string]complete.Predictor{
 	"/alias/list":   aliasCompleter,
 	"/alias/remove": aliasCompleter,
 	"/alias/import": nil,
+	"/alias/env":    aliasCompleter,
+	"/alias/doctor": aliasCompleter,
+
+	"/config/validate": fsCompleter,
 
 	"/support/callhome":     aliasCompleter,
 	"/support/register":     aliasCompleter,
@@ -488,6 +502,8 @@ var completeCmds = map[string]complete.Predictor{
 	"/update":         nil,
 	"/ready":          aliasCompleter,
 	"/ping":           aliasCompleter,
+	"/capabilities":   s3Completer,
+	"/run-as":         aliasCompleter,
 	"/od":             nil,
 	"/batch/generate": aliasCompleter,
 	"/batch/start":    aliasCompleter,
@@ -38,6 +38,9 @@ FLAGS:
 EXAMPLES:
    1. Display current in-progress JOB events.
       {{.Prompt}} {{.HelpName}} myminio/ KwSysDpxcBU9FNhGkn2dCf
+
+   2. Print the JOB events as plain text status updates, for a screen reader or a CI log.
+      {{.Prompt}} {{.HelpName}} --no-tui myminio/ KwSysDpxcBU9FNhGkn2dCf
 `,
 }
 
@@ -71,7 +74,7 @@ func mainBatchStatus(ctx *cli.Context) error {
 	}
 	fatalIf(probe.NewError(e), "Unable to lookup job status")
 
-	ui := tea.NewProgram(initBatchJobMetricsUI(jobID))
+	ui := newUIProgram(initBatchJobMetricsUI(jobID))
 	go func() {
 		opts := madmin.MetricsOptions{
 			Type:     madmin.MetricsBatchJobs,
@@ -0,0 +1,289 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	minio "github.com/trinet2005/oss-go-sdk"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+// Discover the APIs supported by a target.
+var capabilitiesCmd = cli.Command{
+	Name:         "capabilities",
+	Usage:        "probe the APIs supported by a target",
+	Action:       mainCapabilities,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] ALIAS[/BUCKET]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+NOTE:
+  Capabilities are probed with read-only requests. Bucket level capabilities are probed on
+  BUCKET, or on the first bucket of the alias if none is given. Checksums are probed on the
+  first object of the bucket, and only reported as supported if the server returns its
+  checksum. Each capability is reported as 'supported', 'unsupported', 'denied' when the
+  credentials are not allowed to probe it, or 'unknown'.
+
+EXAMPLES:
+  1. Show the APIs supported by the 'play' alias.
+     {{.Prompt}} {{.HelpName}} play
+
+  2. Probe bucket level APIs on 'mybucket' and print the result as JSON for scripting.
+     {{.Prompt}} {{.HelpName}} --json play/mybucket
+`,
+}
+
+// Outcome of a capability probe.
+const (
+	capabilitySupported   = "supported"
+	capabilityUnsupported = "unsupported"
+	capabilityDenied      = "denied"
+	capabilityUnknown     = "unknown"
+)
+
+// capabilitiesMessage container for capabilities messages
+type capabilitiesMessage struct {
+	Status       string            `json:"status"`
+	Alias        string            `json:"alias"`
+	Bucket       string            `json:"bucket,omitempty"`
+	Capabilities map[string]string `json:"capabilities"`
+}
+
+// String colorized capabilities message
+func (c capabilitiesMessage) String() string {
+	names := make([]string, 0, len(c.Capabilities))
+	for name := range c.Capabilities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	title := c.Alias
+	if c.Bucket != "" {
+		title += " (probed on bucket `" + c.Bucket + "`)"
+	}
+	b.WriteString(console.Colorize("Title", title) + "\n")
+	for _, name := range names {
+		status := c.Capabilities[name]
+		tag := "Unknown"
+		switch status {
+		case capabilitySupported:
+			tag = "Supported"
+		case capabilityUnsupported, capabilityDenied:
+			tag = "Unsupported"
+		}
+		fmt.Fprintf(&b, "  %-12s: %s\n", name, console.Colorize(tag, status))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// JSON jsonified capabilities message
+func (c capabilitiesMessage) JSON() string {
+	c.Status = "success"
+	capabilitiesMessageBytes, e := json.MarshalIndent(c, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(capabilitiesMessageBytes)
+}
+
+// capabilityFromError classifies the outcome of a probe request. An API
+// answering that the probed configuration does not exist is supported.
+func capabilityFromError(err *probe.Error) string {
+	if err == nil {
+		return capabilitySupported
+	}
+	if _, ok := err.ToGoError().(APINotImplemented); ok {
+		return capabilityUnsupported
+	}
+	errResp := minio.ToErrorResponse(err.ToGoError())
+	switch errResp.Code {
+	case "NotImplemented", "XNotImplemented", "MethodNotAllowed":
+		return capabilityUnsupported
+	case "AccessDenied":
+		return capabilityDenied
+	case "NoSuchKey", "NoSuchTagSet", "NoSuchLifecycleConfiguration",
+		"ReplicationConfigurationNotFoundError", "ObjectLockConfigurationNotFoundError",
+		"ServerSideEncryptionConfigurationNotFoundError", "NoSuchBucketPolicy":
+		return capabilitySupported
+	}
+	switch errResp.StatusCode {
+	case http.StatusNotImplemented:
+		return capabilityUnsupported
+	case http.StatusForbidden:
+		return capabilityDenied
+	}
+	return capabilityUnknown
+}
+
+// probeCapabilities probes the APIs supported by aliasedURL using
+// read-only requests on the given bucket, if any.
+func probeCapabilities(ctx context.Context, aliasedURL, bucket string) map[string]string {
+	capabilities := map[string]string{
+		"admin":       capabilityUnknown,
+		"tiering":     capabilityUnknown,
+		"versioning":  capabilityUnknown,
+		"objectlock":  capabilityUnknown,
+		"replication": capabilityUnknown,
+		"select":      capabilityUnknown,
+		"checksums":   capabilityUnknown,
+	}
+
+	alias, _ := url2Alias(aliasedURL)
+	if adminClient, err := newAdminClient(alias); err == nil {
+		_, e := adminClient.ServerInfo(ctx)
+		capabilities["admin"] = capabilityFromError(probe.NewError(e))
+		if e == nil {
+			_, e = adminClient.ListTiers(ctx)
+			capabilities["tiering"] = capabilityFromError(probe.NewError(e))
+		}
+	}
+
+	if bucket == "" {
+		return capabilities
+	}
+
+	bucketURL := urlJoinPath(alias, bucket)
+	clnt, err := newClient(bucketURL)
+	if err != nil {
+		return capabilities
+	}
+	_, err = clnt.GetVersion(ctx)
+	capabilities["versioning"] = capabilityFromError(err)
+	_, _, _, _, err = clnt.GetObjectLockConfig(ctx)
+	capabilities["objectlock"] = capabilityFromError(err)
+	_, err = clnt.GetReplication(ctx)
+	capabilities["replication"] = capabilityFromError(err)
+
+	// Select on a missing object fails with NoSuchKey when supported.
+	objClnt, err := newClient(urlJoinPath(bucketURL, fmt.Sprintf("mc-capabilities-probe-%d", UTCNow().UnixNano())))
+	if err == nil {
+		var reader io.ReadCloser
+		reader, err = objClnt.Select(ctx, "SELECT * FROM S3Object", nil, SelectObjectOpts{
+			InputSerOpts:  map[string]map[string]string{"csv": {}},
+			OutputSerOpts: map[string]map[string]string{"csv": {}},
+		})
+		if reader != nil {
+			reader.Close()
+		}
+		capabilities["select"] = capabilityFromError(err)
+	}
+	capabilities["checksums"] = probeChecksums(ctx, clnt, bucketURL)
+	return capabilities
+}
+
+// probeChecksums probes whether the additional checksums of objects are
+// returned, reading the attributes of the first object of the bucket.
+// Support is only confirmed by an object uploaded with a checksum, the
+// capability is unknown otherwise.
+func probeChecksums(ctx context.Context, clnt Client, bucketURL string) string {
+	listCtx, cancelList := context.WithCancel(ctx)
+	defer cancelList()
+
+	var object *ClientContent
+	for content := range clnt.List(listCtx, ListOptions{Recursive: true, ShowDir: DirNone}) {
+		if content.Err != nil {
+			return capabilityUnknown
+		}
+		object = content
+		break
+	}
+	if object == nil {
+		return capabilityUnknown
+	}
+
+	alias, _ := url2Alias(bucketURL)
+	objClnt, err := newClientFromAlias(alias, object.URL.String())
+	if err != nil {
+		return capabilityUnknown
+	}
+	s3Clnt, ok := objClnt.(*S3Client)
+	if !ok {
+		return capabilityUnknown
+	}
+	checksums, err := s3Clnt.GetObjectChecksums(ctx, "", nil)
+	switch {
+	case err != nil:
+		if status := capabilityFromError(err); status != capabilitySupported {
+			return status
+		}
+		return capabilityUnknown
+	case len(checksums) > 0:
+		return capabilitySupported
+	}
+	return capabilityUnknown
+}
+
+// mainCapabilities is the entry point for capabilities command.
+func mainCapabilities(cliCtx *cli.Context) error {
+	ctx, cancelCapabilities := context.WithCancel(globalContext)
+	defer cancelCapabilities()
+
+	if len(cliCtx.Args()) != 1 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+
+	console.SetColor("Title", color.New(color.Bold))
+	console.SetColor("Supported", color.New(color.FgGreen))
+	console.SetColor("Unsupported", color.New(color.FgRed))
+	console.SetColor("Unknown", color.New(color.FgYellow))
+
+	aliasedURL := cliCtx.Args().Get(0)
+	_, _, hostCfg, err := expandAlias(aliasedURL)
+	fatalIf(err.Trace(aliasedURL), "Unable to parse target `"+aliasedURL+"`.")
+	if hostCfg == nil {
+		fatalIf(errInvalidArgument().Trace(aliasedURL), "Target `"+aliasedURL+"` should be an alias.")
+	}
+	alias, path := url2Alias(aliasedURL)
+
+	bucket := strings.Split(strings.TrimPrefix(filepath.ToSlash(path), "/"), "/")[0]
+	if bucket == "" {
+		clnt, err := newClient(alias)
+		fatalIf(err.Trace(aliasedURL), "Unable to initialize target `"+aliasedURL+"`.")
+		buckets, err := clnt.ListBuckets(ctx)
+		fatalIf(err.Trace(aliasedURL), "Unable to list buckets on `"+aliasedURL+"`.")
+		if len(buckets) > 0 {
+			bucket = strings.Trim(buckets[0].URL.Path, "/")
+		}
+	}
+
+	printMsg(capabilitiesMessage{
+		Alias:        alias,
+		Bucket:       bucket,
+		Capabilities: probeCapabilities(ctx, aliasedURL, bucket),
+	})
+	return nil
+}
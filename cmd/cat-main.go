@@ -51,10 +51,19 @@ var catFlags = []cli.Flag{
 		Name:  "offset",
 		Usage: "start offset",
 	},
+	cli.Int64Flag{
+		Name:  "length",
+		Usage: "number of bytes to display, starting at --offset",
+	},
+	cli.BoolFlag{
+		Name:  "decompress",
+		Usage: "decompress gzip, zstd or bzip2 compressed content",
+	},
 	cli.Int64Flag{
 		Name:  "tail",
 		Usage: "tail number of bytes at ending of file",
 	},
+	cseDecryptFlag,
 }
 
 // Display contents of a file.
@@ -75,7 +84,9 @@ FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}
 ENVIRONMENT VARIABLES:
-  MC_ENCRYPT_KEY:  list of comma delimited prefix=secret values
+  MC_ENCRYPT_KEY:       list of comma delimited prefix=secret values
+  MC_ENCRYPT_KEY_FILE:  file of prefix=secret lines, same as --encrypt-key-file
+  MC_DECRYPT_WITH:      key to decrypt client-side encrypted objects, same as --decrypt-with
 
 EXAMPLES:
   1. Stream an object from Amazon S3 cloud storage to mplayer standard input.
@@ -99,6 +110,18 @@ EXAMPLES:
 
   7. Display the content of a particular object version
      {{.Prompt}} {{.HelpName}} --vid "3ddac055-89a7-40fa-8cd3-530a5581b6b8" play/my-bucket/my-object
+
+  8. Display the content of an object encrypted on the client by 'mc cp --encrypt-with', using an age identity file.
+     {{.Prompt}} {{.HelpName}} --decrypt-with age:$HOME/.config/age/key.txt play/my-bucket/my-object
+
+  9. Display the 4 byte magic number at the beginning of a parquet file.
+     {{.Prompt}} {{.HelpName}} --length 4 play/my-bucket/data.parquet
+
+  10. Display 1KiB of a large object, starting 1MiB into it.
+      {{.Prompt}} {{.HelpName}} --offset 1048576 --length 1024 play/my-bucket/my-object | xxd
+
+  11. Search compressed application logs without downloading them first.
+      {{.Prompt}} {{.HelpName}} --decompress play/my-bucket/logs/app-2023-10-01.log.gz play/my-bucket/logs/app-2023-10-02.log.zst | grep ERROR
 `,
 }
 
@@ -159,13 +182,16 @@ func (s prettyStdout) Write(input []byte) (int, error) {
 }
 
 type catOpts struct {
-	args      []string
-	versionID string
-	timeRef   time.Time
-	startO    int64
-	tailO     int64
-	isZip     bool
-	stdinMode bool
+	args       []string
+	versionID  string
+	timeRef    time.Time
+	startO     int64
+	lengthO    int64
+	tailO      int64
+	isZip      bool
+	decompress bool
+	stdinMode  bool
+	decryptKey *cseKey
 }
 
 // parseCatSyntax performs command-line input validation for cat command.
@@ -198,18 +224,28 @@ func parseCatSyntax(ctx *cli.Context) catOpts {
 	o.timeRef = parseRewindFlag(rewind)
 	o.isZip = ctx.Bool("zip")
 	o.startO = ctx.Int64("offset")
+	o.decompress = ctx.Bool("decompress")
+	o.lengthO = ctx.Int64("length")
 	o.tailO = ctx.Int64("tail")
-	if o.tailO != 0 && o.startO != 0 {
-		fatalIf(errInvalidArgument().Trace(), "You cannot specify both --tail and --offset")
+	if o.tailO != 0 && (o.startO != 0 || o.lengthO != 0) {
+		fatalIf(errInvalidArgument().Trace(), "You cannot specify --tail with --offset or --length")
+	}
+	if o.tailO < 0 || o.startO < 0 || o.lengthO < 0 {
+		fatalIf(errInvalidArgument().Trace(), "You cannot specify negative --tail, --offset or --length")
 	}
-	if o.tailO < 0 || o.startO < 0 {
-		fatalIf(errInvalidArgument().Trace(), "You cannot specify negative --tail or --offset")
+	if o.isZip && (o.tailO != 0 || o.startO != 0 || o.lengthO != 0) {
+		fatalIf(errInvalidArgument().Trace(), "You cannot combine --zip with --tail, --offset or --length")
 	}
-	if o.isZip && (o.tailO != 0 || o.startO != 0) {
-		fatalIf(errInvalidArgument().Trace(), "You cannot combine --zip with --tail or --offset")
+	if o.decompress && (o.isZip || o.tailO != 0 || o.startO != 0 || o.lengthO != 0) {
+		fatalIf(errInvalidArgument().Trace(), "You cannot combine --decompress with --zip, --tail, --offset or --length")
 	}
-	if o.stdinMode && (o.isZip || o.startO != 0 || o.tailO != 0) {
-		fatalIf(errInvalidArgument().Trace(), "You cannot use --zip --tail or --offset with stdin")
+	if o.stdinMode && (o.isZip || o.startO != 0 || o.tailO != 0 || o.lengthO != 0) {
+		fatalIf(errInvalidArgument().Trace(), "You cannot use --zip --tail --offset or --length with stdin")
+	}
+	if spec := ctx.String("decrypt-with"); spec != "" {
+		var err *probe.Error
+		o.decryptKey, err = parseCSEKey(spec, true)
+		fatalIf(err, "Unable to parse client-side encryption key.")
 	}
 
 	return o
@@ -224,7 +260,10 @@ func catURL(ctx context.Context, sourceURL string, encKeyDB map[string][]prefixS
 		reader = os.Stdin
 	default:
 		versionID := o.versionID
-		var err *probe.Error
+		var (
+			err      *probe.Error
+			envelope *cseEnvelope
+		)
 		// Try to stat the object, the purpose is to:
 		// 1. extract the size of S3 object so we can check if the size of the
 		// downloaded object is equal to the original one. FS files
@@ -235,6 +274,16 @@ func catURL(ctx context.Context, sourceURL string, encKeyDB map[string][]prefixS
 			if o.versionID == "" {
 				versionID = content.VersionID
 			}
+			if envelope, err = getCSEEnvelope(content.Metadata); err != nil {
+				return err.Trace(sourceURL)
+			}
+			if envelope != nil {
+				if o.tailO != 0 || o.startO != 0 || o.lengthO != 0 {
+					return probe.NewError(errors.New("--offset, --length and --tail are not supported on client-side encrypted objects")).Trace(sourceURL)
+				}
+				// Check the size of the decrypted data.
+				content.Size = envelope.size
+			}
 			if o.tailO > 0 && content.Size > 0 {
 				o.startO = content.Size - o.tailO
 				if o.startO < 0 {
@@ -249,11 +298,18 @@ func catURL(ctx context.Context, sourceURL string, encKeyDB map[string][]prefixS
 					err := probe.NewError(fmt.Errorf("specified offset (%d) bigger than file (%d)", o.startO, content.Size))
 					return err.Trace(sourceURL)
 				}
+				if o.lengthO > 0 && size > o.lengthO {
+					size = o.lengthO
+				}
 			}
 		} else {
 			return err.Trace(sourceURL)
 		}
 		gopts := GetOptions{VersionID: versionID, Zip: o.isZip, RangeStart: o.startO}
+		if o.lengthO > 0 {
+			gopts.RangeEnd = o.startO + o.lengthO - 1
+			gopts.HasRangeEnd = true
+		}
 		if reader, err = getSourceStreamFromURL(ctx, sourceURL, encKeyDB, getSourceOpts{
 			GetOptions: gopts,
 			fetchStat:  false,
@@ -262,6 +318,22 @@ func catURL(ctx context.Context, sourceURL string, encKeyDB map[string][]prefixS
 			return err.Trace(sourceURL)
 		}
 		defer reader.Close()
+		if envelope != nil {
+			decrypted, err := envelope.decryptReader(reader, o.decryptKey)
+			if err != nil {
+				return err.Trace(sourceURL)
+			}
+			reader = io.NopCloser(decrypted)
+		}
+	}
+	if o.decompress {
+		// The size of the decompressed content is unknown.
+		dec, e := decompressReader(reader, sourceURL)
+		if e != nil {
+			return probe.NewError(e).Trace(sourceURL)
+		}
+		defer dec.Close()
+		return catOut(dec, -1).Trace(sourceURL)
 	}
 	return catOut(reader, size).Trace(sourceURL)
 }
@@ -553,6 +553,12 @@ func (f *fsClient) Get(_ context.Context, opts GetOptions) (io.ReadCloser, *prob
 			return nil, err.Trace(f.PathURL.Path)
 		}
 	}
+	if opts.HasRangeEnd {
+		return struct {
+			io.Reader
+			io.Closer
+		}{io.LimitReader(fileData, opts.RangeEnd-opts.RangeStart+1), fileData}, nil
+	}
 
 	return fileData, nil
 }
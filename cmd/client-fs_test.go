@@ -331,6 +331,38 @@ func (s *TestSuite) TestGetRange(c *C) {
 	c.Assert([]byte("hello"), DeepEquals, results.Bytes())
 }
 
+// Test get with a range start and end.
+func (s *TestSuite) TestGetRangeEnd(c *C) {
+	root, e := os.MkdirTemp(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	objectPath := filepath.Join(root, "object")
+	c.Assert(os.WriteFile(objectPath, []byte("hello world"), 0o644), IsNil)
+	fsClient, err := fsNew(objectPath)
+	c.Assert(err, IsNil)
+
+	testCases := []struct {
+		opts     GetOptions
+		expected string
+	}{
+		{GetOptions{}, "hello world"},
+		{GetOptions{RangeStart: 6}, "world"},
+		// A single byte at offset 0.
+		{GetOptions{RangeStart: 0, RangeEnd: 0, HasRangeEnd: true}, "h"},
+		{GetOptions{RangeStart: 0, RangeEnd: 4, HasRangeEnd: true}, "hello"},
+		{GetOptions{RangeStart: 4, RangeEnd: 4, HasRangeEnd: true}, "o"},
+	}
+	for _, testCase := range testCases {
+		reader, err := fsClient.Get(context.Background(), testCase.opts)
+		c.Assert(err, IsNil)
+		data, e := io.ReadAll(reader)
+		reader.Close()
+		c.Assert(e, IsNil)
+		c.Assert(string(data), Equals, testCase.expected)
+	}
+}
+
 // Test stat file.
 func (s *TestSuite) TestStatObject(c *C) {
 	root, e := os.MkdirTemp(os.TempDir(), "fs-")
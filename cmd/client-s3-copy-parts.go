@@ -0,0 +1,187 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/dustin/go-humanize"
+	minio "github.com/trinet2005/oss-go-sdk"
+	"github.com/trinet2005/oss-go-sdk/pkg/encrypt"
+	"github.com/trinet2005/oss-go-sdk/pkg/s3utils"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+const (
+	// Default size of the parts of a server side copy.
+	copyPartsDefaultSize = 512 * humanize.MiByte
+	// Default number of parts copied in parallel.
+	copyPartsDefaultParallel = 4
+	// Maximum number of parts of a multipart upload.
+	copyPartsMaxCount = 10000
+)
+
+// copyPartSize returns the size of the parts of a server side copy of
+// size bytes, partSize if set, grown so that there are no more than
+// 10000 parts.
+func copyPartSize(size, partSize int64) int64 {
+	if partSize <= 0 {
+		partSize = copyPartsDefaultSize
+	}
+	if minSize := (size + copyPartsMaxCount - 1) / copyPartsMaxCount; partSize < minSize {
+		// Round up to the next MiB.
+		partSize = (minSize + humanize.MiByte - 1) / humanize.MiByte * humanize.MiByte
+	}
+	if partSize > maxPartSize {
+		partSize = maxPartSize
+	}
+	return partSize
+}
+
+// copyParts copies a large object server side with UploadPartCopy
+// requests sent in parallel, each part retried on its own according
+// to the retry policy. progress, if any, is read as parts complete.
+func (c *S3Client) copyParts(ctx context.Context, dst minio.CopyDestOptions, src minio.CopySrcOptions, size, partSize int64, parallel int, progress io.Reader) error {
+	if parallel <= 0 {
+		parallel = copyPartsDefaultParallel
+	}
+	partSize = copyPartSize(size, partSize)
+
+	srcInfo, e := c.api.StatObject(ctx, src.Bucket, src.Object, minio.StatObjectOptions{
+		VersionID:            src.VersionID,
+		ServerSideEncryption: src.Encryption,
+	})
+	if e != nil {
+		return e
+	}
+
+	putOpts := minio.PutObjectOptions{
+		ContentType:          srcInfo.ContentType,
+		UserMetadata:         srcInfo.UserMetadata,
+		UserTags:             srcInfo.UserTags,
+		ServerSideEncryption: dst.Encryption,
+		Mode:                 dst.Mode,
+		RetainUntilDate:      dst.RetainUntilDate,
+		LegalHold:            dst.LegalHold,
+	}
+	if dst.ReplaceMetadata {
+		putOpts.UserMetadata = dst.UserMetadata
+	}
+
+	// Headers of every part copy: the source, unchanged since the
+	// copy started, and the encryption keys.
+	h := make(http.Header)
+	if src.Encryption != nil {
+		encrypt.SSECopy(src.Encryption).Marshal(h)
+	}
+	if dst.Encryption != nil && dst.Encryption.Type() == encrypt.SSEC {
+		dst.Encryption.Marshal(h)
+	}
+	h.Set("x-amz-copy-source-if-match", srcInfo.ETag)
+	if src.VersionID != "" {
+		h.Set("x-amz-copy-source", s3utils.EncodePath(src.Bucket+"/"+src.Object)+"?versionId="+src.VersionID)
+	}
+	headers := make(map[string]string, len(h))
+	for k := range h {
+		headers[k] = h.Get(k)
+	}
+
+	core := &minio.Core{Client: c.api}
+	uploadID, e := core.NewMultipartUpload(ctx, dst.Bucket, dst.Object, putOpts)
+	if e != nil {
+		return e
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type copyPart struct {
+		number         int
+		offset, length int64
+	}
+	partCh := make(chan copyPart)
+	go func() {
+		defer close(partCh)
+		for number, offset := 1, int64(0); offset < size; number, offset = number+1, offset+partSize {
+			length := partSize
+			if offset+length > size {
+				length = size - offset
+			}
+			select {
+			case partCh <- copyPart{number, offset, length}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		parts    []minio.CompletePart
+		firstErr error
+	)
+	target := c.targetURL.String()
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for part := range partCh {
+				var completed minio.CompletePart
+				err := withRetry(ctx, fmt.Sprintf("copy of part %d", part.number), target, func() *probe.Error {
+					var e error
+					completed, e = core.CopyObjectPart(ctx, src.Bucket, src.Object, dst.Bucket, dst.Object,
+						uploadID, part.number, part.offset, part.length, headers)
+					return probe.NewError(e)
+				})
+
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err.ToGoError()
+					}
+					cancel()
+				} else {
+					parts = append(parts, completed)
+					if progress != nil {
+						io.CopyN(io.Discard, progress, part.length)
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr == nil {
+		sort.Slice(parts, func(i, j int) bool {
+			return parts[i].PartNumber < parts[j].PartNumber
+		})
+		_, firstErr = core.CompleteMultipartUpload(ctx, dst.Bucket, dst.Object, uploadID, parts, putOpts)
+	}
+	if firstErr != nil {
+		// Do not leave the copied parts behind.
+		core.AbortMultipartUpload(context.Background(), dst.Bucket, dst.Object, uploadID)
+	}
+	return firstErr
+}
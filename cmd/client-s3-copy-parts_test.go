@@ -0,0 +1,46 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/dustin/go-humanize"
+)
+
+func TestCopyPartSize(t *testing.T) {
+	testCases := []struct {
+		size     int64
+		partSize int64
+		expected int64
+	}{
+		{100 * humanize.MiByte, 0, copyPartsDefaultSize},
+		{100 * humanize.MiByte, 16 * humanize.MiByte, 16 * humanize.MiByte},
+		// 10000 parts of 512MiB are not enough for 10TiB.
+		{10 * humanize.TiByte, 0, 1049 * humanize.MiByte},
+		{10 * humanize.TiByte, 64 * humanize.MiByte, 1049 * humanize.MiByte},
+		{10 * humanize.TiByte, 2 * humanize.GiByte, 2 * humanize.GiByte},
+		{100 * humanize.TiByte, 0, maxPartSize},
+	}
+
+	for i, testCase := range testCases {
+		if partSize := copyPartSize(testCase.size, testCase.partSize); partSize != testCase.expected {
+			t.Fatalf("Test %d: expected %d, got %d", i+1, testCase.expected, partSize)
+		}
+	}
+}
@@ -213,6 +213,11 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 				transport = tr
 			}
 
+			if strings.EqualFold(config.Signature, "S3v4") {
+				// Compensate for a skewed local clock once the server rejects the request time.
+				transport = newSkewTransport(hostName, config.SecretKey, transport)
+			}
+
 			transport = limiter.New(config.UploadLimit, config.DownloadLimit, transport)
 
 			if config.Debug {
@@ -861,9 +866,17 @@ func (c *S3Client) Get(ctx context.Context, opts GetOptions) (io.ReadCloser, *pr
 	if opts.Zip {
 		o.Set("x-minio-extract", "true")
 	}
-	if opts.RangeStart != 0 {
-		err := o.SetRange(opts.RangeStart, 0)
-		if err != nil {
+	if opts.MatchETag != "" {
+		if err := o.SetMatchETag(opts.MatchETag); err != nil {
+			return nil, probe.NewError(err)
+		}
+	}
+	if opts.RangeStart != 0 || opts.HasRangeEnd {
+		var rangeEnd int64
+		if opts.HasRangeEnd {
+			rangeEnd = opts.RangeEnd
+		}
+		if err := o.SetRange(opts.RangeStart, rangeEnd); err != nil {
 			return nil, probe.NewError(err)
 		}
 	}
@@ -948,10 +961,12 @@ func (c *S3Client) Copy(ctx context.Context, source string, opts CopyOptions, pr
 	destOpts.ReplaceMetadata = len(metadata) > 0
 
 	var e error
-	if opts.disableMultipart || opts.size < 64*1024*1024 {
+	// A single copy is limited to 5GiB, larger objects are always
+	// copied part by part.
+	if opts.size < 64*1024*1024 || (opts.disableMultipart && opts.size <= maxPartSize) {
 		_, e = c.api.CopyObject(ctx, destOpts, srcOpts)
 	} else {
-		_, e = c.api.ComposeObject(ctx, destOpts, srcOpts)
+		e = c.copyParts(ctx, destOpts, srcOpts, opts.size, opts.partSize, opts.parallelParts, progress)
 	}
 
 	if e != nil {
@@ -1071,6 +1086,10 @@ func (c *S3Client) Put(ctx context.Context, reader io.Reader, size int64, progre
 		opts.RetainUntilDate = retainUntilDate
 	}
 
+	if putOpts.ifNotExists {
+		opts.SetMatchETagExcept("*")
+	}
+
 	if lockModeStr != "" {
 		opts.Mode = lockMode
 		opts.SendContentMd5 = true
@@ -1096,7 +1115,7 @@ func (c *S3Client) Put(ctx context.Context, reader io.Reader, size int64, progre
 				Path: c.targetURL.String(),
 			})
 		}
-		if errResponse.Code == "MethodNotAllowed" {
+		if errResponse.Code == "MethodNotAllowed" || (putOpts.ifNotExists && errResponse.Code == "PreconditionFailed") {
 			return ui.Size, probe.NewError(ObjectAlreadyExists{
 				Object: object,
 			})
@@ -1245,8 +1264,9 @@ func (c *S3Client) Remove(ctx context.Context, isIncomplete, isRemoveBucket, isB
 					for removeStatus := range statusCh {
 						if removeStatus.Err != nil {
 							resultCh <- RemoveResult{
-								BucketName: bucket,
-								Err:        probe.NewError(removeStatus.Err),
+								BucketName:         bucket,
+								RemoveObjectResult: removeStatus,
+								Err:                probe.NewError(removeStatus.Err),
 							}
 						} else {
 							resultCh <- RemoveResult{
@@ -1291,8 +1311,9 @@ func (c *S3Client) Remove(ctx context.Context, isIncomplete, isRemoveBucket, isB
 						case removeStatus := <-statusCh:
 							if removeStatus.Err != nil {
 								resultCh <- RemoveResult{
-									BucketName: bucket,
-									Err:        probe.NewError(removeStatus.Err),
+									BucketName:         bucket,
+									RemoveObjectResult: removeStatus,
+									Err:                probe.NewError(removeStatus.Err),
 								}
 							} else {
 								resultCh <- RemoveResult{
@@ -1331,7 +1352,9 @@ func (c *S3Client) Remove(ctx context.Context, isIncomplete, isRemoveBucket, isB
 					// it is too generic. We have the object's name and vid.
 					// Adding the object's name and version id into the error msg
 					resultCh <- RemoveResult{
-						Err: probe.NewError(removeStatus.Err),
+						BucketName:         prevBucket,
+						RemoveObjectResult: removeStatus,
+						Err:                probe.NewError(removeStatus.Err),
 					}
 				} else {
 					resultCh <- RemoveResult{
@@ -2523,6 +2546,32 @@ func (c *S3Client) GetObjectLegalHold(ctx context.Context, versionID string) (mi
 	return lhold, nil
 }
 
+// GetObjectChecksums - Get the checksums of an object, keyed by algorithm,
+// as returned by GetObjectAttributes.
+func (c *S3Client) GetObjectChecksums(ctx context.Context, versionID string, sse encrypt.ServerSide) (map[string]string, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	attrs, e := c.api.GetObjectAttributes(ctx, bucket, object, minio.ObjectAttributesOptions{
+		VersionID:            versionID,
+		ServerSideEncryption: sse,
+	})
+	if e != nil {
+		return nil, probe.NewError(e).Trace(c.GetURL().String())
+	}
+
+	checksums := make(map[string]string)
+	for algorithm, value := range map[string]string{
+		"CRC32":  attrs.Checksum.ChecksumCRC32,
+		"CRC32C": attrs.Checksum.ChecksumCRC32C,
+		"SHA1":   attrs.Checksum.ChecksumSHA1,
+		"SHA256": attrs.Checksum.ChecksumSHA256,
+	} {
+		if value != "" {
+			checksums[algorithm] = value
+		}
+	}
+	return checksums, nil
+}
+
 // GetObjectLockConfig - Get object lock configuration of bucket.
 func (c *S3Client) GetObjectLockConfig(ctx context.Context) (string, minio.RetentionMode, uint64, minio.ValidityUnit, *probe.Error) {
 	bucket, object := c.url2BucketAndObject()
@@ -2892,6 +2941,7 @@ func (c *S3Client) GetBucketInfo(ctx context.Context) (BucketInfo, *probe.Error)
 	if rcfg, err := c.GetReplication(ctx); err == nil {
 		if !rcfg.Empty() {
 			b.Replication.Enabled = true
+			b.Replication.Config = rcfg
 		}
 	}
 	if algo, keyID, err := c.GetEncryption(ctx); err == nil {
@@ -21,6 +21,7 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -148,11 +149,18 @@ func (h objectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		w.Header().Set("Content-Length", strconv.Itoa(len(h.data)))
+		data, status := h.data, http.StatusOK
+		var start, end int
+		if _, e := fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-%d", &start, &end); e == nil {
+			data, status = h.data[start:end+1], http.StatusPartialContent
+		} else if _, e = fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-", &start); e == nil {
+			data, status = h.data[start:], http.StatusPartialContent
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
 		w.Header().Set("Last-Modified", UTCNow().Format(http.TimeFormat))
 		w.Header().Set("ETag", "9af2f8218b150c351ad802c6f3d66abe")
-		w.WriteHeader(http.StatusOK)
-		io.Copy(w, bytes.NewReader(h.data))
+		w.WriteHeader(status)
+		io.Copy(w, bytes.NewReader(data))
 	}
 }
 
@@ -240,6 +248,43 @@ func (s *TestSuite) TestObjectOperations(c *C) {
 	}
 }
 
+// Test ranged get of an object.
+func (s *TestSuite) TestGetObjectRange(c *C) {
+	object := objectHandler{
+		resource: "/bucket/object",
+		data:     []byte("Hello, World"),
+	}
+	server := httptest.NewServer(object)
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + object.resource
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := S3New(conf)
+	c.Assert(err, IsNil)
+
+	testCases := []struct {
+		opts     GetOptions
+		expected string
+	}{
+		{GetOptions{RangeStart: 7}, "World"},
+		// A single byte at offset 0.
+		{GetOptions{RangeStart: 0, RangeEnd: 0, HasRangeEnd: true}, "H"},
+		{GetOptions{RangeStart: 0, RangeEnd: 4, HasRangeEnd: true}, "Hello"},
+		{GetOptions{RangeStart: 7, RangeEnd: 7, HasRangeEnd: true}, "W"},
+	}
+	for _, testCase := range testCases {
+		reader, err := s3c.Get(context.Background(), testCase.opts)
+		c.Assert(err, IsNil)
+		data, e := io.ReadAll(reader)
+		reader.Close()
+		c.Assert(e, IsNil)
+		c.Assert(string(data), Equals, testCase.expected)
+	}
+}
+
 var testSelectCompressionTypeCases = []struct {
 	opts            SelectObjectOpts
 	object          string
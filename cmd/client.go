@@ -45,10 +45,13 @@ const (
 
 // GetOptions holds options of the GET operation
 type GetOptions struct {
-	SSE        encrypt.ServerSide
-	VersionID  string
-	Zip        bool
-	RangeStart int64
+	SSE         encrypt.ServerSide
+	VersionID   string
+	Zip         bool
+	RangeStart  int64
+	RangeEnd    int64  // last byte of the range, inclusive, used when HasRangeEnd is set
+	HasRangeEnd bool   // read up to RangeEnd rather than to the end of the object
+	MatchETag   string // fail unless the object still has this ETag, ignored when empty
 }
 
 // PutOptions holds options for PUT operation
@@ -61,6 +64,7 @@ type PutOptions struct {
 	multipartSize         uint64
 	multipartThreads      uint
 	concurrentStream      bool
+	ifNotExists           bool // fail with ObjectAlreadyExists if the object exists, where the server supports it
 }
 
 // StatOptions holds options of the HEAD operation
@@ -95,6 +99,8 @@ type CopyOptions struct {
 	disableMultipart bool
 	isPreserve       bool
 	storageClass     string
+	partSize         int64
+	parallelParts    int
 }
 
 // Client - client interface
@@ -0,0 +1,271 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/trinet2005/oss-go-sdk/pkg/s3utils"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+const (
+	signV4Algorithm     = "AWS4-HMAC-SHA256"
+	signV4TimeFormat    = "20060102T150405Z"
+	signV4ShortFormat   = "20060102"
+	streamingPayloadSig = "STREAMING-"
+)
+
+// Clock offsets measured against servers which rejected
+// requests with RequestTimeTooSkewed, keyed by host.
+var (
+	clockOffsetsMu sync.RWMutex
+	clockOffsets   = map[string]time.Duration{}
+)
+
+// clockOffset returns the offset to add to the local
+// clock when signing requests sent to host.
+func clockOffset(host string) time.Duration {
+	clockOffsetsMu.RLock()
+	defer clockOffsetsMu.RUnlock()
+	return clockOffsets[host]
+}
+
+// setClockOffset records the offset measured for host and
+// warns the first time the local clock is found skewed.
+func setClockOffset(host string, offset time.Duration) {
+	clockOffsetsMu.Lock()
+	_, found := clockOffsets[host]
+	clockOffsets[host] = offset
+	clockOffsetsMu.Unlock()
+
+	if !found {
+		warningIf(warningDowngraded, probe.NewError(fmt.Errorf("%s", describeClockSkew(-offset))).Trace(host),
+			"Server `%s` rejected the request time, signing requests with the server clock.", host)
+	}
+}
+
+// describeClockSkew returns a human readable form of the
+// local clock skew, positive when the local clock is ahead.
+func describeClockSkew(skew time.Duration) string {
+	skew = skew.Round(time.Second)
+	if skew < 0 {
+		return fmt.Sprintf("local clock is %s behind the server", -skew)
+	}
+	return fmt.Sprintf("local clock is %s ahead of the server", skew)
+}
+
+// measureClockSkew sends an anonymous HEAD request to endpoint and
+// returns how far the local clock is ahead of the server clock.
+func measureClockSkew(ctx context.Context, endpoint string, timeout time.Duration) (time.Duration, *probe.Error) {
+	client := httpClient(timeout)
+	if tr, ok := client.Transport.(*http.Transport); ok {
+		tr.TLSClientConfig.InsecureSkipVerify = globalInsecure
+	}
+	req, e := http.NewRequestWithContext(ctx, http.MethodHead, endpoint, nil)
+	if e != nil {
+		return 0, probe.NewError(e)
+	}
+	start := time.Now()
+	resp, e := client.Do(req)
+	if e != nil {
+		return 0, probe.NewError(e)
+	}
+	resp.Body.Close()
+
+	serverTime, e := http.ParseTime(resp.Header.Get("Date"))
+	if e != nil {
+		return 0, probe.NewError(fmt.Errorf("no server time in the response from `%s`", endpoint))
+	}
+	// Compare against the middle of the round trip.
+	local := start.Add(time.Since(start) / 2)
+	return local.Sub(serverTime), nil
+}
+
+// skewTransport re-signs AWS Signature V4 requests with the server
+// clock once a server rejected a request with RequestTimeTooSkewed.
+type skewTransport struct {
+	host      string
+	secretKey string
+	transport http.RoundTripper
+}
+
+func newSkewTransport(host, secretKey string, transport http.RoundTripper) http.RoundTripper {
+	return &skewTransport{host: host, secretKey: secretKey, transport: transport}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *skewTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if offset := clockOffset(t.host); offset != 0 {
+		req = t.resign(req, time.Now().Add(offset))
+	}
+	resp, e := t.transport.RoundTrip(req)
+	if e != nil || resp.StatusCode != http.StatusForbidden {
+		return resp, e
+	}
+	serverTime, skewed := requestTimeTooSkewed(resp)
+	if !skewed {
+		return resp, nil
+	}
+	offset := time.Until(serverTime)
+	setClockOffset(t.host, offset)
+
+	// Requests with a body which cannot be replayed are failed,
+	// the caller retries them with the corrected signing time.
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+	retry := t.resign(req, time.Now().Add(offset))
+	if retry == req {
+		return resp, nil
+	}
+	if req.GetBody != nil {
+		body, e := req.GetBody()
+		if e != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+	resp.Body.Close()
+	return t.transport.RoundTrip(retry)
+}
+
+// requestTimeTooSkewed reports whether resp is a RequestTimeTooSkewed
+// error and returns the server time from its Date header. The body
+// of the response is preserved for the caller.
+func requestTimeTooSkewed(resp *http.Response) (time.Time, bool) {
+	body, e := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if e != nil || !bytes.Contains(body, []byte("<Code>RequestTimeTooSkewed</Code>")) {
+		return time.Time{}, false
+	}
+	serverTime, e := http.ParseTime(resp.Header.Get("Date"))
+	if e != nil {
+		return time.Time{}, false
+	}
+	return serverTime, true
+}
+
+// resign returns a copy of req signed at signTime. Requests which
+// are not signed with V4 headers, presigned requests and streaming
+// uploads, whose chunk signatures depend on the seed signature, are
+// returned unchanged.
+func (t *skewTransport) resign(req *http.Request, signTime time.Time) *http.Request {
+	auth := req.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, signV4Algorithm+" ") {
+		return req
+	}
+	payload := req.Header.Get("X-Amz-Content-Sha256")
+	if payload == "" || strings.HasPrefix(payload, streamingPayloadSig) {
+		return req
+	}
+
+	var credential, signedHeaders string
+	for _, field := range strings.Split(strings.TrimPrefix(auth, signV4Algorithm+" "), ",") {
+		field = strings.TrimSpace(field)
+		switch {
+		case strings.HasPrefix(field, "Credential="):
+			credential = strings.TrimPrefix(field, "Credential=")
+		case strings.HasPrefix(field, "SignedHeaders="):
+			signedHeaders = strings.TrimPrefix(field, "SignedHeaders=")
+		}
+	}
+	// Credential is <access-key>/<date>/<region>/<service>/aws4_request
+	parts := strings.Split(credential, "/")
+	if len(parts) < 5 || signedHeaders == "" {
+		return req
+	}
+	accessKey := strings.Join(parts[:len(parts)-4], "/")
+	region, service := parts[len(parts)-3], parts[len(parts)-2]
+
+	signTime = signTime.UTC()
+	clone := req.Clone(req.Context())
+	clone.Header.Set("X-Amz-Date", signTime.Format(signV4TimeFormat))
+
+	scope := strings.Join([]string{signTime.Format(signV4ShortFormat), region, service, "aws4_request"}, "/")
+	canonicalRequest := strings.Join([]string{
+		clone.Method,
+		s3utils.EncodePath(clone.URL.Path),
+		strings.ReplaceAll(clone.URL.Query().Encode(), "+", "%20"),
+		canonicalHeaders(clone, strings.Split(signedHeaders, ";")),
+		signedHeaders,
+		payload,
+	}, "\n")
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		signV4Algorithm,
+		signTime.Format(signV4TimeFormat),
+		scope,
+		hex.EncodeToString(canonicalHash[:]),
+	}, "\n")
+
+	key := []byte("AWS4" + t.secretKey)
+	for _, s := range []string{signTime.Format(signV4ShortFormat), region, service, "aws4_request"} {
+		key = sumHMAC(key, []byte(s))
+	}
+	signature := hex.EncodeToString(sumHMAC(key, []byte(stringToSign)))
+
+	clone.Header.Set("Authorization", signV4Algorithm+" Credential="+accessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+	return clone
+}
+
+// canonicalHeaders returns the canonical form of the signed headers of req.
+func canonicalHeaders(req *http.Request, signed []string) string {
+	sort.Strings(signed)
+	var buf strings.Builder
+	for _, name := range signed {
+		buf.WriteString(name)
+		buf.WriteByte(':')
+		if name == "host" {
+			host := req.Host
+			if host == "" {
+				host = req.URL.Host
+			}
+			buf.WriteString(host)
+		} else {
+			for i, v := range req.Header.Values(name) {
+				if i > 0 {
+					buf.WriteByte(',')
+				}
+				buf.WriteString(strings.Join(strings.Fields(v), " "))
+			}
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+// sumHMAC calculates the HMAC-SHA256 of data with key.
+func sumHMAC(key, data []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/trinet2005/oss-go-sdk/pkg/signer"
+)
+
+func TestSkewTransportResign(t *testing.T) {
+	const (
+		accessKey       = "Q3AM3UQ867SPQQA43P2F"
+		secretKey       = "zuf+tfteSlswRu7BJ86wekitnifILbZam1KYY3TG"
+		unsignedPayload = "UNSIGNED-PAYLOAD"
+	)
+
+	testCases := []struct {
+		method  string
+		url     string
+		payload string
+		headers http.Header
+	}{
+		// Query strings, repeated, escaped and with spaces.
+		{http.MethodGet, "https://play.min.io/bucket?list-type=2&prefix=a+b/c%2Fd&tag=b&tag=a&versionId=", unsignedPayload, nil},
+		// Object names needing escaping.
+		{http.MethodHead, "https://play.min.io/bucket/dir/a b+c/ü.txt?versionId=3a1b", unsignedPayload, nil},
+		// Unsigned payload with user metadata, multi-value headers and extra spaces.
+		{http.MethodPut, "https://play.min.io:9000/bucket/object", unsignedPayload, http.Header{
+			"X-Amz-Meta-Tags":      {"a", "b  c"},
+			"X-Amz-Meta-Comment":   {"  spaced   value "},
+			"Content-Type":         {"text/plain"},
+			"X-Amz-Security-Token": {"token"},
+		}},
+		// Signed payload.
+		{http.MethodPost, "https://play.min.io/bucket?delete=", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", http.Header{
+			"Content-Md5": {"1B2M2Y8AsgTpgAmY7PhCfg=="},
+		}},
+	}
+
+	transport := &skewTransport{secretKey: secretKey}
+	for i, testCase := range testCases {
+		req, e := http.NewRequest(testCase.method, testCase.url, nil)
+		if e != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, e)
+		}
+		for k, v := range testCase.headers {
+			req.Header[k] = v
+		}
+		req.Header.Set("X-Amz-Content-Sha256", testCase.payload)
+		req.Header.Set("User-Agent", "MinIO (linux; amd64) mc/DEVELOPMENT")
+		signed := signer.SignV4(*req, accessKey, secretKey, "", "us-east-1")
+
+		// Signing again at the time of the SDK gives the same signature.
+		signTime, e := time.Parse(signV4TimeFormat, signed.Header.Get("X-Amz-Date"))
+		if e != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, e)
+		}
+		resigned := transport.resign(signed, signTime)
+		if got, expected := resigned.Header.Get("Authorization"), signed.Header.Get("Authorization"); got != expected {
+			t.Fatalf("Test %d: expected %s, got %s", i+1, expected, got)
+		}
+
+		// Signing at another time only changes the date and the signature.
+		resigned = transport.resign(signed, signTime.Add(-time.Hour*30))
+		if resigned.Header.Get("X-Amz-Date") != signTime.Add(-time.Hour*30).Format(signV4TimeFormat) {
+			t.Fatalf("Test %d: expected the request to be signed at the new time", i+1)
+		}
+		if resigned.Header.Get("Authorization") == signed.Header.Get("Authorization") {
+			t.Fatalf("Test %d: expected a new signature", i+1)
+		}
+		if signed.Header.Get("X-Amz-Date") != signTime.Format(signV4TimeFormat) {
+			t.Fatalf("Test %d: expected the original request to be left unchanged", i+1)
+		}
+	}
+}
+
+func TestSkewTransportResignUnchanged(t *testing.T) {
+	testCases := []http.Header{
+		// Not signed.
+		{"X-Amz-Content-Sha256": {"UNSIGNED-PAYLOAD"}},
+		// Signed with V2.
+		{"X-Amz-Content-Sha256": {"UNSIGNED-PAYLOAD"}, "Authorization": {"AWS Q3AM3UQ867SPQQA43P2F:signature"}},
+		// Streaming upload, whose chunks are signed with the seed signature.
+		{"X-Amz-Content-Sha256": {"STREAMING-AWS4-HMAC-SHA256-PAYLOAD"}, "Authorization": {"AWS4-HMAC-SHA256 Credential=a/20230101/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-date, Signature=0"}},
+	}
+
+	transport := &skewTransport{secretKey: "secret"}
+	for i, headers := range testCases {
+		req, e := http.NewRequest(http.MethodPut, "https://play.min.io/bucket/object", nil)
+		if e != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, e)
+		}
+		req.Header = headers
+		if transport.resign(req, time.Now()) != req {
+			t.Fatalf("Test %d: expected the request to be left unchanged", i+1)
+		}
+	}
+}
@@ -0,0 +1,362 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+var cmpFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "bytes",
+		Usage: "compare the contents byte by byte to report the first differing offset",
+	},
+	cli.Int64Flag{
+		Name:  "offset",
+		Usage: "start offset of the byte comparison",
+	},
+	cli.Int64Flag{
+		Name:  "length",
+		Usage: "number of bytes to compare byte by byte, starting at --offset",
+	},
+}
+
+var cmpCmd = cli.Command{
+	Name:         "cmp",
+	Usage:        "compare the contents of two objects",
+	Action:       mainCmp,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(append(cmpFlags, ioFlags...), globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] FIRST SECOND
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Compare two objects, remote or local, without downloading them to disk. Objects of different sizes
+  differ. Otherwise they are compared by their ETags or their checksums when these are enough, and by
+  hashing both contents when they are not. With --bytes, objects which differ are also compared byte
+  by byte, optionally over a range, to report the first differing offset.
+
+  Exits with status 1 when the objects differ.
+
+EXAMPLES:
+  1. Compare an object with its replica.
+     {{.Prompt}} {{.HelpName}} site1/mybucket/data.bin site2/mybucket/data.bin
+
+  2. Find the first byte where an object differs from a local file.
+     {{.Prompt}} {{.HelpName}} --bytes myminio/mybucket/data.bin /tmp/data.bin
+
+  3. Compare the first MiB after offset 1 GiB of two objects byte by byte.
+     {{.Prompt}} {{.HelpName}} --bytes --offset 1073741824 --length 1048576 myminio/mybucket/a.bin myminio/mybucket/b.bin
+
+  4. Compare two objects encrypted with SSE-C.
+     {{.Prompt}} {{.HelpName}} --encrypt-key "myminio/mybucket/=32byteslongsecretkeymustbegiven1" myminio/mybucket/a.bin myminio/mybucket/b.bin
+`,
+}
+
+// Methods used by 'cmp' in addition to the ones of mirror verify.
+const (
+	cmpMethodSize  = "size"
+	cmpMethodBytes = "bytes"
+)
+
+// cmpDifference is the first differing byte of two objects, -1 for
+// the end of an object.
+type cmpDifference struct {
+	Offset int64 `json:"offset"`
+	First  int   `json:"first"`
+	Second int   `json:"second"`
+}
+
+// cmpMessage is the result of comparing two objects.
+type cmpMessage struct {
+	Status     string         `json:"status"`
+	First      string         `json:"first"`
+	Second     string         `json:"second"`
+	FirstSize  int64          `json:"firstSize"`
+	SecondSize int64          `json:"secondSize"`
+	Equal      bool           `json:"equal"`
+	Method     string         `json:"method"`
+	Difference *cmpDifference `json:"difference,omitempty"`
+	Range      string         `json:"range,omitempty"`
+}
+
+// String colorized cmp message.
+func (m cmpMessage) String() string {
+	d := m.Difference
+	switch {
+	case m.Equal:
+		return console.Colorize("CmpEqual", fmt.Sprintf("`%s` and `%s` are identical, compared by %s.", m.First, m.Second, m.Method))
+	case d != nil && d.First < 0:
+		return console.Colorize("CmpDiffer", fmt.Sprintf("`%s` and `%s` differ: EOF on `%s` after byte %d.", m.First, m.Second, m.First, d.Offset))
+	case d != nil && d.Second < 0:
+		return console.Colorize("CmpDiffer", fmt.Sprintf("`%s` and `%s` differ: EOF on `%s` after byte %d.", m.First, m.Second, m.Second, d.Offset))
+	case d != nil:
+		return console.Colorize("CmpDiffer", fmt.Sprintf("`%s` and `%s` differ at byte %d (0x%x): 0x%02x != 0x%02x.",
+			m.First, m.Second, d.Offset, d.Offset, d.First, d.Second))
+	case m.Range != "":
+		return console.Colorize("CmpDiffer", fmt.Sprintf("`%s` and `%s` differ, compared by %s, but not in bytes %s.", m.First, m.Second, m.Method, m.Range))
+	case m.Method == cmpMethodSize:
+		return console.Colorize("CmpDiffer", fmt.Sprintf("`%s` and `%s` differ in size: %d and %d bytes.", m.First, m.Second, m.FirstSize, m.SecondSize))
+	}
+	return console.Colorize("CmpDiffer", fmt.Sprintf("`%s` and `%s` differ, compared by %s.", m.First, m.Second, m.Method))
+}
+
+// JSON jsonified cmp message.
+func (m cmpMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// compareChecksums compares the checksums of two objects for the
+// strongest algorithm both have. Checksums of objects uploaded in
+// several parts depend on the parts, so only equal ones can tell.
+func compareChecksums(first, second map[string]string) (algorithm string, equal, ok bool) {
+	for _, algorithm := range []string{"SHA256", "SHA1", "CRC32C", "CRC32"} {
+		firstSum, secondSum := first[algorithm], second[algorithm]
+		if firstSum == "" || secondSum == "" {
+			continue
+		}
+		if firstSum == secondSum {
+			return algorithm, true, true
+		}
+		if !strings.Contains(firstSum, "-") && !strings.Contains(secondSum, "-") {
+			return algorithm, false, true
+		}
+	}
+	return "", false, false
+}
+
+// compareReaders compares two streams starting at offset, returning the
+// first difference or nil if both are identical.
+func compareReaders(first, second io.Reader, offset int64) (*cmpDifference, error) {
+	firstBuf := make([]byte, 32*1024)
+	secondBuf := make([]byte, 32*1024)
+	for {
+		n1, e := io.ReadFull(first, firstBuf)
+		if e != nil && e != io.EOF && e != io.ErrUnexpectedEOF {
+			return nil, e
+		}
+		n2, e := io.ReadFull(second, secondBuf)
+		if e != nil && e != io.EOF && e != io.ErrUnexpectedEOF {
+			return nil, e
+		}
+
+		n := n1
+		if n2 < n {
+			n = n2
+		}
+		for i := 0; i < n; i++ {
+			if firstBuf[i] != secondBuf[i] {
+				return &cmpDifference{Offset: offset + int64(i), First: int(firstBuf[i]), Second: int(secondBuf[i])}, nil
+			}
+		}
+		switch {
+		case n1 < n2:
+			return &cmpDifference{Offset: offset + int64(n), First: -1, Second: int(secondBuf[n])}, nil
+		case n2 < n1:
+			return &cmpDifference{Offset: offset + int64(n), First: int(firstBuf[n]), Second: -1}, nil
+		case n1 < len(firstBuf):
+			return nil, nil
+		}
+		offset += int64(n)
+	}
+}
+
+// cmpObject is one of the objects compared.
+type cmpObject struct {
+	url     string
+	alias   string
+	urlStr  string
+	opts    GetOptions
+	content *ClientContent
+	clnt    Client
+}
+
+// statCmpObject stats an object to compare.
+func statCmpObject(ctx context.Context, urlStr string, encKeyDB map[string][]prefixSSEPair) (cmpObject, *probe.Error) {
+	alias, urlStrFull, _ := mustExpandAlias(urlStr)
+	clnt, content, err := url2Stat(ctx, urlStr, "", false, encKeyDB, time.Time{}, false)
+	if err != nil {
+		return cmpObject{}, err.Trace(urlStr)
+	}
+	if content.Type.IsDir() {
+		return cmpObject{}, errInvalidArgument().Trace(urlStr)
+	}
+	path := filepath.ToSlash(filepath.Join(alias, clnt.GetURL().Path))
+	return cmpObject{
+		url:     urlStr,
+		alias:   alias,
+		urlStr:  urlStrFull,
+		opts:    GetOptions{SSE: getSSE(path, encKeyDB[alias])},
+		content: content,
+		clnt:    clnt,
+	}, nil
+}
+
+// compareCmpSums compares two objects of the same size by their ETags
+// or checksums when they are enough, and by hashing them otherwise.
+func compareCmpSums(ctx context.Context, first, second cmpObject) (method string, equal bool, err *probe.Error) {
+	// ETags of encrypted objects are not MD5 sums.
+	if first.opts.SSE == nil && second.opts.SSE == nil {
+		if equal, ok := compareETags(first.content.ETag, second.content.ETag); ok {
+			return verifyMethodETag, equal, nil
+		}
+	}
+
+	firstS3, firstOk := first.clnt.(*S3Client)
+	secondS3, secondOk := second.clnt.(*S3Client)
+	if firstOk && secondOk {
+		firstSums, err := firstS3.GetObjectChecksums(ctx, "", first.opts.SSE)
+		if err == nil {
+			secondSums, err := secondS3.GetObjectChecksums(ctx, "", second.opts.SSE)
+			if err == nil {
+				if algorithm, equal, ok := compareChecksums(firstSums, secondSums); ok {
+					return strings.ToLower(algorithm), equal, nil
+				}
+			}
+		}
+	}
+
+	firstSum, err := hashObject(ctx, first.alias, first.urlStr, first.opts)
+	if err != nil {
+		return "", false, err.Trace(first.url)
+	}
+	secondSum, err := hashObject(ctx, second.alias, second.urlStr, second.opts)
+	if err != nil {
+		return "", false, err.Trace(second.url)
+	}
+	return verifyMethodHash, string(firstSum) == string(secondSum), nil
+}
+
+// compareCmpBytes compares two objects byte by byte from offset, over
+// length bytes or up to their end when length is zero.
+func compareCmpBytes(ctx context.Context, first, second cmpObject, offset, length int64) (*cmpDifference, *probe.Error) {
+	open := func(o cmpObject) (io.ReadCloser, *probe.Error) {
+		opts := o.opts
+		opts.RangeStart = offset
+		if length > 0 {
+			opts.RangeEnd = offset + length - 1
+			opts.HasRangeEnd = true
+		}
+		if offset >= o.content.Size {
+			// Nothing left to read past the end of the object.
+			return io.NopCloser(strings.NewReader("")), nil
+		}
+		reader, _, err := getSourceStream(ctx, o.alias, o.urlStr, getSourceOpts{GetOptions: opts})
+		return reader, err
+	}
+
+	firstReader, err := open(first)
+	if err != nil {
+		return nil, err.Trace(first.url)
+	}
+	defer firstReader.Close()
+	secondReader, err := open(second)
+	if err != nil {
+		return nil, err.Trace(second.url)
+	}
+	defer secondReader.Close()
+
+	d, e := compareReaders(firstReader, secondReader, offset)
+	return d, probe.NewError(e)
+}
+
+func mainCmp(cliCtx *cli.Context) error {
+	ctx, cancelCmp := context.WithCancel(globalContext)
+	defer cancelCmp()
+
+	console.SetColor("CmpEqual", color.New(color.FgGreen))
+	console.SetColor("CmpDiffer", color.New(color.FgRed, color.Bold))
+
+	if len(cliCtx.Args()) != 2 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	byteCompare := cliCtx.Bool("bytes")
+	offset := cliCtx.Int64("offset")
+	length := cliCtx.Int64("length")
+	if offset < 0 || length < 0 {
+		fatalIf(errInvalidArgument().Trace(cliCtx.String("offset"), cliCtx.String("length")), "--offset and --length cannot be negative.")
+	}
+	if (cliCtx.IsSet("offset") || cliCtx.IsSet("length")) && !byteCompare {
+		fatalIf(errInvalidArgument().Trace(), "--offset and --length require --bytes.")
+	}
+
+	encKeyDB, err := getEncKeys(cliCtx)
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	first, err := statCmpObject(ctx, cliCtx.Args().Get(0), encKeyDB)
+	fatalIf(err, "Unable to stat the first object.")
+	second, err := statCmpObject(ctx, cliCtx.Args().Get(1), encKeyDB)
+	fatalIf(err, "Unable to stat the second object.")
+
+	msg := cmpMessage{
+		First:      first.url,
+		Second:     second.url,
+		FirstSize:  first.content.Size,
+		SecondSize: second.content.Size,
+		Method:     cmpMethodSize,
+	}
+	if first.content.Size == second.content.Size {
+		msg.Method, msg.Equal, err = compareCmpSums(ctx, first, second)
+		fatalIf(err, "Unable to compare the objects.")
+	}
+	if byteCompare && !msg.Equal {
+		d, err := compareCmpBytes(ctx, first, second, offset, length)
+		fatalIf(err, "Unable to compare the objects byte by byte.")
+		switch {
+		case d != nil:
+			msg.Method = cmpMethodBytes
+			msg.Difference = d
+		case offset > 0 || length > 0:
+			// The objects differ outside of the compared range.
+			msg.Range = fmt.Sprintf("%d-", offset)
+			if length > 0 {
+				msg.Range += fmt.Sprint(offset + length - 1)
+			}
+		default:
+			msg.Method = cmpMethodBytes
+			msg.Equal = true
+		}
+	}
+
+	printMsg(msg)
+	if !msg.Equal {
+		return exitStatus(globalErrorExitStatus)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompareReaders(t *testing.T) {
+	large := bytes.Repeat([]byte("0123456789"), 10000)
+	changed := append([]byte{}, large...)
+	changed[70000] = 'x'
+
+	testCases := []struct {
+		first, second []byte
+		offset        int64
+		expected      *cmpDifference
+	}{
+		{[]byte(""), []byte(""), 0, nil},
+		{[]byte("abc"), []byte("abc"), 0, nil},
+		{[]byte("abc"), []byte("abd"), 0, &cmpDifference{Offset: 2, First: 'c', Second: 'd'}},
+		{[]byte("abc"), []byte("abd"), 100, &cmpDifference{Offset: 102, First: 'c', Second: 'd'}},
+		{[]byte("ab"), []byte("abc"), 0, &cmpDifference{Offset: 2, First: -1, Second: 'c'}},
+		{[]byte("abc"), []byte(""), 0, &cmpDifference{Offset: 0, First: 'a', Second: -1}},
+		{large, large, 0, nil},
+		{large, changed, 0, &cmpDifference{Offset: 70000, First: '0', Second: 'x'}},
+		{large, large[:65536], 0, &cmpDifference{Offset: 65536, First: int(large[65536]), Second: -1}},
+	}
+	for i, testCase := range testCases {
+		d, e := compareReaders(bytes.NewReader(testCase.first), bytes.NewReader(testCase.second), testCase.offset)
+		if e != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, e)
+		}
+		if !reflect.DeepEqual(d, testCase.expected) {
+			t.Errorf("Test %d: expected %+v, got %+v", i+1, testCase.expected, d)
+		}
+	}
+
+	// Read errors are returned.
+	if _, e := compareReaders(strings.NewReader("abc"), errReader{}, 0); e == nil {
+		t.Errorf("expected a read error")
+	}
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) { return 0, errors.New("read failure") }
+
+func TestCompareChecksums(t *testing.T) {
+	testCases := []struct {
+		first, second map[string]string
+		algorithm     string
+		equal, ok     bool
+	}{
+		{map[string]string{"CRC32C": "a"}, map[string]string{"CRC32C": "a"}, "CRC32C", true, true},
+		{map[string]string{"CRC32C": "a"}, map[string]string{"CRC32C": "b"}, "CRC32C", false, true},
+		{map[string]string{"CRC32C": "a", "SHA256": "x"}, map[string]string{"CRC32C": "a", "SHA256": "y"}, "SHA256", false, true},
+		{map[string]string{"CRC32C": "a"}, map[string]string{"SHA256": "a"}, "", false, false},
+		// Multipart checksums only tell when they are equal.
+		{map[string]string{"CRC32C": "a-2"}, map[string]string{"CRC32C": "a-2"}, "CRC32C", true, true},
+		{map[string]string{"CRC32C": "a-2"}, map[string]string{"CRC32C": "b-3"}, "", false, false},
+		{map[string]string{"CRC32C": "a-2"}, map[string]string{"CRC32C": "b"}, "", false, false},
+		{nil, nil, "", false, false},
+	}
+	for i, testCase := range testCases {
+		algorithm, equal, ok := compareChecksums(testCase.first, testCase.second)
+		if algorithm != testCase.algorithm || equal != testCase.equal || ok != testCase.ok {
+			t.Errorf("Test %d: expected (%s, %v, %v), got (%s, %v, %v)", i+1,
+				testCase.algorithm, testCase.equal, testCase.ok, algorithm, equal, ok)
+		}
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -102,6 +103,26 @@ func getEncKeys(ctx *cli.Context) (map[string][]prefixSSEPair, *probe.Error) {
 		return nil, err.Trace(sseKeys)
 	}
 
+	keyFile := os.Getenv("MC_ENCRYPT_KEY_FILE")
+	if f := ctx.String("encrypt-key-file"); f != "" {
+		keyFile = f
+	}
+	if keyFile != "" {
+		fileKeyDB, err := parseEncryptionKeyFile(keyFile)
+		if err != nil {
+			return nil, err
+		}
+		for alias, ps := range fileKeyDB {
+			if hostCfg := mustGetHostConfig(alias); hostCfg == nil {
+				return nil, probe.NewError(errors.New("SSE prefix " + ps[0].Prefix + " has invalid alias")).Trace(keyFile)
+			}
+			// Keys passed on the command line take precedence
+			// over the keys of the file for the same prefix.
+			encKeyDB[alias] = append(encKeyDB[alias], ps...)
+			sort.Stable(byPrefixLength(encKeyDB[alias]))
+		}
+	}
+
 	return encKeyDB, nil
 }
 
@@ -343,6 +364,54 @@ func putTargetStream(ctx context.Context, alias, urlStr, mode, until, legalHold
 	return n, nil
 }
 
+// checkTargetNotExists returns ObjectAlreadyExists when an object is
+// already present at urlStr. This is a HEAD before the upload, a
+// concurrent writer may still create the object in between: uploads
+// with PutOptions.ifNotExists also send 'If-None-Match: *', which
+// closes that window on servers supporting conditional writes.
+func checkTargetNotExists(ctx context.Context, alias, urlStr string, sse encrypt.ServerSide) *probe.Error {
+	targetClnt, err := newClientFromAlias(alias, urlStr)
+	if err != nil {
+		return err.Trace(alias, urlStr)
+	}
+	st, err := targetClnt.Stat(ctx, StatOptions{sse: sse})
+	if err != nil {
+		switch err.ToGoError().(type) {
+		case ObjectMissing, PathNotFound:
+			return nil
+		}
+		return err.Trace(alias, urlStr)
+	}
+	if st.Type.IsDir() {
+		// A prefix of the same name is not an object.
+		return nil
+	}
+	return probe.NewError(ObjectAlreadyExists{Object: urlStr})
+}
+
+// statWithSSEs stats an existing object with the first of the SSE keys
+// of getReadSSEs it is encrypted with, returned along with its stat.
+func statWithSSEs(ctx context.Context, clnt Client, opts StatOptions, sses []encrypt.ServerSide) (content *ClientContent, sse encrypt.ServerSide, err *probe.Error) {
+	for _, sse = range sses {
+		opts.sse = sse
+		content, err = clnt.Stat(ctx, opts)
+		if err == nil {
+			return content, sse, nil
+		}
+		switch err.ToGoError().(type) {
+		case ObjectMissing, PathNotFound:
+			return nil, nil, err
+		}
+	}
+	return nil, nil, err
+}
+
+// isErrTargetExists returns true if err reports an existing target object.
+func isErrTargetExists(err *probe.Error) bool {
+	_, ok := err.ToGoError().(ObjectAlreadyExists)
+	return ok
+}
+
 // putTargetStreamWithURL writes to URL from reader. If length=-1, read until EOF.
 func putTargetStreamWithURL(urlStr string, reader io.Reader, size int64, opts PutOptions) (int64, *probe.Error) {
 	alias, urlStrFull, _, err := expandAlias(urlStr)
@@ -418,8 +487,8 @@ func getAllMetadata(ctx context.Context, sourceAlias, sourceURLStr string, srcSS
 }
 
 // uploadSourceToTargetURL - uploads to targetURL from source.
-// optionally optimizes copy for object sizes <= 5GiB by using
-// server side copy operation.
+// optionally optimizes copy by using server side copy operation,
+// multipart for objects larger than 5GiB.
 func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader, encKeyDB map[string][]prefixSSEPair, preserve, isZip bool) URLs {
 	sourceAlias := urls.SourceAlias
 	sourceURL := urls.SourceContent.URL
@@ -431,7 +500,17 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 	targetPath := filepath.ToSlash(filepath.Join(targetAlias, urls.TargetContent.URL.Path))
 
 	srcSSE := getSSE(sourcePath, encKeyDB[sourceAlias])
-	tgtSSE := getSSE(targetPath, encKeyDB[targetAlias])
+	tgtSSE := getTargetSSE(targetPath, encKeyDB[targetAlias])
+
+	if urls.IfNotExists {
+		if err := checkTargetNotExists(ctx, targetAlias, targetURL.String(), tgtSSE); err != nil {
+			if isErrTargetExists(err) {
+				urls.skipped = true
+				return urls.WithError(nil)
+			}
+			return urls.WithError(err.Trace(targetURL.String()))
+		}
+	}
 
 	var err *probe.Error
 	metadata := map[string]string{}
@@ -481,8 +560,9 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 		metadata[http.CanonicalHeaderKey(k)] = v
 	}
 
-	// Optimize for server side copy if the host is same.
-	if sourceAlias == targetAlias && !isZip {
+	// Optimize for server side copy if the host is same, unless
+	// the data has to be encrypted on the client.
+	if isServerSideCopy(urls, isZip) {
 		// preserve new metadata and save existing ones.
 		if preserve {
 			currentMetadata, err := getAllMetadata(ctx, sourceAlias, sourceURL.String(), srcSSE, urls)
@@ -517,10 +597,13 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 			disableMultipart: urls.DisableMultipart,
 			isPreserve:       preserve,
 			storageClass:     urls.TargetContent.StorageClass,
+			partSize:         int64(urls.MultipartSize),
+			parallelParts:    int(urls.MultipartThreads),
 		}
 
 		err = copySourceToTargetURL(ctx, targetAlias, targetURL.String(), sourcePath, sourceVersion, mode, until,
 			legalHold, length, progress, opts)
+		urls.serverSideCopy = targetAlias != ""
 	} else {
 		if urls.SourceContent.RetentionEnabled {
 			// preserve new metadata and save existing ones.
@@ -548,6 +631,14 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 			return urls.WithError(err.Trace(sourceURL.String()))
 		}
 
+		// Large objects downloaded to the local filesystem are fetched as parallel ranges.
+		if canDownloadRanges(urls, preserve, isZip) {
+			if err = downloadRangesToFS(ctx, urls, srcSSE, progress); err != nil {
+				return urls.WithError(err.Trace(sourceURL.String()))
+			}
+			return urls.WithError(nil)
+		}
+
 		var reader io.ReadCloser
 		// Proceed with regular stream copy.
 		reader, metadata, err = getSourceStream(ctx, sourceAlias, sourceURL.String(), getSourceOpts{
@@ -564,6 +655,14 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 		}
 		defer reader.Close()
 
+		// The filesystem has no place for tags, they are lost.
+		if preserve && targetURL.Type == fileSystem {
+			if count := metadata["X-Amz-Tagging-Count"]; count != "" && count != "0" {
+				warningIf(warningDowngraded, errTagsNotPreserved().Trace(sourceURL.String()),
+					"Tags of `%s` are not preserved on `%s`.", sourceURL.String(), targetURL.String())
+			}
+		}
+
 		// Get metadata from target content as well
 		for k, v := range urls.TargetContent.Metadata {
 			metadata[http.CanonicalHeaderKey(k)] = v
@@ -575,17 +674,20 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 		}
 
 		var e error
-		var multipartSize uint64
-		if v := env.Get("MC_UPLOAD_MULTIPART_SIZE", ""); v != "" {
+		multipartSize := urls.MultipartSize
+		if v := env.Get("MC_UPLOAD_MULTIPART_SIZE", ""); v != "" && multipartSize == 0 {
 			multipartSize, e = humanize.ParseBytes(v)
 			if e != nil {
 				return urls.WithError(probe.NewError(e))
 			}
 		}
 
-		multipartThreads, e := strconv.Atoi(env.Get("MC_UPLOAD_MULTIPART_THREADS", "4"))
-		if e != nil {
-			return urls.WithError(probe.NewError(e))
+		multipartThreads := int(urls.MultipartThreads)
+		if multipartThreads == 0 {
+			multipartThreads, e = strconv.Atoi(env.Get("MC_UPLOAD_MULTIPART_THREADS", "4"))
+			if e != nil {
+				return urls.WithError(probe.NewError(e))
+			}
 		}
 
 		putOpts := PutOptions{
@@ -597,9 +699,24 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 			isPreserve:       preserve,
 			multipartSize:    multipartSize,
 			multipartThreads: uint(multipartThreads),
+			ifNotExists:      urls.IfNotExists,
 		}
 
-		if isReadAt(reader) {
+		if urls.cseKey != nil {
+			var (
+				encrypted   io.Reader
+				cseMetadata map[string]string
+			)
+			encrypted, length, cseMetadata, err = cseEncrypt(reader, length, urls.cseKey)
+			if err != nil {
+				return urls.WithError(err.Trace(sourceURL.String()))
+			}
+			for k, v := range cseMetadata {
+				putOpts.metadata[k] = v
+			}
+			_, err = putTargetStream(ctx, targetAlias, targetURL.String(), mode, until,
+				legalHold, encrypted, length, progress, putOpts)
+		} else if isReadAt(reader) {
 			_, err = putTargetStream(ctx, targetAlias, targetURL.String(), mode, until,
 				legalHold, reader, length, progress, putOpts)
 		} else {
@@ -607,6 +724,11 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 				legalHold, io.LimitReader(reader, length), length, progress, putOpts)
 		}
 	}
+	if err != nil && urls.IfNotExists && isErrTargetExists(err) {
+		// Created by a concurrent writer since checkTargetNotExists.
+		urls.skipped = true
+		return urls.WithError(nil)
+	}
 	if err != nil {
 		return urls.WithError(err.Trace(sourceURL.String()))
 	}
@@ -614,6 +736,54 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 	return urls.WithError(nil)
 }
 
+// Bounds of the part size of a multipart upload.
+const (
+	absMinPartSize = 5 * humanize.MiByte
+	maxPartSize    = 5 * humanize.GiByte
+)
+
+// parseMultipartFlags returns the part size and the number of parallel
+// parts passed with --part-size and --parallel-parts, zero when unset.
+func parseMultipartFlags(cliCtx *cli.Context) (uint64, uint, *probe.Error) {
+	var partSize uint64
+	if v := cliCtx.String("part-size"); v != "" {
+		var e error
+		partSize, e = humanize.ParseBytes(v)
+		if e != nil {
+			return 0, 0, probe.NewError(e).Trace(v)
+		}
+		if partSize < absMinPartSize || partSize > maxPartSize {
+			return 0, 0, probe.NewError(errors.New("part size should be between 5MiB and 5GiB")).Trace(v)
+		}
+	}
+	n := cliCtx.Int("parallel-parts")
+	if n < 0 {
+		return 0, 0, errInvalidArgument().Trace(strconv.Itoa(n))
+	}
+	return partSize, uint(n), nil
+}
+
+// parseRangedDownloadFlags returns the number of byte ranges downloaded
+// in parallel and the size of each range, validating the values.
+func parseRangedDownloadFlags(cliCtx *cli.Context) (uint, int64, *probe.Error) {
+	n := cliCtx.Int("parallel-ranges")
+	if n < 0 {
+		return 0, 0, errInvalidArgument().Trace(strconv.Itoa(n))
+	}
+	rangeSize := uint64(defaultRangeSize)
+	if v := cliCtx.String("range-size"); v != "" {
+		var e error
+		rangeSize, e = humanize.ParseBytes(v)
+		if e != nil {
+			return 0, 0, probe.NewError(e).Trace(v)
+		}
+		if rangeSize < 1<<20 {
+			return 0, 0, probe.NewError(errors.New("range size should be at least 1MiB")).Trace(v)
+		}
+	}
+	return uint(n), int64(rangeSize), nil
+}
+
 // newClientFromAlias gives a new client interface for matching
 // alias entry in the mc config file. If no matching host config entry
 // is found, fs client is returned.
@@ -26,18 +26,19 @@ var configCmd = cli.Command{
 		cli.ShowCommandHelp(ctx, ctx.Args().First())
 		return nil
 	},
-	Hidden:          true,
 	Before:          setGlobalsFromContext,
 	HideHelpCommand: true,
 	Flags:           globalFlags,
 	Subcommands: []cli.Command{
 		configHostCmd,
+		configValidateCmd,
 	},
 }
 
 var configHostCmd = cli.Command{
-	Name:  "host",
-	Usage: "add, remove and list hosts in configuration file",
+	Name:   "host",
+	Usage:  "add, remove and list hosts in configuration file",
+	Hidden: true, // deprecated, use 'mc alias' instead
 	Action: func(ctx *cli.Context) error {
 		cli.ShowCommandHelp(ctx, ctx.Args().First())
 		return nil
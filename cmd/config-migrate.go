@@ -19,6 +19,7 @@ package cmd
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/trinet2005/oss-mc/pkg/probe"
@@ -26,28 +27,62 @@ import (
 	"github.com/trinet2005/oss-pkg/quick"
 )
 
+// configMigration upgrades a config file from one version to the next.
+type configMigration struct {
+	from, to string
+	migrate  func()
+}
+
+// configMigrations lists every config migration in order, each
+// one starting from the version the previous one produces.
+var configMigrations = []configMigration{
+	{"1.0.0", "1.0.1", migrateConfigV1ToV101},
+	{"1.0.1", "2", migrateConfigV101ToV2},
+	{"2", "3", migrateConfigV2ToV3},
+	{"3", "4", migrateConfigV3ToV4},
+	{"4", "5", migrateConfigV4ToV5},
+	{"5", "6", migrateConfigV5ToV6},
+	{"6", "7", migrateConfigV6ToV7},
+	{"7", "8", migrateConfigV7ToV8},
+	{"8", "9", migrateConfigV8ToV9},
+	{"9", "10", migrateConfigV9ToV10},
+}
+
+// configMigrationPath returns the migrations upgrading a config of the
+// given version to the current version, failing for unknown versions.
+func configMigrationPath(version string) ([]configMigration, *probe.Error) {
+	if version == globalMCConfigVersion {
+		return nil, nil
+	}
+	for i, m := range configMigrations {
+		if m.from == version {
+			return configMigrations[i:], nil
+		}
+	}
+	if current, e := strconv.Atoi(globalMCConfigVersion); e == nil {
+		if v, e := strconv.Atoi(version); e == nil && v > current {
+			return nil, probe.NewError(fmt.Errorf("config version `%s` is newer than mc config version `%s`, please update your binary", version, globalMCConfigVersion))
+		}
+	}
+	return nil, probe.NewError(fmt.Errorf("unknown config version `%s`, expected `%s` or one of the older versions %s to %s",
+		version, globalMCConfigVersion, configMigrations[0].from, configMigrations[len(configMigrations)-1].from))
+}
+
 // migrate config files from the any older version to the latest.
 func migrateConfig() {
-	// Migrate config V1 to V101
-	migrateConfigV1ToV101()
-	// Migrate config V101 to V2
-	migrateConfigV101ToV2()
-	// Migrate config V2 to V3
-	migrateConfigV2ToV3()
-	// Migrate config V3 to V4
-	migrateConfigV3ToV4()
-	// Migrate config V4 to V5
-	migrateConfigV4ToV5()
-	// Migrate config V5 to V6
-	migrateConfigV5ToV6()
-	// Migrate config V6 to V7
-	migrateConfigV6ToV7()
-	// Migrate config V7 to V8
-	migrateConfigV7ToV8()
-	// Migrate config V8 to V9
-	migrateConfigV8ToV9()
-	// Migrate config V9 to V10
-	migrateConfigV9ToV10()
+	if !isMcConfigExists() {
+		return
+	}
+
+	anyCfg, e := quick.LoadConfig(mustGetMcConfigPath(), nil, &ConfigAnyVersion{})
+	fatalIf(probe.NewError(e), "Unable to load config version.")
+
+	migrations, err := configMigrationPath(anyCfg.Version())
+	fatalIf(err.Trace(mustGetMcConfigPath()), "Unable to migrate configuration file.")
+
+	for _, m := range migrations {
+		m.migrate()
+	}
 }
 
 // Migrate from config version 1.0 to 1.0.1. Populate example entries and save it back.
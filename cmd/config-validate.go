@@ -18,49 +18,357 @@
 package cmd
 
 import (
+	"bytes"
+	gojson "encoding/json"
 	"fmt"
+	"os"
+	"reflect"
+	"sort"
 	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
 )
 
-// Check if version of the config is valid
-func validateConfigVersion(config *configV10) (bool, string) {
+var configValidateCmd = cli.Command{
+	Name:            "validate",
+	Usage:           "validate the configuration file",
+	Action:          mainConfigValidate,
+	OnUsageError:    onUsageError,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] [FILE]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Check the configuration file, by default the one of the config directory,
+  without migrating or rewriting it. Every problem is reported with the
+  field it is found in and, for parse errors, its line and column. A file
+  of an older version is reported with the migrations applied on the next
+  run of any other command.
+
+EXAMPLES:
+  1. Validate the configuration file after editing it by hand.
+     {{.Prompt}} {{.HelpName}}
+
+  2. Validate a configuration file before copying it to another machine.
+     {{.Prompt}} {{.HelpName}} /tmp/config.json
+`,
+}
+
+// configError is a problem found in a configuration file, located by
+// the path of its field and, for parse errors, its line and column.
+type configError struct {
+	Field   string `json:"field,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// Error returns the user facing description of the problem.
+func (e configError) Error() string {
+	var msg string
+	if e.Line > 0 {
+		msg = fmt.Sprintf("line %d, column %d: ", e.Line, e.Column)
+	}
+	if e.Field != "" {
+		msg += e.Field + ": "
+	}
+	return msg + e.Message
+}
+
+// configPosition returns the line and column of the byte read last
+// when a JSON decoding error occurred after offset bytes.
+func configPosition(data []byte, offset int64) (line, column int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	column = len(before) - bytes.LastIndexByte(before, '\n') - 1
+	if column < 1 {
+		column = 1
+	}
+	return line, column
+}
+
+// configDecodeError locates a JSON decoding error in a configuration file.
+func configDecodeError(data []byte, e error) configError {
+	switch err := e.(type) {
+	case *gojson.SyntaxError:
+		line, column := configPosition(data, err.Offset)
+		return configError{Line: line, Column: column, Message: err.Error()}
+	case *gojson.UnmarshalTypeError:
+		line, column := configPosition(data, err.Offset)
+		return configError{
+			Field:   err.Field,
+			Line:    line,
+			Column:  column,
+			Message: fmt.Sprintf("expected %s, found %s", configTypeName(err.Type), err.Value),
+		}
+	}
+	return configError{Message: e.Error()}
+}
+
+// configTypeName returns the JSON name of a Go type.
+func configTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map:
+		return "object"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Bool:
+		return "bool"
+	case reflect.String:
+		return "string"
+	}
+	return "number"
+}
+
+// parseConfigVersion returns the version of a configuration file.
+func parseConfigVersion(data []byte) (string, *configError) {
+	var cfg struct {
+		Version gojson.RawMessage `json:"version"`
+	}
+	if e := gojson.Unmarshal(data, &cfg); e != nil {
+		err := configDecodeError(data, e)
+		return "", &err
+	}
+	if len(cfg.Version) == 0 {
+		return "", &configError{Field: "version", Message: "missing, expected `" + globalMCConfigVersion + "`"}
+	}
+	var version string
+	if e := gojson.Unmarshal(cfg.Version, &version); e != nil {
+		return "", &configError{Field: "version", Message: "expected a string such as `" + globalMCConfigVersion + "`, found " + string(cfg.Version)}
+	}
+	return version, nil
+}
+
+// configFieldNames returns the JSON names of the fields of a struct.
+func configFieldNames(v interface{}) []string {
+	var names []string
+	t := reflect.TypeOf(v)
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		names = append(names, name)
+	}
+	return names
+}
+
+// unknownConfigFields returns the keys of a JSON object which match none
+// of the known names, compared case insensitively as the decoder does.
+func unknownConfigFields(prefix string, object map[string]gojson.RawMessage, known []string) (errs []configError) {
+	for key := range object {
+		var found bool
+		for _, name := range known {
+			if strings.EqualFold(key, name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, configError{
+				Field:   prefix + key,
+				Message: "unknown field, expected one of " + strings.Join(known, ", "),
+			})
+		}
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+	return errs
+}
+
+// validateConfigData validates the content of a configuration file of
+// the current version, including the fields unknown to this version.
+func validateConfigData(data []byte) []configError {
+	var object map[string]gojson.RawMessage
+	if e := gojson.Unmarshal(data, &object); e != nil {
+		return []configError{configDecodeError(data, e)}
+	}
+	errs := unknownConfigFields("", object, configFieldNames(configV10{}))
+
+	cfg := new(configV10)
+	if e := gojson.Unmarshal(data, cfg); e != nil {
+		return append(errs, configDecodeError(data, e))
+	}
+
+	var aliases map[string]map[string]gojson.RawMessage
+	if e := gojson.Unmarshal(object["aliases"], &aliases); e == nil {
+		for _, alias := range sortedAliases(cfg) {
+			errs = append(errs, unknownConfigFields("aliases."+alias+".", aliases[alias], configFieldNames(aliasConfigV10{}))...)
+		}
+	}
+	return append(errs, validateConfig(cfg)...)
+}
+
+// sortedAliases returns the aliases of a config in order.
+func sortedAliases(config *configV10) []string {
+	aliases := make([]string, 0, len(config.Aliases))
+	for alias := range config.Aliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// validateConfig returns the problems of a decoded config.
+func validateConfig(config *configV10) (errs []configError) {
 	if config.Version != globalMCConfigVersion {
-		return false, fmt.Sprintf("Config version '%s' does not match mc config version '%s', please update your binary.\n",
-			config.Version, globalMCConfigVersion)
+		errs = append(errs, configError{
+			Field: "version",
+			Message: fmt.Sprintf("config version `%s` does not match mc config version `%s`, please update your binary",
+				config.Version, globalMCConfigVersion),
+		})
+	}
+	for _, alias := range sortedAliases(config) {
+		errs = append(errs, validateConfigAlias(alias, config.Aliases[alias])...)
 	}
-	return true, ""
+	return errs
+}
+
+// validateConfigAlias returns the problems of the configuration of an alias.
+func validateConfigAlias(alias string, host aliasConfigV10) (errs []configError) {
+	field := "aliases." + alias
+	if !isValidAlias(alias) {
+		errs = append(errs, configError{Field: field, Message: "invalid alias name, it must start with a letter followed by letters, digits, '-' or '_'"})
+	}
+	if !isValidHostURL(host.URL) {
+		errs = append(errs, configError{Field: field + ".url", Message: fmt.Sprintf("invalid URL `%s`, expected http(s)://host[:port] without a path", host.URL)})
+	}
+	if !isValidAPI(host.API) {
+		errs = append(errs, configError{Field: field + ".api", Message: fmt.Sprintf("invalid API signature `%s`, expected one of %s", host.API, strings.Join(validAPIs, ", "))})
+	}
+	// An empty path is auto, lookup values were written by the defaults of older versions.
+	if host.Path != "" && !isValidPath(host.Path) && !isValidLookup(host.Path) {
+		errs = append(errs, configError{Field: field + ".path", Message: fmt.Sprintf("invalid bucket lookup `%s`, expected one of on, off, auto", host.Path)})
+	}
+	if !isValidAccessKey(host.AccessKey) {
+		errs = append(errs, configError{Field: field + ".accessKey", Message: fmt.Sprintf("too short, expected at least %d characters", accessKeyMinLen)})
+	}
+	if !isValidSecretKey(host.SecretKey) {
+		errs = append(errs, configError{Field: field + ".secretKey", Message: fmt.Sprintf("too short, expected at least %d characters", secretKeyMinLen)})
+	}
+	if (host.AccessKey == "") != (host.SecretKey == "") {
+		errs = append(errs, configError{Field: field, Message: "accessKey and secretKey must be both set, or both empty for anonymous access"})
+	}
+	return errs
 }
 
 // Verifies the config file of the MinIO Client
 func validateConfigFile(config *configV10) (bool, []string) {
-	ok, err := validateConfigVersion(config)
-	validationSuccessful := true
-	var errors []string
-	if !ok {
-		validationSuccessful = false
-		errors = append(errors, err)
-	}
-	aliases := config.Aliases
-	for _, aliasConfig := range aliases {
-		aliasConfigHealthOk, aliasErrors := validateConfigHost(aliasConfig)
-		if !aliasConfigHealthOk {
-			validationSuccessful = false
-			errors = append(errors, aliasErrors...)
+	errs := validateConfig(config)
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return len(errs) == 0, messages
+}
+
+// checkConfigSyntax reports a malformed configuration file by the
+// position of the error, before any attempt to migrate or load it.
+func checkConfigSyntax() {
+	if !isMcConfigExists() {
+		return
+	}
+	data, e := os.ReadFile(mustGetMcConfigPath())
+	fatalIf(probe.NewError(e), "Unable to read configuration file.")
+	if _, err := parseConfigVersion(data); err != nil {
+		fatalIf(errDummy().Trace(mustGetMcConfigPath()), "Unable to parse configuration file `"+mustGetMcConfigPath()+"`: "+err.Error()+". Check it with `mc config validate`.")
+	}
+}
+
+// configValidateMessage is the report of 'mc config validate'.
+type configValidateMessage struct {
+	Status     string        `json:"status"`
+	File       string        `json:"file"`
+	Version    string        `json:"version,omitempty"`
+	Migrations []string      `json:"migrations,omitempty"`
+	Errors     []configError `json:"errors,omitempty"`
+}
+
+// String colorized config validate message
+func (m configValidateMessage) String() string {
+	var b strings.Builder
+	switch {
+	case len(m.Errors) > 0:
+		b.WriteString(console.Colorize("ConfigInvalid", fmt.Sprintf("`%s` has %d error(s):", m.File, len(m.Errors))))
+		for _, err := range m.Errors {
+			b.WriteString("\n  " + err.Error())
 		}
+	case len(m.Migrations) > 0:
+		b.WriteString(console.Colorize("ConfigValid", fmt.Sprintf("`%s` is a valid config of version `%s`, it is migrated by the next command: %s.",
+			m.File, m.Version, strings.Join(m.Migrations, ", "))))
+	default:
+		b.WriteString(console.Colorize("ConfigValid", fmt.Sprintf("`%s` is a valid config of version `%s`.", m.File, m.Version)))
 	}
-	return validationSuccessful, errors
+	return b.String()
 }
 
-func validateConfigHost(host aliasConfigV10) (bool, []string) {
-	validationSuccessful := true
-	var hostErrors []string
-	if !isValidAPI(strings.ToLower(host.API)) {
-		validationSuccessful = false
-		hostErrors = append(hostErrors, errInvalidAPISignature(host.API, host.URL).ToGoError().Error())
+// JSON jsonified config validate message
+func (m configValidateMessage) JSON() string {
+	m.Status = "success"
+	if len(m.Errors) > 0 {
+		m.Status = "error"
 	}
-	if !isValidHostURL(host.URL) {
-		validationSuccessful = false
-		hostErrors = append(hostErrors, errInvalidURL(host.URL).ToGoError().Error())
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(msgBytes)
+}
+
+// validateConfigFileAt validates a configuration file of any version.
+func validateConfigFileAt(file string, data []byte) configValidateMessage {
+	msg := configValidateMessage{File: file}
+	version, err := parseConfigVersion(data)
+	if err != nil {
+		msg.Errors = []configError{*err}
+		return msg
+	}
+	msg.Version = version
+	if version == globalMCConfigVersion {
+		msg.Errors = validateConfigData(data)
+		return msg
+	}
+	migrations, perr := configMigrationPath(version)
+	if perr != nil {
+		msg.Errors = []configError{{Field: "version", Message: perr.ToGoError().Error()}}
+		return msg
+	}
+	for _, m := range migrations {
+		msg.Migrations = append(msg.Migrations, m.from+" -> "+m.to)
+	}
+	return msg
+}
+
+// mainConfigValidate is the handle for "mc config validate" command.
+func mainConfigValidate(ctx *cli.Context) error {
+	console.SetColor("ConfigValid", color.New(color.FgGreen, color.Bold))
+	console.SetColor("ConfigInvalid", color.New(color.FgRed, color.Bold))
+
+	if len(ctx.Args()) > 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	file := ctx.Args().First()
+	if file == "" {
+		file = mustGetMcConfigPath()
+	}
+
+	data, e := os.ReadFile(file)
+	fatalIf(probe.NewError(e).Trace(file), "Unable to read configuration file.")
+
+	msg := validateConfigFileAt(file, data)
+	printMsg(msg)
+	if len(msg.Errors) > 0 {
+		return exitStatus(globalErrorExitStatus)
 	}
-	return validationSuccessful, hostErrors
+	return nil
 }
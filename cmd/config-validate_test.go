@@ -0,0 +1,127 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConfigMigrationPath(t *testing.T) {
+	testCases := []struct {
+		version    string
+		migrations []string
+		success    bool
+	}{
+		{globalMCConfigVersion, nil, true},
+		{"9", []string{"9"}, true},
+		{"1.0.1", []string{"1.0.1", "2", "3", "4", "5", "6", "7", "8", "9"}, true},
+		{"11", nil, false},
+		{"10.1", nil, false},
+		{"", nil, false},
+	}
+
+	for i, testCase := range testCases {
+		migrations, err := configMigrationPath(testCase.version)
+		if (err == nil) != testCase.success {
+			t.Fatalf("Test %d: expected success %t, got %v", i+1, testCase.success, err)
+		}
+		var from []string
+		for _, m := range migrations {
+			from = append(from, m.from)
+		}
+		if !reflect.DeepEqual(from, testCase.migrations) {
+			t.Fatalf("Test %d: expected migrations from %v, got %v", i+1, testCase.migrations, from)
+		}
+	}
+}
+
+func TestValidateConfigFileAt(t *testing.T) {
+	testCases := []struct {
+		data       string
+		version    string
+		migrations []string
+		errors     []string
+	}{
+		{
+			data:    `{"version": "10", "aliases": {"play": {"url": "https://play.min.io", "accessKey": "Q3AM3UQ867SPQQA43P2F", "secretKey": "zuf+tfteSlswRu7BJ86wekitnifILbZam1KYY3TG", "api": "S3v4", "path": "auto"}}}`,
+			version: "10",
+		},
+		// Defaults written by older versions use lookup values as path.
+		{
+			data:    `{"version": "10", "aliases": {"s3": {"url": "https://s3.amazonaws.com", "accessKey": "YOUR-ACCESS-KEY-HERE", "secretKey": "YOUR-SECRET-KEY-HERE", "api": "S3v4", "path": "dns"}}}`,
+			version: "10",
+		},
+		{
+			data:       `{"version": "9", "hosts": {}}`,
+			version:    "9",
+			migrations: []string{"9 -> 10"},
+		},
+		{
+			data:   "{\n  \"version\": \"10\",\n  \"aliases\": {,}\n}",
+			errors: []string{"line 3, column 15: invalid character ',' looking for beginning of object key string"},
+		},
+		{
+			data:   `{"aliases": {}}`,
+			errors: []string{"version: missing, expected `10`"},
+		},
+		{
+			data:    `{"version": "12", "aliases": {}}`,
+			version: "12",
+			errors:  []string{"version: config version `12` is newer than mc config version `10`, please update your binary"},
+		},
+		{
+			data:    "{\"version\": \"10\",\n \"aliases\": [\"local\"]}",
+			version: "10",
+			errors:  []string{"line 2, column 13: aliases: expected object, found array"},
+		},
+		{
+			data:    `{"version": "10", "aliases": {"local": {"url": "http://localhost:9000", "secret": "minio123", "api": "S3v4", "path": "auto"}}}`,
+			version: "10",
+			errors:  []string{"aliases.local.secret: unknown field, expected one of url, accessKey, secretKey, sessionToken, api, path, license, apiKey, protected"},
+		},
+		{
+			data:    `{"version": "10", "aliases": {"1st": {"url": "localhost:9000/data", "accessKey": "minio", "api": "S3v5", "path": "virtual"}}}`,
+			version: "10",
+			errors: []string{
+				"aliases.1st: invalid alias name, it must start with a letter followed by letters, digits, '-' or '_'",
+				"aliases.1st.url: invalid URL `localhost:9000/data`, expected http(s)://host[:port] without a path",
+				"aliases.1st.api: invalid API signature `S3v5`, expected one of S3v4, S3v2",
+				"aliases.1st.path: invalid bucket lookup `virtual`, expected one of on, off, auto",
+				"aliases.1st: accessKey and secretKey must be both set, or both empty for anonymous access",
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		msg := validateConfigFileAt("config.json", []byte(testCase.data))
+		if msg.Version != testCase.version {
+			t.Fatalf("Test %d: expected version %q, got %q", i+1, testCase.version, msg.Version)
+		}
+		if !reflect.DeepEqual(msg.Migrations, testCase.migrations) {
+			t.Fatalf("Test %d: expected migrations %v, got %v", i+1, testCase.migrations, msg.Migrations)
+		}
+		var errors []string
+		for _, err := range msg.Errors {
+			errors = append(errors, err.Error())
+		}
+		if !reflect.DeepEqual(errors, testCase.errors) {
+			t.Fatalf("Test %d: expected errors %q, got %q", i+1, testCase.errors, errors)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+// Maximum number of times a broken HTTP source download is
+// resumed with a range request before the copy fails.
+const httpSourceMaxResumes = 10
+
+// isHTTPSource returns true if urlStr is an http(s) URL
+// which does not belong to any alias.
+func isHTTPSource(urlStr string) bool {
+	if !urlRgx.MatchString(urlStr) {
+		return false
+	}
+	_, _, hostCfg, err := expandAlias(urlStr)
+	return err == nil && hostCfg == nil
+}
+
+// httpSource reads the body of a HTTP(S) URL. When the connection
+// breaks and the server accepts range requests, the download is
+// resumed from the last byte read, provided the content is unchanged.
+type httpSource struct {
+	ctx    context.Context
+	client *http.Client
+	url    string
+
+	size        int64 // -1 when the server does not send Content-Length
+	contentType string
+	validator   string // ETag or Last-Modified, sent in If-Range
+	rangeable   bool
+
+	body    io.ReadCloser
+	offset  int64
+	resumes int
+}
+
+// openHTTPSource starts downloading urlStr.
+func openHTTPSource(ctx context.Context, client *http.Client, urlStr string) (*httpSource, *probe.Error) {
+	src := &httpSource{ctx: ctx, client: client, url: urlStr, size: -1}
+	resp, err := src.get(http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	src.body = resp.Body
+	src.size = resp.ContentLength
+	src.contentType = resp.Header.Get("Content-Type")
+	src.rangeable = resp.Header.Get("Accept-Ranges") == "bytes"
+	if etag := resp.Header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		src.validator = etag
+	} else {
+		src.validator = resp.Header.Get("Last-Modified")
+	}
+	return src, nil
+}
+
+// get sends a GET request for the remaining bytes of the source.
+func (s *httpSource) get(expectedStatus int) (*http.Response, *probe.Error) {
+	req, e := http.NewRequestWithContext(s.ctx, http.MethodGet, s.url, nil)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(s.url)
+	}
+	if s.offset > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(s.offset, 10)+"-")
+		req.Header.Set("If-Range", s.validator)
+	}
+	resp, e := s.client.Do(req)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(s.url)
+	}
+	if resp.StatusCode != expectedStatus {
+		resp.Body.Close()
+		if s.offset > 0 && resp.StatusCode == http.StatusOK {
+			return nil, probe.NewError(fmt.Errorf("`%s` changed while being copied", s.url))
+		}
+		return nil, probe.NewError(fmt.Errorf("unexpected response from `%s`: %s", s.url, resp.Status))
+	}
+	return resp, nil
+}
+
+// Read reads the body, resuming a broken download if possible.
+func (s *httpSource) Read(p []byte) (int, error) {
+	for {
+		n, e := s.body.Read(p)
+		s.offset += int64(n)
+		if e == io.EOF && s.size >= 0 && s.offset < s.size {
+			e = io.ErrUnexpectedEOF
+		}
+		if e == nil || e == io.EOF {
+			return n, e
+		}
+		if n > 0 {
+			// The next read returns the error again.
+			return n, nil
+		}
+		if !s.rangeable || s.validator == "" || s.resumes >= httpSourceMaxResumes || errors.Is(e, context.Canceled) {
+			return n, e
+		}
+
+		s.resumes++
+		s.body.Close()
+		select {
+		case <-s.ctx.Done():
+			return 0, s.ctx.Err()
+		case <-time.After(globalRetryPolicy.wait(s.resumes)):
+		}
+		resp, err := s.get(http.StatusPartialContent)
+		if err != nil {
+			return 0, err.ToGoError()
+		}
+		s.body = resp.Body
+	}
+}
+
+// Close closes the body of the source.
+func (s *httpSource) Close() error {
+	return s.body.Close()
+}
+
+// httpSourceName returns the object name given to the copy of urlStr
+// in a target folder, the last element of its path.
+func httpSourceName(urlStr string) (string, *probe.Error) {
+	u, e := url.Parse(urlStr)
+	if e != nil {
+		return "", probe.NewError(e).Trace(urlStr)
+	}
+	name := path.Base(u.Path)
+	if name == "." || name == "/" {
+		return "", probe.NewError(fmt.Errorf("`%s` has no file name, please specify the target object name", urlStr))
+	}
+	return name, nil
+}
+
+// copyHTTPSource streams the content of the HTTP(S) URL srcURL to
+// targetURL, with a multipart upload of unknown size when the server
+// does not tell the length of the content.
+func copyHTTPSource(ctx context.Context, client *http.Client, srcURL, targetURL string, encKeyDB map[string][]prefixSSEPair, ifNotExists bool, opts PutOptions) (copyMessage, *probe.Error) {
+	msg := copyMessage{Source: srcURL, Target: targetURL}
+
+	alias, urlStrFull, _, err := expandAlias(targetURL)
+	if err != nil {
+		return msg, err.Trace(targetURL)
+	}
+	opts.sse = getTargetSSE(targetURL, encKeyDB[alias])
+	opts.ifNotExists = ifNotExists
+	if ifNotExists {
+		if err = checkTargetNotExists(ctx, alias, urlStrFull, opts.sse); err != nil {
+			return msg, err.Trace(targetURL)
+		}
+	}
+
+	src, err := openHTTPSource(ctx, client, srcURL)
+	if err != nil {
+		return msg, err
+	}
+	defer src.Close()
+	metadata := make(map[string]string, len(opts.metadata)+1)
+	for k, v := range opts.metadata {
+		metadata[k] = v
+	}
+	metadata["Content-Type"] = src.contentType
+	if src.contentType == "" {
+		metadata["Content-Type"] = guessURLContentType(targetURL)
+	}
+	opts.metadata = metadata
+
+	var pg ProgressReader
+	if !globalQuiet && !globalJSON {
+		bar := newProgressBar(src.size)
+		bar.SetCaption(srcURL + ":")
+		pg = bar
+	} else {
+		pg = newAccounter(src.size)
+	}
+
+	if _, err = putTargetStream(ctx, alias, urlStrFull, "", "", "", src, src.size, pg, opts); err != nil {
+		return msg, err.Trace(srcURL, targetURL)
+	}
+	if bar, ok := pg.(*progressBar); ok {
+		bar.ProgressBar.Finish()
+	}
+	msg.Size = src.offset
+	return msg, nil
+}
+
+// mainCopyHTTP copies HTTP(S) URLs, which cp accepts as sources
+// besides aliases and local paths.
+func mainCopyHTTP(ctx context.Context, cliCtx *cli.Context, encKeyDB map[string][]prefixSSEPair, userMetaMap map[string]string) error {
+	args := cliCtx.Args()
+	if len(args) < 2 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code.
+	}
+	sources, target := args[:len(args)-1], args[len(args)-1]
+	for _, source := range sources {
+		if !isHTTPSource(source) {
+			fatalIf(errInvalidArgument().Trace(source), "HTTP(S) sources cannot be mixed with other sources.")
+		}
+	}
+	for _, flag := range []string{"recursive", "continue", "rewind", "version-id", "zip", "files-from", "encrypt-with", "preserve"} {
+		if cliCtx.IsSet(flag) {
+			fatalIf(errInvalidArgument().Trace(flag), "--"+flag+" is not supported with HTTP(S) sources.")
+		}
+	}
+
+	isDir := strings.HasSuffix(target, "/") || isAliasURLDir(ctx, target, encKeyDB, time.Time{})
+	if len(sources) > 1 && !isDir {
+		fatalIf(errInvalidArgument().Trace(target), "Target `"+target+"` should be a folder when copying several sources.")
+	}
+
+	partSize, parallelParts, err := parseMultipartFlags(cliCtx)
+	fatalIf(err, "Unable to parse multipart upload flags.")
+
+	metadata := map[string]string{}
+	for k, v := range userMetaMap {
+		metadata[k] = v
+	}
+	if tags := cliCtx.String("tags"); tags != "" {
+		metadata["X-Amz-Tagging"] = tags
+	}
+
+	client := httpClient(0)
+	if tr, ok := client.Transport.(*http.Transport); ok {
+		tr.TLSClientConfig.InsecureSkipVerify = globalInsecure
+		// Ranges are offsets in the content as sent by the server.
+		tr.DisableCompression = true
+	}
+
+	var retErr error
+	for _, source := range sources {
+		targetURL := target
+		if isDir {
+			name, err := httpSourceName(source)
+			if err != nil {
+				errorIf(err, "Unable to copy `"+source+"`.")
+				retErr = exitStatus(globalErrorExitStatus)
+				continue
+			}
+			targetURL = urlJoinPath(target, name)
+		}
+		msg, err := copyHTTPSource(ctx, client, source, targetURL, encKeyDB, cliCtx.Bool("if-not-exists"), PutOptions{
+			metadata:         metadata,
+			storageClass:     cliCtx.String("storage-class"),
+			md5:              cliCtx.Bool("md5"),
+			disableMultipart: cliCtx.Bool("disable-multipart"),
+			multipartSize:    partSize,
+			multipartThreads: parallelParts,
+		})
+		if err != nil {
+			if !globalQuiet && !globalJSON {
+				console.Eraseline()
+			}
+			if cliCtx.Bool("if-not-exists") && isErrTargetExists(err) {
+				printMsg(copySkippedMessage{Source: source, Target: targetURL})
+				continue
+			}
+			errorIf(err, "Unable to copy `"+source+"`.")
+			retErr = exitStatus(globalErrorExitStatus)
+			continue
+		}
+		if globalQuiet || globalJSON {
+			printMsg(msg)
+		}
+	}
+	return retErr
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHTTPSourceResume(t *testing.T) {
+	policy := globalRetryPolicy
+	defer func() { globalRetryPolicy = policy }()
+	globalRetryPolicy = retryPolicy{attempts: 1, backoff: time.Millisecond}
+
+	content := bytes.Repeat([]byte("0123456789"), 10000)
+
+	testCases := []struct {
+		rangeable  bool
+		changed    bool
+		drops      int
+		expected   []byte
+		expectErr  string
+		ranges     []string
+		conditions []string
+	}{
+		// The download is resumed where the connection dropped.
+		{true, false, 1, content, "", []string{"", "bytes=30000-"}, []string{"", `"v1"`}},
+		// Every drop is resumed from the last byte read.
+		{true, false, 2, content, "", []string{"", "bytes=30000-", "bytes=60000-"}, []string{"", `"v1"`, `"v1"`}},
+		// The content changed between the requests.
+		{true, true, 1, nil, "changed while being copied", []string{"", "bytes=30000-"}, []string{"", `"v1"`}},
+		// The server does not accept range requests.
+		{false, false, 1, nil, io.ErrUnexpectedEOF.Error(), []string{""}, []string{""}},
+	}
+
+	for i, testCase := range testCases {
+		var (
+			mu         sync.Mutex
+			ranges     []string
+			conditions []string
+		)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			ranges = append(ranges, r.Header.Get("Range"))
+			conditions = append(conditions, r.Header.Get("If-Range"))
+			n := len(ranges)
+			mu.Unlock()
+
+			etag := `"v1"`
+			if testCase.changed && n > 1 {
+				etag = `"v2"`
+			}
+			w.Header().Set("ETag", etag)
+			if !testCase.rangeable {
+				w.Header().Set("Accept-Ranges", "none")
+			}
+			if n > testCase.drops {
+				http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(content))
+				return
+			}
+
+			// Drop the connection 30000 bytes after the first one.
+			start := 0
+			if rng := r.Header.Get("Range"); rng != "" {
+				start, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+				w.Header().Set("Content-Range", "bytes "+strconv.Itoa(start)+"-"+strconv.Itoa(len(content)-1)+"/"+strconv.Itoa(len(content)))
+				w.Header().Set("Content-Length", strconv.Itoa(len(content)-start))
+				w.WriteHeader(http.StatusPartialContent)
+			} else {
+				if testCase.rangeable {
+					w.Header().Set("Accept-Ranges", "bytes")
+				}
+				w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+				w.WriteHeader(http.StatusOK)
+			}
+			w.Write(content[start : start+30000])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}))
+
+		src, err := openHTTPSource(context.Background(), srv.Client(), srv.URL+"/object")
+		if err != nil {
+			srv.Close()
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		got, e := io.ReadAll(src)
+		src.Close()
+		srv.Close()
+
+		switch {
+		case testCase.expectErr == "" && e != nil:
+			t.Fatalf("Test %d: unexpected error %v", i+1, e)
+		case testCase.expectErr != "" && (e == nil || !strings.Contains(e.Error(), testCase.expectErr)):
+			t.Fatalf("Test %d: expected error `%s`, got %v", i+1, testCase.expectErr, e)
+		case testCase.expectErr == "" && !bytes.Equal(got, testCase.expected):
+			t.Fatalf("Test %d: expected %d bytes of content, got %d", i+1, len(testCase.expected), len(got))
+		}
+		if strings.Join(ranges, ",") != strings.Join(testCase.ranges, ",") {
+			t.Fatalf("Test %d: expected ranges %q, got %q", i+1, testCase.ranges, ranges)
+		}
+		if strings.Join(conditions, ",") != strings.Join(testCase.conditions, ",") {
+			t.Fatalf("Test %d: expected If-Range %q, got %q", i+1, testCase.conditions, conditions)
+		}
+	}
+}
@@ -87,6 +87,10 @@ var (
 			Name:  "md5",
 			Usage: "force all upload(s) to calculate md5sum checksum",
 		},
+		cli.BoolFlag{
+			Name:  "if-not-exists",
+			Usage: "do not overwrite objects already present on target",
+		},
 		cli.StringFlag{
 			Name:  "tags",
 			Usage: "apply one or more tags to the uploaded objects",
@@ -107,6 +111,18 @@ var (
 			Name:  "zip",
 			Usage: "Extract from remote zip file (MinIO server source only)",
 		},
+		cli.StringFlag{
+			Name:  "files-from",
+			Usage: "copy the keys listed in a file, one per line or JSON with version IDs, relative to the source folder ('-' reads STDIN)",
+		},
+		cli.StringSliceFlag{
+			Name:  "name-transform",
+			Usage: "rewrite target names with 's/REGEX/REPLACEMENT/', 'strip-prefix:P', 'add-prefix:P', 'lower', 'upper' or 'date:TEMPLATE', applied in order",
+		},
+		targetTemplateFlag,
+		tagRouteFlag,
+		cseEncryptFlag,
+		activeHoursFlag,
 	}
 )
 
@@ -126,7 +142,7 @@ var cpCmd = cli.Command{
 	Action:       mainCopy,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        append(append(cpFlags, ioFlags...), globalFlags...),
+	Flags:        append(append(append(append(append(append(cpFlags, multipartFlags...), rangedDownloadFlags...), progressFlags...), retryFlags...), ioFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -138,7 +154,13 @@ FLAGS:
   {{end}}
 ENVIRONMENT VARIABLES:
   MC_ENCRYPT:      list of comma delimited prefixes
-  MC_ENCRYPT_KEY:  list of comma delimited prefix=secret values
+  MC_ENCRYPT_KEY:       list of comma delimited prefix=secret values
+  MC_ENCRYPT_KEY_FILE:  file of prefix=secret lines, same as --encrypt-key-file
+
+NOTE:
+  Objects copied within an alias, or between aliases of the same endpoint and credentials, are copied by the
+  server without going through the client, objects larger than 5GiB part by part. Zipped and client-side
+  encrypted copies go through the client.
 
 EXAMPLES:
   01. Copy a list of objects from local file system to Amazon S3 cloud storage.
@@ -202,6 +224,59 @@ EXAMPLES:
   20. Set tags to the uploaded objects
       {{.Prompt}} {{.HelpName}} -r --tags "category=prod&type=backup" ./data/ play/another-bucket/
 
+  21. Copy a folder recursively, retrying transient failures up to 5 times with a growing wait of at most 1 minute.
+      {{.Prompt}} {{.HelpName}} -r --retry-attempts 5 --retry-backoff 2s --retry-max-wait 1m ./data/ play/mybucket/
+
+  22. Copy the object versions listed by a previous 'mc ls --json' to a local folder.
+      {{.Prompt}} {{.HelpName}} --files-from versions.json play/mybucket/photos/ /tmp/photos/
+
+  23. Copy the keys read from STDIN, one per line.
+      {{.Prompt}} grep 2023 inventory.txt | {{.HelpName}} --files-from - play/mybucket/ s3/archive/
+
+  24. Copy a large file over a high latency link, sending 8 parts of 256MiB in parallel.
+      {{.Prompt}} {{.HelpName}} --part-size 256MiB --parallel-parts 8 backup.img play/mybucket/
+
+  25. Copy a folder recursively, writing a progress record every second to a named pipe read by a dashboard.
+      {{.Prompt}} mkfifo /tmp/mc-progress
+      {{.Prompt}} {{.HelpName}} --recursive --quiet --progress-events /tmp/mc-progress ~/Videos play/mybucket/
+
+  26. Copy a folder recursively, encrypting the data locally for an age recipient ('mc cat' and 'mc get' decrypt it with '--decrypt-with').
+      {{.Prompt}} {{.HelpName}} --recursive --encrypt-with age:age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p ~/Documents play/mybucket/
+
+  27. Copy a folder recursively, aborting and retrying up to 3 times any object sending no data for 2 minutes.
+      {{.Prompt}} {{.HelpName}} --recursive --stall-timeout 2m --retry-attempts 3 ~/Videos play/mybucket/
+
+  28. Rotate the SSE-C key of the objects of a prefix, reading them with the old key and writing them with the new one.
+      {{.Prompt}} echo "s3/mybucket/finance/=32byteslongsecretkeymustbegiven1=32byteslongsecretkeymustbegiven2" > rotate-keys
+      {{.Prompt}} {{.HelpName}} --recursive --encrypt-key-file rotate-keys s3/mybucket/finance/ s3/mybucket/finance/
+
+  29. Migrate logs to a bucket partitioned by date, dropping their 'logs/' prefix and lowering their names.
+      {{.Prompt}} {{.HelpName}} --recursive --name-transform strip-prefix:logs/ --name-transform lower \
+         --name-transform "date:year={yyyy}/month={mm}/" /var/log/app/ s3/datalake/logs/
+
+  30. Copy reports from several hosts to a shared bucket, leaving the objects already uploaded by another host untouched.
+      {{.Prompt}} {{.HelpName}} --recursive --if-not-exists reports/ s3/shared/reports/
+
+  31. Upload events partitioned by the year and month of their modification time, as expected by analytics engines.
+      {{.Prompt}} {{.HelpName}} --recursive --target-template '{{"{{.Year}}/{{.Month}}/{{.SourceBase}}"}}' events/ s3/datalake/events/
+
+  32. Download a file from a web server straight into a bucket, resuming the download if the connection breaks.
+      {{.Prompt}} {{.HelpName}} https://cdimage.debian.org/debian-cd/current/amd64/iso-cd/debian-12.2.0-amd64-netinst.iso s3/isos/
+
+  33. Download large objects to a local folder, fetching 8 byte ranges of each object in parallel.
+      {{.Prompt}} {{.HelpName}} --recursive --parallel-ranges 8 s3/datasets/imagenet/ /data/imagenet/
+
+  34. Copy a bucket in one pass, sending objects tagged 'class=pii' below 'restricted/' and objects
+      tagged 'class=archive' to the GLACIER storage class.
+      {{.Prompt}} {{.HelpName}} --recursive --route-by-tag class=pii:restricted/ \
+         --route-by-tag class=archive::GLACIER s3/raw/ s3/curated/
+
+  35. Copy a large folder only between 10 PM and 6 AM, pausing during the day.
+      {{.Prompt}} {{.HelpName}} --recursive --active-hours 22:00-06:00 /mnt/archive/ s3/archive/
+
+  36. Copy a multi-terabyte object to another bucket server-side, copying 16 parts of 1GiB in parallel.
+      {{.Prompt}} {{.HelpName}} --part-size 1GiB --parallel-parts 16 s3/backups/disk.img s3/archive/
+
 `,
 }
 
@@ -229,6 +304,28 @@ func (c copyMessage) JSON() string {
 	return string(copyMessageBytes)
 }
 
+// copySkippedMessage is printed for a source not copied because its
+// target exists and --if-not-exists is set, which is not an error.
+type copySkippedMessage struct {
+	Status string `json:"status"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// String colorized copy skipped message
+func (c copySkippedMessage) String() string {
+	return console.Colorize("CopySkipped", fmt.Sprintf("Skipped `%s`, `%s` already exists.", c.Source, c.Target))
+}
+
+// JSON jsonified copy skipped message
+func (c copySkippedMessage) JSON() string {
+	c.Status = "skipped"
+	copySkippedMessageBytes, e := json.MarshalIndent(c, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(copySkippedMessageBytes)
+}
+
 // Progress - an interface which describes current amount
 // of data written.
 type Progress interface {
@@ -244,7 +341,7 @@ type ProgressReader interface {
 }
 
 // doCopy - Copy a single file from source to destination
-func doCopy(ctx context.Context, cpURLs URLs, pg ProgressReader, encKeyDB map[string][]prefixSSEPair, isMvCmd, preserve, isZip bool) URLs {
+func doCopy(ctx context.Context, cpURLs URLs, pg ProgressReader, events *progressEvents, encKeyDB map[string][]prefixSSEPair, isMvCmd, preserve, isZip bool) URLs {
 	if cpURLs.Error != nil {
 		cpURLs.Error = cpURLs.Error.Trace()
 		return cpURLs
@@ -270,19 +367,38 @@ func doCopy(ctx context.Context, cpURLs URLs, pg ProgressReader, encKeyDB map[st
 		})
 	}
 
-	urls := uploadSourceToTargetURL(ctx, cpURLs, pg, encKeyDB, preserve, isZip)
-	if isMvCmd && urls.Error == nil {
-		rmManager.add(ctx, sourceAlias, sourceURL.String())
+	events.setCurrent(sourcePath)
+
+	var urls URLs
+	err := withTransferRetry(ctx, "copy", sourcePath, withProgressEvents(pg, events), func(progress io.Reader) *probe.Error {
+		return withStallWatchdog(ctx, sourcePath, stallTimeout(cpURLs, isZip), progress, func(ctx context.Context, progress io.Reader) *probe.Error {
+			urls = uploadSourceToTargetURL(ctx, cpURLs, progress, encKeyDB, preserve, isZip)
+			return urls.Error
+		})
+	})
+	urls = urls.WithError(err)
+	if urls.skipped {
+		// Account for the size of the object which is not copied.
+		doCopyFake(urls, pg, events)
+		return urls
+	}
+	if isMvCmd {
+		if urls.Error != nil {
+			rmManager.copyFailure()
+		} else {
+			rmManager.add(ctx, sourceAlias, sourceURL.String())
+		}
 	}
 
 	return urls
 }
 
 // doCopyFake - Perform a fake copy to update the progress bar appropriately.
-func doCopyFake(cpURLs URLs, pg Progress) URLs {
+func doCopyFake(cpURLs URLs, pg Progress, events *progressEvents) URLs {
 	if progressReader, ok := pg.(*progressBar); ok {
 		progressReader.ProgressBar.Add64(cpURLs.SourceContent.Size)
 	}
+	events.addTransferred(cpURLs.SourceContent.Size)
 
 	return cpURLs
 }
@@ -302,9 +418,28 @@ func doPrepareCopyURLs(ctx context.Context, session *sessionV8, cancelCopy conte
 	newerThan := session.Header.CommandStringFlags["newer-than"]
 	encryptKeys := session.Header.CommandStringFlags["encrypt-key"]
 	encrypt := session.Header.CommandStringFlags["encrypt"]
+	filesFrom := session.Header.CommandStringFlags["files-from"]
 	encKeyDB, err := parseAndValidateEncryptionKeys(encryptKeys, encrypt)
 	fatalIf(err, "Unable to parse encryption keys.")
 
+	var transforms nameTransforms
+	if rules := session.Header.CommandStringFlags["name-transform"]; rules != "" {
+		transforms, err = parseNameTransforms(strings.Split(rules, "\n"))
+		fatalIf(err, "Unable to parse name transforms.")
+	}
+
+	var tmpl *targetTemplate
+	if text := session.Header.CommandStringFlags["target-template"]; text != "" {
+		tmpl, err = parseTargetTemplate(text)
+		fatalIf(err, "Unable to parse target template.")
+	}
+
+	var routes tagRoutes
+	if rules := session.Header.CommandStringFlags["route-by-tag"]; rules != "" {
+		routes, err = parseTagRoutes(strings.Split(rules, "\n"))
+		fatalIf(err, "Unable to parse tag routes.")
+	}
+
 	// Create a session data file to store the processed URLs.
 	dataFP := session.NewDataWriter()
 
@@ -314,14 +449,18 @@ func doPrepareCopyURLs(ctx context.Context, session *sessionV8, cancelCopy conte
 	}
 
 	opts := prepareCopyURLsOpts{
-		sourceURLs:  sourceURLs,
-		targetURL:   targetURL,
-		isRecursive: isRecursive,
-		encKeyDB:    encKeyDB,
-		olderThan:   olderThan,
-		newerThan:   newerThan,
-		timeRef:     parseRewindFlag(rewind),
-		versionID:   versionID,
+		sourceURLs:     sourceURLs,
+		targetURL:      targetURL,
+		isRecursive:    isRecursive,
+		encKeyDB:       encKeyDB,
+		olderThan:      olderThan,
+		newerThan:      newerThan,
+		timeRef:        parseRewindFlag(rewind),
+		versionID:      versionID,
+		filesFrom:      filesFrom,
+		nameTransforms: transforms,
+		targetTemplate: tmpl,
+		tagRoutes:      routes,
 	}
 
 	URLsCh := prepareCopyURLs(ctx, opts)
@@ -393,6 +532,14 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 		pg = newAccounter(totalBytes)
 	}
 
+	events, err := newProgressEvents(cli.String("progress-events"))
+	fatalIf(err, "Unable to open progress events output.")
+	events.start()
+	defer events.stop()
+
+	// Validated by checkCopySyntax.
+	activeHours, _ := parseActiveHours(cli.String("active-hours"))
+
 	sourceURLs := cli.Args()[:len(cli.Args())-1]
 	targetURL := cli.Args()[len(cli.Args())-1] // Last one is target
 
@@ -411,6 +558,7 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 		}
 
 		pg.SetTotal(totalBytes)
+		events.setTotal(totalBytes, totalObjects)
 
 		go func() {
 			jsoniter := jsoniter.ConfigCompatibleWithStandardLibrary
@@ -438,19 +586,30 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 		newerThan := cli.String("newer-than")
 		rewind := cli.String("rewind")
 		versionID := cli.String("version-id")
+		// Validated by checkCopySyntax.
+		transforms, _ := parseNameTransforms(cli.StringSlice("name-transform"))
+		var tmpl *targetTemplate
+		if text := cli.String("target-template"); text != "" {
+			tmpl, _ = parseTargetTemplate(text)
+		}
+		routes, _ := parseTagRoutes(cli.StringSlice("route-by-tag"))
 
 		go func() {
 			totalBytes := int64(0)
 			opts := prepareCopyURLsOpts{
-				sourceURLs:  sourceURLs,
-				targetURL:   targetURL,
-				isRecursive: isRecursive,
-				encKeyDB:    encKeyDB,
-				olderThan:   olderThan,
-				newerThan:   newerThan,
-				timeRef:     parseRewindFlag(rewind),
-				versionID:   versionID,
-				isZip:       cli.Bool("zip"),
+				sourceURLs:     sourceURLs,
+				targetURL:      targetURL,
+				isRecursive:    isRecursive,
+				encKeyDB:       encKeyDB,
+				olderThan:      olderThan,
+				newerThan:      newerThan,
+				timeRef:        parseRewindFlag(rewind),
+				versionID:      versionID,
+				isZip:          cli.Bool("zip"),
+				filesFrom:      cli.String("files-from"),
+				nameTransforms: transforms,
+				targetTemplate: tmpl,
+				tagRoutes:      routes,
 			}
 			for cpURLs := range prepareCopyURLs(ctx, opts) {
 				if cpURLs.Error != nil {
@@ -467,10 +626,15 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 						errorIf(cpURLs.Error.Trace(),
 							"Unable to start copying.")
 					}
+					// A missing key of the list does not stop the copy.
+					if opts.filesFrom != "" {
+						continue
+					}
 					break
 				}
 				totalBytes += cpURLs.SourceContent.Size
 				pg.SetTotal(totalBytes)
+				events.addTotal(cpURLs.SourceContent.Size, 1)
 				totalObjects++
 				cpURLsCh <- cpURLs
 			}
@@ -478,6 +642,14 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 		}()
 	}
 
+	// Validated by checkCopySyntax.
+	partSize, parallelParts, _ := parseMultipartFlags(cli)
+	parallelRanges, rangeSize, _ := parseRangedDownloadFlags(cli)
+	var encryptKey *cseKey
+	if spec := cli.String("encrypt-with"); spec != "" {
+		encryptKey, _ = parseCSEKey(spec, false)
+	}
+
 	quitCh := make(chan struct{})
 	statusCh := make(chan URLs)
 
@@ -514,7 +686,8 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 				cpURLs.TargetContent.UserMetadata = make(map[string]string)
 
 				// Check and handle storage class if passed in command line args
-				if storageClass := cli.String("storage-class"); storageClass != "" {
+				// unless a route of --route-by-tag already chose one.
+				if storageClass := cli.String("storage-class"); storageClass != "" && cpURLs.TargetContent.StorageClass == "" {
 					cpURLs.TargetContent.StorageClass = storageClass
 				}
 
@@ -545,11 +718,15 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 
 				cpURLs.MD5 = cli.Bool("md5") || withLock
 				cpURLs.DisableMultipart = cli.Bool("disable-multipart")
+				cpURLs.IfNotExists = cli.Bool("if-not-exists")
+				cpURLs.MultipartSize, cpURLs.MultipartThreads = partSize, parallelParts
+				cpURLs.ParallelRanges, cpURLs.RangeSize = parallelRanges, rangeSize
+				cpURLs.cseKey = encryptKey
 
 				// Verify if previously copied, notify progress bar.
 				if isCopied != nil && isCopied(cpURLs.SourceContent.URL.String()) {
 					parallel.queueTask(func() URLs {
-						return doCopyFake(cpURLs, pg)
+						return doCopyFake(cpURLs, pg, events)
 					}, 0)
 				} else {
 					// Print the copy resume summary once in start
@@ -561,8 +738,9 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 						}
 						startContinue = false
 					}
+					activeHours.wait(ctx)
 					parallel.queueTask(func() URLs {
-						return doCopy(ctx, cpURLs, pg, encKeyDB, isMvCmd, preserve, isZip)
+						return doCopy(ctx, cpURLs, pg, events, encKeyDB, isMvCmd, preserve, isZip)
 					}, cpURLs.SourceContent.Size)
 				}
 			}
@@ -572,6 +750,7 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 	var retErr error
 	errSeen := false
 	cpAllFilesErr := true
+	var serverSide serverSideCopySummaryMessage
 
 loop:
 	for {
@@ -592,12 +771,29 @@ loop:
 			if !ok {
 				break loop
 			}
+			if cpURLs.skipped {
+				if !globalQuiet && !globalJSON {
+					console.Eraseline()
+				}
+				printMsg(copySkippedMessage{
+					Source: filepath.ToSlash(filepath.Join(cpURLs.SourceAlias, cpURLs.SourceContent.URL.Path)),
+					Target: filepath.ToSlash(filepath.Join(cpURLs.TargetAlias, cpURLs.TargetContent.URL.Path)),
+				})
+				events.objectDone()
+				cpAllFilesErr = false
+				continue loop
+			}
 			if cpURLs.Error == nil {
 				if session != nil {
 					session.Header.LastCopied = cpURLs.SourceContent.URL.String()
 					session.Save()
 				}
+				events.objectDone()
 				cpAllFilesErr = false
+				if cpURLs.serverSideCopy {
+					serverSide.Objects++
+					serverSide.Size += cpURLs.SourceContent.Size
+				}
 			} else {
 
 				// Set exit status for any copy error
@@ -608,12 +804,14 @@ loop:
 				if !globalQuiet && !globalJSON {
 					console.Eraseline()
 				}
-				errorIf(cpURLs.Error.Trace(cpURLs.SourceContent.URL.String()),
-					fmt.Sprintf("Failed to copy `%s`.", cpURLs.SourceContent.URL.String()))
 				if isErrIgnored(cpURLs.Error) {
+					warningIf(warningSkipped, cpURLs.Error.Trace(cpURLs.SourceContent.URL.String()),
+						fmt.Sprintf("Skipped `%s`.", cpURLs.SourceContent.URL.String()))
 					cpAllFilesErr = false
 					continue loop
 				}
+				errorIf(cpURLs.Error.Trace(cpURLs.SourceContent.URL.String()),
+					fmt.Sprintf("Failed to copy `%s`.", cpURLs.SourceContent.URL.String()))
 
 				errSeen = true
 				if progressReader, pgok := pg.(*progressBar); pgok {
@@ -649,6 +847,10 @@ loop:
 			printMsg(accntReader.Stat())
 		}
 	}
+	printStallSummary()
+	if serverSide.Objects > 0 {
+		printMsg(serverSide)
+	}
 
 	return retErr
 }
@@ -669,10 +871,20 @@ func mainCopy(cliCtx *cli.Context) error {
 		fatalIf(err, "Unable to parse attribute %v", cliCtx.String("attr"))
 	}
 
-	// check 'copy' cli arguments.
-	checkCopySyntax(ctx, cliCtx, encKeyDB, false)
 	// Additional command specific theme customization.
 	console.SetColor("Copy", color.New(color.FgGreen, color.Bold))
+	console.SetColor("CopySkipped", color.New(color.FgYellow))
+	console.SetColor("Stall", color.New(color.FgYellow, color.Bold))
+	console.SetColor("ServerSideCopy", color.New(color.FgCyan))
+	console.SetColor("ActiveHours", color.New(color.FgYellow))
+
+	// HTTP(S) sources are downloaded and streamed to the target.
+	if cliCtx.Args().Present() && isHTTPSource(cliCtx.Args().First()) {
+		return mainCopyHTTP(ctx, cliCtx, encKeyDB, userMetaMap)
+	}
+
+	// check 'copy' cli arguments.
+	checkCopySyntax(ctx, cliCtx, encKeyDB, false)
 
 	recursive := cliCtx.Bool("recursive")
 	rewind := cliCtx.String("rewind")
@@ -717,6 +929,10 @@ func mainCopy(cliCtx *cli.Context) error {
 			session.Header.CommandStringFlags[lhFlag] = legalHold
 			session.Header.CommandStringFlags["encrypt-key"] = sseKeys
 			session.Header.CommandStringFlags["encrypt"] = sse
+			session.Header.CommandStringFlags["files-from"] = cliCtx.String("files-from")
+			session.Header.CommandStringFlags["name-transform"] = strings.Join(cliCtx.StringSlice("name-transform"), "\n")
+			session.Header.CommandStringFlags["target-template"] = cliCtx.String("target-template")
+			session.Header.CommandStringFlags["route-by-tag"] = strings.Join(cliCtx.StringSlice("route-by-tag"), "\n")
 			session.Header.CommandBoolFlags["session"] = cliCtx.Bool("continue")
 
 			if cliCtx.Bool("preserve") {
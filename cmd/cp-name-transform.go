@@ -0,0 +1,151 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+// nameTransformRule rewrites the name of an object relative to the
+// target folder, given the modification time of the source object.
+type nameTransformRule func(name string, modTime time.Time) string
+
+// nameTransforms are the rules of --name-transform, applied in order.
+type nameTransforms []nameTransformRule
+
+// apply rewrites name with all the rules.
+func (t nameTransforms) apply(name string, modTime time.Time) string {
+	for _, rule := range t {
+		name = rule(name, modTime)
+	}
+	return name
+}
+
+// modTimeFields returns the zero padded UTC year, month, day and
+// hour of the modification time t, to name targets by date.
+func modTimeFields(t time.Time) (year, month, day, hour string) {
+	t = t.UTC()
+	return fmt.Sprintf("%04d", t.Year()), fmt.Sprintf("%02d", t.Month()), fmt.Sprintf("%02d", t.Day()), fmt.Sprintf("%02d", t.Hour())
+}
+
+// parseNameTransforms parses the rules of --name-transform:
+//
+//	s/REGEX/REPLACEMENT/  replace matches of a regular expression, any
+//	                      character following 's' can be the delimiter
+//	strip-prefix:PREFIX   remove a leading prefix
+//	add-prefix:PREFIX     add a leading prefix
+//	lower, upper          change the case of the name
+//	date:TEMPLATE         add a leading prefix made of the modification time
+//	                      of the source, {yyyy} {mm} {dd} and {hh} in TEMPLATE
+//	                      being replaced by its UTC year, month, day and hour
+func parseNameTransforms(rules []string) (nameTransforms, *probe.Error) {
+	var t nameTransforms
+	for _, rule := range rules {
+		kind, arg, _ := strings.Cut(rule, ":")
+		switch {
+		case rule == "lower":
+			t = append(t, func(name string, _ time.Time) string { return strings.ToLower(name) })
+		case rule == "upper":
+			t = append(t, func(name string, _ time.Time) string { return strings.ToUpper(name) })
+		case kind == "strip-prefix" && arg != "":
+			t = append(t, func(name string, _ time.Time) string { return strings.TrimPrefix(name, arg) })
+		case kind == "add-prefix" && arg != "":
+			t = append(t, func(name string, _ time.Time) string { return arg + name })
+		case kind == "date" && arg != "":
+			t = append(t, func(name string, modTime time.Time) string {
+				year, month, day, hour := modTimeFields(modTime)
+				return strings.NewReplacer(
+					"{yyyy}", year,
+					"{mm}", month,
+					"{dd}", day,
+					"{hh}", hour,
+				).Replace(arg) + name
+			})
+		case len(rule) > 3 && rule[0] == 's':
+			fields := strings.Split(rule[2:], rule[1:2])
+			if len(fields) != 3 || fields[0] == "" || fields[2] != "" {
+				return nil, probe.NewError(fmt.Errorf("regular expression rule should be of the form s/REGEX/REPLACEMENT/")).Trace(rule)
+			}
+			re, e := regexp.Compile(fields[0])
+			if e != nil {
+				return nil, probe.NewError(e).Trace(rule)
+			}
+			replacement := fields[1]
+			t = append(t, func(name string, _ time.Time) string { return re.ReplaceAllString(name, replacement) })
+		default:
+			return nil, probe.NewError(fmt.Errorf("unknown name transform `%s`", rule))
+		}
+	}
+	return t, nil
+}
+
+// transformTargetName rewrites the name of the target of cpURLs below
+// the target folder targetURL. A target outside of targetURL, as when
+// copying a file to a file, is left untouched. Transformed names must
+// stay relative to targetURL.
+func transformTargetName(cpURLs URLs, targetURL string, t nameTransforms) URLs {
+	return rewriteTargetName(cpURLs, targetURL, func(name string, src *ClientContent) (string, *probe.Error) {
+		name = t.apply(name, src.Time)
+		if path.IsAbs(filepath.ToSlash(name)) || filepath.VolumeName(name) != "" {
+			return "", probe.NewError(fmt.Errorf("name transforms give the absolute name `%s`", name))
+		}
+		return name, nil
+	})
+}
+
+// rewriteTargetName replaces the name of the target of cpURLs below the
+// target folder targetURL by the one returned by rewrite. A name leaving
+// the target folder once cleaned, as with `../`, is an error.
+func rewriteTargetName(cpURLs URLs, targetURL string, rewrite func(name string, src *ClientContent) (string, *probe.Error)) URLs {
+	_, expandedTargetURL, _ := mustExpandAlias(targetURL)
+	base := strings.TrimSuffix(filepath.ToSlash(newClientURL(expandedTargetURL).Path), "/") + "/"
+
+	target := cpURLs.TargetContent.URL
+	targetPath := filepath.ToSlash(target.Path)
+	if !strings.HasPrefix(targetPath, base) || targetPath == base {
+		return cpURLs
+	}
+
+	name, err := rewrite(strings.TrimPrefix(targetPath, base), cpURLs.SourceContent)
+	if err != nil {
+		return cpURLs.WithError(err.Trace(targetPath))
+	}
+	name = strings.TrimPrefix(name, "/")
+	if name == "" {
+		return cpURLs.WithError(probe.NewError(fmt.Errorf("rewriting `%s` gives an empty name", targetPath)))
+	}
+	// The name is relative to the target folder, it must not leave it.
+	if clean := path.Clean(filepath.ToSlash(name)); clean == ".." || strings.HasPrefix(clean, "../") {
+		return cpURLs.WithError(probe.NewError(fmt.Errorf("rewriting `%s` gives `%s`, outside of the target", targetPath, name)))
+	}
+	target.Path = base + name
+	if target.Type == fileSystem {
+		target.Path = filepath.FromSlash(target.Path)
+	}
+	targetContent := *cpURLs.TargetContent
+	targetContent.URL = target
+	cpURLs.TargetContent = &targetContent
+	return cpURLs
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+// isServerSideCopy returns true if the object of urls can be copied
+// by the server, without the data going through the client: both
+// source and target are on the same alias, or on two aliases of the
+// same endpoint with the same credentials, and the data is neither
+// zipped nor encrypted on the client.
+func isServerSideCopy(urls URLs, isZip bool) bool {
+	if isZip || urls.cseKey != nil {
+		return false
+	}
+	if urls.SourceAlias == urls.TargetAlias {
+		return true
+	}
+	if urls.SourceAlias == "" || urls.TargetAlias == "" {
+		return false
+	}
+	// Resolve the aliases as the copy does, environment first.
+	_, _, srcCfg := mustExpandAlias(urls.SourceAlias)
+	_, _, tgtCfg := mustExpandAlias(urls.TargetAlias)
+	if srcCfg == nil || tgtCfg == nil {
+		return false
+	}
+	// The server reads the source with the credentials of the target,
+	// they must be the same credentials, not only the same user.
+	return strings.TrimSuffix(srcCfg.URL, "/") == strings.TrimSuffix(tgtCfg.URL, "/") &&
+		srcCfg.AccessKey == tgtCfg.AccessKey &&
+		srcCfg.SecretKey == tgtCfg.SecretKey &&
+		srcCfg.SessionToken == tgtCfg.SessionToken
+}
+
+// serverSideCopySummaryMessage reports the objects copied by the
+// server, whose data did not go through the client.
+type serverSideCopySummaryMessage struct {
+	Status  string `json:"status"`
+	Objects int64  `json:"serverSideObjects"`
+	Size    int64  `json:"serverSideBytes"`
+}
+
+// String colorized server side copy summary message
+func (s serverSideCopySummaryMessage) String() string {
+	return console.Colorize("ServerSideCopy", fmt.Sprintf("%d object(s) copied on the server side, %s did not go through the client.",
+		s.Objects, humanize.IBytes(uint64(s.Size))))
+}
+
+// JSON jsonified server side copy summary message
+func (s serverSideCopySummaryMessage) JSON() string {
+	s.Status = "success"
+	summaryMessageBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(summaryMessageBytes)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import "testing"
+
+func TestIsServerSideCopy(t *testing.T) {
+	aliases := map[string]*aliasConfigV10{
+		"mc-test-ssc":            {URL: "https://localhost:9000", AccessKey: "minio", SecretKey: "minio123"},
+		"mc-test-ssc-same":       {URL: "https://localhost:9000/", AccessKey: "minio", SecretKey: "minio123"},
+		"mc-test-ssc-secret":     {URL: "https://localhost:9000", AccessKey: "minio", SecretKey: "minio456"},
+		"mc-test-ssc-token":      {URL: "https://localhost:9000", AccessKey: "minio", SecretKey: "minio123", SessionToken: "token"},
+		"mc-test-ssc-access":     {URL: "https://localhost:9000", AccessKey: "other", SecretKey: "minio123"},
+		"mc-test-ssc-other-host": {URL: "https://localhost:9001", AccessKey: "minio", SecretKey: "minio123"},
+	}
+	for alias, cfg := range aliases {
+		aliasToConfigMap[alias] = cfg
+		defer delete(aliasToConfigMap, alias)
+	}
+
+	testCases := []struct {
+		sourceAlias string
+		targetAlias string
+		isZip       bool
+		expected    bool
+	}{
+		{"mc-test-ssc", "mc-test-ssc", false, true},
+		{"mc-test-ssc", "mc-test-ssc", true, false},
+		{"mc-test-ssc", "mc-test-ssc-same", false, true},
+		{"mc-test-ssc", "mc-test-ssc-secret", false, false},
+		{"mc-test-ssc", "mc-test-ssc-token", false, false},
+		{"mc-test-ssc-token", "mc-test-ssc-token", false, true},
+		{"mc-test-ssc", "mc-test-ssc-access", false, false},
+		{"mc-test-ssc", "mc-test-ssc-other-host", false, false},
+		{"", "mc-test-ssc", false, false},
+		{"mc-test-ssc", "", false, false},
+	}
+	for i, testCase := range testCases {
+		urls := URLs{SourceAlias: testCase.sourceAlias, TargetAlias: testCase.targetAlias}
+		if got := isServerSideCopy(urls, testCase.isZip); got != testCase.expected {
+			t.Errorf("Test %d: expected %v for %q to %q, got %v", i+1, testCase.expected, testCase.sourceAlias, testCase.targetAlias, got)
+		}
+	}
+}
@@ -20,7 +20,9 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/minio/cli"
@@ -57,6 +59,58 @@ func checkCopySyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[stri
 		fatalIf(errDummy().Trace(cliCtx.Args()...), "--zip and --rewind cannot be used together")
 	}
 
+	if _, _, err := parseMultipartFlags(cliCtx); err != nil {
+		fatalIf(err, "Unable to parse multipart upload flags.")
+	}
+
+	if _, err := parseActiveHours(cliCtx.String("active-hours")); err != nil {
+		fatalIf(err, "Unable to parse --active-hours.")
+	}
+
+	if _, _, err := parseRangedDownloadFlags(cliCtx); err != nil {
+		fatalIf(err, "Unable to parse ranged download flags.")
+	}
+
+	if _, err := parseNameTransforms(cliCtx.StringSlice("name-transform")); err != nil {
+		fatalIf(err, "Unable to parse name transforms.")
+	}
+
+	if text := cliCtx.String("target-template"); text != "" {
+		_, err := parseTargetTemplate(text)
+		fatalIf(err, "Unable to parse target template.")
+	}
+
+	if rules := cliCtx.StringSlice("route-by-tag"); len(rules) > 0 {
+		_, err := parseTagRoutes(rules)
+		fatalIf(err, "Unable to parse tag routes.")
+		for _, srcURL := range srcURLs {
+			if _, _, hostCfg, err := expandAlias(srcURL); err != nil || hostCfg == nil {
+				fatalIf(errInvalidArgument().Trace(srcURL), "--route-by-tag requires the sources to be on an alias, local files have no tags.")
+			}
+		}
+	}
+
+	if spec := cliCtx.String("encrypt-with"); spec != "" {
+		_, err := parseCSEKey(spec, false)
+		fatalIf(err, "Unable to parse client-side encryption key.")
+		if _, _, hostCfg, err := expandAlias(tgtURL); err != nil || hostCfg == nil {
+			fatalIf(errInvalidArgument().Trace(tgtURL), "--encrypt-with requires the target to be on an alias.")
+		}
+	}
+
+	filesFrom := cliCtx.String("files-from")
+	if filesFrom != "" {
+		if len(srcURLs) != 1 || isRecursive || versionID != "" || cliCtx.String("rewind") != "" {
+			fatalIf(errDummy().Trace(cliCtx.Args()...), "--files-from requires a single source folder and cannot be used with --recursive, --version-id or --rewind.")
+		}
+		if filesFrom != "-" {
+			_, e := os.Stat(filesFrom)
+			fatalIf(probe.NewError(e).Trace(filesFrom), "Unable to read list of objects `"+filesFrom+"`.")
+		}
+		// Sources are read from the list, they are verified while copying.
+		srcURLs = nil
+	}
+
 	// Verify if source(s) exists.
 	for _, srcURL := range srcURLs {
 		var err *probe.Error
@@ -91,6 +145,15 @@ func checkCopySyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[stri
 		fatalIf(errInvalidArgument().Trace(), fmt.Sprintf("Both object retention flags `--%s` and `--%s` are required.\n", rdFlag, rmFlag))
 	}
 
+	// Preserve functionality not supported for windows
+	if cliCtx.Bool("preserve") && runtime.GOOS == "windows" {
+		fatalIf(errInvalidArgument().Trace(), "Permissions are not preserved on windows platform.")
+	}
+
+	if filesFrom != "" {
+		return
+	}
+
 	operation := "copy"
 	if isMvCmd {
 		operation = "move"
@@ -133,11 +196,6 @@ func checkCopySyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[stri
 	default:
 		fatalIf(errInvalidArgument().Trace(), "Unable to guess the type of "+operation+" operation.")
 	}
-
-	// Preserve functionality not supported for windows
-	if cliCtx.Bool("preserve") && runtime.GOOS == "windows" {
-		fatalIf(errInvalidArgument().Trace(), "Permissions are not preserved on windows platform.")
-	}
 }
 
 // checkCopySyntaxTypeA verifies if the source and target are valid file arguments.
@@ -195,8 +253,9 @@ func checkCopySyntaxTypeC(ctx context.Context, srcURLs []string, tgtURL string,
 				fatalIf(errInvalidArgument().Trace(srcURL), fmt.Sprintf("To %v a folder requires --recursive flag.", operation))
 			}
 
-			// Check if we are going to copy a directory into itself
-			if isURLContains(srcURL, tgtURL, string(c.GetURL().Separator)) {
+			// Check if we are going to copy a directory into itself,
+			// unless rotating the SSE-C key of its objects in place.
+			if isURLContains(srcURL, tgtURL, string(c.GetURL().Separator)) && !isSSECRotation(srcURL, tgtURL, keys) {
 				operation := "Copying"
 				if isMvCmd {
 					operation = "Moving"
@@ -217,3 +276,18 @@ func checkCopySyntaxTypeD(ctx context.Context, tgtURL string, keys map[string][]
 		}
 	}
 }
+
+// isSSECRotation returns true if the objects of srcURL are copied onto
+// themselves with a rotated SSE-C key.
+func isSSECRotation(srcURL, tgtURL string, keys map[string][]prefixSSEPair) bool {
+	if strings.TrimSuffix(srcURL, "/") != strings.TrimSuffix(tgtURL, "/") {
+		return false
+	}
+	alias, _ := url2Alias(srcURL)
+	for _, k := range keys[alias] {
+		if k.NewSSE != nil && strings.HasPrefix(strings.TrimSuffix(srcURL, "/")+"/", k.Prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -229,6 +229,28 @@ type prepareCopyURLsOpts struct {
 	timeRef              time.Time
 	versionID            string
 	isZip                bool
+	filesFrom            string
+	nameTransforms       nameTransforms
+	targetTemplate       *targetTemplate
+	tagRoutes            tagRoutes
+}
+
+// LIST OF KEYS - copy(d/k1...d/kN, t) -> []copy(d/k, t/k)
+// prepareCopyURLsFromList - prepares source and target clientURLs for
+// every key of the --files-from list, relative to the source folder.
+func prepareCopyURLsFromList(ctx context.Context, o prepareCopyURLsOpts, copyURLsCh chan<- URLs) {
+	err := readFileList(o.filesFrom, func(entry fileListEntry) *probe.Error {
+		select {
+		case copyURLsCh <- prepareCopyURLsTypeA(ctx, urlJoinPath(o.sourceURLs[0], entry.Key), entry.VersionID,
+			urlJoinPath(o.targetURL, entry.Key), o.encKeyDB, o.isZip):
+			return nil
+		case <-ctx.Done():
+			return probe.NewError(ctx.Err())
+		}
+	})
+	if err != nil {
+		copyURLsCh <- URLs{Error: err.Trace(o.filesFrom)}
+	}
 }
 
 // prepareCopyURLs - prepares target and source clientURLs for copying.
@@ -236,6 +258,10 @@ func prepareCopyURLs(ctx context.Context, o prepareCopyURLsOpts) chan URLs {
 	copyURLsCh := make(chan URLs)
 	go func(o prepareCopyURLsOpts) {
 		defer close(copyURLsCh)
+		if o.filesFrom != "" {
+			prepareCopyURLsFromList(ctx, o, copyURLsCh)
+			return
+		}
 		cpType, cpVersion, err := guessCopyURLType(ctx, o)
 		fatalIf(err.Trace(), "Unable to guess the type of copy operation.")
 
@@ -261,6 +287,18 @@ func prepareCopyURLs(ctx context.Context, o prepareCopyURLsOpts) chan URLs {
 	go func() {
 		defer close(finalCopyURLsCh)
 		for cpURLs := range copyURLsCh {
+			if cpURLs.Error == nil && o.targetTemplate != nil {
+				cpURLs = rewriteTargetName(cpURLs, o.targetURL, func(name string, src *ClientContent) (string, *probe.Error) {
+					return o.targetTemplate.render(ctx, cpURLs.SourceAlias, name, src)
+				})
+			}
+			if cpURLs.Error == nil && len(o.nameTransforms) > 0 {
+				cpURLs = transformTargetName(cpURLs, o.targetURL, o.nameTransforms)
+			}
+			if cpURLs.Error == nil && len(o.tagRoutes) > 0 {
+				cpURLs = routeTargetByTag(ctx, cpURLs, o.targetURL, o.tagRoutes)
+			}
+
 			// Skip objects older than --older-than parameter if specified
 			if o.olderThan != "" && isOlder(cpURLs.SourceContent.Time, o.olderThan) {
 				continue
@@ -0,0 +1,305 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+// Client-side encryption (CSE) of object data.
+//
+// The data of an object is encrypted locally with a random data key,
+// using the DARE-like streaming format of sio-go with AES-256-GCM.
+// The data key is wrapped either for an age X25519 recipient, as an
+// age file, or with a 256-bit key read from a file, and stored with
+// the stream nonce and the plaintext size in the user metadata of the
+// object:
+//
+//	X-Amz-Meta-Mc-Cse:       cseVersion
+//	X-Amz-Meta-Mc-Cse-Key:   "age:" or "file:" followed by the base64 wrapped data key
+//	X-Amz-Meta-Mc-Cse-Nonce: base64 nonce of the stream
+//	X-Amz-Meta-Mc-Cse-Size:  size of the plaintext
+//
+// The version, the key kind, the nonce and the size are authenticated
+// as associated data of the stream, so that none of them can be
+// changed without failing the decryption.
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/minio/cli"
+	sio "github.com/secure-io/sio-go"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+const (
+	cseVersion = "v1"
+
+	cseMetaVersion = "X-Amz-Meta-Mc-Cse"
+	cseMetaKey     = "X-Amz-Meta-Mc-Cse-Key"
+	cseMetaNonce   = "X-Amz-Meta-Mc-Cse-Nonce"
+	cseMetaSize    = "X-Amz-Meta-Mc-Cse-Size"
+
+	// Kinds of wrapped data keys.
+	cseKeyAge  = "age"
+	cseKeyFile = "file"
+)
+
+var errCSENoIdentity = errors.New("object is client-side encrypted, pass --decrypt-with to decrypt it")
+
+// cseEncryptFlag encrypts uploaded data on the client.
+var cseEncryptFlag = cli.StringFlag{
+	Name:  "encrypt-with",
+	Usage: "encrypt data on the client for an age recipient ('age:age1...') or with a 32 bytes hex key file ('file:PATH')",
+}
+
+// cseDecryptFlag decrypts downloaded client-side encrypted data.
+var cseDecryptFlag = cli.StringFlag{
+	Name:   "decrypt-with",
+	Usage:  "decrypt client-side encrypted data with an age identity file ('age:PATH') or a 32 bytes hex key file ('file:PATH')",
+	EnvVar: "MC_DECRYPT_WITH",
+}
+
+// cseKey is a key wrapping the data keys of objects, an age
+// recipient or identities, or a symmetric key.
+type cseKey struct {
+	kind       string
+	recipient  age.Recipient  // only to encrypt
+	identities []age.Identity // only to decrypt
+	secret     []byte         // symmetric key
+}
+
+// parseCSEKey parses the value of --encrypt-with, or of
+// --decrypt-with if decrypt is set.
+func parseCSEKey(spec string, decrypt bool) (*cseKey, *probe.Error) {
+	kind, value, ok := strings.Cut(spec, ":")
+	if !ok || value == "" {
+		return nil, probe.NewError(errors.New("expected 'age:' or 'file:' followed by a value")).Trace(spec)
+	}
+	switch kind {
+	case "age":
+		if !decrypt {
+			recipient, e := age.ParseX25519Recipient(value)
+			if e != nil {
+				return nil, probe.NewError(e).Trace(value)
+			}
+			return &cseKey{kind: cseKeyAge, recipient: recipient}, nil
+		}
+		f, e := os.Open(value)
+		if e != nil {
+			return nil, probe.NewError(e).Trace(value)
+		}
+		defer f.Close()
+		identities, e := age.ParseIdentities(f)
+		if e != nil {
+			return nil, probe.NewError(e).Trace(value)
+		}
+		return &cseKey{kind: cseKeyAge, identities: identities}, nil
+	case "file":
+		data, e := os.ReadFile(value)
+		if e != nil {
+			return nil, probe.NewError(e).Trace(value)
+		}
+		secret, e := hex.DecodeString(strings.TrimSpace(string(data)))
+		if e != nil || len(secret) != 32 {
+			return nil, probe.NewError(errors.New("key file should contain 32 hex encoded bytes")).Trace(value)
+		}
+		return &cseKey{kind: cseKeyFile, secret: secret}, nil
+	}
+	return nil, probe.NewError(fmt.Errorf("unknown key type `%s`", kind)).Trace(spec)
+}
+
+// wrap encrypts the data key of an object. An age wrapped key is
+// a binary age file, it can be decrypted with the age tool.
+func (k *cseKey) wrap(dataKey []byte) (string, error) {
+	switch k.kind {
+	case cseKeyAge:
+		var sealed bytes.Buffer
+		w, e := age.Encrypt(&sealed, k.recipient)
+		if e != nil {
+			return "", e
+		}
+		if _, e = w.Write(dataKey); e != nil {
+			return "", e
+		}
+		if e = w.Close(); e != nil {
+			return "", e
+		}
+		return cseKeyAge + ":" + base64.StdEncoding.EncodeToString(sealed.Bytes()), nil
+	default:
+		aead, e := newGCM(k.secret)
+		if e != nil {
+			return "", e
+		}
+		nonce := make([]byte, aead.NonceSize())
+		if _, e = rand.Read(nonce); e != nil {
+			return "", e
+		}
+		sealed := aead.Seal(nonce, nonce, dataKey, nil)
+		return cseKeyFile + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+	}
+}
+
+// unwrap decrypts the data key of an object.
+func (k *cseKey) unwrap(wrapped string) ([]byte, error) {
+	kind, value, _ := strings.Cut(wrapped, ":")
+	if kind != k.kind {
+		return nil, fmt.Errorf("object is encrypted with a `%s` key", kind)
+	}
+	sealed, e := base64.StdEncoding.DecodeString(value)
+	if e != nil {
+		return nil, e
+	}
+	switch kind {
+	case cseKeyAge:
+		r, e := age.Decrypt(bytes.NewReader(sealed), k.identities...)
+		if e != nil {
+			return nil, e
+		}
+		dataKey, e := io.ReadAll(io.LimitReader(r, 33))
+		if e != nil {
+			return nil, e
+		}
+		if len(dataKey) != 32 {
+			return nil, errors.New("malformed wrapped key")
+		}
+		return dataKey, nil
+	default:
+		aead, e := newGCM(k.secret)
+		if e != nil {
+			return nil, e
+		}
+		if len(sealed) < aead.NonceSize() {
+			return nil, errors.New("malformed wrapped key")
+		}
+		return aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	}
+}
+
+// newGCM returns AES-256-GCM keyed with key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, e := aes.NewCipher(key)
+	if e != nil {
+		return nil, e
+	}
+	return cipher.NewGCM(block)
+}
+
+// cseAssociatedData returns the envelope fields authenticated
+// with the data of an object.
+func cseAssociatedData(kind string, nonce []byte, size int64) []byte {
+	return []byte(strings.Join([]string{cseVersion, kind, base64.StdEncoding.EncodeToString(nonce), strconv.FormatInt(size, 10)}, "\n"))
+}
+
+// cseEncrypt returns a reader encrypting the size bytes of r, the
+// size of the ciphertext and the metadata to store with the object.
+func cseEncrypt(r io.Reader, size int64, key *cseKey) (io.Reader, int64, map[string]string, *probe.Error) {
+	dataKey := make([]byte, 32)
+	if _, e := rand.Read(dataKey); e != nil {
+		return nil, 0, nil, probe.NewError(e)
+	}
+	aead, e := newGCM(dataKey)
+	if e != nil {
+		return nil, 0, nil, probe.NewError(e)
+	}
+	stream := sio.NewStream(aead, sio.BufSize)
+	nonce := make([]byte, stream.NonceSize())
+	if _, e = rand.Read(nonce); e != nil {
+		return nil, 0, nil, probe.NewError(e)
+	}
+	wrapped, e := key.wrap(dataKey)
+	if e != nil {
+		return nil, 0, nil, probe.NewError(e)
+	}
+	metadata := map[string]string{
+		cseMetaVersion: cseVersion,
+		cseMetaKey:     wrapped,
+		cseMetaNonce:   base64.StdEncoding.EncodeToString(nonce),
+		cseMetaSize:    strconv.FormatInt(size, 10),
+	}
+	aad := cseAssociatedData(key.kind, nonce, size)
+	encrypted := stream.EncryptReader(io.LimitReader(r, size), nonce, aad)
+	return encrypted, size + stream.Overhead(size), metadata, nil
+}
+
+// cseEnvelope is the encryption metadata of a client-side encrypted object.
+type cseEnvelope struct {
+	wrappedKey string
+	nonce      []byte
+	size       int64
+}
+
+// getCSEEnvelope returns the encryption metadata of an object,
+// or nil if the object is not client-side encrypted.
+func getCSEEnvelope(metadata map[string]string) (*cseEnvelope, *probe.Error) {
+	get := func(key string) string {
+		for k, v := range metadata {
+			if http.CanonicalHeaderKey(k) == key {
+				return v
+			}
+		}
+		return ""
+	}
+	switch v := get(cseMetaVersion); v {
+	case "":
+		return nil, nil
+	case cseVersion:
+	default:
+		return nil, probe.NewError(fmt.Errorf("unsupported client-side encryption version `%s`", v))
+	}
+	nonce, e := base64.StdEncoding.DecodeString(get(cseMetaNonce))
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	size, e := strconv.ParseInt(get(cseMetaSize), 10, 64)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	return &cseEnvelope{wrappedKey: get(cseMetaKey), nonce: nonce, size: size}, nil
+}
+
+// decryptReader returns a reader decrypting r with the data key
+// unwrapped by key.
+func (env *cseEnvelope) decryptReader(r io.Reader, key *cseKey) (io.Reader, *probe.Error) {
+	if key == nil {
+		return nil, probe.NewError(errCSENoIdentity)
+	}
+	dataKey, e := key.unwrap(env.wrappedKey)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	aead, e := newGCM(dataKey)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	stream := sio.NewStream(aead, sio.BufSize)
+	if len(env.nonce) != stream.NonceSize() {
+		return nil, probe.NewError(errors.New("malformed stream nonce"))
+	}
+	kind, _, _ := strings.Cut(env.wrappedKey, ":")
+	return stream.DecryptReader(r, env.nonce, cseAssociatedData(kind, env.nonce, env.size)), nil
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+)
+
+func TestCSERoundTrip(t *testing.T) {
+	identity, e := age.GenerateX25519Identity()
+	if e != nil {
+		t.Fatal(e)
+	}
+	recipient := identity.Recipient()
+	secret := make([]byte, 32)
+	rand.Read(secret)
+
+	testCases := []struct {
+		encryptKey *cseKey
+		decryptKey *cseKey
+		size       int
+	}{
+		{&cseKey{kind: cseKeyAge, recipient: recipient}, &cseKey{kind: cseKeyAge, identities: []age.Identity{identity}}, 0},
+		{&cseKey{kind: cseKeyAge, recipient: recipient}, &cseKey{kind: cseKeyAge, identities: []age.Identity{identity}}, 1 << 20},
+		{&cseKey{kind: cseKeyFile, secret: secret}, &cseKey{kind: cseKeyFile, secret: secret}, 100000},
+	}
+
+	for i, testCase := range testCases {
+		plaintext := make([]byte, testCase.size)
+		rand.Read(plaintext)
+
+		encrypted, size, metadata, err := cseEncrypt(bytes.NewReader(plaintext), int64(testCase.size), testCase.encryptKey)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		ciphertext, e := io.ReadAll(encrypted)
+		if e != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, e)
+		}
+		if int64(len(ciphertext)) != size {
+			t.Fatalf("Test %d: expected %d bytes of ciphertext, got %d", i+1, size, len(ciphertext))
+		}
+
+		envelope, err := getCSEEnvelope(metadata)
+		if err != nil || envelope == nil {
+			t.Fatalf("Test %d: unable to read envelope: %v", i+1, err)
+		}
+		if envelope.size != int64(testCase.size) {
+			t.Fatalf("Test %d: expected size %d, got %d", i+1, testCase.size, envelope.size)
+		}
+		decrypted, err := envelope.decryptReader(bytes.NewReader(ciphertext), testCase.decryptKey)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		got, e := io.ReadAll(decrypted)
+		if e != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, e)
+		}
+		if !bytes.Equal(got, plaintext) {
+			t.Fatalf("Test %d: decrypted data does not match", i+1)
+		}
+	}
+}
+
+func TestCSEAgeWrappedKey(t *testing.T) {
+	identity, e := age.GenerateX25519Identity()
+	if e != nil {
+		t.Fatal(e)
+	}
+	key := &cseKey{kind: cseKeyAge, recipient: identity.Recipient()}
+	dataKey := bytes.Repeat([]byte{0x42}, 32)
+	wrapped, e := key.wrap(dataKey)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	// The wrapped key is a plain age file.
+	sealed, e := base64.StdEncoding.DecodeString(strings.TrimPrefix(wrapped, cseKeyAge+":"))
+	if e != nil {
+		t.Fatal(e)
+	}
+	r, e := age.Decrypt(bytes.NewReader(sealed), identity)
+	if e != nil {
+		t.Fatal(e)
+	}
+	got, e := io.ReadAll(r)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if !bytes.Equal(got, dataKey) {
+		t.Fatalf("expected %x, got %x", dataKey, got)
+	}
+}
+
+func TestCSETamperedEnvelope(t *testing.T) {
+	secret := make([]byte, 32)
+	rand.Read(secret)
+	key := &cseKey{kind: cseKeyFile, secret: secret}
+	plaintext := make([]byte, 1000)
+	rand.Read(plaintext)
+
+	testCases := []struct {
+		key   string
+		value func(string) string
+	}{
+		// Untouched envelope.
+		{"", nil},
+		// Truncation hidden by a smaller size.
+		{cseMetaSize, func(string) string { return "10" }},
+		// Another nonce.
+		{cseMetaNonce, func(v string) string {
+			nonce, _ := base64.StdEncoding.DecodeString(v)
+			nonce[0] ^= 1
+			return base64.StdEncoding.EncodeToString(nonce)
+		}},
+	}
+
+	for i, testCase := range testCases {
+		encrypted, _, metadata, err := cseEncrypt(bytes.NewReader(plaintext), int64(len(plaintext)), key)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		ciphertext, e := io.ReadAll(encrypted)
+		if e != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, e)
+		}
+		if testCase.value != nil {
+			metadata[testCase.key] = testCase.value(metadata[testCase.key])
+		}
+		envelope, err := getCSEEnvelope(metadata)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		decrypted, err := envelope.decryptReader(bytes.NewReader(ciphertext), key)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		_, e = io.ReadAll(decrypted)
+		if testCase.value == nil && e != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, e)
+		}
+		if testCase.value != nil && e == nil {
+			t.Fatalf("Test %d: expected tampered %s to fail the decryption", i+1, testCase.key)
+		}
+	}
+}
@@ -0,0 +1,652 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	bolt "go.etcd.io/bbolt"
+)
+
+// States of a daemon job.
+const (
+	jobQueued    = "queued"
+	jobRunning   = "running"
+	jobSucceeded = "succeeded"
+	jobFailed    = "failed"
+	jobCanceled  = "canceled"
+)
+
+// daemonJobsDB is the bolt database of the configuration folder
+// where the daemon persists its jobs, in the daemonJobsBucket bucket
+// keyed by job ID.
+const daemonJobsDB = "daemon-jobs.db"
+
+var daemonJobsBucket = []byte("jobs")
+
+// maxJobBackoff bounds the wait between two attempts of a job.
+const maxJobBackoff = time.Hour
+
+// daemonCommands are the commands which can be submitted to the daemon.
+var daemonCommands = map[string]bool{
+	"cp":     true,
+	"mirror": true,
+	"rm":     true,
+}
+
+// daemonJobRequest is the body of a job submission. Jobs of higher
+// priority run first. A failed job is attempted again up to Retries
+// times, waiting Backoff, doubled on every retry, in between.
+type daemonJobRequest struct {
+	Command  string   `json:"command"`
+	Args     []string `json:"args"`
+	Priority int      `json:"priority,omitempty"`
+	Retries  int      `json:"retries,omitempty"`
+	Backoff  string   `json:"backoff,omitempty"`
+}
+
+// daemonJobProgress counts the objects handled by the last attempt of
+// a job. The totals are only known to commands reporting them, such
+// as cp.
+type daemonJobProgress struct {
+	Objects      int64 `json:"objects"`
+	Bytes        int64 `json:"bytes"`
+	Errors       int64 `json:"errors"`
+	TotalObjects int64 `json:"totalObjects,omitempty"`
+	TotalBytes   int64 `json:"totalBytes,omitempty"`
+}
+
+// daemonJobStatus is the state of a job as returned by the API
+// and persisted by the daemon.
+type daemonJobStatus struct {
+	ID          string            `json:"id"`
+	Command     string            `json:"command"`
+	Args        []string          `json:"args"`
+	Priority    int               `json:"priority"`
+	Retries     int               `json:"retries"`
+	Backoff     string            `json:"backoff,omitempty"`
+	State       string            `json:"state"`
+	Attempts    int               `json:"attempts"`
+	Submitted   time.Time         `json:"submitted"`
+	Started     *time.Time        `json:"started,omitempty"`
+	Finished    *time.Time        `json:"finished,omitempty"`
+	NextAttempt *time.Time        `json:"nextAttempt,omitempty"`
+	ExitCode    *int              `json:"exitCode,omitempty"`
+	Progress    daemonJobProgress `json:"progress"`
+	LastError   string            `json:"lastError,omitempty"`
+}
+
+// runnable returns true if the job waits to run at now.
+func (s daemonJobStatus) runnable(now time.Time) bool {
+	return s.State == jobQueued && (s.NextAttempt == nil || !s.NextAttempt.After(now))
+}
+
+// backoff returns the wait before the next attempt of the job.
+func (s daemonJobStatus) backoff() time.Duration {
+	backoff, e := ParseDuration(s.Backoff)
+	if e != nil || backoff <= 0 {
+		return 0
+	}
+	wait := time.Duration(backoff)
+	for i := 1; i < s.Attempts && wait < maxJobBackoff; i++ {
+		wait *= 2
+	}
+	if wait > maxJobBackoff {
+		wait = maxJobBackoff
+	}
+	return wait
+}
+
+// daemonJob is a command run by the daemon as a child mc process,
+// whose JSON output is followed to report its progress.
+type daemonJob struct {
+	mu       sync.Mutex
+	status   daemonJobStatus
+	process  *os.Process
+	canceled bool
+
+	// interrupted is set when the daemon stops while the job runs.
+	interrupted bool
+}
+
+// snapshot returns a copy of the status of the job.
+func (j *daemonJob) snapshot() daemonJobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	status := j.status
+	status.Args = append([]string(nil), j.status.Args...)
+	return status
+}
+
+// cancel stops the job: a queued job never starts, a running one is
+// interrupted like with CTRL-C. It returns false if the job is done.
+func (j *daemonJob) cancel() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	switch j.status.State {
+	case jobQueued:
+		j.end(jobCanceled, nil, "")
+	case jobRunning:
+		if !j.canceled {
+			j.canceled = true
+			if j.process != nil {
+				interruptProcess(j.process)
+			}
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// retry queues a failed or canceled job again, returning
+// false if the job is not in one of these states.
+func (j *daemonJob) retry() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	switch j.status.State {
+	case jobFailed, jobCanceled:
+	default:
+		return false
+	}
+	j.status.State = jobQueued
+	j.status.Attempts = 0
+	j.status.Started, j.status.Finished, j.status.NextAttempt = nil, nil, nil
+	j.status.ExitCode = nil
+	j.status.Progress = daemonJobProgress{}
+	j.status.LastError = ""
+	return true
+}
+
+// interruptProcess asks p to stop, killing it where interrupts are
+// not supported.
+func interruptProcess(p *os.Process) {
+	if runtime.GOOS == "windows" || p.Signal(os.Interrupt) != nil {
+		p.Kill()
+	}
+}
+
+// daemonOutputMessage holds the fields of the JSON messages of cp,
+// mirror and rm used to follow their progress.
+type daemonOutputMessage struct {
+	Status     string `json:"status"`
+	Source     string `json:"source"`
+	Key        string `json:"key"`
+	Size       int64  `json:"size"`
+	TotalCount int64  `json:"totalCount"`
+	TotalSize  int64  `json:"totalSize"`
+	Error      *struct {
+		Message string `json:"message"`
+		Cause   struct {
+			Message string `json:"message"`
+		} `json:"cause"`
+	} `json:"error"`
+}
+
+// record updates the progress of the job with one output message.
+func (j *daemonJob) record(msg daemonOutputMessage) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	switch {
+	case msg.Status == "error" && msg.Error != nil:
+		j.status.Progress.Errors++
+		j.status.LastError = msg.Error.Message
+		if msg.Error.Cause.Message != "" {
+			j.status.LastError += " " + msg.Error.Cause.Message
+		}
+	case msg.Status == "success" && (msg.Source != "" || msg.Key != ""):
+		j.status.Progress.Objects++
+		j.status.Progress.Bytes += msg.Size
+		if msg.TotalCount > 0 {
+			j.status.Progress.TotalObjects = msg.TotalCount
+		}
+		if msg.TotalSize > 0 {
+			j.status.Progress.TotalBytes = msg.TotalSize
+		}
+	}
+}
+
+// follow reads the JSON output of the job, indented messages
+// spanning several lines, and records every message. Lines which
+// are not JSON are skipped.
+func (j *daemonJob) follow(r io.Reader) {
+	const maxMessageSize = 1 << 20
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), maxMessageSize)
+	var buf bytes.Buffer
+	for scanner.Scan() {
+		line := scanner.Text()
+		if buf.Len() == 0 && !strings.HasPrefix(line, "{") {
+			continue
+		}
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+		if !strings.HasSuffix(strings.TrimSpace(line), "}") || !json.Valid(buf.Bytes()) {
+			if buf.Len() > maxMessageSize {
+				buf.Reset()
+			}
+			continue
+		}
+		var msg daemonOutputMessage
+		if json.Unmarshal(buf.Bytes(), &msg) == nil {
+			j.record(msg)
+		}
+		buf.Reset()
+	}
+	// Drain the output should a line be too long to be scanned.
+	io.Copy(io.Discard, r)
+}
+
+// start marks the job as running if it is still queued, returning
+// false otherwise.
+func (j *daemonJob) start() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.status.State != jobQueued {
+		return false
+	}
+	j.status.State = jobRunning
+	j.canceled, j.interrupted = false, false
+	return true
+}
+
+// run makes one attempt of the started job with the mc binary
+// mcPath and configDir as configuration folder.
+func (j *daemonJob) run(mcPath, configDir string) {
+	j.mu.Lock()
+	args := append([]string{j.status.Command, "--json", "--config-dir", configDir}, j.status.Args...)
+	cmd := exec.Command(mcPath, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &limitedBuffer{buf: &stderr, limit: 64 << 10}
+	stdout, e := cmd.StdoutPipe()
+	if e == nil {
+		e = cmd.Start()
+	}
+	started := time.Now().UTC()
+	j.status.Attempts++
+	j.status.Started = &started
+	j.status.Finished, j.status.NextAttempt, j.status.ExitCode = nil, nil, nil
+	j.status.Progress = daemonJobProgress{}
+	j.status.LastError = ""
+	if e != nil {
+		j.end(jobFailed, nil, e.Error())
+		j.mu.Unlock()
+		return
+	}
+	j.process = cmd.Process
+	if j.canceled || j.interrupted {
+		// Canceled while starting.
+		interruptProcess(j.process)
+	}
+	j.mu.Unlock()
+
+	j.follow(stdout)
+	e = cmd.Wait()
+
+	exitCode := 0
+	var exitErr *exec.ExitError
+	switch {
+	case e == nil:
+	case errors.As(e, &exitErr):
+		exitCode = exitErr.ExitCode()
+	default:
+		exitCode = -1
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	switch {
+	case j.interrupted:
+		// Run the attempt again when the daemon restarts.
+		j.status.State = jobQueued
+		j.status.Attempts--
+		j.status.LastError = "interrupted by the stop of the daemon"
+		j.process = nil
+	case j.canceled:
+		j.end(jobCanceled, &exitCode, "")
+	case e != nil && exitErr == nil:
+		j.end(jobFailed, nil, e.Error())
+	case exitCode != 0:
+		j.end(jobFailed, &exitCode, strings.TrimSpace(stderr.String()))
+	default:
+		j.end(jobSucceeded, &exitCode, "")
+	}
+}
+
+// end records the end of an attempt, queuing the job again if it
+// failed and has retries left. errMsg, if not empty, replaces the
+// last error unless the output already reported one. The job must
+// be locked.
+func (j *daemonJob) end(state string, exitCode *int, errMsg string) {
+	finished := time.Now().UTC()
+	j.status.State = state
+	j.status.Finished = &finished
+	j.status.ExitCode = exitCode
+	j.process = nil
+	if errMsg != "" && j.status.LastError == "" {
+		j.status.LastError = errMsg
+	}
+	if state == jobFailed && j.status.Attempts <= j.status.Retries {
+		next := finished.Add(j.status.backoff())
+		j.status.State = jobQueued
+		j.status.NextAttempt = &next
+	}
+}
+
+// limitedBuffer keeps the first limit bytes written to it.
+type limitedBuffer struct {
+	buf   *bytes.Buffer
+	limit int
+}
+
+func (l *limitedBuffer) Write(p []byte) (int, error) {
+	if n := l.limit - l.buf.Len(); n > 0 {
+		if len(p) < n {
+			n = len(p)
+		}
+		l.buf.Write(p[:n])
+	}
+	return len(p), nil
+}
+
+// daemonJobs is the queue of the jobs of the daemon. It runs up to
+// maxJobs jobs at a time, by priority then order of submission, and
+// persists every job to a bolt database so that jobs survive restarts
+// of the daemon.
+type daemonJobs struct {
+	mu        sync.Mutex
+	jobs      map[string]*daemonJob
+	maxJobs   int
+	running   int
+	wakeCh    chan struct{}
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+	mcPath    string
+	configDir string
+	db        *bolt.DB
+}
+
+// newDaemonJobs returns the queue of jobs persisted in the database
+// dbPath, loading the jobs of a previous daemon. Jobs interrupted
+// while running are queued again.
+func newDaemonJobs(maxJobs int, mcPath, configDir, dbPath string) (*daemonJobs, *probe.Error) {
+	// The database is locked while open, fail instead of waiting
+	// for another daemon to stop.
+	db, e := bolt.Open(dbPath, 0o600, &bolt.Options{Timeout: time.Second})
+	if e != nil {
+		return nil, probe.NewError(e).Trace(dbPath)
+	}
+	d := &daemonJobs{
+		jobs:      make(map[string]*daemonJob),
+		maxJobs:   maxJobs,
+		wakeCh:    make(chan struct{}, 1),
+		stopCh:    make(chan struct{}),
+		mcPath:    mcPath,
+		configDir: configDir,
+		db:        db,
+	}
+	e = db.Update(func(tx *bolt.Tx) error {
+		bucket, e := tx.CreateBucketIfNotExists(daemonJobsBucket)
+		if e != nil {
+			return e
+		}
+		return bucket.ForEach(func(id, data []byte) error {
+			job := &daemonJob{}
+			if e := json.Unmarshal(data, &job.status); e != nil {
+				return fmt.Errorf("job `%s`: %w", id, e)
+			}
+			if job.status.State == jobRunning {
+				job.status.State = jobQueued
+				job.status.LastError = "interrupted by the restart of the daemon"
+			}
+			d.jobs[job.status.ID] = job
+			return nil
+		})
+	})
+	if e != nil {
+		db.Close()
+		return nil, probe.NewError(e).Trace(dbPath)
+	}
+	return d, nil
+}
+
+// save persists the job, reporting failures without stopping the daemon.
+// The snapshot is taken in the write transaction, which are serialized,
+// so that the last one written is the most recent.
+func (d *daemonJobs) save(job *daemonJob) {
+	var id string
+	e := d.db.Update(func(tx *bolt.Tx) error {
+		status := job.snapshot()
+		id = status.ID
+		data, e := json.Marshal(status)
+		if e != nil {
+			return e
+		}
+		return tx.Bucket(daemonJobsBucket).Put([]byte(status.ID), data)
+	})
+	errorIf(probe.NewError(e).Trace(id), "Unable to save job `"+id+"`.")
+}
+
+// wake makes the scheduler look for jobs to run.
+func (d *daemonJobs) wake() {
+	select {
+	case d.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+// schedule starts the queued jobs as slots free up, until stop is
+// called.
+func (d *daemonJobs) schedule() {
+	for {
+		next := d.startRunnable()
+		var timer <-chan time.Time
+		if !next.IsZero() {
+			timer = time.After(time.Until(next))
+		}
+		select {
+		case <-d.stopCh:
+			return
+		case <-d.wakeCh:
+		case <-timer:
+		}
+	}
+}
+
+// startRunnable starts the runnable jobs while slots are free and
+// returns the time of the next retry of a queued job, if any.
+func (d *daemonJobs) startRunnable() (next time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	select {
+	case <-d.stopCh:
+		return next
+	default:
+	}
+
+	now := time.Now()
+	var runnable []daemonJobStatus
+	for _, job := range d.jobs {
+		status := job.snapshot()
+		switch {
+		case status.runnable(now):
+			runnable = append(runnable, status)
+		case status.State == jobQueued && (next.IsZero() || status.NextAttempt.Before(next)):
+			next = *status.NextAttempt
+		}
+	}
+	sort.Slice(runnable, func(i, j int) bool {
+		if runnable[i].Priority != runnable[j].Priority {
+			return runnable[i].Priority > runnable[j].Priority
+		}
+		return runnable[i].Submitted.Before(runnable[j].Submitted)
+	})
+
+	for _, status := range runnable {
+		if d.running >= d.maxJobs {
+			break
+		}
+		job := d.jobs[status.ID]
+		if !job.start() {
+			continue
+		}
+		d.running++
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			job.run(d.mcPath, d.configDir)
+			d.save(job)
+			d.mu.Lock()
+			d.running--
+			d.mu.Unlock()
+			d.wake()
+		}()
+	}
+	return next
+}
+
+// stop stops scheduling jobs, interrupts the running ones and waits
+// for them, then closes the database. Interrupted jobs are persisted
+// as queued to run again when the daemon restarts.
+func (d *daemonJobs) stop() {
+	close(d.stopCh)
+	d.mu.Lock()
+	for _, job := range d.jobs {
+		job.mu.Lock()
+		if job.status.State == jobRunning {
+			job.interrupted = true
+			if job.process != nil {
+				interruptProcess(job.process)
+			}
+		}
+		job.mu.Unlock()
+	}
+	d.mu.Unlock()
+	d.wg.Wait()
+	d.db.Close()
+}
+
+// submit validates, persists and queues a new job.
+func (d *daemonJobs) submit(req daemonJobRequest) (daemonJobStatus, error) {
+	if !daemonCommands[req.Command] {
+		return daemonJobStatus{}, fmt.Errorf("unsupported command `%s`, valid commands are cp, mirror and rm", req.Command)
+	}
+	if len(req.Args) == 0 {
+		return daemonJobStatus{}, fmt.Errorf("command `%s` needs arguments", req.Command)
+	}
+	if req.Retries < 0 {
+		return daemonJobStatus{}, fmt.Errorf("retries should not be negative")
+	}
+	if req.Backoff != "" {
+		if _, e := ParseDuration(req.Backoff); e != nil {
+			return daemonJobStatus{}, fmt.Errorf("invalid backoff `%s`", req.Backoff)
+		}
+	}
+	job := &daemonJob{
+		status: daemonJobStatus{
+			ID:        uuid.NewString(),
+			Command:   req.Command,
+			Args:      req.Args,
+			Priority:  req.Priority,
+			Retries:   req.Retries,
+			Backoff:   req.Backoff,
+			State:     jobQueued,
+			Submitted: time.Now().UTC(),
+		},
+	}
+	d.save(job)
+	d.mu.Lock()
+	d.jobs[job.status.ID] = job
+	d.mu.Unlock()
+	d.wake()
+	return job.snapshot(), nil
+}
+
+// get returns the job of the given id, nil if there is none.
+func (d *daemonJobs) get(id string) *daemonJob {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.jobs[id]
+}
+
+// cancel cancels and persists the job, returning false if it is done.
+func (d *daemonJobs) cancel(job *daemonJob) bool {
+	if !job.cancel() {
+		return false
+	}
+	d.save(job)
+	return true
+}
+
+// retry queues the failed or canceled job again.
+func (d *daemonJobs) retry(job *daemonJob) bool {
+	if !job.retry() {
+		return false
+	}
+	d.save(job)
+	d.wake()
+	return true
+}
+
+// remove forgets a job which is done, returning false if it is not.
+func (d *daemonJobs) remove(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	job, ok := d.jobs[id]
+	if !ok {
+		return false
+	}
+	switch job.snapshot().State {
+	case jobQueued, jobRunning:
+		return false
+	}
+	delete(d.jobs, id)
+	e := d.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(daemonJobsBucket).Delete([]byte(id))
+	})
+	errorIf(probe.NewError(e).Trace(id), "Unable to remove job `"+id+"`.")
+	return true
+}
+
+// list returns the status of all the jobs, oldest first.
+func (d *daemonJobs) list() []daemonJobStatus {
+	d.mu.Lock()
+	statuses := make([]daemonJobStatus, 0, len(d.jobs))
+	for _, job := range d.jobs {
+		statuses = append(statuses, job.snapshot())
+	}
+	d.mu.Unlock()
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Submitted.Before(statuses[j].Submitted)
+	})
+	return statuses
+}
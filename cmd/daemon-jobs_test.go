@@ -0,0 +1,141 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDaemonJobFollow(t *testing.T) {
+	output := `{
+ "status": "success",
+ "source": "/data/a.txt",
+ "target": "s3/bucket/a.txt",
+ "size": 10,
+ "totalCount": 2,
+ "totalSize": 30
+}
+mc: <WARNING> not a JSON line
+{"status":"error","error":{"message":"Unable to copy.","cause":{"message":"Access Denied."}}}
+{"status":"success","key":"s3/bucket/b.txt","size":20}
+{"status":"retry","operation":"upload","url":"s3/bucket/c.txt"}
+`
+	job := &daemonJob{}
+	job.follow(strings.NewReader(output))
+
+	status := job.snapshot()
+	expected := daemonJobProgress{Objects: 2, Bytes: 30, Errors: 1, TotalObjects: 2, TotalBytes: 30}
+	if status.Progress != expected {
+		t.Fatalf("expected progress %+v, got %+v", expected, status.Progress)
+	}
+	if status.LastError != "Unable to copy. Access Denied." {
+		t.Fatalf("unexpected last error %q", status.LastError)
+	}
+}
+
+func TestDaemonJobRetries(t *testing.T) {
+	testCases := []struct {
+		backoff  string
+		attempts int
+		retries  int
+		state    string
+		wait     time.Duration
+	}{
+		{"", 1, 0, jobFailed, 0},
+		{"", 1, 1, jobQueued, 0},
+		{"10s", 1, 3, jobQueued, 10 * time.Second},
+		{"10s", 3, 3, jobQueued, 40 * time.Second},
+		{"10s", 4, 3, jobFailed, 0},
+		{"30m", 5, 5, jobQueued, maxJobBackoff},
+	}
+	for i, testCase := range testCases {
+		job := &daemonJob{status: daemonJobStatus{
+			Backoff:  testCase.backoff,
+			Attempts: testCase.attempts,
+			Retries:  testCase.retries,
+		}}
+		job.end(jobFailed, nil, "failed")
+		status := job.snapshot()
+		if status.State != testCase.state {
+			t.Fatalf("Test %d: expected state %s, got %s", i+1, testCase.state, status.State)
+		}
+		if status.State != jobQueued {
+			continue
+		}
+		if wait := status.NextAttempt.Sub(*status.Finished); wait != testCase.wait {
+			t.Fatalf("Test %d: expected a wait of %s, got %s", i+1, testCase.wait, wait)
+		}
+	}
+}
+
+func TestDaemonJobsStore(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), daemonJobsDB)
+	jobs, err := newDaemonJobs(1, "mc", "", dbPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	queued, e := jobs.submit(daemonJobRequest{Command: "cp", Args: []string{"a", "b"}, Priority: 2})
+	if e != nil {
+		t.Fatalf("unexpected error: %v", e)
+	}
+	running, e := jobs.submit(daemonJobRequest{Command: "rm", Args: []string{"c"}})
+	if e != nil {
+		t.Fatalf("unexpected error: %v", e)
+	}
+	done, e := jobs.submit(daemonJobRequest{Command: "mirror", Args: []string{"d", "e"}})
+	if e != nil {
+		t.Fatalf("unexpected error: %v", e)
+	}
+
+	// A daemon killed while a job runs.
+	job := jobs.get(running.ID)
+	job.start()
+	jobs.save(job)
+	jobs.cancel(jobs.get(done.ID))
+	if !jobs.remove(done.ID) {
+		t.Fatalf("expected the canceled job to be removed")
+	}
+
+	// The database is locked by the running daemon.
+	if _, err = newDaemonJobs(1, "mc", "", dbPath); err == nil {
+		t.Fatalf("expected a second daemon to fail opening the jobs")
+	}
+	jobs.stop()
+
+	jobs, err = newDaemonJobs(1, "mc", "", dbPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer jobs.stop()
+	statuses := jobs.list()
+	if len(statuses) != 2 {
+		t.Fatalf("expected 2 jobs, got %d", len(statuses))
+	}
+	for i, expected := range []daemonJobStatus{queued, running} {
+		got := statuses[i]
+		if got.ID != expected.ID || got.Command != expected.Command || got.Priority != expected.Priority || got.State != jobQueued {
+			t.Fatalf("Test %d: expected queued job %+v, got %+v", i+1, expected, got)
+		}
+	}
+	if statuses[1].LastError == "" {
+		t.Fatalf("expected the interrupted job to report the restart")
+	}
+}
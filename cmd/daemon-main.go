@@ -0,0 +1,388 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+var daemonFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "address",
+		Value: "127.0.0.1:9191",
+		Usage: "listen on HOST:PORT, or on a unix socket with 'unix:PATH'",
+	},
+	cli.StringFlag{
+		Name:   "token",
+		Usage:  "require 'Authorization: Bearer TOKEN' on every request, generated if not set on HOST:PORT",
+		EnvVar: "MC_DAEMON_TOKEN",
+	},
+	cli.IntFlag{
+		Name:  "max-jobs",
+		Value: 4,
+		Usage: "number of jobs running at the same time, others are queued",
+	},
+}
+
+// Serve an API to run transfer jobs.
+var daemonCmd = cli.Command{
+	Name:            "daemon",
+	Usage:           "serve a local JSON API to queue, follow and cancel transfer jobs",
+	Action:          mainDaemon,
+	OnUsageError:    onUsageError,
+	Before:          setGlobalsFromContext,
+	Flags:           append(daemonFlags, globalFlags...),
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Every job runs 'mc cp', 'mc mirror' or 'mc rm' with the aliases of this mc, its
+  progress being followed from the JSON output of the command. The API is:
+
+    POST   /v1/jobs            submit a job, e.g. {"command": "cp", "args": ["--recursive", "src/", "s3/bucket"]}
+    GET    /v1/jobs            list all jobs
+    GET    /v1/jobs/ID         show the state and progress of a job
+    POST   /v1/jobs/ID/retry   queue a failed or canceled job again
+    DELETE /v1/jobs/ID         cancel a queued or running job, or forget a job which is done
+
+  A job may also set "priority", jobs of higher priority running first, and
+  "retries" with a "backoff" such as "30s", doubled after every failed attempt.
+
+  Jobs are saved in the configuration folder: stopping the daemon interrupts the
+  running jobs, which run again with the queued ones when the daemon restarts.
+  Jobs are managed from the command line with 'mc jobs'.
+
+  On HOST:PORT, every request needs the token: if --token is not set, a token is
+  generated and saved in 'daemon-token' of the configuration folder, where 'mc jobs'
+  finds it. Requests must have the Host of the listen address, no Origin header,
+  and POST requests the 'application/json' content type, so that web pages cannot
+  submit jobs. A unix socket is only accessible to the user and needs no token.
+
+EXAMPLES:
+  1. Serve the API on the default local port and submit a copy.
+     {{.Prompt}} {{.HelpName}}
+     {{.Prompt}} curl -H "Authorization: Bearer $(cat ~/.mc/daemon-token)" -H 'Content-Type: application/json' \
+         -d '{"command": "cp", "args": ["--recursive", "/data/", "s3/backup"]}' http://127.0.0.1:9191/v1/jobs
+
+  2. Serve the API on a unix socket, running up to 8 jobs at a time.
+     {{.Prompt}} {{.HelpName}} --address unix:/run/mc.sock --max-jobs 8
+
+  3. Serve the API on all interfaces with a token of your own.
+     {{.Prompt}} export MC_DAEMON_TOKEN=$(openssl rand -hex 16)
+     {{.Prompt}} {{.HelpName}} --address 0.0.0.0:9191
+
+  4. Submit a mirror on the unix socket ahead of the queued jobs, retried 3 times a minute apart at first.
+     {{.Prompt}} curl --unix-socket /run/mc.sock -H 'Content-Type: application/json' \
+         -d '{"command": "mirror", "args": ["/data/", "s3/backup"], "priority": 10, "retries": 3, "backoff": "1m"}' http://daemon/v1/jobs
+`,
+}
+
+// daemonTokenFile is the file of the configuration folder holding
+// the token generated by a daemon listening on HOST:PORT.
+const daemonTokenFile = "daemon-token"
+
+// daemonMessage is printed when the daemon is ready.
+type daemonMessage struct {
+	Status    string `json:"status"`
+	Address   string `json:"address"`
+	TokenFile string `json:"tokenFile,omitempty"`
+}
+
+// String colorized daemon message
+func (d daemonMessage) String() string {
+	msg := console.Colorize("Daemon", "Serving the job API on "+d.Address)
+	if d.TokenFile != "" {
+		msg += console.Colorize("Daemon", ", token saved in "+d.TokenFile)
+	}
+	return msg
+}
+
+// JSON jsonified daemon message
+func (d daemonMessage) JSON() string {
+	d.Status = "success"
+	daemonMessageBytes, e := json.MarshalIndent(d, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(daemonMessageBytes)
+}
+
+// checkDaemonSyntax - validate all the passed arguments
+func checkDaemonSyntax(cliCtx *cli.Context) {
+	if cliCtx.Args().Present() {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	if cliCtx.Int("max-jobs") < 1 {
+		fatalIf(errInvalidArgument().Trace(cliCtx.String("max-jobs")), "--max-jobs should be at least 1.")
+	}
+}
+
+// daemonListen listens on address, a unix socket if prefixed by 'unix:'.
+func daemonListen(address string) (net.Listener, *probe.Error) {
+	if path := strings.TrimPrefix(address, "unix:"); path != address {
+		// Remove the socket left over by a previous daemon.
+		if st, e := os.Lstat(path); e == nil && st.Mode()&os.ModeSocket != 0 {
+			os.Remove(path)
+		}
+		l, e := net.Listen("unix", path)
+		if e != nil {
+			return nil, probe.NewError(e).Trace(address)
+		}
+		// Only the user may queue jobs running with its aliases.
+		if e = os.Chmod(path, 0o600); e != nil {
+			l.Close()
+			return nil, probe.NewError(e).Trace(address)
+		}
+		return l, nil
+	}
+	l, e := net.Listen("tcp", address)
+	return l, probe.NewError(e).Trace(address)
+}
+
+// writeDaemonJSON writes v as the JSON response with the given status code.
+func writeDaemonJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", " ")
+	enc.Encode(v)
+}
+
+// writeDaemonError writes an error response.
+func writeDaemonError(w http.ResponseWriter, code int, e error) {
+	writeDaemonJSON(w, code, struct {
+		Error string `json:"error"`
+	}{e.Error()})
+}
+
+// isDaemonUnixAddress returns true if address is a unix socket.
+func isDaemonUnixAddress(address string) bool {
+	return strings.HasPrefix(address, "unix:")
+}
+
+// generateDaemonToken saves a new random token in the configuration folder.
+func generateDaemonToken(configDir string) (token, tokenFile string, err *probe.Error) {
+	buf := make([]byte, 32)
+	if _, e := rand.Read(buf); e != nil {
+		return "", "", probe.NewError(e)
+	}
+	token = hex.EncodeToString(buf)
+	tokenFile = filepath.Join(configDir, daemonTokenFile)
+	if e := os.WriteFile(tokenFile+".tmp", []byte(token), 0o600); e != nil {
+		return "", "", probe.NewError(e).Trace(tokenFile)
+	}
+	if e := os.Rename(tokenFile+".tmp", tokenFile); e != nil {
+		return "", "", probe.NewError(e).Trace(tokenFile)
+	}
+	return token, tokenFile, nil
+}
+
+// isDaemonHostAllowed returns true if host, the Host header of a request,
+// names the listen address: a page served from another domain resolving
+// to this machine (DNS rebinding) is refused.
+func isDaemonHostAllowed(host, address string) bool {
+	if isDaemonUnixAddress(address) {
+		return true
+	}
+	hostname, port, e := net.SplitHostPort(host)
+	if e != nil {
+		return false
+	}
+	listenHost, listenPort, e := net.SplitHostPort(address)
+	if e != nil || port != listenPort {
+		return false
+	}
+	if strings.EqualFold(hostname, listenHost) {
+		return true
+	}
+	listenIP := net.ParseIP(listenHost)
+	switch {
+	case listenHost == "" || (listenIP != nil && listenIP.IsUnspecified()):
+		// Listening on all interfaces: any address of this machine.
+		return strings.EqualFold(hostname, "localhost") || net.ParseIP(hostname) != nil
+	case listenIP != nil && listenIP.IsLoopback():
+		ip := net.ParseIP(hostname)
+		return strings.EqualFold(hostname, "localhost") || (ip != nil && ip.IsLoopback())
+	}
+	return false
+}
+
+// checkDaemonRequest refuses requests a web page could send to the
+// daemon, returning the status code and error to respond with.
+func checkDaemonRequest(r *http.Request, address string) (int, error) {
+	if r.Header.Get("Origin") != "" {
+		return http.StatusForbidden, errors.New("cross-origin requests are not allowed")
+	}
+	if !isDaemonHostAllowed(r.Host, address) {
+		return http.StatusForbidden, fmt.Errorf("host `%s` not allowed", r.Host)
+	}
+	if r.Method == http.MethodPost {
+		mediaType, _, e := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if e != nil || mediaType != "application/json" {
+			return http.StatusUnsupportedMediaType, errors.New("content type must be application/json")
+		}
+	}
+	return 0, nil
+}
+
+// daemonHandler returns the handler of the job API of jobs listening on
+// address, requiring token if not empty.
+func daemonHandler(jobs *daemonJobs, address, token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/jobs", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeDaemonJSON(w, http.StatusOK, jobs.list())
+		case http.MethodPost:
+			var req daemonJobRequest
+			if e := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); e != nil {
+				writeDaemonError(w, http.StatusBadRequest, e)
+				return
+			}
+			status, e := jobs.submit(req)
+			if e != nil {
+				writeDaemonError(w, http.StatusBadRequest, e)
+				return
+			}
+			writeDaemonJSON(w, http.StatusCreated, status)
+		default:
+			writeDaemonError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		}
+	})
+	mux.HandleFunc("/v1/jobs/", func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/v1/jobs/")
+		id := strings.TrimSuffix(path, "/retry")
+		retry := id != path
+		job := jobs.get(id)
+		if job == nil {
+			writeDaemonError(w, http.StatusNotFound, fmt.Errorf("no job `%s`", id))
+			return
+		}
+		if retry {
+			if r.Method != http.MethodPost {
+				writeDaemonError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+				return
+			}
+			if !jobs.retry(job) {
+				writeDaemonError(w, http.StatusConflict, fmt.Errorf("job `%s` is not failed or canceled", id))
+				return
+			}
+			writeDaemonJSON(w, http.StatusAccepted, job.snapshot())
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeDaemonJSON(w, http.StatusOK, job.snapshot())
+		case http.MethodDelete:
+			if jobs.cancel(job) {
+				writeDaemonJSON(w, http.StatusAccepted, job.snapshot())
+				return
+			}
+			jobs.remove(id)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeDaemonError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		}
+	})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if code, e := checkDaemonRequest(r, address); e != nil {
+			writeDaemonError(w, code, e)
+			return
+		}
+		if token != "" {
+			auth := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !hmac.Equal([]byte(auth), []byte(token)) {
+				writeDaemonError(w, http.StatusUnauthorized, errors.New("invalid or missing token"))
+				return
+			}
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// mainDaemon is the entry point for daemon command.
+func mainDaemon(cliCtx *cli.Context) error {
+	checkDaemonSyntax(cliCtx)
+
+	// Additional command specific theme customization.
+	console.SetColor("Daemon", color.New(color.FgGreen, color.Bold))
+
+	mcPath, e := os.Executable()
+	if e != nil {
+		mcPath = os.Args[0]
+	}
+	configDir := mustGetMcConfigDir()
+	dbPath := filepath.Join(configDir, daemonJobsDB)
+	jobs, err := newDaemonJobs(cliCtx.Int("max-jobs"), mcPath, configDir, dbPath)
+	fatalIf(err, "Unable to load the jobs of `"+dbPath+"`.")
+
+	address := cliCtx.String("address")
+	token := cliCtx.String("token")
+	var tokenFile string
+	if token == "" && !isDaemonUnixAddress(address) {
+		token, tokenFile, err = generateDaemonToken(configDir)
+		fatalIf(err, "Unable to generate a token for the job API.")
+	}
+
+	listener, err := daemonListen(address)
+	fatalIf(err, "Unable to listen on `"+address+"`.")
+
+	server := &http.Server{
+		Handler:           daemonHandler(jobs, address, token),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-globalContext.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(ctx)
+	}()
+
+	go jobs.schedule()
+
+	printMsg(daemonMessage{Address: address, TokenFile: tokenFile})
+	if e = server.Serve(listener); e != nil && !errors.Is(e, http.ErrServerClosed) {
+		errorIf(probe.NewError(e).Trace(address), "Unable to serve the job API.")
+	}
+	jobs.stop()
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCheckDaemonRequest(t *testing.T) {
+	testCases := []struct {
+		address     string
+		method      string
+		host        string
+		contentType string
+		origin      string
+		expected    int
+	}{
+		{"127.0.0.1:9191", http.MethodPost, "127.0.0.1:9191", "application/json", "", 0},
+		{"127.0.0.1:9191", http.MethodPost, "localhost:9191", "application/json; charset=utf-8", "", 0},
+		{"127.0.0.1:9191", http.MethodGet, "[::1]:9191", "", "", 0},
+		{"127.0.0.1:9191", http.MethodPost, "127.0.0.1:9191", "text/plain", "", http.StatusUnsupportedMediaType},
+		{"127.0.0.1:9191", http.MethodPost, "127.0.0.1:9191", "", "", http.StatusUnsupportedMediaType},
+		{"127.0.0.1:9191", http.MethodPost, "127.0.0.1:9191", "application/json", "http://evil.example", http.StatusForbidden},
+		{"127.0.0.1:9191", http.MethodGet, "evil.example:9191", "", "", http.StatusForbidden},
+		{"127.0.0.1:9191", http.MethodGet, "127.0.0.1:9000", "", "", http.StatusForbidden},
+		{"127.0.0.1:9191", http.MethodGet, "127.0.0.1", "", "", http.StatusForbidden},
+		{"0.0.0.0:9191", http.MethodGet, "192.168.1.10:9191", "", "", 0},
+		{"0.0.0.0:9191", http.MethodGet, "evil.example:9191", "", "", http.StatusForbidden},
+		{"daemon.lan:9191", http.MethodGet, "daemon.lan:9191", "", "", 0},
+		{"unix:/run/mc.sock", http.MethodGet, "daemon", "", "", 0},
+		{"unix:/run/mc.sock", http.MethodPost, "daemon", "text/plain", "", http.StatusUnsupportedMediaType},
+	}
+
+	for i, testCase := range testCases {
+		r := httptest.NewRequest(testCase.method, "/v1/jobs", strings.NewReader("{}"))
+		r.Host = testCase.host
+		if testCase.contentType != "" {
+			r.Header.Set("Content-Type", testCase.contentType)
+		}
+		if testCase.origin != "" {
+			r.Header.Set("Origin", testCase.origin)
+		}
+		code, e := checkDaemonRequest(r, testCase.address)
+		if code != testCase.expected {
+			t.Fatalf("Test %d: expected status %d, got %d (%v)", i+1, testCase.expected, code, e)
+		}
+	}
+}
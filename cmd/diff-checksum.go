@@ -0,0 +1,80 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"regexp"
+
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+// Modes of 'diff --compare'.
+const (
+	diffCompareSize     = "size"
+	diffCompareChecksum = "checksum"
+)
+
+// multipartETagRegex matches the ETag of an object uploaded in
+// several parts without encryption, the MD5 sum of the MD5 sums of
+// its parts followed by the number of parts.
+var multipartETagRegex = regexp.MustCompile("^[0-9a-f]{32}-[0-9]+$")
+
+// compareETags compares two ETags of unencrypted objects of the same
+// size. Different MD5 sums are different contents, the same multipart
+// ETag is the same content uploaded in the same parts. Otherwise, the
+// ETags cannot tell and ok is false.
+func compareETags(first, second string) (equal, ok bool) {
+	switch {
+	case md5ETagRegex.MatchString(first) && md5ETagRegex.MatchString(second):
+		return first == second, true
+	case multipartETagRegex.MatchString(first) && first == second:
+		return true, true
+	}
+	return false, false
+}
+
+// diffContents compares the contents of two objects of the same size,
+// from their ETags when they are enough, by hashing both otherwise.
+func diffContents(ctx context.Context, firstAlias string, first *ClientContent, secondAlias string, second *ClientContent,
+	encKeyDB map[string][]prefixSSEPair,
+) (method string, equal bool, err *probe.Error) {
+	firstPath := filepath.ToSlash(filepath.Join(firstAlias, first.URL.Path))
+	secondPath := filepath.ToSlash(filepath.Join(secondAlias, second.URL.Path))
+	firstSSE := getSSE(firstPath, encKeyDB[firstAlias])
+	secondSSE := getSSE(secondPath, encKeyDB[secondAlias])
+
+	// ETags of encrypted objects are not MD5 sums.
+	if firstSSE == nil && secondSSE == nil {
+		if equal, ok := compareETags(first.ETag, second.ETag); ok {
+			return verifyMethodETag, equal, nil
+		}
+	}
+
+	firstSum, err := hashObject(ctx, firstAlias, first.URL.String(), GetOptions{SSE: firstSSE})
+	if err != nil {
+		return "", false, err.Trace(firstPath)
+	}
+	secondSum, err := hashObject(ctx, secondAlias, second.URL.String(), GetOptions{SSE: secondSSE})
+	if err != nil {
+		return "", false, err.Trace(secondPath)
+	}
+	return verifyMethodHash, bytes.Equal(firstSum, secondSum), nil
+}
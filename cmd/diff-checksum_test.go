@@ -0,0 +1,49 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import "testing"
+
+func TestCompareETags(t *testing.T) {
+	const (
+		md5A       = "d41d8cd98f00b204e9800998ecf8427e"
+		md5B       = "0cc175b9c0f1b6a831c399e269772661"
+		multipartA = "d41d8cd98f00b204e9800998ecf8427e-3"
+		multipartB = "0cc175b9c0f1b6a831c399e269772661-3"
+	)
+	testCases := []struct {
+		first, second string
+		equal, ok     bool
+	}{
+		{md5A, md5A, true, true},
+		{md5A, md5B, false, true},
+		{multipartA, multipartA, true, true},
+		// Same content uploaded in other parts, or not.
+		{multipartA, multipartB, false, false},
+		{md5A, multipartA, false, false},
+		{"", "", false, false},
+		{"not-an-md5", "not-an-md5", false, false},
+	}
+
+	for i, testCase := range testCases {
+		equal, ok := compareETags(testCase.first, testCase.second)
+		if equal != testCase.equal || ok != testCase.ok {
+			t.Errorf("Test %d: expected (%v, %v), got (%v, %v)", i+1, testCase.equal, testCase.ok, equal, ok)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"strings"
+
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+// Reconciliation actions emitted by 'diff --generate-commands'.
+const (
+	diffActionCopy   = "cp"
+	diffActionRemove = "rm"
+	diffActionManual = "manual"
+)
+
+// diffActionMessage is one step of the plan bringing the target in
+// sync with the source, printed as a shell command or an NDJSON line.
+type diffActionMessage struct {
+	Status string `json:"status"`
+	Action string `json:"action"`
+	Source string `json:"source,omitempty"`
+	Target string `json:"target"`
+	Reason string `json:"reason"`
+}
+
+// String returns the shell command for the action.
+func (a diffActionMessage) String() string {
+	switch a.Action {
+	case diffActionCopy:
+		return "mc cp " + shellSingleQuote(a.Source) + " " + shellSingleQuote(a.Target)
+	case diffActionRemove:
+		return "mc rm " + shellSingleQuote(a.Target)
+	}
+	return "# " + shellSingleQuote(a.Target) + " differs in " + a.Reason + ", reconcile it manually"
+}
+
+// JSON returns the action as a single line of an NDJSON plan.
+func (a diffActionMessage) JSON() string {
+	a.Status = "success"
+	jsonMessageBytes, e := json.Marshal(a)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// shellSingleQuote quotes s for a POSIX shell. Unlike shellQuote, it also
+// protects glob characters, object names are passed through verbatim.
+func shellSingleQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// diffAction returns the action reconciling one difference. The URLs of
+// the difference are expanded, they are rewritten relative to the folders
+// given on the command line so that the commands use the same aliases.
+func diffAction(d diffMessage, firstArg, firstURL, secondArg, secondURL string) (diffActionMessage, bool) {
+	source := firstArg + strings.TrimPrefix(d.FirstURL, firstURL)
+	target := secondArg + strings.TrimPrefix(d.SecondURL, secondURL)
+	switch d.Diff {
+	case differInFirst:
+		// Only the source exists, the target takes the same relative name.
+		return diffActionMessage{
+			Action: diffActionCopy,
+			Source: source,
+			Target: secondArg + strings.TrimPrefix(d.FirstURL, firstURL),
+			Reason: d.Diff.String(),
+		}, true
+	case differInSecond:
+		return diffActionMessage{
+			Action: diffActionRemove,
+			Target: target,
+			Reason: d.Diff.String(),
+		}, true
+	case differInSize, differInMetadata, differInAASourceMTime, differInContent:
+		return diffActionMessage{
+			Action: diffActionCopy,
+			Source: source,
+			Target: target,
+			Reason: d.Diff.String(),
+		}, true
+	case differInType:
+		return diffActionMessage{
+			Action: diffActionManual,
+			Source: source,
+			Target: target,
+			Reason: d.Diff.String(),
+		}, true
+	}
+	return diffActionMessage{}, false
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import "testing"
+
+func TestShellQuote(t *testing.T) {
+	testCases := []struct {
+		in       string
+		expected string
+	}{
+		{"s3/bucket/a", "'s3/bucket/a'"},
+		{"s3/bucket/my photo.jpg", "'s3/bucket/my photo.jpg'"},
+		{"s3/bucket/it's", `'s3/bucket/it'\''s'`},
+		{"s3/bucket/$HOME;rm", "'s3/bucket/$HOME;rm'"},
+	}
+	for i, testCase := range testCases {
+		if got := shellSingleQuote(testCase.in); got != testCase.expected {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, got)
+		}
+	}
+}
+
+func TestDiffAction(t *testing.T) {
+	const (
+		firstArg  = "src/bucket/"
+		firstURL  = "http://src:9000/bucket/"
+		secondArg = "dst/bucket/"
+		secondURL = "http://dst:9000/bucket/"
+	)
+	testCases := []struct {
+		diff     diffMessage
+		ok       bool
+		expected string
+	}{
+		{diffMessage{FirstURL: firstURL + "a/b", Diff: differInFirst}, true, "mc cp 'src/bucket/a/b' 'dst/bucket/a/b'"},
+		{diffMessage{SecondURL: secondURL + "c", Diff: differInSecond}, true, "mc rm 'dst/bucket/c'"},
+		{diffMessage{FirstURL: firstURL + "d", SecondURL: secondURL + "d", Diff: differInSize}, true, "mc cp 'src/bucket/d' 'dst/bucket/d'"},
+		{diffMessage{FirstURL: firstURL + "e", SecondURL: secondURL + "e", Diff: differInContent}, true, "mc cp 'src/bucket/e' 'dst/bucket/e'"},
+		{diffMessage{FirstURL: firstURL + "f", SecondURL: secondURL + "f", Diff: differInType}, true, "# 'dst/bucket/f' differs in type, reconcile it manually"},
+		{diffMessage{FirstURL: firstURL + "g", SecondURL: secondURL + "g", Diff: differInNone}, false, ""},
+	}
+	for i, testCase := range testCases {
+		action, ok := diffAction(testCase.diff, firstArg, firstURL, secondArg, secondURL)
+		if ok != testCase.ok {
+			t.Fatalf("Test %d: expected ok %v, got %v", i+1, testCase.ok, ok)
+		}
+		if ok && action.String() != testCase.expected {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, action.String())
+		}
+	}
+}
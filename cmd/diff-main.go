@@ -32,13 +32,31 @@ import (
 
 // diff specific flags.
 var (
-	diffFlags = []cli.Flag{}
+	diffFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "compare",
+			Usage: "compare objects by 'size', with their time, or by 'checksum' of their contents",
+			Value: diffCompareSize,
+		},
+		cli.BoolFlag{
+			Name:  "generate-commands",
+			Usage: "print the 'mc cp' and 'mc rm' commands bringing the target in sync with the source, an NDJSON plan with '--json'",
+		},
+		cli.StringFlag{
+			Name:  "only",
+			Usage: "comma separated classes of differences to report, see CLASSES",
+		},
+		cli.BoolFlag{
+			Name:  "count",
+			Usage: "only print the number of differences of every class",
+		},
+	}
 )
 
 // Compute differences in object name, size, and date between two buckets.
 var diffCmd = cli.Command{
 	Name:         "diff",
-	Usage:        "list differences in object name, size, and date between two buckets",
+	Usage:        "list differences in object name, size, and date between two or three buckets",
 	Action:       mainDiff,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
@@ -47,18 +65,35 @@ var diffCmd = cli.Command{
   {{.HelpName}} - {{.Usage}}
 
 USAGE:
-  {{.HelpName}} [FLAGS] SOURCE TARGET
+  {{.HelpName}} [FLAGS] SOURCE TARGET [TARGET2]
 
 FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}
 DESCRIPTION:
-  Diff only calculates differences in object name, size and time. It *DOES NOT* compare objects' contents.
+  Diff only calculates differences in object name, size and time. It *DOES NOT* compare objects' contents,
+  unless '--compare checksum' is set: objects of the same size are then compared by their ETags when they
+  are MD5 sums, or by reading and hashing both of them.
+
+  With '--generate-commands', diff prints the 'mc cp' and 'mc rm' commands bringing the target in sync
+  with the source instead of the differences, to be reviewed and then piped to a shell. Objects differing
+  in type are printed as comments. With '--json', the same actions are printed as an NDJSON plan.
+
+  With three folders, diff reports the objects diverging between any pair of them, missing or differing
+  in size or MD5 sum, followed by the number of objects compared.
+
+CLASSES:
+  missing-on-target - object is only in source.
+  missing-on-source - object is only in destination.
+  differs           - object is in both, with different type, size, metadata or content.
+  newer-on-source   - object differs and was modified last in source.
+  newer-on-target   - object differs and was modified last in destination.
 
 LEGEND:
   < - object is only in source.
   > - object is only in destination.
   ! - newer object is in source.
+  ! - object content differs, with '--compare checksum'.
 
 EXAMPLES:
   1. Compare a local folder with a folder on Amazon S3 cloud storage.
@@ -66,6 +101,22 @@ EXAMPLES:
 
   2. Compare two folders on a local filesystem.
      {{.Prompt}} {{.HelpName}} ~/Photos /Media/Backup/Photos
+
+  3. Compare the contents of the objects of a bucket with their copies on another cluster.
+     {{.Prompt}} {{.HelpName}} --compare checksum s3/mybucket minio/mybucket
+
+  4. Validate the replication of a bucket to two other sites, as a JSON divergence report.
+     {{.Prompt}} {{.HelpName}} --json site1/mybucket site2/mybucket site3/mybucket
+
+  5. Generate the commands synchronizing a bucket with its copy on another cluster, review and run them.
+     {{.Prompt}} {{.HelpName}} --generate-commands s3/mybucket minio/mybucket > sync.sh
+     {{.Prompt}} sh sync.sh
+
+  6. List the objects of a bucket not copied yet to another cluster.
+     {{.Prompt}} {{.HelpName}} --only missing-on-target s3/mybucket minio/mybucket
+
+  7. Count the objects missing on either side.
+     {{.Prompt}} {{.HelpName}} --only missing-on-target,missing-on-source --count s3/mybucket minio/mybucket
 `,
 }
 
@@ -96,6 +147,8 @@ func (d diffMessage) String() string {
 		msg = console.Colorize("DiffMetadata", "! "+d.SecondURL)
 	case differInAASourceMTime:
 		msg = console.Colorize("DiffMMSourceMTime", "! "+d.SecondURL)
+	case differInContent:
+		msg = console.Colorize("DiffContent", "! "+d.SecondURL)
 	case differInNone:
 		msg = console.Colorize("DiffInNone", "= "+d.FirstURL)
 	default:
@@ -115,9 +168,29 @@ func (d diffMessage) JSON() string {
 }
 
 func checkDiffSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
-	if len(cliCtx.Args()) != 2 {
+	if len(cliCtx.Args()) != 2 && len(cliCtx.Args()) != 3 {
 		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
 	}
+	switch compare := cliCtx.String("compare"); compare {
+	case diffCompareSize, diffCompareChecksum:
+	default:
+		fatalIf(errInvalidArgument().Trace(compare), "--compare should be one of 'size' or 'checksum'.")
+	}
+	if len(cliCtx.Args()) == 3 && cliCtx.String("compare") == diffCompareChecksum {
+		fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), "--compare checksum is only supported between two folders.")
+	}
+	if len(cliCtx.Args()) == 3 && cliCtx.Bool("generate-commands") {
+		fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), "--generate-commands is only supported between two folders.")
+	}
+	if len(cliCtx.Args()) == 3 && (cliCtx.IsSet("only") || cliCtx.Bool("count")) {
+		fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), "--only and --count are only supported between two folders.")
+	}
+	if cliCtx.Bool("count") && cliCtx.Bool("generate-commands") {
+		fatalIf(errInvalidArgument(), "--count and --generate-commands are mutually exclusive.")
+	}
+	if _, err := parseDiffOnly(cliCtx.String("only")); err != nil {
+		fatalIf(err, "--only should be a comma separated list of "+strings.Join(diffOnlyClasses, ", ")+".")
+	}
 	for _, arg := range cliCtx.Args() {
 		if strings.TrimSpace(arg) == "" {
 			fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), "Unable to validate empty argument.")
@@ -125,9 +198,8 @@ func checkDiffSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[stri
 	}
 	URLs := cliCtx.Args()
 	firstURL := URLs[0]
-	secondURL := URLs[1]
 
-	// Diff only works between two directories, verify them below.
+	// Diff only works between directories, verify them below.
 
 	// Verify if firstURL is accessible.
 	_, firstContent, err := url2Stat(ctx, firstURL, "", false, encKeyDB, time.Time{}, false)
@@ -140,23 +212,33 @@ func checkDiffSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[stri
 		fatalIf(errInvalidArgument().Trace(firstURL), fmt.Sprintf("`%s` is not a folder.", firstURL))
 	}
 
-	// Verify if secondURL is accessible.
-	_, secondContent, err := url2Stat(ctx, secondURL, "", false, encKeyDB, time.Time{}, false)
-	if err != nil {
-		// Destination doesn't exist is okay.
-		if _, ok := err.ToGoError().(ObjectMissing); !ok {
-			fatalIf(err.Trace(secondURL), fmt.Sprintf("Unable to stat '%s'.", secondURL))
+	for _, secondURL := range URLs[1:] {
+		// Verify if secondURL is accessible.
+		_, secondContent, err := url2Stat(ctx, secondURL, "", false, encKeyDB, time.Time{}, false)
+		if err != nil {
+			// Destination doesn't exist is okay.
+			if _, ok := err.ToGoError().(ObjectMissing); !ok {
+				fatalIf(err.Trace(secondURL), fmt.Sprintf("Unable to stat '%s'.", secondURL))
+			}
 		}
-	}
 
-	// Verify if its a directory.
-	if err == nil && !secondContent.Type.IsDir() {
-		fatalIf(errInvalidArgument().Trace(secondURL), fmt.Sprintf("`%s` is not a folder.", secondURL))
+		// Verify if its a directory.
+		if err == nil && !secondContent.Type.IsDir() {
+			fatalIf(errInvalidArgument().Trace(secondURL), fmt.Sprintf("`%s` is not a folder.", secondURL))
+		}
 	}
 }
 
+// diffOpts holds the options of a diff between two folders.
+type diffOpts struct {
+	compare          string
+	generateCommands bool
+	only             map[string]bool // classes of differences reported, all when empty
+	count            bool
+}
+
 // doDiffMain runs the diff.
-func doDiffMain(ctx context.Context, firstURL, secondURL string) error {
+func doDiffMain(ctx context.Context, firstURL, secondURL string, opts diffOpts, encKeyDB map[string][]prefixSSEPair) error {
 	// Source and targets are always directories
 	sourceSeparator := string(newClientURL(firstURL).Separator)
 	if !strings.HasSuffix(firstURL, sourceSeparator) {
@@ -166,6 +248,7 @@ func doDiffMain(ctx context.Context, firstURL, secondURL string) error {
 	if !strings.HasSuffix(secondURL, targetSeparator) {
 		secondURL = secondURL + targetSeparator
 	}
+	firstArg, secondArg := firstURL, secondURL
 
 	// Expand aliased urls.
 	firstAlias, firstURL, _ := mustExpandAlias(firstURL)
@@ -183,16 +266,54 @@ func doDiffMain(ctx context.Context, firstURL, secondURL string) error {
 			fmt.Sprintf("Failed to diff '%s' and '%s'", firstURL, secondURL))
 	}
 
+	counts := make(map[string]int64)
+	for _, class := range diffOnlyClasses {
+		if len(opts.only) == 0 || opts.only[class] {
+			counts[class] = 0
+		}
+	}
+
 	// Diff first and second urls.
-	for diffMsg := range objectDifference(ctx, firstClient, secondClient, true) {
+	checksum := opts.compare == diffCompareChecksum
+	for diffMsg := range objectDifference(ctx, firstClient, secondClient, true, checksum) {
 		if diffMsg.Error != nil {
 			errorIf(diffMsg.Error, "Unable to calculate objects difference.")
 			// Ignore error and proceed to next object.
 			continue
 		}
+		if diffMsg.Diff == differInNone {
+			_, equal, err := diffContents(ctx, firstAlias, diffMsg.firstContent, secondAlias, diffMsg.secondContent, encKeyDB)
+			if err != nil {
+				errorIf(err, "Unable to compare the contents of `"+diffMsg.FirstURL+"` and `"+diffMsg.SecondURL+"`.")
+				continue
+			}
+			if equal {
+				continue
+			}
+			diffMsg.Diff = differInContent
+		}
+		classes := diffSelected(diffMsg, opts.only)
+		if len(classes) == 0 {
+			continue
+		}
+		if opts.count {
+			for _, class := range classes {
+				counts[class]++
+			}
+			continue
+		}
+		if opts.generateCommands {
+			if action, ok := diffAction(diffMsg, firstArg, firstURL, secondArg, secondURL); ok {
+				printMsg(action)
+			}
+			continue
+		}
 		printMsg(diffMsg)
 	}
 
+	if opts.count {
+		printMsg(diffCountMessage{First: firstArg, Second: secondArg, Counts: counts})
+	}
 	return nil
 }
 
@@ -216,10 +337,20 @@ func mainDiff(cliCtx *cli.Context) error {
 	console.SetColor("DiffSize", color.New(color.FgYellow, color.Bold))
 	console.SetColor("DiffMetadata", color.New(color.FgYellow, color.Bold))
 	console.SetColor("DiffMMSourceMTime", color.New(color.FgYellow, color.Bold))
+	console.SetColor("DiffContent", color.New(color.FgRed, color.Bold))
 
 	URLs := cliCtx.Args()
+	if len(URLs) == 3 {
+		return doDiff3(ctx, URLs)
+	}
 	firstURL := URLs.Get(0)
 	secondURL := URLs.Get(1)
 
-	return doDiffMain(ctx, firstURL, secondURL)
+	only, _ := parseDiffOnly(cliCtx.String("only"))
+	return doDiffMain(ctx, firstURL, secondURL, diffOpts{
+		compare:          cliCtx.String("compare"),
+		generateCommands: cliCtx.Bool("generate-commands"),
+		only:             only,
+		count:            cliCtx.Bool("count"),
+	}, encKeyDB)
 }
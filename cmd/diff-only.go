@@ -0,0 +1,141 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+// Classes of differences selected by 'diff --only'.
+const (
+	diffOnlyMissingOnTarget = "missing-on-target"
+	diffOnlyMissingOnSource = "missing-on-source"
+	diffOnlyDiffers         = "differs"
+	diffOnlyNewerOnSource   = "newer-on-source"
+	diffOnlyNewerOnTarget   = "newer-on-target"
+)
+
+var diffOnlyClasses = []string{
+	diffOnlyMissingOnTarget,
+	diffOnlyMissingOnSource,
+	diffOnlyDiffers,
+	diffOnlyNewerOnSource,
+	diffOnlyNewerOnTarget,
+}
+
+// parseDiffOnly parses the comma separated classes of --only, an empty
+// value selects every difference.
+func parseDiffOnly(only string) (map[string]bool, *probe.Error) {
+	if only == "" {
+		return nil, nil
+	}
+	classes := make(map[string]bool)
+	for _, class := range strings.Split(only, ",") {
+		class = strings.TrimSpace(class)
+		known := false
+		for _, c := range diffOnlyClasses {
+			if class == c {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, errInvalidArgument().Trace(class)
+		}
+		classes[class] = true
+	}
+	return classes, nil
+}
+
+// diffClasses returns the classes a difference belongs to. An object
+// present on both sides differs, and is newer on the side with the most
+// recent modification time.
+func diffClasses(d diffMessage) []string {
+	switch d.Diff {
+	case differInFirst:
+		return []string{diffOnlyMissingOnTarget}
+	case differInSecond:
+		return []string{diffOnlyMissingOnSource}
+	case differInNone, differInUnknown:
+		return nil
+	}
+	classes := []string{diffOnlyDiffers}
+	if d.firstContent == nil || d.secondContent == nil {
+		return classes
+	}
+	switch {
+	case d.firstContent.Time.After(d.secondContent.Time):
+		classes = append(classes, diffOnlyNewerOnSource)
+	case d.secondContent.Time.After(d.firstContent.Time):
+		classes = append(classes, diffOnlyNewerOnTarget)
+	}
+	return classes
+}
+
+// diffSelected returns the classes of a difference selected by only,
+// all of them when only is empty.
+func diffSelected(d diffMessage, only map[string]bool) []string {
+	classes := diffClasses(d)
+	if len(only) == 0 {
+		return classes
+	}
+	selected := classes[:0]
+	for _, class := range classes {
+		if only[class] {
+			selected = append(selected, class)
+		}
+	}
+	return selected
+}
+
+// diffCountMessage reports the number of differences of every class,
+// printed by 'diff --count'.
+type diffCountMessage struct {
+	Status string           `json:"status"`
+	First  string           `json:"first"`
+	Second string           `json:"second"`
+	Counts map[string]int64 `json:"counts"`
+}
+
+// String colorized diff count message
+func (d diffCountMessage) String() string {
+	classes := make([]string, 0, len(d.Counts))
+	for class := range d.Counts {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	lines := make([]string, 0, len(classes))
+	for _, class := range classes {
+		lines = append(lines, fmt.Sprintf("%s: %d", console.Colorize("DiffMessage", class), d.Counts[class]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// JSON jsonified diff count message
+func (d diffCountMessage) JSON() string {
+	d.Status = "success"
+	msgBytes, e := json.MarshalIndent(d, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffSelected(t *testing.T) {
+	older := &ClientContent{Time: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+	newer := &ClientContent{Time: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)}
+	testCases := []struct {
+		msg      diffMessage
+		only     string
+		expected []string
+	}{
+		{diffMessage{Diff: differInFirst}, "", []string{diffOnlyMissingOnTarget}},
+		{diffMessage{Diff: differInSecond}, "", []string{diffOnlyMissingOnSource}},
+		{diffMessage{Diff: differInSecond}, "missing-on-target", nil},
+		{diffMessage{Diff: differInNone, firstContent: newer, secondContent: older}, "", nil},
+		{diffMessage{Diff: differInSize, firstContent: newer, secondContent: older}, "", []string{diffOnlyDiffers, diffOnlyNewerOnSource}},
+		{diffMessage{Diff: differInSize, firstContent: newer, secondContent: older}, "newer-on-source", []string{diffOnlyNewerOnSource}},
+		{diffMessage{Diff: differInContent, firstContent: older, secondContent: newer}, "newer-on-source,differs", []string{diffOnlyDiffers}},
+		{diffMessage{Diff: differInType, firstContent: older, secondContent: newer}, "newer-on-target", []string{diffOnlyNewerOnTarget}},
+		{diffMessage{Diff: differInMetadata, firstContent: older, secondContent: older}, "", []string{diffOnlyDiffers}},
+	}
+	for i, testCase := range testCases {
+		only, err := parseDiffOnly(testCase.only)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error: %v", i+1, err)
+		}
+		got := diffSelected(testCase.msg, only)
+		if len(got) == 0 && len(testCase.expected) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(got, testCase.expected) {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.expected, got)
+		}
+	}
+	if _, err := parseDiffOnly("missing-on-target,unknown"); err == nil {
+		t.Fatalf("expected an error for an unknown class")
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Names of the endpoints of a three-way diff.
+var diff3Names = [3]string{"source", "target1", "target2"}
+
+// Reasons of the divergence of two endpoints, most important first.
+const (
+	diff3Missing = "missing"
+	diff3Size    = "size"
+	diff3ETag    = "etag"
+)
+
+// diff3Object is an object on one endpoint of a three-way diff.
+type diff3Object struct {
+	Endpoint string     `json:"endpoint"`
+	URL      string     `json:"url,omitempty"`
+	Exists   bool       `json:"exists"`
+	Size     int64      `json:"size,omitempty"`
+	ETag     string     `json:"etag,omitempty"`
+	ModTime  *time.Time `json:"lastModified,omitempty"`
+}
+
+// diff3Pair is a pair of endpoints diverging on an object.
+type diff3Pair struct {
+	First  string `json:"first"`
+	Second string `json:"second"`
+	Diff   string `json:"diff"`
+}
+
+// diff3Message reports an object diverging between any pair of the
+// three endpoints.
+type diff3Message struct {
+	Status  string         `json:"status"`
+	Key     string         `json:"key"`
+	Diff    string         `json:"diff"`
+	Pairs   []diff3Pair    `json:"pairs"`
+	Objects [3]diff3Object `json:"objects"`
+}
+
+// String colorized three-way diff message
+func (d diff3Message) String() string {
+	pairs := make([]string, 0, len(d.Pairs))
+	for _, pair := range d.Pairs {
+		pairs = append(pairs, fmt.Sprintf("%s<>%s: %s", pair.First, pair.Second, pair.Diff))
+	}
+	theme := "DiffContent"
+	switch d.Diff {
+	case diff3Missing:
+		theme = "DiffOnlyInFirst"
+	case diff3Size:
+		theme = "DiffSize"
+	}
+	return console.Colorize(theme, "! "+d.Key) + " (" + strings.Join(pairs, ", ") + ")"
+}
+
+// JSON jsonified three-way diff message
+func (d diff3Message) JSON() string {
+	d.Status = "success"
+	msgBytes, e := json.MarshalIndent(d, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(msgBytes)
+}
+
+// diff3SummaryMessage counts the objects compared by a three-way diff.
+type diff3SummaryMessage struct {
+	Status    string `json:"status"`
+	Objects   int64  `json:"objects"`
+	Divergent int64  `json:"divergent"`
+}
+
+// String colorized three-way diff summary message
+func (d diff3SummaryMessage) String() string {
+	return console.Colorize("DiffMessage", fmt.Sprintf("Compared %d object(s) on three endpoints, %d diverging.", d.Objects, d.Divergent))
+}
+
+// JSON jsonified three-way diff summary message
+func (d diff3SummaryMessage) JSON() string {
+	d.Status = "success"
+	msgBytes, e := json.MarshalIndent(d, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(msgBytes)
+}
+
+// diff3Compare compares the same object on two endpoints, returning
+// why they diverge or an empty string.
+func diff3Compare(first, second diff3Object) string {
+	switch {
+	case first.Exists != second.Exists:
+		return diff3Missing
+	case !first.Exists:
+		return ""
+	case first.Size != second.Size:
+		return diff3Size
+	}
+	if equal, ok := compareETags(first.ETag, second.ETag); ok && !equal {
+		return diff3ETag
+	}
+	return ""
+}
+
+// diff3Objects returns the message of an object if any pair of
+// endpoints diverges on it.
+func diff3Objects(key string, objects [3]diff3Object) (diff3Message, bool) {
+	msg := diff3Message{Key: key, Objects: objects}
+	for i := 0; i < len(objects); i++ {
+		for j := i + 1; j < len(objects); j++ {
+			diff := diff3Compare(objects[i], objects[j])
+			if diff == "" {
+				continue
+			}
+			msg.Pairs = append(msg.Pairs, diff3Pair{First: objects[i].Endpoint, Second: objects[j].Endpoint, Diff: diff})
+			// Keep the most important reason.
+			if msg.Diff == "" || diff == diff3Missing || (diff == diff3Size && msg.Diff == diff3ETag) {
+				msg.Diff = diff
+			}
+		}
+	}
+	return msg, len(msg.Pairs) > 0
+}
+
+// doDiff3 lists the three folders recursively and reports the objects
+// diverging between any pair of them.
+func doDiff3(ctx context.Context, urls []string) error {
+	var (
+		prefixes [3]string
+		chs      [3]<-chan *ClientContent
+		heads    [3]*ClientContent
+	)
+	for i, urlStr := range urls {
+		separator := string(newClientURL(urlStr).Separator)
+		if !strings.HasSuffix(urlStr, separator) {
+			urlStr += separator
+		}
+		alias, expandedURL, _ := mustExpandAlias(urlStr)
+		clnt, err := newClientFromAlias(alias, expandedURL)
+		fatalIf(err.Trace(urlStr), "Unable to initialize `"+urlStr+"`.")
+		prefixes[i] = clnt.GetURL().String()
+		chs[i] = clnt.List(ctx, ListOptions{Recursive: true, ShowDir: DirNone})
+	}
+
+	// next returns the next object of the endpoint i, nil at the end.
+	next := func(i int) *ClientContent {
+		for content := range chs[i] {
+			if content.Err != nil {
+				errorIf(content.Err.Trace(urls[i]), "Unable to list `"+urls[i]+"`.")
+				continue
+			}
+			return content
+		}
+		return nil
+	}
+	key := func(i int) string {
+		return norm.NFC.String(strings.TrimPrefix(heads[i].URL.String(), prefixes[i]))
+	}
+	for i := range heads {
+		heads[i] = next(i)
+	}
+
+	var summary diff3SummaryMessage
+	for {
+		// The smallest key of the three listings, sorted by key.
+		current, found := "", false
+		for i := range heads {
+			if heads[i] != nil && (!found || key(i) < current) {
+				current, found = key(i), true
+			}
+		}
+		if !found {
+			break
+		}
+
+		var objects [3]diff3Object
+		for i := range heads {
+			objects[i].Endpoint = diff3Names[i]
+			if heads[i] == nil || key(i) != current {
+				continue
+			}
+			content := heads[i]
+			objects[i].URL = content.URL.String()
+			objects[i].Exists = true
+			objects[i].Size = content.Size
+			objects[i].ETag = content.ETag
+			modTime := content.Time
+			objects[i].ModTime = &modTime
+			heads[i] = next(i)
+		}
+
+		summary.Objects++
+		if msg, ok := diff3Objects(current, objects); ok {
+			summary.Divergent++
+			printMsg(msg)
+		}
+	}
+	printMsg(summary)
+	return nil
+}
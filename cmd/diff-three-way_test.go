@@ -0,0 +1,61 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff3Objects(t *testing.T) {
+	const (
+		md5A = "d41d8cd98f00b204e9800998ecf8427e"
+		md5B = "0cc175b9c0f1b6a831c399e269772661"
+	)
+	object := func(exists bool, size int64, etag string) diff3Object {
+		return diff3Object{Exists: exists, Size: size, ETag: etag}
+	}
+	testCases := []struct {
+		objects [3]diff3Object
+		diff    string
+		pairs   []string
+	}{
+		{[3]diff3Object{object(true, 1, md5A), object(true, 1, md5A), object(true, 1, md5A)}, "", nil},
+		{[3]diff3Object{object(true, 1, md5A), object(true, 1, md5A), object(false, 0, "")}, diff3Missing, []string{"source<>target2: missing", "target1<>target2: missing"}},
+		{[3]diff3Object{object(false, 0, ""), object(true, 1, md5A), object(true, 1, md5A)}, diff3Missing, []string{"source<>target1: missing", "source<>target2: missing"}},
+		{[3]diff3Object{object(true, 1, md5A), object(true, 2, md5B), object(true, 1, md5B)}, diff3Size, []string{"source<>target1: size", "source<>target2: etag", "target1<>target2: size"}},
+		{[3]diff3Object{object(true, 1, md5A), object(true, 1, md5A+"-2"), object(true, 1, "")}, "", nil},
+	}
+
+	for i, testCase := range testCases {
+		for j := range testCase.objects {
+			testCase.objects[j].Endpoint = diff3Names[j]
+		}
+		msg, ok := diff3Objects("key", testCase.objects)
+		if ok != (testCase.diff != "") || msg.Diff != testCase.diff {
+			t.Errorf("Test %d: expected diff %q, got %q", i+1, testCase.diff, msg.Diff)
+		}
+		var pairs []string
+		for _, pair := range msg.Pairs {
+			pairs = append(pairs, pair.First+"<>"+pair.Second+": "+pair.Diff)
+		}
+		if !reflect.DeepEqual(pairs, testCase.pairs) {
+			t.Errorf("Test %d: expected pairs %v, got %v", i+1, testCase.pairs, pairs)
+		}
+	}
+}
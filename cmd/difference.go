@@ -42,6 +42,7 @@ const (
 	differInFirst                    // only in source (FIRST)
 	differInSecond                   // only in target (SECOND)
 	differInAASourceMTime            // differs in active-active source modtime
+	differInContent                  // differs in content, same size
 )
 
 func (d differType) String() string {
@@ -54,6 +55,8 @@ func (d differType) String() string {
 		return "metadata"
 	case differInAASourceMTime:
 		return "mm-source-mtime"
+	case differInContent:
+		return "content"
 	case differInType:
 		return "type"
 	case differInFirst:
@@ -161,14 +164,14 @@ func metadataEqual(m1, m2 map[string]string) bool {
 	return true
 }
 
-func objectDifference(ctx context.Context, sourceClnt, targetClnt Client, isMetadata bool) (diffCh chan diffMessage) {
+func objectDifference(ctx context.Context, sourceClnt, targetClnt Client, isMetadata, returnSimilar bool) (diffCh chan diffMessage) {
 	sourceURL := sourceClnt.GetURL().String()
 	sourceCh := sourceClnt.List(ctx, ListOptions{Recursive: true, WithMetadata: isMetadata, ShowDir: DirNone})
 
 	targetURL := targetClnt.GetURL().String()
 	targetCh := targetClnt.List(ctx, ListOptions{Recursive: true, WithMetadata: isMetadata, ShowDir: DirNone})
 
-	return difference(sourceURL, sourceCh, targetURL, targetCh, isMetadata, false)
+	return difference(sourceURL, sourceCh, targetURL, targetCh, isMetadata, returnSimilar)
 }
 
 func bucketDifference(ctx context.Context, sourceClnt, targetClnt Client) (diffCh chan diffMessage) {
@@ -19,6 +19,7 @@ package cmd
 
 import (
 	"testing"
+	"time"
 )
 
 var testCases = []struct {
@@ -47,3 +48,58 @@ func TestExcludeOptions(t *testing.T) {
 		}
 	}
 }
+
+func TestConflictPolicyResolve(t *testing.T) {
+	now := time.Now()
+	older := &ClientContent{Time: now.Add(-time.Hour), Size: 10}
+	newer := &ClientContent{Time: now, Size: 5}
+
+	testCases := []struct {
+		policy   conflictPolicy
+		src, tgt *ClientContent
+		action   conflictAction
+	}{
+		{conflictNewest, newer, older, conflictOverwriteTarget},
+		{conflictNewest, older, newer, conflictKeepTarget},
+		{conflictLargest, older, newer, conflictOverwriteTarget},
+		{conflictLargest, newer, older, conflictKeepTarget},
+		{conflictRename, newer, older, conflictRenameTarget},
+		{conflictSkip, newer, older, conflictKeepTarget},
+		{conflictSkip, newer, nil, conflictOverwriteTarget},
+	}
+
+	for i, tc := range testCases {
+		if action := tc.policy.resolve(tc.src, tc.tgt); action != tc.action {
+			t.Fatalf("Test %d: expected action %d, got %d", i+1, tc.action, action)
+		}
+	}
+
+	if conflictPolicy("oldest").IsValid() {
+		t.Fatal("Expected unknown conflict policy to be invalid")
+	}
+}
+
+func TestBucketFilterSkip(t *testing.T) {
+	testCases := []struct {
+		filter bucketFilter
+		suffix string
+		skip   bool
+	}{
+		{bucketFilter{}, "mybucket/object", false},
+		{bucketFilter{exclude: []string{"scratch-*"}}, "scratch-01/object", true},
+		{bucketFilter{exclude: []string{"scratch-*"}}, "mybucket/scratch-01", false},
+		{bucketFilter{include: []string{"finance-*"}}, "finance-eu/report.pdf", false},
+		{bucketFilter{include: []string{"finance-*"}}, "hr/payroll.pdf", true},
+		{bucketFilter{include: []string{"finance-*"}, exclude: []string{"*-eu"}}, "finance-eu/", true},
+		{bucketFilter{skipSystem: true}, ".minio.sys/config", true},
+		{bucketFilter{skipSystem: true}, "/mybucket", false},
+		// Objects only on target have no source suffix.
+		{bucketFilter{include: []string{"finance-*"}}, "", false},
+	}
+
+	for i, testCase := range testCases {
+		if skip := testCase.filter.skip(testCase.suffix); skip != testCase.skip {
+			t.Fatalf("Test %d: expected skip %t for `%s`, got %t", i+1, testCase.skip, testCase.suffix, skip)
+		}
+	}
+}
@@ -22,7 +22,10 @@ import (
 	"fmt"
 	"net/url"
 	"path"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/dustin/go-humanize"
@@ -52,6 +55,15 @@ var (
 			Name:  "versions",
 			Usage: "include all object versions",
 		},
+		cli.BoolFlag{
+			Name:  "breakdown",
+			Usage: "split the usage by storage class and between current, noncurrent versions and delete markers",
+		},
+		cli.IntFlag{
+			Name:  "top",
+			Usage: "print the N largest objects and folder prefixes, prefixes being '--depth' levels deep",
+		},
+		parallelBucketsFlag,
 	}
 )
 
@@ -87,16 +99,85 @@ EXAMPLES:
 
   4. Summarize disk usage of 'jazz-songs' bucket with all objects versions
      {{.Prompt}} {{.HelpName}} --versions s3/jazz-songs/
+
+  5. Summarize disk usage of every bucket of 's3', 64 buckets at a time, followed by the total.
+     {{.Prompt}} {{.HelpName}} --parallel-buckets 64 s3
+
+  6. Summarize disk usage of 'jazz-songs' bucket per storage class, and of its noncurrent versions.
+     {{.Prompt}} {{.HelpName}} --breakdown s3/jazz-songs
+
+  7. List the 10 largest objects of 'jazz-songs' bucket, and its 10 largest prefixes two levels deep.
+     {{.Prompt}} {{.HelpName}} --top 10 --depth 2 s3/jazz-songs
 `,
 }
 
+// duUsage is the size and number of some objects.
+type duUsage struct {
+	Size    int64 `json:"size"`
+	Objects int64 `json:"objects"`
+}
+
+func (u duUsage) String() string {
+	return strings.Join(strings.Fields(humanize.IBytes(uint64(u.Size))), "") + " (" + strconv.FormatInt(u.Objects, 10) + ")"
+}
+
+// duBreakdown splits the disk usage of a prefix by storage class, of
+// the objects counted in its total, and between the current and the
+// noncurrent versions of all its objects.
+type duBreakdown struct {
+	StorageClasses map[string]duUsage `json:"storageClasses"`
+	Current        duUsage            `json:"current"`
+	Noncurrent     duUsage            `json:"noncurrent"`
+	DeleteMarkers  int64              `json:"deleteMarkers"`
+}
+
+func newDuBreakdown() *duBreakdown {
+	return &duBreakdown{StorageClasses: make(map[string]duUsage)}
+}
+
+// add adds the usage of a sub prefix.
+func (b *duBreakdown) add(o *duBreakdown) {
+	if b == nil || o == nil {
+		return
+	}
+	for class, usage := range o.StorageClasses {
+		total := b.StorageClasses[class]
+		total.Size += usage.Size
+		total.Objects += usage.Objects
+		b.StorageClasses[class] = total
+	}
+	b.Current.Size += o.Current.Size
+	b.Current.Objects += o.Current.Objects
+	b.Noncurrent.Size += o.Noncurrent.Size
+	b.Noncurrent.Objects += o.Noncurrent.Objects
+	b.DeleteMarkers += o.DeleteMarkers
+}
+
+// String returns the breakdown on two lines, indented under the usage.
+func (b duBreakdown) String() string {
+	classes := make([]string, 0, len(b.StorageClasses))
+	for class := range b.StorageClasses {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	for i, class := range classes {
+		classes[i] = class + " " + b.StorageClasses[class].String()
+	}
+	if len(classes) == 0 {
+		classes = append(classes, "-")
+	}
+	return fmt.Sprintf("\t  current %s, noncurrent %s, %d delete marker(s)\n\t  %s",
+		b.Current, b.Noncurrent, b.DeleteMarkers, strings.Join(classes, ", "))
+}
+
 // Structured message depending on the type of console.
 type duMessage struct {
-	Prefix     string `json:"prefix"`
-	Size       int64  `json:"size"`
-	Objects    int64  `json:"objects"`
-	Status     string `json:"status"`
-	IsVersions bool   `json:"isVersions"`
+	Prefix     string       `json:"prefix"`
+	Size       int64        `json:"size"`
+	Objects    int64        `json:"objects"`
+	Status     string       `json:"status"`
+	IsVersions bool         `json:"isVersions"`
+	Breakdown  *duBreakdown `json:"breakdown,omitempty"`
 }
 
 // Colorized message for console printing.
@@ -109,9 +190,13 @@ func (r duMessage) String() string {
 	if r.Objects != 1 {
 		cnt += "s" // pluralize
 	}
-	return fmt.Sprintf("%s\t%s\t%s", console.Colorize("Size", humanSize),
+	msg := fmt.Sprintf("%s\t%s\t%s", console.Colorize("Size", humanSize),
 		console.Colorize("Objects", cnt),
 		console.Colorize("Prefix", r.Prefix))
+	if r.Breakdown != nil {
+		msg += "\n" + console.Colorize("Breakdown", r.Breakdown.String())
+	}
+	return msg
 }
 
 // JSON'ified message for scripting.
@@ -121,7 +206,9 @@ func (r duMessage) JSON() string {
 	return string(msgBytes)
 }
 
-func du(ctx context.Context, urlStr string, timeRef time.Time, withVersions bool, depth int, encKeyDB map[string][]prefixSSEPair) (sz, objs int64, err error) {
+// du summarizes the disk usage of urlStr. With breakdown, all the
+// versions are listed to also return the breakdown of the usage.
+func du(ctx context.Context, urlStr string, timeRef time.Time, withVersions, breakdown bool, depth int, encKeyDB map[string][]prefixSSEPair) (sz, objs int64, bd *duBreakdown, err error) {
 	targetAlias, targetURL, _ := mustExpandAlias(urlStr)
 
 	if !strings.HasSuffix(targetURL, "/") {
@@ -131,7 +218,7 @@ func du(ctx context.Context, urlStr string, timeRef time.Time, withVersions bool
 	clnt, pErr := newClientFromAlias(targetAlias, targetURL)
 	if pErr != nil {
 		errorIf(pErr.Trace(urlStr), "Failed to summarize disk usage `"+urlStr+"`.")
-		return 0, 0, exitStatus(globalErrorExitStatus) // End of journey.
+		return 0, 0, nil, exitStatus(globalErrorExitStatus) // End of journey.
 	}
 
 	// No disk usage details below this level,
@@ -142,12 +229,18 @@ func du(ctx context.Context, urlStr string, timeRef time.Time, withVersions bool
 
 	contentCh := clnt.List(ctx, ListOptions{
 		TimeRef:           timeRef,
-		WithOlderVersions: withVersions,
+		WithOlderVersions: withVersions || breakdown,
+		WithDeleteMarkers: breakdown,
 		Recursive:         recursive,
 		ShowDir:           DirFirst,
 	})
 	size := int64(0)
 	objects := int64(0)
+	if breakdown {
+		bd = newDuBreakdown()
+	}
+	// Versions of an object are listed newest first.
+	var lastKey string
 	for content := range contentCh {
 		if content.Err != nil {
 			switch content.Err.ToGoError().(type) {
@@ -159,7 +252,7 @@ func du(ctx context.Context, urlStr string, timeRef time.Time, withVersions bool
 				continue
 			}
 			errorIf(content.Err.Trace(urlStr), "Failed to find disk usage of `"+urlStr+"` recursively.")
-			return 0, 0, exitStatus(globalErrorExitStatus)
+			return 0, 0, nil, exitStatus(globalErrorExitStatus)
 		}
 
 		if content.URL.Path == targetAbsolutePath {
@@ -176,16 +269,42 @@ func du(ctx context.Context, urlStr string, timeRef time.Time, withVersions bool
 			if targetAlias != "" {
 				subDirAlias = targetAlias + "/" + content.URL.Path
 			}
-			used, n, err := du(ctx, subDirAlias, timeRef, withVersions, depth, encKeyDB)
+			used, n, subBreakdown, err := du(ctx, subDirAlias, timeRef, withVersions, breakdown, depth, encKeyDB)
 			if err != nil {
-				return 0, 0, err
+				return 0, 0, nil, err
 			}
 			size += used
 			objects += n
-		} else {
-			if !content.IsDeleteMarker && !content.Type.IsDir() {
-				size += content.Size
-				objects++
+			bd.add(subBreakdown)
+		} else if !content.Type.IsDir() {
+			current := content.URL.Path != lastKey
+			lastKey = content.URL.Path
+			if bd != nil {
+				switch {
+				case content.IsDeleteMarker:
+					bd.DeleteMarkers++
+				case current:
+					bd.Current.Size += content.Size
+					bd.Current.Objects++
+				default:
+					bd.Noncurrent.Size += content.Size
+					bd.Noncurrent.Objects++
+				}
+			}
+			if content.IsDeleteMarker || !current && !withVersions {
+				continue
+			}
+			size += content.Size
+			objects++
+			if bd != nil {
+				class := content.StorageClass
+				if class == "" {
+					class = "STANDARD"
+				}
+				usage := bd.StorageClasses[class]
+				usage.Size += content.Size
+				usage.Objects++
+				bd.StorageClasses[class] = usage
 			}
 		}
 	}
@@ -202,10 +321,58 @@ func du(ctx context.Context, urlStr string, timeRef time.Time, withVersions bool
 			Objects:    objects,
 			Status:     "success",
 			IsVersions: withVersions,
+			Breakdown:  bd,
 		})
 	}
 
-	return size, objects, nil
+	return size, objects, bd, nil
+}
+
+// duAlias summarizes the disk usage of all buckets of an alias
+// concurrently, printing each bucket once done and the total last.
+func duAlias(ctx context.Context, clnt Client, urlStr string, timeRef time.Time, withVersions, breakdown bool, depth, parallel int, encKeyDB map[string][]prefixSSEPair) error {
+	// Buckets are always printed, to report the progress.
+	bucketDepth := depth
+	if depth > 0 {
+		bucketDepth = depth - 1
+	}
+	if bucketDepth == 0 {
+		bucketDepth = 1
+	}
+
+	var (
+		mu         sync.Mutex
+		size, objs int64
+		bd         *duBreakdown
+		duErr      error
+	)
+	if breakdown {
+		bd = newDuBreakdown()
+	}
+	err := forEachBucket(ctx, clnt, urlStr, parallel, func(bucketURL string) {
+		used, n, bucketBreakdown, err := du(ctx, bucketURL, timeRef, withVersions, breakdown, bucketDepth, encKeyDB)
+		mu.Lock()
+		defer mu.Unlock()
+		size += used
+		objs += n
+		bd.add(bucketBreakdown)
+		if duErr == nil {
+			duErr = err
+		}
+	})
+	if err != nil {
+		errorIf(err, "Failed to summarize disk usage `"+urlStr+"`.")
+		return exitStatus(globalErrorExitStatus)
+	}
+
+	printMsg(duMessage{
+		Size:       size,
+		Objects:    objs,
+		Status:     "success",
+		IsVersions: withVersions,
+		Breakdown:  bd,
+	})
+	return duErr
 }
 
 // main for du command.
@@ -219,6 +386,7 @@ func mainDu(cliCtx *cli.Context) error {
 	console.SetColor("Prefix", color.New(color.FgCyan, color.Bold))
 	console.SetColor("Objects", color.New(color.FgGreen))
 	console.SetColor("Size", color.New(color.FgYellow))
+	console.SetColor("Breakdown", color.New(color.FgWhite))
 
 	ctx, cancelRm := context.WithCancel(globalContext)
 	defer cancelRm()
@@ -240,15 +408,46 @@ func mainDu(cliCtx *cli.Context) error {
 	}
 
 	withVersions := cliCtx.Bool("versions")
+	breakdown := cliCtx.Bool("breakdown")
 	timeRef := parseRewindFlag(cliCtx.String("rewind"))
 
+	top := cliCtx.Int("top")
+	if top < 0 {
+		fatalIf(errInvalidArgument().Trace(strconv.Itoa(top)), "--top should be a positive number.")
+	}
+	if top > 0 && breakdown {
+		fatalIf(errInvalidArgument(), "--top cannot be specified with --breakdown.")
+	}
+
 	var duErr error
 	for _, urlStr := range cliCtx.Args() {
 		if !isAliasURLDir(ctx, urlStr, nil, time.Time{}) {
 			fatalIf(errInvalidArgument().Trace(urlStr), fmt.Sprintf("Source `%s` is not a folder. Only folders are supported by 'du' command.", urlStr))
 		}
 
-		if _, _, err := du(ctx, urlStr, timeRef, withVersions, depth, encKeyDB); duErr == nil {
+		clnt, err := newClient(urlStr)
+		fatalIf(err.Trace(urlStr), "Unable to initialize target `"+urlStr+"`.")
+		if top > 0 {
+			if isAliasRoot(clnt) {
+				fatalIf(errInvalidArgument().Trace(urlStr), "--top requires a bucket or a prefix, not `"+urlStr+"`.")
+			}
+			topDepth := cliCtx.Int("depth")
+			if topDepth <= 0 {
+				topDepth = 1
+			}
+			if err := duTop(ctx, urlStr, timeRef, withVersions, top, topDepth); duErr == nil {
+				duErr = err
+			}
+			continue
+		}
+		if isAliasRoot(clnt) {
+			if err := duAlias(ctx, clnt, urlStr, timeRef, withVersions, breakdown, depth, cliCtx.Int("parallel-buckets"), encKeyDB); duErr == nil {
+				duErr = err
+			}
+			continue
+		}
+
+		if _, _, _, err := du(ctx, urlStr, timeRef, withVersions, breakdown, depth, encKeyDB); duErr == nil {
 			duErr = err
 		}
 	}
@@ -0,0 +1,218 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"container/heap"
+	"context"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	json "github.com/minio/colorjson"
+	"github.com/olekukonko/tablewriter"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+// duTopEntry is an object or a prefix of 'du --top'.
+type duTopEntry struct {
+	Key     string `json:"key"`
+	Size    int64  `json:"size"`
+	Objects int64  `json:"objects,omitempty"`
+}
+
+// duTopHeap is a min-heap of entries by size, holding the largest
+// ones seen so far.
+type duTopHeap []duTopEntry
+
+func (h duTopHeap) Len() int            { return len(h) }
+func (h duTopHeap) Less(i, j int) bool  { return h[i].Size < h[j].Size }
+func (h duTopHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *duTopHeap) Push(x interface{}) { *h = append(*h, x.(duTopEntry)) }
+func (h *duTopHeap) Pop() interface{} {
+	old := *h
+	entry := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return entry
+}
+
+// offer keeps entry if it is among the n largest.
+func (h *duTopHeap) offer(entry duTopEntry, n int) {
+	if h.Len() < n {
+		heap.Push(h, entry)
+		return
+	}
+	if entry.Size > (*h)[0].Size {
+		(*h)[0] = entry
+		heap.Fix(h, 0)
+	}
+}
+
+// sorted returns the entries, largest first.
+func (h duTopHeap) sorted() []duTopEntry {
+	entries := append([]duTopEntry(nil), h...)
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Size != entries[j].Size {
+			return entries[i].Size > entries[j].Size
+		}
+		return entries[i].Key < entries[j].Key
+	})
+	return entries
+}
+
+// duTopPrefix returns the prefix of key at depth folders, empty
+// for objects at the top level.
+func duTopPrefix(key string, depth int) string {
+	folders := strings.Split(key, "/")
+	folders = folders[:len(folders)-1]
+	if len(folders) > depth {
+		folders = folders[:depth]
+	}
+	if len(folders) == 0 {
+		return ""
+	}
+	return strings.Join(folders, "/") + "/"
+}
+
+// duTopMessage reports the largest objects and prefixes of a target.
+type duTopMessage struct {
+	Status   string       `json:"status"`
+	Prefix   string       `json:"prefix"`
+	Objects  []duTopEntry `json:"objects"`
+	Prefixes []duTopEntry `json:"prefixes"`
+}
+
+// String colorized du top message
+func (d duTopMessage) String() string {
+	var s strings.Builder
+	render := func(header []string, entries []duTopEntry, withObjects bool) {
+		table := tablewriter.NewWriter(&s)
+		table.SetAutoWrapText(false)
+		table.SetAutoFormatHeaders(true)
+		table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		table.SetCenterSeparator("")
+		table.SetColumnSeparator("")
+		table.SetRowSeparator("")
+		table.SetHeaderLine(false)
+		table.SetBorder(false)
+		table.SetTablePadding("\t") // pad with tabs
+		table.SetNoWhiteSpace(true)
+		table.SetHeader(header)
+		for _, entry := range entries {
+			row := []string{strings.Join(strings.Fields(humanize.IBytes(uint64(entry.Size))), "")}
+			if withObjects {
+				row = append(row, strconv.FormatInt(entry.Objects, 10))
+			}
+			table.Append(append(row, entry.Key))
+		}
+		table.Render()
+	}
+	if len(d.Prefixes) > 0 {
+		render([]string{"SIZE", "OBJECTS", "PREFIX"}, d.Prefixes, true)
+		s.WriteString("\n")
+	}
+	if len(d.Objects) == 0 {
+		s.WriteString("No objects found under `" + d.Prefix + "`.")
+		return s.String()
+	}
+	render([]string{"SIZE", "OBJECT"}, d.Objects, false)
+	return strings.TrimSuffix(s.String(), "\n")
+}
+
+// JSON jsonified du top message
+func (d duTopMessage) JSON() string {
+	d.Status = "success"
+	msgBytes, e := json.MarshalIndent(d, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(msgBytes)
+}
+
+// duTop lists urlStr recursively and prints its n largest objects and
+// prefixes, the prefixes being depth folders deep. Only n objects and
+// the sizes of the prefixes are kept in memory.
+func duTop(ctx context.Context, urlStr string, timeRef time.Time, withVersions bool, n, depth int) error {
+	targetAlias, targetURL, _ := mustExpandAlias(urlStr)
+	if !strings.HasSuffix(targetURL, "/") {
+		targetURL += "/"
+	}
+
+	clnt, err := newClientFromAlias(targetAlias, targetURL)
+	if err != nil {
+		errorIf(err.Trace(urlStr), "Failed to summarize disk usage `"+urlStr+"`.")
+		return exitStatus(globalErrorExitStatus)
+	}
+	targetPath := strings.TrimSuffix(path.Clean(clnt.GetURL().Path), "/") + "/"
+
+	var objects duTopHeap
+	prefixes := make(map[string]*duTopEntry)
+	for content := range clnt.List(ctx, ListOptions{
+		TimeRef:           timeRef,
+		WithOlderVersions: withVersions,
+		Recursive:         true,
+		ShowDir:           DirNone,
+	}) {
+		if content.Err != nil {
+			switch content.Err.ToGoError().(type) {
+			case BrokenSymlink, TooManyLevelsSymlink, PathNotFound, ObjectOnGlacier:
+				continue
+			case PathInsufficientPermission:
+				errorIf(content.Err.Trace(clnt.GetURL().String()), "Unable to list folder.")
+				continue
+			}
+			errorIf(content.Err.Trace(urlStr), "Failed to find the largest objects of `"+urlStr+"`.")
+			return exitStatus(globalErrorExitStatus)
+		}
+		if content.IsDeleteMarker || content.Type.IsDir() {
+			continue
+		}
+
+		key := strings.TrimPrefix(content.URL.Path, targetPath)
+		name := key
+		if withVersions && content.VersionID != "" {
+			name += " (" + content.VersionID + ")"
+		}
+		objects.offer(duTopEntry{Key: name, Size: content.Size}, n)
+
+		if prefix := duTopPrefix(key, depth); prefix != "" {
+			entry, ok := prefixes[prefix]
+			if !ok {
+				entry = &duTopEntry{Key: prefix}
+				prefixes[prefix] = entry
+			}
+			entry.Size += content.Size
+			entry.Objects++
+		}
+	}
+
+	var topPrefixes duTopHeap
+	for _, entry := range prefixes {
+		topPrefixes.offer(*entry, n)
+	}
+
+	printMsg(duTopMessage{
+		Prefix:   urlStr,
+		Objects:  objects.sorted(),
+		Prefixes: topPrefixes.sorted(),
+	})
+	return nil
+}
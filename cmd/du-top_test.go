@@ -0,0 +1,70 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDuTopHeap(t *testing.T) {
+	testCases := []struct {
+		sizes    []int64
+		n        int
+		expected []int64
+	}{
+		{nil, 3, []int64{}},
+		{[]int64{5, 1, 3}, 5, []int64{5, 3, 1}},
+		{[]int64{5, 1, 3, 9, 7, 2}, 3, []int64{9, 7, 5}},
+		{[]int64{1, 1, 1, 4}, 1, []int64{4}},
+	}
+
+	for i, testCase := range testCases {
+		var h duTopHeap
+		for _, size := range testCase.sizes {
+			h.offer(duTopEntry{Size: size}, testCase.n)
+		}
+		sizes := []int64{}
+		for _, entry := range h.sorted() {
+			sizes = append(sizes, entry.Size)
+		}
+		if !reflect.DeepEqual(sizes, testCase.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, sizes)
+		}
+	}
+}
+
+func TestDuTopPrefix(t *testing.T) {
+	testCases := []struct {
+		key      string
+		depth    int
+		expected string
+	}{
+		{"object", 1, ""},
+		{"a/object", 1, "a/"},
+		{"a/b/c/object", 1, "a/"},
+		{"a/b/c/object", 2, "a/b/"},
+		{"a/b/object", 5, "a/b/"},
+	}
+
+	for i, testCase := range testCases {
+		if prefix := duTopPrefix(testCase.key, testCase.depth); prefix != testCase.expected {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expected, prefix)
+		}
+	}
+}
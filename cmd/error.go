@@ -21,11 +21,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
+	"sync"
 	"unicode"
 
+	"github.com/fatih/color"
 	"github.com/minio/cli"
 	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/i18n"
 	"github.com/trinet2005/oss-mc/pkg/probe"
 	"github.com/trinet2005/oss-pkg/console"
 )
@@ -81,13 +85,13 @@ func fatal(err *probe.Error, msg string, data ...interface{}) {
 		console.Fatalln()
 	}
 
-	msg = fmt.Sprintf(msg, data...)
+	msg = fmt.Sprintf(i18n.T(msg), data...)
 	errmsg := err.String()
 	if !globalDebug {
 		var e error
 		if errors.Is(globalContext.Err(), context.Canceled) {
 			// mc is getting killed
-			e = errors.New("Canceling upon user request")
+			e = errors.New(i18n.T("Canceling upon user request"))
 		} else {
 			e = err.ToGoError()
 		}
@@ -158,12 +162,12 @@ func errorIf(err *probe.Error, msg string, data ...interface{}) {
 		console.Println(string(json))
 		return
 	}
-	msg = fmt.Sprintf(msg, data...)
+	msg = fmt.Sprintf(i18n.T(msg), data...)
 	if !globalDebug {
 		var e error
 		if errors.Is(globalContext.Err(), context.Canceled) {
 			// mc is getting killed
-			e = errors.New("Canceling upon user request")
+			e = errors.New(i18n.T("Canceling upon user request"))
 		} else {
 			e = err.ToGoError()
 		}
@@ -173,8 +177,54 @@ func errorIf(err *probe.Error, msg string, data ...interface{}) {
 	console.Errorln(fmt.Sprintf("%s %s", msg, err))
 }
 
+// Types of warnings.
+const (
+	// An object was skipped, the operation went on with the others.
+	warningSkipped = "skipped"
+	// An object was processed with a lower fidelity than requested,
+	// e.g. its tags could not be preserved.
+	warningDowngraded = "downgraded"
+)
+
+var warningColorOnce sync.Once
+
+// warningIf prints a warning of the given type when err is not nil. In
+// JSON mode, warnings have their own "warning" status, apart from the
+// results and the errors, so that scripts can detect partial-fidelity
+// operations.
+func warningIf(warningType string, err *probe.Error, msg string, data ...interface{}) {
+	if err == nil {
+		return
+	}
+	if globalJSON {
+		json, e := json.MarshalIndent(struct {
+			Status  string       `json:"status"`
+			Warning errorMessage `json:"warning"`
+		}{
+			Status: "warning",
+			Warning: errorMessage{
+				Message: fmt.Sprintf(msg, data...),
+				Type:    warningType,
+				Cause: causeMessage{
+					Message: err.ToGoError().Error(),
+					Error:   err.ToGoError(),
+				},
+			},
+		}, "", " ")
+		if e != nil {
+			console.Fatalln(probe.NewError(e))
+		}
+		console.Println(string(json))
+		return
+	}
+	warningColorOnce.Do(func() {
+		console.SetColor("Warning", color.New(color.FgYellow, color.Bold))
+	})
+	fmt.Fprintln(os.Stderr, console.Colorize("Warning", "mc: <WARNING> ")+fmt.Sprintf(i18n.T(msg), data...)+" "+err.ToGoError().Error())
+}
+
 // deprecatedError function for deprecated commands
 func deprecatedError(newCommandName string) {
-	err := probe.NewError(fmt.Errorf("Please use '%s' instead", newCommandName))
+	err := probe.NewError(fmt.Errorf(i18n.T("Please use '%s' instead"), newCommandName))
 	fatal(err, "Deprecated command")
 }
@@ -28,7 +28,12 @@ import (
 	"github.com/trinet2005/oss-pkg/console"
 )
 
-var eventListFlags = []cli.Flag{}
+var eventListFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "status",
+		Usage: "show whether the target of every ARN is online, its queued events and the events it failed to deliver since the servers started",
+	},
+}
 
 var eventListCmd = cli.Command{
 	Name:         "list",
@@ -53,6 +58,9 @@ EXAMPLES:
 
   2. List all notification configurations
     {{.Prompt}} {{.HelpName}} s3/mybucket
+
+  3. List all notification configurations with the delivery health of their targets
+    {{.Prompt}} {{.HelpName}} --status myminio/mybucket
 `,
 }
 
@@ -71,6 +79,8 @@ type eventListMessage struct {
 	Prefix string   `json:"prefix"`
 	Suffix string   `json:"suffix"`
 	Arn    string   `json:"arn"`
+
+	TargetStatus *eventTargetStatus `json:"targetStatus,omitempty"`
 }
 
 func (u eventListMessage) JSON() string {
@@ -95,6 +105,13 @@ func (u eventListMessage) String() string {
 	if u.Suffix != "" {
 		msg += console.Colorize("Filter", fmt.Sprintf("suffix=\"%s\"", u.Suffix))
 	}
+	if u.TargetStatus != nil {
+		theme := "TargetOnline"
+		if u.TargetStatus.State != "online" || (u.TargetStatus.QueuedEvents != nil && *u.TargetStatus.QueuedEvents > 0) {
+			theme = "TargetOffline"
+		}
+		msg += "   Target: " + console.Colorize(theme, u.TargetStatus.String())
+	}
 	return msg
 }
 
@@ -105,6 +122,8 @@ func mainEventList(cliCtx *cli.Context) error {
 	console.SetColor("ARN", color.New(color.FgGreen, color.Bold))
 	console.SetColor("Event", color.New(color.FgCyan, color.Bold))
 	console.SetColor("Filter", color.New(color.Bold))
+	console.SetColor("TargetOnline", color.New(color.FgGreen))
+	console.SetColor("TargetOffline", color.New(color.FgRed, color.Bold))
 
 	checkEventListSyntax(cliCtx)
 
@@ -128,14 +147,28 @@ func mainEventList(cliCtx *cli.Context) error {
 	configs, err := s3Client.ListNotificationConfigs(ctx, arn)
 	fatalIf(err, "Unable to list notifications on the specified bucket.")
 
+	var statuses map[string]eventTargetStatus
+	if cliCtx.Bool("status") {
+		statuses, err = eventTargetStatuses(ctx, path)
+		fatalIf(err, "Unable to fetch the status of the notification targets.")
+	}
+
 	for _, config := range configs {
-		printMsg(eventListMessage{
+		msg := eventListMessage{
 			Event:  config.Events,
 			Prefix: config.Prefix,
 			Suffix: config.Suffix,
 			Arn:    config.Arn,
 			ID:     config.ID,
-		})
+		}
+		if statuses != nil {
+			status, ok := statuses[eventTargetKey(config.Arn)]
+			if !ok {
+				status.State = "unknown"
+			}
+			msg.TargetStatus = &status
+		}
+		printMsg(msg)
 	}
 
 	return nil
@@ -0,0 +1,197 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/prom2json"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+// Per target notification metrics of the cluster metrics.
+const (
+	metricNotifyTargetTotal  = "minio_notify_target_total_events"
+	metricNotifyTargetFailed = "minio_notify_target_failed_events"
+	metricNotifyTargetQueue  = "minio_notify_target_queue_length"
+)
+
+// eventTargetStatus is the delivery health of the target of an ARN.
+// The events sent and failed are counted by all the servers since
+// they started, the queued events are the current ones.
+type eventTargetStatus struct {
+	State                  string `json:"state"`
+	TotalEventsSinceStart  *int64 `json:"totalEventsSinceStart,omitempty"`
+	FailedEventsSinceStart *int64 `json:"failedEventsSinceStart,omitempty"`
+	QueuedEvents           *int64 `json:"queuedEvents,omitempty"`
+}
+
+// String returns the state of the target followed by its
+// delivery counters, when the server reports them.
+func (s eventTargetStatus) String() string {
+	msg := s.State
+	if s.FailedEventsSinceStart != nil {
+		msg += fmt.Sprintf(", %d failed", *s.FailedEventsSinceStart)
+		if s.TotalEventsSinceStart != nil {
+			msg += fmt.Sprintf(" of %d", *s.TotalEventsSinceStart)
+		}
+		msg += " since start"
+	}
+	if s.QueuedEvents != nil {
+		msg += fmt.Sprintf(", %d queued", *s.QueuedEvents)
+	}
+	return msg
+}
+
+// eventTargetKey returns the key identifying the target of an ARN,
+// e.g. '1:webhook' for 'arn:minio:sqs:us-east-1:1:webhook'.
+func eventTargetKey(arn string) string {
+	fields := strings.Split(arn, ":")
+	if len(fields) < 6 {
+		return arn
+	}
+	return fields[4] + ":" + fields[5]
+}
+
+// eventTargetStatuses returns the status of all the notification
+// targets of the server of aliasedURL, keyed by eventTargetKey. The
+// state comes from the server information, the delivery counters
+// from the cluster metrics, summed over all the servers; servers
+// without per target metrics only report the state.
+func eventTargetStatuses(ctx context.Context, aliasedURL string) (map[string]eventTargetStatus, *probe.Error) {
+	client, err := newAdminClient(aliasedURL)
+	if err != nil {
+		return nil, err.Trace(aliasedURL)
+	}
+	info, e := client.ServerInfo(ctx)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(aliasedURL)
+	}
+
+	statuses := make(map[string]eventTargetStatus)
+	for _, byName := range info.Services.Notifications {
+		for name, targets := range byName {
+			for _, target := range targets {
+				for id, status := range target {
+					statuses[id+":"+name] = eventTargetStatus{State: status.Status}
+				}
+			}
+		}
+	}
+
+	families, err := fetchClusterMetrics(ctx, aliasedURL)
+	if err != nil {
+		errorIf(err.Trace(aliasedURL), "Unable to fetch the delivery metrics of the notification targets.")
+		return statuses, nil
+	}
+	addEventTargetMetrics(statuses, families)
+	return statuses, nil
+}
+
+// addEventTargetMetrics adds the per target notification metrics of
+// families to statuses. Every server of a cluster reports its own
+// counters for a target, they are summed.
+func addEventTargetMetrics(statuses map[string]eventTargetStatus, families []*dto.MetricFamily) {
+	add := func(counter **int64, value int64) {
+		if *counter == nil {
+			*counter = new(int64)
+		}
+		**counter += value
+	}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			var id, name string
+			for _, label := range metric.GetLabel() {
+				switch label.GetName() {
+				case "target_id":
+					id = label.GetValue()
+				case "target_name":
+					name = label.GetValue()
+				}
+			}
+			if id == "" || name == "" {
+				continue
+			}
+			status, ok := statuses[id+":"+name]
+			if !ok {
+				status.State = "unknown"
+			}
+			value := int64(metric.GetCounter().GetValue() + metric.GetGauge().GetValue() + metric.GetUntyped().GetValue())
+			switch family.GetName() {
+			case metricNotifyTargetTotal:
+				add(&status.TotalEventsSinceStart, value)
+			case metricNotifyTargetFailed:
+				add(&status.FailedEventsSinceStart, value)
+			case metricNotifyTargetQueue:
+				add(&status.QueuedEvents, value)
+			default:
+				continue
+			}
+			statuses[id+":"+name] = status
+		}
+	}
+}
+
+// fetchClusterMetrics returns the Prometheus cluster metrics of the
+// server of aliasedURL.
+func fetchClusterMetrics(ctx context.Context, aliasedURL string) ([]*dto.MetricFamily, *probe.Error) {
+	_, _, hostConfig, err := expandAlias(aliasedURL)
+	if err != nil {
+		return nil, err.Trace(aliasedURL)
+	}
+	if hostConfig == nil {
+		return nil, errInvalidAliasedURL(aliasedURL)
+	}
+	token, e := getPrometheusToken(hostConfig)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	req, e := http.NewRequestWithContext(ctx, http.MethodGet, hostConfig.URL+metricsEndPointRoot+"cluster", nil)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	req.Header.Add("Authorization", "Bearer "+token)
+	resp, e := httpClient(60 * time.Second).Do(req)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, probe.NewError(fmt.Errorf("metrics request failed with %s", resp.Status))
+	}
+
+	mfChan := make(chan *dto.MetricFamily)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- prom2json.ParseReader(io.LimitReader(resp.Body, metricsRespBodyLimit), mfChan)
+	}()
+	var families []*dto.MetricFamily
+	for mf := range mfChan {
+		families = append(families, mf)
+	}
+	if e = <-errCh; e != nil {
+		return nil, probe.NewError(e)
+	}
+	return families, nil
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/prom2json"
+)
+
+// Cluster metrics of a two nodes deployment with two targets.
+const eventTargetMetricsFixture = `# HELP minio_notify_target_total_events Total number of events sent to the target
+# TYPE minio_notify_target_total_events counter
+minio_notify_target_total_events{server="node1:9000",target_id="1",target_name="webhook"} 120
+minio_notify_target_total_events{server="node2:9000",target_id="1",target_name="webhook"} 80
+minio_notify_target_total_events{server="node1:9000",target_id="2",target_name="kafka"} 10
+minio_notify_target_total_events{server="node2:9000",target_id="2",target_name="kafka"} 5
+# HELP minio_notify_target_failed_events Number of events failed to be sent to the target
+# TYPE minio_notify_target_failed_events counter
+minio_notify_target_failed_events{server="node1:9000",target_id="1",target_name="webhook"} 3
+minio_notify_target_failed_events{server="node2:9000",target_id="1",target_name="webhook"} 4
+minio_notify_target_failed_events{server="node1:9000",target_id="2",target_name="kafka"} 0
+minio_notify_target_failed_events{server="node2:9000",target_id="2",target_name="kafka"} 0
+# HELP minio_notify_target_queue_length Number of events currently staged in the queue_dir configured for the target
+# TYPE minio_notify_target_queue_length gauge
+minio_notify_target_queue_length{server="node1:9000",target_id="1",target_name="webhook"} 2
+minio_notify_target_queue_length{server="node2:9000",target_id="1",target_name="webhook"} 1
+# HELP minio_node_file_descriptor_open_total Total number of open file descriptors by the MinIO Server process
+# TYPE minio_node_file_descriptor_open_total gauge
+minio_node_file_descriptor_open_total{server="node1:9000"} 100
+`
+
+func TestAddEventTargetMetrics(t *testing.T) {
+	mfChan := make(chan *dto.MetricFamily)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- prom2json.ParseReader(strings.NewReader(eventTargetMetricsFixture), mfChan)
+	}()
+	var families []*dto.MetricFamily
+	for mf := range mfChan {
+		families = append(families, mf)
+	}
+	if e := <-errCh; e != nil {
+		t.Fatalf("unexpected error: %v", e)
+	}
+
+	statuses := map[string]eventTargetStatus{
+		"1:webhook": {State: "online"},
+	}
+	addEventTargetMetrics(statuses, families)
+
+	testCases := []struct {
+		key      string
+		expected string
+	}{
+		{"1:webhook", "online, 7 failed of 200 since start, 3 queued"},
+		// Unknown to the server information.
+		{"2:kafka", "unknown, 0 failed of 15 since start"},
+	}
+	for i, testCase := range testCases {
+		status, ok := statuses[testCase.key]
+		if !ok {
+			t.Fatalf("Test %d: expected a status for %s", i+1, testCase.key)
+		}
+		if got := status.String(); got != testCase.expected {
+			t.Fatalf("Test %d: expected `%s`, got `%s`", i+1, testCase.expected, got)
+		}
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("expected 2 targets, got %d", len(statuses))
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+// fileListEntry is one object read from a --files-from list.
+type fileListEntry struct {
+	Key       string `json:"key"`
+	VersionID string `json:"versionId"`
+}
+
+// parseFileListLine parses one line of a --files-from list, either
+// a plain object key or a JSON document with 'key' and 'versionId'
+// fields such as the output of 'mc ls --json'.
+func parseFileListLine(line string) (fileListEntry, *probe.Error) {
+	if !strings.HasPrefix(strings.TrimSpace(line), "{") {
+		return fileListEntry{Key: strings.TrimRight(line, "\r")}, nil
+	}
+	var entry fileListEntry
+	if e := json.Unmarshal([]byte(line), &entry); e != nil {
+		return entry, probe.NewError(e).Trace(line)
+	}
+	if entry.Key == "" {
+		return entry, errInvalidArgument().Trace(line)
+	}
+	return entry, nil
+}
+
+// readFileList calls fn for every entry of the --files-from list at
+// listPath, '-' reads the list from STDIN. Empty lines are ignored.
+func readFileList(listPath string, fn func(fileListEntry) *probe.Error) *probe.Error {
+	var r io.Reader = os.Stdin
+	if listPath != "-" {
+		f, e := os.Open(listPath)
+		if e != nil {
+			return probe.NewError(e).Trace(listPath)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		entry, err := parseFileListLine(scanner.Text())
+		if err != nil {
+			return err.Trace(listPath)
+		}
+		if err = fn(entry); err != nil {
+			return err
+		}
+	}
+	if e := scanner.Err(); e != nil {
+		return probe.NewError(e).Trace(listPath)
+	}
+	return nil
+}
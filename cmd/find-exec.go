@@ -0,0 +1,250 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/google/shlex"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+// findExecArg is an argument of the --exec command line, with
+// its Go template when it has {{...}} placeholders.
+type findExecArg struct {
+	text string
+	tmpl *template.Template
+}
+
+// findExecutor runs the --exec command line for every matching object
+// with a pool of workers. Unless --continue is set, no new command is
+// started once one of them failed.
+type findExecutor struct {
+	args            []findExecArg
+	continueOnError bool
+
+	jobs    chan contentMessage
+	wg      sync.WaitGroup // workers
+	running sync.WaitGroup // commands being run
+
+	mu         sync.Mutex // serializes the output of the commands
+	succeeded  int64
+	failed     int64
+	exitStatus int           // exit status of the first failed command
+	statuses   map[int]int64 // number of failed commands by exit status
+	halted     bool
+}
+
+// findExecSummaryMessage reports the commands run by --exec.
+type findExecSummaryMessage struct {
+	Status       string           `json:"status"`
+	Succeeded    int64            `json:"succeeded"`
+	Failed       int64            `json:"failed"`
+	ExitStatuses map[string]int64 `json:"exitStatuses"`
+}
+
+// String colorized exec summary message
+func (s findExecSummaryMessage) String() string {
+	statuses := make([]string, 0, len(s.ExitStatuses))
+	for status := range s.ExitStatuses {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		a, _ := strconv.Atoi(statuses[i])
+		b, _ := strconv.Atoi(statuses[j])
+		return a < b
+	})
+	for i, status := range statuses {
+		statuses[i] = fmt.Sprintf("%d with exit status %s", s.ExitStatuses[status], status)
+	}
+	return console.Colorize("FindExecErr", fmt.Sprintf("%d command(s) failed (%s), %d succeeded.",
+		s.Failed, strings.Join(statuses, ", "), s.Succeeded))
+}
+
+// JSON jsonified exec summary message
+func (s findExecSummaryMessage) JSON() string {
+	s.Status = "error"
+	msgBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(msgBytes)
+}
+
+// parseFindExec splits the --exec command line into its arguments,
+// parsing the Go templates they contain.
+func parseFindExec(cmdline string) ([]findExecArg, *probe.Error) {
+	split, e := shlex.Split(cmdline)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(cmdline)
+	}
+	if len(split) == 0 {
+		return nil, errInvalidArgument().Trace(cmdline)
+	}
+	args := make([]findExecArg, len(split))
+	for i, arg := range split {
+		args[i].text = arg
+		if !strings.Contains(arg, "{{") {
+			continue
+		}
+		tmpl, e := template.New("exec").Option("missingkey=zero").Parse(arg)
+		if e != nil {
+			return nil, probe.NewError(e).Trace(arg)
+		}
+		args[i].tmpl = tmpl
+	}
+	return args, nil
+}
+
+// findExecArgv returns the command line run for an object.
+func findExecArgv(ctx context.Context, args []findExecArg, content contentMessage) ([]string, error) {
+	argv := make([]string, len(args))
+	for i, arg := range args {
+		text := arg.text
+		if arg.tmpl != nil {
+			var buf bytes.Buffer
+			if e := arg.tmpl.Execute(&buf, content); e != nil {
+				return nil, e
+			}
+			text = buf.String()
+		}
+		argv[i] = stringsReplace(ctx, text, content)
+	}
+	return argv, nil
+}
+
+// newFindExecutor starts workers running cmdline for every submitted object.
+func newFindExecutor(ctx context.Context, cmdline string, workers int, continueOnError bool) (*findExecutor, *probe.Error) {
+	args, err := parseFindExec(cmdline)
+	if err != nil {
+		return nil, err
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	x := &findExecutor{
+		args:            args,
+		continueOnError: continueOnError,
+		jobs:            make(chan contentMessage),
+		statuses:        make(map[int]int64),
+	}
+	for i := 0; i < workers; i++ {
+		x.wg.Add(1)
+		go func() {
+			defer x.wg.Done()
+			for content := range x.jobs {
+				x.run(ctx, content)
+			}
+		}()
+	}
+	return x, nil
+}
+
+// submit queues the command for an object, returning false once halted.
+func (x *findExecutor) submit(content contentMessage) bool {
+	x.mu.Lock()
+	halted := x.halted
+	x.mu.Unlock()
+	if halted {
+		return false
+	}
+	x.jobs <- content
+	return true
+}
+
+// run executes the command line for an object.
+func (x *findExecutor) run(ctx context.Context, content contentMessage) {
+	x.mu.Lock()
+	if x.halted {
+		x.mu.Unlock()
+		return
+	}
+	x.running.Add(1)
+	x.mu.Unlock()
+	defer x.running.Done()
+
+	argv, e := findExecArgv(ctx, x.args, content)
+	if e != nil {
+		x.done(e, nil, nil)
+		return
+	}
+
+	cmd := exec.Command(argv[0], argv[1:]...)
+	var out, stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	x.done(cmd.Run(), &out, &stderr)
+}
+
+// done prints the outcome of a command and records it.
+func (x *findExecutor) done(e error, out, stderr *bytes.Buffer) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if e == nil {
+		x.succeeded++
+		console.PrintC(out.String())
+		return
+	}
+	if stderr != nil && stderr.Len() > 0 {
+		console.Println(console.Colorize("FindExecErr", strings.TrimSpace(stderr.String())))
+	}
+	console.Println(console.Colorize("FindExecErr", e.Error()))
+	status := getExitStatus(e)
+	if x.failed == 0 {
+		x.exitStatus = status
+	}
+	x.failed++
+	x.statuses[status]++
+	if !x.continueOnError && !x.halted {
+		// Let the commands already started complete, as
+		// they would be orphaned otherwise, then exit.
+		x.halted = true
+		go func() {
+			x.running.Wait()
+			os.Exit(x.exitStatus)
+		}()
+	}
+}
+
+// wait waits for the running commands. If any failed, a summary is
+// printed and the process exits with the status of the first failure.
+func (x *findExecutor) wait() {
+	close(x.jobs)
+	x.wg.Wait()
+	if x.failed == 0 {
+		return
+	}
+	if x.continueOnError {
+		statuses := make(map[string]int64, len(x.statuses))
+		for status, count := range x.statuses {
+			statuses[strconv.Itoa(status)] = count
+		}
+		printMsg(findExecSummaryMessage{Succeeded: x.succeeded, Failed: x.failed, ExitStatuses: statuses})
+	}
+	os.Exit(x.exitStatus)
+}
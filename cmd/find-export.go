@@ -0,0 +1,163 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/csv"
+	gojson "encoding/json"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+// findInventoryRecord is an object version of a --export inventory.
+type findInventoryRecord struct {
+	Key          string            `json:"key"`
+	VersionID    string            `json:"versionId,omitempty"`
+	Size         int64             `json:"size"`
+	ETag         string            `json:"etag"`
+	LastModified time.Time         `json:"lastModified"`
+	StorageClass string            `json:"storageClass,omitempty"`
+	Tags         map[string]string `json:"tags,omitempty"`
+}
+
+// findInventoryHeader is the header row of CSV inventories.
+var findInventoryHeader = []string{"key", "versionId", "size", "etag", "lastModified", "storageClass", "tags"}
+
+// findExporter writes the objects matched by find as a CSV or JSON
+// lines inventory, to the standard output or streamed to a local
+// file or an object.
+type findExporter struct {
+	format  string
+	target  string
+	csv     *csv.Writer
+	enc     *gojson.Encoder
+	pw      *io.PipeWriter
+	putErr  chan *probe.Error
+	records int64
+}
+
+// newFindExporter returns an exporter in format, 'csv' or 'jsonl',
+// writing to target, the standard output if empty.
+func newFindExporter(format, target string) *findExporter {
+	x := &findExporter{format: format, target: target}
+	var w io.Writer = os.Stdout
+	if target != "" {
+		pr, pw := io.Pipe()
+		x.pw = pw
+		x.putErr = make(chan *probe.Error, 1)
+		go func() {
+			_, err := putTargetStreamWithURL(target, pr, -1, PutOptions{})
+			pr.CloseWithError(err.ToGoError())
+			x.putErr <- err
+		}()
+		w = pw
+	}
+	switch format {
+	case "csv":
+		x.csv = csv.NewWriter(w)
+		x.csv.Write(findInventoryHeader)
+	default:
+		x.enc = gojson.NewEncoder(w)
+		x.enc.SetEscapeHTML(false)
+	}
+	return x
+}
+
+// encodeInventoryTags returns the tags in the URL encoded form of
+// the S3 tagging header.
+func encodeInventoryTags(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	values := make(url.Values, len(tags))
+	for _, key := range keys {
+		values.Set(key, tags[key])
+	}
+	return values.Encode()
+}
+
+// write adds an object version to the inventory.
+func (x *findExporter) write(rec findInventoryRecord) *probe.Error {
+	x.records++
+	if x.csv != nil {
+		x.csv.Write([]string{
+			rec.Key,
+			rec.VersionID,
+			strconv.FormatInt(rec.Size, 10),
+			rec.ETag,
+			rec.LastModified.UTC().Format(time.RFC3339),
+			rec.StorageClass,
+			encodeInventoryTags(rec.Tags),
+		})
+		return probe.NewError(x.csv.Error())
+	}
+	rec.LastModified = rec.LastModified.UTC()
+	return probe.NewError(x.enc.Encode(rec))
+}
+
+// close flushes the inventory and waits for its upload.
+func (x *findExporter) close() *probe.Error {
+	var err *probe.Error
+	if x.csv != nil {
+		x.csv.Flush()
+		err = probe.NewError(x.csv.Error())
+	}
+	if x.pw == nil {
+		return err
+	}
+	x.pw.CloseWithError(err.ToGoError())
+	if putErr := <-x.putErr; putErr != nil {
+		return putErr.Trace(x.target)
+	}
+	if err == nil {
+		printMsg(findExportMessage{Target: x.target, Format: x.format, Objects: x.records})
+	}
+	return err
+}
+
+// findExportMessage reports an inventory written to a file or an object.
+type findExportMessage struct {
+	Status  string `json:"status"`
+	Target  string `json:"target"`
+	Format  string `json:"format"`
+	Objects int64  `json:"objects"`
+}
+
+// String colorized find export message
+func (f findExportMessage) String() string {
+	return console.Colorize("Find", "Exported "+strconv.FormatInt(f.Objects, 10)+" object(s) to `"+f.Target+"`.")
+}
+
+// JSON jsonified find export message
+func (f findExportMessage) JSON() string {
+	f.Status = "success"
+	findExportMessageBytes, e := json.MarshalIndent(f, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(findExportMessageBytes)
+}
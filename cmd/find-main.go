@@ -37,6 +37,19 @@ var (
 			Name:  "exec",
 			Usage: "spawn an external process for each matching object (see FORMAT)",
 		},
+		cli.IntFlag{
+			Name:  "exec-workers, exec-parallel",
+			Value: 1,
+			Usage: "number of --exec commands run in parallel",
+		},
+		cli.BoolFlag{
+			Name:  "halt-on-error",
+			Usage: "stop starting --exec commands after the first failure (default)",
+		},
+		cli.BoolFlag{
+			Name:  "continue",
+			Usage: "keep running --exec commands after a failure, printing a summary of the failures",
+		},
 		cli.StringFlag{
 			Name:  "ignore",
 			Usage: "exclude objects matching the wildcard pattern",
@@ -93,6 +106,26 @@ var (
 			Name:  "tags",
 			Usage: "match tags with RE2 regex pattern. Specify each with key=regex. MinIO server only.",
 		},
+		cli.StringFlag{
+			Name:  "where",
+			Usage: "match objects with an expression of their fields (see EXPRESSIONS)",
+		},
+		cli.StringFlag{
+			Name:  "export",
+			Usage: "write an inventory of the matching objects as 'csv' or 'jsonl'",
+		},
+		cli.StringFlag{
+			Name:  "export-to",
+			Usage: "write the --export inventory to a local file or an object instead of STDOUT",
+		},
+		cli.BoolFlag{
+			Name:  "delete-markers-only",
+			Usage: "match only the delete markers, of all versions",
+		},
+		cli.BoolFlag{
+			Name:  "latest-is-delete-marker",
+			Usage: "match only deleted objects, whose latest version is a delete marker",
+		},
 	}
 )
 
@@ -137,6 +170,23 @@ FORMAT
 
      {url} --> Substitutes to a shareable URL of the path.
 
+  Arguments of --exec also accept Go templates of the fields of the object,
+  such as {{"{{.Key}}"}}, {{"{{.Size}}"}}, {{"{{.VersionID}}"}}, {{"{{.ETag}}"}} and {{"{{.Time}}"}}.
+
+EXPRESSIONS
+  --where combines comparisons of the fields of objects with '&&' (or 'and'), '||'
+  (or 'or'), '!' (or 'not') and parentheses. The fields are:
+
+     name, path             base name and path relative to TARGET
+     size                   size, compared with a number in units (see UNITS)
+     age                    time since the last modification, compared with a duration
+     etag, version          ETag and version identifier
+     storageclass           storage class
+     tags.KEY, metadata.KEY value of a tag or of user metadata (MinIO server only)
+
+  Sizes and ages are compared with ==, !=, <, <=, > and >=, other fields with ==, !=,
+  ~ (matches a RE2 regex) and !~. Strings are quoted, in double quotes Go escapes apply.
+
 EXAMPLES:
   01. Find all "foo.jpg" in all buckets under "s3" account.
       {{.Prompt}} {{.HelpName}} s3 --name "foo.jpg"
@@ -171,6 +221,21 @@ EXAMPLES:
 
   11. Copy all versions of all objects in bucket in the local machine
       {{.Prompt}} {{.HelpName}} s3/bucket --versions --exec "mc cp --version-id {version} {} /tmp/dir/{}.{version}"
+
+  12. Tag all objects larger than 1GiB running 16 commands in parallel, reporting the failures at the end.
+      {{.Prompt}} {{.HelpName}} s3/bucket --larger 1GiB --exec-workers 16 --continue --exec "mc tag set {{"{{.Key}}"}} size={{"{{.Size}}"}}"
+
+  13. Find the Parquet files larger than 1GiB of the production datasets, or older than 90 days in any storage class but GLACIER.
+      {{.Prompt}} {{.HelpName}} s3/datalake --where 'name ~ "\.parquet$" && (size > 1GiB && tags.env == "prod" || age > 90d && storageclass != "GLACIER")'
+
+  14. Copy all the archives to another bucket 8 at a time, counting the failures by exit status.
+      {{.Prompt}} {{.HelpName}} s3/archives --name "*.tgz" --exec-parallel 8 --continue --exec "mc cp {} play/backup/{base}"
+
+  15. Write a CSV inventory of all the versions of a bucket, with their ETag, storage class and tags, to an object.
+      {{.Prompt}} {{.HelpName}} s3/bucket --versions --export csv --export-to s3/reports/bucket-inventory.csv
+
+  16. Restore the objects deleted from a bucket in the last day by removing their delete markers.
+      {{.Prompt}} {{.HelpName}} s3/bucket --latest-is-delete-marker --newer-than 1d --exec "mc rm --version-id {version} {}"
 `,
 }
 
@@ -183,6 +248,38 @@ func checkFindSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[stri
 		args[0] = "./" // If the arg is '.' treat it as './'.
 	}
 
+	if cliCtx.Bool("halt-on-error") && cliCtx.Bool("continue") {
+		fatalIf(errInvalidArgument(), "--halt-on-error and --continue cannot be used together.")
+	}
+	if cliCtx.Int("exec-workers") < 1 {
+		fatalIf(errInvalidArgument().Trace(cliCtx.String("exec-workers")), "--exec-workers should be at least 1.")
+	}
+	if cmdline := cliCtx.String("exec"); cmdline != "" {
+		_, err := parseFindExec(cmdline)
+		fatalIf(err, "Unable to parse --exec.")
+	}
+	if text := cliCtx.String("where"); text != "" {
+		_, err := parseFindWhere(text)
+		fatalIf(err, "Unable to parse --where.")
+	}
+	if (cliCtx.Bool("delete-markers-only") || cliCtx.Bool("latest-is-delete-marker")) && cliCtx.Bool("watch") {
+		fatalIf(errInvalidArgument(), "--delete-markers-only and --latest-is-delete-marker cannot be used with --watch.")
+	}
+	switch format := cliCtx.String("export"); format {
+	case "":
+		if cliCtx.String("export-to") != "" {
+			fatalIf(errInvalidArgument(), "--export-to requires --export.")
+		}
+	case "csv", "jsonl":
+		for _, flag := range []string{"exec", "print", "watch"} {
+			if cliCtx.IsSet(flag) {
+				fatalIf(errInvalidArgument(), "--export cannot be used with --"+flag+".")
+			}
+		}
+	default:
+		fatalIf(errInvalidArgument().Trace(format), "--export should be 'csv' or 'jsonl'.")
+	}
+
 	for _, arg := range args {
 		if strings.TrimSpace(arg) == "" {
 			fatalIf(errInvalidArgument().Trace(args...), "Unable to validate empty argument.")
@@ -207,7 +304,6 @@ func checkFindSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[stri
 // ease of repurposing.
 type findContext struct {
 	*cli.Context
-	execCmd           string
 	ignorePattern     string
 	namePattern       string
 	pathPattern       string
@@ -222,6 +318,11 @@ type findContext struct {
 	withOlderVersions bool
 	matchMeta         map[string]*regexp.Regexp
 	matchTags         map[string]*regexp.Regexp
+	where             *findWhere
+	executor          *findExecutor
+	exporter          *findExporter
+	markersOnly       bool
+	latestIsMarker    bool
 
 	// Internal values
 	targetAlias   string
@@ -294,10 +395,26 @@ func mainFind(cliCtx *cli.Context) error {
 		regMatch = regexp.MustCompile(cliCtx.String("regex"))
 	}
 
-	return doFind(ctx, &findContext{
+	var where *findWhere
+	if text := cliCtx.String("where"); text != "" {
+		// Validated by checkFindSyntax.
+		where, _ = parseFindWhere(text)
+	}
+
+	var executor *findExecutor
+	if cmdline := cliCtx.String("exec"); cmdline != "" {
+		executor, err = newFindExecutor(ctx, cmdline, cliCtx.Int("exec-workers"), cliCtx.Bool("continue"))
+		fatalIf(err, "Unable to parse --exec.")
+	}
+
+	var exporter *findExporter
+	if format := cliCtx.String("export"); format != "" {
+		exporter = newFindExporter(format, cliCtx.String("export-to"))
+	}
+
+	e = doFind(ctx, &findContext{
 		Context:           cliCtx,
 		maxDepth:          cliCtx.Uint("maxdepth"),
-		execCmd:           cliCtx.String("exec"),
 		printFmt:          cliCtx.String("print"),
 		namePattern:       cliCtx.String("name"),
 		pathPattern:       cliCtx.String("path"),
@@ -315,5 +432,17 @@ func mainFind(cliCtx *cli.Context) error {
 		clnt:              clnt,
 		matchMeta:         getRegexMap(cliCtx, "metadata"),
 		matchTags:         getRegexMap(cliCtx, "tags"),
+		where:             where,
+		executor:          executor,
+		exporter:          exporter,
+		markersOnly:       cliCtx.Bool("delete-markers-only"),
+		latestIsMarker:    cliCtx.Bool("latest-is-delete-marker"),
 	})
+	if executor != nil {
+		executor.wait()
+	}
+	if exporter != nil {
+		fatalIf(exporter.close(), "Unable to write the inventory.")
+	}
+	return e
 }
@@ -0,0 +1,401 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+// findRecord holds the fields of an object tested by --where.
+type findRecord struct {
+	path         string // relative to the find target
+	size         int64
+	modTime      time.Time
+	etag         string
+	versionID    string
+	storageClass string
+	tags         map[string]string
+	metadata     map[string]string
+}
+
+// findExpr is a node of a --where expression.
+type findExpr interface {
+	eval(r *findRecord) bool
+}
+
+type findAnd struct{ left, right findExpr }
+
+func (e findAnd) eval(r *findRecord) bool { return e.left.eval(r) && e.right.eval(r) }
+
+type findOr struct{ left, right findExpr }
+
+func (e findOr) eval(r *findRecord) bool { return e.left.eval(r) || e.right.eval(r) }
+
+type findNot struct{ expr findExpr }
+
+func (e findNot) eval(r *findRecord) bool { return !e.expr.eval(r) }
+
+// findCompare compares a field of the object with a value: a number
+// of bytes for size, a duration for age, a string or a regular
+// expression for the other fields.
+type findCompare struct {
+	field string // name, path, size, age, etag, version, storageclass, tags or metadata
+	key   string // key of tags and metadata
+	op    string
+	str   string
+	num   int64
+	re    *regexp.Regexp
+}
+
+func (e findCompare) eval(r *findRecord) bool {
+	switch e.field {
+	case "size":
+		return compareInt(r.size, e.op, e.num)
+	case "age":
+		return compareInt(int64(time.Since(r.modTime)), e.op, e.num)
+	}
+
+	var value string
+	switch e.field {
+	case "name":
+		value = path.Base(r.path)
+	case "path":
+		value = r.path
+	case "etag":
+		value = strings.Trim(r.etag, "\"")
+	case "version":
+		value = r.versionID
+	case "storageclass":
+		value = r.storageClass
+	case "tags":
+		value = r.tags[e.key]
+	case "metadata":
+		value = lookupMetadata(r.metadata, e.key)
+	}
+	switch e.op {
+	case "==":
+		return value == e.str
+	case "!=":
+		return value != e.str
+	case "~":
+		return e.re.MatchString(value)
+	case "!~":
+		return !e.re.MatchString(value)
+	}
+	return false
+}
+
+// compareInt returns the result of 'a op b'.
+func compareInt(a int64, op string, b int64) bool {
+	switch op {
+	case "==":
+		return a == b
+	case "!=":
+		return a != b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	}
+	return false
+}
+
+// lookupMetadata returns the value of the metadata key, compared
+// case-insensitively and with or without its 'X-Amz-Meta-' prefix.
+func lookupMetadata(metadata map[string]string, key string) string {
+	for k, v := range metadata {
+		if strings.EqualFold(k, key) || strings.EqualFold(strings.TrimPrefix(strings.ToLower(k), "x-amz-meta-"), key) {
+			return v
+		}
+	}
+	return ""
+}
+
+// findWhere is a parsed --where expression.
+type findWhere struct {
+	expr          findExpr
+	needsMetadata bool // the expression tests tags or metadata
+}
+
+// match returns true if the object r satisfies the expression.
+func (w *findWhere) match(r *findRecord) bool {
+	return w.expr.eval(r)
+}
+
+// Kinds of tokens of a --where expression.
+const (
+	findTokenEOF = iota
+	findTokenIdent
+	findTokenString
+	findTokenNumber
+	findTokenOp
+	findTokenLParen
+	findTokenRParen
+)
+
+type findToken struct {
+	kind int
+	text string
+}
+
+// lexFindWhere splits a --where expression into tokens.
+func lexFindWhere(text string) ([]findToken, error) {
+	var tokens []findToken
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(':
+			tokens = append(tokens, findToken{findTokenLParen, "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, findToken{findTokenRParen, ")"})
+			i++
+		case c == '"' || c == '\'':
+			j := i + 1
+			for ; j < len(text) && text[j] != c; j++ {
+				if text[j] == '\\' && c == '"' {
+					j++
+				}
+			}
+			if j >= len(text) {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+			// Double quoted strings are unquoted like Go strings, unless
+			// they hold escapes Go does not know, such as "\.csv$".
+			value := text[i+1 : j]
+			if unquoted, e := strconv.Unquote(text[i : j+1]); c == '"' && e == nil {
+				value = unquoted
+			}
+			tokens = append(tokens, findToken{findTokenString, value})
+			i = j + 1
+		case strings.ContainsRune("=!<>~&|", rune(c)):
+			op := ""
+			for _, candidate := range []string{"&&", "||", "==", "!=", "<=", ">=", "!~", "<", ">", "~", "!"} {
+				if strings.HasPrefix(text[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unknown operator at offset %d", i)
+			}
+			tokens = append(tokens, findToken{findTokenOp, op})
+			i += len(op)
+		default:
+			j := i
+			for ; j < len(text) && isFindWordChar(text[j]); j++ {
+			}
+			if j == i {
+				return nil, fmt.Errorf("unexpected character %q at offset %d", c, i)
+			}
+			kind := findTokenIdent
+			if c >= '0' && c <= '9' {
+				kind = findTokenNumber
+			}
+			tokens = append(tokens, findToken{kind, text[i:j]})
+			i = j
+		}
+	}
+	return append(tokens, findToken{kind: findTokenEOF}), nil
+}
+
+func isFindWordChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-' || c == '.'
+}
+
+// findParser is a recursive descent parser of --where expressions:
+//
+//	expr       = and { ("||" | "or") and }
+//	and        = unary { ("&&" | "and") unary }
+//	unary      = ("!" | "not") unary | "(" expr ")" | comparison
+//	comparison = field op value
+type findParser struct {
+	tokens        []findToken
+	pos           int
+	needsMetadata bool
+}
+
+func (p *findParser) peek() findToken { return p.tokens[p.pos] }
+
+func (p *findParser) next() findToken {
+	t := p.tokens[p.pos]
+	if t.kind != findTokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is one of words.
+func (p *findParser) accept(words ...string) bool {
+	t := p.peek()
+	if t.kind != findTokenOp && t.kind != findTokenIdent {
+		return false
+	}
+	for _, w := range words {
+		if strings.EqualFold(t.text, w) {
+			p.pos++
+			return true
+		}
+	}
+	return false
+}
+
+func (p *findParser) parseOr() (findExpr, error) {
+	left, e := p.parseAnd()
+	for e == nil && p.accept("||", "or") {
+		var right findExpr
+		if right, e = p.parseAnd(); e == nil {
+			left = findOr{left, right}
+		}
+	}
+	return left, e
+}
+
+func (p *findParser) parseAnd() (findExpr, error) {
+	left, e := p.parseUnary()
+	for e == nil && p.accept("&&", "and") {
+		var right findExpr
+		if right, e = p.parseUnary(); e == nil {
+			left = findAnd{left, right}
+		}
+	}
+	return left, e
+}
+
+func (p *findParser) parseUnary() (findExpr, error) {
+	if p.accept("!", "not") {
+		expr, e := p.parseUnary()
+		return findNot{expr}, e
+	}
+	if p.peek().kind == findTokenLParen {
+		p.next()
+		expr, e := p.parseOr()
+		if e != nil {
+			return nil, e
+		}
+		if p.next().kind != findTokenRParen {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		return expr, nil
+	}
+	return p.parseComparison()
+}
+
+// findOrderOps are the operators comparing sizes and ages.
+var findOrderOps = map[string]bool{"==": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true}
+
+func (p *findParser) parseComparison() (findExpr, error) {
+	t := p.next()
+	if t.kind != findTokenIdent {
+		return nil, fmt.Errorf("expected a field, got `%s`", t.text)
+	}
+	field, key, _ := strings.Cut(strings.ToLower(t.text), ".")
+	// storage-class, storage_class and sc name the storage class.
+	if field = strings.NewReplacer("-", "", "_", "").Replace(field); field == "sc" {
+		field = "storageclass"
+	}
+	if field == "tags" || field == "metadata" {
+		if key == "" {
+			return nil, fmt.Errorf("field `%s` needs a key, e.g. `%s.owner`", field, field)
+		}
+		// Keep the case of the key, tags are case-sensitive.
+		key = t.text[strings.Index(t.text, ".")+1:]
+		p.needsMetadata = true
+	} else if key != "" {
+		return nil, fmt.Errorf("unknown field `%s`", t.text)
+	}
+
+	opToken := p.next()
+	if opToken.kind != findTokenOp {
+		return nil, fmt.Errorf("expected an operator after `%s`, got `%s`", t.text, opToken.text)
+	}
+	op := opToken.text
+	value := p.next()
+	if value.kind != findTokenString && value.kind != findTokenNumber && value.kind != findTokenIdent {
+		return nil, fmt.Errorf("expected a value after `%s %s`", t.text, op)
+	}
+
+	cmp := findCompare{field: field, key: key, op: op, str: value.text}
+	switch field {
+	case "size":
+		if !findOrderOps[op] {
+			return nil, fmt.Errorf("operator `%s` cannot compare sizes", op)
+		}
+		size, e := humanize.ParseBytes(value.text)
+		if e != nil {
+			return nil, fmt.Errorf("invalid size `%s`", value.text)
+		}
+		cmp.num = int64(size)
+	case "age":
+		if !findOrderOps[op] {
+			return nil, fmt.Errorf("operator `%s` cannot compare ages", op)
+		}
+		age, e := ParseDuration(value.text)
+		if e != nil {
+			return nil, fmt.Errorf("invalid age `%s`", value.text)
+		}
+		cmp.num = int64(age)
+	case "name", "path", "etag", "version", "storageclass", "tags", "metadata":
+		switch op {
+		case "==", "!=":
+		case "~", "!~":
+			re, e := regexp.Compile(value.text)
+			if e != nil {
+				return nil, fmt.Errorf("invalid regular expression `%s`: %v", value.text, e)
+			}
+			cmp.re = re
+		default:
+			return nil, fmt.Errorf("operator `%s` cannot compare %s, use ==, !=, ~ or !~", op, field)
+		}
+	default:
+		return nil, fmt.Errorf("unknown field `%s`", t.text)
+	}
+	return cmp, nil
+}
+
+// parseFindWhere parses a --where expression, such as
+// 'size > 1GiB && tags.env == "prod" && name ~ "\.parquet$"'.
+func parseFindWhere(text string) (*findWhere, *probe.Error) {
+	tokens, e := lexFindWhere(text)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(text)
+	}
+	p := &findParser{tokens: tokens}
+	expr, e := p.parseOr()
+	if e == nil && p.peek().kind != findTokenEOF {
+		e = fmt.Errorf("unexpected `%s`", p.peek().text)
+	}
+	if e != nil {
+		return nil, probe.NewError(e).Trace(text)
+	}
+	return &findWhere{expr: expr, needsMetadata: p.needsMetadata}, nil
+}
@@ -18,10 +18,8 @@
 package cmd
 
 import (
-	"bytes"
 	"context"
 	"fmt"
-	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
@@ -31,7 +29,6 @@ import (
 	"time"
 
 	"github.com/dustin/go-humanize"
-	"github.com/google/shlex"
 	"github.com/minio/cli"
 	"github.com/trinet2005/oss-mc/pkg/probe"
 	"github.com/trinet2005/oss-pkg/console"
@@ -123,36 +120,6 @@ func getExitStatus(err error) int {
 	return 1
 }
 
-// execFind executes the input command line, additionally formats input
-// for the command line in accordance with subsititution arguments.
-func execFind(ctx context.Context, args string, fileContent contentMessage) {
-	split, err := shlex.Split(args)
-	if err != nil {
-		console.Println(console.Colorize("FindExecErr", "Unable to parse --exec: "+err.Error()))
-		os.Exit(getExitStatus(err))
-	}
-	if len(split) == 0 {
-		return
-	}
-	for i, arg := range split {
-		split[i] = stringsReplace(ctx, arg, fileContent)
-	}
-	cmd := exec.Command(split[0], split[1:]...)
-	var out bytes.Buffer
-	var stderr bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
-		if stderr.Len() > 0 {
-			console.Println(console.Colorize("FindExecErr", strings.TrimSpace(stderr.String())))
-		}
-		console.Println(console.Colorize("FindExecErr", err.Error()))
-		// Return exit status of the command run
-		os.Exit(getExitStatus(err))
-	}
-	console.PrintC(out.String())
-}
-
 // watchFind - enables listening on the input path, listens for all file/object
 // created actions. Asynchronously executes the input command line, also allows
 // formatting for the command line in accordance with subsititution arguments.
@@ -252,8 +219,8 @@ func find(ctxCtx context.Context, ctx *findContext, fileContent contentMessage)
 	} // For all matching content
 
 	// proceed to either exec, format the output string.
-	if ctx.execCmd != "" {
-		execFind(ctxCtx, ctx.execCmd, fileContent)
+	if ctx.executor != nil {
+		ctx.executor.submit(fileContent)
 		return
 	}
 	if ctx.printFmt != "" {
@@ -271,13 +238,18 @@ func doFind(ctxCtx context.Context, ctx *findContext) error {
 	defer watchFind(ctxCtx, ctx)
 
 	lstOptions := ListOptions{
-		WithOlderVersions: ctx.withOlderVersions,
-		WithDeleteMarkers: false,
+		WithOlderVersions: ctx.withOlderVersions || ctx.markersOnly,
+		WithDeleteMarkers: ctx.markersOnly || ctx.latestIsMarker,
 		Recursive:         true,
 		ShowDir:           DirFirst,
-		WithMetadata:      len(ctx.matchMeta) > 0 || len(ctx.matchTags) > 0,
+		WithMetadata:      len(ctx.matchMeta) > 0 || len(ctx.matchTags) > 0 || (ctx.where != nil && ctx.where.needsMetadata) || ctx.exporter != nil,
 	}
 
+	// Versions of an object are listed newest first, deleted tells
+	// whether the latest version of the current object is a delete marker.
+	var lastKey string
+	var deleted bool
+
 	// iterate over all content which is within the given directory
 	for content := range ctx.clnt.List(globalContext, lstOptions) {
 		if content.Err != nil {
@@ -299,28 +271,55 @@ func doFind(ctxCtx context.Context, ctx *findContext) error {
 			fatalIf(content.Err.Trace(ctx.clnt.GetURL().String()), "Unable to list folder.")
 			continue
 		}
+		if content.URL.Path != lastKey {
+			lastKey = content.URL.Path
+			deleted = content.IsDeleteMarker
+		}
 		if content.StorageClass == s3StorageClassGlacier {
 			continue
 		}
+		if ctx.markersOnly && !content.IsDeleteMarker || ctx.latestIsMarker && !deleted {
+			continue
+		}
 
 		fileKeyName := getAliasedPath(ctx, content.URL.String())
 		fileContent := contentMessage{
-			Key:       fileKeyName,
-			VersionID: content.VersionID,
-			Time:      content.Time.Local(),
-			Size:      content.Size,
-			Metadata:  content.UserMetadata,
-			Tags:      content.Tags,
+			Key:            fileKeyName,
+			VersionID:      content.VersionID,
+			Time:           content.Time.Local(),
+			Size:           content.Size,
+			Metadata:       content.UserMetadata,
+			Tags:           content.Tags,
+			IsDeleteMarker: content.IsDeleteMarker,
 		}
 
-		// Match the incoming content, didn't match return.
-		if !matchFind(ctx, fileContent) {
+		// Match the incoming content, didn't match return. The storage
+		// class and ETag are only matched by --where, not printed.
+		matchContent := fileContent
+		matchContent.StorageClass = content.StorageClass
+		matchContent.ETag = content.ETag
+		if !matchFind(ctx, matchContent) {
 			continue
 		} // For all matching content
 
-		// proceed to either exec, format the output string.
-		if ctx.execCmd != "" {
-			execFind(ctxCtx, ctx.execCmd, fileContent)
+		// proceed to either exec, export or format the output string.
+		if ctx.executor != nil {
+			if !ctx.executor.submit(fileContent) {
+				break
+			}
+			continue
+		}
+		if ctx.exporter != nil {
+			err := ctx.exporter.write(findInventoryRecord{
+				Key:          fileKeyName,
+				VersionID:    content.VersionID,
+				Size:         content.Size,
+				ETag:         content.ETag,
+				LastModified: content.Time,
+				StorageClass: content.StorageClass,
+				Tags:         content.Tags,
+			})
+			fatalIf(err.Trace(fileKeyName), "Unable to write the inventory.")
 			continue
 		}
 		if ctx.printFmt != "" {
@@ -431,6 +430,18 @@ func matchFind(ctx *findContext, fileContent contentMessage) (match bool) {
 	if match && len(ctx.matchTags) > 0 {
 		match = matchRegexMaps(ctx.matchTags, fileContent.Tags)
 	}
+	if match && ctx.where != nil {
+		match = ctx.where.match(&findRecord{
+			path:         filepath.ToSlash(path),
+			size:         fileContent.Size,
+			modTime:      fileContent.Time,
+			etag:         fileContent.ETag,
+			versionID:    fileContent.VersionID,
+			storageClass: fileContent.StorageClass,
+			tags:         fileContent.Tags,
+			metadata:     fileContent.Metadata,
+		})
+	}
 	return match
 }
 
@@ -409,6 +409,90 @@ func TestStringReplace(t *testing.T) {
 	}
 }
 
+// Tests parsing of the --exec command line and its templates
+func TestParseFindExec(t *testing.T) {
+	content := contentMessage{Key: "s3/bucket/a b.txt", Size: 42, VersionID: "v1"}
+	testCases := []struct {
+		cmdline  string
+		expected []string
+		success  bool
+	}{
+		{`mc stat {}`, []string{"mc", "stat", "s3/bucket/a b.txt"}, true},
+		{`mc tag set "{{.Key}}" size={{.Size}}`, []string{"mc", "tag", "set", "s3/bucket/a b.txt", "size=42"}, true},
+		{`echo {{.VersionID}}/{base}`, []string{"echo", "v1/a b.txt"}, true},
+		{`echo {{.Key`, nil, false},
+		{`echo "unterminated`, nil, false},
+		{``, nil, false},
+	}
+	for i, testCase := range testCases {
+		args, err := parseFindExec(testCase.cmdline)
+		if err != nil && testCase.success {
+			t.Fatalf("Test %d: Expected success, got %s", i+1, err)
+		}
+		if err == nil && !testCase.success {
+			t.Fatalf("Test %d: Expected error, got success", i+1)
+		}
+		if !testCase.success {
+			continue
+		}
+		got, e := findExecArgv(context.Background(), args, content)
+		if e != nil {
+			t.Fatalf("Test %d: Unable to expand arguments: %s", i+1, e)
+		}
+		if strings.Join(got, "|") != strings.Join(testCase.expected, "|") {
+			t.Errorf("Test %d: Expected %q, got %q", i+1, testCase.expected, got)
+		}
+	}
+}
+
+// Tests parsing and evaluation of --where expressions.
+func TestFindWhere(t *testing.T) {
+	record := &findRecord{
+		path:         "datasets/2023/events.parquet",
+		size:         2 << 30,
+		modTime:      time.Now().Add(-100 * 24 * time.Hour),
+		etag:         `"abc"`,
+		storageClass: "STANDARD",
+		tags:         map[string]string{"env": "prod"},
+		metadata:     map[string]string{"X-Amz-Meta-Owner": "alice"},
+	}
+	testCases := []struct {
+		expr    string
+		match   bool
+		success bool
+	}{
+		{`size > 1GiB && tags.env == "prod" && name ~ "\\.parquet$"`, true, true},
+		{`size > 1GiB and tags.env == 'prod' and name ~ '\.parquet$'`, true, true},
+		{`size < 1GiB || age > 90d`, true, true},
+		{`!(storageclass == "STANDARD") || metadata.owner != "alice"`, false, true},
+		{`not sc == GLACIER && metadata.X-Amz-Meta-Owner == alice && etag == abc`, true, true},
+		{`path ~ "^datasets/" && (tags.team == "ml" || age < 1d)`, false, true},
+		{`tags.missing == ""`, true, true},
+		{`size > big`, false, false},
+		{`owner == "alice"`, false, false},
+		{`name > "a"`, false, false},
+		{`name ~ "("`, false, false},
+		{`(size > 1`, false, false},
+		{`size > 1 size`, false, false},
+		{`tags == "x"`, false, false},
+	}
+	for i, testCase := range testCases {
+		where, err := parseFindWhere(testCase.expr)
+		if err != nil && testCase.success {
+			t.Fatalf("Test %d: Expected success, got %s", i+1, err)
+		}
+		if err == nil && !testCase.success {
+			t.Fatalf("Test %d: Expected error, got success", i+1)
+		}
+		if !testCase.success {
+			continue
+		}
+		if got := where.match(record); got != testCase.match {
+			t.Errorf("Test %d: Expected match %v, got %v", i+1, testCase.match, got)
+		}
+	}
+}
+
 // Tests exit status, getExitStatus() function
 func TestGetExitStatus(t *testing.T) {
 	if runtime.GOOS != "linux" {
@@ -38,6 +38,10 @@ var globalFlags = []cli.Flag{
 		Name:  "no-color",
 		Usage: "disable color theme",
 	},
+	cli.BoolFlag{
+		Name:  "no-tui",
+		Usage: "print live views as plain text status updates, for screen readers and CI logs",
+	},
 	cli.BoolFlag{
 		Name:  "json",
 		Usage: "enable JSON lines formatted output",
@@ -58,6 +62,10 @@ var globalFlags = []cli.Flag{
 		Name:  "limit-download",
 		Usage: "limits downloads to a maximum rate in KiB/s, MiB/s, GiB/s. (default: unlimited)",
 	},
+	cli.StringFlag{
+		Name:  "lang",
+		Usage: "language of the output, e.g. de or es, defaults to MC_LANG or the system locale",
+	},
 	cli.DurationFlag{
 		Name:   "conn-read-deadline",
 		Usage:  "custom connection READ deadline",
@@ -78,4 +86,64 @@ var ioFlags = []cli.Flag{
 		Name:  "encrypt-key",
 		Usage: "encrypt/decrypt objects (using server-side encryption with customer provided keys)",
 	},
+	cli.StringFlag{
+		Name:  "encrypt-key-file",
+		Usage: "read SSE-C keys from a file, one prefix=key or prefix=oldkey=newkey to rotate keys per line",
+	},
+}
+
+// Flags common across commands which retry failed requests such as cp, mirror and rm.
+var retryFlags = []cli.Flag{
+	cli.IntFlag{
+		Name:  "retry-attempts",
+		Usage: "maximum number of attempts for a request failing with a transient error",
+		Value: 1,
+	},
+	cli.DurationFlag{
+		Name:  "retry-backoff",
+		Usage: "wait before the first retry, doubled on every subsequent retry",
+		Value: time.Second,
+	},
+	cli.DurationFlag{
+		Name:  "retry-max-wait",
+		Usage: "maximum wait between two retries",
+		Value: 30 * time.Second,
+	},
+}
+
+// Flags tuning multipart uploads of large objects.
+var multipartFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "part-size",
+		Usage: "size of each part of a multipart upload or server-side copy, between 5MiB and 5GiB (e.g. 128MiB)",
+	},
+	cli.IntFlag{
+		Name:  "parallel-parts",
+		Usage: "number of parts of a multipart upload or server-side copy sent in parallel",
+	},
+}
+
+// Flags tuning parallel ranged downloads of large objects to the local filesystem.
+var rangedDownloadFlags = []cli.Flag{
+	cli.IntFlag{
+		Name:  "parallel-ranges",
+		Usage: "number of byte ranges of a large object downloaded in parallel",
+	},
+	cli.StringFlag{
+		Name:  "range-size",
+		Usage: "size of each byte range of a parallel download (e.g. 64MiB)",
+		Value: "64MiB",
+	},
+}
+
+// Flags reporting and watching the progress of long transfers such as cp and mirror.
+var progressFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "progress-events",
+		Usage: "write progress records as JSON lines to a file or named pipe, '-' for stdout",
+	},
+	cli.DurationFlag{
+		Name:  "stall-timeout",
+		Usage: "abort and retry the client-side transfer of an object sending no data for this long (min 1s), 0 disables",
+	},
 }
@@ -0,0 +1,189 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+)
+
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	zstdMagic  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	bzip2Magic = []byte{0x42, 0x5a, 0x68} // "BZh" followed by the block size, '1' to '9'.
+)
+
+// Extensions of the compressed streams understood by decompressReader.
+var compressedExts = []string{".gz", ".zst", ".bz2"}
+
+// errUnsafeTarPath is returned for archive entries which
+// would be written outside of the extraction folder.
+var errUnsafeTarPath = errors.New("archive entry points outside of the target folder")
+
+// decompressReader returns a reader decompressing the gzip, zstd or
+// bzip2 stream read from r. The compression is detected from the
+// leading magic bytes, or else from the extension of name so that a
+// corrupted stream is reported. Uncompressed streams are returned
+// unchanged.
+func decompressReader(r io.Reader, name string) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	magic, e := br.Peek(len(zstdMagic))
+	if e != nil && e != io.EOF {
+		return nil, e
+	}
+	ext := strings.ToLower(filepath.Ext(name))
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic), ext == ".gz":
+		return gzip.NewReader(br)
+	case bytes.HasPrefix(magic, zstdMagic), ext == ".zst":
+		dec, e := zstd.NewReader(br)
+		if e != nil {
+			return nil, e
+		}
+		return dec.IOReadCloser(), nil
+	case bytes.HasPrefix(magic, bzip2Magic) && len(magic) > 3 && magic[3] >= '1' && magic[3] <= '9', ext == ".bz2":
+		return io.NopCloser(bzip2.NewReader(br)), nil
+	}
+	return io.NopCloser(br), nil
+}
+
+// tarEntryPath returns the local path of an archive entry,
+// making sure it stays below targetDir.
+func tarEntryPath(targetDir, name string) (string, error) {
+	name = filepath.FromSlash(name)
+	if filepath.IsAbs(name) || filepath.VolumeName(name) != "" {
+		return "", fmt.Errorf("%w: %s", errUnsafeTarPath, name)
+	}
+	entryPath := filepath.Join(targetDir, name)
+	rel, e := filepath.Rel(targetDir, entryPath)
+	if e != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %s", errUnsafeTarPath, name)
+	}
+	return entryPath, nil
+}
+
+// checkTarEntryParents refuses entries with a symbolic link among their
+// existing parent folders below targetDir, as writing through the link
+// could reach outside of the target folder.
+func checkTarEntryParents(targetDir, entryPath string) error {
+	rel, e := filepath.Rel(targetDir, filepath.Dir(entryPath))
+	if e != nil {
+		return e
+	}
+	if rel == "." {
+		return nil
+	}
+	dir := targetDir
+	for _, elem := range strings.Split(rel, string(filepath.Separator)) {
+		dir = filepath.Join(dir, elem)
+		fi, e := os.Lstat(dir)
+		if os.IsNotExist(e) {
+			return nil
+		}
+		if e != nil {
+			return e
+		}
+		if fi.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("%w: %s", errUnsafeTarPath, entryPath)
+		}
+	}
+	return nil
+}
+
+// extractTar extracts the tar stream read from r below targetDir
+// in one pass, returning the number of extracted regular files.
+func extractTar(r io.Reader, targetDir string) (int64, error) {
+	var files int64
+	tr := tar.NewReader(r)
+	for {
+		hdr, e := tr.Next()
+		if e == io.EOF {
+			return files, nil
+		}
+		if e != nil {
+			return files, e
+		}
+
+		entryPath, e := tarEntryPath(targetDir, hdr.Name)
+		if e != nil {
+			return files, e
+		}
+		if e = checkTarEntryParents(targetDir, entryPath); e != nil {
+			return files, e
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if e = os.MkdirAll(entryPath, 0o777); e != nil {
+				return files, e
+			}
+		case tar.TypeReg:
+			if e = os.MkdirAll(filepath.Dir(entryPath), 0o777); e != nil {
+				return files, e
+			}
+			// Replace a link of the same name rather than following it.
+			if fi, e := os.Lstat(entryPath); e == nil && fi.Mode()&os.ModeSymlink != 0 {
+				os.Remove(entryPath)
+			}
+			f, e := os.OpenFile(entryPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, hdr.FileInfo().Mode().Perm())
+			if e != nil {
+				return files, e
+			}
+			if _, e = io.Copy(f, tr); e != nil {
+				f.Close()
+				return files, e
+			}
+			if e = f.Close(); e != nil {
+				return files, e
+			}
+			os.Chtimes(entryPath, hdr.ModTime, hdr.ModTime)
+			files++
+		case tar.TypeSymlink:
+			// Links are resolved relative to their own folder, they
+			// may not point to a parent folder as links can be chained.
+			linkname := filepath.Clean(filepath.FromSlash(hdr.Linkname))
+			if filepath.IsAbs(linkname) || linkname == ".." || strings.HasPrefix(linkname, ".."+string(filepath.Separator)) {
+				return files, fmt.Errorf("%w: %s", errUnsafeTarPath, hdr.Linkname)
+			}
+			if _, e = tarEntryPath(targetDir, filepath.Join(filepath.Dir(hdr.Name), hdr.Linkname)); e != nil {
+				return files, e
+			}
+			if e = os.MkdirAll(filepath.Dir(entryPath), 0o777); e != nil {
+				return files, e
+			}
+			os.Remove(entryPath)
+			if e = os.Symlink(hdr.Linkname, entryPath); e != nil {
+				return files, e
+			}
+		default:
+			// Hard links, devices and fifos are not restored.
+			continue
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTarEntryPath(t *testing.T) {
+	targetDir := filepath.FromSlash("/tmp/restore")
+	testCases := []struct {
+		name     string
+		expected string
+		unsafe   bool
+	}{
+		{"file.txt", filepath.FromSlash("/tmp/restore/file.txt"), false},
+		{"dir/sub/file.txt", filepath.FromSlash("/tmp/restore/dir/sub/file.txt"), false},
+		{"./dir/../file.txt", filepath.FromSlash("/tmp/restore/file.txt"), false},
+		{"../file.txt", "", true},
+		{"dir/../../file.txt", "", true},
+		{"/etc/passwd", "", true},
+	}
+
+	for i, testCase := range testCases {
+		got, e := tarEntryPath(targetDir, testCase.name)
+		if testCase.unsafe {
+			if !errors.Is(e, errUnsafeTarPath) {
+				t.Fatalf("Test %d: expected unsafe path error, got %v", i+1, e)
+			}
+			continue
+		}
+		if e != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, e)
+		}
+		if got != testCase.expected {
+			t.Fatalf("Test %d: expected %s, got %s", i+1, testCase.expected, got)
+		}
+	}
+}
+
+func TestExtractTarLinks(t *testing.T) {
+	type entry struct {
+		name, linkname string
+		typeflag       byte
+	}
+	testCases := []struct {
+		entries []entry
+		unsafe  bool
+	}{
+		// Chained links, each one looking safe on its own.
+		{[]entry{
+			{"a/", "", tar.TypeDir},
+			{"a/b", "..", tar.TypeSymlink},
+			{"a/b/c", "..", tar.TypeSymlink},
+			{"a/b/c/x", "", tar.TypeReg},
+		}, true},
+		{[]entry{{"a/b", "c/../..", tar.TypeSymlink}}, true},
+		// Writing through a link staying in the target folder.
+		{[]entry{
+			{"sub/", "", tar.TypeDir},
+			{"a", "sub", tar.TypeSymlink},
+			{"a/x", "", tar.TypeReg},
+		}, true},
+		// A file replacing a link is written in place of the link.
+		{[]entry{
+			{"a", "b", tar.TypeSymlink},
+			{"a", "", tar.TypeReg},
+		}, false},
+		{[]entry{
+			{"sub/x", "", tar.TypeReg},
+			{"link", "sub/x", tar.TypeSymlink},
+		}, false},
+	}
+
+	for i, testCase := range testCases {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		for _, entry := range testCase.entries {
+			hdr := &tar.Header{Name: entry.name, Linkname: entry.linkname, Typeflag: entry.typeflag, Mode: 0o644}
+			if entry.typeflag == tar.TypeReg {
+				hdr.Size = 4
+			}
+			if e := tw.WriteHeader(hdr); e != nil {
+				t.Fatal(e)
+			}
+			if entry.typeflag == tar.TypeReg {
+				tw.Write([]byte("data"))
+			}
+		}
+		tw.Close()
+
+		parentDir := t.TempDir()
+		targetDir := filepath.Join(parentDir, "restore")
+		if e := os.Mkdir(targetDir, 0o777); e != nil {
+			t.Fatal(e)
+		}
+		_, e := extractTar(&buf, targetDir)
+		if testCase.unsafe {
+			if !errors.Is(e, errUnsafeTarPath) {
+				t.Fatalf("Test %d: expected unsafe path error, got %v", i+1, e)
+			}
+		} else if e != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, e)
+		}
+		if _, e = os.Lstat(filepath.Join(parentDir, "x")); !os.IsNotExist(e) {
+			t.Fatalf("Test %d: a file was written outside of the target folder", i+1)
+		}
+	}
+}
@@ -0,0 +1,524 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/hookreader"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+var getFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "version-id, vid",
+		Usage: "download a specific version of the object",
+	},
+	cli.BoolFlag{
+		Name:  "no-resume",
+		Usage: "discard any partial download and start from the beginning",
+	},
+	cli.BoolFlag{
+		Name:  "no-verify",
+		Usage: "skip checksum verification of the downloaded object",
+	},
+	cli.BoolFlag{
+		Name:  "decompress",
+		Usage: "decompress a gzip, zstd or bzip2 compressed object while downloading",
+	},
+	cli.BoolFlag{
+		Name:  "extract, x",
+		Usage: "decompress and extract a tar archive object into the TARGET folder",
+	},
+	cseDecryptFlag,
+}
+
+// Download a single object.
+var getCmd = cli.Command{
+	Name:         "get",
+	Usage:        "download an object to the local filesystem",
+	Action:       mainGet,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(append(append(getFlags, rangedDownloadFlags...), ioFlags...), globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] SOURCE [TARGET]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+ENVIRONMENT VARIABLES:
+  MC_ENCRYPT_KEY:   list of comma delimited prefix=secret values
+  MC_DECRYPT_WITH:  key to decrypt client-side encrypted objects, same as --decrypt-with
+
+NOTE:
+  An interrupted download is kept next to TARGET and resumed by the next
+  '{{.HelpName}}' of the same object. The content is verified against the
+  object ETag whenever the ETag is a plain MD5 sum.
+
+  With '--parallel-ranges', objects larger than '--range-size' are downloaded
+  as several byte ranges fetched in parallel and written in place.
+
+  With '--decompress', '--extract' or '-' as TARGET, the object is processed
+  in a single streaming pass which can neither be resumed nor verified. So
+  are objects encrypted on the client by 'mc cp --encrypt-with'.
+
+EXAMPLES:
+  1. Download an object to the current folder.
+     {{.Prompt}} {{.HelpName}} play/mybucket/backup.tgz
+
+  2. Download an object to a specific local path.
+     {{.Prompt}} {{.HelpName}} play/mybucket/backup.tgz /mnt/restore/backup-2023.tgz
+
+  3. Download a specific object version, starting over even if a partial download exists.
+     {{.Prompt}} {{.HelpName}} --no-resume --version-id "3ddac055-89a7-40fa-8cd3-530a5581b6b8" play/mybucket/backup.tgz /tmp/
+
+  4. Restore a compressed tar archive into a local folder.
+     {{.Prompt}} {{.HelpName}} --extract play/mybucket/backup.tar.zst /mnt/restore/
+
+  5. Decompress a gzip compressed log to the standard output.
+     {{.Prompt}} {{.HelpName}} --decompress play/mybucket/app.log.gz - | grep ERROR
+
+  6. Download an object encrypted on the client, decrypting it with a key file.
+     {{.Prompt}} {{.HelpName}} --decrypt-with file:/etc/mc/backup.key play/mybucket/backup.tgz
+
+  7. Download a large object over a high latency link as 8 parallel ranges of 128MiB.
+     {{.Prompt}} {{.HelpName}} --parallel-ranges 8 --range-size 128MiB s3/mybucket/dataset.tar /data/
+`,
+}
+
+// getMessage container for get messages
+type getMessage struct {
+	Status      string `json:"status"`
+	Source      string `json:"source"`
+	Target      string `json:"target"`
+	Size        int64  `json:"size"`
+	ResumedFrom int64  `json:"resumedFrom,omitempty"`
+	Extracted   int64  `json:"extracted,omitempty"`
+	Checksum    string `json:"checksum"`
+}
+
+// String colorized get message
+func (g getMessage) String() string {
+	msg := console.Colorize("Get", fmt.Sprintf("`%s` -> `%s`", g.Source, g.Target))
+	if g.ResumedFrom > 0 {
+		msg += fmt.Sprintf(" (resumed at %d bytes)", g.ResumedFrom)
+	}
+	if g.Extracted > 0 {
+		msg += fmt.Sprintf(" (%d files extracted)", g.Extracted)
+	}
+	return msg + fmt.Sprintf(" (checksum %s)", g.Checksum)
+}
+
+// JSON jsonified get message
+func (g getMessage) JSON() string {
+	g.Status = "success"
+	getMessageBytes, e := json.MarshalIndent(g, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(getMessageBytes)
+}
+
+// Checksum verification states reported by get and put.
+const (
+	checksumVerified = "verified"
+	checksumSkipped  = "skipped"
+)
+
+// etagIsMD5 returns true if the ETag of the object is the
+// MD5 sum of its content, i.e. it was not uploaded with
+// multipart and is not encrypted.
+func etagIsMD5(content *ClientContent) bool {
+	etag := strings.Trim(content.ETag, "\"")
+	if len(etag) != 32 || strings.Contains(etag, "-") {
+		return false
+	}
+	for k := range content.Metadata {
+		if strings.HasPrefix(http.CanonicalHeaderKey(k), http.CanonicalHeaderKey(serverEncryptionKeyPrefix)) {
+			return false
+		}
+	}
+	return true
+}
+
+// getPartPath returns the path of the partial download of an object,
+// unique per object version so that a modified object is never resumed
+// on top of stale content.
+func getPartPath(targetPath string, content *ClientContent) string {
+	sum := sha256.Sum256([]byte(content.ETag + content.VersionID))
+	return targetPath + "." + hex.EncodeToString(sum[:4]) + partSuffix
+}
+
+// getTargetPath returns the local path where the source object is saved.
+func getTargetPath(sourceURL, targetPath string, decompress bool) string {
+	name := path.Base(filepath.ToSlash(sourceURL))
+	if decompress {
+		for _, ext := range compressedExts {
+			if trimmed := strings.TrimSuffix(name, ext); trimmed != "" {
+				name = trimmed
+			}
+		}
+	}
+	if targetPath == "" {
+		return name
+	}
+	if strings.HasSuffix(targetPath, string(filepath.Separator)) {
+		return filepath.Join(targetPath, name)
+	}
+	if st, e := os.Stat(targetPath); e == nil && st.IsDir() {
+		return filepath.Join(targetPath, name)
+	}
+	return targetPath
+}
+
+// checkGetSyntax - validate all the passed arguments
+func checkGetSyntax(cliCtx *cli.Context) {
+	args := cliCtx.Args()
+	if len(args) < 1 || len(args) > 2 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	_, _, hostCfg, err := expandAlias(args.Get(0))
+	fatalIf(err.Trace(args...), "Unable to parse source `"+args.Get(0)+"`.")
+	if hostCfg == nil {
+		fatalIf(errInvalidArgument().Trace(args...), "Source `"+args.Get(0)+"` should be an object on an alias.")
+	}
+	if cliCtx.Bool("extract") && args.Get(1) == "-" {
+		fatalIf(errInvalidArgument().Trace(args...), "--extract requires a folder as TARGET.")
+	}
+	if _, _, err := parseRangedDownloadFlags(cliCtx); err != nil {
+		fatalIf(err, "Unable to parse ranged download flags.")
+	}
+}
+
+// getObject downloads a single object to targetPath, resuming a previous
+// partial download if any and verifying the checksum of the content. Large
+// objects are fetched as up to ranges byte ranges in parallel.
+func getObject(ctx context.Context, sourceURL, targetPath, versionID string, encKeyDB map[string][]prefixSSEPair, decryptKey *cseKey, resume, verify bool, ranges uint, rangeSize int64) (getMessage, *probe.Error) {
+	msg := getMessage{Source: sourceURL, Target: targetPath, Checksum: checksumSkipped}
+
+	_, content, err := url2Stat(ctx, sourceURL, versionID, false, encKeyDB, time.Time{}, false)
+	if err != nil {
+		return msg, err.Trace(sourceURL)
+	}
+	if content.Type.IsDir() {
+		return msg, errInvalidArgument().Trace(sourceURL)
+	}
+	// Client-side encrypted content is decrypted in a single pass.
+	if envelope, err := getCSEEnvelope(content.Metadata); err != nil || envelope != nil {
+		if err != nil {
+			return msg, err.Trace(sourceURL)
+		}
+		return getObjectStream(ctx, sourceURL, targetPath, content.VersionID, encKeyDB, decryptKey, false, false)
+	}
+	msg.Size = content.Size
+
+	if dir := filepath.Dir(targetPath); dir != "" {
+		if e := os.MkdirAll(dir, 0o777); e != nil {
+			return msg, probe.NewError(e).Trace(targetPath)
+		}
+	}
+
+	partPath := getPartPath(targetPath, content)
+	if !resume {
+		removePartFile(partPath)
+	}
+	partFile, e := os.OpenFile(partPath, os.O_CREATE|os.O_RDWR, 0o666)
+	if e != nil {
+		return msg, probe.NewError(e).Trace(partPath)
+	}
+	defer partFile.Close()
+
+	offset, e := trimPartFile(partFile)
+	if e != nil {
+		return msg, probe.NewError(e).Trace(partPath)
+	}
+	if offset > content.Size {
+		if e = partFile.Truncate(0); e != nil {
+			return msg, probe.NewError(e).Trace(partPath)
+		}
+		offset = 0
+	}
+	msg.ResumedFrom = offset
+
+	var hasher hash.Hash
+	verify = verify && etagIsMD5(content)
+	if verify {
+		hasher = md5.New()
+		// Account for the content downloaded by a previous attempt.
+		if _, e = io.Copy(hasher, io.NewSectionReader(partFile, 0, offset)); e != nil {
+			return msg, probe.NewError(e).Trace(partPath)
+		}
+	}
+	if _, e = partFile.Seek(offset, io.SeekStart); e != nil {
+		return msg, probe.NewError(e).Trace(partPath)
+	}
+
+	var pg ProgressReader
+	if !globalQuiet && !globalJSON {
+		bar := newProgressBar(content.Size)
+		bar.SetCaption(sourceURL + ":")
+		bar.Set64(offset)
+		pg = bar
+	} else {
+		pg = newAccounter(content.Size).Set(offset)
+	}
+
+	if offset < content.Size && useRangedDownload(ranges, rangeSize, content.Size-offset) {
+		fetch := func(ctx context.Context, start, end int64) (io.ReadCloser, *probe.Error) {
+			return getSourceStreamFromURL(ctx, sourceURL, encKeyDB, getSourceOpts{
+				GetOptions: GetOptions{
+					VersionID:   content.VersionID,
+					RangeStart:  start,
+					RangeEnd:    end,
+					HasRangeEnd: true,
+					MatchETag:   content.ETag,
+				},
+			})
+		}
+		if err = downloadRanges(ctx, fetch, partFile, offset, content.Size, ranges, rangeSize, pg); err != nil {
+			return msg, err.Trace(sourceURL)
+		}
+		if hasher != nil {
+			if _, e = io.Copy(hasher, io.NewSectionReader(partFile, offset, content.Size-offset)); e != nil {
+				return msg, probe.NewError(e).Trace(partPath)
+			}
+		}
+	} else if offset < content.Size {
+		reader, err := getSourceStreamFromURL(ctx, sourceURL, encKeyDB, getSourceOpts{
+			GetOptions: GetOptions{
+				VersionID:  content.VersionID,
+				RangeStart: offset,
+				MatchETag:  content.ETag,
+			},
+		})
+		if err != nil {
+			return msg, err.Trace(sourceURL)
+		}
+		defer reader.Close()
+
+		var writer io.Writer = partFile
+		if hasher != nil {
+			writer = io.MultiWriter(partFile, hasher)
+		}
+		n, e := io.Copy(writer, hookreader.NewHook(reader, pg))
+		if e != nil {
+			return msg, probe.NewError(e).Trace(sourceURL)
+		}
+		if offset+n != content.Size {
+			return msg, probe.NewError(UnexpectedEOF{
+				TotalSize:    content.Size,
+				TotalWritten: offset + n,
+			}).Trace(sourceURL)
+		}
+	}
+	if bar, ok := pg.(*progressBar); ok {
+		bar.ProgressBar.Finish()
+	}
+
+	if verify {
+		if sum := hex.EncodeToString(hasher.Sum(nil)); sum != strings.Trim(content.ETag, "\"") {
+			partFile.Close()
+			removePartFile(partPath)
+			return msg, probe.NewError(errors.New("checksum mismatch, expected " + content.ETag + " got " + sum)).Trace(sourceURL)
+		}
+		msg.Checksum = checksumVerified
+	}
+
+	if e = partFile.Close(); e != nil {
+		return msg, probe.NewError(e).Trace(partPath)
+	}
+	if e = os.Rename(partPath, targetPath); e != nil {
+		return msg, probe.NewError(e).Trace(targetPath)
+	}
+	if !content.Time.IsZero() {
+		os.Chtimes(targetPath, content.Time, content.Time)
+	}
+	return msg, nil
+}
+
+// getObjectStream downloads a single object in one streaming pass, optionally
+// decompressing it and extracting it as a tar archive below targetPath.
+func getObjectStream(ctx context.Context, sourceURL, targetPath, versionID string, encKeyDB map[string][]prefixSSEPair, decryptKey *cseKey, decompress, extract bool) (getMessage, *probe.Error) {
+	msg := getMessage{Source: sourceURL, Target: targetPath, Checksum: checksumSkipped}
+
+	_, content, err := url2Stat(ctx, sourceURL, versionID, false, encKeyDB, time.Time{}, false)
+	if err != nil {
+		return msg, err.Trace(sourceURL)
+	}
+	if content.Type.IsDir() {
+		return msg, errInvalidArgument().Trace(sourceURL)
+	}
+	msg.Size = content.Size
+
+	envelope, err := getCSEEnvelope(content.Metadata)
+	if err != nil {
+		return msg, err.Trace(sourceURL)
+	}
+	if envelope != nil {
+		msg.Size = envelope.size
+	}
+
+	reader, err := getSourceStreamFromURL(ctx, sourceURL, encKeyDB, getSourceOpts{
+		GetOptions: GetOptions{VersionID: content.VersionID},
+	})
+	if err != nil {
+		return msg, err.Trace(sourceURL)
+	}
+	defer reader.Close()
+
+	// Never draw a progress bar over content written to the standard output.
+	var pg ProgressReader
+	if !globalQuiet && !globalJSON && targetPath != "-" {
+		bar := newProgressBar(content.Size)
+		bar.SetCaption(sourceURL + ":")
+		pg = bar
+	} else {
+		pg = newAccounter(content.Size)
+	}
+
+	var src io.Reader = hookreader.NewHook(reader, pg)
+	if envelope != nil {
+		if src, err = envelope.decryptReader(src, decryptKey); err != nil {
+			return msg, err.Trace(sourceURL)
+		}
+	}
+	if decompress || extract {
+		dec, e := decompressReader(src, sourceURL)
+		if e != nil {
+			return msg, probe.NewError(e).Trace(sourceURL)
+		}
+		defer dec.Close()
+		src = dec
+	}
+
+	switch {
+	case extract:
+		if e := os.MkdirAll(targetPath, 0o777); e != nil {
+			return msg, probe.NewError(e).Trace(targetPath)
+		}
+		n, e := extractTar(src, targetPath)
+		msg.Extracted = n
+		if e != nil {
+			return msg, probe.NewError(e).Trace(sourceURL, targetPath)
+		}
+	case targetPath == "-":
+		if _, e := io.Copy(os.Stdout, src); e != nil {
+			return msg, probe.NewError(e).Trace(sourceURL)
+		}
+	default:
+		if dir := filepath.Dir(targetPath); dir != "" {
+			if e := os.MkdirAll(dir, 0o777); e != nil {
+				return msg, probe.NewError(e).Trace(targetPath)
+			}
+		}
+		partPath := targetPath + partSuffix
+		f, e := os.Create(partPath)
+		if e != nil {
+			return msg, probe.NewError(e).Trace(partPath)
+		}
+		if _, e = io.Copy(f, src); e != nil {
+			f.Close()
+			os.Remove(partPath)
+			return msg, probe.NewError(e).Trace(sourceURL)
+		}
+		if e = f.Close(); e != nil {
+			return msg, probe.NewError(e).Trace(partPath)
+		}
+		if e = os.Rename(partPath, targetPath); e != nil {
+			return msg, probe.NewError(e).Trace(targetPath)
+		}
+	}
+	if bar, ok := pg.(*progressBar); ok {
+		bar.ProgressBar.Finish()
+	}
+	return msg, nil
+}
+
+// mainGet is the entry point for get command.
+func mainGet(cliCtx *cli.Context) error {
+	ctx, cancelGet := context.WithCancel(globalContext)
+	defer cancelGet()
+
+	checkGetSyntax(cliCtx)
+
+	// Additional command specific theme customization.
+	console.SetColor("Get", color.New(color.FgGreen, color.Bold))
+
+	encKeyDB, err := getEncKeys(cliCtx)
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	var decryptKey *cseKey
+	if spec := cliCtx.String("decrypt-with"); spec != "" {
+		decryptKey, err = parseCSEKey(spec, true)
+		fatalIf(err, "Unable to parse client-side encryption key.")
+	}
+
+	sourceURL := cliCtx.Args().Get(0)
+	decompress := cliCtx.Bool("decompress")
+	extract := cliCtx.Bool("extract")
+
+	switch targetPath := cliCtx.Args().Get(1); {
+	case extract:
+		if targetPath == "" {
+			targetPath = "."
+		}
+		msg, err := getObjectStream(ctx, sourceURL, targetPath, cliCtx.String("version-id"), encKeyDB, decryptKey, true, true)
+		fatalIf(err, "Unable to extract `"+sourceURL+"`.")
+		printMsg(msg)
+		return nil
+	case targetPath == "-":
+		_, err = getObjectStream(ctx, sourceURL, targetPath, cliCtx.String("version-id"), encKeyDB, decryptKey, decompress, false)
+		fatalIf(err, "Unable to download `"+sourceURL+"`.")
+		return nil
+	case decompress:
+		targetPath = getTargetPath(sourceURL, targetPath, true)
+		msg, err := getObjectStream(ctx, sourceURL, targetPath, cliCtx.String("version-id"), encKeyDB, decryptKey, true, false)
+		fatalIf(err, "Unable to download `"+sourceURL+"`.")
+		printMsg(msg)
+		return nil
+	}
+
+	targetPath := getTargetPath(sourceURL, cliCtx.Args().Get(1), false)
+	ranges, rangeSize, _ := parseRangedDownloadFlags(cliCtx)
+	msg, err := getObject(ctx, sourceURL, targetPath, cliCtx.String("version-id"), encKeyDB, decryptKey,
+		!cliCtx.Bool("no-resume"), !cliCtx.Bool("no-verify"), ranges, rangeSize)
+	fatalIf(err, "Unable to download `"+sourceURL+"`. Run the same command again to resume.")
+
+	printMsg(msg)
+	return nil
+}
@@ -21,6 +21,7 @@ package cmd
 import (
 	"context"
 	"crypto/x509"
+	"errors"
 	"net/url"
 	"time"
 
@@ -40,6 +41,7 @@ const (
 	// session config and shared urls related constants
 	globalSessionDir           = "session"
 	globalSharedURLsDataDir    = "share"
+	globalIndexDir             = "index"
 	globalSessionConfigVersion = "8"
 
 	// Profile directory for dumping profiler outputs.
@@ -64,6 +66,7 @@ var (
 	globalJSONLine       = false               // Print json as single line.
 	globalDebug          = false               // Debug flag set via command line
 	globalNoColor        = false               // No Color flag set via command line
+	globalNoTUI          = false               // No TUI flag set via command line
 	globalInsecure       = false               // Insecure flag set via command line
 	globalDevMode        = false               // dev flag set via command line
 	globalAirgapped      = false               // Airgapped flag set via command line
@@ -76,6 +79,10 @@ var (
 	globalLimitUpload   uint64
 	globalLimitDownload uint64
 
+	globalRetryPolicy = retryPolicy{attempts: 1}
+
+	globalStallTimeout time.Duration
+
 	globalContext, globalCancel = context.WithCancel(context.Background())
 )
 
@@ -95,6 +102,7 @@ func setGlobalsFromContext(ctx *cli.Context) error {
 	debug := ctx.IsSet("debug") || ctx.GlobalIsSet("debug")
 	json := ctx.IsSet("json") || ctx.GlobalIsSet("json")
 	noColor := ctx.IsSet("no-color") || ctx.GlobalIsSet("no-color")
+	noTUI := ctx.IsSet("no-tui") || ctx.GlobalIsSet("no-tui")
 	insecure := ctx.IsSet("insecure") || ctx.GlobalIsSet("insecure")
 	devMode := ctx.IsSet("dev") || ctx.GlobalIsSet("dev")
 	airgapped := ctx.IsSet("airgap") || ctx.GlobalIsSet("airgap")
@@ -104,6 +112,7 @@ func setGlobalsFromContext(ctx *cli.Context) error {
 	globalJSONLine = !isTerminal() && json
 	globalJSON = globalJSON || json
 	globalNoColor = globalNoColor || noColor || globalJSONLine
+	globalNoTUI = globalNoTUI || noTUI
 	globalInsecure = globalInsecure || insecure
 	globalDevMode = globalDevMode || devMode
 	globalAirgapped = globalAirgapped || airgapped
@@ -148,5 +157,28 @@ func setGlobalsFromContext(ctx *cli.Context) error {
 		}
 	}
 
+	if ctx.IsSet("retry-attempts") {
+		globalRetryPolicy.attempts = ctx.Int("retry-attempts")
+		if globalRetryPolicy.attempts < 1 {
+			return errors.New("--retry-attempts should be at least 1")
+		}
+	}
+	globalRetryPolicy.backoff = ctx.Duration("retry-backoff")
+	globalRetryPolicy.maxWait = ctx.Duration("retry-max-wait")
+
+	lang := ctx.String("lang")
+	if lang == "" {
+		lang = ctx.GlobalString("lang")
+	}
+	setLocale(lang)
+
+	globalStallTimeout = ctx.Duration("stall-timeout")
+	if globalStallTimeout < 0 {
+		return errors.New("--stall-timeout should not be negative")
+	}
+	if e := checkStallTimeout(globalStallTimeout); e != nil {
+		return e
+	}
+
 	return nil
 }
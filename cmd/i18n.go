@@ -0,0 +1,85 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/trinet2005/oss-mc/pkg/i18n"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+var loadLocalesOnce sync.Once
+
+// getLocalesDir returns the folder of the user message catalogs,
+// ~/.mc/locales by default.
+func getLocalesDir() (string, *probe.Error) {
+	configDir, err := getMcConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "locales"), nil
+}
+
+// loadUserLocales registers the catalogs of the locales folder, one JSON
+// object per locale named after it, e.g. pt-BR.json. They extend and
+// override the builtin catalogs.
+func loadUserLocales() *probe.Error {
+	dir, err := getLocalesDir()
+	if err != nil {
+		return err
+	}
+	entries, e := os.ReadDir(dir)
+	if e != nil {
+		if os.IsNotExist(e) {
+			return nil
+		}
+		return probe.NewError(e)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		f, e := os.Open(filepath.Join(dir, entry.Name()))
+		if e != nil {
+			return probe.NewError(e)
+		}
+		e = i18n.Load(strings.TrimSuffix(entry.Name(), ".json"), f)
+		f.Close()
+		if e != nil {
+			return probe.NewError(e).Trace(entry.Name())
+		}
+	}
+	return nil
+}
+
+// setLocale selects the language of the output, from --lang, or else
+// from the environment. JSON output is never translated so that scripts
+// do not depend on the locale.
+func setLocale(lang string) {
+	loadLocalesOnce.Do(func() {
+		errorIf(loadUserLocales(), "Unable to load the message catalogs.")
+	})
+	if lang == "" {
+		lang = i18n.FromEnv(os.Getenv)
+	}
+	i18n.SetLocale(lang)
+}
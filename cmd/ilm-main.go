@@ -27,6 +27,7 @@ var ilmSubcommands = []cli.Command{
 	ilmRuleCmd,
 	ilmTierCmd,
 	ilmRestoreCmd,
+	ilmSimulateCmd,
 }
 
 var ilmCmd = cli.Command{
@@ -47,6 +48,8 @@ const (
 	ilmThemeExpiry        string = "Row-Expiry"
 	ilmThemeResultSuccess string = "SuccessOp"
 	ilmThemeResultFailure string = "FailureOp"
+	ilmThemeDiffAdded     string = "Diff-Added"
+	ilmThemeDiffRemoved   string = "Diff-Removed"
 )
 
 func mainILM(ctx *cli.Context) error {
@@ -63,4 +66,6 @@ func setILMDisplayColorScheme() {
 	console.SetColor(ilmThemeExpiry, color.New(color.BlinkRapid, color.FgGreen))
 	console.SetColor(ilmThemeResultSuccess, color.New(color.FgGreen, color.Bold))
 	console.SetColor(ilmThemeResultFailure, color.New(color.FgHiYellow, color.Bold))
+	console.SetColor(ilmThemeDiffAdded, color.New(color.FgGreen))
+	console.SetColor(ilmThemeDiffRemoved, color.New(color.FgRed))
 }
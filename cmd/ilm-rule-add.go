@@ -48,6 +48,16 @@ FLAGS:
 DESCRIPTION:
   Add a lifecycle configuration rule.
 
+  With --template, the rule starts from a template and the other flags complete or override it.
+  Built-in templates are:
+    log-expiry-30d       expire objects 30 days after their creation
+    tier-after-90d       transition objects to --transition-tier 90 days after their creation
+    cleanup-incomplete   abort multipart uploads not completed within 7 days, not supported by MinIO
+
+  User templates are rules in the JSON format of 'mc ilm rule export', or in YAML, saved as
+  NAME.json, NAME.yaml or NAME.yml in ~/.mc/templates/ilm/. They take precedence over the
+  built-in templates of the same name.
+
 EXAMPLES:
   1. Add a lifecycle rule with a transition and a noncurrent version transition action for objects with prefix doc/ in mybucket.
      Tiers must exist in MinIO. Use existing tiers or add new tiers.
@@ -65,10 +75,33 @@ EXAMPLES:
   3. Add a lifecycle rule with an expiration and a noncurrent version expiration action for all objects with prefix doc/ in mybucket.
      {{.Prompt}} {{.HelpName}} --prefix "doc/" --expire-days "300" --noncurrent-expire-days "100" \
           myminio/mybucket/
+
+  4. Expire the objects with prefix logs/ 30 days after their creation.
+     {{.Prompt}} {{.HelpName}} --template log-expiry-30d --prefix "logs/" myminio/mybucket
+
+  5. Transition objects to the tier WARM-TIER after 180 days, instead of the 90 days of the template.
+     {{.Prompt}} {{.HelpName}} --template tier-after-90d --transition-tier "WARM-TIER" --transition-days "180" myminio/mybucket
+
+  6. Add a rule from the user template ~/.mc/templates/ilm/archive.yaml.
+     {{.Prompt}} {{.HelpName}} --template archive myminio/mybucket
+
+  7. Transition objects larger than 100MiB to the tier WARM-TIER after 30 days.
+     {{.Prompt}} {{.HelpName}} --size-gt 100MiB --transition-days "30" --transition-tier "WARM-TIER" myminio/mybucket
+
+  8. Expire objects with prefix tmp/ smaller than 1KiB after 7 days.
+     {{.Prompt}} {{.HelpName}} --prefix "tmp/" --size-lt 1KiB --expire-days "7" myminio/mybucket
 `,
 }
 
 var ilmAddFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "template",
+		Usage: "start from a built-in or user rule template, see DESCRIPTION",
+	},
+	cli.IntFlag{
+		Name:  "abort-incomplete-days",
+		Usage: "number of days to abort incomplete multipart uploads",
+	},
 	cli.StringFlag{
 		Name:  "prefix",
 		Usage: "object prefix",
@@ -77,6 +110,14 @@ var ilmAddFlags = []cli.Flag{
 		Name:  "tags",
 		Usage: "key value pairs of the form '<key1>=<value1>&<key2>=<value2>&<key3>=<value3>'",
 	},
+	cli.StringFlag{
+		Name:  "size-lt",
+		Usage: "only objects smaller than this size, e.g. 1MiB",
+	},
+	cli.StringFlag{
+		Name:  "size-gt",
+		Usage: "only objects larger than this size, e.g. 1GiB",
+	},
 	cli.StringFlag{
 		Name:   "expiry-date",
 		Usage:  "format 'YYYY-MM-DD' the date of expiration",
@@ -224,8 +265,16 @@ func mainILMAdd(cliCtx *cli.Context) error {
 	opts, err := ilm.GetLifecycleOptions(cliCtx)
 	fatalIf(err.Trace(args...), "Unable to generate new lifecycle rules for the input")
 
-	newRule, err := opts.ToILMRule()
-	fatalIf(err.Trace(args...), "Unable to generate new lifecycle rules for the input")
+	var newRule lifecycle.Rule
+	if name := cliCtx.String("template"); name != "" {
+		template, err := loadILMRuleTemplate(name)
+		fatalIf(err, "Unable to load lifecycle rule template `"+name+"`")
+		newRule, err = opts.FromTemplate(template)
+		fatalIf(err.Trace(args...), "Unable to generate new lifecycle rules from template `"+name+"`")
+	} else {
+		newRule, err = opts.ToILMRule()
+		fatalIf(err.Trace(args...), "Unable to generate new lifecycle rules for the input")
+	}
 
 	lfcCfg.Rules = append(lfcCfg.Rules, newRule)
 
@@ -59,6 +59,9 @@ EXAMPLES:
   3. Disable the rule with id "rHTY.a123".
      {{.Prompt}} {{.HelpName}} --id "rHTY.a123" --disable s3/mybucket
 
+  4. Restrict the rule with id "rHTY.a123" to objects larger than 1GiB.
+     {{.Prompt}} {{.HelpName}} --id "rHTY.a123" --size-gt 1GiB s3/mybucket
+
 `,
 }
 
@@ -28,21 +28,32 @@ import (
 	"github.com/trinet2005/oss-mc/pkg/probe"
 )
 
+var ilmExportFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "format",
+		Usage: "format of the lifecycle configuration, json or yaml",
+		Value: ilmFormatJSON,
+	},
+}
+
 var ilmExportCmd = cli.Command{
 	Name:         "export",
-	Usage:        "export lifecycle configuration in JSON format",
+	Usage:        "export lifecycle configuration in JSON or YAML format",
 	Action:       mainILMExport,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        globalFlags,
+	Flags:        append(ilmExportFlags, globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
 USAGE:
-  {{.HelpName}} TARGET
+  {{.HelpName}} [FLAGS] TARGET
 
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
 DESCRIPTION:
-  Exports lifecycle configuration in JSON format to STDOUT.
+  Exports lifecycle configuration in JSON or YAML format to STDOUT.
 
 EXAMPLES:
   1. Export lifecycle configuration for 'mybucket' to 'lifecycle.json' file.
@@ -50,6 +61,9 @@ EXAMPLES:
 
   2. Print lifecycle configuration for 'mybucket' to STDOUT.
      {{.Prompt}} {{.HelpName}} play/mybucket
+
+  3. Export lifecycle configuration for 'mybucket' to 'lifecycle.yaml' file, to edit it.
+     {{.Prompt}} {{.HelpName}} --format yaml myminio/mybucket > lifecycle.yaml
 `,
 }
 
@@ -58,9 +72,15 @@ type ilmExportMessage struct {
 	Target    string                   `json:"target"`
 	Config    *lifecycle.Configuration `json:"config"`
 	UpdatedAt time.Time                `json:"updatedAt,omitempty"`
+	format    string
 }
 
 func (i ilmExportMessage) String() string {
+	if i.format == ilmFormatYAML {
+		out, err := ilmConfigToYAML(i.Config)
+		fatalIf(err, "Unable to export ILM configuration")
+		return string(out)
+	}
 	msgBytes, e := json.MarshalIndent(i.Config, "", " ")
 	fatalIf(probe.NewError(e), "Unable to export ILM configuration")
 
@@ -79,6 +99,7 @@ func checkILMExportSyntax(ctx *cli.Context) {
 	if len(ctx.Args()) != 1 {
 		showCommandHelpAndExit(ctx, globalErrorExitStatus)
 	}
+	checkILMFormat(ctx.String("format"))
 }
 
 func mainILMExport(cliCtx *cli.Context) error {
@@ -106,6 +127,7 @@ func mainILMExport(cliCtx *cli.Context) error {
 		Target:    urlStr,
 		Config:    ilmCfg,
 		UpdatedAt: updatedAt,
+		format:    cliCtx.String("format"),
 	})
 
 	return nil
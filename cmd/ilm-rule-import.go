@@ -20,29 +20,49 @@ package cmd
 import (
 	"context"
 	"os"
+	"strings"
 
 	"github.com/minio/cli"
 	json "github.com/minio/colorjson"
+	minio "github.com/trinet2005/oss-go-sdk"
 	"github.com/trinet2005/oss-go-sdk/pkg/lifecycle"
+	"github.com/trinet2005/oss-mc/cmd/ilm"
 	"github.com/trinet2005/oss-mc/pkg/probe"
 	"github.com/trinet2005/oss-pkg/console"
 )
 
+var ilmImportFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "format",
+		Usage: "format of the lifecycle configuration, json or yaml",
+		Value: ilmFormatJSON,
+	},
+	cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "validate the lifecycle configuration and show the changes, without importing it",
+	},
+}
+
 var ilmImportCmd = cli.Command{
 	Name:         "import",
-	Usage:        "import lifecycle configuration in JSON format",
+	Usage:        "import lifecycle configuration in JSON or YAML format",
 	Action:       mainILMImport,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        globalFlags,
+	Flags:        append(ilmImportFlags, globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
 USAGE:
-  {{.HelpName}} TARGET
+  {{.HelpName}} [FLAGS] TARGET
 
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
 DESCRIPTION:
-  Import entire lifecycle configuration from STDIN, input file is expected to be in JSON format.
+  Import entire lifecycle configuration from STDIN, input file is expected to be in JSON format,
+  or in YAML format with --format yaml. The configuration is validated and the changes to the
+  current configuration are shown before it is imported.
 
 EXAMPLES:
   1. Set lifecycle configuration for the mybucket on alias 'myminio' to the rules imported from lifecycle.json
@@ -50,6 +70,10 @@ EXAMPLES:
 
   2. Set lifecycle configuration for the mybucket on alias 'myminio'. User is expected to enter the JSON contents on STDIN
      {{.Prompt}} {{.HelpName}} myminio/mybucket
+
+  3. Review the changes of an edited lifecycle.yaml, exported with 'mc ilm rule export --format yaml', then import it.
+     {{.Prompt}} {{.HelpName}} --format yaml --dry-run myminio/mybucket < lifecycle.yaml
+     {{.Prompt}} {{.HelpName}} --format yaml myminio/mybucket < lifecycle.yaml
 `,
 }
 
@@ -68,18 +92,34 @@ func (i ilmImportMessage) JSON() string {
 	return string(msgBytes)
 }
 
-// readILMConfig read from stdin, returns XML.
-func readILMConfig() (*lifecycle.Configuration, *probe.Error) {
-	// User is expected to enter the lifecycleConfiguration instance contents in JSON format
-	cfg := lifecycle.NewConfiguration()
+// ilmImportDiffMessage shows the changes of an import to the current
+// lifecycle configuration, as a diff of their YAML forms.
+type ilmImportDiffMessage struct {
+	Status string   `json:"status"`
+	Target string   `json:"target"`
+	Diff   []string `json:"diff"`
+	DryRun bool     `json:"dryRun"`
+}
 
-	// Consume json from STDIN
-	dec := json.NewDecoder(os.Stdin)
-	if e := dec.Decode(cfg); e != nil {
-		return cfg, probe.NewError(e)
+func (i ilmImportDiffMessage) String() string {
+	lines := make([]string, 0, len(i.Diff))
+	for _, line := range i.Diff {
+		switch {
+		case strings.HasPrefix(line, "+"):
+			line = console.Colorize(ilmThemeDiffAdded, line)
+		case strings.HasPrefix(line, "-"):
+			line = console.Colorize(ilmThemeDiffRemoved, line)
+		}
+		lines = append(lines, line)
 	}
+	return strings.Join(lines, "\n")
+}
 
-	return cfg, nil
+func (i ilmImportDiffMessage) JSON() string {
+	i.Status = "success"
+	msgBytes, e := json.MarshalIndent(i, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
 }
 
 // checkILMImportSyntax - validate arguments passed by user
@@ -87,6 +127,27 @@ func checkILMImportSyntax(ctx *cli.Context) {
 	if len(ctx.Args()) != 1 {
 		showCommandHelpAndExit(ctx, globalErrorExitStatus)
 	}
+	checkILMFormat(ctx.String("format"))
+}
+
+// diffILMConfig returns the diff between the YAML forms of two lifecycle
+// configurations and whether they differ.
+func diffILMConfig(current, imported *lifecycle.Configuration) ([]string, bool, *probe.Error) {
+	currentYAML, err := ilmConfigToYAML(current)
+	if err != nil {
+		return nil, false, err
+	}
+	importedYAML, err := ilmConfigToYAML(imported)
+	if err != nil {
+		return nil, false, err
+	}
+	diff := diffLines(splitYAMLLines(currentYAML), splitYAMLLines(importedYAML))
+	for _, line := range diff {
+		if !strings.HasPrefix(line, "  ") {
+			return diff, true, nil
+		}
+	}
+	return diff, false, nil
 }
 
 func mainILMImport(cliCtx *cli.Context) error {
@@ -102,7 +163,7 @@ func mainILMImport(cliCtx *cli.Context) error {
 	client, err := newClient(urlStr)
 	fatalIf(err.Trace(urlStr), "Unable to initialize client for "+urlStr)
 
-	ilmCfg, err := readILMConfig()
+	ilmCfg, err := readILMConfigFrom(os.Stdin, cliCtx.String("format"))
 	fatalIf(err.Trace(args...), "Unable to read ILM configuration")
 
 	if len(ilmCfg.Rules) == 0 {
@@ -110,6 +171,31 @@ func mainILMImport(cliCtx *cli.Context) error {
 		// since no rules are provided and we will show a success message.
 		fatalIf(errDummy(), "The provided ILM configuration does not contain any rule, aborting.")
 	}
+	fatalIf(ilm.ValidateConfig(ilmCfg).Trace(args...), "Invalid ILM configuration")
+
+	// Preview the changes to the current configuration.
+	currentCfg, _, err := client.GetLifecycle(ctx)
+	if err != nil {
+		if minio.ToErrorResponse(err.ToGoError()).Code != "NoSuchLifecycleConfiguration" {
+			fatalIf(err.Trace(urlStr), "Unable to get lifecycle configuration")
+		}
+		currentCfg = lifecycle.NewConfiguration()
+	}
+	diff, changed, err := diffILMConfig(currentCfg, ilmCfg)
+	fatalIf(err.Trace(urlStr), "Unable to compare lifecycle configurations")
+	if !changed {
+		console.Infoln("Lifecycle configuration of `" + urlStr + "` is unchanged.")
+		return nil
+	}
+	isFake := cliCtx.Bool("dry-run")
+	printMsg(ilmImportDiffMessage{
+		Target: urlStr,
+		Diff:   diff,
+		DryRun: isFake,
+	})
+	if isFake {
+		return nil
+	}
 
 	fatalIf(client.SetLifecycle(ctx, ilmCfg).Trace(urlStr), "Unable to set new lifecycle rules")
 
@@ -0,0 +1,135 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	minio "github.com/trinet2005/oss-go-sdk"
+	"github.com/trinet2005/oss-mc/cmd/ilm"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+var ilmLintCmd = cli.Command{
+	Name:         "lint",
+	Usage:        "check the lifecycle configuration of a bucket for contradictions",
+	Action:       mainILMLint,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Check the lifecycle configuration of a bucket and print a warning, with the IDs of the rules
+  involved, for every:
+    invalid                    configuration which would be rejected by 'mc ilm rule import'
+    disabled                   rule which is disabled
+    overlapping-filters        pair of rules expiring or transitioning the same objects differently
+    never-fires                action made impossible by another rule expiring the objects first
+    transition-after-expiry    transition of a rule scheduled after the expiration of its objects
+    noncurrent-on-unversioned  noncurrent version or delete marker action on an unversioned bucket
+
+  The exit status is non-zero when a warning is printed.
+
+EXAMPLES:
+  1. Check the lifecycle configuration of mybucket on alias 'myminio'.
+     {{.Prompt}} {{.HelpName}} myminio/mybucket
+`,
+}
+
+// ilmLintMessage is a warning about a lifecycle configuration.
+type ilmLintMessage struct {
+	Status  string   `json:"status"`
+	Target  string   `json:"target"`
+	Check   string   `json:"check"`
+	RuleIDs []string `json:"ruleIDs,omitempty"`
+	Message string   `json:"message"`
+}
+
+func (i ilmLintMessage) String() string {
+	rules := ""
+	if len(i.RuleIDs) > 0 {
+		rules = " [" + strings.Join(i.RuleIDs, ", ") + "]"
+	}
+	return console.Colorize(ilmThemeResultFailure, i.Check+rules+": ") + i.Message
+}
+
+func (i ilmLintMessage) JSON() string {
+	i.Status = "warning"
+	msgBytes, e := json.MarshalIndent(i, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// checkILMLintSyntax - validate arguments passed by user
+func checkILMLintSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, globalErrorExitStatus)
+	}
+}
+
+func mainILMLint(cliCtx *cli.Context) error {
+	ctx, cancelILMLint := context.WithCancel(globalContext)
+	defer cancelILMLint()
+
+	checkILMLintSyntax(cliCtx)
+	setILMDisplayColorScheme()
+
+	urlStr := cliCtx.Args().Get(0)
+	client, err := newClient(urlStr)
+	fatalIf(err.Trace(urlStr), "Unable to initialize client for "+urlStr)
+
+	cfg, _, err := client.GetLifecycle(ctx)
+	if err != nil {
+		if minio.ToErrorResponse(err.ToGoError()).Code == "NoSuchLifecycleConfiguration" {
+			console.Infoln("No lifecycle configuration is set on `" + urlStr + "`.")
+			return nil
+		}
+		fatalIf(err.Trace(urlStr), "Unable to get lifecycle configuration")
+	}
+
+	versioning, err := client.GetVersion(ctx)
+	fatalIf(err.Trace(urlStr), "Unable to get versioning configuration")
+	versioned := versioning.Status != ""
+
+	warnings := ilm.Lint(cfg, versioned)
+	if len(warnings) == 0 {
+		console.Infoln("Lifecycle configuration of `" + urlStr + "` has no problem.")
+		return nil
+	}
+	for _, w := range warnings {
+		printMsg(ilmLintMessage{
+			Target:  urlStr,
+			Check:   w.Check,
+			RuleIDs: w.RuleIDs,
+			Message: w.Message,
+		})
+	}
+	return exitStatus(globalErrorExitStatus)
+}
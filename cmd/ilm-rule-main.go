@@ -26,6 +26,7 @@ var ilmRuleSubcommands = []cli.Command{
 	ilmRmCmd,
 	ilmExportCmd,
 	ilmImportCmd,
+	ilmLintCmd,
 }
 
 var ilmRuleCmd = cli.Command{
@@ -0,0 +1,104 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	gojson "encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/trinet2005/oss-go-sdk/pkg/lifecycle"
+	"github.com/trinet2005/oss-mc/cmd/ilm"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// getILMTemplatesDir returns the folder of the user lifecycle rule
+// templates, ~/.mc/templates/ilm by default.
+func getILMTemplatesDir() (string, *probe.Error) {
+	configDir, err := getMcConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "templates", "ilm"), nil
+}
+
+// ilmRuleFromTemplateFile decodes a rule template file, one lifecycle
+// rule in the JSON format of 'mc ilm rule export', or in YAML.
+func ilmRuleFromTemplateFile(data []byte, format string) (lifecycle.Rule, *probe.Error) {
+	var rule lifecycle.Rule
+	if format == ilmFormatYAML {
+		var v interface{}
+		if e := yaml.Unmarshal(data, &v); e != nil {
+			return rule, probe.NewError(e)
+		}
+		v, e := yamlToJSONValue(v)
+		if e != nil {
+			return rule, probe.NewError(e)
+		}
+		if data, e = gojson.Marshal(v); e != nil {
+			return rule, probe.NewError(e)
+		}
+	}
+	dec := gojson.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if e := dec.Decode(&rule); e != nil {
+		return rule, probe.NewError(e)
+	}
+	return rule, nil
+}
+
+// loadILMRuleTemplate returns the rule of the named template. A user
+// template, name.json, name.yaml or name.yml in the templates folder,
+// takes precedence over the built-in template of the same name.
+func loadILMRuleTemplate(name string) (lifecycle.Rule, *probe.Error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return lifecycle.Rule{}, errInvalidArgument().Trace(name)
+	}
+	dir, err := getILMTemplatesDir()
+	if err != nil {
+		return lifecycle.Rule{}, err
+	}
+	for _, ext := range []string{".json", ".yaml", ".yml"} {
+		filename := filepath.Join(dir, name+ext)
+		data, e := os.ReadFile(filename)
+		if os.IsNotExist(e) {
+			continue
+		}
+		if e != nil {
+			return lifecycle.Rule{}, probe.NewError(e).Trace(filename)
+		}
+		format := ilmFormatJSON
+		if ext != ".json" {
+			format = ilmFormatYAML
+		}
+		rule, err := ilmRuleFromTemplateFile(data, format)
+		if err != nil {
+			return lifecycle.Rule{}, err.Trace(filename)
+		}
+		return rule, nil
+	}
+	if t, ok := ilm.BuiltinTemplate(name); ok {
+		return t.Rule, nil
+	}
+	return lifecycle.Rule{}, probe.NewError(errors.New("no such lifecycle rule template, built-in templates are " +
+		strings.Join(ilm.BuiltinTemplateNames(), ", "))).Trace(name)
+}
@@ -0,0 +1,248 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	gojson "encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/trinet2005/oss-go-sdk/pkg/lifecycle"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Formats of an exported lifecycle configuration.
+const (
+	ilmFormatJSON = "json"
+	ilmFormatYAML = "yaml"
+)
+
+// checkILMFormat validates the value of --format.
+func checkILMFormat(format string) {
+	if format != ilmFormatJSON && format != ilmFormatYAML {
+		fatalIf(errInvalidArgument().Trace(format), "Unknown format `"+format+"`, valid values are json and yaml.")
+	}
+}
+
+// ilmConfigToYAML returns the YAML form of a lifecycle configuration. The
+// fields are the ones of its JSON form, in the same order, without the
+// empty ones, so that the YAML round-trips through the JSON form.
+func ilmConfigToYAML(cfg *lifecycle.Configuration) ([]byte, *probe.Error) {
+	data, e := gojson.Marshal(cfg)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	dec := gojson.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	v, e := jsonToYAMLValue(dec)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	out, e := yaml.Marshal(v)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	return out, nil
+}
+
+// jsonToYAMLValue reads the next JSON value from dec, keeping the order of
+// object keys. Null values and empty objects and arrays are returned as nil.
+func jsonToYAMLValue(dec *gojson.Decoder) (interface{}, error) {
+	t, e := dec.Token()
+	if e != nil {
+		return nil, e
+	}
+	switch t := t.(type) {
+	case gojson.Delim:
+		switch t {
+		case '{':
+			var m yaml.MapSlice
+			for dec.More() {
+				k, e := dec.Token()
+				if e != nil {
+					return nil, e
+				}
+				v, e := jsonToYAMLValue(dec)
+				if e != nil {
+					return nil, e
+				}
+				if v != nil {
+					m = append(m, yaml.MapItem{Key: k, Value: v})
+				}
+			}
+			if _, e = dec.Token(); e != nil {
+				return nil, e
+			}
+			if len(m) == 0 {
+				return nil, nil
+			}
+			return m, nil
+		case '[':
+			var s []interface{}
+			for dec.More() {
+				v, e := jsonToYAMLValue(dec)
+				if e != nil {
+					return nil, e
+				}
+				if v != nil {
+					s = append(s, v)
+				}
+			}
+			if _, e = dec.Token(); e != nil {
+				return nil, e
+			}
+			if len(s) == 0 {
+				return nil, nil
+			}
+			return s, nil
+		}
+		return nil, fmt.Errorf("unexpected %v", t)
+	case gojson.Number:
+		if i, e := t.Int64(); e == nil {
+			return i, nil
+		}
+		return t.Float64()
+	default:
+		return t, nil
+	}
+}
+
+// ilmConfigFromYAML parses the YAML form of a lifecycle configuration.
+func ilmConfigFromYAML(data []byte) (*lifecycle.Configuration, *probe.Error) {
+	var v interface{}
+	if e := yaml.Unmarshal(data, &v); e != nil {
+		return nil, probe.NewError(e)
+	}
+	v, e := yamlToJSONValue(v)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	data, e = gojson.Marshal(v)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	cfg := lifecycle.NewConfiguration()
+	if e = gojson.Unmarshal(data, cfg); e != nil {
+		return nil, probe.NewError(e)
+	}
+	return cfg, nil
+}
+
+// yamlToJSONValue converts the maps decoded from YAML, keyed by any value,
+// to maps keyed by strings which can be encoded in JSON.
+func yamlToJSONValue(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			key, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("key %v is not a string", k)
+			}
+			val, e := yamlToJSONValue(val)
+			if e != nil {
+				return nil, e
+			}
+			m[key] = val
+		}
+		return m, nil
+	case []interface{}:
+		for i, val := range v {
+			val, e := yamlToJSONValue(val)
+			if e != nil {
+				return nil, e
+			}
+			v[i] = val
+		}
+		return v, nil
+	}
+	return v, nil
+}
+
+// readILMConfigFrom reads a lifecycle configuration in the given format.
+func readILMConfigFrom(r io.Reader, format string) (*lifecycle.Configuration, *probe.Error) {
+	data, e := io.ReadAll(r)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	if format == ilmFormatYAML {
+		return ilmConfigFromYAML(data)
+	}
+	cfg := lifecycle.NewConfiguration()
+	if e = gojson.Unmarshal(data, cfg); e != nil {
+		return nil, probe.NewError(e)
+	}
+	return cfg, nil
+}
+
+// diffLines returns the lines of a line by line diff from a to b, each
+// prefixed with "- " if removed, "+ " if added or "  " if kept.
+func diffLines(a, b []string) []string {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []string
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, "  "+a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, "- "+a[i])
+			i++
+		default:
+			lines = append(lines, "+ "+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, "- "+a[i])
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, "+ "+b[j])
+	}
+	return lines
+}
+
+// splitYAMLLines splits YAML into lines, without the trailing empty line.
+func splitYAMLLines(data []byte) []string {
+	s := strings.TrimSuffix(string(data), "\n")
+	if s == "" || s == "{}" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	gojson "encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestILMYAMLValues(t *testing.T) {
+	testCases := []struct {
+		json     string
+		yaml     string
+		jsonBack string
+	}{
+		{
+			`{"Rules":[{"ID":"a","Status":"Enabled","Expiration":{"Days":30},"Filter":null,"Tags":[]}]}`,
+			"Rules:\n- ID: a\n  Status: Enabled\n  Expiration:\n    Days: 30\n",
+			`{"Rules":[{"Expiration":{"Days":30},"ID":"a","Status":"Enabled"}]}`,
+		},
+		{
+			`{"Rules":[{"ID":"b","Transition":{"Date":"2030-01-01T00:00:00Z","StorageClass":"WARM"},"Filter":{}}]}`,
+			"Rules:\n- ID: b\n  Transition:\n    Date: \"2030-01-01T00:00:00Z\"\n    StorageClass: WARM\n",
+			`{"Rules":[{"ID":"b","Transition":{"Date":"2030-01-01T00:00:00Z","StorageClass":"WARM"}}]}`,
+		},
+	}
+
+	for i, testCase := range testCases {
+		dec := gojson.NewDecoder(strings.NewReader(testCase.json))
+		dec.UseNumber()
+		v, e := jsonToYAMLValue(dec)
+		if e != nil {
+			t.Fatalf("Test %d: unexpected error: %v", i+1, e)
+		}
+		out, e := yaml.Marshal(v)
+		if e != nil {
+			t.Fatalf("Test %d: unexpected error: %v", i+1, e)
+		}
+		if string(out) != testCase.yaml {
+			t.Fatalf("Test %d: expected YAML %q, got %q", i+1, testCase.yaml, string(out))
+		}
+
+		var back interface{}
+		if e = yaml.Unmarshal(out, &back); e != nil {
+			t.Fatalf("Test %d: unexpected error: %v", i+1, e)
+		}
+		if back, e = yamlToJSONValue(back); e != nil {
+			t.Fatalf("Test %d: unexpected error: %v", i+1, e)
+		}
+		data, e := gojson.Marshal(back)
+		if e != nil {
+			t.Fatalf("Test %d: unexpected error: %v", i+1, e)
+		}
+		if !bytes.Equal(data, []byte(testCase.jsonBack)) {
+			t.Fatalf("Test %d: expected JSON %s, got %s", i+1, testCase.jsonBack, data)
+		}
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	testCases := []struct {
+		a, b     []string
+		expected []string
+	}{
+		{nil, nil, nil},
+		{[]string{"a"}, nil, []string{"- a"}},
+		{nil, []string{"a"}, []string{"+ a"}},
+		{[]string{"a", "b", "c"}, []string{"a", "b", "c"}, []string{"  a", "  b", "  c"}},
+		{[]string{"a", "b", "c"}, []string{"a", "x", "c"}, []string{"  a", "- b", "+ x", "  c"}},
+		{[]string{"a", "c"}, []string{"a", "b", "c", "d"}, []string{"  a", "+ b", "  c", "+ d"}},
+	}
+
+	for i, testCase := range testCases {
+		if diff := diffLines(testCase.a, testCase.b); !reflect.DeepEqual(diff, testCase.expected) {
+			t.Fatalf("Test %d: expected %q, got %q", i+1, testCase.expected, diff)
+		}
+	}
+}
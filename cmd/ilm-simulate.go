@@ -0,0 +1,303 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	minio "github.com/trinet2005/oss-go-sdk"
+	"github.com/trinet2005/oss-go-sdk/pkg/lifecycle"
+	"github.com/trinet2005/oss-mc/cmd/ilm"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+var ilmSimulateFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "file",
+		Usage: "simulate the lifecycle configuration of this file instead of the bucket's",
+	},
+	cli.StringFlag{
+		Name:  "format",
+		Usage: "format of --file, json or yaml",
+		Value: ilmFormatJSON,
+	},
+	cli.BoolFlag{
+		Name:  "include-disabled",
+		Usage: "simulate the disabled rules as if they were enabled",
+	},
+	cli.BoolFlag{
+		Name:  "summary",
+		Usage: "only print the aggregate counts, not every object",
+	},
+}
+
+var ilmSimulateCmd = cli.Command{
+	Name:         "simulate",
+	Usage:        "preview which objects lifecycle rules expire or transition",
+	Action:       mainILMSimulate,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(ilmSimulateFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  List the current versions of the objects under TARGET and report the first rule which
+  would expire or transition each of them, and when. Nothing is modified.
+
+EXAMPLES:
+  1. Preview the effect of the lifecycle configuration of mybucket.
+     {{.Prompt}} {{.HelpName}} myminio/mybucket
+
+  2. Preview the effect of the rules of lifecycle.yaml on the objects under a prefix, before importing it.
+     {{.Prompt}} {{.HelpName}} --file lifecycle.yaml --format yaml myminio/mybucket/logs/
+
+  3. Print how many objects and bytes each rule, including the disabled ones, would act on.
+     {{.Prompt}} {{.HelpName}} --include-disabled --summary myminio/mybucket
+`,
+}
+
+// ilmSimulateMessage reports the lifecycle action due for an object.
+type ilmSimulateMessage struct {
+	Status       string    `json:"status"`
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"lastModified"`
+	RuleID       string    `json:"ruleID"`
+	Action       string    `json:"action"`
+	Tier         string    `json:"tier,omitempty"`
+	Due          time.Time `json:"due"`
+	Overdue      bool      `json:"overdue"`
+}
+
+func (m ilmSimulateMessage) String() string {
+	action := m.Action
+	if m.Tier != "" {
+		action += " to " + m.Tier
+	}
+	due := m.Due.Format(printDate)
+	if m.Overdue {
+		due = console.Colorize(ilmThemeResultFailure, due)
+	}
+	return fmt.Sprintf("[%s] %-20s %-16s %7s %s", due, action, m.RuleID, humanize.IBytes(uint64(m.Size)), m.Key)
+}
+
+func (m ilmSimulateMessage) JSON() string {
+	m.Status = "success"
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// ilmSimulateRuleSummary aggregates the objects a rule acts on.
+type ilmSimulateRuleSummary struct {
+	RuleID  string `json:"ruleID"`
+	Action  string `json:"action"`
+	Tier    string `json:"tier,omitempty"`
+	Objects int64  `json:"objects"`
+	Size    int64  `json:"size"`
+	Overdue int64  `json:"overdue"`
+}
+
+// ilmSimulateSummaryMessage aggregates a simulation.
+type ilmSimulateSummaryMessage struct {
+	Status          string                   `json:"status"`
+	Target          string                   `json:"target"`
+	Objects         int64                    `json:"objects"`
+	Size            int64                    `json:"size"`
+	UnaffectedCount int64                    `json:"unaffectedObjects"`
+	UnaffectedSize  int64                    `json:"unaffectedSize"`
+	Rules           []ilmSimulateRuleSummary `json:"rules"`
+	ruleIndex       map[string]int
+	now             time.Time
+}
+
+// add records the action due for an object, nil when no rule applies.
+func (s *ilmSimulateSummaryMessage) add(action *ilm.SimulatedAction, size int64) {
+	s.Objects++
+	s.Size += size
+	if action == nil {
+		s.UnaffectedCount++
+		s.UnaffectedSize += size
+		return
+	}
+	id := action.RuleID + "\x00" + action.Action
+	i, ok := s.ruleIndex[id]
+	if !ok {
+		if s.ruleIndex == nil {
+			s.ruleIndex = make(map[string]int)
+		}
+		i = len(s.Rules)
+		s.ruleIndex[id] = i
+		s.Rules = append(s.Rules, ilmSimulateRuleSummary{RuleID: action.RuleID, Action: action.Action, Tier: action.Tier})
+	}
+	s.Rules[i].Objects++
+	s.Rules[i].Size += size
+	if !action.Due.After(s.now) {
+		s.Rules[i].Overdue++
+	}
+}
+
+func (s ilmSimulateSummaryMessage) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %d object(s), %s\n", console.Colorize(ilmMainHeader, s.Target+":"), s.Objects, humanize.IBytes(uint64(s.Size)))
+	rules := append([]ilmSimulateRuleSummary{}, s.Rules...)
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].RuleID != rules[j].RuleID {
+			return rules[i].RuleID < rules[j].RuleID
+		}
+		return rules[i].Action < rules[j].Action
+	})
+	for _, r := range rules {
+		action := r.Action
+		if r.Tier != "" {
+			action += " to " + r.Tier
+		}
+		fmt.Fprintf(&b, "  %-16s %-20s %d object(s), %s, %d already due\n", r.RuleID, action, r.Objects, humanize.IBytes(uint64(r.Size)), r.Overdue)
+	}
+	fmt.Fprintf(&b, "  %-16s %-20s %d object(s), %s", "-", "no action", s.UnaffectedCount, humanize.IBytes(uint64(s.UnaffectedSize)))
+	return b.String()
+}
+
+func (s ilmSimulateSummaryMessage) JSON() string {
+	s.Status = "success"
+	msgBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// checkILMSimulateSyntax - validate arguments passed by user
+func checkILMSimulateSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, globalErrorExitStatus)
+	}
+	checkILMFormat(ctx.String("format"))
+}
+
+// readILMSimulateConfig returns the lifecycle configuration to simulate,
+// from --file when set or from the bucket otherwise.
+func readILMSimulateConfig(ctx context.Context, cliCtx *cli.Context, client Client) (*lifecycle.Configuration, *probe.Error) {
+	file := cliCtx.String("file")
+	if file == "" {
+		cfg, _, err := client.GetLifecycle(ctx)
+		if err != nil && minio.ToErrorResponse(err.ToGoError()).Code == "NoSuchLifecycleConfiguration" {
+			return lifecycle.NewConfiguration(), nil
+		}
+		return cfg, err
+	}
+	f, e := os.Open(file)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	defer f.Close()
+	cfg, err := readILMConfigFrom(f, cliCtx.String("format"))
+	if err != nil {
+		return nil, err.Trace(file)
+	}
+	return cfg, ilm.ValidateConfig(cfg).Trace(file)
+}
+
+func mainILMSimulate(cliCtx *cli.Context) error {
+	ctx, cancelILMSimulate := context.WithCancel(globalContext)
+	defer cancelILMSimulate()
+
+	checkILMSimulateSyntax(cliCtx)
+	setILMDisplayColorScheme()
+
+	urlStr := cliCtx.Args().Get(0)
+	targetAlias, _, _ := mustExpandAlias(urlStr)
+
+	client, err := newClient(urlStr)
+	fatalIf(err.Trace(urlStr), "Unable to initialize client for "+urlStr)
+	if client.GetURL().Type != objectStorage {
+		fatalIf(errInvalidArgument().Trace(urlStr), "Lifecycle simulation works only with buckets.")
+	}
+
+	cfg, err := readILMSimulateConfig(ctx, cliCtx, client)
+	fatalIf(err.Trace(urlStr), "Unable to get lifecycle configuration")
+
+	includeDisabled := cliCtx.Bool("include-disabled")
+	var withTags bool
+	for _, rule := range cfg.Rules {
+		if (rule.Status == "Enabled" || includeDisabled) && ilm.RuleHasTags(rule) {
+			withTags = true
+		}
+	}
+
+	summaryOnly := cliCtx.Bool("summary")
+	summary := ilmSimulateSummaryMessage{Target: urlStr, now: time.Now().UTC()}
+	var failed bool
+	for content := range client.List(ctx, ListOptions{Recursive: true, WithMetadata: withTags, ShowDir: DirNone}) {
+		if content.Err != nil {
+			errorIf(content.Err.Trace(urlStr), "Unable to list the objects.")
+			failed = true
+			continue
+		}
+		if content.IsDeleteMarker {
+			continue
+		}
+		var tags map[string]string
+		if withTags {
+			tags, err = getContentTags(ctx, targetAlias, content)
+			if err != nil {
+				warningIf(warningSkipped, err.Trace(content.URL.String()), "Unable to get the tags of `"+content.URL.String()+"`, ignoring it.")
+				continue
+			}
+		}
+		_, key := url2BucketAndObject(&content.URL)
+		action, ok := ilm.Simulate(cfg, key, content.Size, content.Time, tags, includeDisabled)
+		if !ok {
+			summary.add(nil, content.Size)
+			continue
+		}
+		summary.add(&action, content.Size)
+		if !summaryOnly {
+			printMsg(ilmSimulateMessage{
+				Key:          key,
+				Size:         content.Size,
+				LastModified: content.Time,
+				RuleID:       action.RuleID,
+				Action:       action.Action,
+				Tier:         action.Tier,
+				Due:          action.Due,
+				Overdue:      !action.Due.After(summary.now),
+			})
+		}
+	}
+	printMsg(summary)
+
+	if failed {
+		return exitStatus(globalErrorExitStatus)
+	}
+	return nil
+}
@@ -18,18 +18,43 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
 	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-admin-go"
+	minio "github.com/trinet2005/oss-go-sdk"
 	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
 )
 
+var adminTierVerifyFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "access-key",
+		Usage: "also write, read and delete a test object from this machine with the access key of the tier",
+	},
+	cli.StringFlag{
+		Name:  "secret-key",
+		Usage: "secret key of the tier, with --access-key",
+	},
+}
+
 var adminTierVerifyCmd = cli.Command{
 	Name:         "verify",
-	Usage:        "verifies if remote tier configuration is valid",
+	Usage:        "verify a remote tier with a test write, read and delete",
 	Action:       mainAdminTierVerify,
-	Hidden:       true,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        globalFlags,
+	Flags:        append(adminTierVerifyFlags, globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -42,12 +67,177 @@ NAME:
 FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}
+DESCRIPTION:
+  The server writes, reads and deletes a test object on the remote tier with the credentials
+  it is configured with, and the latency of the check is reported. With --access-key and
+  --secret-key, the same operations are also run from this machine one by one, for S3 and
+  MinIO tiers, to report the latency and the permission problems of each of them.
+
 EXAMPLES:
   1. Verify if a tier config is valid.
      {{.Prompt}} {{.HelpName}} myminio WARM-TIER
+
+  2. Verify a tier and the permissions of its credentials on its bucket.
+     {{.Prompt}} {{.HelpName}} --access-key ACCESSKEY --secret-key SECRETKEY myminio WARM-TIER
 `,
 }
 
+// tierVerifyCheck is the outcome of one operation against a tier.
+type tierVerifyCheck struct {
+	Name    string        `json:"name"`
+	Latency time.Duration `json:"latency"`
+	Error   string        `json:"error,omitempty"`
+	Hint    string        `json:"hint,omitempty"`
+}
+
+// tierVerifyMessage reports the checks of a tier.
+type tierVerifyMessage struct {
+	Status   string            `json:"status"`
+	TierName string            `json:"tierName"`
+	Endpoint string            `json:"tierEndpoint,omitempty"`
+	Bucket   string            `json:"bucket,omitempty"`
+	Prefix   string            `json:"prefix,omitempty"`
+	Checks   []tierVerifyCheck `json:"checks"`
+}
+
+// failed returns true if a check failed.
+func (msg tierVerifyMessage) failed() bool {
+	for _, c := range msg.Checks {
+		if c.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// String colorized tier verify message.
+func (msg tierVerifyMessage) String() string {
+	var b strings.Builder
+	if msg.failed() {
+		b.WriteString(console.Colorize("TierFail", "Remote tier "+msg.TierName+" failed verification"))
+	} else {
+		b.WriteString(console.Colorize("TierMessage", "Verified remote tier "+msg.TierName))
+	}
+	for _, c := range msg.Checks {
+		latency := c.Latency.Round(time.Millisecond).String()
+		if c.Error == "" {
+			fmt.Fprintf(&b, "\n  %-7s %s %s", c.Name, console.Colorize("TierMessage", "OK  "), latency)
+			continue
+		}
+		fmt.Fprintf(&b, "\n  %-7s %s %s %s", c.Name, console.Colorize("TierFail", "FAIL"), latency, c.Error)
+		if c.Hint != "" {
+			fmt.Fprintf(&b, "\n          %s", c.Hint)
+		}
+	}
+	return b.String()
+}
+
+// JSON jsonified tier verify message.
+func (msg tierVerifyMessage) JSON() string {
+	msg.Status = "success"
+	if msg.failed() {
+		msg.Status = "error"
+	}
+	jsonMessageBytes, e := json.MarshalIndent(msg, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// tierVerifyHint returns an actionable explanation of the error of a
+// check, from the S3 or admin API error code when there is one.
+func tierVerifyHint(code string, e error) string {
+	switch code {
+	case "AccessDenied", "XMinioAdminTierInsufficientPermissions":
+		return "the tier credentials are not allowed this operation on the tier bucket and prefix"
+	case "InvalidAccessKeyId", "SignatureDoesNotMatch", "InvalidToken", "ExpiredToken", "XMinioAdminTierInvalidCredentials":
+		return "the tier credentials are invalid or expired, update them with 'mc ilm tier update'"
+	case "NoSuchBucket", "XMinioAdminTierBucketNotFound":
+		return "the tier bucket does not exist"
+	case "XMinioAdminTierBackendNotEmpty":
+		return "the tier bucket and prefix are not empty"
+	}
+	var netErr net.Error
+	if errors.As(e, &netErr) {
+		return "the tier endpoint is unreachable"
+	}
+	return ""
+}
+
+// runTierVerifyCheck runs and times one check.
+func runTierVerifyCheck(name string, fn func() error, code func(error) string) tierVerifyCheck {
+	start := time.Now()
+	e := fn()
+	c := tierVerifyCheck{Name: name, Latency: time.Since(start)}
+	if e != nil {
+		c.Error = e.Error()
+		c.Hint = tierVerifyHint(code(e), e)
+	}
+	return c
+}
+
+// verifyTierOperations writes, reads and deletes a test object on an S3
+// or MinIO tier with the given credentials, stopping at the first failure.
+func verifyTierOperations(ctx context.Context, tier *madmin.TierConfig, accessKey, secretKey string) ([]tierVerifyCheck, *probe.Error) {
+	s3Config := NewS3Config(urlJoinPath(tier.Endpoint(), tier.Bucket()), &aliasConfigV10{
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+		API:       "S3v4",
+		Path:      "auto",
+	})
+	clnt, err := S3New(s3Config)
+	if err != nil {
+		return nil, err.Trace(tier.Endpoint())
+	}
+	s3Clnt, ok := clnt.(*S3Client)
+	if !ok {
+		return nil, errInvalidTarget(tier.Endpoint())
+	}
+	api := s3Clnt.api
+	bucket := tier.Bucket()
+	object := strings.TrimSuffix(tier.Prefix(), "/") + "/" + randString(16, rand.NewSource(time.Now().UnixNano()), "mc-tier-verify-")
+	object = strings.TrimPrefix(object, "/")
+	payload := []byte("mc ilm tier verify")
+	code := func(e error) string { return minio.ToErrorResponse(e).Code }
+
+	steps := []struct {
+		name string
+		fn   func() error
+	}{
+		{"write", func() error {
+			_, e := api.PutObject(ctx, bucket, object, bytes.NewReader(payload), int64(len(payload)), minio.PutObjectOptions{})
+			return e
+		}},
+		{"read", func() error {
+			obj, e := api.GetObject(ctx, bucket, object, minio.GetObjectOptions{})
+			if e != nil {
+				return e
+			}
+			defer obj.Close()
+			data, e := io.ReadAll(obj)
+			if e != nil {
+				return e
+			}
+			if !bytes.Equal(data, payload) {
+				return errors.New("the object read differs from the object written")
+			}
+			return nil
+		}},
+		{"delete", func() error {
+			return api.RemoveObject(ctx, bucket, object, minio.RemoveObjectOptions{})
+		}},
+	}
+	var checks []tierVerifyCheck
+	for _, step := range steps {
+		c := runTierVerifyCheck(step.name, step.fn, code)
+		checks = append(checks, c)
+		if c.Error != "" {
+			break
+		}
+	}
+	return checks, nil
+}
+
 func mainAdminTierVerify(ctx *cli.Context) error {
 	args := ctx.Args()
 	nArgs := len(args)
@@ -64,18 +254,59 @@ func mainAdminTierVerify(ctx *cli.Context) error {
 	if tierName == "" {
 		fatalIf(errInvalidArgument(), "Tier name can't be empty")
 	}
+	accessKey, secretKey := ctx.String("access-key"), ctx.String("secret-key")
+	if (accessKey == "") != (secretKey == "") {
+		fatalIf(errInvalidArgument(), "--access-key and --secret-key must be set together.")
+	}
+
+	console.SetColor("TierMessage", color.New(color.FgGreen))
+	console.SetColor("TierFail", color.New(color.FgRed, color.Bold))
 
 	// Create a new MinIO Admin Client
 	client, cerr := newAdminClient(aliasedURL)
 	fatalIf(cerr, "Unable to initialize admin connection.")
 
-	e := client.VerifyTier(globalContext, tierName)
-	fatalIf(probe.NewError(e).Trace(args...), "Unable to verify remote tier target")
+	if ctx.Command.Name == "check" {
+		e := client.VerifyTier(globalContext, tierName)
+		fatalIf(probe.NewError(e).Trace(args...), "Unable to verify remote tier target")
 
-	printMsg(&tierMessage{
-		op:       ctx.Command.Name,
-		Status:   "success",
-		TierName: tierName,
-	})
+		printMsg(&tierMessage{
+			op:       ctx.Command.Name,
+			Status:   "success",
+			TierName: tierName,
+		})
+		return nil
+	}
+
+	msg := tierVerifyMessage{TierName: tierName}
+	msg.Checks = append(msg.Checks, runTierVerifyCheck("server", func() error {
+		return client.VerifyTier(globalContext, tierName)
+	}, func(e error) string { return madmin.ToErrorResponse(e).Code }))
+
+	if accessKey != "" {
+		tiers, e := client.ListTiers(globalContext)
+		fatalIf(probe.NewError(e).Trace(aliasedURL), "Unable to list remote tiers")
+		var tier *madmin.TierConfig
+		for _, t := range tiers {
+			if t.Name == tierName {
+				tier = t
+			}
+		}
+		if tier == nil {
+			fatalIf(errInvalidArgument().Trace(tierName), "No remote tier `"+tierName+"` found.")
+		}
+		if tier.Type != madmin.S3 && tier.Type != madmin.MinIO {
+			fatalIf(errInvalidArgument().Trace(tier.Type.String()), "Remote tier `"+tierName+"` is of type "+tier.Type.String()+", only S3 and MinIO tiers can be checked with --access-key.")
+		}
+		msg.Endpoint, msg.Bucket, msg.Prefix = tier.Endpoint(), tier.Bucket(), tier.Prefix()
+		checks, err := verifyTierOperations(globalContext, tier, accessKey, secretKey)
+		fatalIf(err, "Unable to connect to remote tier `"+tierName+"`.")
+		msg.Checks = append(msg.Checks, checks...)
+	}
+
+	printMsg(msg)
+	if msg.failed() {
+		return exitStatus(globalErrorExitStatus)
+	}
 	return nil
 }
@@ -0,0 +1,46 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestTierVerifyHint(t *testing.T) {
+	testCases := []struct {
+		code   string
+		e      error
+		expect string
+	}{
+		{"", nil, ""},
+		{"InternalError", errors.New("internal error"), ""},
+		{"AccessDenied", nil, "the tier credentials are not allowed this operation on the tier bucket and prefix"},
+		{"XMinioAdminTierInsufficientPermissions", nil, "the tier credentials are not allowed this operation on the tier bucket and prefix"},
+		{"SignatureDoesNotMatch", nil, "the tier credentials are invalid or expired, update them with 'mc ilm tier update'"},
+		{"NoSuchBucket", nil, "the tier bucket does not exist"},
+		{"", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, "the tier endpoint is unreachable"},
+	}
+	for i, testCase := range testCases {
+		hint := tierVerifyHint(testCase.code, testCase.e)
+		if hint != testCase.expect {
+			t.Fatalf("Test %d: expected %q, got %q", i+1, testCase.expect, hint)
+		}
+	}
+}
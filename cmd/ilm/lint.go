@@ -0,0 +1,187 @@
+// Copyright (c) 2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package ilm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/trinet2005/oss-go-sdk/pkg/lifecycle"
+)
+
+// Checks reported by Lint.
+const (
+	LintInvalid        = "invalid"
+	LintDisabled       = "disabled"
+	LintOverlap        = "overlapping-filters"
+	LintNeverFires     = "never-fires"
+	LintTransitionLate = "transition-after-expiry"
+	LintUnversioned    = "noncurrent-on-unversioned"
+)
+
+// LintWarning is a problem found in a lifecycle configuration.
+type LintWarning struct {
+	Check   string
+	RuleIDs []string
+	Message string
+}
+
+// ruleTagMap returns the tags of the filter of rule.
+func ruleTagMap(rule lifecycle.Rule) map[string]string {
+	tags := make(map[string]string)
+	if !rule.RuleFilter.Tag.IsEmpty() {
+		tags[rule.RuleFilter.Tag.Key] = rule.RuleFilter.Tag.Value
+	}
+	for _, tag := range rule.RuleFilter.And.Tags {
+		tags[tag.Key] = tag.Value
+	}
+	return tags
+}
+
+// sizesOverlap returns true if some object size is in the size ranges
+// of both rules, a zero bound being unbounded.
+func sizesOverlap(a, b lifecycle.Rule) bool {
+	la, ga := getObjectSizes(a)
+	lb, gb := getObjectSizes(b)
+	lo, hi := ga, la
+	if gb > lo {
+		lo = gb
+	}
+	if hi == 0 || lb > 0 && lb < hi {
+		hi = lb
+	}
+	return hi == 0 || lo+1 < hi
+}
+
+// sizeCovers returns true if the size range of a contains the one of b.
+func sizeCovers(a, b lifecycle.Rule) bool {
+	la, ga := getObjectSizes(a)
+	lb, gb := getObjectSizes(b)
+	return ga <= gb && (la == 0 || lb > 0 && lb <= la)
+}
+
+// filtersOverlap returns true if some object may match the filters of
+// both rules.
+func filtersOverlap(a, b lifecycle.Rule) bool {
+	pa, pb := getPrefix(a), getPrefix(b)
+	if !strings.HasPrefix(pa, pb) && !strings.HasPrefix(pb, pa) {
+		return false
+	}
+	if !sizesOverlap(a, b) {
+		return false
+	}
+	tb := ruleTagMap(b)
+	for k, v := range ruleTagMap(a) {
+		if vb, ok := tb[k]; ok && vb != v {
+			return false
+		}
+	}
+	return true
+}
+
+// filterCovers returns true if every object matching the filter of b
+// also matches the filter of a.
+func filterCovers(a, b lifecycle.Rule) bool {
+	if !strings.HasPrefix(getPrefix(b), getPrefix(a)) || !sizeCovers(a, b) {
+		return false
+	}
+	tb := ruleTagMap(b)
+	for k, v := range ruleTagMap(a) {
+		if vb, ok := tb[k]; !ok || vb != v {
+			return false
+		}
+	}
+	return true
+}
+
+// expires returns true if rule expires the current versions of objects.
+func expires(rule lifecycle.Rule) bool {
+	return !rule.Expiration.IsDaysNull() || !rule.Expiration.IsDateNull()
+}
+
+// Lint returns the contradictions of a lifecycle configuration, of a
+// versioned bucket or not, for which rules behave differently than one
+// would expect or not at all.
+func Lint(cfg *lifecycle.Configuration, versioned bool) []LintWarning {
+	var warnings []LintWarning
+	warn := func(check, message string, ids ...string) {
+		warnings = append(warnings, LintWarning{Check: check, RuleIDs: ids, Message: message})
+	}
+
+	if err := ValidateConfig(cfg); err != nil {
+		warn(LintInvalid, err.ToGoError().Error())
+	}
+
+	var enabled []lifecycle.Rule
+	for _, rule := range cfg.Rules {
+		if rule.Status != "Enabled" {
+			warn(LintDisabled, "rule is disabled and never fires", rule.ID)
+			continue
+		}
+		enabled = append(enabled, rule)
+
+		expiryDays := int(rule.Expiration.Days)
+		if transitionDays := int(rule.Transition.Days); transitionDays > 0 && expiryDays > 0 && transitionDays >= expiryDays {
+			warn(LintTransitionLate, fmt.Sprintf("objects expire after %d days, before their transition after %d days", expiryDays, transitionDays), rule.ID)
+		}
+		noncurrentExpiryDays := int(rule.NoncurrentVersionExpiration.NoncurrentDays)
+		if days := int(rule.NoncurrentVersionTransition.NoncurrentDays); days > 0 && noncurrentExpiryDays > 0 && days >= noncurrentExpiryDays {
+			warn(LintTransitionLate, fmt.Sprintf("noncurrent versions expire after %d days, before their transition after %d days", noncurrentExpiryDays, days), rule.ID)
+		}
+
+		if !versioned {
+			switch {
+			case !rule.NoncurrentVersionExpiration.IsDaysNull() || rule.NoncurrentVersionExpiration.NewerNoncurrentVersions > 0:
+				warn(LintUnversioned, "noncurrent version expiration never fires, the bucket is not versioned", rule.ID)
+			case rule.NoncurrentVersionTransition.StorageClass != "":
+				warn(LintUnversioned, "noncurrent version transition never fires, the bucket is not versioned", rule.ID)
+			case rule.Expiration.IsDeleteMarkerExpirationEnabled():
+				warn(LintUnversioned, "delete marker expiration never fires, the bucket is not versioned", rule.ID)
+			}
+		}
+	}
+
+	for i, a := range enabled {
+		for j, b := range enabled {
+			if i == j || !filtersOverlap(a, b) {
+				continue
+			}
+			// a expiring all the objects of b earlier shadows the actions of b.
+			if days := int(a.Expiration.Days); days > 0 && filterCovers(a, b) {
+				if d := int(b.Expiration.Days); d > 0 && (days < d || days == d && i < j) {
+					warn(LintNeverFires, fmt.Sprintf("expiration after %d days never fires, rule %s expires the same objects after %d days", d, a.ID, days), b.ID, a.ID)
+					continue
+				}
+				if d := int(b.Transition.Days); d > 0 && days <= d {
+					warn(LintNeverFires, fmt.Sprintf("transition after %d days never fires, rule %s expires the same objects after %d days", d, a.ID, days), b.ID, a.ID)
+					continue
+				}
+			}
+			if j < i {
+				continue // report the overlap of a pair once
+			}
+			if expires(a) && expires(b) && !filterCovers(a, b) && !filterCovers(b, a) {
+				warn(LintOverlap, "both rules expire some objects, the earliest expiration applies", a.ID, b.ID)
+			}
+			if a.Transition.StorageClass != "" && b.Transition.StorageClass != "" && a.Transition.StorageClass != b.Transition.StorageClass {
+				warn(LintOverlap, fmt.Sprintf("both rules transition some objects, to %s and %s, the earliest transition applies", a.Transition.StorageClass, b.Transition.StorageClass), a.ID, b.ID)
+			}
+		}
+	}
+	return warnings
+}
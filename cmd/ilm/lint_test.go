@@ -0,0 +1,78 @@
+// Copyright (c) 2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package ilm
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/trinet2005/oss-go-sdk/pkg/lifecycle"
+)
+
+func TestLint(t *testing.T) {
+	rule := func(id, prefix string, expireDays, transitionDays int) lifecycle.Rule {
+		r := lifecycle.Rule{ID: id, Status: "Enabled", RuleFilter: lifecycle.Filter{Prefix: prefix}}
+		r.Expiration.Days = lifecycle.ExpirationDays(expireDays)
+		if transitionDays > 0 {
+			r.Transition = lifecycle.Transition{Days: lifecycle.ExpirationDays(transitionDays), StorageClass: "WARM"}
+		}
+		return r
+	}
+	tagged := func(r lifecycle.Rule, key, value string) lifecycle.Rule {
+		r.RuleFilter.Tag = lifecycle.Tag{Key: key, Value: value}
+		return r
+	}
+	sized := func(r lifecycle.Rule, lessThan, greaterThan int64) lifecycle.Rule {
+		r.RuleFilter.ObjectSizeLessThan, r.RuleFilter.ObjectSizeGreaterThan = lessThan, greaterThan
+		return r
+	}
+	disabled := rule("off", "", 10, 0)
+	disabled.Status = "Disabled"
+	noncurrent := lifecycle.Rule{ID: "nc", Status: "Enabled"}
+	noncurrent.NoncurrentVersionExpiration.NoncurrentDays = 30
+
+	testCases := []struct {
+		rules     []lifecycle.Rule
+		versioned bool
+		expected  []string // checks of the warnings
+	}{
+		{[]lifecycle.Rule{rule("all", "", 365, 0), rule("logs", "logs/", 30, 0)}, false, nil},
+		{[]lifecycle.Rule{rule("all", "", 30, 0), rule("logs", "logs/", 90, 0)}, false, []string{LintNeverFires}},
+		{[]lifecycle.Rule{rule("all", "", 30, 0), rule("tier", "data/", 0, 60)}, false, []string{LintNeverFires}},
+		{[]lifecycle.Rule{rule("one", "", 30, 90)}, false, []string{LintTransitionLate}},
+		{[]lifecycle.Rule{tagged(rule("a", "", 30, 0), "k", "1"), tagged(rule("b", "", 60, 0), "t", "2")}, false, []string{LintOverlap}},
+		{[]lifecycle.Rule{tagged(rule("a", "", 30, 0), "k", "1"), tagged(rule("b", "", 60, 0), "k", "2")}, false, nil},
+		{[]lifecycle.Rule{rule("a", "logs/", 30, 0), rule("b", "data/", 10, 0)}, false, nil},
+		{[]lifecycle.Rule{sized(rule("small", "", 30, 0), 1000, 0), sized(rule("large", "", 0, 60), 0, 999)}, false, nil},
+		{[]lifecycle.Rule{sized(rule("large", "", 30, 0), 0, 500), sized(rule("larger", "", 0, 60), 0, 1000)}, false, []string{LintNeverFires}},
+		{[]lifecycle.Rule{rule("a", "", 30, 0), disabled}, false, []string{LintDisabled}},
+		{[]lifecycle.Rule{noncurrent}, false, []string{LintUnversioned}},
+		{[]lifecycle.Rule{noncurrent}, true, nil},
+		{[]lifecycle.Rule{rule("dup", "a/", 30, 0), rule("dup", "b/", 60, 0)}, false, []string{LintInvalid}},
+	}
+	for i, testCase := range testCases {
+		warnings := Lint(&lifecycle.Configuration{Rules: testCase.rules}, testCase.versioned)
+		var checks []string
+		for _, w := range warnings {
+			checks = append(checks, w.Check)
+		}
+		if !reflect.DeepEqual(checks, testCase.expected) {
+			t.Fatalf("Test %d: expected %v, got %v (%+v)", i+1, testCase.expected, checks, warnings)
+		}
+	}
+}
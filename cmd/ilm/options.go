@@ -23,6 +23,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/dustin/go-humanize"
 	"github.com/minio/cli"
 	"github.com/rs/xid"
 	"github.com/trinet2005/oss-go-sdk/pkg/lifecycle"
@@ -75,6 +76,49 @@ type LifecycleOptions struct {
 	NoncurrentVersionTransitionDays         *int
 	NewerNoncurrentTransitionVersions       *int
 	NoncurrentVersionTransitionStorageClass *string
+	AbortIncompleteDays                     *int
+
+	ObjectSizeLessThan    *int64
+	ObjectSizeGreaterThan *int64
+}
+
+// ruleFilter returns the filter selecting the objects matching all the
+// given predicates. Tags, or more than one predicate, are combined in an
+// And element, a single predicate is set in the filter itself.
+func ruleFilter(prefix string, tags []lifecycle.Tag, sizeLessThan, sizeGreaterThan int64) lifecycle.Filter {
+	predicates := len(tags)
+	for _, set := range []bool{prefix != "", sizeLessThan > 0, sizeGreaterThan > 0} {
+		if set {
+			predicates++
+		}
+	}
+	if len(tags) > 0 || predicates > 1 {
+		return lifecycle.Filter{
+			And: lifecycle.And{
+				Prefix:                prefix,
+				Tags:                  tags,
+				ObjectSizeLessThan:    sizeLessThan,
+				ObjectSizeGreaterThan: sizeGreaterThan,
+			},
+		}
+	}
+	return lifecycle.Filter{
+		Prefix:                prefix,
+		ObjectSizeLessThan:    sizeLessThan,
+		ObjectSizeGreaterThan: sizeGreaterThan,
+	}
+}
+
+// getObjectSizes returns the object size predicates of the filter of rule.
+func getObjectSizes(rule lifecycle.Rule) (lessThan, greaterThan int64) {
+	lessThan, greaterThan = rule.RuleFilter.ObjectSizeLessThan, rule.RuleFilter.ObjectSizeGreaterThan
+	if lessThan == 0 {
+		lessThan = rule.RuleFilter.And.ObjectSizeLessThan
+	}
+	if greaterThan == 0 {
+		greaterThan = rule.RuleFilter.And.ObjectSizeGreaterThan
+	}
+	return lessThan, greaterThan
 }
 
 // ToILMRule creates lifecycle.Configuration based on LifecycleOptions
@@ -89,6 +133,7 @@ func (opts LifecycleOptions) ToILMRule() (lifecycle.Rule, *probe.Error) {
 		nonCurrentVersionTransitionDays         lifecycle.ExpirationDays
 		newerNonCurrentTransitionVersions       int
 		nonCurrentVersionTransitionStorageClass string
+		abortIncompleteDays                     lifecycle.ExpirationDays
 	)
 
 	id = opts.ID
@@ -110,22 +155,24 @@ func (opts LifecycleOptions) ToILMRule() (lifecycle.Rule, *probe.Error) {
 		return lifecycle.Rule{}, err
 	}
 
-	andVal := lifecycle.And{}
+	var (
+		prefix                        string
+		tags                          []lifecycle.Tag
+		sizeLessThan, sizeGreaterThan int64
+	)
+	if opts.Prefix != nil {
+		prefix = *opts.Prefix
+	}
 	if opts.Tags != nil {
-		andVal.Tags = extractILMTags(*opts.Tags)
+		tags = extractILMTags(*opts.Tags)
 	}
-
-	if opts.Prefix != nil {
-		filter.Prefix = *opts.Prefix
+	if opts.ObjectSizeLessThan != nil {
+		sizeLessThan = *opts.ObjectSizeLessThan
 	}
-
-	if len(andVal.Tags) > 0 {
-		filter.And = andVal
-		if opts.Prefix != nil {
-			filter.And.Prefix = *opts.Prefix
-		}
-		filter.Prefix = ""
+	if opts.ObjectSizeGreaterThan != nil {
+		sizeGreaterThan = *opts.ObjectSizeGreaterThan
 	}
+	filter = ruleFilter(prefix, tags, sizeLessThan, sizeGreaterThan)
 
 	if opts.NoncurrentVersionExpirationDays != nil {
 		nonCurrentVersionExpirationDays = lifecycle.ExpirationDays(*opts.NoncurrentVersionExpirationDays)
@@ -142,6 +189,9 @@ func (opts LifecycleOptions) ToILMRule() (lifecycle.Rule, *probe.Error) {
 	if opts.NoncurrentVersionTransitionStorageClass != nil {
 		nonCurrentVersionTransitionStorageClass = *opts.NoncurrentVersionTransitionStorageClass
 	}
+	if opts.AbortIncompleteDays != nil {
+		abortIncompleteDays = lifecycle.ExpirationDays(*opts.AbortIncompleteDays)
+	}
 
 	newRule := lifecycle.Rule{
 		ID:         id,
@@ -158,6 +208,9 @@ func (opts LifecycleOptions) ToILMRule() (lifecycle.Rule, *probe.Error) {
 			NewerNoncurrentVersions: newerNonCurrentTransitionVersions,
 			StorageClass:            nonCurrentVersionTransitionStorageClass,
 		},
+		AbortIncompleteMultipartUpload: lifecycle.AbortIncompleteMultipartUpload{
+			DaysAfterInitiation: abortIncompleteDays,
+		},
 	}
 
 	if err := validateILMRule(newRule); err != nil {
@@ -203,6 +256,9 @@ func GetLifecycleOptions(ctx *cli.Context) (LifecycleOptions, *probe.Error) {
 		noncurrentVersionTransitionDays   *int
 		newerNoncurrentTransitionVersions *int
 		noncurrentTier                    *string
+		abortIncompleteDays               *int
+		sizeLessThan                      *int64
+		sizeGreaterThan                   *int64
 	)
 
 	id = ctx.String("id")
@@ -251,10 +307,12 @@ func GetLifecycleOptions(ctx *cli.Context) (LifecycleOptions, *probe.Error) {
 	if f := "noncurrent-transition-tier"; ctx.IsSet(f) {
 		noncurrentTier = strPtr(strings.ToUpper(ctx.String(f)))
 	}
-	if tier != nil && !ctx.IsSet("transition-days") && !ctx.IsSet("transition-date") {
+	// A template may provide the days of the transitions.
+	fromTemplate := ctx.IsSet("template")
+	if tier != nil && !fromTemplate && !ctx.IsSet("transition-days") && !ctx.IsSet("transition-date") {
 		return LifecycleOptions{}, probe.NewError(errors.New("transition-date or transition-days must be set"))
 	}
-	if noncurrentTier != nil && !ctx.IsSet("noncurrentversion-transition-days") && !ctx.IsSet("noncurrent-transition-days") {
+	if noncurrentTier != nil && !fromTemplate && !ctx.IsSet("noncurrentversion-transition-days") && !ctx.IsSet("noncurrent-transition-days") {
 		return LifecycleOptions{}, probe.NewError(errors.New("noncurrentversion-transition-days must be set"))
 	}
 	// for MinIO transition storage-class is same as label defined on
@@ -312,6 +370,26 @@ func GetLifecycleOptions(ctx *cli.Context) (LifecycleOptions, *probe.Error) {
 	if f := "noncurrent-transition-newer"; ctx.IsSet(f) {
 		newerNoncurrentTransitionVersions = intPtr(ctx.Int(f))
 	}
+	if f := "abort-incomplete-days"; ctx.IsSet(f) {
+		abortIncompleteDays = intPtr(ctx.Int(f))
+	}
+	for _, size := range []struct {
+		flag string
+		dest **int64
+	}{
+		{"size-lt", &sizeLessThan},
+		{"size-gt", &sizeGreaterThan},
+	} {
+		if !ctx.IsSet(size.flag) {
+			continue
+		}
+		n, e := humanize.ParseBytes(ctx.String(size.flag))
+		if e != nil {
+			return LifecycleOptions{}, probe.NewError(e).Trace(ctx.String(size.flag))
+		}
+		v := int64(n)
+		*size.dest = &v
+	}
 
 	return LifecycleOptions{
 		ID:                                      id,
@@ -329,24 +407,42 @@ func GetLifecycleOptions(ctx *cli.Context) (LifecycleOptions, *probe.Error) {
 		NoncurrentVersionTransitionDays:         noncurrentVersionTransitionDays,
 		NewerNoncurrentTransitionVersions:       newerNoncurrentTransitionVersions,
 		NoncurrentVersionTransitionStorageClass: noncurrentTier,
+		AbortIncompleteDays:                     abortIncompleteDays,
+		ObjectSizeLessThan:                      sizeLessThan,
+		ObjectSizeGreaterThan:                   sizeGreaterThan,
 	}, nil
 }
 
 // ApplyRuleFields applies non nil fields of LifcycleOptions to the existing lifecycle rule
 func ApplyRuleFields(dest *lifecycle.Rule, opts LifecycleOptions) *probe.Error {
-	// If src has tags, it should override the destination
-	if opts.Tags != nil {
-		dest.RuleFilter.And.Tags = extractILMTags(*opts.Tags)
-	}
+	// The filter predicates given in src override the ones of the destination,
+	// the others are kept.
+	if opts.Tags != nil || opts.Prefix != nil || opts.ObjectSizeLessThan != nil || opts.ObjectSizeGreaterThan != nil {
+		prefix := dest.RuleFilter.Prefix
+		if prefix == "" {
+			prefix = dest.RuleFilter.And.Prefix
+		}
+		tags := dest.RuleFilter.And.Tags
+		if !dest.RuleFilter.Tag.IsEmpty() {
+			tags = append([]lifecycle.Tag{dest.RuleFilter.Tag}, tags...)
+		}
+		sizeLessThan, sizeGreaterThan := getObjectSizes(*dest)
 
-	// since prefix is a part of command args, it is always present in the src rule and
-	// it should be always set to the destination.
-	if opts.Prefix != nil {
-		if dest.RuleFilter.And.Tags != nil {
-			dest.RuleFilter.And.Prefix = *opts.Prefix
-		} else {
-			dest.RuleFilter.Prefix = *opts.Prefix
+		if opts.Tags != nil {
+			tags = extractILMTags(*opts.Tags)
+		}
+		// since prefix is a part of command args, it is always present in the src rule and
+		// it should be always set to the destination.
+		if opts.Prefix != nil {
+			prefix = *opts.Prefix
 		}
+		if opts.ObjectSizeLessThan != nil {
+			sizeLessThan = *opts.ObjectSizeLessThan
+		}
+		if opts.ObjectSizeGreaterThan != nil {
+			sizeGreaterThan = *opts.ObjectSizeGreaterThan
+		}
+		dest.RuleFilter = ruleFilter(prefix, tags, sizeLessThan, sizeGreaterThan)
 	}
 
 	// only one of expiration day, date or transition day, date is expected
@@ -415,6 +511,10 @@ func ApplyRuleFields(dest *lifecycle.Rule, opts LifecycleOptions) *probe.Error {
 		dest.Transition.StorageClass = *opts.StorageClass
 	}
 
+	if opts.AbortIncompleteDays != nil {
+		dest.AbortIncompleteMultipartUpload.DaysAfterInitiation = lifecycle.ExpirationDays(*opts.AbortIncompleteDays)
+	}
+
 	// Updated the status
 	if opts.Status != nil {
 		dest.Status = func() string {
@@ -0,0 +1,95 @@
+// Copyright (c) 2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package ilm
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/trinet2005/oss-go-sdk/pkg/lifecycle"
+)
+
+func TestApplyRuleFieldsObjectSize(t *testing.T) {
+	int64Ptr := func(n int64) *int64 { return &n }
+	tag := lifecycle.Tag{Key: "k", Value: "v"}
+
+	testCases := []struct {
+		filter   lifecycle.Filter
+		opts     LifecycleOptions
+		expected lifecycle.Filter
+	}{
+		{
+			lifecycle.Filter{},
+			LifecycleOptions{ObjectSizeGreaterThan: int64Ptr(100)},
+			lifecycle.Filter{ObjectSizeGreaterThan: 100},
+		},
+		{
+			lifecycle.Filter{Prefix: "logs/"},
+			LifecycleOptions{ObjectSizeLessThan: int64Ptr(100)},
+			lifecycle.Filter{And: lifecycle.And{Prefix: "logs/", ObjectSizeLessThan: 100}},
+		},
+		{
+			lifecycle.Filter{Tag: tag},
+			LifecycleOptions{ObjectSizeLessThan: int64Ptr(100)},
+			lifecycle.Filter{And: lifecycle.And{Tags: []lifecycle.Tag{tag}, ObjectSizeLessThan: 100}},
+		},
+		{
+			lifecycle.Filter{And: lifecycle.And{Prefix: "logs/", ObjectSizeLessThan: 100}},
+			LifecycleOptions{ObjectSizeLessThan: int64Ptr(0)},
+			lifecycle.Filter{Prefix: "logs/"},
+		},
+		{
+			lifecycle.Filter{ObjectSizeGreaterThan: 100},
+			LifecycleOptions{Prefix: strPtr("logs/"), ObjectSizeLessThan: int64Ptr(1000)},
+			lifecycle.Filter{And: lifecycle.And{Prefix: "logs/", ObjectSizeLessThan: 1000, ObjectSizeGreaterThan: 100}},
+		},
+		{
+			lifecycle.Filter{And: lifecycle.And{Prefix: "logs/", Tags: []lifecycle.Tag{tag}}},
+			LifecycleOptions{Prefix: strPtr("data/")},
+			lifecycle.Filter{And: lifecycle.And{Prefix: "data/", Tags: []lifecycle.Tag{tag}}},
+		},
+	}
+	for i, testCase := range testCases {
+		rule := lifecycle.Rule{RuleFilter: testCase.filter}
+		if err := ApplyRuleFields(&rule, testCase.opts); err != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		if !reflect.DeepEqual(rule.RuleFilter, testCase.expected) {
+			t.Fatalf("Test %d: expected %+v, got %+v", i+1, testCase.expected, rule.RuleFilter)
+		}
+	}
+}
+
+func TestValidateObjectSize(t *testing.T) {
+	testCases := []struct {
+		filter  lifecycle.Filter
+		success bool
+	}{
+		{lifecycle.Filter{}, true},
+		{lifecycle.Filter{ObjectSizeLessThan: 100}, true},
+		{lifecycle.Filter{And: lifecycle.And{ObjectSizeLessThan: 100, ObjectSizeGreaterThan: 10}}, true},
+		{lifecycle.Filter{And: lifecycle.And{ObjectSizeLessThan: 100, ObjectSizeGreaterThan: 100}}, false},
+		{lifecycle.Filter{ObjectSizeGreaterThan: -1}, false},
+	}
+	for i, testCase := range testCases {
+		e := validateObjectSize(lifecycle.Rule{RuleFilter: testCase.filter})
+		if (e == nil) != testCase.success {
+			t.Fatalf("Test %d: expected success %v, got %v", i+1, testCase.success, e)
+		}
+	}
+}
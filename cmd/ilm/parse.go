@@ -90,8 +90,9 @@ func validateRuleAction(rule lifecycle.Rule) error {
 	newerNoncurrentVersionsExpiry := rule.NoncurrentVersionExpiration.NewerNoncurrentVersions > 0
 	noncurrentTransitionSet := rule.NoncurrentVersionTransition.StorageClass != ""
 	newerNoncurrentVersionsTransition := rule.NoncurrentVersionTransition.NewerNoncurrentVersions > 0
-	if !expirySet && !transitionSet && !noncurrentExpirySet && !noncurrentTransitionSet && !newerNoncurrentVersionsExpiry && !newerNoncurrentVersionsTransition {
-		return errors.New("at least one of Expiry, Transition, NoncurrentExpiry, NoncurrentVersionTransition actions should be specified in a rule")
+	abortIncompleteSet := !rule.AbortIncompleteMultipartUpload.IsDaysNull()
+	if !expirySet && !transitionSet && !noncurrentExpirySet && !noncurrentTransitionSet && !newerNoncurrentVersionsExpiry && !newerNoncurrentVersionsTransition && !abortIncompleteSet {
+		return errors.New("at least one of Expiry, Transition, NoncurrentExpiry, NoncurrentVersionTransition, AbortIncompleteMultipartUpload actions should be specified in a rule")
 	}
 	return nil
 }
@@ -154,6 +155,18 @@ func validateTranExpCurdate(rule lifecycle.Rule) error {
 	return e
 }
 
+// The object size range of a rule must not be empty.
+func validateObjectSize(rule lifecycle.Rule) error {
+	lessThan, greaterThan := getObjectSizes(rule)
+	if lessThan < 0 || greaterThan < 0 {
+		return errors.New("object size filters must not be negative")
+	}
+	if lessThan > 0 && greaterThan >= lessThan {
+		return errors.New("object size greater than filter must be smaller than object size less than filter")
+	}
+	return nil
+}
+
 // Check S3 compatibility for the new rule and some other basic checks.
 func validateILMRule(rule lifecycle.Rule) *probe.Error {
 	if e := validateRuleAction(rule); e != nil {
@@ -177,10 +190,49 @@ func validateILMRule(rule lifecycle.Rule) *probe.Error {
 	if e := validateNoncurrentTransition(rule); e != nil {
 		return probe.NewError(e)
 	}
+	if e := validateObjectSize(rule); e != nil {
+		return probe.NewError(e)
+	}
 
 	return nil
 }
 
+// ValidateConfig checks a whole lifecycle configuration before it is
+// imported: every rule has a unique ID, a valid status and passes the
+// checks of a new rule, except that its dates may be in the past.
+func ValidateConfig(cfg *lifecycle.Configuration) *probe.Error {
+	if len(cfg.Rules) == 0 {
+		return probe.NewError(errors.New("lifecycle configuration does not contain any rule"))
+	}
+	ids := make(map[string]bool, len(cfg.Rules))
+	for _, rule := range cfg.Rules {
+		if rule.ID == "" {
+			return probe.NewError(errors.New("rule ID is missing"))
+		}
+		if ids[rule.ID] {
+			return probe.NewError(errors.New("rule ID is not unique")).Trace(rule.ID)
+		}
+		ids[rule.ID] = true
+		if rule.Status != "Enabled" && rule.Status != "Disabled" {
+			return probe.NewError(errors.New("rule status should be Enabled or Disabled")).Trace(rule.ID, rule.Status)
+		}
+		for _, validate := range []func(lifecycle.Rule) error{
+			validateRuleAction,
+			validateExpiration,
+			validateTranExpDate,
+			validateTranDays,
+			validateNoncurrentExpiration,
+			validateNoncurrentTransition,
+			validateObjectSize,
+		} {
+			if e := validate(rule); e != nil {
+				return probe.NewError(e).Trace(rule.ID)
+			}
+		}
+	}
+	return nil
+}
+
 func parseTransitionDate(transitionDateStr string) (lifecycle.ExpirationDate, *probe.Error) {
 	transitionDate, e := time.Parse(defaultILMDateFormat, transitionDateStr)
 	if e != nil {
@@ -0,0 +1,113 @@
+// Copyright (c) 2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package ilm
+
+import (
+	"strings"
+	"time"
+
+	"github.com/trinet2005/oss-go-sdk/pkg/lifecycle"
+)
+
+// Lifecycle actions reported by Simulate.
+const (
+	ActionExpire     = "expire"
+	ActionTransition = "transition"
+)
+
+// SimulatedAction is the first lifecycle action that applies to an object.
+type SimulatedAction struct {
+	RuleID string
+	Action string
+	Tier   string
+	Due    time.Time
+}
+
+// expectedDueTime returns the midnight UTC following modTime plus days,
+// which is when the server applies a rule configured in days.
+func expectedDueTime(modTime time.Time, days int) time.Time {
+	return modTime.UTC().Add(time.Duration(days+1) * 24 * time.Hour).Truncate(24 * time.Hour)
+}
+
+// ruleMatches returns true if the filter of rule selects the object.
+func ruleMatches(rule lifecycle.Rule, key string, size int64, tags map[string]string) bool {
+	if !strings.HasPrefix(key, getPrefix(rule)) {
+		return false
+	}
+	lessThan, greaterThan := getObjectSizes(rule)
+	if lessThan > 0 && size >= lessThan || size <= greaterThan && greaterThan > 0 {
+		return false
+	}
+	hasTag := func(tag lifecycle.Tag) bool {
+		v, ok := tags[tag.Key]
+		return ok && v == tag.Value
+	}
+	if !rule.RuleFilter.Tag.IsEmpty() && !hasTag(rule.RuleFilter.Tag) {
+		return false
+	}
+	for _, tag := range rule.RuleFilter.And.Tags {
+		if !hasTag(tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// RuleHasTags returns true if rule filters objects by their tags.
+func RuleHasTags(rule lifecycle.Rule) bool {
+	return getTags(rule) != ""
+}
+
+// Simulate returns the earliest expiration or transition of the current
+// version of an object among the rules of cfg, expiration first when both
+// are due at the same time. Disabled rules are only considered when
+// includeDisabled is set.
+func Simulate(cfg *lifecycle.Configuration, key string, size int64, modTime time.Time, tags map[string]string, includeDisabled bool) (SimulatedAction, bool) {
+	var action SimulatedAction
+	var found bool
+	consider := func(a SimulatedAction) {
+		if !found || a.Due.Before(action.Due) || a.Due.Equal(action.Due) && a.Action == ActionExpire && action.Action != ActionExpire {
+			action, found = a, true
+		}
+	}
+	for _, rule := range cfg.Rules {
+		if rule.Status != "Enabled" && !includeDisabled {
+			continue
+		}
+		if !ruleMatches(rule, key, size, tags) {
+			continue
+		}
+		switch {
+		case rule.Expiration.Days > 0:
+			consider(SimulatedAction{RuleID: rule.ID, Action: ActionExpire, Due: expectedDueTime(modTime, int(rule.Expiration.Days))})
+		case !rule.Expiration.Date.IsZero():
+			consider(SimulatedAction{RuleID: rule.ID, Action: ActionExpire, Due: rule.Expiration.Date.Time})
+		}
+		if rule.Transition.StorageClass == "" {
+			continue
+		}
+		transition := SimulatedAction{RuleID: rule.ID, Action: ActionTransition, Tier: rule.Transition.StorageClass}
+		if !rule.Transition.Date.IsZero() {
+			transition.Due = rule.Transition.Date.Time
+		} else {
+			transition.Due = expectedDueTime(modTime, int(rule.Transition.Days))
+		}
+		consider(transition)
+	}
+	return action, found
+}
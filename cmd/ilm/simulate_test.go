@@ -0,0 +1,89 @@
+// Copyright (c) 2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package ilm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/trinet2005/oss-go-sdk/pkg/lifecycle"
+)
+
+func TestSimulate(t *testing.T) {
+	modTime := time.Date(2023, 5, 10, 15, 30, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2023, 5, d, 0, 0, 0, 0, time.UTC) }
+	cfg := &lifecycle.Configuration{
+		Rules: []lifecycle.Rule{
+			{
+				ID:         "logs-expire",
+				Status:     "Enabled",
+				RuleFilter: lifecycle.Filter{Prefix: "logs/"},
+				Expiration: lifecycle.Expiration{Days: 30},
+			},
+			{
+				ID:         "logs-tier",
+				Status:     "Enabled",
+				RuleFilter: lifecycle.Filter{Prefix: "logs/"},
+				Transition: lifecycle.Transition{Days: 7, StorageClass: "WARM"},
+			},
+			{
+				ID:         "tmp",
+				Status:     "Enabled",
+				RuleFilter: lifecycle.Filter{Tag: lifecycle.Tag{Key: "tmp", Value: "true"}},
+				Expiration: lifecycle.Expiration{Days: 7},
+			},
+			{
+				ID:         "large",
+				Status:     "Enabled",
+				RuleFilter: lifecycle.Filter{And: lifecycle.And{Prefix: "media/", ObjectSizeGreaterThan: 1000}},
+				Transition: lifecycle.Transition{Days: 1, StorageClass: "COLD"},
+			},
+			{
+				ID:         "disabled",
+				Status:     "Disabled",
+				Expiration: lifecycle.Expiration{Days: 1},
+			},
+		},
+	}
+	testCases := []struct {
+		key             string
+		size            int64
+		tags            map[string]string
+		includeDisabled bool
+		expected        SimulatedAction
+		found           bool
+	}{
+		{key: "data/a", found: false},
+		{key: "logs/a", expected: SimulatedAction{RuleID: "logs-tier", Action: ActionTransition, Tier: "WARM", Due: day(18)}, found: true},
+		{key: "data/a", tags: map[string]string{"tmp": "true"}, expected: SimulatedAction{RuleID: "tmp", Action: ActionExpire, Due: day(18)}, found: true},
+		{key: "logs/a", tags: map[string]string{"tmp": "true"}, expected: SimulatedAction{RuleID: "tmp", Action: ActionExpire, Due: day(18)}, found: true},
+		{key: "data/a", tags: map[string]string{"tmp": "false"}, found: false},
+		{key: "media/a", size: 1000, found: false},
+		{key: "media/a", size: 1001, expected: SimulatedAction{RuleID: "large", Action: ActionTransition, Tier: "COLD", Due: day(12)}, found: true},
+		{key: "data/a", includeDisabled: true, expected: SimulatedAction{RuleID: "disabled", Action: ActionExpire, Due: day(12)}, found: true},
+	}
+	for i, testCase := range testCases {
+		action, found := Simulate(cfg, testCase.key, testCase.size, modTime, testCase.tags, testCase.includeDisabled)
+		if found != testCase.found {
+			t.Fatalf("Test %d: expected found %v, got %v", i+1, testCase.found, found)
+		}
+		if found && action != testCase.expected {
+			t.Fatalf("Test %d: expected %+v, got %+v", i+1, testCase.expected, action)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package ilm
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/trinet2005/oss-go-sdk/pkg/lifecycle"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+// RuleTemplate is a lifecycle rule to start from when adding a rule,
+// completed and overridden by the command line flags.
+type RuleTemplate struct {
+	Description string
+	Rule        lifecycle.Rule
+}
+
+// builtinTemplates is the library of templates always available.
+var builtinTemplates = map[string]RuleTemplate{
+	"log-expiry-30d": {
+		Description: "expire objects 30 days after their creation",
+		Rule: lifecycle.Rule{
+			Expiration: lifecycle.Expiration{Days: 30},
+		},
+	},
+	"tier-after-90d": {
+		Description: "transition objects to --transition-tier 90 days after their creation",
+		Rule: lifecycle.Rule{
+			Transition: lifecycle.Transition{Days: 90},
+		},
+	},
+	"cleanup-incomplete": {
+		Description: "abort multipart uploads not completed within 7 days, not supported by MinIO",
+		Rule: lifecycle.Rule{
+			AbortIncompleteMultipartUpload: lifecycle.AbortIncompleteMultipartUpload{DaysAfterInitiation: 7},
+		},
+	},
+}
+
+// BuiltinTemplate returns the built-in template with the given name.
+func BuiltinTemplate(name string) (RuleTemplate, bool) {
+	t, ok := builtinTemplates[name]
+	return t, ok
+}
+
+// BuiltinTemplateNames returns the sorted names of the built-in templates.
+func BuiltinTemplateNames() []string {
+	names := make([]string, 0, len(builtinTemplates))
+	for name := range builtinTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FromTemplate returns a new rule made of rule, with the non nil fields of
+// opts applied on top of it.
+func (opts LifecycleOptions) FromTemplate(rule lifecycle.Rule) (lifecycle.Rule, *probe.Error) {
+	rule.ID = opts.ID
+	if rule.Status == "" {
+		rule.Status = "Enabled"
+	}
+	if err := ApplyRuleFields(&rule, opts); err != nil {
+		return lifecycle.Rule{}, err
+	}
+	transitionSet := !rule.Transition.IsDaysNull() || !rule.Transition.IsDateNull()
+	if transitionSet && rule.Transition.StorageClass == "" {
+		return lifecycle.Rule{}, probe.NewError(errors.New("the template transitions objects, transition-tier must be set"))
+	}
+	if err := validateILMRule(rule); err != nil {
+		return lifecycle.Rule{}, err
+	}
+	return rule, nil
+}
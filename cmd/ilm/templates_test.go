@@ -0,0 +1,88 @@
+// Copyright (c) 2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package ilm
+
+import (
+	"testing"
+
+	"github.com/trinet2005/oss-go-sdk/pkg/lifecycle"
+)
+
+func TestFromTemplate(t *testing.T) {
+	testCases := []struct {
+		template string
+		opts     LifecycleOptions
+		check    func(lifecycle.Rule) bool
+		success  bool
+	}{
+		{
+			template: "log-expiry-30d",
+			opts:     LifecycleOptions{ID: "logs", Prefix: strPtr("logs/")},
+			check: func(r lifecycle.Rule) bool {
+				return r.ID == "logs" && r.Status == "Enabled" && r.Expiration.Days == 30 && r.RuleFilter.Prefix == "logs/"
+			},
+			success: true,
+		},
+		{
+			template: "log-expiry-30d",
+			opts:     LifecycleOptions{ID: "logs", ExpiryDays: strPtr("7"), Status: boolPtr(false)},
+			check: func(r lifecycle.Rule) bool {
+				return r.Expiration.Days == 7 && r.Status == "Disabled"
+			},
+			success: true,
+		},
+		{
+			template: "tier-after-90d",
+			opts:     LifecycleOptions{ID: "tier"},
+			success:  false,
+		},
+		{
+			template: "tier-after-90d",
+			opts:     LifecycleOptions{ID: "tier", StorageClass: strPtr("WARM")},
+			check: func(r lifecycle.Rule) bool {
+				return r.Transition.Days == 90 && r.Transition.StorageClass == "WARM"
+			},
+			success: true,
+		},
+		{
+			template: "cleanup-incomplete",
+			opts:     LifecycleOptions{ID: "mpu", AbortIncompleteDays: intPtr(3)},
+			check: func(r lifecycle.Rule) bool {
+				return r.AbortIncompleteMultipartUpload.DaysAfterInitiation == 3
+			},
+			success: true,
+		},
+	}
+	for i, testCase := range testCases {
+		template, ok := BuiltinTemplate(testCase.template)
+		if !ok {
+			t.Fatalf("Test %d: template %s not found", i+1, testCase.template)
+		}
+		rule, err := testCase.opts.FromTemplate(template.Rule)
+		if (err == nil) != testCase.success {
+			t.Fatalf("Test %d: expected success %v, got %v", i+1, testCase.success, err)
+		}
+		if err == nil && !testCase.check(rule) {
+			t.Fatalf("Test %d: unexpected rule %+v", i+1, rule)
+		}
+	}
+	// Templates must not be modified by the rules made from them.
+	if template, _ := BuiltinTemplate("log-expiry-30d"); template.Rule.Expiration.Days != 30 || template.Rule.ID != "" {
+		t.Fatalf("built-in template was modified: %+v", template.Rule)
+	}
+}
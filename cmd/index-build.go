@@ -0,0 +1,186 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+	bolt "go.etcd.io/bbolt"
+)
+
+var indexBuildCmd = cli.Command{
+	Name:         "build",
+	Usage:        "crawl a bucket or a prefix and save the tags and metadata of its objects locally",
+	Action:       mainIndexBuild,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Build lists TARGET recursively with the tags and metadata of its objects, a MinIO server extension,
+  and saves them in the mc configuration folder, replacing any previous index of TARGET. The index is
+  not updated as objects change, run build again to refresh it.
+
+EXAMPLES:
+  1. Index the objects of a bucket.
+     {{.Prompt}} {{.HelpName}} myminio/datalake
+
+  2. Index the objects under a prefix.
+     {{.Prompt}} {{.HelpName}} myminio/datalake/2023/
+`,
+}
+
+// indexBuildMessage is printed once an index is saved.
+type indexBuildMessage struct {
+	Status  string        `json:"status"`
+	Target  string        `json:"target"`
+	Objects int64         `json:"objects"`
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+func (i indexBuildMessage) String() string {
+	return console.Colorize("IndexBuild", fmt.Sprintf("Indexed %d object(s) of `%s` in %s.",
+		i.Objects, i.Target, i.Elapsed.Round(time.Millisecond)))
+}
+
+func (i indexBuildMessage) JSON() string {
+	i.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(i, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// buildIndex lists target recursively, as a folder, and saves its index.
+// The index is written to a temporary database first so that an
+// interrupted crawl keeps the previous index.
+func buildIndex(ctx context.Context, target string) (int64, *probe.Error) {
+	clnt, err := newClient(target + "/")
+	if err != nil {
+		return 0, err.Trace(target)
+	}
+
+	indexFile, err := getIndexFile(target)
+	if err != nil {
+		return 0, err.Trace(target)
+	}
+	if e := os.MkdirAll(filepath.Dir(indexFile), 0o700); e != nil {
+		return 0, probe.NewError(e).Trace(filepath.Dir(indexFile))
+	}
+	// Start from an empty database, a previous build may have been
+	// interrupted.
+	tmpFile := indexFile + ".tmp"
+	if e := os.Remove(tmpFile); e != nil && !os.IsNotExist(e) {
+		return 0, probe.NewError(e).Trace(tmpFile)
+	}
+	db, e := bolt.Open(tmpFile, 0o600, &bolt.Options{Timeout: time.Second})
+	if e != nil {
+		return 0, probe.NewError(e).Trace(tmpFile)
+	}
+	defer os.Remove(tmpFile)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	base := filepath.ToSlash(clnt.GetURL().Path)
+	recordCh := make(chan indexRecord)
+	var listErr *probe.Error
+	go func() {
+		defer close(recordCh)
+		for content := range clnt.List(ctx, ListOptions{Recursive: true, WithMetadata: true, ShowDir: DirNone}) {
+			if content.Err != nil {
+				listErr = content.Err.Trace(target)
+				return
+			}
+			select {
+			case recordCh <- indexRecord{
+				Key:          strings.TrimPrefix(filepath.ToSlash(content.URL.Path), base),
+				Size:         content.Size,
+				LastModified: content.Time,
+				ETag:         strings.Trim(content.ETag, "\""),
+				StorageClass: content.StorageClass,
+				Tags:         content.Tags,
+				Metadata:     content.Metadata,
+			}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	n, e := writeIndex(db, indexHeader{Version: indexVersion, Target: target, Built: UTCNow()}, recordCh)
+	if e != nil {
+		cancel()
+		// Drain the listing so that the goroutine above returns.
+		for range recordCh {
+		}
+		db.Close()
+		return n, probe.NewError(e).Trace(tmpFile)
+	}
+	if listErr != nil {
+		db.Close()
+		return n, listErr
+	}
+	if e = db.Close(); e != nil {
+		return n, probe.NewError(e).Trace(tmpFile)
+	}
+	if e = os.Rename(tmpFile, indexFile); e != nil {
+		return n, probe.NewError(e).Trace(indexFile)
+	}
+	return n, nil
+}
+
+func mainIndexBuild(cliCtx *cli.Context) error {
+	ctx, cancelIndexBuild := context.WithCancel(globalContext)
+	defer cancelIndexBuild()
+
+	if len(cliCtx.Args()) != 1 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	console.SetColor("IndexBuild", color.New(color.FgGreen, color.Bold))
+
+	target := normalizeIndexTarget(cliCtx.Args().Get(0))
+	start := time.Now()
+	n, err := buildIndex(ctx, target)
+	fatalIf(err, "Unable to build the index of `"+target+"`.")
+
+	printMsg(indexBuildMessage{
+		Target:  target,
+		Objects: n,
+		Elapsed: time.Since(start),
+	})
+	return nil
+}
@@ -0,0 +1,316 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	gojson "encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	bolt "go.etcd.io/bbolt"
+)
+
+var indexSubcommands = []cli.Command{
+	indexBuildCmd,
+	indexQueryCmd,
+}
+
+var indexCmd = cli.Command{
+	Name:            "index",
+	Usage:           "build and query a local index of the tags and metadata of objects",
+	Action:          mainIndex,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	HideHelpCommand: true,
+	Subcommands:     indexSubcommands,
+}
+
+// mainIndex is the handle for "mc index" command.
+func mainIndex(ctx *cli.Context) error {
+	commandNotFound(ctx, indexSubcommands)
+	return nil
+	// Sub-commands like "build" and "query" have their own main.
+}
+
+// indexVersion is the version of the format of index databases.
+const indexVersion = "2"
+
+// An index is a bolt database of the mc configuration folder. Objects are
+// stored by key and indexed by tag and by metadata, so that tag and
+// metadata predicates only read the objects carrying the tag or the
+// metadata they test.
+var (
+	indexInfoBucket     = []byte("info")     // holds the header
+	indexObjectsBucket  = []byte("objects")  // key -> record
+	indexTagsBucket     = []byte("tags")     // tag, value, key -> nothing
+	indexMetadataBucket = []byte("metadata") // metadata, value, key -> nothing
+	indexHeaderKey      = []byte("header")
+)
+
+// indexSep separates the fields of the keys of the tags and metadata
+// buckets, it cannot appear in object names, tags or metadata.
+const indexSep = "\x00"
+
+// indexBatchSize is the number of objects written per transaction.
+const indexBatchSize = 10000
+
+// indexHeader describes an index.
+type indexHeader struct {
+	Version string    `json:"version"`
+	Target  string    `json:"target"`
+	Built   time.Time `json:"built"`
+}
+
+// indexRecord is an object of an index.
+type indexRecord struct {
+	Key          string            `json:"key"` // relative to the target
+	Size         int64             `json:"size"`
+	LastModified time.Time         `json:"lastModified"`
+	ETag         string            `json:"etag,omitempty"`
+	StorageClass string            `json:"storageClass,omitempty"`
+	Tags         map[string]string `json:"tags,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+}
+
+// getIndexFile returns the database of the index of target, named after
+// a hash of the target so that any alias and prefix map to a valid name.
+func getIndexFile(target string) (string, *probe.Error) {
+	configDir, err := getMcConfigDir()
+	if err != nil {
+		return "", err.Trace()
+	}
+	sum := sha256.Sum256([]byte(target))
+	return filepath.Join(configDir, globalIndexDir, hex.EncodeToString(sum[:16])+".db"), nil
+}
+
+// normalizeIndexTarget returns the form of target the index is keyed by,
+// a target being always indexed as a folder.
+func normalizeIndexTarget(target string) string {
+	return strings.TrimRight(filepath.ToSlash(target), "/")
+}
+
+// indexEntry returns the key of an entry of the tags or metadata bucket.
+func indexEntry(name, value, key string) []byte {
+	return []byte(name + indexSep + value + indexSep + key)
+}
+
+// writeIndex writes an index with the records received from recordCh
+// to db and returns the number of objects written.
+func writeIndex(db *bolt.DB, header indexHeader, recordCh <-chan indexRecord) (int64, error) {
+	e := db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{indexObjectsBucket, indexTagsBucket, indexMetadataBucket} {
+			if _, e := tx.CreateBucketIfNotExists(name); e != nil {
+				return e
+			}
+		}
+		info, e := tx.CreateBucketIfNotExists(indexInfoBucket)
+		if e != nil {
+			return e
+		}
+		data, e := gojson.Marshal(header)
+		if e != nil {
+			return e
+		}
+		return info.Put(indexHeaderKey, data)
+	})
+	if e != nil {
+		return 0, e
+	}
+
+	var n int64
+	batch := make([]indexRecord, 0, indexBatchSize)
+	flush := func() error {
+		e := db.Update(func(tx *bolt.Tx) error {
+			objects := tx.Bucket(indexObjectsBucket)
+			tags := tx.Bucket(indexTagsBucket)
+			metadata := tx.Bucket(indexMetadataBucket)
+			for _, record := range batch {
+				data, e := gojson.Marshal(record)
+				if e != nil {
+					return e
+				}
+				if e = objects.Put([]byte(record.Key), data); e != nil {
+					return e
+				}
+				for name, value := range record.Tags {
+					if e = tags.Put(indexEntry(name, value, record.Key), nil); e != nil {
+						return e
+					}
+				}
+				for name, value := range record.Metadata {
+					if e = metadata.Put(indexEntry(name, value, record.Key), nil); e != nil {
+						return e
+					}
+				}
+			}
+			return nil
+		})
+		if e != nil {
+			return e
+		}
+		n += int64(len(batch))
+		batch = batch[:0]
+		return nil
+	}
+	for record := range recordCh {
+		batch = append(batch, record)
+		if len(batch) == indexBatchSize {
+			if e = flush(); e != nil {
+				return n, e
+			}
+		}
+	}
+	return n, flush()
+}
+
+// readIndexHeader returns the header of the index in db.
+func readIndexHeader(db *bolt.DB) (indexHeader, error) {
+	var header indexHeader
+	e := db.View(func(tx *bolt.Tx) error {
+		info := tx.Bucket(indexInfoBucket)
+		if info == nil {
+			return errors.New("not an index")
+		}
+		return gojson.Unmarshal(info.Get(indexHeaderKey), &header)
+	})
+	if e == nil && header.Version != indexVersion {
+		e = fmt.Errorf("unsupported index version %s", header.Version)
+	}
+	return header, e
+}
+
+// lookupIndex returns the keys of the objects whose tag or metadata name,
+// as indexed by bucket, matches re. Only the entries of name are read,
+// a single value if re is an exact match.
+func lookupIndex(bucket *bolt.Bucket, name string, re *regexp.Regexp) map[string]bool {
+	keys := make(map[string]bool)
+	prefix := []byte(name + indexSep)
+	if literal, complete := re.LiteralPrefix(); complete && re.String() == "^"+regexp.QuoteMeta(literal)+"$" {
+		prefix = []byte(name + indexSep + literal + indexSep)
+	}
+	c := bucket.Cursor()
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		fields := strings.SplitN(string(k[len(name)+len(indexSep):]), indexSep, 2)
+		if len(fields) == 2 && re.MatchString(fields[0]) {
+			keys[fields[1]] = true
+		}
+	}
+	return keys
+}
+
+// candidates returns the keys of the objects that may match q according
+// to the tags and metadata buckets, or nil if q must be tested against
+// every object.
+func (q indexQuery) candidates(tx *bolt.Tx) map[string]bool {
+	var keys map[string]bool
+	intersect := func(bucket *bolt.Bucket, matches map[string]*regexp.Regexp) {
+		for name, re := range matches {
+			// A missing or empty value matches a nil regex, those
+			// objects are not indexed.
+			if re == nil {
+				continue
+			}
+			found := lookupIndex(bucket, name, re)
+			if keys != nil {
+				for key := range keys {
+					if !found[key] {
+						delete(keys, key)
+					}
+				}
+				continue
+			}
+			keys = found
+		}
+	}
+	intersect(tx.Bucket(indexTagsBucket), q.matchTags)
+	intersect(tx.Bucket(indexMetadataBucket), q.matchMeta)
+	return keys
+}
+
+// queryIndex calls fn, in the order of their keys, for every record of
+// the index in db matching q.
+func queryIndex(db *bolt.DB, q indexQuery, fn func(indexRecord)) error {
+	return db.View(func(tx *bolt.Tx) error {
+		objects := tx.Bucket(indexObjectsBucket)
+		if objects == nil {
+			return errors.New("not an index")
+		}
+		visit := func(data []byte) error {
+			var record indexRecord
+			if e := gojson.Unmarshal(data, &record); e != nil {
+				return e
+			}
+			if q.match(record) {
+				fn(record)
+			}
+			return nil
+		}
+
+		candidates := q.candidates(tx)
+		if candidates == nil {
+			return objects.ForEach(func(_, data []byte) error {
+				return visit(data)
+			})
+		}
+		keys := make([]string, 0, len(candidates))
+		for key := range candidates {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if data := objects.Get([]byte(key)); data != nil {
+				if e := visit(data); e != nil {
+					return e
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// openIndex opens the index of target, read-only.
+func openIndex(target string) (*bolt.DB, *probe.Error) {
+	indexFile, err := getIndexFile(target)
+	if err != nil {
+		return nil, err
+	}
+	// Opening a missing database creates it, check that it exists first.
+	if _, e := os.Stat(indexFile); e != nil {
+		return nil, probe.NewError(e).Trace(indexFile)
+	}
+	db, e := bolt.Open(indexFile, 0o600, &bolt.Options{ReadOnly: true, Timeout: time.Second})
+	if e != nil {
+		return nil, probe.NewError(e).Trace(indexFile)
+	}
+	if _, e = readIndexHeader(db); e != nil {
+		db.Close()
+		return nil, probe.NewError(e).Trace(indexFile)
+	}
+	return db, nil
+}
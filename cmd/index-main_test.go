@@ -0,0 +1,99 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestIndexQuery(t *testing.T) {
+	modTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := []indexRecord{
+		{Key: "2023/a.parquet", Size: 2 << 30, LastModified: modTime, Tags: map[string]string{"env": "prod"}, Metadata: map[string]string{"X-Amz-Meta-App": "ingest"}},
+		{Key: "2023/b.parquet", Size: 10, LastModified: modTime, Tags: map[string]string{"env": "dev", "team": "data"}},
+		{Key: "2023/c.csv", Size: 2 << 30, LastModified: modTime, Tags: map[string]string{"env": "prod", "team": "data"}},
+		{Key: "2023/d.csv", Size: 10, LastModified: modTime, Tags: map[string]string{"env": "production"}, Metadata: map[string]string{"X-Amz-Meta-App": "export"}},
+	}
+
+	db, e := bolt.Open(filepath.Join(t.TempDir(), "index.db"), 0o600, nil)
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer db.Close()
+
+	recordCh := make(chan indexRecord, len(records))
+	for _, record := range records {
+		recordCh <- record
+	}
+	close(recordCh)
+	n, e := writeIndex(db, indexHeader{Version: indexVersion, Target: "myminio/datalake", Built: modTime}, recordCh)
+	if e != nil || n != int64(len(records)) {
+		t.Fatalf("expected %d records written, got %d: %v", len(records), n, e)
+	}
+	header, e := readIndexHeader(db)
+	if e != nil || header.Target != "myminio/datalake" {
+		t.Fatalf("expected the header of myminio/datalake, got %+v: %v", header, e)
+	}
+
+	var read []indexRecord
+	if e = queryIndex(db, indexQuery{}, func(record indexRecord) {
+		read = append(read, record)
+	}); e != nil {
+		t.Fatal(e)
+	}
+	if !reflect.DeepEqual(read, records) {
+		t.Fatalf("expected %v, got %v", records, read)
+	}
+
+	where, err := parseFindWhere(`size > 1GiB && metadata.app == "ingest"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		query    indexQuery
+		expected []string
+	}{
+		{indexQuery{}, []string{"2023/a.parquet", "2023/b.parquet", "2023/c.csv", "2023/d.csv"}},
+		{indexQuery{namePattern: "*.parquet"}, []string{"2023/a.parquet", "2023/b.parquet"}},
+		{indexQuery{matchTags: map[string]*regexp.Regexp{"env": regexp.MustCompile("^prod$")}}, []string{"2023/a.parquet", "2023/c.csv"}},
+		{indexQuery{matchTags: map[string]*regexp.Regexp{"env": regexp.MustCompile("^prod")}}, []string{"2023/a.parquet", "2023/c.csv", "2023/d.csv"}},
+		{indexQuery{matchTags: map[string]*regexp.Regexp{"env": regexp.MustCompile("^prod"), "team": regexp.MustCompile("data")}}, []string{"2023/c.csv"}},
+		{indexQuery{matchTags: map[string]*regexp.Regexp{"team": nil}}, []string{"2023/a.parquet", "2023/d.csv"}},
+		{indexQuery{matchTags: map[string]*regexp.Regexp{"owner": regexp.MustCompile(".*")}}, nil},
+		{indexQuery{matchMeta: map[string]*regexp.Regexp{"X-Amz-Meta-App": regexp.MustCompile("^ingest$")}}, []string{"2023/a.parquet"}},
+		{indexQuery{namePattern: "*.csv", matchTags: map[string]*regexp.Regexp{"env": regexp.MustCompile("^prod")}}, []string{"2023/c.csv", "2023/d.csv"}},
+		{indexQuery{where: where}, []string{"2023/a.parquet"}},
+	}
+	for i, testCase := range testCases {
+		var got []string
+		if e = queryIndex(db, testCase.query, func(record indexRecord) {
+			got = append(got, record.Key)
+		}); e != nil {
+			t.Fatalf("Test %d: expected no error, got %v", i+1, e)
+		}
+		if !reflect.DeepEqual(got, testCase.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, got)
+		}
+	}
+}
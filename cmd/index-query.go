@@ -0,0 +1,180 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+var indexQueryFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "name",
+		Usage: "match object names matching wildcard pattern",
+	},
+	cli.StringSliceFlag{
+		Name:  "tags",
+		Usage: "match tags with RE2 regex pattern. Specify each with key=regex",
+	},
+	cli.StringSliceFlag{
+		Name:  "metadata",
+		Usage: "match metadata with RE2 regex pattern. Specify each with key=regex",
+	},
+	cli.StringFlag{
+		Name:  "where",
+		Usage: "match objects with an expression of their fields, as in 'mc find --where'",
+	},
+}
+
+var indexQueryCmd = cli.Command{
+	Name:         "query",
+	Usage:        "list the objects of an index matching tag and metadata predicates",
+	Action:       mainIndexQuery,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(indexQueryFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Query reads the index saved by 'mc index build TARGET' instead of listing TARGET, so the objects
+  are as they were when the index was built. The predicates are those of 'mc find' and all of them
+  must match. Objects are indexed by tag and metadata, --tags and --metadata only read the objects
+  carrying the tags and metadata they test, while --name and --where read every object.
+
+EXAMPLES:
+  1. List the objects of a bucket tagged as production data.
+     {{.Prompt}} {{.HelpName}} --tags "env=^prod$" myminio/datalake
+
+  2. List the parquet files larger than 1GiB uploaded by a given application, as JSON.
+     {{.Prompt}} {{.HelpName}} --json --name "*.parquet" --where 'size > 1GiB && metadata.app == "ingest"' myminio/datalake
+`,
+}
+
+// indexQueryMessage is an object of an index matching a query.
+type indexQueryMessage struct {
+	Status       string            `json:"status"`
+	Key          string            `json:"key"`
+	Size         int64             `json:"size"`
+	LastModified time.Time         `json:"lastModified"`
+	ETag         string            `json:"etag,omitempty"`
+	StorageClass string            `json:"storageClass,omitempty"`
+	Tags         map[string]string `json:"tags,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+}
+
+func (i indexQueryMessage) String() string {
+	return console.Colorize("IndexQuery", i.Key)
+}
+
+func (i indexQueryMessage) JSON() string {
+	i.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(i, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// indexQuery holds the predicates of a query, all of them must match.
+type indexQuery struct {
+	namePattern string
+	matchTags   map[string]*regexp.Regexp
+	matchMeta   map[string]*regexp.Regexp
+	where       *findWhere
+}
+
+// match returns true if record satisfies the query.
+func (q indexQuery) match(record indexRecord) bool {
+	if q.namePattern != "" && !nameMatch(q.namePattern, record.Key) {
+		return false
+	}
+	if len(q.matchTags) > 0 && !matchRegexMaps(q.matchTags, record.Tags) {
+		return false
+	}
+	if len(q.matchMeta) > 0 && !matchRegexMaps(q.matchMeta, record.Metadata) {
+		return false
+	}
+	if q.where != nil {
+		return q.where.match(&findRecord{
+			path:         record.Key,
+			size:         record.Size,
+			modTime:      record.LastModified,
+			etag:         record.ETag,
+			storageClass: record.StorageClass,
+			tags:         record.Tags,
+			metadata:     record.Metadata,
+		})
+	}
+	return true
+}
+
+func mainIndexQuery(cliCtx *cli.Context) error {
+	if len(cliCtx.Args()) != 1 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	console.SetColor("IndexQuery", color.New(color.Bold))
+
+	query := indexQuery{
+		namePattern: cliCtx.String("name"),
+		matchTags:   getRegexMap(cliCtx, "tags"),
+		matchMeta:   getRegexMap(cliCtx, "metadata"),
+	}
+	if text := cliCtx.String("where"); text != "" {
+		where, err := parseFindWhere(text)
+		fatalIf(err, "Unable to parse --where.")
+		query.where = where
+	}
+
+	target := normalizeIndexTarget(cliCtx.Args().Get(0))
+	db, err := openIndex(target)
+	if err != nil && os.IsNotExist(err.ToGoError()) {
+		fatalIf(err, "No index of `"+target+"`, run `mc index build "+target+"` first.")
+	}
+	fatalIf(err, "Unable to open the index of `"+target+"`.")
+	defer db.Close()
+
+	e := queryIndex(db, query, func(record indexRecord) {
+		if !query.match(record) {
+			return
+		}
+		printMsg(indexQueryMessage{
+			Key:          target + "/" + record.Key,
+			Size:         record.Size,
+			LastModified: record.LastModified,
+			ETag:         record.ETag,
+			StorageClass: record.StorageClass,
+			Tags:         record.Tags,
+			Metadata:     record.Metadata,
+		})
+	})
+	fatalIf(probe.NewError(e), "Unable to read the index of `"+target+"`.")
+	return nil
+}
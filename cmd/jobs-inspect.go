@@ -0,0 +1,146 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+var jobsInspectCmd = cli.Command{
+	Name:         "inspect",
+	Usage:        "show the state and progress of a job of the daemon",
+	Action:       mainJobsInspect,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(jobsFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] JOBID
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Show a job of the daemon listening on the default address:
+     {{.Prompt}} {{.HelpName}} 5f6c1b6e-95e4-4b8c-9e0a-2f1b6f0d7c3a
+`,
+}
+
+// jobsInspectMessage container for jobs inspect messages
+type jobsInspectMessage struct {
+	Status string          `json:"status"`
+	Job    daemonJobStatus `json:"job"`
+}
+
+// String colorized jobs inspect message
+func (j jobsInspectMessage) String() string {
+	var s strings.Builder
+	timeOf := func(t *time.Time) string {
+		if t == nil {
+			return "-"
+		}
+		return t.Local().Format(printDate) + " (" + humanize.Time(*t) + ")"
+	}
+	job := j.Job
+	fmt.Fprintf(&s, "ID:          %s\n", console.Colorize("JobID", job.ID))
+	fmt.Fprintf(&s, "Command:     mc %s %s\n", job.Command, strings.Join(job.Args, " "))
+	fmt.Fprintf(&s, "State:       %s\n", console.Colorize("JobState", job.State))
+	fmt.Fprintf(&s, "Priority:    %d\n", job.Priority)
+	fmt.Fprintf(&s, "Attempts:    %d/%d\n", job.Attempts, job.Retries+1)
+	if job.Backoff != "" {
+		fmt.Fprintf(&s, "Backoff:     %s\n", job.Backoff)
+	}
+	fmt.Fprintf(&s, "Submitted:   %s\n", timeOf(&job.Submitted))
+	fmt.Fprintf(&s, "Started:     %s\n", timeOf(job.Started))
+	fmt.Fprintf(&s, "Finished:    %s\n", timeOf(job.Finished))
+	if job.NextAttempt != nil {
+		fmt.Fprintf(&s, "Next try:    %s\n", timeOf(job.NextAttempt))
+	}
+	if job.ExitCode != nil {
+		fmt.Fprintf(&s, "Exit code:   %d\n", *job.ExitCode)
+	}
+	objects := fmt.Sprintf("%d", job.Progress.Objects)
+	if job.Progress.TotalObjects > 0 {
+		objects += fmt.Sprintf("/%d", job.Progress.TotalObjects)
+	}
+	size := humanize.IBytes(uint64(job.Progress.Bytes))
+	if job.Progress.TotalBytes > 0 {
+		size += "/" + humanize.IBytes(uint64(job.Progress.TotalBytes))
+	}
+	fmt.Fprintf(&s, "Objects:     %s\n", objects)
+	fmt.Fprintf(&s, "Size:        %s\n", size)
+	fmt.Fprintf(&s, "Errors:      %d\n", job.Progress.Errors)
+	if job.LastError != "" {
+		fmt.Fprintf(&s, "Last error:  %s\n", console.Colorize("JobError", job.LastError))
+	}
+	return strings.TrimSuffix(s.String(), "\n")
+}
+
+// JSON jsonified jobs inspect message
+func (j jobsInspectMessage) JSON() string {
+	j.Status = "success"
+	jobsInspectMessageBytes, e := json.MarshalIndent(j, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jobsInspectMessageBytes)
+}
+
+// checkJobsInspectSyntax - validate all the passed arguments
+func checkJobsInspectSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+}
+
+// mainJobsInspect is the handle for "mc jobs inspect" command.
+func mainJobsInspect(ctx *cli.Context) error {
+	checkJobsInspectSyntax(ctx)
+
+	// Additional command specific theme customization.
+	console.SetColor("JobID", color.New(color.Bold))
+	console.SetColor("JobState", color.New(color.FgCyan, color.Bold))
+	console.SetColor("JobError", color.New(color.FgRed))
+
+	jobID := ctx.Args().Get(0)
+
+	ctxt, cancel := context.WithCancel(globalContext)
+	defer cancel()
+
+	var job daemonJobStatus
+	err := newDaemonClient(ctx).do(ctxt, "GET", "/v1/jobs/"+url.PathEscape(jobID), &job)
+	fatalIf(err.Trace(jobID), "Unable to inspect job `"+jobID+"`.")
+
+	printMsg(jobsInspectMessage{
+		Status: "success",
+		Job:    job,
+	})
+	return nil
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/olekukonko/tablewriter"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+var jobsListFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "state",
+		Usage: "only list the jobs in this state: queued, running, succeeded, failed or canceled",
+	},
+}
+
+var jobsListCmd = cli.Command{
+	Name:         "list",
+	ShortName:    "ls",
+	Usage:        "list the jobs of the daemon",
+	Action:       mainJobsList,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(append(jobsListFlags, jobsFlags...), globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. List all the jobs of the daemon listening on the default address:
+     {{.Prompt}} {{.HelpName}}
+
+  2. List the failed jobs of the daemon listening on a unix socket:
+     {{.Prompt}} {{.HelpName}} --address unix:/run/mc.sock --state failed
+`,
+}
+
+// jobsListMessage container for jobs list messages
+type jobsListMessage struct {
+	Status string            `json:"status"`
+	Jobs   []daemonJobStatus `json:"jobs"`
+}
+
+// String colorized jobs list message
+func (j jobsListMessage) String() string {
+	if len(j.Jobs) == 0 {
+		return "no jobs found"
+	}
+
+	var s strings.Builder
+
+	// Set table header
+	table := tablewriter.NewWriter(&s)
+	table.SetAutoWrapText(false)
+	table.SetAutoFormatHeaders(true)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+	table.SetTablePadding("\t") // pad with tabs
+	table.SetNoWhiteSpace(true)
+
+	table.SetHeader([]string{"ID", "COMMAND", "STATE", "PRIORITY", "ATTEMPTS", "OBJECTS", "SIZE", "SUBMITTED"})
+	data := make([][]string, 0, len(j.Jobs))
+
+	for _, job := range j.Jobs {
+		data = append(data, []string{
+			job.ID,
+			job.Command,
+			job.State,
+			strconv.Itoa(job.Priority),
+			strconv.Itoa(job.Attempts) + "/" + strconv.Itoa(job.Retries+1),
+			strconv.FormatInt(job.Progress.Objects, 10),
+			humanize.IBytes(uint64(job.Progress.Bytes)),
+			humanize.Time(job.Submitted),
+		})
+	}
+
+	table.AppendBulk(data)
+	table.Render()
+
+	return s.String()
+}
+
+// JSON jsonified jobs list message
+func (j jobsListMessage) JSON() string {
+	j.Status = "success"
+	jobsListMessageBytes, e := json.MarshalIndent(j, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jobsListMessageBytes)
+}
+
+// checkJobsListSyntax - validate all the passed arguments
+func checkJobsListSyntax(ctx *cli.Context) {
+	if ctx.Args().Present() {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	switch ctx.String("state") {
+	case "", jobQueued, jobRunning, jobSucceeded, jobFailed, jobCanceled:
+	default:
+		fatalIf(errInvalidArgument().Trace(ctx.String("state")), "Invalid job state `"+ctx.String("state")+"`.")
+	}
+}
+
+// mainJobsList is the handle for "mc jobs ls" command.
+func mainJobsList(ctx *cli.Context) error {
+	checkJobsListSyntax(ctx)
+
+	ctxt, cancel := context.WithCancel(globalContext)
+	defer cancel()
+
+	var jobs []daemonJobStatus
+	err := newDaemonClient(ctx).do(ctxt, "GET", "/v1/jobs", &jobs)
+	fatalIf(err, "Unable to list jobs.")
+
+	if state := ctx.String("state"); state != "" {
+		filtered := jobs[:0]
+		for _, job := range jobs {
+			if job.State == state {
+				filtered = append(filtered, job)
+			}
+		}
+		jobs = filtered
+	}
+
+	printMsg(jobsListMessage{
+		Status: "success",
+		Jobs:   jobs,
+	})
+	return nil
+}
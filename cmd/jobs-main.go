@@ -0,0 +1,141 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+var jobsFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "address",
+		Value: "127.0.0.1:9191",
+		Usage: "address of the daemon, HOST:PORT or 'unix:PATH'",
+	},
+	cli.StringFlag{
+		Name:   "token",
+		Usage:  "token required by the daemon, read from the configuration folder if not set",
+		EnvVar: "MC_DAEMON_TOKEN",
+	},
+}
+
+var jobsSubcommands = []cli.Command{
+	jobsListCmd,
+	jobsInspectCmd,
+	jobsRetryCmd,
+}
+
+var jobsCmd = cli.Command{
+	Name:            "jobs",
+	Usage:           "manage the transfer jobs of 'mc daemon'",
+	Action:          mainJobs,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	Subcommands:     jobsSubcommands,
+	HideHelpCommand: true,
+}
+
+// mainJobs is the handle for "mc jobs" command.
+func mainJobs(ctx *cli.Context) error {
+	commandNotFound(ctx, jobsSubcommands)
+	return nil
+	// Sub-commands like "ls", "inspect" have their own main.
+}
+
+// daemonClient calls the job API of a running 'mc daemon'.
+type daemonClient struct {
+	endpoint string
+	token    string
+	client   *http.Client
+}
+
+// newDaemonClient returns a client of the daemon set by the
+// --address and --token flags, using the token generated by a
+// daemon listening on HOST:PORT if no token is set.
+func newDaemonClient(cliCtx *cli.Context) *daemonClient {
+	address := cliCtx.String("address")
+	token := cliCtx.String("token")
+	transport := &http.Transport{}
+	endpoint := "http://" + address
+	if token == "" && !isDaemonUnixAddress(address) {
+		if buf, e := os.ReadFile(filepath.Join(mustGetMcConfigDir(), daemonTokenFile)); e == nil {
+			token = strings.TrimSpace(string(buf))
+		}
+	}
+	if path := strings.TrimPrefix(address, "unix:"); path != address {
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", path)
+		}
+		endpoint = "http://daemon"
+	}
+	return &daemonClient{
+		endpoint: endpoint,
+		token:    token,
+		client:   &http.Client{Transport: transport, Timeout: 30 * time.Second},
+	}
+}
+
+// do sends a request to path of the job API and decodes the
+// response into v, if not nil.
+func (c *daemonClient) do(ctx context.Context, method, path string, v interface{}) *probe.Error {
+	req, e := http.NewRequestWithContext(ctx, method, c.endpoint+path, nil)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if method == http.MethodPost {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, e := c.client.Do(req)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	defer resp.Body.Close()
+	body, e := io.ReadAll(io.LimitReader(resp.Body, 16<<20))
+	if e != nil {
+		return probe.NewError(e)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(body, &errResp) != nil || errResp.Error == "" {
+			errResp.Error = resp.Status
+		}
+		return probe.NewError(errors.New(errResp.Error))
+	}
+	if v == nil {
+		return nil
+	}
+	return probe.NewError(json.Unmarshal(body, v))
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+var jobsRetryCmd = cli.Command{
+	Name:         "retry",
+	Usage:        "queue a failed or canceled job of the daemon again",
+	Action:       mainJobsRetry,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(jobsFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] JOBID [JOBID...]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Queue a failed job again on the daemon listening on the default address:
+     {{.Prompt}} {{.HelpName}} 5f6c1b6e-95e4-4b8c-9e0a-2f1b6f0d7c3a
+
+  2. Queue all the failed jobs again.
+     {{.Prompt}} {{.HelpName}} $(mc jobs ls --state failed --json | jq -r '.jobs[].id')
+`,
+}
+
+// jobsRetryMessage container for jobs retry messages
+type jobsRetryMessage struct {
+	Status string `json:"status"`
+	ID     string `json:"id"`
+}
+
+// String colorized jobs retry message
+func (j jobsRetryMessage) String() string {
+	return console.Colorize("JobRetry", "Job `"+j.ID+"` is queued again.")
+}
+
+// JSON jsonified jobs retry message
+func (j jobsRetryMessage) JSON() string {
+	j.Status = "success"
+	jobsRetryMessageBytes, e := json.MarshalIndent(j, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jobsRetryMessageBytes)
+}
+
+// checkJobsRetrySyntax - validate all the passed arguments
+func checkJobsRetrySyntax(ctx *cli.Context) {
+	if !ctx.Args().Present() {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+}
+
+// mainJobsRetry is the handle for "mc jobs retry" command.
+func mainJobsRetry(ctx *cli.Context) error {
+	checkJobsRetrySyntax(ctx)
+
+	// Additional command specific theme customization.
+	console.SetColor("JobRetry", color.New(color.FgGreen, color.Bold))
+
+	ctxt, cancel := context.WithCancel(globalContext)
+	defer cancel()
+
+	client := newDaemonClient(ctx)
+	var cErr error
+	for _, jobID := range ctx.Args() {
+		err := client.do(ctxt, "POST", "/v1/jobs/"+url.PathEscape(jobID)+"/retry", nil)
+		if err != nil {
+			errorIf(err.Trace(jobID), "Unable to retry job `"+jobID+"`.")
+			cErr = exitStatus(globalErrorExitStatus)
+			continue
+		}
+		printMsg(jobsRetryMessage{ID: jobID})
+	}
+	return cErr
+}
@@ -52,7 +52,7 @@ var legalHoldClearCmd = cli.Command{
 	Action:       mainLegalHoldClear,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        append(lhClearFlags, globalFlags...),
+	Flags:        append(append(lhClearFlags, legalHoldFilterFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -75,6 +75,9 @@ EXAMPLES:
 
    4. Disable object legal hold recursively for all objects versions older than one year
       $ {{.HelpName}} myminio/mybucket/prefix --recursive --rewind 365d --versions
+
+   5. Disable object legal hold on the objects of a settled case, keeping the holds of other cases
+      $ {{.HelpName}} myminio/mybucket --recursive --versions --tags "case=2024-117"
 `,
 }
 
@@ -85,6 +88,7 @@ func mainLegalHoldClear(cliCtx *cli.Context) error {
 	console.SetColor("LegalHoldMessageFailure", color.New(color.FgYellow))
 
 	targetURL, versionID, timeRef, recursive, withVersions := parseLegalHoldArgs(cliCtx)
+	filter := parseLegalHoldFilter(cliCtx, recursive, withVersions)
 	if timeRef.IsZero() && withVersions {
 		timeRef = time.Now().UTC()
 	}
@@ -100,5 +104,5 @@ func mainLegalHoldClear(cliCtx *cli.Context) error {
 		fatalIf(errDummy().Trace(), "Bucket locking needs to be enabled in order to use this feature.")
 	}
 
-	return setLegalHold(ctx, targetURL, versionID, timeRef, withVersions, recursive, minio.LegalHoldDisabled)
+	return setLegalHold(ctx, targetURL, versionID, timeRef, withVersions, recursive, minio.LegalHoldDisabled, filter)
 }
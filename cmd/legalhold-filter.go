@@ -0,0 +1,102 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/minio/cli"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+// legalHoldFilterFlags select the objects of a prefix whose legal hold is set or cleared.
+var legalHoldFilterFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "older-than",
+		Usage: "only object(s) older than value in duration string (e.g. 7d10h31s), with --recursive or --versions",
+	},
+	cli.StringFlag{
+		Name:  "newer-than",
+		Usage: "only object(s) newer than value in duration string (e.g. 7d10h31s), with --recursive or --versions",
+	},
+	cli.StringSliceFlag{
+		Name:  "tags",
+		Usage: "only object(s) whose tags match, each specified as key=value or key!=value, with --recursive or --versions",
+	},
+}
+
+// legalHoldFilter selects objects by age and tags.
+type legalHoldFilter struct {
+	olderThan string
+	newerThan string
+	tags      []rmTagFilter
+}
+
+// parseLegalHoldFilter parses and validates the filter flags.
+func parseLegalHoldFilter(cliCtx *cli.Context, recursive, withVersions bool) legalHoldFilter {
+	filter := legalHoldFilter{
+		olderThan: cliCtx.String("older-than"),
+		newerThan: cliCtx.String("newer-than"),
+	}
+	for _, d := range []string{filter.olderThan, filter.newerThan} {
+		if d == "" {
+			continue
+		}
+		if _, e := ParseDuration(d); e != nil {
+			fatalIf(probe.NewError(e).Trace(d), "Unable to parse duration `"+d+"`.")
+		}
+	}
+	var err *probe.Error
+	filter.tags, err = parseRmTagFilters(cliCtx.StringSlice("tags"))
+	fatalIf(err, "Unable to parse --tags.")
+
+	if filter.isSet() && !recursive && !withVersions {
+		fatalIf(errInvalidArgument(), "--older-than, --newer-than and --tags require --recursive or --versions.")
+	}
+	return filter
+}
+
+// isSet returns true if any filter is set.
+func (f legalHoldFilter) isSet() bool {
+	return f.olderThan != "" || f.newerThan != "" || len(f.tags) > 0
+}
+
+// match returns true if content is within --older-than and
+// --newer-than and its tags match --tags.
+func (f legalHoldFilter) match(ctx context.Context, alias string, content *ClientContent) (bool, *probe.Error) {
+	// Skip objects older than --older-than parameter, if specified
+	if f.olderThan != "" && isOlder(content.Time, f.olderThan) {
+		return false, nil
+	}
+	// Skip objects newer than --newer-than parameter if specified
+	if f.newerThan != "" && isNewer(content.Time, f.newerThan) {
+		return false, nil
+	}
+	if len(f.tags) > 0 {
+		// Delete markers carry no tags, they are never selected.
+		if content.IsDeleteMarker {
+			return false, nil
+		}
+		tags, err := getContentTags(ctx, alias, content)
+		if err != nil {
+			return false, err
+		}
+		return matchRmTagFilters(f.tags, tags), nil
+	}
+	return true, nil
+}
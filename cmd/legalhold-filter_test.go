@@ -0,0 +1,56 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLegalHoldFilterMatch(t *testing.T) {
+	day := 24 * time.Hour
+	window := legalHoldFilter{olderThan: "30d", newerThan: "90d"}
+	tagged := legalHoldFilter{tags: []rmTagFilter{{key: "case", value: "2024-117"}}}
+
+	testCases := []struct {
+		filter   legalHoldFilter
+		content  ClientContent
+		expected bool
+	}{
+		{window, ClientContent{Time: time.Now().Add(-10 * day)}, false},
+		{window, ClientContent{Time: time.Now().Add(-60 * day)}, true},
+		{window, ClientContent{Time: time.Now().Add(-100 * day)}, false},
+		{tagged, ClientContent{Time: time.Now(), Tags: map[string]string{"case": "2024-117"}}, true},
+		{tagged, ClientContent{Time: time.Now(), Tags: map[string]string{"case": "2023-5"}}, false},
+		{tagged, ClientContent{Time: time.Now(), Tags: map[string]string{}}, false},
+		{tagged, ClientContent{Time: time.Now(), IsDeleteMarker: true}, false},
+		{legalHoldFilter{}, ClientContent{Time: time.Now()}, true},
+	}
+
+	for i, testCase := range testCases {
+		content := testCase.content
+		selected, err := testCase.filter.match(context.Background(), "myminio", &content)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		if selected != testCase.expected {
+			t.Fatalf("Test %d: expected %t, got %t", i+1, testCase.expected, selected)
+		}
+	}
+}
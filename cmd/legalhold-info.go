@@ -49,6 +49,18 @@ var lhInfoFlags = []cli.Flag{
 		Name:  "versions",
 		Usage: "show legal hold status of multiple versions of object(s)",
 	},
+	cli.BoolFlag{
+		Name:  "report",
+		Usage: "report the number and size of object(s) per legal hold status, requires --recursive",
+	},
+	cli.StringFlag{
+		Name:  "export",
+		Usage: "write the legal hold status of every object to a file, requires --recursive",
+	},
+	cli.StringFlag{
+		Name:  "format",
+		Usage: "format of the exported file, 'csv' or 'jsonl', guessed from its extension by default",
+	},
 }
 
 var legalHoldInfoCmd = cli.Command{
@@ -80,6 +92,12 @@ EXAMPLES:
 
    4. Show object legal hold recursively for all objects versions older than one year
       $ {{.HelpName}} myminio/mybucket/prefix --recursive --rewind 365d --versions
+
+   5. Report how many objects and bytes are under legal hold at a prefix
+      $ {{.HelpName}} myminio/mybucket/prefix --recursive --report
+
+   6. Report the legal hold of all object versions and export the list to a CSV file
+      $ {{.HelpName}} myminio/mybucket --recursive --versions --report --export legalhold.csv
 `,
 }
 
@@ -230,6 +248,13 @@ func mainLegalHoldInfo(cliCtx *cli.Context) error {
 	console.SetColor("LegalHoldMessageFailure", color.New(color.FgYellow))
 
 	targetURL, versionID, timeRef, recursive, withVersions := parseLegalHoldArgs(cliCtx)
+	report, exportFile := cliCtx.Bool("report"), cliCtx.String("export")
+	if (report || exportFile != "") && !recursive {
+		fatalIf(errInvalidArgument(), "--report and --export require --recursive.")
+	}
+	if cliCtx.String("format") != "" && exportFile == "" {
+		fatalIf(errInvalidArgument(), "--format requires --export.")
+	}
 	if timeRef.IsZero() && withVersions {
 		timeRef = time.Now().UTC()
 	}
@@ -245,5 +270,13 @@ func mainLegalHoldInfo(cliCtx *cli.Context) error {
 		fatalIf(errDummy().Trace(), "Bucket lock needs to be enabled in order to use this feature.")
 	}
 
+	if report || exportFile != "" {
+		format := strings.ToLower(cliCtx.String("format"))
+		if format == "" && exportFile != "" {
+			format = strings.TrimPrefix(strings.ToLower(filepath.Ext(exportFile)), ".")
+		}
+		return reportLegalHold(ctx, targetURL, timeRef, withVersions, report, exportFile, format)
+	}
+
 	return showLegalHoldInfo(ctx, targetURL, versionID, timeRef, withVersions, recursive)
 }
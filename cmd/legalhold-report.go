@@ -0,0 +1,273 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	gojson "encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	json "github.com/minio/colorjson"
+	minio "github.com/trinet2005/oss-go-sdk"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+// legalHoldUsage is the number and size of objects in a legal hold status.
+type legalHoldUsage struct {
+	Objects int64 `json:"objects"`
+	Size    int64 `json:"size"`
+}
+
+func (u *legalHoldUsage) add(size int64) {
+	u.Objects++
+	u.Size += size
+}
+
+// legalHoldReport aggregates the legal hold status of the listed objects.
+type legalHoldReport struct {
+	on, off, notSet legalHoldUsage
+}
+
+// add accounts for an object of the given size and legal hold status.
+func (r *legalHoldReport) add(status minio.LegalHoldStatus, size int64) {
+	switch status {
+	case minio.LegalHoldEnabled:
+		r.on.add(size)
+	case minio.LegalHoldDisabled:
+		r.off.add(size)
+	default:
+		r.notSet.add(size)
+	}
+}
+
+// message returns the report of the objects under url.
+func (r *legalHoldReport) message(url string, versions bool) legalHoldReportMessage {
+	return legalHoldReportMessage{
+		URL:      url,
+		Versions: versions,
+		Total: legalHoldUsage{
+			Objects: r.on.Objects + r.off.Objects + r.notSet.Objects,
+			Size:    r.on.Size + r.off.Size + r.notSet.Size,
+		},
+		On:     r.on,
+		Off:    r.off,
+		NotSet: r.notSet,
+	}
+}
+
+// legalHoldReportMessage container for the legal hold report of a prefix.
+type legalHoldReportMessage struct {
+	Status   string         `json:"status"`
+	URL      string         `json:"url"`
+	Versions bool           `json:"versions"`
+	Total    legalHoldUsage `json:"total"`
+	On       legalHoldUsage `json:"on"`
+	Off      legalHoldUsage `json:"off"`
+	NotSet   legalHoldUsage `json:"notSet"`
+}
+
+// String colorized legal hold report message.
+func (m legalHoldReportMessage) String() string {
+	unit := "OBJECTS"
+	if m.Versions {
+		unit = "VERSIONS"
+	}
+	var s strings.Builder
+	fmt.Fprintf(&s, "%s %s\n\n", console.Colorize("LegalHoldVersion", "Legal hold of"), console.Colorize("LegalHoldVersion", m.URL))
+	w := tabwriter.NewWriter(&s, 1, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "STATUS\t%s\tSIZE\n", unit)
+	for _, row := range []struct {
+		status string
+		usage  legalHoldUsage
+	}{
+		{console.Colorize("LegalHoldOn", minio.LegalHoldEnabled), m.On},
+		{console.Colorize("LegalHoldOff", minio.LegalHoldDisabled), m.Off},
+		{console.Colorize("LegalHoldNotSet", "Not set"), m.NotSet},
+		{"Total", m.Total},
+	} {
+		fmt.Fprintf(w, "%s\t%d\t%s\n", row.status, row.usage.Objects, humanize.IBytes(uint64(row.usage.Size)))
+	}
+	w.Flush()
+	return strings.TrimSuffix(s.String(), "\n")
+}
+
+// JSON jsonified legal hold report message.
+func (m legalHoldReportMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// legalHoldInventoryEntry is the legal hold of one object version.
+type legalHoldInventoryEntry struct {
+	Key          string                `json:"key"`
+	VersionID    string                `json:"versionID,omitempty"`
+	Size         int64                 `json:"size"`
+	LastModified time.Time             `json:"lastModified"`
+	LegalHold    minio.LegalHoldStatus `json:"legalhold"`
+}
+
+// legalHoldInventoryWriter writes a legal hold inventory as CSV or JSON lines.
+type legalHoldInventoryWriter struct {
+	csv  *csv.Writer
+	json *gojson.Encoder
+}
+
+func newLegalHoldInventoryWriter(w io.Writer, format string) (*legalHoldInventoryWriter, *probe.Error) {
+	switch format {
+	case "csv":
+		iw := &legalHoldInventoryWriter{csv: csv.NewWriter(w)}
+		if e := iw.csv.Write([]string{"key", "versionID", "size", "lastModified", "legalhold"}); e != nil {
+			return nil, probe.NewError(e)
+		}
+		return iw, nil
+	case "jsonl", "json":
+		return &legalHoldInventoryWriter{json: gojson.NewEncoder(w)}, nil
+	}
+	return nil, errInvalidArgument().Trace(format)
+}
+
+// write adds an entry to the inventory.
+func (w *legalHoldInventoryWriter) write(entry legalHoldInventoryEntry) *probe.Error {
+	var e error
+	if w.csv != nil {
+		e = w.csv.Write([]string{
+			entry.Key,
+			entry.VersionID,
+			strconv.FormatInt(entry.Size, 10),
+			entry.LastModified.UTC().Format(time.RFC3339),
+			string(entry.LegalHold),
+		})
+	} else {
+		e = w.json.Encode(entry)
+	}
+	return probe.NewError(e)
+}
+
+// flush flushes the buffered CSV records.
+func (w *legalHoldInventoryWriter) flush() *probe.Error {
+	if w.csv == nil {
+		return nil
+	}
+	w.csv.Flush()
+	return probe.NewError(w.csv.Error())
+}
+
+// legalHoldExportMessage container for an exported legal hold inventory.
+type legalHoldExportMessage struct {
+	Status  string `json:"status"`
+	URL     string `json:"url"`
+	File    string `json:"file"`
+	Objects int64  `json:"objects"`
+}
+
+// String colorized legal hold export message.
+func (m legalHoldExportMessage) String() string {
+	return console.Colorize("LegalHoldSuccess", fmt.Sprintf("Exported the legal hold of %d objects under `%s` to `%s`.", m.Objects, m.URL, m.File))
+}
+
+// JSON jsonified legal hold export message.
+func (m legalHoldExportMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// reportLegalHold fetches the legal hold of every object under urlStr to
+// report how many are held, export them to exportFile, or both.
+func reportLegalHold(ctx context.Context, urlStr string, timeRef time.Time, withVersions, report bool, exportFile, format string) error {
+	clnt, err := newClient(urlStr)
+	fatalIf(err.Trace(urlStr), "Unable to parse the provided url.")
+	alias, _, _ := mustExpandAlias(urlStr)
+
+	var inventory *legalHoldInventoryWriter
+	var f *os.File
+	if exportFile != "" {
+		var e error
+		f, e = os.Create(exportFile)
+		fatalIf(probe.NewError(e).Trace(exportFile), "Unable to create the legal hold inventory.")
+		defer f.Close()
+		inventory, err = newLegalHoldInventoryWriter(f, format)
+		fatalIf(err.Trace(exportFile), "Unable to write the legal hold inventory, the format must be 'csv' or 'jsonl'.")
+	}
+
+	var cErr error
+	var stats legalHoldReport
+	basePath := strings.TrimSuffix(clnt.GetURL().Path, "/") + "/"
+	lstOptions := ListOptions{Recursive: true, ShowDir: DirNone}
+	if !timeRef.IsZero() {
+		lstOptions.WithOlderVersions = withVersions
+		lstOptions.TimeRef = timeRef
+	}
+	for content := range clnt.List(ctx, lstOptions) {
+		if content.Err != nil {
+			errorIf(content.Err.Trace(clnt.GetURL().String()), "Unable to list folder.")
+			cErr = exitStatus(globalErrorExitStatus)
+			continue
+		}
+		if content.IsDeleteMarker || content.Type.IsDir() {
+			continue
+		}
+
+		objClnt, err := newClientFromAlias(alias, content.URL.String())
+		if err == nil {
+			var lhold minio.LegalHoldStatus
+			lhold, err = objClnt.GetObjectLegalHold(ctx, content.VersionID)
+			if err == nil {
+				stats.add(lhold, content.Size)
+				if inventory != nil {
+					err = inventory.write(legalHoldInventoryEntry{
+						Key:          strings.TrimPrefix(content.URL.Path, basePath),
+						VersionID:    content.VersionID,
+						Size:         content.Size,
+						LastModified: content.Time,
+						LegalHold:    lhold,
+					})
+					fatalIf(err.Trace(exportFile), "Unable to write the legal hold inventory.")
+				}
+				continue
+			}
+		}
+		errorIf(err.Trace(content.URL.String()), "Failed to get legal hold information on `"+content.URL.Path+"`")
+		cErr = exitStatus(globalErrorExitStatus)
+	}
+
+	msg := stats.message(urlStr, withVersions)
+	if inventory != nil {
+		fatalIf(inventory.flush().Trace(exportFile), "Unable to write the legal hold inventory.")
+		fatalIf(probe.NewError(f.Close()).Trace(exportFile), "Unable to write the legal hold inventory.")
+		if !report {
+			printMsg(legalHoldExportMessage{URL: urlStr, File: exportFile, Objects: msg.Total.Objects})
+		}
+	}
+	if report {
+		printMsg(msg)
+	}
+	return cErr
+}
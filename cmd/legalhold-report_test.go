@@ -0,0 +1,92 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	minio "github.com/trinet2005/oss-go-sdk"
+)
+
+func TestLegalHoldReport(t *testing.T) {
+	var report legalHoldReport
+	report.add(minio.LegalHoldEnabled, 100)
+	report.add(minio.LegalHoldEnabled, 50)
+	report.add(minio.LegalHoldDisabled, 10)
+	report.add("", 5)
+
+	msg := report.message("myminio/mybucket", true)
+	testCases := []struct {
+		name     string
+		usage    legalHoldUsage
+		expected legalHoldUsage
+	}{
+		{"on", msg.On, legalHoldUsage{Objects: 2, Size: 150}},
+		{"off", msg.Off, legalHoldUsage{Objects: 1, Size: 10}},
+		{"not set", msg.NotSet, legalHoldUsage{Objects: 1, Size: 5}},
+		{"total", msg.Total, legalHoldUsage{Objects: 4, Size: 165}},
+	}
+	for i, testCase := range testCases {
+		if testCase.usage != testCase.expected {
+			t.Fatalf("Test %d: expected %s %+v, got %+v", i+1, testCase.name, testCase.expected, testCase.usage)
+		}
+	}
+}
+
+func TestLegalHoldInventoryWriter(t *testing.T) {
+	entries := []legalHoldInventoryEntry{
+		{Key: "a/b.txt", VersionID: "v1", Size: 10, LastModified: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), LegalHold: minio.LegalHoldEnabled},
+		{Key: "c, d.txt", Size: 5, LastModified: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+	}
+	testCases := []struct {
+		format   string
+		expected string
+		success  bool
+	}{
+		{"csv", "key,versionID,size,lastModified,legalhold\n" +
+			"a/b.txt,v1,10,2024-01-02T03:04:05Z,ON\n" +
+			"\"c, d.txt\",,5,2024-01-02T03:04:05Z,\n", true},
+		{"jsonl", `{"key":"a/b.txt","versionID":"v1","size":10,"lastModified":"2024-01-02T03:04:05Z","legalhold":"ON"}` + "\n" +
+			`{"key":"c, d.txt","size":5,"lastModified":"2024-01-02T03:04:05Z","legalhold":""}` + "\n", true},
+		{"xml", "", false},
+	}
+
+	for i, testCase := range testCases {
+		var buf bytes.Buffer
+		w, err := newLegalHoldInventoryWriter(&buf, testCase.format)
+		if (err == nil) != testCase.success {
+			t.Fatalf("Test %d: expected success %t, got %v", i+1, testCase.success, err)
+		}
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if err = w.write(entry); err != nil {
+				t.Fatalf("Test %d: unexpected error %v", i+1, err)
+			}
+		}
+		if err = w.flush(); err != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		if buf.String() != testCase.expected {
+			t.Fatalf("Test %d: expected %q, got %q", i+1, testCase.expected, buf.String())
+		}
+	}
+}
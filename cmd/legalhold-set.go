@@ -55,7 +55,7 @@ var legalHoldSetCmd = cli.Command{
 	Action:       mainLegalHoldSet,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        append(lhSetFlags, globalFlags...),
+	Flags:        append(append(lhSetFlags, legalHoldFilterFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -78,11 +78,17 @@ EXAMPLES:
 
    4. Enable object legal hold recursively for all objects versions older than one year
       $ {{.HelpName}} myminio/mybucket/prefix --recursive --rewind 365d --versions
+
+   5. Enable object legal hold on the objects of a litigation window, written between 90 and 30 days ago
+      $ {{.HelpName}} myminio/mybucket/prefix --recursive --newer-than 90d --older-than 30d
+
+   6. Enable object legal hold on all versions of the objects tagged with a case number
+      $ {{.HelpName}} myminio/mybucket --recursive --versions --tags "case=2024-117"
 `,
 }
 
 // setLegalHold - Set legalhold for all objects within a given prefix.
-func setLegalHold(ctx context.Context, urlStr, versionID string, timeRef time.Time, withOlderVersions, recursive bool, lhold minio.LegalHoldStatus) error {
+func setLegalHold(ctx context.Context, urlStr, versionID string, timeRef time.Time, withOlderVersions, recursive bool, lhold minio.LegalHoldStatus, filter legalHoldFilter) error {
 	clnt, err := newClient(urlStr)
 	if err != nil {
 		fatalIf(err.Trace(), "Unable to parse the provided url.")
@@ -117,7 +123,7 @@ func setLegalHold(ctx context.Context, urlStr, versionID string, timeRef time.Ti
 	alias, _, _ := mustExpandAlias(urlStr)
 	var cErr error
 	objectsFound := false
-	lstOptions := ListOptions{Recursive: recursive, ShowDir: DirNone}
+	lstOptions := ListOptions{Recursive: recursive, ShowDir: DirNone, WithMetadata: len(filter.tags) > 0}
 	if !timeRef.IsZero() {
 		lstOptions.WithOlderVersions = withOlderVersions
 		lstOptions.TimeRef = timeRef
@@ -133,6 +139,18 @@ func setLegalHold(ctx context.Context, urlStr, versionID string, timeRef time.Ti
 			break
 		}
 
+		if filter.isSet() {
+			selected, err := filter.match(ctx, alias, content)
+			if err != nil {
+				errorIf(err.Trace(content.URL.Path), "Unable to get the tags of `"+content.URL.Path+"`, skipping it.")
+				cErr = exitStatus(globalErrorExitStatus)
+				continue
+			}
+			if !selected {
+				continue
+			}
+		}
+
 		objectsFound = true
 
 		newClnt, perr := newClientFromAlias(alias, content.URL.String())
@@ -202,6 +220,7 @@ func mainLegalHoldSet(cliCtx *cli.Context) error {
 	console.SetColor("LegalHoldMessageFailure", color.New(color.FgYellow))
 
 	targetURL, versionID, timeRef, recursive, withVersions := parseLegalHoldArgs(cliCtx)
+	filter := parseLegalHoldFilter(cliCtx, recursive, withVersions)
 	if timeRef.IsZero() && withVersions {
 		timeRef = time.Now().UTC()
 	}
@@ -217,5 +236,5 @@ func mainLegalHoldSet(cliCtx *cli.Context) error {
 		fatalIf(errDummy().Trace(), "Bucket lock needs to be enabled in order to use this feature.")
 	}
 
-	return setLegalHold(ctx, targetURL, versionID, timeRef, withVersions, recursive, minio.LegalHoldEnabled)
+	return setLegalHold(ctx, targetURL, versionID, timeRef, withVersions, recursive, minio.LegalHoldEnabled, filter)
 }
@@ -0,0 +1,181 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+// lsFilterWorkers is the number of objects whose tags and
+// metadata are looked up concurrently by 'ls --tags/--metadata'.
+const lsFilterWorkers = 16
+
+// lsContentFilter keeps the objects carrying all the given tags and
+// metadata. Listings do not return them, so they are looked up object
+// by object.
+type lsContentFilter struct {
+	tags     map[string]string
+	metadata map[string]string // keyed by canonical header name
+}
+
+// parseLsContentFilter parses the KEY=VALUE pairs of --tags and
+// --metadata, returning a nil filter if there are none.
+func parseLsContentFilter(tags, metadata []string) (*lsContentFilter, *probe.Error) {
+	if len(tags) == 0 && len(metadata) == 0 {
+		return nil, nil
+	}
+	f := &lsContentFilter{
+		tags:     make(map[string]string, len(tags)),
+		metadata: make(map[string]string, len(metadata)),
+	}
+	for _, pair := range tags {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, probe.NewError(fmt.Errorf("tag filter should be of the form KEY=VALUE")).Trace(pair)
+		}
+		f.tags[key] = value
+	}
+	for _, pair := range metadata {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, probe.NewError(fmt.Errorf("metadata filter should be of the form KEY=VALUE")).Trace(pair)
+		}
+		f.metadata[http.CanonicalHeaderKey(key)] = value
+	}
+	return f, nil
+}
+
+// match looks up the tags and metadata of the object version c of
+// alias and returns true if it carries all the ones of the filter.
+// Delete markers and folders never match.
+func (f *lsContentFilter) match(ctx context.Context, alias string, c *ClientContent) (bool, *probe.Error) {
+	if c.IsDeleteMarker || c.Type.IsDir() {
+		return false, nil
+	}
+	clnt, err := newClientFromAlias(alias, c.URL.String())
+	if err != nil {
+		return false, err.Trace(alias, c.URL.String())
+	}
+	if len(f.tags) > 0 {
+		tags, err := clnt.GetTags(ctx, c.VersionID)
+		if err != nil {
+			return false, err.Trace(c.URL.String())
+		}
+		if !matchPairs(f.tags, tags) {
+			return false, nil
+		}
+		c.Tags = tags
+	}
+	if len(f.metadata) > 0 {
+		st, err := clnt.Stat(ctx, StatOptions{versionID: c.VersionID})
+		if err != nil {
+			return false, err.Trace(c.URL.String())
+		}
+		if !matchPairs(f.metadata, st.Metadata) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// matchPairs returns true if values holds every pair of want.
+func matchPairs(want, values map[string]string) bool {
+	for key, value := range want {
+		if v, ok := values[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+// lsFilterResult holds the versions of one object, the ones matching
+// the filter and the error met while looking them up, if any.
+type lsFilterResult struct {
+	versions []*ClientContent
+	matched  map[*ClientContent]bool
+	err      *probe.Error
+}
+
+// filterObjects looks up the versions of every object received from
+// objects, up to lsFilterWorkers objects at a time, and returns their
+// results in the order of objects. Without allVersions only the latest
+// version, the one printed by ls, is looked up.
+func (f *lsContentFilter) filterObjects(ctx context.Context, alias string, allVersions bool, objects <-chan []*ClientContent) <-chan lsFilterResult {
+	pending := make(chan chan lsFilterResult, lsFilterWorkers)
+	go func() {
+		defer close(pending)
+		for versions := range objects {
+			resultCh := make(chan lsFilterResult, 1)
+			pending <- resultCh
+			go func(versions []*ClientContent) {
+				sortObjectVersions(versions)
+				result := lsFilterResult{versions: versions, matched: make(map[*ClientContent]bool)}
+				candidates := versions
+				if !allVersions && len(candidates) > 0 {
+					candidates = candidates[:1]
+				}
+				for _, c := range candidates {
+					ok, err := f.match(ctx, alias, c)
+					if err != nil {
+						result.err = err
+						break
+					}
+					if ok {
+						result.matched[c] = true
+					}
+				}
+				resultCh <- result
+			}(versions)
+		}
+	}()
+
+	results := make(chan lsFilterResult)
+	go func() {
+		defer close(results)
+		for resultCh := range pending {
+			results <- <-resultCh
+		}
+	}()
+	return results
+}
+
+// matchDeleteMarkers returns the versions of one object selected by
+// --delete-markers-only, all its delete markers, and by
+// --latest-is-delete-marker, all its versions if the latest one is
+// a delete marker. Both must hold if both are set.
+func matchDeleteMarkers(versions []*ClientContent, markersOnly, latestIsMarker bool) map[*ClientContent]bool {
+	matched := make(map[*ClientContent]bool)
+	if len(versions) == 0 {
+		return matched
+	}
+	sortObjectVersions(versions)
+	if latestIsMarker && !versions[0].IsDeleteMarker {
+		return matched
+	}
+	for _, c := range versions {
+		if !markersOnly || c.IsDeleteMarker {
+			matched[c] = true
+		}
+	}
+	return matched
+}
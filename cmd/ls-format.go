@@ -0,0 +1,138 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+// lsFieldNames lists the fields accepted by 'ls --fields'.
+var lsFieldNames = []string{"key", "size", "etag", "storage-class", "version-id", "last-modified", "type", "url"}
+
+// lsFieldValue returns the value of a field of 'ls --fields'.
+func lsFieldValue(c contentMessage, field string) interface{} {
+	switch field {
+	case "key":
+		return c.Key
+	case "size":
+		return c.Size
+	case "etag":
+		return c.ETag
+	case "storage-class":
+		return c.StorageClass
+	case "version-id":
+		return c.VersionID
+	case "last-modified":
+		return c.Time.UTC().Format(time.RFC3339)
+	case "type":
+		return c.Filetype
+	case "url":
+		return c.URL
+	}
+	return nil
+}
+
+// lsFormat prints the listed entries with a custom layout,
+// either a list of fields or a Go template.
+type lsFormat struct {
+	fields []string
+	tmpl   *template.Template
+}
+
+// parseLsFormat validates the fields of --fields or the template
+// of --format, only one of them may be set.
+func parseLsFormat(fields, format string) (*lsFormat, *probe.Error) {
+	switch {
+	case fields != "" && format != "":
+		return nil, probe.NewError(fmt.Errorf("--fields and --format cannot be used together"))
+	case fields != "":
+		f := &lsFormat{}
+		for _, field := range strings.Split(fields, ",") {
+			field = strings.ToLower(strings.TrimSpace(field))
+			if lsFieldValue(contentMessage{}, field) == nil {
+				return nil, probe.NewError(fmt.Errorf("unknown field `%s`, valid fields are %s", field, strings.Join(lsFieldNames, ", ")))
+			}
+			f.fields = append(f.fields, field)
+		}
+		return f, nil
+	case format != "":
+		tmpl, e := template.New("ls").Parse(format)
+		if e != nil {
+			return nil, probe.NewError(e)
+		}
+		// Catch references to unknown fields before listing.
+		if e = tmpl.Execute(&bytes.Buffer{}, contentMessage{}); e != nil {
+			return nil, probe.NewError(e)
+		}
+		return &lsFormat{tmpl: tmpl}, nil
+	}
+	return nil, nil
+}
+
+// lsFormatMessage is an entry listed with --fields or --format.
+type lsFormatMessage struct {
+	line   string
+	values map[string]interface{}
+}
+
+// String tab separated fields or rendered template
+func (m lsFormatMessage) String() string {
+	return m.line
+}
+
+// JSON jsonified selected fields
+func (m lsFormatMessage) JSON() string {
+	m.values["status"] = "success"
+	msgBytes, e := json.MarshalIndent(m.values, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(msgBytes)
+}
+
+// message returns the listed entry c laid out by f.
+func (f *lsFormat) message(c contentMessage) (lsFormatMessage, *probe.Error) {
+	if f.tmpl != nil {
+		var buf bytes.Buffer
+		if e := f.tmpl.Execute(&buf, c); e != nil {
+			return lsFormatMessage{}, probe.NewError(e)
+		}
+		return lsFormatMessage{line: buf.String()}, nil
+	}
+	m := lsFormatMessage{values: map[string]interface{}{}}
+	columns := make([]string, 0, len(f.fields))
+	for _, field := range f.fields {
+		value := lsFieldValue(c, field)
+		m.values[field] = value
+		switch v := value.(type) {
+		case int64:
+			columns = append(columns, strconv.FormatInt(v, 10))
+		default:
+			columns = append(columns, fmt.Sprint(v))
+		}
+	}
+	m.line = strings.Join(columns, "\t")
+	return m, nil
+}
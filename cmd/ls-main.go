@@ -36,6 +36,14 @@ var (
 			Name:  "rewind",
 			Usage: "list all object versions no later than specified date",
 		},
+		cli.StringFlag{
+			Name:  "until",
+			Usage: "end of the time range reported by '--changed', defaults to now",
+		},
+		cli.BoolFlag{
+			Name:  "changed",
+			Usage: "list objects created, overwritten or deleted between '--rewind' and '--until'",
+		},
 		cli.BoolFlag{
 			Name:  "versions",
 			Usage: "list all versions",
@@ -60,6 +68,31 @@ var (
 			Name:  "zip",
 			Usage: "list files inside zip archive (MinIO servers only)",
 		},
+		parallelBucketsFlag,
+		cli.StringFlag{
+			Name:  "fields",
+			Usage: "print only the comma separated fields, among key, size, etag, storage-class, version-id, last-modified, type and url",
+		},
+		cli.StringFlag{
+			Name:  "format",
+			Usage: "print every entry with a Go template, e.g. '{{.Key}} {{.Size}}'",
+		},
+		cli.StringSliceFlag{
+			Name:  "tags",
+			Usage: "list only objects with the tag KEY=VALUE, looked up for every object",
+		},
+		cli.StringSliceFlag{
+			Name:  "metadata",
+			Usage: "list only objects with the metadata KEY=VALUE, looked up for every object",
+		},
+		cli.BoolFlag{
+			Name:  "delete-markers-only",
+			Usage: "list only the delete markers, of all versions",
+		},
+		cli.BoolFlag{
+			Name:  "latest-is-delete-marker",
+			Usage: "list only deleted objects, whose latest version is a delete marker",
+		},
 	}
 )
 
@@ -112,6 +145,28 @@ EXAMPLES:
   
   10. List all objects on mybucket, for the GLACIER storage class
      {{.Prompt}} {{.HelpName}} --storage-class 'GLACIER' s3/mybucket 
+
+  11. List objects created, overwritten or deleted between two points in time if the bucket versioning is enabled.
+     {{.Prompt}} {{.HelpName}} --recursive --changed --rewind 2023.10.01T08:00 --until 2023.10.01T12:00 s3/mybucket
+     {{.Prompt}} {{.HelpName}} --recursive --changed --rewind 1d s3/mybucket
+
+  12. List all objects of all buckets on s3, 32 buckets at a time, and summarize them.
+     {{.Prompt}} {{.HelpName}} --recursive --summarize --parallel-buckets 32 s3
+
+  13. List the key, size and ETag of all objects of mybucket as tab separated columns.
+     {{.Prompt}} {{.HelpName}} --recursive --fields key,size,etag s3/mybucket
+
+  14. List the size and key of all objects of mybucket with a Go template.
+     {{.Prompt}} {{.HelpName}} --recursive --format '{{"{{.Size}} {{.Key}}"}}' s3/mybucket
+
+  15. List the objects of mybucket tagged 'env=prod' and owned by alice, summarizing their number and size.
+     {{.Prompt}} {{.HelpName}} --recursive --summarize --tags env=prod --metadata X-Amz-Meta-Owner=alice s3/mybucket
+
+  16. List all the versions of the objects deleted from mybucket, to find the ones to restore.
+     {{.Prompt}} {{.HelpName}} --recursive --versions --latest-is-delete-marker s3/mybucket
+
+  17. List all the delete markers of mybucket.
+     {{.Prompt}} {{.HelpName}} --recursive --delete-markers-only s3/mybucket
 `,
 }
 
@@ -124,6 +179,12 @@ var rewindSupportedFormat = []string{
 
 // Parse rewind flag while considering the system local time zone
 func parseRewindFlag(rewind string) (timeRef time.Time) {
+	return parseTimeRefFlag("rewind", rewind)
+}
+
+// Parse a date or a duration in the past passed to the
+// given flag while considering the system local time zone
+func parseTimeRefFlag(flag, rewind string) (timeRef time.Time) {
 	if rewind != "" {
 		location, e := time.LoadLocation("Local")
 		if e != nil {
@@ -142,7 +203,7 @@ func parseRewindFlag(rewind string) (timeRef time.Time) {
 			if duration, e := ParseDuration(rewind); e == nil {
 				if duration < 0 {
 					fatalIf(probe.NewError(errors.New("negative duration is not supported")),
-						"Unable to parse --rewind argument")
+						"Unable to parse --"+flag+" argument")
 				}
 				timeRef = time.Now().Add(-time.Duration(duration))
 			}
@@ -150,7 +211,7 @@ func parseRewindFlag(rewind string) (timeRef time.Time) {
 
 		if timeRef.IsZero() {
 			// rewind argument still not parsed, error out
-			fatalIf(probe.NewError(errors.New("unknown format")), "Unable to parse --rewind argument")
+			fatalIf(probe.NewError(errors.New("unknown format")), "Unable to parse --"+flag+" argument")
 		}
 	}
 	return
@@ -175,19 +236,72 @@ func checkListSyntax(cliCtx *cli.Context) ([]string, doListOptions) {
 	listZip := cliCtx.Bool("zip")
 
 	timeRef := parseRewindFlag(cliCtx.String("rewind"))
+	untilRef := parseTimeRefFlag("until", cliCtx.String("until"))
+	isChanged := cliCtx.Bool("changed")
+
+	if isChanged {
+		if timeRef.IsZero() {
+			fatalIf(errInvalidArgument().Trace(args...), "--changed requires --rewind to set the start of the time range")
+		}
+		if untilRef.IsZero() {
+			untilRef = time.Now()
+		}
+		if !untilRef.After(timeRef) {
+			fatalIf(errInvalidArgument().Trace(args...), "--until should be later than --rewind")
+		}
+		if withOlderVersions || isIncomplete || listZip {
+			fatalIf(errInvalidArgument().Trace(args...), "--changed cannot be used with --versions, --incomplete or --zip")
+		}
+	} else if !untilRef.IsZero() {
+		fatalIf(errInvalidArgument().Trace(args...), "--until can only be used with --changed")
+	}
 
 	if listZip && (withOlderVersions || !timeRef.IsZero()) {
 		fatalIf(errInvalidArgument().Trace(args...), "Zip file listing can only be performed on the latest version")
 	}
+	format, err := parseLsFormat(cliCtx.String("fields"), cliCtx.String("format"))
+	fatalIf(err.Trace(args...), "Unable to parse the output format.")
+	if format != nil {
+		if isChanged {
+			fatalIf(errInvalidArgument().Trace(args...), "--fields and --format cannot be used with --changed")
+		}
+		if format.tmpl != nil && globalJSON {
+			fatalIf(errInvalidArgument().Trace(args...), "--format cannot be used with --json, use --fields instead")
+		}
+	}
+
+	contentFilter, err := parseLsContentFilter(cliCtx.StringSlice("tags"), cliCtx.StringSlice("metadata"))
+	fatalIf(err.Trace(args...), "Unable to parse the tag and metadata filters.")
+	if contentFilter != nil && (isChanged || isIncomplete || listZip) {
+		fatalIf(errInvalidArgument().Trace(args...), "--tags and --metadata cannot be used with --changed, --incomplete or --zip")
+	}
+
+	markersOnly := cliCtx.Bool("delete-markers-only")
+	latestIsMarker := cliCtx.Bool("latest-is-delete-marker")
+	if markersOnly || latestIsMarker {
+		if isChanged || isIncomplete || listZip || contentFilter != nil {
+			fatalIf(errInvalidArgument().Trace(args...), "--delete-markers-only and --latest-is-delete-marker cannot be used with --changed, --incomplete, --zip, --tags or --metadata")
+		}
+		// Delete markers are listed among the versions.
+		withOlderVersions = withOlderVersions || markersOnly
+	}
+
 	storageClasss := cliCtx.String("storage-class")
 	opts := doListOptions{
 		timeRef:           timeRef,
+		untilRef:          untilRef,
+		isChanged:         isChanged,
 		isRecursive:       isRecursive,
 		isIncomplete:      isIncomplete,
 		isSummary:         isSummary,
 		withOlderVersions: withOlderVersions,
 		listZip:           listZip,
 		filter:            storageClasss,
+		parallelBuckets:   cliCtx.Int("parallel-buckets"),
+		format:            format,
+		contentFilter:     contentFilter,
+		markersOnly:       markersOnly,
+		latestIsMarker:    latestIsMarker,
 	}
 	return args, opts
 }
@@ -208,6 +322,9 @@ func mainList(cliCtx *cli.Context) error {
 	console.SetColor("Time", color.New(color.FgGreen))
 	console.SetColor("Summarize", color.New(color.Bold))
 	console.SetColor("SC", color.New(color.FgBlue))
+	console.SetColor("Created", color.New(color.FgGreen, color.Bold))
+	console.SetColor("Overwritten", color.New(color.FgYellow, color.Bold))
+	console.SetColor("Deleted", color.New(color.FgRed, color.Bold))
 
 	// check 'ls' cliCtx arguments.
 	args, opts := checkListSyntax(cliCtx)
@@ -216,6 +333,12 @@ func mainList(cliCtx *cli.Context) error {
 	for _, targetURL := range args {
 		clnt, err := newClient(targetURL)
 		fatalIf(err.Trace(targetURL), "Unable to initialize target `"+targetURL+"`.")
+		if opts.contentFilter != nil {
+			if clnt.GetURL().Type != objectStorage {
+				fatalIf(errInvalidArgument().Trace(targetURL), "--tags and --metadata require `"+targetURL+"` to be on object storage.")
+			}
+			opts.alias, _ = url2Alias(targetURL)
+		}
 		if !strings.HasSuffix(targetURL, string(clnt.GetURL().Separator)) {
 			var st *ClientContent
 			st, err = clnt.Stat(ctx, StatOptions{incomplete: opts.isIncomplete})
@@ -225,6 +348,18 @@ func mainList(cliCtx *cli.Context) error {
 				fatalIf(err.Trace(targetURL), "Unable to initialize target `"+targetURL+"`.")
 			}
 		}
+		if opts.isChanged {
+			if e := doListChanges(ctx, clnt, opts); e != nil {
+				cErr = e
+			}
+			continue
+		}
+		if opts.isRecursive && isAliasRoot(clnt) {
+			if e := doListBuckets(ctx, clnt, targetURL, opts); e != nil {
+				cErr = e
+			}
+			continue
+		}
 		if e := doList(ctx, clnt, opts); e != nil {
 			cErr = e
 		}
@@ -23,6 +23,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/dustin/go-humanize"
@@ -197,35 +198,166 @@ func (s summaryMessage) JSON() string {
 	return string(jsonMessageBytes)
 }
 
-// Pretty print the list of versions belonging to one object
-func printObjectVersions(clntURL ClientURL, ctntVersions []*ClientContent, printAllVersions bool) {
+// Pretty print the list of versions belonging to one object,
+// with the custom layout of format if not nil. Only the versions
+// in matched are printed, unless matched is nil.
+func printObjectVersions(clntURL ClientURL, ctntVersions []*ClientContent, printAllVersions bool, format *lsFormat, matched map[*ClientContent]bool) {
 	sortObjectVersions(ctntVersions)
 	msgs := generateContentMessages(clntURL, ctntVersions, printAllVersions)
-	for _, msg := range msgs {
-		printMsg(msg)
+	for i, msg := range msgs {
+		if matched != nil && !matched[ctntVersions[i]] {
+			continue
+		}
+		if format == nil {
+			printMsg(msg)
+			continue
+		}
+		formatted, err := format.message(msg)
+		if err != nil {
+			errorIf(err.Trace(msg.Key), "Unable to format `"+msg.Key+"`.")
+			continue
+		}
+		printMsg(formatted)
 	}
 }
 
 type doListOptions struct {
 	timeRef           time.Time
+	untilRef          time.Time
+	isChanged         bool
 	isRecursive       bool
 	isIncomplete      bool
 	isSummary         bool
 	withOlderVersions bool
 	listZip           bool
 	filter            string
+	parallelBuckets   int
+	format            *lsFormat
+	contentFilter     *lsContentFilter
+	alias             string
+	markersOnly       bool
+	latestIsMarker    bool
 }
 
 // doList - list all entities inside a folder.
 func doList(ctx context.Context, clnt Client, o doListOptions) error {
+	totalSize, totalObjects, cErr := listObjects(ctx, clnt, clnt.GetURL(), o)
+
+	if o.isSummary {
+		printMsg(summaryMessage{
+			TotalObjects: totalObjects,
+			TotalSize:    totalSize,
+		})
+	}
+
+	return cErr
+}
+
+// doListBuckets - list all buckets of an alias recursively, listing
+// several buckets concurrently with --parallel-buckets. Objects are
+// printed relative to the alias like a sequential listing, but buckets
+// listed concurrently may interleave.
+func doListBuckets(ctx context.Context, clnt Client, aliasedURL string, o doListOptions) error {
+	var (
+		mu           sync.Mutex
+		cErr         error
+		totalSize    int64
+		totalObjects int64
+	)
+	err := forEachBucket(ctx, clnt, aliasedURL, o.parallelBuckets, func(bucketURL string) {
+		bucketClnt, err := newClient(bucketURL + "/")
+		if err != nil {
+			errorIf(err.Trace(bucketURL), "Unable to initialize target `"+bucketURL+"`.")
+			mu.Lock()
+			cErr = exitStatus(globalErrorExitStatus)
+			mu.Unlock()
+			return
+		}
+		size, objects, e := listObjects(ctx, bucketClnt, clnt.GetURL(), o)
+
+		mu.Lock()
+		defer mu.Unlock()
+		totalSize += size
+		totalObjects += objects
+		if e != nil {
+			cErr = e
+		}
+	})
+	if err != nil {
+		errorIf(err, "Unable to list buckets.")
+		return exitStatus(globalErrorExitStatus)
+	}
+
+	if o.isSummary {
+		printMsg(summaryMessage{
+			TotalObjects: totalObjects,
+			TotalSize:    totalSize,
+		})
+	}
+
+	return cErr
+}
+
+// listObjects - print all entities listed by clnt relative to
+// baseURL, returning their total size and number.
+func listObjects(ctx context.Context, clnt Client, baseURL ClientURL, o doListOptions) (totalSize, totalObjects int64, cErr error) {
 	var (
 		lastPath          string
 		perObjectVersions []*ClientContent
-		cErr              error
-		totalSize         int64
-		totalObjects      int64
 	)
 
+	// With --tags or --metadata, objects are sent to be looked up
+	// and only the matching ones are printed and summarized.
+	var (
+		objects   chan []*ClientContent
+		filterErr error
+		done      = make(chan struct{})
+	)
+	if o.contentFilter != nil {
+		objects = make(chan []*ClientContent)
+		results := o.contentFilter.filterObjects(ctx, o.alias, o.withOlderVersions, objects)
+		go func() {
+			defer close(done)
+			for result := range results {
+				if result.err != nil {
+					errorIf(result.err, "Unable to look up the tags and metadata of an object.")
+					filterErr = exitStatus(globalErrorExitStatus)
+					continue
+				}
+				if len(result.matched) == 0 {
+					continue
+				}
+				printObjectVersions(baseURL, result.versions, o.withOlderVersions, o.format, result.matched)
+				for c := range result.matched {
+					totalSize += c.Size
+					totalObjects++
+				}
+			}
+		}()
+	}
+	flush := func(versions []*ClientContent) {
+		switch {
+		case objects != nil:
+			if len(versions) > 0 {
+				objects <- versions
+			}
+		case o.markersOnly || o.latestIsMarker:
+			matched := matchDeleteMarkers(versions, o.markersOnly, o.latestIsMarker)
+			if len(matched) == 0 {
+				return
+			}
+			printObjectVersions(baseURL, versions, o.withOlderVersions, o.format, matched)
+			for c := range matched {
+				if o.withOlderVersions || c == versions[0] {
+					totalSize += c.Size
+					totalObjects++
+				}
+			}
+		default:
+			printObjectVersions(baseURL, versions, o.withOlderVersions, o.format, nil)
+		}
+	}
+
 	for content := range clnt.List(ctx, ListOptions{
 		Recursive:         o.isRecursive,
 		Incomplete:        o.isIncomplete,
@@ -247,24 +379,165 @@ func doList(ctx context.Context, clnt Client, o doListOptions) error {
 
 		if lastPath != content.URL.Path {
 			// Print any object in the current list before reinitializing it
-			printObjectVersions(clnt.GetURL(), perObjectVersions, o.withOlderVersions)
+			flush(perObjectVersions)
 			lastPath = content.URL.Path
 			perObjectVersions = []*ClientContent{}
 		}
 
 		perObjectVersions = append(perObjectVersions, content)
-		totalSize += content.Size
-		totalObjects++
+		if objects == nil && !o.markersOnly && !o.latestIsMarker {
+			totalSize += content.Size
+			totalObjects++
+		}
 	}
 
-	printObjectVersions(clnt.GetURL(), perObjectVersions, o.withOlderVersions)
+	flush(perObjectVersions)
+	if objects != nil {
+		close(objects)
+		<-done
+		if filterErr != nil {
+			cErr = filterErr
+		}
+	}
 
-	if o.isSummary {
-		printMsg(summaryMessage{
-			TotalObjects: totalObjects,
-			TotalSize:    totalSize,
-		})
+	return totalSize, totalObjects, cErr
+}
+
+// Kinds of change reported by 'ls --changed'.
+const (
+	objectCreated     = "created"
+	objectOverwritten = "overwritten"
+	objectDeleted     = "deleted"
+)
+
+// changeMessage container for a change of an object between two points in time.
+type changeMessage struct {
+	Status    string    `json:"status"`
+	Change    string    `json:"change"`
+	Time      time.Time `json:"lastModified"`
+	Size      int64     `json:"size"`
+	Key       string    `json:"key"`
+	ETag      string    `json:"etag,omitempty"`
+	VersionID string    `json:"versionId,omitempty"`
+}
+
+// String colorized string message.
+func (c changeMessage) String() string {
+	message := console.Colorize("Time", fmt.Sprintf("[%s]", c.Time.Format(printDate)))
+	message += console.Colorize("Size", fmt.Sprintf("%7s", strings.Join(strings.Fields(humanize.IBytes(uint64(c.Size))), "")))
+	switch c.Change {
+	case objectCreated:
+		message += console.Colorize("Created", fmt.Sprintf(" %-11s", c.Change))
+	case objectOverwritten:
+		message += console.Colorize("Overwritten", fmt.Sprintf(" %-11s", c.Change))
+	case objectDeleted:
+		message += console.Colorize("Deleted", fmt.Sprintf(" %-11s", c.Change))
+	}
+	return message + console.Colorize("File", " "+c.Key)
+}
+
+// JSON jsonified change message.
+func (c changeMessage) JSON() string {
+	c.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(c, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// versionAt returns the version of an object current at the given time,
+// which may be a delete marker, or nil if the object did not exist yet.
+func versionAt(ctntVersions []*ClientContent, t time.Time) *ClientContent {
+	var current *ClientContent
+	for _, c := range ctntVersions {
+		if c.Time.After(t) {
+			continue
+		}
+		if current == nil || c.Time.After(current.Time) {
+			current = c
+		}
+	}
+	return current
+}
+
+// objectChange compares the versions of an object current at from and
+// until, returning the kind of change and the version describing it.
+// An empty change is returned if the object was not modified.
+func objectChange(ctntVersions []*ClientContent, from, until time.Time) (string, *ClientContent) {
+	before := versionAt(ctntVersions, from)
+	after := versionAt(ctntVersions, until)
+	existedBefore := before != nil && !before.IsDeleteMarker
+	existsAfter := after != nil && !after.IsDeleteMarker
+	switch {
+	case !existedBefore && existsAfter:
+		return objectCreated, after
+	case existedBefore && !existsAfter:
+		return objectDeleted, after
+	case existedBefore && existsAfter && before != after:
+		return objectOverwritten, after
+	}
+	return "", nil
+}
+
+// printObjectChange prints the change of one object, if any.
+func printObjectChange(clntURL ClientURL, ctntVersions []*ClientContent, o doListOptions) {
+	change, c := objectChange(ctntVersions, o.timeRef, o.untilRef)
+	if c == nil {
+		return
 	}
+	msgs := generateContentMessages(clntURL, []*ClientContent{c}, false)
+	if len(msgs) == 0 {
+		return
+	}
+	printMsg(changeMessage{
+		Change:    change,
+		Time:      msgs[0].Time,
+		Size:      msgs[0].Size,
+		Key:       msgs[0].Key,
+		ETag:      msgs[0].ETag,
+		VersionID: msgs[0].VersionID,
+	})
+}
+
+// doListChanges - list objects created, overwritten or deleted
+// between o.timeRef and o.untilRef using the version history.
+func doListChanges(ctx context.Context, clnt Client, o doListOptions) error {
+	var (
+		lastPath          string
+		perObjectVersions []*ClientContent
+		cErr              error
+	)
+
+	for content := range clnt.List(ctx, ListOptions{
+		Recursive:         o.isRecursive,
+		WithOlderVersions: true,
+		WithDeleteMarkers: true,
+		ShowDir:           DirNone,
+	}) {
+		if content.Err != nil {
+			errorIf(content.Err.Trace(clnt.GetURL().String()), "Unable to list folder.")
+			cErr = exitStatus(globalErrorExitStatus) // Set the exit status.
+			continue
+		}
+
+		if content.Type.IsDir() {
+			continue
+		}
+
+		if content.StorageClass != "" && o.filter != "" && o.filter != "*" && content.StorageClass != o.filter {
+			continue
+		}
+
+		if lastPath != content.URL.Path {
+			printObjectChange(clnt.GetURL(), perObjectVersions, o)
+			lastPath = content.URL.Path
+			perObjectVersions = []*ClientContent{}
+		}
+
+		perObjectVersions = append(perObjectVersions, content)
+	}
+
+	printObjectChange(clnt.GetURL(), perObjectVersions, o)
 
 	return cErr
 }
@@ -16,3 +16,149 @@
 // along with this program.  If not, see <http://www.gnu.org/licenses/>.
 
 package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestObjectChange(t *testing.T) {
+	from := time.Date(2023, 10, 1, 8, 0, 0, 0, time.UTC)
+	until := from.Add(4 * time.Hour)
+
+	v1 := &ClientContent{VersionID: "v1", Time: from.Add(-time.Hour)}
+	v2 := &ClientContent{VersionID: "v2", Time: from.Add(time.Hour)}
+	del := &ClientContent{VersionID: "d1", Time: from.Add(2 * time.Hour), IsDeleteMarker: true}
+	v3 := &ClientContent{VersionID: "v3", Time: until.Add(time.Hour)}
+
+	testCases := []struct {
+		versions []*ClientContent
+		change   string
+		version  *ClientContent
+	}{
+		// Unchanged in the time range.
+		{[]*ClientContent{v1}, "", nil},
+		// Created in the time range.
+		{[]*ClientContent{v2}, objectCreated, v2},
+		// Overwritten in the time range.
+		{[]*ClientContent{v2, v1}, objectOverwritten, v2},
+		// Deleted in the time range.
+		{[]*ClientContent{del, v1}, objectDeleted, del},
+		// Created then deleted in the time range.
+		{[]*ClientContent{del, v2}, "", nil},
+		// Changed after the time range only.
+		{[]*ClientContent{v3, v1}, "", nil},
+	}
+
+	for i, testCase := range testCases {
+		change, version := objectChange(testCase.versions, from, until)
+		if change != testCase.change || version != testCase.version {
+			t.Fatalf("Test %d: expected (%q, %v), got (%q, %v)", i+1, testCase.change, testCase.version, change, version)
+		}
+	}
+}
+
+func TestLsFormat(t *testing.T) {
+	msg := contentMessage{Key: "dir/object.txt", Size: 1024, ETag: "abc", StorageClass: "STANDARD"}
+
+	testCases := []struct {
+		fields, format string
+		line           string
+		shouldFail     bool
+	}{
+		{"key,size", "", "dir/object.txt\t1024", false},
+		{" ETag , storage-class", "", "abc\tSTANDARD", false},
+		{"key,owner", "", "", true},
+		{"", "{{.Size}} {{.Key}}", "1024 dir/object.txt", false},
+		{"", "{{.Owner}}", "", true},
+		{"", "{{.Key", "", true},
+		{"key", "{{.Key}}", "", true},
+	}
+
+	for i, testCase := range testCases {
+		format, err := parseLsFormat(testCase.fields, testCase.format)
+		if (err != nil) != testCase.shouldFail {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		if err != nil {
+			continue
+		}
+		formatted, err := format.message(msg)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		if formatted.String() != testCase.line {
+			t.Fatalf("Test %d: expected %q, got %q", i+1, testCase.line, formatted.String())
+		}
+	}
+}
+
+func TestLsContentFilter(t *testing.T) {
+	tags := map[string]string{"env": "prod", "team": "ml"}
+	metadata := map[string]string{"X-Amz-Meta-Owner": "alice", "Content-Type": "text/csv"}
+	testCases := []struct {
+		tags, metadata []string
+		match          bool
+		shouldFail     bool
+	}{
+		{[]string{"env=prod"}, nil, true, false},
+		{[]string{"env=prod", "team=ml"}, []string{"x-amz-meta-owner=alice"}, true, false},
+		{[]string{"env=dev"}, nil, false, false},
+		{nil, []string{"Content-Type=text/csv", "X-Amz-Meta-Owner=bob"}, false, false},
+		{[]string{"empty="}, nil, false, false},
+		{[]string{"env"}, nil, false, true},
+		{nil, []string{"=alice"}, false, true},
+	}
+
+	for i, testCase := range testCases {
+		f, err := parseLsContentFilter(testCase.tags, testCase.metadata)
+		if (err != nil) != testCase.shouldFail {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		if err != nil {
+			continue
+		}
+		if match := matchPairs(f.tags, tags) && matchPairs(f.metadata, metadata); match != testCase.match {
+			t.Fatalf("Test %d: expected match %v, got %v", i+1, testCase.match, match)
+		}
+	}
+
+	if f, _ := parseLsContentFilter(nil, nil); f != nil {
+		t.Fatalf("expected no filter without tags and metadata")
+	}
+}
+
+func TestMatchDeleteMarkers(t *testing.T) {
+	now := time.Date(2023, 10, 1, 8, 0, 0, 0, time.UTC)
+	marker := &ClientContent{VersionID: "m", IsDeleteMarker: true, Time: now}
+	v2 := &ClientContent{VersionID: "v2", Time: now.Add(-time.Hour)}
+	oldMarker := &ClientContent{VersionID: "m1", IsDeleteMarker: true, Time: now.Add(-2 * time.Hour)}
+	v1 := &ClientContent{VersionID: "v1", Time: now.Add(-3 * time.Hour)}
+
+	testCases := []struct {
+		versions       []*ClientContent
+		markersOnly    bool
+		latestIsMarker bool
+		expected       []string
+	}{
+		{[]*ClientContent{v1, marker, v2, oldMarker}, true, false, []string{"m", "m1"}},
+		{[]*ClientContent{v1, marker, v2, oldMarker}, false, true, []string{"m", "v2", "m1", "v1"}},
+		{[]*ClientContent{v1, marker, v2, oldMarker}, true, true, []string{"m", "m1"}},
+		{[]*ClientContent{v1, v2, oldMarker}, true, false, []string{"m1"}},
+		{[]*ClientContent{v1, v2, oldMarker}, false, true, nil},
+		{[]*ClientContent{v1, v2, oldMarker}, true, true, nil},
+	}
+	for i, testCase := range testCases {
+		matched := matchDeleteMarkers(testCase.versions, testCase.markersOnly, testCase.latestIsMarker)
+		var got []string
+		for _, c := range testCase.versions {
+			if matched[c] {
+				got = append(got, c.VersionID)
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(testCase.expected, ",") {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.expected, got)
+		}
+	}
+}
@@ -249,6 +249,9 @@ func checkConfig() {
 }
 
 func migrate() {
+	// Report malformed config files precisely.
+	checkConfigSyntax()
+
 	// Fix broken config files if any.
 	fixConfig()
 
@@ -370,6 +373,12 @@ func registerBefore(ctx *cli.Context) error {
 	// Set global flags.
 	setGlobalsFromContext(ctx)
 
+	// 'mc config validate' reports the problems of the config
+	// file itself, which must not be migrated or checked before.
+	if args := ctx.Args(); len(args) > 1 && args[0] == configCmd.Name && args[1] == configValidateCmd.Name {
+		return nil
+	}
+
 	// Migrate any old version of config / state files to newer format.
 	migrate()
 
@@ -429,12 +438,17 @@ var appCmds = []cli.Command{
 	mirrorCmd,
 	catCmd,
 	headCmd,
+	getCmd,
+	putCmd,
 	pipeCmd,
 	findCmd,
+	indexCmd,
 	sqlCmd,
 	statCmd,
 	treeCmd,
 	duCmd,
+	summaryCmd,
+	pruneCmd,
 	retentionCmd,
 	legalHoldCmd,
 	supportCmd,
@@ -447,10 +461,13 @@ var appCmds = []cli.Command{
 	eventCmd,
 	watchCmd,
 	undoCmd,
+	undeleteCmd,
+	approveCmd,
 	anonymousCmd,
 	policyCmd,
 	tagCmd,
 	diffCmd,
+	cmpCmd,
 	replicateCmd,
 	adminCmd,
 	idpCmd,
@@ -458,6 +475,10 @@ var appCmds = []cli.Command{
 	updateCmd,
 	readyCmd,
 	pingCmd,
+	capabilitiesCmd,
+	daemonCmd,
+	jobsCmd,
+	runAsCmd,
 	odCmd,
 	batchCmd,
 }
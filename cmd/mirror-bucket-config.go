@@ -0,0 +1,197 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	json "github.com/minio/colorjson"
+	minio "github.com/trinet2005/oss-go-sdk"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+// Policies of 'mirror --create-buckets' for buckets missing on the target.
+const (
+	createBucketsBare       = "bare"        // create an empty bucket, as 'mc mb' does
+	createBucketsWithConfig = "with-config" // also copy versioning, locking, tags and policies
+)
+
+// Bucket configurations copied by 'mirror --create-buckets with-config'.
+const (
+	bucketConfigVersioning = "versioning"
+	bucketConfigObjectLock = "object-lock"
+	bucketConfigTags       = "tags"
+	bucketConfigPolicy     = "policy"
+)
+
+// bucketCreateMessage reports a bucket created on the target
+// with the configuration copied from its source bucket.
+type bucketCreateMessage struct {
+	Status     string `json:"status"`
+	Source     string `json:"source"`
+	Target     string `json:"target"`
+	Versioning string `json:"versioning,omitempty"`
+	ObjectLock bool   `json:"objectLock"`
+	Retention  string `json:"retention,omitempty"`
+	Tags       int    `json:"tags"`
+	Policies   int    `json:"policies"`
+}
+
+// String colorized bucket create message
+func (m bucketCreateMessage) String() string {
+	var configs []string
+	if m.Versioning != "" {
+		configs = append(configs, "versioning "+strings.ToLower(m.Versioning))
+	}
+	if m.ObjectLock {
+		lock := "object lock"
+		if m.Retention != "" {
+			lock += " (" + m.Retention + ")"
+		}
+		configs = append(configs, lock)
+	}
+	if m.Tags > 0 {
+		configs = append(configs, fmt.Sprintf("%d tag(s)", m.Tags))
+	}
+	if m.Policies > 0 {
+		configs = append(configs, fmt.Sprintf("%d policy rule(s)", m.Policies))
+	}
+	if len(configs) == 0 {
+		configs = append(configs, "no configuration")
+	}
+	return console.Colorize("Mirror", fmt.Sprintf("Created bucket `%s` from `%s`: ", m.Target, m.Source)) +
+		strings.Join(configs, ", ")
+}
+
+// JSON jsonified bucket create message
+func (m bucketCreateMessage) JSON() string {
+	m.Status = "created"
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(msgBytes)
+}
+
+// createBucketWithConfig creates the target bucket and copies the
+// versioning, object lock, tags and policies of the source bucket.
+// A configuration which cannot be copied is reported without failing
+// the mirror, only a failure to create the bucket is returned.
+func (mj *mirrorJob) createBucketWithConfig(ctx context.Context, srcClnt, tgtClnt Client, region string) *probe.Error {
+	msg := bucketCreateMessage{
+		Source: srcClnt.GetURL().String(),
+		Target: tgtClnt.GetURL().String(),
+	}
+	report := func(config string, err *probe.Error) {
+		if err == nil || isObjectConfigNotFound(err) {
+			return
+		}
+		msg := objectConfigErrorMessage{
+			Source: srcClnt.GetURL().String(),
+			Target: tgtClnt.GetURL().String(),
+			Config: config,
+			Error:  err.ToGoError().Error(),
+		}
+		if globalJSON {
+			printMsg(msg)
+			return
+		}
+		mj.status.Println(msg.String())
+	}
+
+	// Object lock can only be enabled when the bucket is created.
+	lockStatus, mode, validity, unit, err := srcClnt.GetObjectLockConfig(ctx)
+	report(bucketConfigObjectLock, err)
+	msg.ObjectLock = err == nil && lockStatus == "Enabled"
+
+	if err = tgtClnt.MakeBucket(ctx, region, false, msg.ObjectLock); err != nil {
+		return err
+	}
+	if msg.ObjectLock && mode != "" {
+		err = tgtClnt.SetObjectLockConfig(ctx, mode, validity, unit)
+		report(bucketConfigObjectLock, err)
+		if err == nil {
+			msg.Retention = fmt.Sprintf("%s %d %s", mode, validity, strings.ToLower(string(unit)))
+			// Uploads to a bucket with default retention require a Content-MD5.
+			mj.opts.md5 = true
+		}
+	}
+
+	// Buckets with object lock are always versioned.
+	versioning, err := srcClnt.GetVersion(ctx)
+	report(bucketConfigVersioning, err)
+	if err == nil {
+		switch {
+		case msg.ObjectLock:
+			msg.Versioning = minio.Enabled
+		case versioning.Status == minio.Enabled:
+			prefixes := make([]string, 0, len(versioning.ExcludedPrefixes))
+			for _, prefix := range versioning.ExcludedPrefixes {
+				prefixes = append(prefixes, prefix.Prefix)
+			}
+			err = tgtClnt.SetVersion(ctx, "enable", prefixes, versioning.ExcludeFolders)
+			report(bucketConfigVersioning, err)
+			if err == nil {
+				msg.Versioning = versioning.Status
+			}
+		case versioning.Status == minio.Suspended:
+			err = tgtClnt.SetVersion(ctx, "suspend", nil, false)
+			report(bucketConfigVersioning, err)
+			if err == nil {
+				msg.Versioning = versioning.Status
+			}
+		}
+	}
+
+	tags, err := srcClnt.GetTags(ctx, "")
+	report(bucketConfigTags, err)
+	if err == nil && len(tags) > 0 {
+		values := url.Values{}
+		for k, v := range tags {
+			values.Set(k, v)
+		}
+		err = tgtClnt.SetTags(ctx, "", values.Encode())
+		report(bucketConfigTags, err)
+		if err == nil {
+			msg.Tags = len(tags)
+		}
+	}
+
+	rules, err := srcClnt.GetAccessRules(ctx)
+	if err == nil && len(rules) > 0 {
+		err = copyBucketPolicies(ctx, srcClnt, tgtClnt, true)
+		if err == nil {
+			msg.Policies = len(rules)
+		}
+	}
+	if err != nil {
+		if _, ok := err.ToGoError().(APINotImplemented); !ok {
+			report(bucketConfigPolicy, err)
+		}
+	}
+
+	if globalJSON {
+		printMsg(msg)
+	} else {
+		mj.status.Println(msg.String())
+	}
+	return nil
+}
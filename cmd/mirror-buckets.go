@@ -0,0 +1,183 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dustin/go-humanize"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+// bucketFilter selects the buckets mirrored when the
+// source and target of a mirror are alias roots.
+type bucketFilter struct {
+	include    []string // mirror only buckets matching one of these patterns
+	exclude    []string // never mirror buckets matching one of these patterns
+	skipSystem bool     // skip system buckets such as .minio.sys
+}
+
+// isSet returns true if any bucket filter was requested.
+func (f bucketFilter) isSet() bool {
+	return len(f.include) > 0 || len(f.exclude) > 0 || f.skipSystem
+}
+
+// match returns true if bucket is to be mirrored.
+func (f bucketFilter) match(bucket string) bool {
+	if f.skipSystem && isSystemBucket(bucket) {
+		return false
+	}
+	if matchExcludeOptions(f.exclude, bucket) {
+		return false
+	}
+	return len(f.include) == 0 || matchExcludeOptions(f.include, bucket)
+}
+
+// skip returns true if the object or bucket at suffix, relative to
+// an alias root, belongs to a bucket which is not mirrored.
+func (f bucketFilter) skip(suffix string) bool {
+	bucket := suffixBucket(suffix)
+	return bucket != "" && !f.match(bucket)
+}
+
+// isSystemBucket returns true for buckets reserved by the server,
+// whose names cannot be created through the S3 API.
+func isSystemBucket(bucket string) bool {
+	return strings.HasPrefix(bucket, ".")
+}
+
+// suffixBucket returns the bucket of a path relative to an alias root.
+func suffixBucket(suffix string) string {
+	suffix = strings.TrimLeft(filepath.ToSlash(suffix), "/")
+	if i := strings.Index(suffix, "/"); i >= 0 {
+		return suffix[:i]
+	}
+	return suffix
+}
+
+// mirrorBucketMessage is the per-bucket summary of a mirror of alias roots.
+type mirrorBucketMessage struct {
+	Status  string `json:"status"`
+	Bucket  string `json:"bucket"`
+	Objects int64  `json:"objects"`
+	Size    int64  `json:"size"`
+	Removed int64  `json:"removed,omitempty"`
+	Errors  int64  `json:"errors,omitempty"`
+}
+
+// String colorized mirror bucket message
+func (m mirrorBucketMessage) String() string {
+	msg := console.Colorize("MirrorBucket", fmt.Sprintf("%-30s", m.Bucket)) +
+		fmt.Sprintf(" %d object(s), %s", m.Objects, humanize.IBytes(uint64(m.Size)))
+	if m.Removed > 0 {
+		msg += fmt.Sprintf(", %d removed", m.Removed)
+	}
+	if m.Errors > 0 {
+		msg += console.Colorize("MirrorBucketError", fmt.Sprintf(", %d error(s)", m.Errors))
+	}
+	return msg
+}
+
+// JSON jsonified mirror bucket message
+func (m mirrorBucketMessage) JSON() string {
+	m.Status = "bucket"
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(msgBytes)
+}
+
+// bucketReport accounts the transfers of a mirror per bucket.
+type bucketReport struct {
+	mu      sync.Mutex
+	buckets map[string]*mirrorBucketMessage
+	current string // bucket of the last queued object
+}
+
+func newBucketReport() *bucketReport {
+	return &bucketReport{buckets: map[string]*mirrorBucketMessage{}}
+}
+
+func (r *bucketReport) get(bucket string) *mirrorBucketMessage {
+	m, ok := r.buckets[bucket]
+	if !ok {
+		m = &mirrorBucketMessage{Bucket: bucket}
+		r.buckets[bucket] = m
+	}
+	return m
+}
+
+// queued records the bucket of a queued object and returns
+// true when it is the first object queued of that bucket.
+func (r *bucketReport) queued(bucket string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if bucket == r.current {
+		return false
+	}
+	r.current = bucket
+	_, seen := r.buckets[bucket]
+	r.get(bucket)
+	return !seen
+}
+
+// done records the outcome of a transfer described by sURLs.
+func (r *bucketReport) done(sURLs URLs) {
+	var bucket string
+	switch {
+	case sURLs.SourceContent != nil:
+		bucket = suffixBucket(sURLs.SourceContent.URL.Path)
+	case sURLs.TargetContent != nil:
+		bucket = suffixBucket(sURLs.TargetContent.URL.Path)
+	default:
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m := r.get(bucket)
+	switch {
+	case sURLs.Error != nil:
+		m.Errors++
+	case sURLs.SourceContent != nil:
+		m.Objects++
+		m.Size += sURLs.SourceContent.Size
+	default:
+		m.Removed++
+	}
+}
+
+// summary returns the per-bucket messages sorted by bucket name.
+func (r *bucketReport) summary() []mirrorBucketMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	msgs := make([]mirrorBucketMessage, 0, len(r.buckets))
+	for _, m := range r.buckets {
+		msgs = append(msgs, *m)
+	}
+	sort.Slice(msgs, func(i, j int) bool {
+		return msgs[i].Bucket < msgs[j].Bucket
+	})
+	return msgs
+}
@@ -20,6 +20,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"math/rand"
 	"net/http"
 	"path"
@@ -61,16 +62,25 @@ var (
 		},
 		cli.BoolFlag{
 			Name:  "dry-run",
-			Usage: "perform a fake mirror operation",
+			Usage: "print the plan of a mirror operation without performing it",
 		},
 		cli.BoolFlag{
 			Name:  "watch, w",
 			Usage: "watch and synchronize changes",
 		},
+		cli.DurationFlag{
+			Name:  "watch-debounce",
+			Usage: "coalesce watch events for the same object and process them in batches every given duration",
+		},
 		cli.BoolFlag{
 			Name:  "remove",
 			Usage: "remove extraneous object(s) on target",
 		},
+		cli.IntFlag{
+			Name:  "limit-objects",
+			Usage: "maximum number of object(s) copied or removed per second",
+		},
+		activeHoursFlag,
 		cli.StringFlag{
 			Name:  "region",
 			Usage: "specify region when creating new bucket(s) on target",
@@ -80,6 +90,20 @@ var (
 			Name:  "preserve, a",
 			Usage: "preserve file(s)/object(s) attributes and bucket(s) policy/locking configuration(s) on target bucket(s)",
 		},
+		cli.BoolFlag{
+			Name:  "preserve-object-config",
+			Usage: "apply the tags, retention and legal hold of source object(s) on target (object storage only)",
+		},
+		cli.BoolFlag{
+			Name:  "refresh-metadata",
+			Usage: "refresh tags, metadata and storage class of object(s) on target with the same content as source, without copying data",
+		},
+		targetTemplateFlag,
+		tagRouteFlag,
+		cli.BoolFlag{
+			Name:  "verify",
+			Usage: "compare every mirrored object with its source after the copy, failing on any mismatch",
+		},
 		cli.BoolFlag{
 			Name:  "md5",
 			Usage: "force all upload(s) to calculate md5sum checksum",
@@ -101,6 +125,23 @@ var (
 			Name:  "exclude",
 			Usage: "exclude object(s) that match specified object name pattern",
 		},
+		cli.StringSliceFlag{
+			Name:  "include-bucket",
+			Usage: "mirror only bucket(s) that match specified bucket name pattern, when mirroring an alias",
+		},
+		cli.StringSliceFlag{
+			Name:  "exclude-bucket",
+			Usage: "exclude bucket(s) that match specified bucket name pattern, when mirroring an alias",
+		},
+		cli.StringFlag{
+			Name:  "create-buckets",
+			Usage: "create missing target bucket(s) 'bare' or 'with-config' copying versioning, locking, tags and policies",
+			Value: createBucketsBare,
+		},
+		cli.BoolFlag{
+			Name:  "skip-system-buckets",
+			Usage: "exclude system bucket(s) such as .minio.sys, when mirroring an alias",
+		},
 		cli.StringFlag{
 			Name:  "older-than",
 			Usage: "filter object(s) older than value in duration string (e.g. 7d10h31s)",
@@ -121,6 +162,10 @@ var (
 			Name:  "attr",
 			Usage: "add custom metadata for all objects",
 		},
+		cli.StringFlag{
+			Name:  "on-conflict",
+			Usage: "resolve object(s) changed on both sides, one of 'newest', 'largest', 'rename' or 'skip'",
+		},
 		cli.StringFlag{
 			Name:  "monitoring-address",
 			Usage: "if specified, a new prometheus endpoint will be created to report mirroring activity. (eg: localhost:8081)",
@@ -135,7 +180,7 @@ var mirrorCmd = cli.Command{
 	Action:       mainMirror,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        append(append(mirrorFlags, ioFlags...), globalFlags...),
+	Flags:        append(append(append(append(append(mirrorFlags, multipartFlags...), progressFlags...), retryFlags...), ioFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -147,7 +192,8 @@ FLAGS:
   {{end}}
 ENVIRONMENT VARIABLES:
    MC_ENCRYPT:      list of comma delimited prefixes
-   MC_ENCRYPT_KEY:  list of comma delimited prefix=secret values
+   MC_ENCRYPT_KEY:       list of comma delimited prefix=secret values
+   MC_ENCRYPT_KEY_FILE:  file of prefix=secret lines, same as --encrypt-key-file
 
 EXAMPLES:
   01. Mirror a bucket recursively from MinIO cloud storage to a bucket on Amazon S3 cloud storage.
@@ -202,6 +248,63 @@ EXAMPLES:
   16. Cross mirror between sites in a active-active deployment.
       Site-A: {{.Prompt}} {{.HelpName}} --active-active siteA siteB
       Site-B: {{.Prompt}} {{.HelpName}} --active-active siteB siteA
+
+  17. Mirror a shared team bucket, keeping the changed copy on target as 'name.conflict-<timestamp>'
+      instead of overwriting it.
+      {{.Prompt}} {{.HelpName}} --on-conflict rename play/team-share s3/team-share
+
+  18. Continuously mirror a build output folder, uploading a frequently rewritten file at most once every 5 seconds.
+      {{.Prompt}} {{.HelpName}} --watch --watch-debounce 5s ./build play/artifacts
+
+  19. Mirror a folder of large files, uploading them in parts of 512MiB sent 16 at a time.
+      {{.Prompt}} {{.HelpName}} --part-size 512MiB --parallel-parts 16 /mnt/vm-images play/images
+
+  20. Review the plan of a mirror as one JSON object per line, followed by a summary.
+      {{.Prompt}} {{.HelpName}} --dry-run --remove --overwrite --json play/mybucket s3/mybucket
+
+  21. Mirror a bucket, writing a progress record every second to a log file.
+      {{.Prompt}} {{.HelpName}} --quiet --progress-events /var/log/mc-mirror-progress.json play/mybucket s3/mybucket
+
+  22. Migrate millions of small files to a small server, copying at most 200 objects per second.
+      {{.Prompt}} {{.HelpName}} --limit-objects 200 /mnt/small-files myminio/archive
+
+  23. Mirror a bucket over an unreliable link, retrying up to 3 times any object sending no data for 2 minutes.
+      {{.Prompt}} {{.HelpName}} --stall-timeout 2m --retry-attempts 3 play/mybucket s3/mybucket
+
+  24. Mirror a local folder, encrypting each prefix with its own SSE-C key read from a file.
+      {{.Prompt}} cat ~/.mc/sse-keys
+      s3/mybucket/finance/=32byteslongsecretkeymustbegiven1
+      s3/mybucket/hr/=MzJieXRlc2xvbmdzZWNyZXRrZXltdXN0YmVnaXZlbjI=
+      {{.Prompt}} {{.HelpName}} --encrypt-key-file ~/.mc/sse-keys /mnt/data s3/mybucket
+
+  25. Mirror a bucket to another object storage, keeping the tags, retention and legal hold of every object.
+      {{.Prompt}} {{.HelpName}} --preserve-object-config play/mybucket s3/mybucket
+
+  26. Mirror a local folder to a bucket partitioned by the date of the files, only uploading new files on every run.
+      {{.Prompt}} {{.HelpName}} --target-template '{{"{{.Year}}/{{.Month}}/{{.Day}}/{{.SourceBase}}"}}' /var/log/events/ s3/datalake/events/
+
+  27. Mirror a bucket, then compare every copied object with its source, exiting with an error on any mismatch.
+      {{.Prompt}} {{.HelpName}} --verify play/mybucket s3/mybucket
+
+  28. Mirror all the buckets of a site except the scratch and log buckets, printing a summary per bucket.
+      {{.Prompt}} {{.HelpName}} --exclude-bucket "scratch-*" --exclude-bucket "*-logs" --skip-system-buckets site1/ site2/
+
+  29. Mirror only the buckets of the finance department from one site to another.
+      {{.Prompt}} {{.HelpName}} --include-bucket "finance-*" site1/ site2/
+
+  30. Migrate all buckets to a new site, creating the missing buckets with the versioning, object lock,
+      tags and policies of their source bucket.
+      {{.Prompt}} {{.HelpName}} --create-buckets with-config site1/ site2/
+
+  31. Mirror a bucket in one pass, sending objects tagged 'class=pii' below 'restricted/' of the target.
+      {{.Prompt}} {{.HelpName}} --route-by-tag class=pii:restricted/ s3/raw s3/curated
+
+  32. Migrate a bucket off-hours only, pausing between 6 AM and 10 PM and resuming where it stopped.
+      {{.Prompt}} {{.HelpName}} --active-hours 22:00-06:00 --watch play/mybucket s3/mybucket
+
+  33. Fix the tags, metadata and storage class of objects left behind by an earlier migration,
+      without copying their data again.
+      {{.Prompt}} {{.HelpName}} --refresh-metadata play/mybucket s3/mybucket
 `,
 }
 
@@ -252,6 +355,24 @@ type mirrorJob struct {
 	TotalObjects int64
 	TotalBytes   int64
 
+	// actions planned by a dry-run
+	plan mirrorPlan
+
+	// progress records, nil unless requested
+	events *progressEvents
+
+	// caps the objects processed per second, nil if unlimited
+	limiter *objectLimiter
+
+	// outcome of --verify
+	verify *verifyReport
+
+	// outcome of --refresh-metadata
+	refresh *refreshReport
+
+	// transfers per bucket, nil unless mirroring alias roots
+	buckets *bucketReport
+
 	sourceURL string
 	targetURL string
 
@@ -282,6 +403,14 @@ func (m mirrorMessage) JSON() string {
 	return string(mirrorMessageBytes)
 }
 
+// printPlan prints and records a planned action of a dry-run.
+func (mj *mirrorJob) printPlan(m mirrorPlanMessage) {
+	mj.plan.add(m)
+	// Only one of them prints, depending on the kind of status.
+	mj.status.PrintMsg(m)
+	mj.status.Println(m.String())
+}
+
 func (mj *mirrorJob) doCreateBucket(ctx context.Context, sURLs URLs) URLs {
 	if mj.opts.isFake {
 		return sURLs.WithError(nil)
@@ -330,6 +459,11 @@ func (mj *mirrorJob) doDeleteBucket(ctx context.Context, sURLs URLs) URLs {
 // doRemove - removes files on target.
 func (mj *mirrorJob) doRemove(ctx context.Context, sURLs URLs) URLs {
 	if mj.opts.isFake {
+		mj.printPlan(mirrorPlanMessage{
+			Action: planDelete,
+			Key:    filepath.ToSlash(filepath.Join(sURLs.TargetAlias, sURLs.TargetContent.URL.Path)),
+			Reason: planReason(sURLs.diff),
+		})
 		return sURLs.WithError(nil)
 	}
 
@@ -364,7 +498,7 @@ func (mj *mirrorJob) doRemove(ctx context.Context, sURLs URLs) URLs {
 }
 
 // doMirror - Mirror an object to multiple destination. URLs status contains a copy of sURLs and error if any.
-func (mj *mirrorJob) doMirrorWatch(ctx context.Context, targetPath string, tgtSSE encrypt.ServerSide, sURLs URLs) URLs {
+func (mj *mirrorJob) doMirrorWatch(ctx context.Context, targetPath string, tgtSSEs []encrypt.ServerSide, sURLs URLs) URLs {
 	shouldQueue := false
 	if !mj.opts.isOverwrite && !mj.opts.activeActive {
 		targetClient, err := newClient(targetPath)
@@ -372,7 +506,7 @@ func (mj *mirrorJob) doMirrorWatch(ctx context.Context, targetPath string, tgtSS
 			// cannot create targetclient
 			return sURLs.WithError(err)
 		}
-		_, err = targetClient.Stat(ctx, StatOptions{sse: tgtSSE})
+		_, _, err = statWithSSEs(ctx, targetClient, StatOptions{}, tgtSSEs)
 		if err == nil {
 			if !sURLs.SourceContent.RetentionEnabled && !sURLs.SourceContent.LegalHoldEnabled {
 				return sURLs.WithError(probe.NewError(ObjectAlreadyExists{}))
@@ -386,6 +520,7 @@ func (mj *mirrorJob) doMirrorWatch(ctx context.Context, targetPath string, tgtSS
 		mj.status.Add(sURLs.SourceContent.Size)
 		mj.status.SetTotal(mj.status.Get()).Update()
 		mj.status.AddCounts(1)
+		mj.events.addTotal(sURLs.SourceContent.Size, 1)
 		sURLs.TotalSize = mj.status.Get()
 		sURLs.TotalCount = mj.status.GetCounts()
 		return mj.doMirror(ctx, sURLs)
@@ -416,11 +551,22 @@ func (mj *mirrorJob) doMirror(ctx context.Context, sURLs URLs) URLs {
 		return sURLs.WithError(sURLs.Error.Trace())
 	}
 
+	if sURLs.refresh {
+		return mj.refreshObject(ctx, sURLs)
+	}
+
 	// For a fake mirror make sure we update respective progress bars
 	// and accounting readers under relevant conditions.
 	if mj.opts.isFake {
 		if sURLs.SourceContent != nil {
 			mj.status.Add(sURLs.SourceContent.Size)
+			mj.printPlan(mirrorPlanMessage{
+				Action: planCopy,
+				Key:    filepath.ToSlash(filepath.Join(sURLs.TargetAlias, sURLs.TargetContent.URL.Path)),
+				Source: filepath.ToSlash(filepath.Join(sURLs.SourceAlias, sURLs.SourceContent.URL.Path)),
+				Size:   sURLs.SourceContent.Size,
+				Reason: planReason(sURLs.diff),
+			})
 		}
 		mj.status.Update()
 		return sURLs.WithError(nil)
@@ -437,7 +583,7 @@ func (mj *mirrorJob) doMirror(ctx context.Context, sURLs URLs) URLs {
 	// Initialize target metadata.
 	sURLs.TargetContent.Metadata = make(map[string]string)
 
-	if mj.opts.storageClass != "" {
+	if mj.opts.storageClass != "" && sURLs.TargetContent.StorageClass == "" {
 		sURLs.TargetContent.StorageClass = mj.opts.storageClass
 	}
 
@@ -457,6 +603,7 @@ func (mj *mirrorJob) doMirror(ctx context.Context, sURLs URLs) URLs {
 
 	sourcePath := filepath.ToSlash(filepath.Join(sourceAlias, sourceURL.Path))
 	targetPath := filepath.ToSlash(filepath.Join(targetAlias, targetURL.Path))
+	mj.events.setCurrent(sourcePath)
 	mj.status.PrintMsg(mirrorMessage{
 		Source:     sourcePath,
 		Target:     targetPath,
@@ -466,9 +613,30 @@ func (mj *mirrorJob) doMirror(ctx context.Context, sURLs URLs) URLs {
 	})
 	sURLs.MD5 = mj.opts.md5
 	sURLs.DisableMultipart = mj.opts.disableMultipart
+	sURLs.MultipartSize = mj.opts.multipartSize
+	sURLs.MultipartThreads = mj.opts.multipartThreads
+
+	if sURLs.conflictContent != nil {
+		if err := mj.renameConflict(ctx, sURLs); err != nil {
+			return sURLs.WithError(err)
+		}
+	}
 
 	now := time.Now()
-	ret := uploadSourceToTargetURL(ctx, sURLs, mj.status, mj.opts.encKeyDB, mj.opts.isMetadata, false)
+	var ret URLs
+	err := withTransferRetry(ctx, "mirror", sourcePath, mj.status, func(progress io.Reader) *probe.Error {
+		return withStallWatchdog(ctx, sourcePath, stallTimeout(sURLs, false), progress, func(ctx context.Context, progress io.Reader) *probe.Error {
+			ret = uploadSourceToTargetURL(ctx, sURLs, progress, mj.opts.encKeyDB, mj.opts.isMetadata, false)
+			return ret.Error
+		})
+	})
+	ret = ret.WithError(err)
+	if ret.Error == nil && mj.opts.preserveObjectConfig {
+		mj.preserveObjectConfig(ctx, sURLs)
+	}
+	if ret.Error == nil && mj.opts.verify {
+		mj.verifyObject(ctx, sURLs)
+	}
 	if ret.Error == nil {
 		durationMs := time.Since(now).Milliseconds()
 		mirrorReplicationDurations.With(prometheus.Labels{"object_size": convertSizeToTag(sURLs.SourceContent.Size)}).Observe(float64(durationMs))
@@ -476,6 +644,46 @@ func (mj *mirrorJob) doMirror(ctx context.Context, sURLs URLs) URLs {
 	return ret
 }
 
+// renameConflict preserves the existing target object under a
+// conflict name before it is overwritten by the source object.
+func (mj *mirrorJob) renameConflict(ctx context.Context, sURLs URLs) *probe.Error {
+	targetURL := sURLs.TargetContent.URL
+	targetPath := filepath.ToSlash(filepath.Join(sURLs.TargetAlias, targetURL.Path))
+	srcSSEs := getReadSSEs(targetPath, mj.opts.encKeyDB[sURLs.TargetAlias])
+	tgtSSE := getTargetSSE(targetPath, mj.opts.encKeyDB[sURLs.TargetAlias])
+
+	conflictURL := targetURL
+	conflictURL.Path = conflictName(targetURL.Path, UTCNow())
+
+	clnt, err := newClientFromAlias(sURLs.TargetAlias, conflictURL.String())
+	if err != nil {
+		return err.Trace(sURLs.TargetAlias, conflictURL.String())
+	}
+
+	// The existing object may not be rotated to the new key yet.
+	srcSSE := srcSSEs[0]
+	if len(srcSSEs) > 1 {
+		targetClnt, err := newClientFromAlias(sURLs.TargetAlias, targetURL.String())
+		if err != nil {
+			return err.Trace(sURLs.TargetAlias, targetURL.String())
+		}
+		if _, srcSSE, err = statWithSSEs(ctx, targetClnt, StatOptions{}, srcSSEs); err != nil {
+			return err.Trace(targetURL.String())
+		}
+	}
+
+	opts := CopyOptions{
+		size:     sURLs.conflictContent.Size,
+		srcSSE:   srcSSE,
+		tgtSSE:   tgtSSE,
+		metadata: map[string]string{},
+	}
+	if err = clnt.Copy(ctx, targetURL.Path, opts, nil); err != nil {
+		return err.Trace(targetURL.String(), conflictURL.String())
+	}
+	return nil
+}
+
 // Update progress status
 func (mj *mirrorJob) monitorMirrorStatus(cancel context.CancelFunc) (errDuringMirror bool) {
 	// now we want to start the progress bar
@@ -495,12 +703,18 @@ func (mj *mirrorJob) monitorMirrorStatus(cancel context.CancelFunc) (errDuringMi
 		// Update prometheus fields
 		mirrorTotalOps.Inc()
 
+		if mj.buckets != nil && !mj.opts.isFake {
+			mj.buckets.done(sURLs)
+		}
+
 		if sURLs.Error != nil {
 			var ignoreErr bool
 
 			switch {
 			case sURLs.SourceContent != nil:
 				if isErrIgnored(sURLs.Error) {
+					warningIf(warningSkipped, sURLs.Error.Trace(sURLs.SourceContent.URL.String()),
+						fmt.Sprintf("Skipped `%s`.", sURLs.SourceContent.URL.String()))
 					ignoreErr = true
 				} else {
 					errorIf(sURLs.Error.Trace(sURLs.SourceContent.URL.String()),
@@ -536,8 +750,11 @@ func (mj *mirrorJob) monitorMirrorStatus(cancel context.CancelFunc) (errDuringMi
 		}
 
 		if sURLs.SourceContent != nil {
-			mirrorTotalUploadedBytes.Add(float64(sURLs.SourceContent.Size))
-		} else if sURLs.TargetContent != nil {
+			if !sURLs.refresh {
+				mirrorTotalUploadedBytes.Add(float64(sURLs.SourceContent.Size))
+			}
+			mj.events.objectDone()
+		} else if sURLs.TargetContent != nil && !mj.opts.isFake {
 			// Construct user facing message and path.
 			targetPath := filepath.ToSlash(filepath.Join(sURLs.TargetAlias, sURLs.TargetContent.URL.Path))
 			mj.status.PrintMsg(rmMessage{Key: targetPath})
@@ -580,13 +797,44 @@ func (mj *mirrorJob) watchMirrorEvents(ctx context.Context, events []EventInfo)
 		if matchExcludeOptions(mj.opts.excludeOptions, sourceSuffix) {
 			continue
 		}
+		// Skip the object, if its bucket is filtered out
+		if mj.opts.bucketFilter.skip(sourceSuffix) {
+			continue
+		}
+
+		if mj.opts.targetTemplate != nil {
+			sourceModTime, _ := time.Parse(time.RFC3339Nano, event.Time)
+			name, err := mj.opts.targetTemplate.render(ctx, sourceAlias, filepath.ToSlash(sourceSuffix), &ClientContent{
+				URL:      *sourceURL,
+				Time:     sourceModTime,
+				Metadata: event.UserMetadata,
+			})
+			if err != nil {
+				errorIf(err.Trace(eventPath), "Unable to name the target of `"+eventPath+"`.")
+				continue
+			}
+			sourceSuffix = name
+		}
+
+		var storageClass string
+		if len(mj.opts.tagRoutes) > 0 && strings.HasPrefix(string(event.Type), "s3:ObjectCreated:") {
+			route, err := mj.opts.tagRoutes.lookup(ctx, sourceAlias, &ClientContent{URL: *sourceURL})
+			if err != nil {
+				errorIf(err.Trace(eventPath), "Unable to route `"+eventPath+"` by its tags.")
+				continue
+			}
+			if route != nil {
+				sourceSuffix = route.rename(filepath.ToSlash(sourceSuffix))
+				storageClass = route.storageClass
+			}
+		}
 
 		targetPath := urlJoinPath(mj.targetURL, sourceSuffix)
 
 		// newClient needs the unexpanded  path, newCLientURL needs the expanded path
 		targetAlias, expandedTargetPath, _ := mustExpandAlias(targetPath)
 		targetURL := newClientURL(expandedTargetPath)
-		tgtSSE := getSSE(targetPath, mj.opts.encKeyDB[targetAlias])
+		tgtSSEs := getReadSSEs(targetPath, mj.opts.encKeyDB[targetAlias])
 
 		if strings.HasPrefix(string(event.Type), "s3:ObjectCreated:") {
 			sourceModTime, _ := time.Parse(time.RFC3339Nano, event.Time)
@@ -601,9 +849,11 @@ func (mj *mirrorJob) watchMirrorEvents(ctx context.Context, events []EventInfo)
 					Metadata:         event.UserMetadata,
 				},
 				TargetAlias:      targetAlias,
-				TargetContent:    &ClientContent{URL: *targetURL},
+				TargetContent:    &ClientContent{URL: *targetURL, StorageClass: storageClass},
 				MD5:              mj.opts.md5,
 				DisableMultipart: mj.opts.disableMultipart,
+				MultipartSize:    mj.opts.multipartSize,
+				MultipartThreads: mj.opts.multipartThreads,
 				encKeyDB:         mj.opts.encKeyDB,
 			}
 			if mj.opts.activeActive &&
@@ -614,8 +864,10 @@ func (mj *mirrorJob) watchMirrorEvents(ctx context.Context, events []EventInfo)
 				// to avoid copying it.
 				continue
 			}
+			mj.opts.activeHours.wait(ctx)
+			mj.limiter.wait()
 			mj.parallel.queueTask(func() URLs {
-				return mj.doMirrorWatch(ctx, targetPath, tgtSSE, mirrorURL)
+				return mj.doMirrorWatch(ctx, targetPath, tgtSSEs, mirrorURL)
 			}, mirrorURL.SourceContent.Size)
 		} else if event.Type == notification.ObjectRemovedDelete {
 			if targetAlias != "" && strings.Contains(event.UserAgent, uaMirrorAppName+":"+targetAlias) {
@@ -629,11 +881,15 @@ func (mj *mirrorJob) watchMirrorEvents(ctx context.Context, events []EventInfo)
 				TargetContent:    &ClientContent{URL: *targetURL},
 				MD5:              mj.opts.md5,
 				DisableMultipart: mj.opts.disableMultipart,
+				MultipartSize:    mj.opts.multipartSize,
+				MultipartThreads: mj.opts.multipartThreads,
 				encKeyDB:         mj.opts.encKeyDB,
 			}
 			mirrorURL.TotalCount = mj.status.GetCounts()
 			mirrorURL.TotalSize = mj.status.Get()
 			if mirrorURL.TargetContent != nil && (mj.opts.isRemove || mj.opts.activeActive) {
+				mj.opts.activeHours.wait(ctx)
+				mj.limiter.wait()
 				mj.parallel.queueTask(func() URLs {
 					return mj.doRemove(ctx, mirrorURL)
 				}, 0)
@@ -661,17 +917,50 @@ func (mj *mirrorJob) watchMirrorEvents(ctx context.Context, events []EventInfo)
 	}
 }
 
+// coalesceEvents keeps only the last event received for each path,
+// so that an object written many times in a row is processed once.
+// Events are returned in the order of their last occurrence.
+func coalesceEvents(events []EventInfo) []EventInfo {
+	last := make(map[string]int, len(events))
+	for i, event := range events {
+		last[event.Path] = i
+	}
+	coalesced := make([]EventInfo, 0, len(last))
+	for i, event := range events {
+		if last[event.Path] == i {
+			coalesced = append(coalesced, event)
+		}
+	}
+	return coalesced
+}
+
 // this goroutine will watch for notifications, and add modified objects to the queue
 func (mj *mirrorJob) watchMirror(ctx context.Context) {
 	defer mj.watcher.Stop()
 
+	// With --watch-debounce, events are held for the debounce window
+	// starting at the first pending event and processed as one batch.
+	var pending []EventInfo
+	var flushCh <-chan time.Time
+
 	for {
 		select {
 		case events, ok := <-mj.watcher.Events():
 			if !ok {
+				mj.watchMirrorEvents(ctx, coalesceEvents(pending))
 				return
 			}
-			mj.watchMirrorEvents(ctx, events)
+			if mj.opts.watchDebounce <= 0 {
+				mj.watchMirrorEvents(ctx, events)
+				continue
+			}
+			pending = append(pending, events...)
+			if flushCh == nil {
+				flushCh = time.After(mj.opts.watchDebounce)
+			}
+		case <-flushCh:
+			mj.watchMirrorEvents(ctx, coalesceEvents(pending))
+			pending, flushCh = nil, nil
 		case err, ok := <-mj.watcher.Errors():
 			if !ok {
 				return
@@ -723,6 +1012,13 @@ func (mj *mirrorJob) startMirror(ctx context.Context) {
 
 			if sURLs.SourceContent != nil {
 				mj.status.Add(sURLs.SourceContent.Size)
+				mj.events.addTotal(sURLs.SourceContent.Size, 1)
+
+				if mj.buckets != nil && !globalQuiet && !globalJSON {
+					if bucket := suffixBucket(sURLs.SourceContent.URL.Path); mj.buckets.queued(bucket) {
+						mj.status.Println(console.Colorize("MirrorBucket", "Mirroring bucket `"+bucket+"`..."))
+					}
+				}
 			}
 
 			mj.status.SetTotal(mj.status.Get()).Update()
@@ -734,10 +1030,14 @@ func (mj *mirrorJob) startMirror(ctx context.Context) {
 			sURLs.TotalSize = mj.status.Get()
 
 			if sURLs.SourceContent != nil {
+				mj.opts.activeHours.wait(ctx)
+				mj.limiter.wait()
 				mj.parallel.queueTask(func() URLs {
 					return mj.doMirror(ctx, sURLs)
 				}, sURLs.SourceContent.Size)
 			} else if sURLs.TargetContent != nil && mj.opts.isRemove {
+				mj.opts.activeHours.wait(ctx)
+				mj.limiter.wait()
 				mj.parallel.queueTask(func() URLs {
 					return mj.doRemove(ctx, sURLs)
 				}, 0)
@@ -779,10 +1079,28 @@ func (mj *mirrorJob) mirror(ctx context.Context) bool {
 		close(mj.statusCh)
 	}()
 
-	return mj.monitorMirrorStatus(cancel)
+	errDuringMirror := mj.monitorMirrorStatus(cancel)
+	mj.limiter.stop()
+	if mj.opts.isFake {
+		printMsg(mj.plan.summary())
+	}
+	printStallSummary()
+	if mj.buckets != nil && !mj.opts.isFake && !mj.opts.isWatch {
+		for _, msg := range mj.buckets.summary() {
+			printMsg(msg)
+		}
+	}
+	if mj.opts.verify {
+		printMsg(mj.verify.summary())
+		errDuringMirror = errDuringMirror || mj.verify.hasFailures()
+	}
+	if mj.opts.refreshMetadata && !mj.opts.isFake {
+		printMsg(mj.refresh.summary())
+	}
+	return errDuringMirror
 }
 
-func newMirrorJob(srcURL, dstURL string, opts mirrorOptions) *mirrorJob {
+func newMirrorJob(srcURL, dstURL string, opts mirrorOptions, events *progressEvents) *mirrorJob {
 	mj := mirrorJob{
 		stopCh: make(chan struct{}),
 
@@ -791,18 +1109,23 @@ func newMirrorJob(srcURL, dstURL string, opts mirrorOptions) *mirrorJob {
 		opts:      opts,
 		statusCh:  make(chan URLs),
 		watcher:   NewWatcher(UTCNow()),
+		events:    events,
+		limiter:   newObjectLimiter(opts.limitObjects),
+		verify:    &verifyReport{},
+		refresh:   &refreshReport{},
 	}
 
 	mj.parallel = newParallelManager(mj.statusCh)
+	hook := withProgressEvents(mj.parallel, events)
 
 	// we'll define the status to use here,
 	// do we want the quiet status? or the progressbar
 	if globalQuiet {
-		mj.status = NewQuietStatus(mj.parallel)
+		mj.status = NewQuietStatus(hook)
 	} else if globalJSON {
-		mj.status = NewQuietStatus(mj.parallel)
+		mj.status = NewQuietStatus(hook)
 	} else {
-		mj.status = NewProgressStatus(mj.parallel)
+		mj.status = NewProgressStatus(hook)
 	}
 
 	return &mj
@@ -860,7 +1183,7 @@ func getEventPathURLWin(srcURL, eventPath string) string {
 }
 
 // runMirror - mirrors all buckets to another S3 server
-func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, encKeyDB map[string][]prefixSSEPair) bool {
+func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, encKeyDB map[string][]prefixSSEPair, events *progressEvents) bool {
 	// Parse metadata.
 	userMetadata := make(map[string]string)
 	if cli.String("attr") != "" {
@@ -884,6 +1207,10 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 	isWatch := cli.Bool("watch") || cli.Bool("multi-master") || cli.Bool("active-active")
 	isRemove := cli.Bool("remove")
 
+	// Validated by checkMirrorSyntax.
+	partSize, parallelParts, _ := parseMultipartFlags(cli)
+	activeHours, _ := parseActiveHours(cli.String("active-hours"))
+
 	// preserve is also expected to be overwritten if necessary
 	isMetadata := cli.Bool("a") || isWatch || len(userMetadata) > 0
 	isOverwrite = isOverwrite || isMetadata
@@ -904,16 +1231,41 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 		userMetadata:     userMetadata,
 		encKeyDB:         encKeyDB,
 		activeActive:     isWatch,
+		onConflict:       conflictPolicy(cli.String("on-conflict")),
+		watchDebounce:    cli.Duration("watch-debounce"),
+		multipartSize:    partSize,
+		multipartThreads: parallelParts,
+		limitObjects:     cli.Int("limit-objects"),
+		activeHours:      activeHours,
+
+		preserveObjectConfig: cli.Bool("preserve-object-config"),
+		verify:               cli.Bool("verify"),
+		createBuckets:        cli.String("create-buckets"),
+		refreshMetadata:      cli.Bool("refresh-metadata"),
+		bucketFilter: bucketFilter{
+			include:    cli.StringSlice("include-bucket"),
+			exclude:    cli.StringSlice("exclude-bucket"),
+			skipSystem: cli.Bool("skip-system-buckets"),
+		},
 	}
+	if text := cli.String("target-template"); text != "" {
+		// Validated by checkMirrorSyntax.
+		mopts.targetTemplate, _ = parseTargetTemplate(text)
+	}
+	// Validated by checkMirrorSyntax.
+	mopts.tagRoutes, _ = parseTagRoutes(cli.StringSlice("route-by-tag"))
 
 	// Create a new mirror job and execute it
-	mj := newMirrorJob(srcURL, dstURL, mopts)
+	mj := newMirrorJob(srcURL, dstURL, mopts, events)
 
 	preserve := cli.Bool("preserve")
 
 	createDstBuckets := dstClt.GetURL().Type == objectStorage && dstClt.GetURL().Path == string(dstClt.GetURL().Separator)
 	mirrorSrcBuckets := srcClt.GetURL().Type == objectStorage && srcClt.GetURL().Path == string(srcClt.GetURL().Separator)
 	mirrorBucketsToBuckets := mirrorSrcBuckets && createDstBuckets
+	if mirrorSrcBuckets {
+		mj.buckets = newBucketReport()
+	}
 
 	if mirrorSrcBuckets || createDstBuckets {
 		// Synchronize buckets using dirDifference function
@@ -928,6 +1280,16 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 
 			if d.Diff == differInSecond {
 				diffBucket := strings.TrimPrefix(d.SecondURL, dstClt.GetURL().String())
+				if mj.opts.bucketFilter.skip(diffBucket) {
+					continue
+				}
+				if isFake && isRemove {
+					mj.printPlan(mirrorPlanMessage{
+						Action: planDeleteBucket,
+						Key:    path.Join(dstURL, diffBucket),
+						Reason: planReason(d.Diff),
+					})
+				}
 				if !isFake && isRemove {
 					aliasedDstBucket := path.Join(dstURL, diffBucket)
 					err := deleteBucket(ctx, aliasedDstBucket, false)
@@ -937,6 +1299,9 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 			}
 
 			sourceSuffix := strings.TrimPrefix(d.FirstURL, srcClt.GetURL().String())
+			if mj.opts.bucketFilter.skip(sourceSuffix) {
+				continue
+			}
 
 			newSrcURL := path.Join(srcURL, sourceSuffix)
 			newTgtURL := path.Join(dstURL, sourceSuffix)
@@ -944,6 +1309,11 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 			newSrcClt, _ := newClient(newSrcURL)
 			newDstClt, _ := newClient(newTgtURL)
 
+			// Only the objects existing on both sides are refreshed.
+			if d.Diff == differInFirst && mj.opts.refreshMetadata {
+				continue
+			}
+
 			if d.Diff == differInFirst {
 				var (
 					withLock bool
@@ -959,15 +1329,27 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 					}
 				}
 
+				if mj.opts.isFake {
+					mj.printPlan(mirrorPlanMessage{
+						Action: planCreateBucket,
+						Key:    newTgtURL,
+						Source: newSrcURL,
+						Reason: planReason(d.Diff),
+					})
+					continue
+				}
+
+				if mj.opts.createBuckets == createBucketsWithConfig && mirrorBucketsToBuckets {
+					errorIf(mj.createBucketWithConfig(ctx, newSrcClt, newDstClt, cli.String("region")),
+						"Unable to create bucket at `"+newTgtURL+"`.")
+					continue
+				}
+
 				mj.status.PrintMsg(mirrorMessage{
 					Source: newSrcURL,
 					Target: newTgtURL,
 				})
 
-				if mj.opts.isFake {
-					continue
-				}
-
 				// Bucket only exists in the source, create the same bucket in the destination
 				if err := newDstClt.MakeBucket(ctx, cli.String("region"), false, withLock); err != nil {
 					errorIf(err, "Unable to create bucket at `"+newTgtURL+"`.")
@@ -1011,6 +1393,18 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 func mainMirror(cliCtx *cli.Context) error {
 	// Additional command specific theme customization.
 	console.SetColor("Mirror", color.New(color.FgGreen, color.Bold))
+	console.SetColor("Plan", color.New(color.FgYellow, color.Bold))
+	console.SetColor("PlanSize", color.New(color.FgYellow))
+	console.SetColor("PlanReason", color.New(color.FgCyan))
+	console.SetColor("Stall", color.New(color.FgYellow, color.Bold))
+	console.SetColor("ObjectConfigError", color.New(color.FgRed, color.Bold))
+	console.SetColor("Verify", color.New(color.FgGreen))
+	console.SetColor("VerifyError", color.New(color.FgRed, color.Bold))
+	console.SetColor("MirrorBucket", color.New(color.FgCyan, color.Bold))
+	console.SetColor("MirrorBucketError", color.New(color.FgRed, color.Bold))
+	console.SetColor("ActiveHours", color.New(color.FgYellow))
+	console.SetColor("Refresh", color.New(color.FgGreen))
+	console.SetColor("RefreshSkipped", color.New(color.FgYellow))
 
 	ctx, cancelMirror := context.WithCancel(globalContext)
 	defer cancelMirror()
@@ -1031,13 +1425,18 @@ func mainMirror(cliCtx *cli.Context) error {
 		}()
 	}
 
+	events, err := newProgressEvents(cliCtx.String("progress-events"))
+	fatalIf(err, "Unable to open progress events output.")
+	events.start()
+	defer events.stop()
+
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
 	for {
 		select {
 		case <-ctx.Done():
 			return exitStatus(globalErrorExitStatus)
 		default:
-			errorDetected := runMirror(ctx, srcURL, tgtURL, cliCtx, encKeyDB)
+			errorDetected := runMirror(ctx, srcURL, tgtURL, cliCtx, encKeyDB, events)
 			if cliCtx.Bool("watch") || cliCtx.Bool("multi-master") || cliCtx.Bool("active-active") {
 				mirrorRestarts.Inc()
 				time.Sleep(time.Duration(r.Float64() * float64(2*time.Second)))
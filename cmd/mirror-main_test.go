@@ -0,0 +1,49 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/trinet2005/oss-go-sdk/pkg/notification"
+)
+
+func TestCoalesceEvents(t *testing.T) {
+	created := notification.EventType("s3:ObjectCreated:Put")
+	testCases := []struct {
+		events   []EventInfo
+		expected []EventInfo
+	}{
+		{nil, []EventInfo{}},
+		{
+			[]EventInfo{{Path: "a", Size: 1, Type: created}, {Path: "b", Type: created}, {Path: "a", Size: 2, Type: created}},
+			[]EventInfo{{Path: "b", Type: created}, {Path: "a", Size: 2, Type: created}},
+		},
+		{
+			[]EventInfo{{Path: "a", Type: created}, {Path: "a", Type: notification.ObjectRemovedDelete}},
+			[]EventInfo{{Path: "a", Type: notification.ObjectRemovedDelete}},
+		},
+	}
+
+	for i, testCase := range testCases {
+		if got := coalesceEvents(testCase.events); !reflect.DeepEqual(got, testCase.expected) {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.expected, got)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+
+	json "github.com/minio/colorjson"
+	minio "github.com/trinet2005/oss-go-sdk"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+// Object configurations preserved by 'mirror --preserve-object-config'.
+const (
+	objectConfigTags      = "tags"
+	objectConfigRetention = "retention"
+	objectConfigLegalHold = "legal-hold"
+)
+
+// objectConfigErrorMessage reports an object configuration
+// which could not be preserved on the target.
+type objectConfigErrorMessage struct {
+	Status string `json:"status"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Config string `json:"config"`
+	Error  string `json:"error"`
+}
+
+// String colorized object configuration error message
+func (m objectConfigErrorMessage) String() string {
+	return console.Colorize("ObjectConfigError", fmt.Sprintf("Unable to preserve %s of `%s` on `%s`: %s", m.Config, m.Source, m.Target, m.Error))
+}
+
+// JSON jsonified object configuration error message
+func (m objectConfigErrorMessage) JSON() string {
+	m.Status = "error"
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(msgBytes)
+}
+
+// isObjectConfigNotFound returns true if the error tells
+// that an object has no such configuration.
+func isObjectConfigNotFound(err *probe.Error) bool {
+	switch minio.ToErrorResponse(err.ToGoError()).Code {
+	case "NoSuchTagSet", "NoSuchObjectLockConfiguration", "ObjectLockConfigurationNotFoundError":
+		return true
+	}
+	return false
+}
+
+// preserveObjectConfig applies the tags, retention and legal hold of a
+// mirrored source object to its target, reporting every failure without
+// failing the mirror of the object.
+func (mj *mirrorJob) preserveObjectConfig(ctx context.Context, sURLs URLs) {
+	sourcePath := filepath.ToSlash(filepath.Join(sURLs.SourceAlias, sURLs.SourceContent.URL.Path))
+	targetPath := filepath.ToSlash(filepath.Join(sURLs.TargetAlias, sURLs.TargetContent.URL.Path))
+	report := func(config string, err *probe.Error) {
+		if err == nil || isObjectConfigNotFound(err) {
+			return
+		}
+		msg := objectConfigErrorMessage{
+			Source: sourcePath,
+			Target: targetPath,
+			Config: config,
+			Error:  err.ToGoError().Error(),
+		}
+		if globalJSON {
+			printMsg(msg)
+			return
+		}
+		mj.status.Println(msg.String())
+	}
+
+	srcClnt, err := newClientFromAlias(sURLs.SourceAlias, sURLs.SourceContent.URL.String())
+	if err != nil {
+		report(objectConfigTags, err)
+		return
+	}
+	tgtClnt, err := newClientFromAlias(sURLs.TargetAlias, sURLs.TargetContent.URL.String())
+	if err != nil {
+		report(objectConfigTags, err)
+		return
+	}
+	versionID := sURLs.SourceContent.VersionID
+
+	tags, err := srcClnt.GetTags(ctx, versionID)
+	if err == nil && len(tags) > 0 {
+		values := url.Values{}
+		for k, v := range tags {
+			values.Set(k, v)
+		}
+		err = tgtClnt.SetTags(ctx, "", values.Encode())
+	}
+	report(objectConfigTags, err)
+
+	mode, until, err := srcClnt.GetObjectRetention(ctx, versionID)
+	if err == nil && mode.IsValid() {
+		err = tgtClnt.PutObjectRetention(ctx, "", mode, until, false)
+	}
+	report(objectConfigRetention, err)
+
+	hold, err := srcClnt.GetObjectLegalHold(ctx, versionID)
+	if err == nil && hold == minio.LegalHoldEnabled {
+		err = tgtClnt.PutObjectLegalHold(ctx, "", hold)
+	}
+	report(objectConfigLegalHold, err)
+}
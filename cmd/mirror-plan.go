@@ -0,0 +1,150 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/dustin/go-humanize"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+// Actions of a mirror plan, printed by 'mirror --dry-run'.
+const (
+	planCopy         = "copy"
+	planDelete       = "delete"
+	planCreateBucket = "create-bucket"
+	planDeleteBucket = "delete-bucket"
+	planRefresh      = "refresh"
+)
+
+// planReason returns a user facing reason of a planned action.
+func planReason(d differType) string {
+	switch d {
+	case differInFirst:
+		return "missing on target"
+	case differInSecond:
+		return "missing on source"
+	case differInSize:
+		return "size differs"
+	case differInMetadata:
+		return "metadata differs"
+	case differInAASourceMTime:
+		return "source modified"
+	}
+	return "changed"
+}
+
+// mirrorPlanMessage is one planned action of a dry-run mirror,
+// printed as a single line of JSON in JSON mode.
+type mirrorPlanMessage struct {
+	Status string `json:"status"`
+	Action string `json:"action"`
+	Key    string `json:"key"`
+	Source string `json:"source,omitempty"`
+	Size   int64  `json:"size"`
+	Reason string `json:"reason"`
+}
+
+// String colorized mirror plan message
+func (m mirrorPlanMessage) String() string {
+	msg := console.Colorize("Plan", fmt.Sprintf("%-13s", m.Action)) + " "
+	if m.Source != "" {
+		msg += fmt.Sprintf("`%s` -> ", m.Source)
+	}
+	msg += fmt.Sprintf("`%s`", m.Key)
+	if m.Action == planCopy {
+		msg += " " + console.Colorize("PlanSize", humanize.IBytes(uint64(m.Size)))
+	}
+	return msg + " " + console.Colorize("PlanReason", "("+m.Reason+")")
+}
+
+// JSON jsonified mirror plan message
+func (m mirrorPlanMessage) JSON() string {
+	m.Status = "plan"
+	planMessageBytes, e := json.Marshal(m)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(planMessageBytes)
+}
+
+// mirrorPlan accumulates the planned actions of a dry-run mirror.
+type mirrorPlan struct {
+	copies    int64
+	copyBytes int64
+	deletes   int64
+	buckets   int64
+	refreshes int64
+}
+
+// add records a planned action.
+func (p *mirrorPlan) add(m mirrorPlanMessage) {
+	switch m.Action {
+	case planCopy:
+		atomic.AddInt64(&p.copies, 1)
+		atomic.AddInt64(&p.copyBytes, m.Size)
+	case planDelete:
+		atomic.AddInt64(&p.deletes, 1)
+	case planCreateBucket, planDeleteBucket:
+		atomic.AddInt64(&p.buckets, 1)
+	case planRefresh:
+		atomic.AddInt64(&p.refreshes, 1)
+	}
+}
+
+// summary returns the totals of the plan.
+func (p *mirrorPlan) summary() mirrorPlanSummaryMessage {
+	return mirrorPlanSummaryMessage{
+		Copies:    atomic.LoadInt64(&p.copies),
+		CopyBytes: atomic.LoadInt64(&p.copyBytes),
+		Deletes:   atomic.LoadInt64(&p.deletes),
+		Buckets:   atomic.LoadInt64(&p.buckets),
+		Refreshes: atomic.LoadInt64(&p.refreshes),
+	}
+}
+
+// mirrorPlanSummaryMessage is printed at the end of a dry-run mirror.
+type mirrorPlanSummaryMessage struct {
+	Status    string `json:"status"`
+	Copies    int64  `json:"objectsToCopy"`
+	CopyBytes int64  `json:"bytesToCopy"`
+	Deletes   int64  `json:"objectsToDelete"`
+	Buckets   int64  `json:"bucketsToChange"`
+	Refreshes int64  `json:"objectsToRefresh,omitempty"`
+}
+
+// String colorized mirror plan summary message
+func (m mirrorPlanSummaryMessage) String() string {
+	if m.Refreshes > 0 {
+		return console.Colorize("Plan", fmt.Sprintf("Plan: %d object(s) to refresh.", m.Refreshes))
+	}
+	return console.Colorize("Plan", fmt.Sprintf("Plan: %d object(s) to copy (%s), %d object(s) to delete, %d bucket(s) to create or delete.",
+		m.Copies, humanize.IBytes(uint64(m.CopyBytes)), m.Deletes, m.Buckets))
+}
+
+// JSON jsonified mirror plan summary message
+func (m mirrorPlanSummaryMessage) JSON() string {
+	m.Status = "success"
+	summaryMessageBytes, e := json.Marshal(m)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(summaryMessageBytes)
+}
@@ -0,0 +1,305 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-go-sdk/pkg/encrypt"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+// Attributes of an object updated by 'mirror --refresh-metadata'.
+const (
+	refreshMetadata     = "metadata"
+	refreshStorageClass = "storage-class"
+	refreshTags         = "tags"
+)
+
+// refreshHeaders are the standard headers of an object
+// refreshed along with its user metadata.
+var refreshHeaders = []string{
+	"Cache-Control",
+	"Content-Disposition",
+	"Content-Encoding",
+	"Content-Language",
+	"Content-Type",
+	"Expires",
+}
+
+// objectAttributes are the attributes of an object which
+// can be changed without rewriting its data.
+type objectAttributes struct {
+	metadata     map[string]string
+	storageClass string
+	tags         map[string]string
+}
+
+// refreshMetadataOf returns the metadata of an object compared and
+// set by a refresh: its user metadata and standard headers.
+func refreshMetadataOf(content *ClientContent) map[string]string {
+	metadata := make(map[string]string)
+	for _, k := range refreshHeaders {
+		if v := content.Metadata[k]; v != "" {
+			metadata[k] = v
+		}
+	}
+	for k, v := range content.UserMetadata {
+		metadata[http.CanonicalHeaderKey(k)] = v
+	}
+	return metadata
+}
+
+// normalizeStorageClass returns the storage class of an object,
+// which is not reported for the default storage class.
+func normalizeStorageClass(storageClass string) string {
+	if storageClass == "" {
+		return "STANDARD"
+	}
+	return strings.ToUpper(storageClass)
+}
+
+// refreshChanges returns the attributes of the target
+// which differ from the attributes of the source.
+func refreshChanges(source, target objectAttributes) (changes []string) {
+	if !metadataEqual(source.metadata, target.metadata) {
+		changes = append(changes, refreshMetadata)
+	}
+	if normalizeStorageClass(source.storageClass) != normalizeStorageClass(target.storageClass) {
+		changes = append(changes, refreshStorageClass)
+	}
+	if !metadataEqual(source.tags, target.tags) {
+		changes = append(changes, refreshTags)
+	}
+	return changes
+}
+
+// statObjectAttributes returns the refreshed attributes of an object.
+func statObjectAttributes(ctx context.Context, clnt Client, versionID string, sse encrypt.ServerSide) (objectAttributes, *probe.Error) {
+	st, err := clnt.Stat(ctx, StatOptions{versionID: versionID, sse: sse})
+	if err != nil {
+		return objectAttributes{}, err.Trace(clnt.GetURL().String())
+	}
+	tags, err := clnt.GetTags(ctx, versionID)
+	if err != nil && !isObjectConfigNotFound(err) {
+		return objectAttributes{}, err.Trace(clnt.GetURL().String())
+	}
+	return objectAttributes{
+		metadata:     refreshMetadataOf(st),
+		storageClass: st.StorageClass,
+		tags:         tags,
+	}, nil
+}
+
+// applyRefresh sets the changed attributes of source on the target
+// object. Metadata and storage class are replaced by a server side
+// copy of the object onto itself, the data never leaves the server.
+func applyRefresh(ctx context.Context, clnt Client, size int64, srcSSE, tgtSSE encrypt.ServerSide, source objectAttributes, changes []string) *probe.Error {
+	var rewrite, retag bool
+	for _, change := range changes {
+		switch change {
+		case refreshMetadata, refreshStorageClass:
+			rewrite = true
+		case refreshTags:
+			retag = true
+		}
+	}
+
+	if rewrite {
+		opts := CopyOptions{
+			size:         size,
+			srcSSE:       srcSSE,
+			tgtSSE:       tgtSSE,
+			metadata:     source.metadata,
+			storageClass: normalizeStorageClass(source.storageClass),
+		}
+		if err := clnt.Copy(ctx, clnt.GetURL().Path, opts, nil); err != nil {
+			return err.Trace(clnt.GetURL().String())
+		}
+	}
+
+	if retag {
+		if len(source.tags) == 0 {
+			return clnt.DeleteTags(ctx, "")
+		}
+		values := url.Values{}
+		for k, v := range source.tags {
+			values.Set(k, v)
+		}
+		return clnt.SetTags(ctx, "", values.Encode())
+	}
+	return nil
+}
+
+// refreshMessage reports the refresh of a target object.
+type refreshMessage struct {
+	Status  string   `json:"status"`
+	Source  string   `json:"source"`
+	Target  string   `json:"target"`
+	Changes []string `json:"changes,omitempty"`
+	Skipped bool     `json:"skipped,omitempty"`
+}
+
+// String colorized refresh message
+func (m refreshMessage) String() string {
+	if m.Skipped {
+		return console.Colorize("RefreshSkipped", fmt.Sprintf("Skipped `%s`, its content differs from `%s`.", m.Target, m.Source))
+	}
+	return console.Colorize("Refresh", fmt.Sprintf("Refreshed %s of `%s` from `%s`.", strings.Join(m.Changes, ", "), m.Target, m.Source))
+}
+
+// JSON jsonified refresh message
+func (m refreshMessage) JSON() string {
+	m.Status = "success"
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(msgBytes)
+}
+
+// refreshReport counts the objects examined by a refresh.
+type refreshReport struct {
+	refreshed, unchanged, skipped int64
+}
+
+// summary returns the message printed at the end of the mirror.
+func (r *refreshReport) summary() refreshSummaryMessage {
+	return refreshSummaryMessage{
+		Refreshed: atomic.LoadInt64(&r.refreshed),
+		Unchanged: atomic.LoadInt64(&r.unchanged),
+		Skipped:   atomic.LoadInt64(&r.skipped),
+	}
+}
+
+// refreshSummaryMessage is the report of 'mirror --refresh-metadata'.
+type refreshSummaryMessage struct {
+	Status    string `json:"status"`
+	Refreshed int64  `json:"refreshed"`
+	Unchanged int64  `json:"unchanged"`
+	Skipped   int64  `json:"skipped"`
+}
+
+// String colorized refresh summary message
+func (m refreshSummaryMessage) String() string {
+	return console.Colorize("Refresh", fmt.Sprintf("Refreshed %d object(s), %d already up to date, %d skipped as their content differs.",
+		m.Refreshed, m.Unchanged, m.Skipped))
+}
+
+// JSON jsonified refresh summary message
+func (m refreshSummaryMessage) JSON() string {
+	m.Status = "success"
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(msgBytes)
+}
+
+// refreshObject updates the tags, metadata and storage class of a
+// target object to match its source, when both have the same content.
+// Objects with a different content are reported and left untouched.
+func (mj *mirrorJob) refreshObject(ctx context.Context, sURLs URLs) URLs {
+	sourcePath := filepath.ToSlash(filepath.Join(sURLs.SourceAlias, sURLs.SourceContent.URL.Path))
+	targetPath := filepath.ToSlash(filepath.Join(sURLs.TargetAlias, sURLs.TargetContent.URL.Path))
+	srcSSE := getSSE(sourcePath, mj.opts.encKeyDB[sURLs.SourceAlias])
+	tgtReadSSEs := getReadSSEs(targetPath, mj.opts.encKeyDB[sURLs.TargetAlias])
+	tgtSSE := getTargetSSE(targetPath, mj.opts.encKeyDB[sURLs.TargetAlias])
+
+	mj.status.SetCaption(sourcePath + ":")
+	// No data is transferred, account for the object at once.
+	defer func() {
+		mj.status.Add(sURLs.SourceContent.Size)
+		mj.status.Update()
+	}()
+
+	msg := refreshMessage{Source: sourcePath, Target: targetPath}
+	equal := sURLs.SourceContent.Size == sURLs.TargetContent.Size
+	if equal {
+		var err *probe.Error
+		_, equal, err = diffContents(ctx, sURLs.SourceAlias, sURLs.SourceContent, sURLs.TargetAlias, sURLs.TargetContent, mj.opts.encKeyDB)
+		if err != nil {
+			return sURLs.WithError(err)
+		}
+	}
+	if !equal {
+		atomic.AddInt64(&mj.refresh.skipped, 1)
+		msg.Skipped = true
+		mj.status.PrintMsg(msg)
+		mj.status.Println(msg.String())
+		return sURLs.WithError(nil)
+	}
+
+	srcClnt, err := newClientFromAlias(sURLs.SourceAlias, sURLs.SourceContent.URL.String())
+	if err != nil {
+		return sURLs.WithError(err.Trace(sourcePath))
+	}
+	tgtClnt, err := newClientFromAlias(sURLs.TargetAlias, sURLs.TargetContent.URL.String())
+	if err != nil {
+		return sURLs.WithError(err.Trace(targetPath))
+	}
+	source, err := statObjectAttributes(ctx, srcClnt, sURLs.SourceContent.VersionID, srcSSE)
+	if err != nil {
+		return sURLs.WithError(err)
+	}
+	// The target may not be rotated to the new key yet.
+	tgtReadSSE := tgtReadSSEs[0]
+	if len(tgtReadSSEs) > 1 {
+		if _, tgtReadSSE, err = statWithSSEs(ctx, tgtClnt, StatOptions{}, tgtReadSSEs); err != nil {
+			return sURLs.WithError(err.Trace(targetPath))
+		}
+	}
+	target, err := statObjectAttributes(ctx, tgtClnt, "", tgtReadSSE)
+	if err != nil {
+		return sURLs.WithError(err)
+	}
+
+	msg.Changes = refreshChanges(source, target)
+	if len(msg.Changes) == 0 {
+		atomic.AddInt64(&mj.refresh.unchanged, 1)
+		return sURLs.WithError(nil)
+	}
+
+	if mj.opts.isFake {
+		mj.printPlan(mirrorPlanMessage{
+			Action: planRefresh,
+			Key:    targetPath,
+			Source: sourcePath,
+			Size:   sURLs.SourceContent.Size,
+			Reason: strings.Join(msg.Changes, ", ") + " differ",
+		})
+		return sURLs.WithError(nil)
+	}
+
+	err = withRetry(ctx, "refresh", sourcePath, func() *probe.Error {
+		return applyRefresh(ctx, tgtClnt, sURLs.TargetContent.Size, tgtReadSSE, tgtSSE, source, msg.Changes)
+	})
+	if err != nil {
+		return sURLs.WithError(err)
+	}
+	atomic.AddInt64(&mj.refresh.refreshed, 1)
+	mj.status.PrintMsg(msg)
+	mj.status.Println(msg.String())
+	return sURLs.WithError(nil)
+}
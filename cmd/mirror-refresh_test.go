@@ -0,0 +1,82 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRefreshMetadataOf(t *testing.T) {
+	content := &ClientContent{
+		Metadata: map[string]string{
+			"Content-Type":  "text/plain",
+			"Cache-Control": "max-age=60",
+			"Etag":          "\"d41d8cd98f00b204e9800998ecf8427e\"",
+			"Last-Modified": "Mon, 02 Jan 2006 15:04:05 GMT",
+		},
+		UserMetadata: map[string]string{"project": "alpha"},
+	}
+	expected := map[string]string{
+		"Content-Type":  "text/plain",
+		"Cache-Control": "max-age=60",
+		"Project":       "alpha",
+	}
+	if got := refreshMetadataOf(content); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestRefreshChanges(t *testing.T) {
+	source := objectAttributes{
+		metadata: map[string]string{"Content-Type": "text/plain", "Project": "alpha"},
+		tags:     map[string]string{"team": "data"},
+	}
+	testCases := []struct {
+		target   objectAttributes
+		expected []string
+	}{
+		// Same attributes, the default storage class is not reported.
+		{objectAttributes{
+			metadata:     map[string]string{"Content-Type": "text/plain", "Project": "alpha"},
+			storageClass: "STANDARD",
+			tags:         map[string]string{"team": "data"},
+		}, nil},
+		// Metadata lost by an earlier migration.
+		{objectAttributes{
+			metadata: map[string]string{"Content-Type": "application/octet-stream"},
+			tags:     map[string]string{"team": "data"},
+		}, []string{refreshMetadata}},
+		// Storage class and tags differ.
+		{objectAttributes{
+			metadata:     map[string]string{"Content-Type": "text/plain", "Project": "alpha"},
+			storageClass: "REDUCED_REDUNDANCY",
+		}, []string{refreshStorageClass, refreshTags}},
+		// Extra tags on target.
+		{objectAttributes{
+			metadata: map[string]string{"Content-Type": "text/plain", "Project": "alpha"},
+			tags:     map[string]string{"team": "data", "stale": "true"},
+		}, []string{refreshTags}},
+	}
+
+	for i, testCase := range testCases {
+		if got := refreshChanges(source, testCase.target); !reflect.DeepEqual(got, testCase.expected) {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.expected, got)
+		}
+	}
+}
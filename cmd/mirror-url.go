@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/minio/cli"
+	"github.com/trinet2005/oss-mc/pkg/probe"
 	"github.com/trinet2005/oss-pkg/wildcard"
 )
 
@@ -64,6 +65,88 @@ func checkMirrorSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[st
 		}
 	}
 
+	if policy := cliCtx.String("on-conflict"); policy != "" && !conflictPolicy(policy).IsValid() {
+		fatalIf(errInvalidArgument().Trace(policy), "Unknown conflict policy `"+policy+"`, valid values are newest, largest, rename and skip.")
+	}
+
+	if cliCtx.Bool("preserve-object-config") && (srcClient.Type != objectStorage || destClient.Type != objectStorage) {
+		fatalIf(errInvalidArgument().Trace(URLs...), "--preserve-object-config requires both source and target on object storage.")
+	}
+
+	if text := cliCtx.String("target-template"); text != "" {
+		_, err := parseTargetTemplate(text)
+		fatalIf(err, "Unable to parse target template.")
+		if cliCtx.Bool("remove") || cliCtx.Bool("active-active") || cliCtx.Bool("multi-master") {
+			fatalIf(errInvalidArgument().Trace(URLs...), "--target-template cannot be used with --remove or --active-active.")
+		}
+	}
+
+	if rules := cliCtx.StringSlice("route-by-tag"); len(rules) > 0 {
+		_, err := parseTagRoutes(rules)
+		fatalIf(err, "Unable to parse tag routes.")
+		if srcClient.Type != objectStorage {
+			fatalIf(errInvalidArgument().Trace(URLs...), "--route-by-tag requires the source on object storage, local files have no tags.")
+		}
+		if cliCtx.Bool("remove") || cliCtx.Bool("active-active") || cliCtx.Bool("multi-master") {
+			fatalIf(errInvalidArgument().Trace(URLs...), "--route-by-tag cannot be used with --remove or --active-active.")
+		}
+	}
+
+	if len(cliCtx.StringSlice("include-bucket")) > 0 || len(cliCtx.StringSlice("exclude-bucket")) > 0 || cliCtx.Bool("skip-system-buckets") {
+		if srcClient.Type != objectStorage || strings.Trim(srcClient.Path, string(srcClient.Separator)) != "" {
+			fatalIf(errInvalidArgument().Trace(URLs...), "Bucket filters require an alias as SOURCE, e.g. `site1/`.")
+		}
+	}
+
+	switch cliCtx.String("create-buckets") {
+	case createBucketsBare:
+	case createBucketsWithConfig:
+		if srcClient.Type != objectStorage || destClient.Type != objectStorage {
+			fatalIf(errInvalidArgument().Trace(URLs...), "--create-buckets with-config requires both source and target on object storage.")
+		}
+	default:
+		fatalIf(errInvalidArgument().Trace(cliCtx.String("create-buckets")), "Unknown bucket creation policy `"+cliCtx.String("create-buckets")+"`, valid values are bare and with-config.")
+	}
+
+	if cliCtx.Bool("refresh-metadata") {
+		if srcClient.Type != objectStorage || destClient.Type != objectStorage {
+			fatalIf(errInvalidArgument().Trace(URLs...), "--refresh-metadata requires both source and target on object storage.")
+		}
+		for _, flag := range []string{"overwrite", "remove", "watch", "active-active", "multi-master", "on-conflict", "target-template", "route-by-tag", "verify"} {
+			if cliCtx.IsSet(flag) {
+				fatalIf(errInvalidArgument().Trace(URLs...), "--refresh-metadata does not copy data, it cannot be used with --"+flag+".")
+			}
+		}
+	}
+
+	if cliCtx.Bool("verify") && (cliCtx.Bool("fake") || cliCtx.Bool("dry-run")) {
+		fatalIf(errInvalidArgument().Trace(URLs...), "--verify cannot be used with --dry-run, nothing is copied to verify.")
+	}
+
+	if cliCtx.Int("limit-objects") < 0 {
+		fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), "--limit-objects cannot be negative.")
+	}
+	if cliCtx.Int("limit-objects") > maxLimitObjects {
+		fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), "--limit-objects cannot be more than %d.", maxLimitObjects)
+	}
+
+	if _, _, err := parseMultipartFlags(cliCtx); err != nil {
+		fatalIf(err, "Unable to parse multipart upload flags.")
+	}
+
+	if _, err := parseActiveHours(cliCtx.String("active-hours")); err != nil {
+		fatalIf(err, "Unable to parse --active-hours.")
+	}
+
+	if cliCtx.IsSet("watch-debounce") {
+		if cliCtx.Duration("watch-debounce") < 0 {
+			fatalIf(errInvalidArgument().Trace(cliCtx.String("watch-debounce")), "Watch debounce window cannot be negative.")
+		}
+		if !cliCtx.Bool("watch") && !cliCtx.Bool("active-active") && !cliCtx.Bool("multi-master") {
+			fatalIf(errInvalidArgument(), "--watch-debounce requires --watch or --active-active.")
+		}
+	}
+
 	/****** Generic rules *******/
 	if !cliCtx.Bool("watch") && !cliCtx.Bool("active-active") && !cliCtx.Bool("multi-master") {
 		_, srcContent, err := url2Stat(ctx, srcURL, "", false, encKeyDB, time.Time{}, false)
@@ -128,7 +211,7 @@ func deltaSourceTarget(ctx context.Context, sourceURL, targetURL string, opts mi
 	}
 
 	// List both source and target, compare and return values through channel.
-	for diffMsg := range objectDifference(ctx, sourceClnt, targetClnt, opts.isMetadata) {
+	for diffMsg := range objectDifference(ctx, sourceClnt, targetClnt, opts.isMetadata, opts.refreshMetadata) {
 		if diffMsg.Error != nil {
 			// Send all errors through the channel
 			URLsCh <- URLs{Error: diffMsg.Error, ErrorCond: differInUnknown}
@@ -140,12 +223,46 @@ func deltaSourceTarget(ctx context.Context, sourceURL, targetURL string, opts mi
 		if matchExcludeOptions(opts.excludeOptions, srcSuffix) {
 			continue
 		}
+		// Skip the source object if its bucket is filtered out
+		if opts.bucketFilter.skip(srcSuffix) {
+			continue
+		}
+
+		if opts.targetTemplate != nil || len(opts.tagRoutes) > 0 {
+			// Objects only on target cannot be matched to a source.
+			if diffMsg.firstContent != nil {
+				if urls, ok := renamedTargetURLs(ctx, sourceAlias, diffMsg.firstContent, srcSuffix, targetAlias, targetURL, opts); ok {
+					URLsCh <- urls
+				}
+			}
+			continue
+		}
 
 		tgtSuffix := strings.TrimPrefix(diffMsg.SecondURL, targetURL)
 		// Skip the target object if it matches the Exclude options provided
 		if matchExcludeOptions(opts.excludeOptions, tgtSuffix) {
 			continue
 		}
+		// Skip the target object if its bucket is filtered out
+		if opts.bucketFilter.skip(tgtSuffix) {
+			continue
+		}
+
+		if opts.refreshMetadata {
+			// Every object on both sides is reported once as differInNone,
+			// objects on one side only are neither copied nor removed.
+			if diffMsg.Diff == differInNone && diffMsg.secondContent.Type.IsRegular() {
+				URLsCh <- URLs{
+					SourceAlias:   sourceAlias,
+					SourceContent: diffMsg.firstContent,
+					TargetAlias:   targetAlias,
+					TargetContent: diffMsg.secondContent,
+					diff:          diffMsg.Diff,
+					refresh:       true,
+				}
+			}
+			continue
+		}
 
 		switch diffMsg.Diff {
 		case differInNone:
@@ -153,6 +270,25 @@ func deltaSourceTarget(ctx context.Context, sourceURL, targetURL string, opts mi
 		case differInType:
 			URLsCh <- URLs{Error: errInvalidTarget(diffMsg.SecondURL)}
 		case differInSize, differInMetadata, differInAASourceMTime:
+			if opts.onConflict != "" {
+				sourceSuffix := strings.TrimPrefix(diffMsg.FirstURL, sourceURL)
+				targetPath := urlJoinPath(targetURL, sourceSuffix)
+				copyURLs := URLs{
+					SourceAlias:   sourceAlias,
+					SourceContent: diffMsg.firstContent,
+					TargetAlias:   targetAlias,
+					TargetContent: &ClientContent{URL: *newClientURL(targetPath)},
+					diff:          diffMsg.Diff,
+				}
+				switch opts.onConflict.resolve(diffMsg.firstContent, diffMsg.secondContent) {
+				case conflictKeepTarget:
+					continue
+				case conflictRenameTarget:
+					copyURLs.conflictContent = diffMsg.secondContent
+				}
+				URLsCh <- copyURLs
+				continue
+			}
 			if !opts.isOverwrite && !opts.isFake && !opts.activeActive {
 				// Size or time or etag differs but --overwrite not set.
 				URLsCh <- URLs{
@@ -172,6 +308,7 @@ func deltaSourceTarget(ctx context.Context, sourceURL, targetURL string, opts mi
 				SourceContent: sourceContent,
 				TargetAlias:   targetAlias,
 				TargetContent: targetContent,
+				diff:          diffMsg.Diff,
 			}
 		case differInFirst:
 			// Only in first, always copy.
@@ -184,6 +321,7 @@ func deltaSourceTarget(ctx context.Context, sourceURL, targetURL string, opts mi
 				SourceContent: sourceContent,
 				TargetAlias:   targetAlias,
 				TargetContent: targetContent,
+				diff:          diffMsg.Diff,
 			}
 		case differInSecond:
 			if !opts.isRemove && !opts.isFake {
@@ -192,6 +330,7 @@ func deltaSourceTarget(ctx context.Context, sourceURL, targetURL string, opts mi
 			URLsCh <- URLs{
 				TargetAlias:   targetAlias,
 				TargetContent: diffMsg.secondContent,
+				diff:          diffMsg.Diff,
 			}
 		default:
 			URLsCh <- URLs{
@@ -202,6 +341,66 @@ func deltaSourceTarget(ctx context.Context, sourceURL, targetURL string, opts mi
 	}
 }
 
+// renamedTargetURLs returns the URLs mirroring the source object
+// srcContent to the target named by --target-template and routed by
+// --route-by-tag. Target names differ from source names, so instead
+// of the target listing, the renamed target is looked up: an object
+// of the same size there means the source was already mirrored and
+// ok is false.
+func renamedTargetURLs(ctx context.Context, sourceAlias string, srcContent *ClientContent, srcSuffix, targetAlias, targetURL string, opts mirrorOptions) (urls URLs, ok bool) {
+	name := filepath.ToSlash(srcSuffix)
+	if opts.targetTemplate != nil {
+		var err *probe.Error
+		if name, err = opts.targetTemplate.render(ctx, sourceAlias, name, srcContent); err != nil {
+			return URLs{Error: err.Trace(srcContent.URL.String())}, true
+		}
+	}
+	var storageClass string
+	if len(opts.tagRoutes) > 0 {
+		route, err := opts.tagRoutes.lookup(ctx, sourceAlias, srcContent)
+		if err != nil {
+			return URLs{Error: err.Trace(srcContent.URL.String())}, true
+		}
+		if route != nil {
+			name = route.rename(name)
+			storageClass = route.storageClass
+		}
+	}
+	targetPath := urlJoinPath(targetURL, name)
+	urls = URLs{
+		SourceAlias:   sourceAlias,
+		SourceContent: srcContent,
+		TargetAlias:   targetAlias,
+		TargetContent: &ClientContent{URL: *newClientURL(targetPath), StorageClass: storageClass},
+		diff:          differInFirst,
+	}
+
+	targetClnt, err := newClientFromAlias(targetAlias, targetPath)
+	if err != nil {
+		return URLs{Error: err.Trace(targetAlias, targetPath)}, true
+	}
+	aliasedTargetPath := filepath.ToSlash(filepath.Join(targetAlias, urls.TargetContent.URL.Path))
+	st, _, err := statWithSSEs(ctx, targetClnt, StatOptions{}, getReadSSEs(aliasedTargetPath, opts.encKeyDB[targetAlias]))
+	if err != nil {
+		switch err.ToGoError().(type) {
+		case ObjectMissing, PathNotFound:
+			return urls, true
+		}
+		return URLs{Error: err.Trace(targetPath)}, true
+	}
+	if st.Type.IsDir() {
+		return urls, true
+	}
+	if st.Size == srcContent.Size {
+		return URLs{}, false
+	}
+	if !opts.isOverwrite && !opts.isFake {
+		return URLs{Error: errOverWriteNotAllowed(targetPath), ErrorCond: differInSize}, true
+	}
+	urls.diff = differInSize
+	return urls, true
+}
+
 type mirrorOptions struct {
 	isFake, isOverwrite, activeActive bool
 	isWatch, isRemove, isMetadata     bool
@@ -211,6 +410,79 @@ type mirrorOptions struct {
 	olderThan, newerThan              string
 	storageClass                      string
 	userMetadata                      map[string]string
+	onConflict                        conflictPolicy
+	watchDebounce                     time.Duration
+	multipartSize                     uint64
+	multipartThreads                  uint
+	limitObjects                      int
+	activeHours                       *activeHours
+	preserveObjectConfig              bool
+	targetTemplate                    *targetTemplate
+	tagRoutes                         tagRoutes
+	verify                            bool
+	bucketFilter                      bucketFilter
+	createBuckets                     string
+	refreshMetadata                   bool
+}
+
+// conflictPolicy decides which copy wins when an object
+// exists on both source and target but differs.
+type conflictPolicy string
+
+const (
+	conflictNewest  conflictPolicy = "newest"
+	conflictLargest conflictPolicy = "largest"
+	conflictRename  conflictPolicy = "rename"
+	conflictSkip    conflictPolicy = "skip"
+)
+
+// IsValid returns true if the policy is a known conflict policy.
+func (p conflictPolicy) IsValid() bool {
+	switch p {
+	case conflictNewest, conflictLargest, conflictRename, conflictSkip:
+		return true
+	}
+	return false
+}
+
+// conflictAction is the outcome of resolving a conflict.
+type conflictAction int
+
+const (
+	conflictOverwriteTarget conflictAction = iota // copy source over target
+	conflictKeepTarget                            // leave target untouched
+	conflictRenameTarget                          // move target aside, then copy source
+)
+
+// resolve returns the action to take for a source and
+// target object which both exist and differ.
+func (p conflictPolicy) resolve(src, tgt *ClientContent) conflictAction {
+	if src == nil || tgt == nil {
+		return conflictOverwriteTarget
+	}
+	switch p {
+	case conflictNewest:
+		if src.Time.After(tgt.Time) {
+			return conflictOverwriteTarget
+		}
+		return conflictKeepTarget
+	case conflictLargest:
+		if src.Size > tgt.Size {
+			return conflictOverwriteTarget
+		}
+		return conflictKeepTarget
+	case conflictRename:
+		return conflictRenameTarget
+	case conflictSkip:
+		return conflictKeepTarget
+	}
+	return conflictOverwriteTarget
+}
+
+// conflictName returns the name under which the losing copy
+// of a conflicting object is preserved.
+func conflictName(name string, t time.Time) string {
+	return name + ".conflict-" + t.UTC().Format("20060102T150405Z")
 }
 
 // Prepares urls that need to be copied or removed based on requested options.
@@ -0,0 +1,208 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sync/atomic"
+
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+// Methods used by 'mirror --verify' to compare an object with its copy.
+const (
+	verifyMethodSize = "size"
+	verifyMethodETag = "etag"
+	verifyMethodHash = "sha256"
+)
+
+// md5ETagRegex matches the ETag of an object uploaded in a single part
+// without encryption, which is the MD5 sum of its content.
+var md5ETagRegex = regexp.MustCompile("^[0-9a-f]{32}$")
+
+// verifyMessage reports the verification of a mirrored object.
+type verifyMessage struct {
+	Status string `json:"status"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Method string `json:"method,omitempty"`
+	Match  bool   `json:"match"`
+	Error  string `json:"error,omitempty"`
+}
+
+// String colorized verify message
+func (v verifyMessage) String() string {
+	if v.Error != "" {
+		return console.Colorize("VerifyError", fmt.Sprintf("Unable to verify `%s` against `%s`: %s", v.Target, v.Source, v.Error))
+	}
+	if !v.Match {
+		return console.Colorize("VerifyError", fmt.Sprintf("`%s` does not match `%s` (%s).", v.Target, v.Source, v.Method))
+	}
+	return console.Colorize("Verify", fmt.Sprintf("`%s` matches `%s` (%s).", v.Target, v.Source, v.Method))
+}
+
+// JSON jsonified verify message
+func (v verifyMessage) JSON() string {
+	v.Status = "verify"
+	msgBytes, e := json.MarshalIndent(v, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(msgBytes)
+}
+
+// verifyReport counts the verifications of a mirror run.
+type verifyReport struct {
+	matched, mismatched, failed int64
+}
+
+// hasFailures returns true if any object mismatched or could not be verified.
+func (r *verifyReport) hasFailures() bool {
+	return atomic.LoadInt64(&r.mismatched) > 0 || atomic.LoadInt64(&r.failed) > 0
+}
+
+// summary returns the message printed at the end of the mirror.
+func (r *verifyReport) summary() verifySummaryMessage {
+	return verifySummaryMessage{
+		Matched:    atomic.LoadInt64(&r.matched),
+		Mismatched: atomic.LoadInt64(&r.mismatched),
+		Failed:     atomic.LoadInt64(&r.failed),
+	}
+}
+
+// verifySummaryMessage is the verification report of 'mirror --verify'.
+type verifySummaryMessage struct {
+	Status     string `json:"status"`
+	Matched    int64  `json:"matched"`
+	Mismatched int64  `json:"mismatched"`
+	Failed     int64  `json:"failed"`
+}
+
+// String colorized verify summary message
+func (v verifySummaryMessage) String() string {
+	color := "Verify"
+	if v.Mismatched > 0 || v.Failed > 0 {
+		color = "VerifyError"
+	}
+	return console.Colorize(color, fmt.Sprintf("Verified %d object(s): %d matched, %d mismatched, %d could not be verified.",
+		v.Matched+v.Mismatched+v.Failed, v.Matched, v.Mismatched, v.Failed))
+}
+
+// JSON jsonified verify summary message
+func (v verifySummaryMessage) JSON() string {
+	v.Status = "success"
+	if v.Mismatched > 0 || v.Failed > 0 {
+		v.Status = "error"
+	}
+	msgBytes, e := json.MarshalIndent(v, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(msgBytes)
+}
+
+// verifyObject re-reads a mirrored object on both sides to check that
+// the target matches the source. Sizes are compared first, then ETags
+// when both are MD5 sums, falling back to a SHA-256 of the contents.
+func (mj *mirrorJob) verifyObject(ctx context.Context, sURLs URLs) {
+	sourcePath := filepath.ToSlash(filepath.Join(sURLs.SourceAlias, sURLs.SourceContent.URL.Path))
+	targetPath := filepath.ToSlash(filepath.Join(sURLs.TargetAlias, sURLs.TargetContent.URL.Path))
+	msg := verifyMessage{Source: sourcePath, Target: targetPath}
+
+	var err *probe.Error
+	msg.Method, msg.Match, err = verifyMirroredObject(ctx, sURLs, mj.opts.encKeyDB)
+	switch {
+	case err != nil:
+		msg.Error = err.ToGoError().Error()
+		atomic.AddInt64(&mj.verify.failed, 1)
+	case msg.Match:
+		atomic.AddInt64(&mj.verify.matched, 1)
+	default:
+		atomic.AddInt64(&mj.verify.mismatched, 1)
+	}
+
+	if globalJSON {
+		printMsg(msg)
+		return
+	}
+	// Only report the problems, not to bury them under matches.
+	if !msg.Match {
+		mj.status.Println(msg.String())
+	}
+}
+
+// verifyMirroredObject compares a mirrored object with its source,
+// returning the method used for the comparison.
+func verifyMirroredObject(ctx context.Context, sURLs URLs, encKeyDB map[string][]prefixSSEPair) (method string, match bool, err *probe.Error) {
+	sourcePath := filepath.ToSlash(filepath.Join(sURLs.SourceAlias, sURLs.SourceContent.URL.Path))
+	targetPath := filepath.ToSlash(filepath.Join(sURLs.TargetAlias, sURLs.TargetContent.URL.Path))
+	srcSSE := getSSE(sourcePath, encKeyDB[sURLs.SourceAlias])
+	tgtClnt, err := newClientFromAlias(sURLs.TargetAlias, sURLs.TargetContent.URL.String())
+	if err != nil {
+		return "", false, err.Trace(targetPath)
+	}
+	// Objects left unchanged by the mirror may not be rotated to the new key yet.
+	st, tgtSSE, err := statWithSSEs(ctx, tgtClnt, StatOptions{}, getReadSSEs(targetPath, encKeyDB[sURLs.TargetAlias]))
+	if err != nil {
+		return "", false, err.Trace(targetPath)
+	}
+	if st.Size != sURLs.SourceContent.Size {
+		return verifyMethodSize, false, nil
+	}
+
+	// ETags of encrypted objects are not MD5 sums, a different
+	// ETag may still be the same content: compare the contents.
+	srcETag, tgtETag := sURLs.SourceContent.ETag, st.ETag
+	if srcSSE == nil && tgtSSE == nil && md5ETagRegex.MatchString(srcETag) && srcETag == tgtETag {
+		return verifyMethodETag, true, nil
+	}
+
+	srcSum, err := hashObject(ctx, sURLs.SourceAlias, sURLs.SourceContent.URL.String(), GetOptions{
+		SSE:       srcSSE,
+		VersionID: sURLs.SourceContent.VersionID,
+	})
+	if err != nil {
+		return "", false, err.Trace(sourcePath)
+	}
+	tgtSum, err := hashObject(ctx, sURLs.TargetAlias, sURLs.TargetContent.URL.String(), GetOptions{SSE: tgtSSE})
+	if err != nil {
+		return "", false, err.Trace(targetPath)
+	}
+	return verifyMethodHash, bytes.Equal(srcSum, tgtSum), nil
+}
+
+// hashObject returns the SHA-256 sum of the content of an object.
+func hashObject(ctx context.Context, alias, urlStr string, opts GetOptions) ([]byte, *probe.Error) {
+	reader, _, err := getSourceStream(ctx, alias, urlStr, getSourceOpts{GetOptions: opts})
+	if err != nil {
+		return nil, err.Trace(alias, urlStr)
+	}
+	defer reader.Close()
+
+	h := sha256.New()
+	if _, e := io.Copy(h, reader); e != nil {
+		return nil, probe.NewError(e).Trace(alias, urlStr)
+	}
+	return h.Sum(nil), nil
+}
@@ -21,10 +21,13 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path"
 	"sync"
+	"sync/atomic"
 
 	"github.com/fatih/color"
 	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
 	"github.com/trinet2005/oss-mc/pkg/probe"
 	"github.com/trinet2005/oss-pkg/console"
 )
@@ -92,6 +95,10 @@ ENVIRONMENT VARIABLES:
   MC_ENCRYPT:      list of comma delimited prefixes
   MC_ENCRYPT_KEY:  list of comma delimited prefix=secret values
 
+NOTE:
+  Objects moved within the same alias are copied on the server side without
+  being downloaded, and the sources are removed in batches once copied.
+
 EXAMPLES:
   01. Move a list of objects from local file system to Amazon S3 cloud storage.
       {{.Prompt}} {{.HelpName}} Music/*.ogg s3/jukebox/
@@ -144,62 +151,90 @@ EXAMPLES:
 `,
 }
 
+// Number of concurrent batched removals per alias, each worker
+// deletes the moved sources in batches with DeleteObjects.
+const mvRemoveWorkers = 4
+
 type removeClientInfo struct {
-	client    Client
-	contentCh chan *ClientContent
-	resultCh  <-chan RemoveResult
+	workers []chan *ClientContent
+	next    int
 }
 
 type removeManager struct {
 	removeMap      map[string]*removeClientInfo
 	removeMapMutex sync.RWMutex
 	wg             sync.WaitGroup
+
+	// Outcome of the move operations.
+	moved, copyFailed, removeFailed int64
 }
 
-func (rm *removeManager) readErrors(resultCh <-chan RemoveResult, targetURL string) {
+func (rm *removeManager) readErrors(resultCh <-chan RemoveResult, targetAlias, targetURL string) {
 	rm.wg.Add(1)
 	go func() {
 		defer rm.wg.Done()
 		for result := range resultCh {
 			if result.Err != nil {
-				errorIf(result.Err.Trace(targetURL), "Failed to remove in`"+targetURL+"`.")
+				atomic.AddInt64(&rm.removeFailed, 1)
+				sourceURL := targetURL
+				if result.ObjectName != "" {
+					sourceURL = path.Join(targetAlias, result.BucketName, result.ObjectName)
+				}
+				errorIf(result.Err.Trace(sourceURL),
+					"Failed to remove source `"+sourceURL+"` after copying it, the object now exists on both source and target.")
+				continue
 			}
+			atomic.AddInt64(&rm.moved, 1)
 		}
 	}()
 }
 
+// copyFailure records a source which was not copied and thus not removed.
+func (rm *removeManager) copyFailure() {
+	atomic.AddInt64(&rm.copyFailed, 1)
+}
+
 // This function should be parallel-safe because it is executed by ParallelManager
 // If targetAlias is empty, it means we will target local FS contents
 func (rm *removeManager) add(ctx context.Context, targetAlias, targetURL string) {
 	rm.removeMapMutex.Lock()
 	clientInfo := rm.removeMap[targetAlias]
 	if clientInfo == nil {
-		client, pErr := newClientFromAlias(targetAlias, targetURL)
-		if pErr != nil {
-			errorIf(pErr.Trace(targetURL), "Invalid argument `"+targetURL+"`.")
-			return
+		// Create all the clients before starting any worker, so that
+		// a failure does not leave workers which are never closed.
+		clients := make([]Client, 0, mvRemoveWorkers)
+		for i := 0; i < mvRemoveWorkers; i++ {
+			client, pErr := newClientFromAlias(targetAlias, targetURL)
+			if pErr != nil {
+				rm.removeMapMutex.Unlock()
+				atomic.AddInt64(&rm.removeFailed, 1)
+				errorIf(pErr.Trace(targetURL), "Invalid argument `"+targetURL+"`.")
+				return
+			}
+			clients = append(clients, client)
 		}
 
-		contentCh := make(chan *ClientContent, 10000)
-		resultCh := client.Remove(ctx, false, false, false, false, contentCh)
-		rm.readErrors(resultCh, targetURL)
-
-		clientInfo = &removeClientInfo{
-			client:    client,
-			contentCh: contentCh,
-			resultCh:  resultCh,
+		clientInfo = &removeClientInfo{}
+		for _, client := range clients {
+			contentCh := make(chan *ClientContent, 10000)
+			resultCh := client.Remove(ctx, false, false, false, false, contentCh)
+			rm.readErrors(resultCh, targetAlias, targetURL)
+			clientInfo.workers = append(clientInfo.workers, contentCh)
 		}
-
 		rm.removeMap[targetAlias] = clientInfo
 	}
+	contentCh := clientInfo.workers[clientInfo.next%len(clientInfo.workers)]
+	clientInfo.next++
 	rm.removeMapMutex.Unlock()
 
-	clientInfo.contentCh <- &ClientContent{URL: *newClientURL(targetURL)}
+	contentCh <- &ClientContent{URL: *newClientURL(targetURL)}
 }
 
 func (rm *removeManager) close() {
 	for _, clientInfo := range rm.removeMap {
-		close(clientInfo.contentCh)
+		for _, contentCh := range clientInfo.workers {
+			close(contentCh)
+		}
 	}
 
 	// Wait until all on-going client.Remove() operations to finish
@@ -210,6 +245,34 @@ var rmManager = &removeManager{
 	removeMap: make(map[string]*removeClientInfo),
 }
 
+// moveSummaryMessage reports the outcome of a move.
+type moveSummaryMessage struct {
+	Status       string `json:"status"`
+	Moved        int64  `json:"moved"`
+	CopyFailed   int64  `json:"copyFailed"`
+	RemoveFailed int64  `json:"removeFailed"`
+}
+
+// String colorized move summary message
+func (m moveSummaryMessage) String() string {
+	msg := console.Colorize("Copy", fmt.Sprintf("Moved %d object(s).", m.Moved))
+	if m.CopyFailed > 0 {
+		msg += console.Colorize("MoveFailed", fmt.Sprintf(" %d object(s) failed to copy and were left on source.", m.CopyFailed))
+	}
+	if m.RemoveFailed > 0 {
+		msg += console.Colorize("MoveFailed", fmt.Sprintf(" %d object(s) were copied but failed to be removed from source.", m.RemoveFailed))
+	}
+	return msg
+}
+
+// JSON jsonified move summary message
+func (m moveSummaryMessage) JSON() string {
+	m.Status = "success"
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
 // mainMove is the entry point for mv command.
 func mainMove(cliCtx *cli.Context) error {
 	ctx, cancelMove := context.WithCancel(globalContext)
@@ -240,6 +303,8 @@ func mainMove(cliCtx *cli.Context) error {
 
 	// Additional command speific theme customization.
 	console.SetColor("Copy", color.New(color.FgGreen, color.Bold))
+	console.SetColor("MoveFailed", color.New(color.FgRed, color.Bold))
+	console.SetColor("ServerSideCopy", color.New(color.FgCyan))
 
 	recursive := cliCtx.Bool("recursive")
 	olderThan := cliCtx.String("older-than")
@@ -299,5 +364,16 @@ func mainMove(cliCtx *cli.Context) error {
 	console.Colorize("Copy", "Waiting for move operations to complete")
 	rmManager.close()
 
+	if rmManager.copyFailed > 0 || rmManager.removeFailed > 0 {
+		printMsg(moveSummaryMessage{
+			Moved:        rmManager.moved,
+			CopyFailed:   rmManager.copyFailed,
+			RemoveFailed: rmManager.removeFailed,
+		})
+		if e == nil {
+			e = exitStatus(globalErrorExitStatus)
+		}
+	}
+
 	return e
 }
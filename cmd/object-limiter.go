@@ -0,0 +1,56 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import "time"
+
+// maxLimitObjects is the highest rate accepted by --limit-objects, one
+// object per microsecond, well above any rate a server can sustain.
+const maxLimitObjects = 1000000
+
+// objectLimiter caps the number of objects processed per second,
+// limiting the request rate to the server independently of the
+// bandwidth. A nil *objectLimiter does not limit anything.
+type objectLimiter struct {
+	ticker *time.Ticker
+}
+
+// newObjectLimiter returns a limiter allowing perSecond objects per
+// second, or nil if perSecond is not positive or too high to limit.
+func newObjectLimiter(perSecond int) *objectLimiter {
+	if perSecond <= 0 || perSecond > maxLimitObjects {
+		return nil
+	}
+	return &objectLimiter{ticker: time.NewTicker(time.Second / time.Duration(perSecond))}
+}
+
+// wait blocks until the next object may be processed.
+func (l *objectLimiter) wait() {
+	if l == nil {
+		return
+	}
+	<-l.ticker.C
+}
+
+// stop releases the resources of the limiter.
+func (l *objectLimiter) stop() {
+	if l == nil {
+		return
+	}
+	l.ticker.Stop()
+}
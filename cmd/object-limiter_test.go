@@ -0,0 +1,42 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import "testing"
+
+func TestNewObjectLimiter(t *testing.T) {
+	testCases := []struct {
+		perSecond int
+		limited   bool
+	}{
+		{-1, false},
+		{0, false},
+		{1, true},
+		{maxLimitObjects, true},
+		{maxLimitObjects + 1, false},
+		{2000000000, false},
+	}
+
+	for i, testCase := range testCases {
+		l := newObjectLimiter(testCase.perSecond)
+		if (l != nil) != testCase.limited {
+			t.Fatalf("Test %d: expected limited %v, got %v", i+1, testCase.limited, l != nil)
+		}
+		l.stop()
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/minio/cli"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+// parallelBucketsFlag sets how many buckets are processed
+// concurrently when a command targets the root of an alias. Buckets
+// are processed one by one by default, so that their output does not
+// interleave.
+var parallelBucketsFlag = cli.IntFlag{
+	Name:  "parallel-buckets",
+	Value: 1,
+	Usage: "number of buckets processed concurrently when TARGET is an alias, the output of buckets may interleave above 1",
+}
+
+// isAliasRoot returns true if clnt points to the root of an
+// object storage alias, i.e. to the list of its buckets.
+func isAliasRoot(clnt Client) bool {
+	u := clnt.GetURL()
+	return u.Type == objectStorage && u.Path == string(u.Separator)
+}
+
+// forEachBucket calls fn with the aliased URL of every bucket of the
+// alias root aliasedURL, running up to parallel calls concurrently.
+func forEachBucket(ctx context.Context, clnt Client, aliasedURL string, parallel int, fn func(bucketURL string)) *probe.Error {
+	buckets, err := clnt.ListBuckets(ctx)
+	if err != nil {
+		return err.Trace(aliasedURL)
+	}
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, parallel)
+	defer wg.Wait()
+	for _, bucket := range buckets {
+		bucketURL := urlJoinPath(aliasedURL, strings.Trim(bucket.URL.Path, "/"))
+		select {
+		case <-ctx.Done():
+			return probe.NewError(ctx.Err())
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(bucketURL)
+		}()
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestForEachBucketDefault(t *testing.T) {
+	root := t.TempDir()
+	var names []string
+	for i := 0; i < 8; i++ {
+		name := fmt.Sprintf("bucket%d", i)
+		if e := os.Mkdir(filepath.Join(root, name), 0o755); e != nil {
+			t.Fatal(e)
+		}
+		names = append(names, name)
+	}
+	clnt, err := newClient(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mu             sync.Mutex
+		order          []string
+		active, maxRun int
+	)
+	err = forEachBucket(context.Background(), clnt, "myminio", parallelBucketsFlag.Value, func(bucketURL string) {
+		mu.Lock()
+		order = append(order, bucketURL)
+		active++
+		if active > maxRun {
+			maxRun = active
+		}
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if maxRun != 1 {
+		t.Fatalf("expected buckets to be processed one by one by default, got %d concurrently", maxRun)
+	}
+	if len(order) != len(names) {
+		t.Fatalf("expected %d buckets, got %d", len(names), len(order))
+	}
+	for i, name := range names {
+		if !strings.HasSuffix(order[i], "/"+name) {
+			t.Fatalf("Test %d: expected bucket %s, got %s", i+1, name, order[i])
+		}
+	}
+}
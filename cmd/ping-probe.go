@@ -0,0 +1,196 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	json "github.com/minio/colorjson"
+	minio "github.com/trinet2005/oss-go-sdk"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+// pingProbeCombination is one addressing style and signature
+// version pair tried against an endpoint.
+type pingProbeCombination struct {
+	Path      string `json:"path"`
+	Signature string `json:"api"`
+}
+
+// Style returns the human readable addressing style.
+func (c pingProbeCombination) Style() string {
+	if c.Path == "off" {
+		return "virtual-host"
+	}
+	return "path-style"
+}
+
+// pingProbeCombinations lists the combinations in order of preference,
+// signature v4 and path-style addressing first.
+var pingProbeCombinations = []pingProbeCombination{
+	{Path: "on", Signature: "s3v4"},
+	{Path: "off", Signature: "s3v4"},
+	{Path: "on", Signature: "s3v2"},
+	{Path: "off", Signature: "s3v2"},
+}
+
+// pingProbeResult is the outcome of one probed combination.
+type pingProbeResult struct {
+	pingProbeCombination
+	Style string `json:"style"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// pingProbeMessage reports which combinations work against an alias.
+type pingProbeMessage struct {
+	Status      string                `json:"status"`
+	Alias       string                `json:"alias"`
+	Endpoint    string                `json:"endpoint"`
+	Bucket      string                `json:"bucket"`
+	Results     []pingProbeResult     `json:"results"`
+	Recommended *pingProbeCombination `json:"recommended,omitempty"`
+}
+
+// String colorized ping probe message.
+func (m pingProbeMessage) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s\n", console.Colorize("Info", m.Alias+":"), m.Endpoint)
+	for _, r := range m.Results {
+		line := fmt.Sprintf("  %-12s  %-4s  ", r.Style, r.Signature)
+		if r.OK {
+			b.WriteString(line + console.Colorize("Info", "OK") + "\n")
+			continue
+		}
+		b.WriteString(line + console.Colorize("InfoFail", "FAIL") + "  " + r.Error + "\n")
+	}
+	if m.Recommended == nil {
+		b.WriteString(console.Colorize("InfoFail", "  No working combination found."))
+		return b.String()
+	}
+	fmt.Fprintf(&b, "  Recommended: mc alias set ALIAS ... --path %s --api %s", m.Recommended.Path, m.Recommended.Signature)
+	return b.String()
+}
+
+// JSON jsonified ping probe message.
+func (m pingProbeMessage) JSON() string {
+	m.Status = "success"
+	if m.Recommended == nil {
+		m.Status = "error"
+	}
+	probeJSONBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(probeJSONBytes)
+}
+
+// recommendProbeCombination returns the first working combination,
+// results are expected in the order of pingProbeCombinations.
+func recommendProbeCombination(results []pingProbeResult) *pingProbeCombination {
+	for _, r := range results {
+		if r.OK {
+			c := r.pingProbeCombination
+			return &c
+		}
+	}
+	return nil
+}
+
+// isProbeSuccess reports whether a listing error still proves that the
+// request was routed and signed correctly.
+func isProbeSuccess(e error) bool {
+	if e == nil {
+		return true
+	}
+	switch minio.ToErrorResponse(e).Code {
+	case "NoSuchBucket", "AccessDenied":
+		return true
+	}
+	return false
+}
+
+// probeCombination lists at most one object of bucket with the given
+// combination. A GET request is used so that signature errors are not
+// hidden behind the empty body of a HEAD response.
+func probeCombination(ctx context.Context, hostCfg *aliasConfigV10, bucket string, c pingProbeCombination) *probe.Error {
+	cfg := *hostCfg
+	cfg.Path = c.Path
+	cfg.API = c.Signature
+	s3Config := NewS3Config(hostCfg.URL, &cfg)
+
+	clnt, err := S3New(s3Config)
+	if err != nil {
+		return err.Trace(hostCfg.URL)
+	}
+	s3Clnt, ok := clnt.(*S3Client)
+	if !ok {
+		return errInvalidTarget(hostCfg.URL)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	for obj := range s3Clnt.api.ListObjects(ctx, bucket, minio.ListObjectsOptions{MaxKeys: 1}) {
+		if !isProbeSuccess(obj.Err) {
+			return probe.NewError(obj.Err)
+		}
+		break
+	}
+	return nil
+}
+
+// pingProbe tries every addressing style and signature version against
+// each alias and prints which combinations work.
+func pingProbe(ctx context.Context, aliasedURLs []string) error {
+	var failed bool
+	for _, aliasedURL := range aliasedURLs {
+		alias, path := url2Alias(aliasedURL)
+		hostCfg := mustGetHostConfig(alias)
+		if hostCfg == nil {
+			fatalIf(errInvalidAliasedURL(aliasedURL), "No such alias `"+alias+"` found.")
+		}
+		bucket := splitStr(strings.TrimPrefix(path, "/"), "/", 2)[0]
+		if bucket == "" {
+			bucket = randString(60, rand.NewSource(time.Now().UnixNano()), "probe-bucket-")
+		}
+
+		msg := pingProbeMessage{Alias: alias, Endpoint: hostCfg.URL, Bucket: bucket}
+		for _, c := range pingProbeCombinations {
+			r := pingProbeResult{pingProbeCombination: c, Style: c.Style(), OK: true}
+			if err := probeCombination(ctx, hostCfg, bucket, c); err != nil {
+				r.OK = false
+				r.Error = err.ToGoError().Error()
+			}
+			msg.Results = append(msg.Results, r)
+		}
+		msg.Recommended = recommendProbeCombination(msg.Results)
+		if msg.Recommended == nil {
+			failed = true
+		}
+		printMsg(msg)
+	}
+	if failed {
+		return exitStatus(globalErrorExitStatus)
+	}
+	return nil
+}
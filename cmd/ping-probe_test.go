@@ -0,0 +1,42 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import "testing"
+
+func TestRecommendProbeCombination(t *testing.T) {
+	result := func(path, api string, ok bool) pingProbeResult {
+		c := pingProbeCombination{Path: path, Signature: api}
+		return pingProbeResult{pingProbeCombination: c, Style: c.Style(), OK: ok}
+	}
+	testCases := []struct {
+		results  []pingProbeResult
+		expected *pingProbeCombination
+	}{
+		{nil, nil},
+		{[]pingProbeResult{result("on", "s3v4", false), result("off", "s3v4", false)}, nil},
+		{[]pingProbeResult{result("on", "s3v4", true), result("off", "s3v4", true)}, &pingProbeCombination{"on", "s3v4"}},
+		{[]pingProbeResult{result("on", "s3v4", false), result("off", "s3v4", false), result("on", "s3v2", false), result("off", "s3v2", true)}, &pingProbeCombination{"off", "s3v2"}},
+	}
+	for i, testCase := range testCases {
+		got := recommendProbeCombination(testCase.results)
+		if (got == nil) != (testCase.expected == nil) || got != nil && *got != *testCase.expected {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.expected, got)
+		}
+	}
+}
@@ -58,6 +58,14 @@ var pingFlags = []cli.Flag{
 		Name:  "distributed, a",
 		Usage: "ping all the servers in the cluster, use it when you have direct access to nodes/pods",
 	},
+	cli.BoolFlag{
+		Name:  "check-skew",
+		Usage: "report the clock skew between this machine and every TARGET",
+	},
+	cli.BoolFlag{
+		Name:  "probe",
+		Usage: "try path-style and virtual-host addressing with S3v4 and S3v2 signatures and report which work",
+	},
 }
 
 // return latency and liveness probe.
@@ -90,6 +98,12 @@ EXAMPLES:
 
   4. Stop pinging when error count > 20.
      {{.Prompt}} {{.HelpName}} --error-count 20 myminio
+
+  5. Report the clock skew of this machine against two aliases.
+     {{.Prompt}} {{.HelpName}} --check-skew myminio play
+
+  6. Find the addressing style and signature version supported by a third-party endpoint.
+     {{.Prompt}} {{.HelpName}} --probe s3appliance/mybucket
 `,
 }
 
@@ -339,10 +353,19 @@ func mainPing(cliCtx *cli.Context) error {
 
 	console.SetColor("Info", color.New(color.FgGreen, color.Bold))
 	console.SetColor("InfoFail", color.New(color.FgRed, color.Bold))
+	console.SetColor("SkewWarn", color.New(color.FgYellow, color.Bold))
 
 	ctx, cancel := context.WithCancel(globalContext)
 	defer cancel()
 
+	if cliCtx.Bool("check-skew") {
+		return pingSkew(ctx, cliCtx.Args())
+	}
+
+	if cliCtx.Bool("probe") {
+		return pingProbe(ctx, cliCtx.Args())
+	}
+
 	aliasedURL := cliCtx.Args().Get(0)
 	admClient, err := newAdminClient(aliasedURL)
 	fatalIf(err.Trace(aliasedURL), "Unable to initialize admin client for `"+aliasedURL+"`.")
@@ -391,3 +414,65 @@ func mainPing(cliCtx *cli.Context) error {
 	}
 	return nil
 }
+
+// pingSkewMessage reports the clock skew against an alias.
+type pingSkewMessage struct {
+	Status   string  `json:"status"`
+	Alias    string  `json:"alias"`
+	Endpoint string  `json:"endpoint"`
+	Skew     float64 `json:"skewSeconds"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// String colorized ping skew message.
+func (m pingSkewMessage) String() string {
+	if m.Error != "" {
+		return console.Colorize("InfoFail", fmt.Sprintf("%s: %s", m.Alias, m.Error))
+	}
+	skew := time.Duration(m.Skew * float64(time.Second))
+	theme := "Info"
+	switch {
+	case skew > doctorMaxClockSkew || skew < -doctorMaxClockSkew:
+		theme = "InfoFail"
+	case skew > time.Minute || skew < -time.Minute:
+		theme = "SkewWarn"
+	}
+	return console.Colorize(theme, m.Alias+": ") + m.Endpoint + "\t" + describeClockSkew(skew)
+}
+
+// JSON jsonified ping skew message.
+func (m pingSkewMessage) JSON() string {
+	m.Status = "success"
+	if m.Error != "" {
+		m.Status = "error"
+	}
+	skewJSONBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(skewJSONBytes)
+}
+
+// pingSkew measures and prints the clock skew against every alias.
+func pingSkew(ctx context.Context, aliasedURLs []string) error {
+	var failed bool
+	for _, aliasedURL := range aliasedURLs {
+		alias, _ := url2Alias(aliasedURL)
+		hostCfg := mustGetHostConfig(alias)
+		if hostCfg == nil {
+			fatalIf(errInvalidAliasedURL(aliasedURL), "No such alias `"+alias+"` found.")
+		}
+		msg := pingSkewMessage{Alias: alias, Endpoint: hostCfg.URL}
+		skew, err := measureClockSkew(ctx, hostCfg.URL, 10*time.Second)
+		if err != nil {
+			msg.Error = err.ToGoError().Error()
+			failed = true
+		} else {
+			msg.Skew = skew.Round(time.Millisecond).Seconds()
+		}
+		printMsg(msg)
+	}
+	if failed {
+		return exitStatus(globalErrorExitStatus)
+	}
+	return nil
+}
@@ -24,9 +24,11 @@ import (
 	"syscall"
 
 	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
 	"github.com/minio/cli"
 	minio "github.com/trinet2005/oss-go-sdk"
 	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
 )
 
 func defaultPartSize() string {
@@ -52,7 +54,7 @@ var pipeFlags = []cli.Flag{
 		Usage: "apply one or more tags to the uploaded objects",
 	},
 	cli.IntFlag{
-		Name:  "concurrent",
+		Name:  "concurrent, parallel-parts",
 		Value: 1,
 		Usage: "allow N concurrent uploads [WARNING: will use more memory use it with caution]",
 	},
@@ -61,6 +63,18 @@ var pipeFlags = []cli.Flag{
 		Value: defaultPartSize(),
 		Usage: "customize chunk size for each concurrent upload",
 	},
+	cli.BoolFlag{
+		Name:  "if-not-exists",
+		Usage: "do not overwrite the object if already present on target",
+	},
+	cli.StringSliceFlag{
+		Name:  "tee",
+		Usage: "also write the stream to this target, can be repeated",
+	},
+	cli.BoolFlag{
+		Name:  "stdout",
+		Usage: "also write the stream to STDOUT",
+	},
 	cli.IntFlag{
 		Name:   "pipe-max-size",
 		Usage:  "increase the pipe buffer size to a custom value",
@@ -110,6 +124,15 @@ EXAMPLES:
 
   7. Set tags to the uploaded objects
       {{.Prompt}} tar cvf - . | {{.HelpName}} --tags "category=prod&type=backup" play/mybucket/backup.tar
+
+  8. Stream a large backup over a high latency link, sending 8 parts of 128MiB in parallel.
+      {{.Prompt}} tar cvf - /data | {{.HelpName}} --part-size 128MiB --parallel-parts 8 play/mybucket/data.tar
+
+  9. Write a nightly dump only if no other writer already uploaded it.
+      {{.Prompt}} pg_dumpall | {{.HelpName}} --if-not-exists play/mybucket/dumps/2023-10-01.sql
+
+  10. Ingest a stream into a bucket, backing it up to another site and passing it to the next command.
+      {{.Prompt}} tar cvf - /data | {{.HelpName}} --tee s3/backup/data.tar --stdout play/mybucket/data.tar | sha256sum
 `,
 }
 
@@ -124,9 +147,37 @@ func pipe(ctx *cli.Context, targetURL string, encKeyDB map[string][]prefixSSEPai
 		return catOut(os.Stdin, -1).Trace()
 	}
 
-	storageClass := ctx.String("storage-class")
-	alias, _ := url2Alias(targetURL)
-	sseKey := getSSE(targetURL, encKeyDB[alias])
+	targetURLs := append([]string{targetURL}, ctx.StringSlice("tee")...)
+	toStdout := ctx.Bool("stdout")
+
+	// Status messages would be mixed with the stream on STDOUT.
+	printStatus := printMsg
+	if toStdout {
+		printStatus = printMsgStderr
+	}
+
+	if ctx.Bool("if-not-exists") {
+		// Only the targets which already exist are skipped.
+		var uploadURLs []string
+		for _, targetURL := range targetURLs {
+			alias, urlStrFull, _, err := expandAlias(targetURL)
+			if err != nil {
+				return err.Trace(targetURL)
+			}
+			if err = checkTargetNotExists(globalContext, alias, urlStrFull, getTargetSSE(targetURL, encKeyDB[alias])); err != nil {
+				if isErrTargetExists(err) {
+					printStatus(copySkippedMessage{Source: "stdin", Target: targetURL})
+					continue
+				}
+				return err.Trace(targetURL)
+			}
+			uploadURLs = append(uploadURLs, targetURL)
+		}
+		if len(uploadURLs) == 0 && !toStdout {
+			return nil
+		}
+		targetURLs = uploadURLs
+	}
 
 	multipartThreads := ctx.Int("concurrent")
 	if multipartThreads > 1 {
@@ -147,17 +198,35 @@ func pipe(ctx *cli.Context, targetURL string, encKeyDB map[string][]prefixSSEPai
 	// Ignore size, since os.Stat() would not return proper size all the time
 	// for local filesystem for example /proc files.
 	opts := PutOptions{
-		sse:              sseKey,
-		storageClass:     storageClass,
-		metadata:         meta,
+		storageClass:     ctx.String("storage-class"),
 		multipartSize:    multipartSize,
 		multipartThreads: uint(multipartThreads),
-		concurrentStream: ctx.IsSet("concurrent"),
+		concurrentStream: ctx.IsSet("concurrent") || ctx.IsSet("parallel-parts"),
+		ifNotExists:      ctx.Bool("if-not-exists"),
 	}
 
-	pg := newProgressBar(0)
+	// The progress bar would be mixed with the stream on STDOUT.
+	var pg io.Writer = io.Discard
+	if !toStdout {
+		pg = newProgressBar(0)
+	}
+	reader := io.TeeReader(os.Stdin, pg)
 
-	_, err := putTargetStreamWithURL(targetURL, io.TeeReader(os.Stdin, pg), -1, opts)
+	var err *probe.Error
+	if len(targetURLs) == 1 && !toStdout {
+		targetURL = targetURLs[0]
+		alias, _ := url2Alias(targetURL)
+		opts.sse = getTargetSSE(targetURL, encKeyDB[alias])
+		opts.metadata = meta
+		_, err = putTargetStreamWithURL(targetURL, reader, -1, opts)
+		if err != nil && opts.ifNotExists && isErrTargetExists(err) {
+			// Created by a concurrent writer since checkTargetNotExists.
+			printStatus(copySkippedMessage{Source: "stdin", Target: targetURL})
+			return nil
+		}
+	} else {
+		err = teeTargetStreams(targetURLs, reader, toStdout, encKeyDB, meta, opts, printStatus)
+	}
 	// TODO: See if this check is necessary.
 	switch e := err.ToGoError().(type) {
 	case *os.PathError:
@@ -169,6 +238,64 @@ func pipe(ctx *cli.Context, targetURL string, encKeyDB map[string][]prefixSSEPai
 	return err.Trace(targetURL)
 }
 
+// teeTargetStreams writes the stream read from reader to all the
+// targets at once, and to STDOUT if toStdout is set. The upload
+// to every target fails when one of them fails, except for the
+// targets created meanwhile with --if-not-exists which are skipped.
+func teeTargetStreams(targetURLs []string, reader io.Reader, toStdout bool, encKeyDB map[string][]prefixSSEPair, meta map[string]string, opts PutOptions, printStatus func(message)) *probe.Error {
+	writers := make([]io.Writer, 0, len(targetURLs)+1)
+	pipeWriters := make([]*io.PipeWriter, 0, len(targetURLs))
+	errCh := make(chan *probe.Error, len(targetURLs))
+	for _, targetURL := range targetURLs {
+		pr, pw := io.Pipe()
+		writers = append(writers, pw)
+		pipeWriters = append(pipeWriters, pw)
+
+		alias, _ := url2Alias(targetURL)
+		targetOpts := opts
+		targetOpts.sse = getTargetSSE(targetURL, encKeyDB[alias])
+		// putTargetStreamWithURL sets the content type of every target.
+		targetOpts.metadata = make(map[string]string, len(meta)+1)
+		for k, v := range meta {
+			targetOpts.metadata[k] = v
+		}
+		go func(targetURL string) {
+			_, err := putTargetStreamWithURL(targetURL, pr, -1, targetOpts)
+			if err != nil && targetOpts.ifNotExists && isErrTargetExists(err) {
+				// Keep streaming to the other targets.
+				printStatus(copySkippedMessage{Source: "stdin", Target: targetURL})
+				io.Copy(io.Discard, pr)
+				err = nil
+			}
+			if err != nil {
+				// Unblock the writes to this target.
+				pr.CloseWithError(err.ToGoError())
+				err = err.Trace(targetURL)
+			}
+			errCh <- err
+		}(targetURL)
+	}
+	if toStdout {
+		writers = append(writers, os.Stdout)
+	}
+
+	_, e := io.Copy(io.MultiWriter(writers...), reader)
+	for _, pw := range pipeWriters {
+		pw.CloseWithError(e)
+	}
+
+	var err *probe.Error
+	for range targetURLs {
+		if targetErr := <-errCh; targetErr != nil && err == nil {
+			err = targetErr
+		}
+	}
+	if err == nil && e != nil {
+		err = probe.NewError(e)
+	}
+	return err
+}
+
 // checkPipeSyntax - validate arguments passed by user
 func checkPipeSyntax(ctx *cli.Context) {
 	if len(ctx.Args()) != 1 {
@@ -180,6 +307,10 @@ func checkPipeSyntax(ctx *cli.Context) {
 func mainPipe(ctx *cli.Context) error {
 	// validate pipe input arguments.
 	checkPipeSyntax(ctx)
+
+	// Additional command specific theme customization.
+	console.SetColor("CopySkipped", color.New(color.FgYellow))
+
 	// Parse encryption keys per command.
 	encKeyDB, err := getEncKeys(ctx)
 	fatalIf(err, "Unable to parse encryption keys.")
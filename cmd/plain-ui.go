@@ -0,0 +1,161 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// plainUIInterval is the minimum interval between two status updates
+// printed with --no-tui.
+const plainUIInterval = 5 * time.Second
+
+// uiProgram runs the model of a live view, interactively or, with
+// --no-tui, as plain text status updates.
+type uiProgram interface {
+	Run() (tea.Model, error)
+	Send(msg tea.Msg)
+	Quit()
+}
+
+// newUIProgram returns the program running model.
+func newUIProgram(model tea.Model, opts ...tea.ProgramOption) uiProgram {
+	if globalNoTUI {
+		return newPlainUI(model, os.Stdout, plainUIInterval)
+	}
+	return tea.NewProgram(model, opts...)
+}
+
+var ansiEscapeRegexp = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+// plainText returns the view of a model without its escape sequences,
+// trailing spaces and blank lines.
+func plainText(view string) string {
+	var lines []string
+	for _, line := range strings.Split(ansiEscapeRegexp.ReplaceAllString(view, ""), "\n") {
+		if line = strings.TrimRight(line, " \t\r"); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// plainUI runs a bubbletea model without a terminal: the messages and
+// commands are processed as bubbletea does, except the spinner ticks,
+// and the view is printed as plain text when it changed, at most once
+// per interval and once more when the model quits. Screen readers and
+// CI logs get the same information as the interactive view.
+type plainUI struct {
+	model    tea.Model
+	out      io.Writer
+	interval time.Duration
+	msgs     chan tea.Msg
+	done     chan struct{}
+	last     string
+}
+
+func newPlainUI(model tea.Model, out io.Writer, interval time.Duration) *plainUI {
+	return &plainUI{
+		model:    model,
+		out:      out,
+		interval: interval,
+		msgs:     make(chan tea.Msg),
+		done:     make(chan struct{}),
+	}
+}
+
+// Send sends a message to the model, it is dropped once the model quit.
+func (p *plainUI) Send(msg tea.Msg) {
+	select {
+	case p.msgs <- msg:
+	case <-p.done:
+	}
+}
+
+// Quit stops the program.
+func (p *plainUI) Quit() {
+	p.Send(tea.Quit())
+}
+
+// exec runs cmd in the background and sends its messages to the model.
+func (p *plainUI) exec(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	go func() {
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for _, cmd := range batch {
+				p.exec(cmd)
+			}
+			return
+		}
+		if msg != nil {
+			p.Send(msg)
+		}
+	}()
+}
+
+// flush prints the view if it changed since it was last printed.
+func (p *plainUI) flush() {
+	view := plainText(p.model.View())
+	if view == "" || view == p.last {
+		return
+	}
+	p.last = view
+	fmt.Fprintf(p.out, "[%s]\n%s\n", time.Now().Format("15:04:05"), view)
+}
+
+// Run processes the messages until the model quits.
+func (p *plainUI) Run() (tea.Model, error) {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	p.exec(p.model.Init())
+	for {
+		select {
+		case <-globalContext.Done():
+			p.flush()
+			return p.model, nil
+		case <-ticker.C:
+			p.flush()
+		case msg := <-p.msgs:
+			switch msg.(type) {
+			case tea.QuitMsg:
+				p.flush()
+				return p.model, nil
+			case spinner.TickMsg:
+				// Spinners only animate the interactive view.
+				continue
+			}
+			var cmd tea.Cmd
+			p.model, cmd = p.model.Update(msg)
+			p.exec(cmd)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPlainText(t *testing.T) {
+	testCases := []struct {
+		view   string
+		expect string
+	}{
+		{"", ""},
+		{"\n  \n", ""},
+		{"\x1b[1mBold\x1b[0m  \n\n\x1b[38;5;205mPink\x1b[0m\n", "Bold\nPink"},
+	}
+	for i, testCase := range testCases {
+		if text := plainText(testCase.view); text != testCase.expect {
+			t.Fatalf("Test %d: expected %q, got %q", i+1, testCase.expect, text)
+		}
+	}
+}
+
+// plainUITestModel counts the messages it receives and quits on the
+// string "quit".
+type plainUITestModel struct {
+	count int
+}
+
+func (m *plainUITestModel) Init() tea.Cmd { return nil }
+
+func (m *plainUITestModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m.count++
+	if msg == "quit" {
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m *plainUITestModel) View() string {
+	return "\x1b[1mcount\x1b[0m: " + strings.Repeat("+", m.count)
+}
+
+func TestPlainUI(t *testing.T) {
+	var out bytes.Buffer
+	p := newPlainUI(&plainUITestModel{}, &out, time.Hour)
+	go func() {
+		p.Send("a")
+		p.Send(spinner.TickMsg{})
+		p.Send("b")
+		p.Send("quit")
+	}()
+	model, e := p.Run()
+	if e != nil {
+		t.Fatal(e)
+	}
+	// Spinner ticks do not reach the model.
+	if count := model.(*plainUITestModel).count; count != 3 {
+		t.Fatalf("expected 3 messages, got %d", count)
+	}
+	if lines := strings.Split(strings.TrimSpace(out.String()), "\n"); len(lines) != 2 || lines[1] != "count: +++" {
+		t.Fatalf("expected the final view, got %q", out.String())
+	}
+	// Messages sent after the model quit are dropped.
+	p.Send("c")
+}
@@ -20,6 +20,8 @@ package cmd
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"os"
 	"strings"
 
 	"github.com/trinet2005/oss-pkg/console"
@@ -33,6 +35,18 @@ type message interface {
 
 // printMsg prints message string or JSON structure depending on the type of output console.
 func printMsg(msg message) {
+	console.Println(formatMsg(msg))
+}
+
+// printMsgStderr is like printMsg, printing to STDERR for commands
+// which write data to STDOUT.
+func printMsgStderr(msg message) {
+	fmt.Fprintln(os.Stderr, formatMsg(msg))
+}
+
+// formatMsg returns the message string or JSON structure depending on
+// the type of output console.
+func formatMsg(msg message) string {
 	var msgStr string
 	if !globalJSON {
 		msgStr = msg.String()
@@ -46,6 +60,5 @@ func printMsg(msg message) {
 			}
 		}
 	}
-	msgStr = strings.TrimSuffix(msgStr, "\n")
-	console.Println(msgStr)
+	return strings.TrimSuffix(msgStr, "\n")
 }
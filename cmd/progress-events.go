@@ -0,0 +1,222 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+// Interval between two progress records.
+const progressEventsInterval = time.Second
+
+// progressEventMessage is a progress record of a transfer,
+// written as a single line of JSON.
+type progressEventMessage struct {
+	Status       string    `json:"status"`
+	Time         time.Time `json:"time"`
+	Transferred  int64     `json:"transferred"`
+	Total        int64     `json:"total"`
+	Objects      int64     `json:"objects"`
+	TotalObjects int64     `json:"totalObjects"`
+	Rate         float64   `json:"rate"`
+	ETA          int64     `json:"eta,omitempty"`
+	Current      string    `json:"current,omitempty"`
+}
+
+// progressEvents periodically writes progress records of a
+// transfer to a file, a named pipe or stdout. A nil
+// *progressEvents is valid and reports nothing.
+type progressEvents struct {
+	// Keep the counters first for 64bit alignment of atomic
+	// operations on 32 bit machines.
+	transferred  int64
+	total        int64
+	objects      int64
+	totalObjects int64
+
+	current   atomic.Value
+	w         io.Writer
+	closer    io.Closer
+	startTime time.Time
+	doneCh    chan struct{}
+	wg        sync.WaitGroup
+}
+
+// newProgressEvents opens target for progress records, target
+// "-" is stdout. It returns nil when target is empty.
+func newProgressEvents(target string) (*progressEvents, *probe.Error) {
+	if target == "" {
+		return nil, nil
+	}
+	p := &progressEvents{
+		w:      os.Stdout,
+		doneCh: make(chan struct{}),
+	}
+	if target != "-" {
+		// Opening a named pipe blocks until a reader opens it.
+		f, e := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+		if e != nil {
+			return nil, probe.NewError(e).Trace(target)
+		}
+		p.w, p.closer = f, f
+	}
+	return p, nil
+}
+
+// Read counts the transferred bytes, it implements the
+// progress hook of the transfer functions.
+func (p *progressEvents) Read(b []byte) (int, error) {
+	p.addTransferred(int64(len(b)))
+	return len(b), nil
+}
+
+// addTransferred adds n bytes to the transferred bytes.
+func (p *progressEvents) addTransferred(n int64) {
+	if p == nil {
+		return
+	}
+	atomic.AddInt64(&p.transferred, n)
+}
+
+// addTotal adds size bytes and count objects to the totals.
+func (p *progressEvents) addTotal(size, count int64) {
+	if p == nil {
+		return
+	}
+	atomic.AddInt64(&p.total, size)
+	atomic.AddInt64(&p.totalObjects, count)
+}
+
+// setTotal sets the totals of the transfer.
+func (p *progressEvents) setTotal(size, count int64) {
+	if p == nil {
+		return
+	}
+	atomic.StoreInt64(&p.total, size)
+	atomic.StoreInt64(&p.totalObjects, count)
+}
+
+// setCurrent sets the object being transferred.
+func (p *progressEvents) setCurrent(name string) {
+	if p == nil {
+		return
+	}
+	p.current.Store(name)
+}
+
+// objectDone counts a transferred object.
+func (p *progressEvents) objectDone() {
+	if p == nil {
+		return
+	}
+	atomic.AddInt64(&p.objects, 1)
+}
+
+// record returns the current progress record.
+func (p *progressEvents) record(status string) progressEventMessage {
+	now := time.Now()
+	m := progressEventMessage{
+		Status:       status,
+		Time:         now.UTC(),
+		Transferred:  atomic.LoadInt64(&p.transferred),
+		Total:        atomic.LoadInt64(&p.total),
+		Objects:      atomic.LoadInt64(&p.objects),
+		TotalObjects: atomic.LoadInt64(&p.totalObjects),
+	}
+	m.Current, _ = p.current.Load().(string)
+	if elapsed := now.Sub(p.startTime).Seconds(); elapsed > 0 {
+		m.Rate = float64(m.Transferred) / elapsed
+	}
+	if m.Rate > 0 && m.Total > m.Transferred {
+		m.ETA = int64(float64(m.Total-m.Transferred) / m.Rate)
+	}
+	return m
+}
+
+// write writes a progress record as a line of JSON.
+func (p *progressEvents) write(m progressEventMessage) {
+	recordBytes, e := json.Marshal(m)
+	if e != nil {
+		return
+	}
+	p.w.Write(append(recordBytes, '\n'))
+}
+
+// start writes a progress record every progressEventsInterval
+// until stop is called.
+func (p *progressEvents) start() {
+	if p == nil {
+		return
+	}
+	p.startTime = time.Now()
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(progressEventsInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.doneCh:
+				return
+			case <-ticker.C:
+				p.write(p.record("progress"))
+			}
+		}
+	}()
+}
+
+// stop writes the final progress record and closes the output.
+func (p *progressEvents) stop() {
+	if p == nil {
+		return
+	}
+	close(p.doneCh)
+	p.wg.Wait()
+	p.write(p.record("done"))
+	if p.closer != nil {
+		p.closer.Close()
+	}
+}
+
+// progressHooks forwards the transferred bytes to several
+// progress hooks.
+type progressHooks []io.Reader
+
+// Read implements the io.Reader interface
+func (h progressHooks) Read(b []byte) (int, error) {
+	for _, hook := range h {
+		hook.Read(b)
+	}
+	return len(b), nil
+}
+
+// withProgressEvents returns a progress hook reporting to both
+// progress and events.
+func withProgressEvents(progress io.Reader, events *progressEvents) io.Reader {
+	if events == nil {
+		return progress
+	}
+	return progressHooks{progress, events}
+}
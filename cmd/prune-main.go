@@ -0,0 +1,408 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	minio "github.com/trinet2005/oss-go-sdk"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+var pruneFlags = []cli.Flag{
+	cli.IntFlag{
+		Name:  "keep-last",
+		Usage: "keep the N most recent versions of every object",
+	},
+	cli.IntFlag{
+		Name:  "keep-days",
+		Usage: "keep the versions of every object created in the last D days",
+	},
+	cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "print the versions that would be kept and removed, without removing any",
+	},
+	cli.BoolFlag{
+		Name:  "force",
+		Usage: "allow the removal of versions",
+	},
+}
+
+// Prune object versions.
+var pruneCmd = cli.Command{
+	Name:         "prune",
+	Usage:        "remove old object versions, keeping the last N versions and/or the versions of the last D days",
+	Action:       mainPrune,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(pruneFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+NOTE:
+  The current version of an object is always kept. A version is removed only when it is kept by neither
+  '--keep-last' nor '--keep-days'. Versions under retention or legal hold are never removed.
+
+EXAMPLES:
+  1. Print the plan to keep the 3 most recent versions of every object of 'backups' bucket.
+     {{.Prompt}} {{.HelpName}} --keep-last 3 --dry-run s3/backups
+
+  2. Remove the versions older than 30 days under the 'db/' prefix of 'backups' bucket.
+     {{.Prompt}} {{.HelpName}} --keep-days 30 --force s3/backups/db/
+
+  3. Keep the 5 most recent versions and all the versions of the last 7 days of every object.
+     {{.Prompt}} {{.HelpName}} --keep-last 5 --keep-days 7 --force s3/backups
+`,
+}
+
+const (
+	pruneKeep   = "keep"
+	prunePrune  = "prune"
+	pruneLocked = "locked"
+)
+
+// pruneDecision is the plan for a single version.
+type pruneDecision struct {
+	content *ClientContent
+	action  string
+	reason  string
+}
+
+// planPrune decides which versions of a single object are kept, the
+// versions being sorted newest first. The current version is always
+// kept, the others are kept if they are among the keepLast most recent
+// ones or were created less than keepDays days before now.
+func planPrune(versions []*ClientContent, keepLast, keepDays int, now time.Time) []pruneDecision {
+	decisions := make([]pruneDecision, 0, len(versions))
+	for i, version := range versions {
+		decision := pruneDecision{content: version, action: pruneKeep}
+		switch {
+		case i == 0:
+			decision.reason = "current version"
+		case i < keepLast:
+			decision.reason = fmt.Sprintf("among the last %d versions", keepLast)
+		case keepDays > 0 && now.Sub(version.Time) < time.Duration(keepDays)*24*time.Hour:
+			decision.reason = fmt.Sprintf("newer than %d days", keepDays)
+		default:
+			decision.action = prunePrune
+			switch {
+			case keepLast > 0 && keepDays > 0:
+				decision.reason = fmt.Sprintf("not among the last %d versions and older than %d days", keepLast, keepDays)
+			case keepLast > 0:
+				decision.reason = fmt.Sprintf("not among the last %d versions", keepLast)
+			default:
+				decision.reason = fmt.Sprintf("older than %d days", keepDays)
+			}
+		}
+		decisions = append(decisions, decision)
+	}
+	return decisions
+}
+
+// pruneMessage is printed for every version of the plan with
+// --dry-run, and for removed and locked versions otherwise.
+type pruneMessage struct {
+	Status         string    `json:"status"`
+	Key            string    `json:"key"`
+	VersionID      string    `json:"versionId"`
+	IsDeleteMarker bool      `json:"isDeleteMarker,omitempty"`
+	ModTime        time.Time `json:"modTime"`
+	Size           int64     `json:"size"`
+	Action         string    `json:"action"`
+	Reason         string    `json:"reason"`
+	DryRun         bool      `json:"dryRun,omitempty"`
+}
+
+// String colorized prune message
+func (p pruneMessage) String() string {
+	action := strings.ToUpper(p.Action)
+	if p.Action == prunePrune && !p.DryRun {
+		action = "REMOVED"
+	}
+	version := p.VersionID
+	if p.IsDeleteMarker {
+		version += ", delete marker"
+	}
+	theme := "PruneKeep"
+	switch p.Action {
+	case prunePrune:
+		theme = "PrunePrune"
+	case pruneLocked:
+		theme = "PruneLocked"
+	}
+	return console.Colorize(theme, fmt.Sprintf("%-7s", action)) + " " +
+		console.Colorize("PruneKey", fmt.Sprintf("`%s`", p.Key)) +
+		fmt.Sprintf(" (versionId=%s) (modTime=%s) %s", version, p.ModTime.Format(printDate), p.Reason)
+}
+
+// JSON jsonified prune message
+func (p pruneMessage) JSON() string {
+	p.Status = "success"
+	msgBytes, e := json.MarshalIndent(p, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(msgBytes)
+}
+
+// pruneSummaryMessage totals the versions of a prune.
+type pruneSummaryMessage struct {
+	Status     string `json:"status"`
+	Kept       int64  `json:"kept"`
+	Pruned     int64  `json:"pruned"`
+	PrunedSize int64  `json:"prunedSize"`
+	Locked     int64  `json:"locked"`
+	DryRun     bool   `json:"dryRun,omitempty"`
+}
+
+// String colorized prune summary message
+func (p pruneSummaryMessage) String() string {
+	verb := "Removed"
+	if p.DryRun {
+		verb = "Would remove"
+	}
+	return console.Colorize("PruneSummary", fmt.Sprintf("%s %d versions (%s), kept %d, skipped %d locked.",
+		verb, p.Pruned, humanize.IBytes(uint64(p.PrunedSize)), p.Kept, p.Locked))
+}
+
+// JSON jsonified prune summary message
+func (p pruneSummaryMessage) JSON() string {
+	p.Status = "success"
+	msgBytes, e := json.MarshalIndent(p, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(msgBytes)
+}
+
+// isVersionLocked returns true if the version is under legal hold or
+// under a retention period which is not over yet.
+func isVersionLocked(ctx context.Context, alias string, content *ClientContent) (bool, *probe.Error) {
+	clnt, err := newClientFromAlias(alias, content.URL.String())
+	if err != nil {
+		return false, err
+	}
+	hold, err := clnt.GetObjectLegalHold(ctx, content.VersionID)
+	if err != nil {
+		return false, err
+	}
+	if hold == "ON" {
+		return true, nil
+	}
+	mode, until, err := clnt.GetObjectRetention(ctx, content.VersionID)
+	if err != nil {
+		if minio.ToErrorResponse(err.ToGoError()).Code == "NoSuchObjectLockConfiguration" {
+			return false, nil
+		}
+		return false, err
+	}
+	return mode != "" && until.After(UTCNow()), nil
+}
+
+// checkPruneSyntax - validate all the passed arguments
+func checkPruneSyntax(cliCtx *cli.Context) {
+	if len(cliCtx.Args()) != 1 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	keepLast, keepDays := cliCtx.Int("keep-last"), cliCtx.Int("keep-days")
+	if keepLast < 0 || keepDays < 0 {
+		fatalIf(errInvalidArgument(), "--keep-last and --keep-days cannot be negative.")
+	}
+	if keepLast == 0 && keepDays == 0 {
+		fatalIf(errInvalidArgument(), "Please specify --keep-last and/or --keep-days.")
+	}
+	if !cliCtx.Bool("dry-run") && !cliCtx.Bool("force") {
+		fatalIf(errDummy().Trace(),
+			"Pruning requires --force flag. This operation is *IRREVERSIBLE*. Please review the plan with --dry-run first.")
+	}
+	targetURL := cliCtx.Args().Get(0)
+	_, _, hostCfg, err := expandAlias(targetURL)
+	fatalIf(err.Trace(targetURL), "Unable to parse target `"+targetURL+"`.")
+	if hostCfg == nil {
+		fatalIf(errInvalidArgument().Trace(targetURL), "Target `"+targetURL+"` should be on an alias.")
+	}
+	if _, bucket := url2Alias(targetURL); bucket == "" {
+		fatalIf(errInvalidArgument().Trace(targetURL), "Target `"+targetURL+"` should be a bucket or a prefix.")
+	}
+}
+
+// mainPrune is the entry point for prune command.
+func mainPrune(cliCtx *cli.Context) error {
+	ctx, cancelPrune := context.WithCancel(globalContext)
+	defer cancelPrune()
+
+	checkPruneSyntax(cliCtx)
+
+	// Additional command specific theme customization.
+	console.SetColor("PruneKeep", color.New(color.FgGreen))
+	console.SetColor("PrunePrune", color.New(color.FgRed, color.Bold))
+	console.SetColor("PruneLocked", color.New(color.FgYellow))
+	console.SetColor("PruneKey", color.New(color.Bold))
+	console.SetColor("PruneSummary", color.New(color.FgCyan, color.Bold))
+
+	keepLast, keepDays := cliCtx.Int("keep-last"), cliCtx.Int("keep-days")
+	isFake := cliCtx.Bool("dry-run")
+	urlStr := cliCtx.Args().Get(0)
+
+	locking, err := isBucketLockEnabled(ctx, urlStr)
+	fatalIf(err.Trace(urlStr), "Unable to get the object lock configuration of `"+urlStr+"`.")
+
+	targetAlias, targetURL, _ := mustExpandAlias(urlStr)
+	clnt, err := newClientFromAlias(targetAlias, targetURL)
+	fatalIf(err.Trace(urlStr), "Unable to initialize target `"+urlStr+"`.")
+
+	summary := pruneSummaryMessage{DryRun: isFake}
+	contentCh := make(chan *ClientContent)
+	resultCh := clnt.Remove(ctx, false, false, false, false, contentCh)
+
+	var pruneErr error
+	// Versions sent for removal, by version id, reported once removed.
+	pending := make(map[string][]pruneMessage)
+	handleResult := func(result RemoveResult) {
+		if result.Err != nil {
+			key := path.Join(targetAlias, result.BucketName, result.ObjectName)
+			errorIf(result.Err.Trace(key), "Failed to remove `"+key+"`.")
+			pruneErr = exitStatus(globalErrorExitStatus)
+			return
+		}
+		msgs := pending[result.ObjectVersionID]
+		for i, msg := range msgs {
+			if !strings.HasSuffix(msg.Key, "/"+result.ObjectName) {
+				continue
+			}
+			summary.Pruned++
+			summary.PrunedSize += msg.Size
+			printMsg(msg)
+			if msgs = append(msgs[:i], msgs[i+1:]...); len(msgs) == 0 {
+				delete(pending, result.ObjectVersionID)
+			} else {
+				pending[result.ObjectVersionID] = msgs
+			}
+			return
+		}
+	}
+
+	// prune plans and applies the removal of the versions of one object.
+	prune := func(versions []*ClientContent) {
+		for _, decision := range planPrune(versions, keepLast, keepDays, UTCNow()) {
+			content := decision.content
+			msg := pruneMessage{
+				Key:            targetAlias + getKey(content),
+				VersionID:      content.VersionID,
+				IsDeleteMarker: content.IsDeleteMarker,
+				ModTime:        content.Time,
+				Size:           content.Size,
+				Action:         decision.action,
+				Reason:         decision.reason,
+				DryRun:         isFake,
+			}
+			if msg.Action == prunePrune && locking {
+				locked, err := isVersionLocked(ctx, targetAlias, content)
+				if err != nil {
+					warningIf(warningSkipped, err.Trace(msg.Key), "Unable to get the retention of `"+msg.Key+"`, skipping it.")
+					pruneErr = exitStatus(globalErrorExitStatus)
+					continue
+				}
+				if locked {
+					msg.Action, msg.Reason = pruneLocked, "under retention or legal hold"
+				}
+			}
+
+			switch msg.Action {
+			case pruneKeep:
+				summary.Kept++
+				if isFake {
+					printMsg(msg)
+				}
+				continue
+			case pruneLocked:
+				summary.Locked++
+				printMsg(msg)
+				continue
+			}
+
+			if isFake {
+				summary.Pruned++
+				summary.PrunedSize += content.Size
+				printMsg(msg)
+				continue
+			}
+			// Reported by handleResult once the removal succeeds.
+			pending[content.VersionID] = append(pending[content.VersionID], msg)
+			for sent := false; !sent; {
+				select {
+				case contentCh <- content:
+					sent = true
+				case result := <-resultCh:
+					handleResult(result)
+				}
+			}
+		}
+	}
+
+	// Versions of an object are listed newest first.
+	var (
+		versions []*ClientContent
+		listErr  error
+	)
+	for content := range clnt.List(ctx, ListOptions{
+		Recursive:         true,
+		WithOlderVersions: true,
+		WithDeleteMarkers: true,
+		ShowDir:           DirNone,
+	}) {
+		if content.Err != nil {
+			errorIf(content.Err.Trace(urlStr), "Unable to list `"+urlStr+"`.")
+			listErr = exitStatus(globalErrorExitStatus)
+			break
+		}
+		if len(versions) > 0 && versions[0].URL.Path != content.URL.Path {
+			prune(versions)
+			versions = nil
+		}
+		versions = append(versions, content)
+	}
+	// The versions of the last object may be incomplete if listing failed.
+	if len(versions) > 0 && listErr == nil {
+		prune(versions)
+	}
+
+	close(contentCh)
+	for result := range resultCh {
+		handleResult(result)
+	}
+
+	printMsg(summary)
+	if listErr != nil {
+		return listErr
+	}
+	return pruneErr
+}
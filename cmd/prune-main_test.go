@@ -0,0 +1,55 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPlanPrune(t *testing.T) {
+	now := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	// Versions created 0, 2, 5, 10 and 40 days ago, newest first.
+	var versions []*ClientContent
+	for _, days := range []int{0, 2, 5, 10, 40} {
+		versions = append(versions, &ClientContent{Time: now.Add(-time.Duration(days) * 24 * time.Hour)})
+	}
+
+	testCases := []struct {
+		keepLast, keepDays int
+		expected           []string
+	}{
+		{1, 0, []string{pruneKeep, prunePrune, prunePrune, prunePrune, prunePrune}},
+		{3, 0, []string{pruneKeep, pruneKeep, pruneKeep, prunePrune, prunePrune}},
+		{0, 7, []string{pruneKeep, pruneKeep, pruneKeep, prunePrune, prunePrune}},
+		{0, 1, []string{pruneKeep, prunePrune, prunePrune, prunePrune, prunePrune}},
+		{2, 30, []string{pruneKeep, pruneKeep, pruneKeep, pruneKeep, prunePrune}},
+		{10, 0, []string{pruneKeep, pruneKeep, pruneKeep, pruneKeep, pruneKeep}},
+	}
+
+	for i, testCase := range testCases {
+		var actions []string
+		for _, decision := range planPrune(versions, testCase.keepLast, testCase.keepDays, now) {
+			actions = append(actions, decision.action)
+		}
+		if !reflect.DeepEqual(actions, testCase.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, actions)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+var putFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "storage-class, sc",
+		Usage: "set storage class for the uploaded object",
+	},
+	cli.StringFlag{
+		Name:  "attr",
+		Usage: "add custom metadata for the object",
+	},
+	cli.BoolFlag{
+		Name:  "disable-multipart",
+		Usage: "disable multipart upload feature",
+	},
+	cli.BoolFlag{
+		Name:  "no-verify",
+		Usage: "do not send an MD5 checksum for the server to verify the upload",
+	},
+}
+
+// Upload a single file.
+var putCmd = cli.Command{
+	Name:         "put",
+	Usage:        "upload a local file as an object",
+	Action:       mainPut,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(append(putFlags, ioFlags...), globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] SOURCE TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+ENVIRONMENT VARIABLES:
+  MC_ENCRYPT_KEY:  list of comma delimited prefix=secret values
+
+NOTE:
+  Every uploaded part carries an MD5 checksum verified by the server, unless '--no-verify' is set.
+
+EXAMPLES:
+  1. Upload a file to a bucket, keeping its name.
+     {{.Prompt}} {{.HelpName}} backup.tgz play/mybucket
+
+  2. Upload a file under a different object name.
+     {{.Prompt}} {{.HelpName}} backup.tgz play/mybucket/backups/2023-10-01.tgz
+
+  3. Upload a file with the REDUCED_REDUNDANCY storage class and custom metadata.
+     {{.Prompt}} {{.HelpName}} --storage-class REDUCED_REDUNDANCY --attr "key1=value1" backup.tgz play/mybucket/
+`,
+}
+
+// putMessage container for put messages
+type putMessage struct {
+	Status   string `json:"status"`
+	Source   string `json:"source"`
+	Target   string `json:"target"`
+	Size     int64  `json:"size"`
+	Checksum string `json:"checksum"`
+}
+
+// String colorized put message
+func (p putMessage) String() string {
+	return console.Colorize("Put", fmt.Sprintf("`%s` -> `%s`", p.Source, p.Target)) +
+		fmt.Sprintf(" (checksum %s)", p.Checksum)
+}
+
+// JSON jsonified put message
+func (p putMessage) JSON() string {
+	p.Status = "success"
+	putMessageBytes, e := json.MarshalIndent(p, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(putMessageBytes)
+}
+
+// checkPutSyntax - validate all the passed arguments
+func checkPutSyntax(cliCtx *cli.Context) {
+	args := cliCtx.Args()
+	if len(args) != 2 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	st, e := os.Stat(args.Get(0))
+	fatalIf(probe.NewError(e).Trace(args.Get(0)), "Unable to stat source `"+args.Get(0)+"`.")
+	if !st.Mode().IsRegular() {
+		fatalIf(errInvalidArgument().Trace(args.Get(0)), "Source `"+args.Get(0)+"` should be a regular file.")
+	}
+	_, _, hostCfg, err := expandAlias(args.Get(1))
+	fatalIf(err.Trace(args...), "Unable to parse target `"+args.Get(1)+"`.")
+	if hostCfg == nil {
+		fatalIf(errInvalidArgument().Trace(args...), "Target `"+args.Get(1)+"` should be on an alias.")
+	}
+}
+
+// putObject uploads a single local file to targetURL.
+func putObject(ctx context.Context, sourcePath, targetURL string, encKeyDB map[string][]prefixSSEPair, opts PutOptions) (putMessage, *probe.Error) {
+	msg := putMessage{Source: sourcePath, Target: targetURL, Checksum: checksumSkipped}
+
+	file, e := os.Open(sourcePath)
+	if e != nil {
+		return msg, probe.NewError(e).Trace(sourcePath)
+	}
+	defer file.Close()
+
+	st, e := file.Stat()
+	if e != nil {
+		return msg, probe.NewError(e).Trace(sourcePath)
+	}
+	msg.Size = st.Size()
+
+	alias, urlStrFull, _, err := expandAlias(targetURL)
+	if err != nil {
+		return msg, err.Trace(targetURL)
+	}
+	opts.sse = getTargetSSE(targetURL, encKeyDB[alias])
+	opts.metadata["Content-Type"] = guessURLContentType(sourcePath)
+
+	var pg ProgressReader
+	if !globalQuiet && !globalJSON {
+		bar := newProgressBar(st.Size())
+		bar.SetCaption(sourcePath + ":")
+		pg = bar
+	} else {
+		pg = newAccounter(st.Size())
+	}
+
+	if _, err = putTargetStream(ctx, alias, urlStrFull, "", "", "", file, st.Size(), pg, opts); err != nil {
+		return msg, err.Trace(sourcePath, targetURL)
+	}
+	if bar, ok := pg.(*progressBar); ok {
+		bar.ProgressBar.Finish()
+	}
+	if opts.md5 {
+		msg.Checksum = checksumVerified
+	}
+	return msg, nil
+}
+
+// mainPut is the entry point for put command.
+func mainPut(cliCtx *cli.Context) error {
+	ctx, cancelPut := context.WithCancel(globalContext)
+	defer cancelPut()
+
+	checkPutSyntax(cliCtx)
+
+	// Additional command specific theme customization.
+	console.SetColor("Put", color.New(color.FgGreen, color.Bold))
+
+	encKeyDB, err := getEncKeys(cliCtx)
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	sourcePath := cliCtx.Args().Get(0)
+	targetURL := cliCtx.Args().Get(1)
+	if isAliasURLDir(ctx, targetURL, encKeyDB, time.Time{}) {
+		targetURL = urlJoinPath(targetURL, filepath.Base(sourcePath))
+	}
+
+	metadata := map[string]string{}
+	if attr := cliCtx.String("attr"); attr != "" {
+		metadata, err = getMetaDataEntry(attr)
+		fatalIf(err, "Unable to parse attribute %v", attr)
+	}
+
+	msg, err := putObject(ctx, sourcePath, targetURL, encKeyDB, PutOptions{
+		metadata:         metadata,
+		storageClass:     cliCtx.String("storage-class"),
+		md5:              !cliCtx.Bool("no-verify"),
+		disableMultipart: cliCtx.Bool("disable-multipart"),
+	})
+	fatalIf(err, "Unable to upload `"+sourcePath+"`.")
+
+	printMsg(msg)
+	return nil
+}
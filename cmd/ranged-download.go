@@ -0,0 +1,333 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	minio "github.com/trinet2005/oss-go-sdk"
+	"github.com/trinet2005/oss-go-sdk/pkg/encrypt"
+	"github.com/trinet2005/oss-mc/pkg/hookreader"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+// Default size of a single range of a parallel ranged download.
+const defaultRangeSize = 64 << 20
+
+// rangeFetcher returns the content of the object between
+// start and end, both inclusive.
+type rangeFetcher func(ctx context.Context, start, end int64) (io.ReadCloser, *probe.Error)
+
+// offsetWriter writes sequentially to a file from a given offset.
+type offsetWriter struct {
+	file   *os.File
+	offset int64
+}
+
+func (w *offsetWriter) Write(p []byte) (int, error) {
+	n, e := w.file.WriteAt(p, w.offset)
+	w.offset += int64(n)
+	return n, e
+}
+
+// rangesJournalPath returns the path of the file recording how much of
+// the partial download partPath was downloaded without gaps.
+func rangesJournalPath(partPath string) string {
+	return partPath + ".ranges"
+}
+
+// writeRangesJournal durably records that the first complete bytes of
+// file are downloaded.
+func writeRangesJournal(file *os.File, complete int64) error {
+	if e := file.Sync(); e != nil {
+		return e
+	}
+	return writeFileAtomic(rangesJournalPath(file.Name()), []byte(strconv.FormatInt(complete, 10)))
+}
+
+// writeFileAtomic replaces file with data: a crash leaves either the
+// previous or the new content, never a partial one.
+func writeFileAtomic(file string, data []byte) error {
+	f, e := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".*.tmp")
+	if e != nil {
+		return e
+	}
+	tmpFile := f.Name()
+	if _, e = f.Write(data); e == nil {
+		e = f.Chmod(0o600)
+	}
+	if e == nil {
+		e = f.Sync()
+	}
+	if ce := f.Close(); e == nil {
+		e = ce
+	}
+	if e == nil {
+		e = os.Rename(tmpFile, file)
+	}
+	if e != nil {
+		os.Remove(tmpFile)
+		return e
+	}
+	// Persist the rename itself.
+	if dir, e := os.Open(filepath.Dir(file)); e == nil {
+		dir.Sync()
+		dir.Close()
+	}
+	return nil
+}
+
+// trimPartFile returns the size of the content of a partial download
+// which can be resumed. A ranged download extends the file to its final
+// size up front and records its progress in a journal: the file is then
+// truncated to the recorded size, and the journal removed, as its
+// remaining ranges may never have been written.
+func trimPartFile(file *os.File) (int64, error) {
+	st, e := file.Stat()
+	if e != nil {
+		return 0, e
+	}
+	journalPath := rangesJournalPath(file.Name())
+	data, e := os.ReadFile(journalPath)
+	if os.IsNotExist(e) {
+		return st.Size(), nil
+	}
+	if e != nil {
+		return 0, e
+	}
+	complete, e := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if e != nil || complete < 0 || complete > st.Size() {
+		complete = 0
+	}
+	if e = file.Truncate(complete); e != nil {
+		return 0, e
+	}
+	if e = file.Sync(); e != nil {
+		return 0, e
+	}
+	if e = os.Remove(journalPath); e != nil {
+		return 0, e
+	}
+	return complete, nil
+}
+
+// removePartFile removes a partial download and its journal.
+func removePartFile(partPath string) {
+	os.Remove(partPath)
+	os.Remove(rangesJournalPath(partPath))
+}
+
+// useRangedDownload returns true if an object of the given size
+// is worth downloading as several ranges fetched in parallel.
+func useRangedDownload(ranges uint, rangeSize, size int64) bool {
+	return ranges > 1 && size > rangeSize
+}
+
+// downloadRanges downloads the bytes of an object between from and size
+// into file with up to ranges concurrent ranged GET requests of rangeSize
+// bytes each. The file is first extended to its final size, sparsely on
+// file systems which support it, so that every range is written in place.
+// The ranges downloaded without gaps are recorded in a journal, see
+// trimPartFile, so that an interrupted download resumes after them even
+// if the process is killed. On failure the file is also truncated after
+// them right away.
+func downloadRanges(ctx context.Context, fetch rangeFetcher, file *os.File, from, size int64, ranges uint, rangeSize int64, progress io.Reader) *probe.Error {
+	if rangeSize <= 0 {
+		rangeSize = defaultRangeSize
+	}
+	if e := writeRangesJournal(file, from); e != nil {
+		return probe.NewError(e).Trace(file.Name())
+	}
+	if e := file.Truncate(size); e != nil {
+		return probe.NewError(e).Trace(file.Name())
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	starts := make(chan int64)
+	go func() {
+		defer close(starts)
+		for start := from; start < size; start += rangeSize {
+			select {
+			case starts <- start:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		done     = map[int64]bool{}
+		complete = from
+		firstErr *probe.Error
+	)
+	for i := uint(0); i < ranges; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := range starts {
+				end := start + rangeSize - 1
+				if end >= size {
+					end = size - 1
+				}
+				err := downloadRange(ctx, fetch, file, start, end, progress)
+				mu.Lock()
+				if err == nil {
+					done[start] = true
+					if start == complete {
+						for complete < size && done[complete] {
+							complete += rangeSize
+						}
+						if complete > size {
+							complete = size
+						}
+						if e := writeRangesJournal(file, complete); e != nil {
+							err = probe.NewError(e).Trace(file.Name())
+						}
+					}
+				}
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				if err != nil {
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		if complete < size {
+			file.Truncate(complete)
+		}
+		return firstErr
+	}
+	// The journal is synced, the file is complete.
+	os.Remove(rangesJournalPath(file.Name()))
+	return nil
+}
+
+// downloadRange downloads the bytes between start and end into file.
+func downloadRange(ctx context.Context, fetch rangeFetcher, file *os.File, start, end int64, progress io.Reader) *probe.Error {
+	reader, err := fetch(ctx, start, end)
+	if err != nil {
+		return err.Trace(file.Name())
+	}
+	defer reader.Close()
+
+	var src io.Reader = reader
+	if progress != nil {
+		src = hookreader.NewHook(reader, progress)
+	}
+	n, e := io.Copy(&offsetWriter{file: file, offset: start}, src)
+	if e != nil {
+		return probe.NewError(e).Trace(file.Name())
+	}
+	if n != end-start+1 {
+		return probe.NewError(UnexpectedEOF{
+			TotalSize:    end - start + 1,
+			TotalWritten: n,
+		}).Trace(file.Name())
+	}
+	return nil
+}
+
+// canDownloadRanges returns true if the transfer described by urls
+// can be done as a parallel ranged download to the local filesystem.
+func canDownloadRanges(urls URLs, preserve, isZip bool) bool {
+	return urls.SourceContent.URL.Type == objectStorage &&
+		urls.TargetContent.URL.Type == fileSystem &&
+		!preserve && !isZip && urls.cseKey == nil &&
+		useRangedDownload(urls.ParallelRanges, urls.RangeSize, urls.SourceContent.Size)
+}
+
+// downloadRangesToFS copies the source object of urls to its target
+// file with parallel ranged downloads. Every range requires the ETag of
+// the source object, so that an object overwritten during the download
+// fails it instead of mixing two contents. The partial file, named after
+// that ETag, is kept on failure for a retry to resume from.
+func downloadRangesToFS(ctx context.Context, urls URLs, sse encrypt.ServerSide, progress io.Reader) *probe.Error {
+	sourceURL := urls.SourceContent.URL.String()
+	sourceClnt, err := newClientFromAlias(urls.SourceAlias, sourceURL)
+	if err != nil {
+		return err.Trace(sourceURL)
+	}
+	source := *urls.SourceContent
+	if source.ETag == "" {
+		st, err := sourceClnt.Stat(ctx, StatOptions{sse: sse, versionID: source.VersionID})
+		if err != nil {
+			return err.Trace(sourceURL)
+		}
+		source.ETag = st.ETag
+	}
+	fetch := func(ctx context.Context, start, end int64) (io.ReadCloser, *probe.Error) {
+		return sourceClnt.Get(ctx, GetOptions{
+			SSE:         sse,
+			VersionID:   source.VersionID,
+			RangeStart:  start,
+			RangeEnd:    end,
+			HasRangeEnd: true,
+			MatchETag:   source.ETag,
+		})
+	}
+
+	targetPath := urls.TargetContent.URL.Path
+	if e := os.MkdirAll(filepath.Dir(targetPath), 0o777); e != nil {
+		return probe.NewError(e).Trace(targetPath)
+	}
+	partPath := getPartPath(targetPath, &source)
+	partFile, e := os.OpenFile(partPath, os.O_CREATE|os.O_RDWR, 0o666)
+	if e != nil {
+		return probe.NewError(e).Trace(partPath)
+	}
+	offset, e := trimPartFile(partFile)
+	if e != nil {
+		partFile.Close()
+		return probe.NewError(e).Trace(partPath)
+	}
+	if offset > source.Size {
+		offset = 0
+	}
+	err = downloadRanges(ctx, fetch, partFile, offset, source.Size, urls.ParallelRanges, urls.RangeSize, progress)
+	if e = partFile.Close(); e != nil && err == nil {
+		err = probe.NewError(e).Trace(partPath)
+	}
+	if err != nil {
+		// Resuming is pointless once the source object has changed.
+		if minio.ToErrorResponse(err.ToGoError()).Code == "PreconditionFailed" {
+			removePartFile(partPath)
+		}
+		return err
+	}
+	if e = os.Rename(partPath, targetPath); e != nil {
+		return probe.NewError(e).Trace(targetPath)
+	}
+	return nil
+}
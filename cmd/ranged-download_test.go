@@ -0,0 +1,201 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+func TestDownloadRanges(t *testing.T) {
+	data := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+	size := int64(len(data))
+
+	testCases := []struct {
+		from      int64
+		rangeSize int64
+		failAt    int64 // start of the range which fails, -1 for none
+		expected  []byte
+	}{
+		{0, 5, -1, data},
+		{0, 100, -1, data},
+		{10, 4, -1, append([]byte("__________"), data[10:]...)},
+		// Truncated after the last range downloaded without gaps.
+		{0, 6, 12, data[:12]},
+		{0, 6, 0, []byte{}},
+	}
+
+	for i, testCase := range testCases {
+		file, e := os.Create(filepath.Join(t.TempDir(), "object.part.minio"))
+		if e != nil {
+			t.Fatal(e)
+		}
+		if testCase.from > 0 {
+			file.Write(bytes.Repeat([]byte("_"), int(testCase.from)))
+		}
+		fetch := func(_ context.Context, start, end int64) (io.ReadCloser, *probe.Error) {
+			if start == testCase.failAt {
+				return nil, probe.NewError(errors.New("connection reset"))
+			}
+			return io.NopCloser(bytes.NewReader(data[start : end+1])), nil
+		}
+		err := downloadRanges(context.Background(), fetch, file, testCase.from, size, 3, testCase.rangeSize, nil)
+		if (err != nil) != (testCase.failAt >= 0) {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		got, e := os.ReadFile(file.Name())
+		if e != nil {
+			t.Fatal(e)
+		}
+		file.Close()
+		if !bytes.Equal(got, testCase.expected) {
+			t.Fatalf("Test %d: expected %q, got %q", i+1, testCase.expected, got)
+		}
+	}
+}
+
+// closeNotifier calls onClose once the range is fully read.
+type closeNotifier struct {
+	io.Reader
+	onClose func()
+}
+
+func (c closeNotifier) Close() error {
+	c.onClose()
+	return nil
+}
+
+func TestDownloadRangesResume(t *testing.T) {
+	data := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+	size := int64(len(data))
+
+	testCases := []struct {
+		ranges   uint
+		blockAt  int64 // start of the range in progress when killed
+		closes   int   // ranges downloaded when killed
+		expected int64 // offset to resume from
+	}{
+		// The ranges after the first one are downloaded.
+		{3, 0, 5, 0},
+		// The first range is downloaded.
+		{1, 6, 1, 6},
+	}
+
+	for i, testCase := range testCases {
+		dir := t.TempDir()
+		file, e := os.Create(filepath.Join(dir, "object.part.minio"))
+		if e != nil {
+			t.Fatal(e)
+		}
+		var closes sync.WaitGroup
+		closes.Add(testCase.closes)
+		blocked := make(chan struct{})
+		release := make(chan struct{})
+		fetch := func(_ context.Context, start, end int64) (io.ReadCloser, *probe.Error) {
+			if start == testCase.blockAt {
+				close(blocked)
+				<-release
+				return nil, probe.NewError(errors.New("killed"))
+			}
+			return closeNotifier{bytes.NewReader(data[start : end+1]), closes.Done}, nil
+		}
+		errCh := make(chan *probe.Error, 1)
+		go func() {
+			errCh <- downloadRanges(context.Background(), fetch, file, 0, size, testCase.ranges, 6, nil)
+		}()
+		<-blocked
+		closes.Wait()
+
+		// Copy the partial download as the process, killed at this point,
+		// would leave it.
+		killedPath := filepath.Join(dir, "killed.part.minio")
+		for _, path := range [][2]string{{file.Name(), killedPath}, {rangesJournalPath(file.Name()), rangesJournalPath(killedPath)}} {
+			content, e := os.ReadFile(path[0])
+			if e != nil {
+				t.Fatal(e)
+			}
+			if e = os.WriteFile(path[1], content, 0o666); e != nil {
+				t.Fatal(e)
+			}
+		}
+		close(release)
+		<-errCh
+		file.Close()
+
+		killed, e := os.OpenFile(killedPath, os.O_RDWR, 0o666)
+		if e != nil {
+			t.Fatal(e)
+		}
+		offset, e := trimPartFile(killed)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if offset != testCase.expected {
+			t.Fatalf("Test %d: expected to resume from %d, got %d", i+1, testCase.expected, offset)
+		}
+		fetch = func(_ context.Context, start, end int64) (io.ReadCloser, *probe.Error) {
+			return io.NopCloser(bytes.NewReader(data[start : end+1])), nil
+		}
+		if err := downloadRanges(context.Background(), fetch, killed, offset, size, testCase.ranges, 6, nil); err != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		killed.Close()
+		got, e := os.ReadFile(killedPath)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if !bytes.Equal(got, data) {
+			t.Fatalf("Test %d: expected %q, got %q", i+1, data, got)
+		}
+		if _, e = os.Stat(rangesJournalPath(killedPath)); !os.IsNotExist(e) {
+			t.Fatalf("Test %d: expected the journal to be removed, got %v", i+1, e)
+		}
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "part.ranges")
+	for _, data := range []string{"1048576", "2097152"} {
+		if e := writeFileAtomic(file, []byte(data)); e != nil {
+			t.Fatalf("unexpected error: %v", e)
+		}
+		got, e := os.ReadFile(file)
+		if e != nil {
+			t.Fatalf("unexpected error: %v", e)
+		}
+		if string(got) != data {
+			t.Fatalf("expected %s, got %s", data, got)
+		}
+	}
+	entries, e := os.ReadDir(dir)
+	if e != nil {
+		t.Fatalf("unexpected error: %v", e)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected only the journal, got %d entries", len(entries))
+	}
+}
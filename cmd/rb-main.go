@@ -41,6 +41,7 @@ var rbFlags = []cli.Flag{
 		Name:  "dangerous",
 		Usage: "allow site-wide removal of objects",
 	},
+	approvalFlag,
 }
 
 // remove a bucket.
@@ -72,6 +73,9 @@ EXAMPLES:
 
   4. Remove all buckets and objects recursively from S3 host
      {{.Prompt}} {{.HelpName}} --force --dangerous s3
+
+  5. Remove bucket 'jazz-songs' on the protected alias 'prod', once the request was approved with 'mc approve'.
+     {{.Prompt}} {{.HelpName}} --force --approval mc-approval-6f1c2e9a4b7d3c05.json prod/jazz-songs
 `,
 }
 
@@ -245,6 +249,11 @@ func mainRemoveBucket(cliCtx *cli.Context) error {
 	// Additional command specific theme customization.
 	console.SetColor("RemoveBucket", color.New(color.FgGreen, color.Bold))
 
+	// Forced removals on protected aliases need a second operator's approval.
+	if isForce && !checkApproval(cliCtx, approvalCommand(cliCtx, "rb", "force", "dangerous"), cliCtx.Args()) {
+		return exitStatus(globalErrorExitStatus)
+	}
+
 	var cErr error
 	for _, targetURL := range cliCtx.Args() {
 		// Instantiate client for URL.
@@ -534,7 +534,7 @@ func mainReplicateBacklog(cliCtx *cli.Context) error {
 			}
 			return nil
 		}
-		ui := tea.NewProgram(initReplicateBacklogUI("", "mrf", mrfCh))
+		ui := newUIProgram(initReplicateBacklogUI("", "mrf", mrfCh))
 		if _, e := ui.Run(); e != nil {
 			cancel()
 			fatalIf(probe.NewError(e).Trace(aliasedURL), "Unable to fetch replication backlog")
@@ -561,7 +561,7 @@ func mainReplicateBacklog(cliCtx *cli.Context) error {
 		return nil
 	}
 
-	ui := tea.NewProgram(initReplicateBacklogUI(arn, "diff", diffCh))
+	ui := newUIProgram(initReplicateBacklogUI(arn, "diff", diffCh))
 	if _, e := ui.Run(); e != nil {
 		cancel()
 		fatalIf(probe.NewError(e).Trace(aliasedURL), "Unable to fetch replication backlog")
@@ -0,0 +1,217 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	json "github.com/minio/colorjson"
+	minio "github.com/trinet2005/oss-go-sdk"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+// retentionClearExpiredMessage summarizes 'retention clear --only-expired'.
+type retentionClearExpiredMessage struct {
+	Status   string `json:"status"`
+	Cleared  int    `json:"cleared"`
+	Removed  int    `json:"removed"`
+	Retained int    `json:"retained"`
+}
+
+func (m retentionClearExpiredMessage) String() string {
+	msg := fmt.Sprintf("Cleared %d expired retention(s)", m.Cleared)
+	if m.Removed > 0 {
+		msg += fmt.Sprintf(", removed %d version(s)", m.Removed)
+	}
+	msg += fmt.Sprintf(", %d version(s) still retained.", m.Retained)
+	return console.Colorize("RetentionSuccess", msg)
+}
+
+func (m retentionClearExpiredMessage) JSON() string {
+	m.Status = "success"
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// isRetentionExpired returns true if a retention is set and its retain
+// until date has passed at now.
+func isRetentionExpired(mode minio.RetentionMode, until, now time.Time) bool {
+	return mode != "" && !until.After(now)
+}
+
+// expiredRetentionSweep clears the retention of the versions whose retain
+// until date has passed, leaving the others untouched, and removes them too
+// when remove is set. Versions with no retention are neither cleared nor
+// removed.
+type expiredRetentionSweep struct {
+	alias string
+	url   string
+	now   time.Time
+
+	getRetention   func(content *ClientContent) (minio.RetentionMode, time.Time, *probe.Error)
+	clearRetention func(content *ClientContent) *probe.Error
+	// remove removes the versions sent to its channel, nil to keep them.
+	remove func(contentCh <-chan *ClientContent) <-chan RemoveResult
+}
+
+// run sweeps the versions read from contentCh.
+func (s expiredRetentionSweep) run(contentCh <-chan *ClientContent) (summary retentionClearExpiredMessage, cErr error) {
+	removeCh := make(chan *ClientContent)
+	var resultCh <-chan RemoveResult
+	if s.remove != nil {
+		resultCh = s.remove(removeCh)
+	}
+	handleResult := func(result RemoveResult) {
+		key := path.Join(s.alias, result.BucketName, result.ObjectName)
+		if result.Err != nil {
+			errorIf(result.Err.Trace(key), "Failed to remove `"+key+"`.")
+			cErr = exitStatus(globalErrorExitStatus)
+			return
+		}
+		summary.Removed++
+		printMsg(rmMessage{Key: key, VersionID: result.ObjectVersionID})
+	}
+
+	for content := range contentCh {
+		if content.Err != nil {
+			errorIf(content.Err.Trace(s.url), "Unable to list folder.")
+			cErr = exitStatus(globalErrorExitStatus)
+			continue
+		}
+		// The spec does not allow setting retention on delete marker
+		if content.IsDeleteMarker {
+			continue
+		}
+
+		key := s.alias + getKey(content)
+		mode, until, err := s.getRetention(content)
+		if err != nil {
+			if minio.ToErrorResponse(err.ToGoError()).Code == "NoSuchObjectLockConfiguration" {
+				continue
+			}
+			errorIf(err.Trace(content.URL.String()), "Unable to get the retention of `"+key+"`.")
+			cErr = exitStatus(globalErrorExitStatus)
+			continue
+		}
+		if !isRetentionExpired(mode, until, s.now) {
+			if mode != "" {
+				summary.Retained++
+			}
+			continue
+		}
+
+		if err = s.clearRetention(content); err != nil {
+			cErr = exitStatus(globalErrorExitStatus)
+			continue
+		}
+		summary.Cleared++
+
+		if s.remove == nil {
+			continue
+		}
+		if content.VersionID == "" {
+			// Removing it would only add a delete marker.
+			errorIf(errDummy().Trace(key), "Unable to remove `"+key+"`, its version is unknown.")
+			cErr = exitStatus(globalErrorExitStatus)
+			continue
+		}
+		for sent := false; !sent; {
+			select {
+			case removeCh <- content:
+				sent = true
+			case result := <-resultCh:
+				handleResult(result)
+			}
+		}
+	}
+
+	close(removeCh)
+	if s.remove != nil {
+		for result := range resultCh {
+			handleResult(result)
+		}
+	}
+	return summary, cErr
+}
+
+// clearExpiredRetention runs an expiredRetentionSweep over the versions
+// of target.
+func clearExpiredRetention(ctx context.Context, target, versionID string, timeRef time.Time, withOlderVersions, isRecursive, remove bool) error {
+	alias, urlStr, _ := mustExpandAlias(target)
+	clnt, err := newClientFromAlias(alias, urlStr)
+	fatalIf(err.Trace(target), "Unable to parse the provided url.")
+	if _, ok := clnt.(*S3Client); !ok {
+		fatal(errDummy().Trace(), "Retention is supported only for S3 servers.")
+	}
+
+	contentCh := make(chan *ClientContent)
+	go func() {
+		defer close(contentCh)
+		if versionID != "" || !isRecursive && !withOlderVersions {
+			content, err := clnt.Stat(ctx, StatOptions{versionID: versionID})
+			if err != nil {
+				content = &ClientContent{Err: err}
+			}
+			contentCh <- content
+			return
+		}
+		lstOptions := ListOptions{Recursive: isRecursive, ShowDir: DirNone}
+		if !timeRef.IsZero() {
+			lstOptions.WithOlderVersions = withOlderVersions
+			lstOptions.WithDeleteMarkers = true
+			lstOptions.TimeRef = timeRef
+		}
+		for content := range clnt.List(ctx, lstOptions) {
+			if content.Err == nil && !isRecursive && withOlderVersions && alias+getKey(content) != getStandardizedURL(target) {
+				continue
+			}
+			contentCh <- content
+		}
+	}()
+
+	sweep := expiredRetentionSweep{
+		alias: alias,
+		url:   clnt.GetURL().String(),
+		now:   UTCNow(),
+		getRetention: func(content *ClientContent) (minio.RetentionMode, time.Time, *probe.Error) {
+			versionClnt, err := newClientFromAlias(alias, content.URL.String())
+			if err != nil {
+				return "", time.Time{}, err
+			}
+			return versionClnt.GetObjectRetention(ctx, content.VersionID)
+		},
+		clearRetention: func(content *ClientContent) *probe.Error {
+			return setRetentionSingle(ctx, lockOpClear, alias, content.URL.String(), content.VersionID, "", time.Time{}, true)
+		},
+	}
+	if remove {
+		sweep.remove = func(removeCh <-chan *ClientContent) <-chan RemoveResult {
+			// The retention was just cleared: a retention set again
+			// meanwhile must not be bypassed.
+			return clnt.Remove(ctx, false, false, false, false, removeCh)
+		}
+	}
+	summary, cErr := sweep.run(contentCh)
+	printMsg(summary)
+	return cErr
+}
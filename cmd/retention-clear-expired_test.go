@@ -0,0 +1,126 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"path"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	minio "github.com/trinet2005/oss-go-sdk"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+func TestExpiredRetentionSweep(t *testing.T) {
+	now := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	type retention struct {
+		mode  minio.RetentionMode
+		until time.Time
+		err   *probe.Error
+	}
+	retentions := map[string]retention{
+		"expired":    {minio.Compliance, now.Add(-time.Hour), nil},
+		"expiring":   {minio.Governance, now, nil},
+		"retained":   {minio.Governance, now.Add(time.Hour), nil},
+		"none":       {"", time.Time{}, nil},
+		"nolock":     {"", time.Time{}, probe.NewError(minio.ErrorResponse{Code: "NoSuchObjectLockConfiguration"})},
+		"failed":     {"", time.Time{}, probe.NewError(errors.New("connection reset"))},
+		"clearfails": {minio.Compliance, now.Add(-time.Hour), nil},
+		"":           {minio.Compliance, now.Add(-time.Hour), nil},
+	}
+	newContents := func() []*ClientContent {
+		var contents []*ClientContent
+		for _, versionID := range []string{"expired", "expiring", "retained", "none", "nolock", "failed", "clearfails", ""} {
+			contents = append(contents, &ClientContent{URL: *newClientURL("https://localhost:9000/bucket/object"), VersionID: versionID})
+		}
+		return append(contents, &ClientContent{URL: *newClientURL("https://localhost:9000/bucket/object"), VersionID: "marker", IsDeleteMarker: true})
+	}
+
+	testCases := []struct {
+		remove   bool
+		summary  retentionClearExpiredMessage
+		cleared  []string
+		removed  []string
+		hasError bool
+	}{
+		{false, retentionClearExpiredMessage{Cleared: 3, Retained: 1}, []string{"", "expired", "expiring"}, nil, true},
+		// A version without version ID is cleared but never removed.
+		{true, retentionClearExpiredMessage{Cleared: 3, Removed: 2, Retained: 1}, []string{"", "expired", "expiring"}, []string{"expired", "expiring"}, true},
+	}
+
+	for i, testCase := range testCases {
+		var cleared, removed []string
+		sweep := expiredRetentionSweep{
+			alias: "myminio",
+			url:   "https://localhost:9000/bucket/",
+			now:   now,
+			getRetention: func(content *ClientContent) (minio.RetentionMode, time.Time, *probe.Error) {
+				r := retentions[content.VersionID]
+				return r.mode, r.until, r.err
+			},
+			clearRetention: func(content *ClientContent) *probe.Error {
+				if content.VersionID == "clearfails" {
+					return probe.NewError(errors.New("access denied"))
+				}
+				cleared = append(cleared, content.VersionID)
+				return nil
+			},
+		}
+		if testCase.remove {
+			sweep.remove = func(contentCh <-chan *ClientContent) <-chan RemoveResult {
+				resultCh := make(chan RemoveResult)
+				go func() {
+					defer close(resultCh)
+					for content := range contentCh {
+						removed = append(removed, content.VersionID)
+						result := RemoveResult{BucketName: "bucket"}
+						result.ObjectName = path.Base(content.URL.Path)
+						result.ObjectVersionID = content.VersionID
+						resultCh <- result
+					}
+				}()
+				return resultCh
+			}
+		}
+
+		contentCh := make(chan *ClientContent)
+		go func() {
+			defer close(contentCh)
+			for _, content := range newContents() {
+				contentCh <- content
+			}
+		}()
+		summary, e := sweep.run(contentCh)
+		if summary != testCase.summary {
+			t.Fatalf("Test %d: expected summary %+v, got %+v", i+1, testCase.summary, summary)
+		}
+		if (e != nil) != testCase.hasError {
+			t.Fatalf("Test %d: expected error %v, got %v", i+1, testCase.hasError, e)
+		}
+		sort.Strings(cleared)
+		if !reflect.DeepEqual(cleared, testCase.cleared) {
+			t.Fatalf("Test %d: expected %q to be cleared, got %q", i+1, testCase.cleared, cleared)
+		}
+		if !reflect.DeepEqual(removed, testCase.removed) {
+			t.Fatalf("Test %d: expected %q to be removed, got %q", i+1, testCase.removed, removed)
+		}
+	}
+}
@@ -48,6 +48,14 @@ var retentionClearFlags = []cli.Flag{
 		Name:  "default",
 		Usage: "set default bucket locking",
 	},
+	cli.BoolFlag{
+		Name:  "only-expired",
+		Usage: "only clear retention whose retain until date has passed",
+	},
+	cli.BoolFlag{
+		Name:  "remove",
+		Usage: "remove the versions whose expired retention is cleared, with --only-expired and --versions or --version-id",
+	},
 }
 
 var retentionClearCmd = cli.Command{
@@ -85,10 +93,16 @@ EXAMPLES:
 
   6. Clear a bucket retention configuration
      $ {{.HelpName}} --default myminio/mybucket/
+
+  7. Clear the expired retention of all versions of all objects at a given prefix, leaving the others
+     $ {{.HelpName}} myminio/mybucket/prefix --recursive --versions --only-expired
+
+  8. Clear the expired retention of all versions at a given prefix and remove these versions
+     $ {{.HelpName}} myminio/mybucket/prefix --recursive --versions --only-expired --remove
 `,
 }
 
-func parseClearRetentionArgs(cliCtx *cli.Context) (target, versionID string, timeRef time.Time, withVersions, recursive, bucketMode bool) {
+func parseClearRetentionArgs(cliCtx *cli.Context) (target, versionID string, timeRef time.Time, withVersions, recursive, bucketMode, onlyExpired, remove bool) {
 	args := cliCtx.Args()
 
 	if len(args) != 1 {
@@ -105,17 +119,29 @@ func parseClearRetentionArgs(cliCtx *cli.Context) (target, versionID string, tim
 	withVersions = cliCtx.Bool("versions")
 	recursive = cliCtx.Bool("recursive")
 	bucketMode = cliCtx.Bool("default")
+	onlyExpired = cliCtx.Bool("only-expired")
+	remove = cliCtx.Bool("remove")
 
 	if bucketMode && (versionID != "" || !timeRef.IsZero() || withVersions || recursive) {
 		fatalIf(errDummy(), "--default cannot be specified with any of --version-id, --rewind, --versions or --recursive.")
 	}
+	if bucketMode && onlyExpired {
+		fatalIf(errDummy(), "--default cannot be specified with --only-expired.")
+	}
+	if remove && !onlyExpired {
+		fatalIf(errDummy(), "--remove can only be specified with --only-expired.")
+	}
+	if remove && !withVersions && versionID == "" {
+		// Without a version ID, removing an object only adds a delete marker.
+		fatalIf(errDummy(), "--remove requires --versions or --version-id.")
+	}
 
 	return
 }
 
 // Clear Retention for one object/version or many objects within a given prefix, bypass governance is always enabled
 func clearRetention(ctx context.Context, target, versionID string, timeRef time.Time, withOlderVersions, isRecursive bool) error {
-	return applyRetention(ctx, lockOpClear, target, versionID, timeRef, withOlderVersions, isRecursive, "", 0, minio.Days, true)
+	return applyRetention(ctx, lockOpClear, target, versionID, timeRef, withOlderVersions, isRecursive, "", 0, minio.Days, true, 1)
 }
 
 func clearBucketLock(urlStr string) error {
@@ -130,7 +156,7 @@ func mainRetentionClear(cliCtx *cli.Context) error {
 	console.SetColor("RetentionSuccess", color.New(color.FgGreen, color.Bold))
 	console.SetColor("RetentionFailure", color.New(color.FgYellow))
 
-	target, versionID, rewind, withVersions, recursive, bucketMode := parseClearRetentionArgs(cliCtx)
+	target, versionID, rewind, withVersions, recursive, bucketMode, onlyExpired, remove := parseClearRetentionArgs(cliCtx)
 
 	fatalIfBucketLockNotSupported(ctx, target)
 
@@ -142,5 +168,10 @@ func mainRetentionClear(cliCtx *cli.Context) error {
 		rewind = time.Now().UTC()
 	}
 
+	if onlyExpired {
+		console.SetColor("Removed", color.New(color.FgGreen, color.Bold))
+		return clearExpiredRetention(ctx, target, versionID, rewind, withVersions, recursive, remove)
+	}
+
 	return clearRetention(ctx, target, versionID, rewind, withVersions, recursive)
 }
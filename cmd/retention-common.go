@@ -177,9 +177,10 @@ func fatalIfBucketLockNotSupported(ctx context.Context, aliasedURL string) {
 	}
 }
 
-// Apply Retention for one object/version or many objects within a given prefix.
+// Apply Retention for one object/version or many objects within a given prefix,
+// recursively with the given number of workers.
 func applyRetention(ctx context.Context, op lockOpType, target, versionID string, timeRef time.Time, withOlderVersions, isRecursive bool,
-	mode minio.RetentionMode, validity uint64, unit minio.ValidityUnit, bypassGovernance bool,
+	mode minio.RetentionMode, validity uint64, unit minio.ValidityUnit, bypassGovernance bool, workers int,
 ) error {
 	clnt, err := newClient(target)
 	if err != nil {
@@ -220,6 +221,10 @@ func applyRetention(ctx context.Context, op lockOpType, target, versionID string
 		lstOptions.TimeRef = timeRef
 	}
 
+	if isRecursive && workers > 1 {
+		return applyRetentionParallel(ctx, clnt, op, alias, target, lstOptions, mode, until, bypassGovernance, workers)
+	}
+
 	var cErr error
 	var atLeastOneRetentionApplied bool
 
@@ -0,0 +1,386 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	json "github.com/minio/colorjson"
+	"github.com/olekukonko/tablewriter"
+	minio "github.com/trinet2005/oss-go-sdk"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+// retentionProgressInterval is the interval between two updates of the
+// progress view of a recursive retention change.
+const retentionProgressInterval = 250 * time.Millisecond
+
+// retentionProgress is a snapshot of a recursive retention change.
+type retentionProgress struct {
+	op       lockOpType
+	listed   int64
+	done     int64
+	failed   int64
+	listing  bool
+	finished bool
+	elapsed  time.Duration
+	current  string
+}
+
+// rate returns the number of objects processed per second.
+func (p retentionProgress) rate() float64 {
+	if p.elapsed <= 0 {
+		return 0
+	}
+	return float64(p.done+p.failed) / p.elapsed.Seconds()
+}
+
+// eta returns the estimated time left, known once every object is listed.
+func (p retentionProgress) eta() (time.Duration, bool) {
+	rate := p.rate()
+	if p.listing || rate == 0 {
+		return 0, false
+	}
+	remaining := p.listed - p.done - p.failed
+	return time.Duration(float64(remaining) / rate * float64(time.Second)).Round(time.Second), true
+}
+
+func initRetentionProgressUI(op lockOpType) *retentionProgressUI {
+	s := spinner.New()
+	s.Spinner = spinner.Points
+	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	return &retentionProgressUI{
+		spinner: s,
+		current: retentionProgress{op: op, listing: true},
+	}
+}
+
+type retentionProgressUI struct {
+	current  retentionProgress
+	spinner  spinner.Model
+	quitting bool
+}
+
+func (m *retentionProgressUI) Init() tea.Cmd {
+	return m.spinner.Tick
+}
+
+func (m *retentionProgressUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			m.quitting = true
+			return m, tea.Quit
+		default:
+			return m, nil
+		}
+	case retentionProgress:
+		m.current = msg
+		if msg.finished {
+			m.quitting = true
+			return m, tea.Quit
+		}
+		return m, nil
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	default:
+		return m, nil
+	}
+}
+
+func (m *retentionProgressUI) View() string {
+	var s strings.Builder
+
+	// Set table header
+	table := tablewriter.NewWriter(&s)
+	table.SetAutoWrapText(false)
+	table.SetAutoFormatHeaders(true)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+	table.SetTablePadding("\t") // pad with tabs
+	table.SetNoWhiteSpace(true)
+
+	var data [][]string
+	addLine := func(prefix string, value interface{}) {
+		data = append(data, []string{
+			prefix,
+			whiteStyle.Render(fmt.Sprint(value)),
+		})
+	}
+
+	p := m.current
+	if !m.quitting {
+		s.WriteString(m.spinner.View())
+	} else if p.finished && p.failed == 0 {
+		s.WriteString(m.spinner.Style.Render((tickCell + tickCell + tickCell)))
+	} else {
+		s.WriteString(m.spinner.Style.Render((crossTickCell + crossTickCell + crossTickCell)))
+	}
+	s.WriteString("\n")
+
+	listed := fmt.Sprint(p.listed)
+	if p.listing {
+		listed += " (listing)"
+	}
+	addLine("Operation: ", "retention "+string(p.op))
+	addLine("Objects: ", listed)
+	addLine("Done: ", p.done)
+	addLine("Failed: ", p.failed)
+	addLine("Rate: ", fmt.Sprintf("%.2f objs/s", p.rate()))
+	addLine("Elapsed: ", p.elapsed.Round(time.Second).String())
+	if eta, ok := p.eta(); ok && !p.finished {
+		addLine("ETA: ", eta.String())
+	}
+	if !p.finished && p.current != "" {
+		addLine("CurrObjName: ", p.current)
+	}
+
+	table.AppendBulk(data)
+	table.Render()
+
+	if m.quitting {
+		s.WriteString("\n")
+	}
+	return s.String()
+}
+
+// retentionSummaryMessage is the outcome of a recursive retention change.
+type retentionSummaryMessage struct {
+	Status  string        `json:"status"`
+	Op      lockOpType    `json:"op"`
+	URL     string        `json:"url"`
+	Objects int64         `json:"objects"`
+	Failed  int64         `json:"failed"`
+	Listed  int64         `json:"listed"`
+	Aborted bool          `json:"aborted,omitempty"`
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+// String colorized retention summary message.
+func (m retentionSummaryMessage) String() string {
+	color := "RetentionSuccess"
+	if m.Failed > 0 || m.Aborted {
+		color = "RetentionFailure"
+	}
+	if m.Aborted {
+		return console.Colorize(color, fmt.Sprintf("Object retention %s aborted on `%s` after %d of %d listed objects, %d failed, in %s.",
+			m.Op, m.URL, m.Objects, m.Listed, m.Failed, m.Elapsed.Round(time.Millisecond)))
+	}
+	ed := ""
+	if m.Op == lockOpClear {
+		ed = "ed"
+	}
+	return console.Colorize(color, fmt.Sprintf("Object retention %s%s on %d objects of `%s`, %d failed, in %s.",
+		m.Op, ed, m.Objects, m.URL, m.Failed, m.Elapsed.Round(time.Millisecond)))
+}
+
+// JSON jsonified retention summary message.
+func (m retentionSummaryMessage) JSON() string {
+	m.Status = "success"
+	if m.Failed > 0 || m.Aborted {
+		m.Status = "failure"
+	}
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// applyRetentionParallel applies a retention change to the listed
+// objects with a pool of workers, showing the progress of the change
+// unless the output is JSON, where every object is reported.
+func applyRetentionParallel(ctx context.Context, clnt Client, op lockOpType, alias, target string, lstOptions ListOptions,
+	mode minio.RetentionMode, until time.Time, bypassGovernance bool, workers int,
+) error {
+	parentCtx := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		aborted              bool
+		listed, done, failed int64
+		listing              int32 = 1
+		current              atomic.Value
+		mu                   sync.Mutex
+		failures             []retentionCmdMessage
+		cErr                 error
+	)
+	current.Store("")
+	start := time.Now()
+	snapshot := func() retentionProgress {
+		return retentionProgress{
+			op:      op,
+			listed:  atomic.LoadInt64(&listed),
+			done:    atomic.LoadInt64(&done),
+			failed:  atomic.LoadInt64(&failed),
+			listing: atomic.LoadInt32(&listing) == 1,
+			elapsed: time.Since(start),
+			current: current.Load().(string),
+		}
+	}
+
+	jobs := make(chan *ClientContent)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for content := range jobs {
+				current.Store(content.URL.Path)
+				msg := retentionCmdMessage{
+					Op:        op,
+					Mode:      mode,
+					URLPath:   urlJoinPath(alias, content.URL.String()),
+					VersionID: content.VersionID,
+					Status:    "success",
+				}
+				objClnt, err := newClientFromAlias(alias, content.URL.String())
+				if err == nil {
+					err = objClnt.PutObjectRetention(ctx, content.VersionID, mode, until, bypassGovernance)
+				}
+				if err != nil {
+					msg.Err = err.ToGoError()
+					msg.Status = "failure"
+					atomic.AddInt64(&failed, 1)
+					if !globalJSON {
+						mu.Lock()
+						failures = append(failures, msg)
+						mu.Unlock()
+					}
+				} else {
+					atomic.AddInt64(&done, 1)
+				}
+				if globalJSON {
+					printMsg(msg)
+				}
+			}
+		}()
+	}
+
+	var ui uiProgram
+	if !globalJSON && !globalQuiet {
+		ui = newUIProgram(initRetentionProgressUI(op))
+	}
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		for content := range clnt.List(ctx, lstOptions) {
+			if content.Err != nil {
+				errorIf(content.Err.Trace(clnt.GetURL().String()), "Unable to list folder.")
+				cErr = exitStatus(globalErrorExitStatus) // Set the exit status.
+				continue
+			}
+
+			// The spec does not allow setting retention on delete marker
+			if content.IsDeleteMarker {
+				continue
+			}
+
+			atomic.AddInt64(&listed, 1)
+			select {
+			case jobs <- content:
+			case <-ctx.Done():
+			}
+		}
+		atomic.StoreInt32(&listing, 0)
+		close(jobs)
+		wg.Wait()
+	}()
+
+	if ui != nil {
+		go func() {
+			ticker := time.NewTicker(retentionProgressInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					ui.Send(snapshot())
+				case <-finished:
+					p := snapshot()
+					p.finished = true
+					ui.Send(p)
+					return
+				}
+			}
+		}()
+		if _, e := ui.Run(); e != nil {
+			cancel()
+			fatalIf(probe.NewError(e).Trace(target), "Unable to show the progress of the retention change.")
+		}
+		// Stop the change if the view was quit before the end.
+		select {
+		case <-finished:
+		default:
+			aborted = true
+		}
+		cancel()
+	}
+	<-finished
+
+	for _, msg := range failures {
+		printMsg(msg)
+	}
+	p := snapshot()
+	if parentCtx.Err() != nil || p.done+p.failed < p.listed {
+		aborted = true
+	}
+	if aborted {
+		printMsg(retentionSummaryMessage{
+			Op:      op,
+			URL:     target,
+			Objects: p.done,
+			Failed:  p.failed,
+			Listed:  p.listed,
+			Aborted: true,
+			Elapsed: p.elapsed,
+		})
+		return exitStatus(globalErrorExitStatus)
+	}
+	if p.listed == 0 {
+		errorIf(errDummy().Trace(clnt.GetURL().String()), "Unable to find any object/version to "+string(op)+" its retention.")
+		return exitStatus(globalErrorExitStatus)
+	}
+	printMsg(retentionSummaryMessage{
+		Op:      op,
+		URL:     target,
+		Objects: p.done,
+		Failed:  p.failed,
+		Listed:  p.listed,
+		Elapsed: p.elapsed,
+	})
+	if p.failed > 0 {
+		cErr = exitStatus(globalErrorExitStatus)
+	}
+	return cErr
+}
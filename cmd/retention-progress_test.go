@@ -0,0 +1,51 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetentionProgressETA(t *testing.T) {
+	testCases := []struct {
+		progress retentionProgress
+		rate     float64
+		eta      time.Duration
+		ok       bool
+	}{
+		// Nothing done yet.
+		{retentionProgress{listed: 100, elapsed: 0}, 0, 0, false},
+		{retentionProgress{listed: 100, elapsed: time.Second}, 0, 0, false},
+		// Still listing, the total is unknown.
+		{retentionProgress{listed: 100, done: 50, listing: true, elapsed: 10 * time.Second}, 5, 0, false},
+		// Failed objects count as processed.
+		{retentionProgress{listed: 100, done: 40, failed: 10, elapsed: 10 * time.Second}, 5, 10 * time.Second, true},
+		{retentionProgress{listed: 1000, done: 250, elapsed: 5 * time.Second}, 50, 15 * time.Second, true},
+		{retentionProgress{listed: 10, done: 10, elapsed: 2 * time.Second}, 5, 0, true},
+	}
+	for i, testCase := range testCases {
+		if rate := testCase.progress.rate(); rate != testCase.rate {
+			t.Errorf("Test %d: expected rate %v, got %v", i+1, testCase.rate, rate)
+		}
+		eta, ok := testCase.progress.eta()
+		if eta != testCase.eta || ok != testCase.ok {
+			t.Errorf("Test %d: expected ETA (%v, %v), got (%v, %v)", i+1, testCase.eta, testCase.ok, eta, ok)
+		}
+	}
+}
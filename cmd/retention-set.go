@@ -54,6 +54,11 @@ var retentionSetFlags = []cli.Flag{
 		Name:  "default",
 		Usage: "set bucket default retention mode",
 	},
+	cli.IntFlag{
+		Name:  "workers",
+		Usage: "number of objects updated in parallel with --recursive, 1 to report every object",
+		Value: 16,
+	},
 }
 
 var retentionSetCmd = cli.Command{
@@ -90,6 +95,9 @@ EXAMPLES:
 
   5. Set default lock retention configuration for a bucket
      $ {{.HelpName}} --default governance 30d myminio/mybucket/
+
+  6. Set object retention recursively for all versions of all objects, 64 objects at a time
+     $ {{.HelpName}} governance 30d myminio/mybucket/prefix --recursive --versions --workers 64
 `,
 }
 
@@ -129,9 +137,9 @@ func parseSetRetentionArgs(cliCtx *cli.Context) (target, versionID string, recur
 
 // Set Retention for one object/version or many objects within a given prefix.
 func setRetention(ctx context.Context, target, versionID string, timeRef time.Time, withOlderVersions, isRecursive bool,
-	mode minio.RetentionMode, validity uint64, unit minio.ValidityUnit, bypassGovernance bool,
+	mode minio.RetentionMode, validity uint64, unit minio.ValidityUnit, bypassGovernance bool, workers int,
 ) error {
-	return applyRetention(ctx, lockOpSet, target, versionID, timeRef, withOlderVersions, isRecursive, mode, validity, unit, bypassGovernance)
+	return applyRetention(ctx, lockOpSet, target, versionID, timeRef, withOlderVersions, isRecursive, mode, validity, unit, bypassGovernance, workers)
 }
 
 func setBucketLock(urlStr string, mode minio.RetentionMode, validity uint64, unit minio.ValidityUnit) error {
@@ -158,5 +166,10 @@ func mainRetentionSet(cliCtx *cli.Context) error {
 		rewind = time.Now().UTC()
 	}
 
-	return setRetention(ctx, target, versionID, rewind, withVersions, recursive, mode, validity, unit, bypass)
+	workers := cliCtx.Int("workers")
+	if workers < 1 {
+		fatalIf(errInvalidArgument().Trace(cliCtx.String("workers")), "--workers should be at least 1.")
+	}
+
+	return setRetention(ctx, target, versionID, rewind, withVersions, recursive, mode, validity, unit, bypass, workers)
 }
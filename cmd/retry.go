@@ -0,0 +1,199 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	json "github.com/minio/colorjson"
+	minio "github.com/trinet2005/oss-go-sdk"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+// retryPolicy describes how transient failures of a
+// transfer request are retried.
+type retryPolicy struct {
+	attempts int           // total number of attempts, including the first one
+	backoff  time.Duration // wait before the first retry, doubled on every retry
+	maxWait  time.Duration // upper bound of a single wait
+}
+
+// wait returns the time to wait before the given retry attempt.
+func (p retryPolicy) wait(attempt int) time.Duration {
+	wait := p.backoff
+	for i := 1; i < attempt; i++ {
+		wait *= 2
+		if p.maxWait > 0 && wait >= p.maxWait {
+			break
+		}
+	}
+	if p.maxWait > 0 && wait > p.maxWait {
+		wait = p.maxWait
+	}
+	return wait
+}
+
+// retryMessage is printed each time a request is retried.
+type retryMessage struct {
+	Status      string `json:"status"`
+	Operation   string `json:"operation"`
+	URL         string `json:"url"`
+	Attempt     int    `json:"attempt"`
+	MaxAttempts int    `json:"maxAttempts"`
+	Wait        string `json:"wait"`
+	Error       string `json:"error"`
+}
+
+// String colorized retry message
+func (r retryMessage) String() string {
+	return console.Colorize("Retry", fmt.Sprintf("Retrying %s of `%s` in %s (attempt %d/%d): %s",
+		r.Operation, r.URL, r.Wait, r.Attempt, r.MaxAttempts, r.Error))
+}
+
+// JSON jsonified retry message
+func (r retryMessage) JSON() string {
+	r.Status = "retry"
+	retryMessageBytes, e := json.MarshalIndent(r, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(retryMessageBytes)
+}
+
+// isRetriableError returns true if the error is likely transient
+// and the failed request is worth retrying: timeouts, connections
+// reset or dropped by the server, and server side or throttling
+// errors.
+func isRetriableError(err *probe.Error) bool {
+	if err == nil {
+		return false
+	}
+	e := err.ToGoError()
+	switch {
+	case errors.Is(e, errTransferStalled):
+		return true
+	case errors.Is(e, context.Canceled):
+		return false
+	case errors.Is(e, context.DeadlineExceeded):
+		return true
+	case errors.Is(e, io.ErrUnexpectedEOF), errors.Is(e, syscall.ECONNRESET), errors.Is(e, syscall.EPIPE):
+		return true
+	}
+	var netErr net.Error
+	if errors.As(e, &netErr) {
+		return netErr.Timeout()
+	}
+	errResp := minio.ToErrorResponse(e)
+	switch errResp.Code {
+	case "RequestTimeout", "SlowDown", "SlowDownRead", "SlowDownWrite",
+		"InternalError", "ServiceUnavailable", "XMinioServerNotInitialized":
+		return true
+	}
+	return errResp.StatusCode >= http.StatusInternalServerError ||
+		errResp.StatusCode == http.StatusTooManyRequests
+}
+
+// withRetry runs fn until it succeeds, fails with a permanent
+// error or the configured number of attempts is exhausted. A
+// retry message is emitted before every retry.
+func withRetry(ctx context.Context, operation, url string, fn func() *probe.Error) *probe.Error {
+	return retryAfter(ctx, operation, url, fn(), fn)
+}
+
+// withTransferRetry is like withRetry for a transfer reporting its
+// bytes to progress: the bytes reported by a failed attempt are taken
+// back from progress before the transfer is retried.
+func withTransferRetry(ctx context.Context, operation, url string, progress io.Reader, fn func(progress io.Reader) *probe.Error) *probe.Error {
+	var attempt *countingReader
+	return withRetry(ctx, operation, url, func() *probe.Error {
+		if attempt != nil {
+			rewindProgress(progress, atomic.LoadInt64(&attempt.n))
+		}
+		attempt = &countingReader{r: progress}
+		return fn(attempt)
+	})
+}
+
+// countingReader counts the bytes reported to a progress hook.
+type countingReader struct {
+	n int64
+	r io.Reader
+}
+
+// Read implements the io.Reader interface
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, e := c.r.Read(p)
+	atomic.AddInt64(&c.n, int64(n))
+	return n, e
+}
+
+// rewindProgress takes n bytes back from the progress hook progress.
+func rewindProgress(progress io.Reader, n int64) {
+	switch p := progress.(type) {
+	case progressHooks:
+		for _, hook := range p {
+			rewindProgress(hook, n)
+		}
+	case Status:
+		p.Add(-n)
+	case *progressBar:
+		p.ProgressBar.Add64(-n)
+	case *accounter:
+		p.Add(-n)
+	case *progressEvents:
+		p.addTransferred(-n)
+	}
+}
+
+// retryAfter is like withRetry, for a first attempt that was
+// already made elsewhere and failed with err, e.g. as part of
+// a bulk request.
+func retryAfter(ctx context.Context, operation, url string, err *probe.Error, fn func() *probe.Error) *probe.Error {
+	for attempt := 1; ; attempt++ {
+		if err == nil || attempt >= globalRetryPolicy.attempts || !isRetriableError(err) {
+			return err
+		}
+
+		wait := globalRetryPolicy.wait(attempt)
+		if globalJSON || !globalQuiet {
+			printMsg(retryMessage{
+				Operation:   operation,
+				URL:         url,
+				Attempt:     attempt + 1,
+				MaxAttempts: globalRetryPolicy.attempts,
+				Wait:        wait.String(),
+				Error:       err.ToGoError().Error(),
+			})
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		err = fn()
+	}
+}
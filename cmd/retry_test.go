@@ -0,0 +1,132 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	minio "github.com/trinet2005/oss-go-sdk"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+func TestIsRetriableError(t *testing.T) {
+	testCases := []struct {
+		err       error
+		retriable bool
+	}{
+		{fmt.Errorf("%w: no data transferred for 1m", errTransferStalled), true},
+		{context.Canceled, false},
+		{context.DeadlineExceeded, true},
+		{io.ErrUnexpectedEOF, true},
+		// Connection reset by the server.
+		{&url.Error{Op: "Put", URL: "http://localhost:9000/bucket/object", Err: &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}}, true},
+		{&net.OpError{Op: "write", Net: "tcp", Err: os.NewSyscallError("write", syscall.EPIPE)}, true},
+		// Network errors which are not transient.
+		{&url.Error{Op: "Get", URL: "http://localhost:9000/bucket", Err: &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}}, false},
+		{&net.DNSError{Err: "no such host", Name: "unknown.invalid"}, false},
+		{&net.DNSError{Err: "i/o timeout", Name: "unknown.invalid", IsTimeout: true}, true},
+		// Server errors.
+		{minio.ErrorResponse{Code: "SlowDown", StatusCode: 503}, true},
+		{minio.ErrorResponse{Code: "SlowDownRead", StatusCode: 400}, true},
+		{minio.ErrorResponse{Code: "InternalError", StatusCode: 500}, true},
+		{minio.ErrorResponse{Code: "BadGateway", StatusCode: 502}, true},
+		{minio.ErrorResponse{StatusCode: 429}, true},
+		{minio.ErrorResponse{Code: "AccessDenied", StatusCode: 403}, false},
+		{minio.ErrorResponse{Code: "RequestTimeTooSkewed", StatusCode: 403}, false},
+		{minio.ErrorResponse{Code: "NoSuchKey", StatusCode: 404}, false},
+		{errors.New("unexpected"), false},
+	}
+
+	for i, testCase := range testCases {
+		if got := isRetriableError(probe.NewError(testCase.err)); got != testCase.retriable {
+			t.Fatalf("Test %d: expected retriable %v for `%v`, got %v", i+1, testCase.retriable, testCase.err, got)
+		}
+	}
+	if isRetriableError(nil) {
+		t.Fatalf("expected no retry without error")
+	}
+}
+
+func TestRetryPolicyWait(t *testing.T) {
+	testCases := []struct {
+		policy   retryPolicy
+		attempt  int
+		expected time.Duration
+	}{
+		{retryPolicy{backoff: time.Second, maxWait: 30 * time.Second}, 1, time.Second},
+		{retryPolicy{backoff: time.Second, maxWait: 30 * time.Second}, 2, 2 * time.Second},
+		{retryPolicy{backoff: time.Second, maxWait: 30 * time.Second}, 5, 16 * time.Second},
+		{retryPolicy{backoff: time.Second, maxWait: 30 * time.Second}, 6, 30 * time.Second},
+		{retryPolicy{backoff: time.Second, maxWait: 30 * time.Second}, 100, 30 * time.Second},
+		{retryPolicy{backoff: time.Second}, 4, 8 * time.Second},
+		{retryPolicy{backoff: 0, maxWait: 30 * time.Second}, 3, 0},
+	}
+
+	for i, testCase := range testCases {
+		if got := testCase.policy.wait(testCase.attempt); got != testCase.expected {
+			t.Fatalf("Test %d: expected a wait of %s, got %s", i+1, testCase.expected, got)
+		}
+	}
+}
+
+func TestWithTransferRetry(t *testing.T) {
+	policy, quiet := globalRetryPolicy, globalQuiet
+	defer func() {
+		globalRetryPolicy, globalQuiet = policy, quiet
+	}()
+	globalRetryPolicy = retryPolicy{attempts: 3, backoff: time.Millisecond}
+	globalQuiet = true
+
+	acct := &accounter{}
+	events := &progressEvents{}
+	attempts := 0
+	err := withTransferRetry(context.Background(), "copy", "object", progressHooks{acct, events}, func(progress io.Reader) *probe.Error {
+		attempts++
+		size := 100
+		if attempts == 1 {
+			// The connection drops after 60 bytes.
+			size = 60
+		}
+		progress.Read(make([]byte, size))
+		if attempts == 1 {
+			return probe.NewError(io.ErrUnexpectedEOF)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempts != 2 {
+		t.Fatalf("expected 2 attempts, got %d", attempts)
+	}
+	if acct.Get() != 100 {
+		t.Fatalf("expected a progress of 100 bytes, got %d", acct.Get())
+	}
+	if events.transferred != 100 {
+		t.Fatalf("expected 100 transferred bytes, got %d", events.transferred)
+	}
+}
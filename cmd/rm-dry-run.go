@@ -0,0 +1,103 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+// rmDryRunReport accounts for what a removal with --dry-run would remove.
+type rmDryRunReport struct {
+	lastKey       string
+	Objects       uint64
+	Versions      uint64
+	DeleteMarkers uint64
+	Size          int64
+}
+
+// add accounts for content, an object or a version of the object key.
+// Listings are sorted, the versions of an object are consecutive.
+func (r *rmDryRunReport) add(key string, content *ClientContent) {
+	if r == nil {
+		return
+	}
+	if key != r.lastKey {
+		r.lastKey = key
+		r.Objects++
+	}
+	if content.VersionID != "" {
+		r.Versions++
+	}
+	if content.IsDeleteMarker {
+		r.DeleteMarkers++
+		return
+	}
+	r.Size += content.Size
+}
+
+// rmDryRunSummaryMessage is the summary of a removal with --dry-run.
+type rmDryRunSummaryMessage struct {
+	Status        string `json:"status"`
+	DryRun        bool   `json:"dryRun"`
+	Objects       uint64 `json:"objects"`
+	Versions      uint64 `json:"versions"`
+	DeleteMarkers uint64 `json:"deleteMarkers"`
+	Size          int64  `json:"size"`
+}
+
+func (r rmDryRunSummaryMessage) String() string {
+	msg := fmt.Sprintf("DRYRUN: Would remove %s object(s)", humanize.Comma(int64(r.Objects)))
+	if r.Versions > 0 {
+		msg += fmt.Sprintf(", %s version(s) including %s delete marker(s)",
+			humanize.Comma(int64(r.Versions)), humanize.Comma(int64(r.DeleteMarkers)))
+	}
+	msg += ", freeing up to " + humanize.IBytes(uint64(r.Size)) + "."
+	return console.Colorize("RemoveSummary", msg)
+}
+
+func (r rmDryRunSummaryMessage) JSON() string {
+	r.Status = "success"
+	msgBytes, e := json.MarshalIndent(r, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// finishDryRun prints the summary of report, if any, and fails when
+// nothing would have been removed so that scripts can be validated.
+func finishDryRun(report *rmDryRunReport, err error) error {
+	if report == nil {
+		return err
+	}
+	printMsg(rmDryRunSummaryMessage{
+		DryRun:        true,
+		Objects:       report.Objects,
+		Versions:      report.Versions,
+		DeleteMarkers: report.DeleteMarkers,
+		Size:          report.Size,
+	})
+	if err == nil && report.Objects == 0 {
+		errorIf(errDummy().Trace(), "No object/version matches, nothing would be removed.")
+		return exitStatus(globalErrorExitStatus)
+	}
+	return err
+}
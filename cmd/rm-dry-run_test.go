@@ -0,0 +1,56 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import "testing"
+
+func TestRmDryRunReport(t *testing.T) {
+	testCases := []struct {
+		keys     []string
+		contents []ClientContent
+		expected rmDryRunReport
+	}{
+		{nil, nil, rmDryRunReport{}},
+		{
+			[]string{"s3/b/a", "s3/b/c"},
+			[]ClientContent{{Size: 10}, {Size: 20}},
+			rmDryRunReport{Objects: 2, Size: 30},
+		},
+		{
+			[]string{"s3/b/a", "s3/b/a", "s3/b/a", "s3/b/c"},
+			[]ClientContent{
+				{VersionID: "v3", IsDeleteMarker: true},
+				{VersionID: "v2", Size: 10},
+				{VersionID: "v1", Size: 5},
+				{VersionID: "v1", Size: 20},
+			},
+			rmDryRunReport{Objects: 2, Versions: 4, DeleteMarkers: 1, Size: 35},
+		},
+	}
+
+	for i, testCase := range testCases {
+		report := &rmDryRunReport{}
+		for j := range testCase.contents {
+			report.add(testCase.keys[j], &testCase.contents[j])
+		}
+		report.lastKey = ""
+		if *report != testCase.expected {
+			t.Fatalf("Test %d: expected %+v, got %+v", i+1, testCase.expected, *report)
+		}
+	}
+}
@@ -69,7 +69,7 @@ var (
 		},
 		cli.BoolFlag{
 			Name:  "dry-run",
-			Usage: "perform a fake remove operation",
+			Usage: "list the object(s) that would be removed, with their count and size, without removing them",
 		},
 		cli.BoolFlag{
 			Name:   "fake",
@@ -80,6 +80,10 @@ var (
 			Name:  "stdin",
 			Usage: "read object names from STDIN",
 		},
+		cli.StringFlag{
+			Name:  "files-from",
+			Usage: "remove the keys listed in a file, one per line or JSON with version IDs, relative to TARGET ('-' reads STDIN)",
+		},
 		cli.StringFlag{
 			Name:  "older-than",
 			Usage: "remove objects older than value in duration string (e.g. 7d10h31s)",
@@ -88,6 +92,20 @@ var (
 			Name:  "newer-than",
 			Usage: "remove objects newer than value in duration string (e.g. 7d10h31s)",
 		},
+		cli.IntFlag{
+			Name:  "limit-objects",
+			Usage: "maximum number of objects removed per second, with --recursive or --versions",
+		},
+		activeHoursFlag,
+		cli.IntFlag{
+			Name:  "workers",
+			Usage: "number of concurrent batches of up to 1000 objects removed, with --recursive or --versions",
+			Value: 4,
+		},
+		cli.StringSliceFlag{
+			Name:  "tags",
+			Usage: "remove only object(s) whose tags match, each specified as key=value or key!=value, with --recursive",
+		},
 		cli.BoolFlag{
 			Name:  "bypass",
 			Usage: "bypass governance",
@@ -101,6 +119,7 @@ var (
 			Usage:  "attempt a prefix purge, requires confirmation please use with caution - only works with '--force'",
 			Hidden: true,
 		},
+		approvalFlag,
 	}
 )
 
@@ -111,7 +130,7 @@ var rmCmd = cli.Command{
 	Action:       mainRm,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        append(append(rmFlags, ioFlags...), globalFlags...),
+	Flags:        append(append(append(rmFlags, retryFlags...), ioFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -167,6 +186,34 @@ EXAMPLES:
   14. Perform a fake removal of object(s) versions that are non-current and older than 10 days. If top-level version is a delete 
   marker, this will also be deleted when --non-current flag is specified.
       {{.Prompt}} {{.HelpName}} s3/docs/ --recursive --force --versions --non-current --older-than 10d --dry-run
+
+  15. Remove a file, retrying up to 3 times if the server is temporarily unavailable.
+      {{.Prompt}} {{.HelpName}} --retry-attempts 3 s3/sql-backups/1999/old-backup.tgz
+
+  16. Remove the object versions listed in an inventory report, one JSON document per line.
+      {{.Prompt}} {{.HelpName}} --force --files-from expired.json s3/sql-backups/
+
+  17. Remove a large prefix recursively, deleting at most 500 objects per second.
+      {{.Prompt}} {{.HelpName}} --recursive --force --limit-objects 500 s3/logs/2019/
+
+  18. Remove a large prefix recursively, only between 10 PM and 6 AM.
+      {{.Prompt}} {{.HelpName}} --recursive --force --active-hours 22:00-06:00 s3/logs/2019/
+
+  19. Remove millions of objects recursively, with 16 concurrent batches of up to 1000 objects.
+      {{.Prompt}} {{.HelpName}} --recursive --force --workers 16 s3/logs/2018/
+
+  20. Validate a removal before running it, counting the objects and versions and the bytes to be freed.
+      The command fails if no object matches.
+      {{.Prompt}} {{.HelpName}} --recursive --force --versions --older-than 30d --dry-run s3/logs/
+
+  21. Remove all objects recursively, except those tagged 'retain=true'.
+      {{.Prompt}} {{.HelpName}} --recursive --force --tags "retain!=true" s3/backups/
+
+  22. Remove all objects recursively tagged 'env=dev' and 'tmp=yes'.
+      {{.Prompt}} {{.HelpName}} --recursive --force --tags "env=dev" --tags "tmp=yes" s3/scratch/
+
+  23. Remove all object versions recursively on the protected alias 'prod', once the request was approved with 'mc approve'.
+      {{.Prompt}} {{.HelpName}} --recursive --force --versions --approval mc-approval-6f1c2e9a4b7d3c05.json prod/backups/
 `,
 }
 
@@ -222,8 +269,45 @@ func checkRmSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[string
 	isForceDel := cliCtx.Bool("purge")
 	versionID := cliCtx.String("version-id")
 	rewind := cliCtx.String("rewind")
+	filesFrom := cliCtx.String("files-from")
+	limitObjects := cliCtx.Int("limit-objects")
 	isNamespaceRemoval := false
 
+	if limitObjects < 0 {
+		fatalIf(errDummy().Trace(), "--limit-objects cannot be negative.")
+	}
+	if limitObjects > maxLimitObjects {
+		fatalIf(errDummy().Trace(), "--limit-objects cannot be more than %d.", maxLimitObjects)
+	}
+	if limitObjects > 0 && !isRecursive && !isVersions {
+		fatalIf(errDummy().Trace(), "--limit-objects requires --recursive or --versions.")
+	}
+	if cliCtx.Int("workers") < 1 {
+		fatalIf(errDummy().Trace(), "--workers should be at least 1.")
+	}
+	if cliCtx.String("active-hours") != "" && !isRecursive && !isVersions {
+		fatalIf(errDummy().Trace(), "--active-hours requires --recursive or --versions.")
+	}
+	if cliCtx.IsSet("tags") {
+		if !isRecursive {
+			fatalIf(errDummy().Trace(), "--tags requires --recursive.")
+		}
+		_, err := parseRmTagFilters(cliCtx.StringSlice("tags"))
+		fatalIf(err, "Unable to parse --tags.")
+	}
+
+	if filesFrom != "" {
+		if len(cliCtx.Args()) != 1 || isStdin || isRecursive || isVersions || isForceDel || versionID != "" || rewind != "" {
+			fatalIf(errDummy().Trace(),
+				"You cannot specify --files-from with more than one TARGET or with any of --stdin, --recursive, --versions, --purge, --version-id and --rewind flags.")
+		}
+		if !isForce {
+			fatalIf(errDummy().Trace(),
+				"Removal requires --force flag. This operation is *IRREVERSIBLE*. Please review carefully before performing this *DANGEROUS* operation.")
+		}
+		return
+	}
+
 	if versionID != "" && (isRecursive || isVersions || rewind != "") {
 		fatalIf(errDummy().Trace(),
 			"You cannot specify --version-id with any of --versions, --rewind and --recursive flags.")
@@ -348,13 +432,9 @@ func removeSingle(url, versionID string, opts removeOpts) error {
 			targetURL = targetURL + string(clnt.GetURL().Separator)
 		}
 
-		contentCh := make(chan *ClientContent, 1)
 		contentURL := *newClientURL(targetURL)
-		contentCh <- &ClientContent{URL: contentURL, VersionID: versionID}
-		close(contentCh)
-		isRemoveBucket := false
-		resultCh := clnt.Remove(ctx, opts.isIncomplete, isRemoveBucket, opts.isBypass, opts.isForce && opts.isForceDel, contentCh)
-		for result := range resultCh {
+		results := removeWithRetry(ctx, clnt, &ClientContent{URL: contentURL, VersionID: versionID}, opts)
+		for _, result := range results {
 			if result.Err != nil {
 				errorIf(result.Err.Trace(url), "Failed to remove `"+url+"`.")
 				switch result.Err.ToGoError().(type) {
@@ -375,11 +455,47 @@ func removeSingle(url, versionID string, opts removeOpts) error {
 			printMsg(msg)
 		}
 	} else {
-		printDryRunMsg(targetAlias, content, opts.withVersions)
+		printDryRunMsg(targetAlias, content, opts.withVersions, opts.dryRunReport)
 	}
 	return nil
 }
 
+// removeWithRetry removes a single object, retrying transient
+// failures according to the configured retry policy.
+func removeWithRetry(ctx context.Context, clnt Client, content *ClientContent, opts removeOpts) (results []RemoveResult) {
+	withRetry(ctx, "remove", content.URL.String(), removeOnce(ctx, clnt, content, opts, &results))
+	return results
+}
+
+// retryRemove retries the removal of an object whose first attempt,
+// made as part of a bulk removal, failed with a retriable error.
+func retryRemove(ctx context.Context, clnt Client, content *ClientContent, failed RemoveResult, opts removeOpts) (results []RemoveResult) {
+	results = []RemoveResult{failed}
+	retryAfter(ctx, "remove", content.URL.String(), failed.Err, removeOnce(ctx, clnt, content, opts, &results))
+	return results
+}
+
+// removeOnce returns a function removing content, storing its results
+// in results and returning the first retriable error.
+func removeOnce(ctx context.Context, clnt Client, content *ClientContent, opts removeOpts, results *[]RemoveResult) func() *probe.Error {
+	return func() *probe.Error {
+		contentCh := make(chan *ClientContent, 1)
+		contentCh <- content
+		close(contentCh)
+
+		var err *probe.Error
+		isRemoveBucket := false
+		*results = (*results)[:0]
+		for result := range clnt.Remove(ctx, opts.isIncomplete, isRemoveBucket, opts.isBypass, opts.isForce && opts.isForceDel, contentCh) {
+			if err == nil && isRetriableError(result.Err) {
+				err = result.Err
+			}
+			*results = append(*results, result)
+		}
+		return err
+	}
+}
+
 type removeOpts struct {
 	timeRef           time.Time
 	withVersions      bool
@@ -393,12 +509,18 @@ type removeOpts struct {
 	olderThan         string
 	newerThan         string
 	encKeyDB          map[string][]prefixSSEPair
+	limiter           *objectLimiter
+	activeHours       *activeHours
+	workers           int
+	tagFilters        []rmTagFilter
+	dryRunReport      *rmDryRunReport
 }
 
-func printDryRunMsg(targetAlias string, content *ClientContent, printModTime bool) {
+func printDryRunMsg(targetAlias string, content *ClientContent, printModTime bool, report *rmDryRunReport) {
 	if content == nil {
 		return
 	}
+	report.add(targetAlias+getKey(content), content)
 	msg := rmMessage{
 		Status:    "success",
 		DryRun:    true,
@@ -427,9 +549,8 @@ func listAndRemove(url string, opts removeOpts) error {
 		return exitStatus(globalErrorExitStatus) // End of journey.
 	}
 	contentCh := make(chan *ClientContent)
-	isRemoveBucket := false
 
-	listOpts := ListOptions{Recursive: opts.isRecursive, Incomplete: opts.isIncomplete, ShowDir: DirLast}
+	listOpts := ListOptions{Recursive: opts.isRecursive, Incomplete: opts.isIncomplete, ShowDir: DirLast, WithMetadata: len(opts.tagFilters) > 0}
 	if !opts.timeRef.IsZero() {
 		listOpts.WithOlderVersions = opts.withVersions
 		listOpts.WithDeleteMarkers = true
@@ -437,7 +558,116 @@ func listAndRemove(url string, opts removeOpts) error {
 	}
 	atLeastOneObjectFound := false
 
-	resultCh := clnt.Remove(ctx, opts.isIncomplete, isRemoveBucket, opts.isBypass, false, contentCh)
+	resultCh := removeWorkers(ctx, clnt, opts.workers, opts.isIncomplete, opts.isBypass, contentCh)
+
+	// Failures are reported per object, the removal goes on with the
+	// next objects and the exit status is set at the end.
+	failed := false
+	reportResult := func(result RemoveResult) {
+		path := path.Join(targetAlias, result.BucketName, result.ObjectName)
+		if result.Err != nil {
+			errorIf(result.Err.Trace(path), "Failed to remove `"+path+"`.")
+			switch e := result.Err.ToGoError().(type) {
+			case PathInsufficientPermission:
+				// Ignore Permission error.
+				return
+			case minio.ErrorResponse:
+				if strings.Contains(e.Message, "Object is WORM protected and cannot be overwritten") {
+					return
+				}
+			}
+			failed = true
+			return
+		}
+		msg := rmMessage{
+			Key:       path,
+			VersionID: result.ObjectVersionID,
+		}
+		if result.DeleteMarker {
+			msg.DeleteMarker = true
+			msg.VersionID = result.DeleteMarkerVersionID
+		}
+		printMsg(msg)
+	}
+	handleResult := func(result RemoveResult) {
+		if !isRetriableError(result.Err) || globalRetryPolicy.attempts <= 1 {
+			reportResult(result)
+			return
+		}
+		// Bulk removals fail per object, retry the failed
+		// objects one by one.
+		_, objectURL, _ := mustExpandAlias(path.Join(targetAlias, result.BucketName, result.ObjectName))
+		content := &ClientContent{URL: *newClientURL(objectURL), VersionID: result.ObjectVersionID}
+		for _, result := range retryRemove(ctx, clnt, content, result, opts) {
+			reportResult(result)
+		}
+	}
+
+	// removeContent sends content to the workers, handling their results
+	// meanwhile so that they never block.
+	removeContent := func(content *ClientContent) {
+		if opts.isFake {
+			printDryRunMsg(targetAlias, content, opts.withVersions, opts.dryRunReport)
+			return
+		}
+		opts.activeHours.wait(ctx)
+		opts.limiter.wait()
+		for {
+			select {
+			case contentCh <- content:
+				return
+			case result, ok := <-resultCh:
+				if !ok {
+					return
+				}
+				handleResult(result)
+			}
+		}
+	}
+
+	// isSelected returns true if content is within --older-than and --newer-than
+	// and its tags match --tags.
+	isSelected := func(content *ClientContent) bool {
+		if content.Time.IsZero() {
+			// Skip prefix levels.
+			return false
+		}
+		// Skip objects older than --older-than parameter, if specified
+		if opts.olderThan != "" && isOlder(content.Time, opts.olderThan) {
+			return false
+		}
+		// Skip objects newer than --newer-than parameter if specified
+		if opts.newerThan != "" && isNewer(content.Time, opts.newerThan) {
+			return false
+		}
+		if len(opts.tagFilters) > 0 {
+			// Delete markers carry no tags, they are never selected.
+			if content.IsDeleteMarker {
+				return false
+			}
+			key := targetAlias + getKey(content)
+			tags, err := getContentTags(ctx, targetAlias, content)
+			if err != nil {
+				warningIf(warningSkipped, err.Trace(key), "Unable to get the tags of `"+key+"`, skipping it.")
+				failed = true
+				return false
+			}
+			return matchRmTagFilters(opts.tagFilters, tags)
+		}
+		return true
+	}
+
+	// removeNonCurrent removes the non-current versions of an object.
+	removeNonCurrent := func(versions []*ClientContent) {
+		for _, content := range versions {
+			if content.IsLatest && !content.IsDeleteMarker {
+				continue
+			}
+			if isSelected(content) {
+				removeContent(content)
+			}
+		}
+	}
 
 	var lastPath string
 	var perObjectVersions []*ClientContent
@@ -471,60 +701,7 @@ func listAndRemove(url string, opts removeOpts) error {
 		if opts.nonCurrentVersion && opts.isRecursive && opts.withVersions {
 			if lastPath != content.URL.Path {
 				lastPath = content.URL.Path
-				for _, content := range perObjectVersions {
-					if content.IsLatest && !content.IsDeleteMarker {
-						continue
-					}
-					if !content.Time.IsZero() {
-						// Skip objects older than --older-than parameter, if specified
-						if opts.olderThan != "" && isOlder(content.Time, opts.olderThan) {
-							continue
-						}
-
-						// Skip objects newer than --newer-than parameter if specified
-						if opts.newerThan != "" && isNewer(content.Time, opts.newerThan) {
-							continue
-						}
-					} else {
-						// Skip prefix levels.
-						continue
-					}
-
-					if opts.isFake {
-						printDryRunMsg(targetAlias, content, true)
-						continue
-					}
-
-					sent := false
-					for !sent {
-						select {
-						case contentCh <- content:
-							sent = true
-						case result := <-resultCh:
-							path := path.Join(targetAlias, result.BucketName, result.ObjectName)
-							if result.Err != nil {
-								errorIf(result.Err.Trace(path),
-									"Failed to remove `"+path+"`.")
-								switch result.Err.ToGoError().(type) {
-								case PathInsufficientPermission:
-									// Ignore Permission error.
-									continue
-								}
-								close(contentCh)
-								return exitStatus(globalErrorExitStatus)
-							}
-							msg := rmMessage{
-								Key:       path,
-								VersionID: result.ObjectVersionID,
-							}
-							if result.DeleteMarker {
-								msg.DeleteMarker = true
-								msg.VersionID = result.DeleteMarkerVersionID
-							}
-							printMsg(msg)
-						}
-					}
-				}
+				removeNonCurrent(perObjectVersions)
 				perObjectVersions = []*ClientContent{}
 			}
 			atLeastOneObjectFound = true
@@ -537,142 +714,25 @@ func listAndRemove(url string, opts removeOpts) error {
 		// inform the user that he was searching in an empty area
 		atLeastOneObjectFound = true
 
-		if !content.Time.IsZero() {
-			// Skip objects older than --older-than parameter, if specified
-			if opts.olderThan != "" && isOlder(content.Time, opts.olderThan) {
-				continue
-			}
-
-			// Skip objects newer than --newer-than parameter if specified
-			if opts.newerThan != "" && isNewer(content.Time, opts.newerThan) {
-				continue
-			}
-		} else {
-			// Skip prefix levels.
-			continue
-		}
-
-		if !opts.isFake {
-			sent := false
-			for !sent {
-				select {
-				case contentCh <- content:
-					sent = true
-				case result := <-resultCh:
-					path := path.Join(targetAlias, result.BucketName, result.ObjectName)
-					if result.Err != nil {
-						errorIf(result.Err.Trace(path),
-							"Failed to remove `"+path+"`.")
-						switch e := result.Err.ToGoError().(type) {
-						case PathInsufficientPermission:
-							// Ignore Permission error.
-							continue
-						case minio.ErrorResponse:
-							if strings.Contains(e.Message, "Object is WORM protected and cannot be overwritten") {
-								continue
-							}
-						}
-						close(contentCh)
-						return exitStatus(globalErrorExitStatus)
-					}
-					msg := rmMessage{
-						Key:       path,
-						VersionID: result.ObjectVersionID,
-					}
-					if result.DeleteMarker {
-						msg.DeleteMarker = true
-						msg.VersionID = result.DeleteMarkerVersionID
-					}
-					printMsg(msg)
-				}
-			}
-		} else {
-			printDryRunMsg(targetAlias, content, opts.withVersions)
+		if isSelected(content) {
+			removeContent(content)
 		}
 	}
 
 	if opts.nonCurrentVersion && opts.isRecursive && opts.withVersions {
-		for _, content := range perObjectVersions {
-			if content.IsLatest && !content.IsDeleteMarker {
-				continue
-			}
-			if !content.Time.IsZero() {
-				// Skip objects older than --older-than parameter, if specified
-				if opts.olderThan != "" && isOlder(content.Time, opts.olderThan) {
-					continue
-				}
-
-				// Skip objects newer than --newer-than parameter if specified
-				if opts.newerThan != "" && isNewer(content.Time, opts.newerThan) {
-					continue
-				}
-			} else {
-				// Skip prefix levels.
-				continue
-			}
-
-			if opts.isFake {
-				printDryRunMsg(targetAlias, content, true)
-				continue
-			}
-
-			sent := false
-			for !sent {
-				select {
-				case contentCh <- content:
-					sent = true
-				case result := <-resultCh:
-					path := path.Join(targetAlias, result.BucketName, result.ObjectName)
-					if result.Err != nil {
-						errorIf(result.Err.Trace(path),
-							"Failed to remove `"+path+"`.")
-						switch result.Err.ToGoError().(type) {
-						case PathInsufficientPermission:
-							// Ignore Permission error.
-							continue
-						}
-						close(contentCh)
-						return exitStatus(globalErrorExitStatus)
-					}
-					msg := rmMessage{
-						Key:       path,
-						VersionID: result.ObjectVersionID,
-					}
-					if result.DeleteMarker {
-						msg.DeleteMarker = true
-						msg.VersionID = result.DeleteMarkerVersionID
-					}
-					printMsg(msg)
-				}
-			}
-		}
+		removeNonCurrent(perObjectVersions)
 	}
 
 	close(contentCh)
+	for result := range resultCh {
+		handleResult(result)
+	}
+	if failed {
+		return exitStatus(globalErrorExitStatus)
+	}
 	if opts.isFake {
 		return nil
 	}
-	for result := range resultCh {
-		path := path.Join(targetAlias, result.BucketName, result.ObjectName)
-		if result.Err != nil {
-			errorIf(result.Err.Trace(path), "Failed to remove `"+path+"` recursively.")
-			switch result.Err.ToGoError().(type) {
-			case PathInsufficientPermission:
-				// Ignore Permission error.
-				continue
-			}
-			return exitStatus(globalErrorExitStatus)
-		}
-		msg := rmMessage{
-			Key:       path,
-			VersionID: result.ObjectVersionID,
-		}
-		if result.DeleteMarker {
-			msg.DeleteMarker = true
-			msg.VersionID = result.DeleteMarkerVersionID
-		}
-		printMsg(msg)
-	}
 
 	if !atLeastOneObjectFound {
 		if opts.isForce {
@@ -720,6 +780,55 @@ func mainRm(cliCtx *cli.Context) error {
 
 	// Set color.
 	console.SetColor("Removed", color.New(color.FgGreen, color.Bold))
+	console.SetColor("ActiveHours", color.New(color.FgYellow))
+	console.SetColor("RemoveSummary", color.New(color.Bold))
+	console.SetColor("Retry", color.New(color.FgYellow))
+
+	// Removing every version on protected aliases needs a second operator's approval.
+	if withVersions && isRecursive && !isFake {
+		command := approvalCommand(cliCtx, "rm", "recursive", "versions", "force", "rewind", "older-than", "newer-than",
+			"non-current", "tags", "bypass", "purge", "files-from")
+		if !checkApproval(cliCtx, command, cliCtx.Args()) {
+			return exitStatus(globalErrorExitStatus)
+		}
+	}
+
+	var dryRunReport *rmDryRunReport
+	if isFake {
+		dryRunReport = &rmDryRunReport{}
+	}
+
+	if filesFrom := cliCtx.String("files-from"); filesFrom != "" {
+		targetURL := cliCtx.Args().Get(0)
+		var rerr error
+		err = readFileList(filesFrom, func(entry fileListEntry) *probe.Error {
+			e := removeSingle(urlJoinPath(targetURL, entry.Key), entry.VersionID, removeOpts{
+				isIncomplete: isIncomplete,
+				isFake:       isFake,
+				dryRunReport: dryRunReport,
+				isForce:      isForce,
+				isBypass:     isBypass,
+				olderThan:    olderThan,
+				newerThan:    newerThan,
+				encKeyDB:     encKeyDB,
+			})
+			if rerr == nil {
+				rerr = e
+			}
+			return nil
+		})
+		fatalIf(err, "Unable to read list of objects `"+filesFrom+"`.")
+		return finishDryRun(dryRunReport, rerr)
+	}
+
+	limiter := newObjectLimiter(cliCtx.Int("limit-objects"))
+	defer limiter.stop()
+
+	activeHours, err := parseActiveHours(cliCtx.String("active-hours"))
+	fatalIf(err, "Unable to parse --active-hours.")
+
+	tagFilters, err := parseRmTagFilters(cliCtx.StringSlice("tags"))
+	fatalIf(err, "Unable to parse --tags.")
 
 	var rerr error
 	var e error
@@ -734,15 +843,21 @@ func mainRm(cliCtx *cli.Context) error {
 				isRecursive:       isRecursive,
 				isIncomplete:      isIncomplete,
 				isFake:            isFake,
+				dryRunReport:      dryRunReport,
 				isBypass:          isBypass,
 				olderThan:         olderThan,
 				newerThan:         newerThan,
 				encKeyDB:          encKeyDB,
+				limiter:           limiter,
+				activeHours:       activeHours,
+				workers:           cliCtx.Int("workers"),
+				tagFilters:        tagFilters,
 			})
 		} else {
 			e = removeSingle(url, versionID, removeOpts{
 				isIncomplete: isIncomplete,
 				isFake:       isFake,
+				dryRunReport: dryRunReport,
 				isForce:      isForce,
 				isForceDel:   isForceDel,
 				isBypass:     isBypass,
@@ -757,12 +872,21 @@ func mainRm(cliCtx *cli.Context) error {
 	}
 
 	if !isStdin {
-		return rerr
+		return finishDryRun(dryRunReport, rerr)
 	}
 
 	scanner := bufio.NewScanner(os.Stdin)
 	for scanner.Scan() {
 		url := scanner.Text()
+		if withVersions && isRecursive && !isFake {
+			if err := checkStdinApproval(url); err != nil {
+				errorIf(err.Trace(url), "Unable to remove all versions of `"+url+"`.")
+				if rerr == nil {
+					rerr = exitStatus(globalErrorExitStatus)
+				}
+				continue
+			}
+		}
 		if isRecursive || withVersions {
 			e = listAndRemove(url, removeOpts{
 				timeRef:           rewind,
@@ -772,15 +896,21 @@ func mainRm(cliCtx *cli.Context) error {
 				isRecursive:       isRecursive,
 				isIncomplete:      isIncomplete,
 				isFake:            isFake,
+				dryRunReport:      dryRunReport,
 				isBypass:          isBypass,
 				olderThan:         olderThan,
 				newerThan:         newerThan,
 				encKeyDB:          encKeyDB,
+				limiter:           limiter,
+				activeHours:       activeHours,
+				workers:           cliCtx.Int("workers"),
+				tagFilters:        tagFilters,
 			})
 		} else {
 			e = removeSingle(url, versionID, removeOpts{
 				isIncomplete: isIncomplete,
 				isFake:       isFake,
+				dryRunReport: dryRunReport,
 				isForce:      isForce,
 				isForceDel:   isForceDel,
 				isBypass:     isBypass,
@@ -794,5 +924,5 @@ func mainRm(cliCtx *cli.Context) error {
 		}
 	}
 
-	return rerr
+	return finishDryRun(dryRunReport, rerr)
 }
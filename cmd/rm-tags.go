@@ -0,0 +1,80 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+// rmTagFilter selects objects on the value of one of their tags.
+type rmTagFilter struct {
+	key    string
+	value  string
+	negate bool
+}
+
+// parseRmTagFilters parses --tags values, each of the form key=value,
+// the tag is set to value, or key!=value, the tag is missing or set to
+// another value.
+func parseRmTagFilters(values []string) ([]rmTagFilter, *probe.Error) {
+	filters := make([]rmTagFilter, 0, len(values))
+	for _, v := range values {
+		i := strings.Index(v, "=")
+		if i < 0 {
+			return nil, probe.NewError(errors.New("want key=value or key!=value")).Trace(v)
+		}
+		filter := rmTagFilter{key: v[:i], value: v[i+1:]}
+		if strings.HasSuffix(filter.key, "!") {
+			filter.key = strings.TrimSuffix(filter.key, "!")
+			filter.negate = true
+		}
+		if filter.key == "" {
+			return nil, probe.NewError(errors.New("tag key cannot be empty")).Trace(v)
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+// matchRmTagFilters returns true if tags satisfy all the filters.
+func matchRmTagFilters(filters []rmTagFilter, tags map[string]string) bool {
+	for _, filter := range filters {
+		value, ok := tags[filter.key]
+		if (ok && value == filter.value) == filter.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// getContentTags returns the tags of content, from the listing when the
+// server returned them, MinIO only, otherwise from a request per object.
+func getContentTags(ctx context.Context, targetAlias string, content *ClientContent) (map[string]string, *probe.Error) {
+	if content.Tags != nil {
+		return content.Tags, nil
+	}
+	clnt, err := newClientFromAlias(targetAlias, content.URL.String())
+	if err != nil {
+		return nil, err
+	}
+	return clnt.GetTags(ctx, content.VersionID)
+}
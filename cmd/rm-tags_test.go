@@ -0,0 +1,57 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import "testing"
+
+func TestRmTagFilters(t *testing.T) {
+	testCases := []struct {
+		filters []string
+		tags    map[string]string
+		match   bool
+		err     bool
+	}{
+		{[]string{"retain!=true"}, nil, true, false},
+		{[]string{"retain!=true"}, map[string]string{"retain": "false"}, true, false},
+		{[]string{"retain!=true"}, map[string]string{"retain": "true"}, false, false},
+		{[]string{"env=dev"}, map[string]string{"env": "dev"}, true, false},
+		{[]string{"env=dev"}, map[string]string{"env": "prod"}, false, false},
+		{[]string{"env=dev"}, nil, false, false},
+		{[]string{"env=dev", "tmp=yes"}, map[string]string{"env": "dev"}, false, false},
+		{[]string{"env=dev", "tmp=yes"}, map[string]string{"env": "dev", "tmp": "yes"}, true, false},
+		{[]string{"expr=a=b"}, map[string]string{"expr": "a=b"}, true, false},
+		{[]string{"retain"}, nil, false, true},
+		{[]string{"!=true"}, nil, false, true},
+	}
+
+	for i, testCase := range testCases {
+		filters, err := parseRmTagFilters(testCase.filters)
+		if testCase.err {
+			if err == nil {
+				t.Fatalf("Test %d: expected an error", i+1)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error: %v", i+1, err)
+		}
+		if match := matchRmTagFilters(filters, testCase.tags); match != testCase.match {
+			t.Fatalf("Test %d: expected match %v, got %v", i+1, testCase.match, match)
+		}
+	}
+}
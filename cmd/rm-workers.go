@@ -0,0 +1,54 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"sync"
+)
+
+// removeWorkers removes the objects received from contentCh with workers
+// concurrent removals and merges their results. On object storage, each
+// removal groups the objects it receives into DeleteObjects requests of
+// up to 1000 keys, so up to workers such requests are in flight at once.
+// Folders of a filesystem must be removed after their contents, they are
+// removed by a single worker.
+func removeWorkers(ctx context.Context, clnt Client, workers int, isIncomplete, isBypass bool, contentCh <-chan *ClientContent) <-chan RemoveResult {
+	if _, ok := clnt.(*S3Client); !ok || workers <= 1 {
+		return clnt.Remove(ctx, isIncomplete, false, isBypass, false, contentCh)
+	}
+
+	resultCh := make(chan RemoveResult)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		// All the workers read from contentCh, objects go to whichever
+		// worker is not busy sending a request.
+		go func(workerCh <-chan RemoveResult) {
+			defer wg.Done()
+			for result := range workerCh {
+				resultCh <- result
+			}
+		}(clnt.Remove(ctx, isIncomplete, false, isBypass, false, contentCh))
+	}
+	go func() {
+		wg.Wait()
+		close(resultCh)
+	}()
+	return resultCh
+}
@@ -0,0 +1,217 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// multiDeleteHandler serves the listing and the DeleteObjects requests
+// of a single bucket, the removal of the denied keys fails.
+type multiDeleteHandler struct {
+	bucket string
+	keys   []string
+	denied map[string]bool
+
+	mu      sync.Mutex
+	removed map[string]int
+}
+
+func (h *multiDeleteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	switch {
+	case r.URL.Path != "/"+h.bucket && r.URL.Path != "/"+h.bucket+"/":
+		w.WriteHeader(http.StatusNotFound)
+	case query.Has("location"):
+		fmt.Fprint(w, `<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">us-east-1</LocationConstraint>`)
+	case r.Method == http.MethodPost && query.Has("delete"):
+		var request struct {
+			Objects []struct {
+				Key string
+			} `xml:"Object"`
+		}
+		if e := xml.NewDecoder(r.Body).Decode(&request); e != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var b strings.Builder
+		b.WriteString(`<DeleteResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/">`)
+		h.mu.Lock()
+		for _, object := range request.Objects {
+			h.removed[object.Key]++
+			if h.denied[object.Key] {
+				fmt.Fprintf(&b, "<Error><Key>%s</Key><Code>AccessDenied</Code><Message>Access Denied.</Message></Error>", object.Key)
+				continue
+			}
+			fmt.Fprintf(&b, "<Deleted><Key>%s</Key></Deleted>", object.Key)
+		}
+		h.mu.Unlock()
+		b.WriteString("</DeleteResult>")
+		fmt.Fprint(w, b.String())
+	case r.Method == http.MethodGet && query.Has("list-type"):
+		var b strings.Builder
+		fmt.Fprintf(&b, `<ListBucketResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><Name>%s</Name><KeyCount>%d</KeyCount><MaxKeys>1000</MaxKeys><IsTruncated>false</IsTruncated>`, h.bucket, len(h.keys))
+		for _, key := range h.keys {
+			fmt.Fprintf(&b, `<Contents><Key>%s</Key><LastModified>%s</LastModified><ETag>"d41d8cd98f00b204e9800998ecf8427e"</ETag><Size>0</Size><StorageClass>STANDARD</StorageClass></Contents>`, key, time.Now().UTC().Format(time.RFC3339))
+		}
+		b.WriteString("</ListBucketResult>")
+		fmt.Fprint(w, b.String())
+	default:
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func newMultiDeleteHandler(n int, denied ...string) *multiDeleteHandler {
+	h := &multiDeleteHandler{
+		bucket:  "bucket",
+		denied:  map[string]bool{},
+		removed: map[string]int{},
+	}
+	for i := 0; i < n; i++ {
+		h.keys = append(h.keys, fmt.Sprintf("object-%03d", i))
+	}
+	for _, key := range denied {
+		h.denied[key] = true
+	}
+	return h
+}
+
+func TestRemoveWorkers(t *testing.T) {
+	testCases := []struct {
+		workers int
+		objects int
+		denied  []string
+	}{
+		{1, 10, nil},
+		{4, 10, nil},
+		{4, 2500, nil},
+		{4, 50, []string{"object-007", "object-031"}},
+		{8, 3, []string{"object-000", "object-001", "object-002"}},
+	}
+
+	for i, testCase := range testCases {
+		h := newMultiDeleteHandler(testCase.objects, testCase.denied...)
+		server := httptest.NewServer(h)
+
+		conf := new(Config)
+		conf.HostURL = server.URL + "/" + h.bucket
+		conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+		conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+		conf.Signature = "S3v4"
+		clnt, err := S3New(conf)
+		if err != nil {
+			server.Close()
+			t.Fatalf("Test %d: expected no error, got %v", i+1, err)
+		}
+
+		contentCh := make(chan *ClientContent)
+		go func() {
+			defer close(contentCh)
+			for _, key := range h.keys {
+				contentCh <- &ClientContent{URL: *newClientURL(server.URL + "/" + h.bucket + "/" + key)}
+			}
+		}()
+
+		// Every key is reported exactly once, the failed ones with
+		// their own error.
+		var removed, failed []string
+		for result := range removeWorkers(context.Background(), clnt, testCase.workers, false, false, contentCh) {
+			if result.BucketName != h.bucket {
+				t.Errorf("Test %d: expected bucket %q, got %q", i+1, h.bucket, result.BucketName)
+			}
+			if result.Err != nil {
+				if !strings.Contains(result.Err.ToGoError().Error(), "Access Denied") {
+					t.Errorf("Test %d: expected access denied for %s, got %v", i+1, result.ObjectName, result.Err.ToGoError())
+				}
+				failed = append(failed, result.ObjectName)
+				continue
+			}
+			removed = append(removed, result.ObjectName)
+		}
+		server.Close()
+
+		if len(removed)+len(failed) != testCase.objects {
+			t.Fatalf("Test %d: expected %d results, got %d", i+1, testCase.objects, len(removed)+len(failed))
+		}
+		sort.Strings(failed)
+		if strings.Join(failed, ",") != strings.Join(testCase.denied, ",") {
+			t.Errorf("Test %d: expected failures %v, got %v", i+1, testCase.denied, failed)
+		}
+		seen := map[string]bool{}
+		for _, key := range append(removed, failed...) {
+			if seen[key] {
+				t.Errorf("Test %d: expected %s to be reported once", i+1, key)
+			}
+			seen[key] = true
+		}
+		for _, key := range h.keys {
+			if h.removed[key] != 1 {
+				t.Errorf("Test %d: expected %s to be removed once, got %d", i+1, key, h.removed[key])
+			}
+		}
+	}
+}
+
+func TestListAndRemoveExitStatus(t *testing.T) {
+	testCases := []struct {
+		workers    int
+		denied     []string
+		shouldFail bool
+	}{
+		{1, nil, false},
+		{4, nil, false},
+		{1, []string{"object-004"}, true},
+		{4, []string{"object-004"}, true},
+		{4, []string{"object-000", "object-019"}, true},
+	}
+
+	for i, testCase := range testCases {
+		h := newMultiDeleteHandler(20, testCase.denied...)
+		server := httptest.NewServer(h)
+		t.Setenv(mcEnvHostPrefix+"rmworkers", strings.Replace(server.URL, "://", "://WLGDGYAQYIGI833EV05A:BYvgJM101sHngl2uzjXS@", 1))
+
+		e := listAndRemove("rmworkers/"+h.bucket+"/", removeOpts{
+			isRecursive: true,
+			isForce:     true,
+			workers:     testCase.workers,
+		})
+		server.Close()
+
+		if testCase.shouldFail && e == nil {
+			t.Errorf("Test %d: expected an error exit status, got none", i+1)
+		}
+		if !testCase.shouldFail && e != nil {
+			t.Errorf("Test %d: expected no error, got %v", i+1, e)
+		}
+		// The failures do not stop the removal of the other objects.
+		for _, key := range h.keys {
+			if h.removed[key] != 1 {
+				t.Errorf("Test %d: expected %s to be removed once, got %d", i+1, key, h.removed[key])
+			}
+		}
+	}
+}
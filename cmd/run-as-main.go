@@ -0,0 +1,184 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/trinet2005/oss-admin-go"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/policy"
+)
+
+var runAsFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "policy",
+		Usage: "path to a JSON policy file restricting the temporary service account",
+	},
+	cli.DurationFlag{
+		Name:  "duration",
+		Value: time.Hour,
+		Usage: "expiry of the temporary service account if it cannot be revoked, between 15m and 24h",
+	},
+}
+
+// Run a mc command with a temporary service account.
+var runAsCmd = cli.Command{
+	Name:            "run-as",
+	Usage:           "run a command with a temporary, policy restricted service account",
+	Action:          mainRunAs,
+	OnUsageError:    onUsageError,
+	Before:          setGlobalsFromContext,
+	Flags:           append(runAsFlags, globalFlags...),
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] ALIAS -- COMMAND [ARGUMENTS...]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  A service account of the alias credentials is created for the time of COMMAND and
+  removed once it returns. While COMMAND runs, ALIAS resolves to the service account,
+  whose permissions are the intersection of the alias user policy and '--policy'.
+
+EXAMPLES:
+  1. Remove old logs with a service account only allowed to delete objects of the logs bucket.
+     {{.Prompt}} {{.HelpName}} --policy delete-logs.json myminio -- rm --recursive --force --older-than 90d myminio/logs
+
+  2. Run a script mirroring data with a service account expiring after 15 minutes.
+     {{.Prompt}} {{.HelpName}} --policy mirror-only.json --duration 15m myminio -- mirror ./data myminio/backup
+`,
+}
+
+// checkRunAsSyntax - validate all the passed arguments
+func checkRunAsSyntax(cliCtx *cli.Context) {
+	args := cliCtx.Args()
+	if len(args) < 2 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+
+	alias := cleanAlias(args.Get(0))
+	if !isValidAlias(alias) {
+		fatalIf(errDummy().Trace(alias), "Invalid alias `"+alias+"`.")
+	}
+
+	if d := cliCtx.Duration("duration"); d < 15*time.Minute || d > 24*time.Hour {
+		fatalIf(errInvalidArgument().Trace(d.String()), "--duration should be between 15m and 24h.")
+	}
+}
+
+// readRunAsPolicy reads and validates the policy of the
+// temporary service account.
+func readRunAsPolicy(policyPath string) ([]byte, *probe.Error) {
+	if policyPath == "" {
+		return nil, nil
+	}
+	policyBytes, e := os.ReadFile(policyPath)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(policyPath)
+	}
+	p, e := policy.ParseConfig(bytes.NewReader(policyBytes))
+	if e != nil {
+		return nil, probe.NewError(e).Trace(policyPath)
+	}
+	if p.IsEmpty() {
+		return nil, errInvalidArgument().Trace(policyPath)
+	}
+	return policyBytes, nil
+}
+
+// runAsHostEnv returns the MC_HOST_<alias> value pointing
+// alias to the given credentials.
+func runAsHostEnv(hostURL, accessKey, secretKey string) (string, *probe.Error) {
+	u, e := url.Parse(hostURL)
+	if e != nil {
+		return "", probe.NewError(e).Trace(hostURL)
+	}
+	u.User = url.UserPassword(accessKey, secretKey)
+	return u.String(), nil
+}
+
+// mainRunAs is the entry point for run-as command.
+func mainRunAs(cliCtx *cli.Context) error {
+	checkRunAsSyntax(cliCtx)
+
+	args := cliCtx.Args()
+	alias := cleanAlias(args.Get(0))
+	command := args[1:]
+
+	_, _, hostCfg, err := expandAlias(alias)
+	fatalIf(err.Trace(alias), "Unable to parse alias `"+alias+"`.")
+	if hostCfg == nil {
+		fatalIf(errInvalidAliasedURL(alias), "No such alias `"+alias+"` found.")
+	}
+
+	policyBytes, err := readRunAsPolicy(cliCtx.String("policy"))
+	fatalIf(err, "Unable to read the policy document.")
+
+	accessKey, secretKey, e := generateCredentials()
+	fatalIf(probe.NewError(e), "Unable to generate temporary credentials.")
+
+	client, err := newAdminClient(alias)
+	fatalIf(err, "Unable to initialize admin connection.")
+
+	// The expiry disables the service account should the revocation
+	// below never happen, e.g. when mc is interrupted.
+	expiration := UTCNow().Add(cliCtx.Duration("duration"))
+	_, e = client.AddServiceAccount(globalContext, madmin.AddServiceAccountReq{
+		Policy:      policyBytes,
+		AccessKey:   accessKey,
+		SecretKey:   secretKey,
+		Description: "temporary service account of mc run-as",
+		Expiration:  &expiration,
+	})
+	fatalIf(probe.NewError(e).Trace(alias), "Unable to add a temporary service account.")
+
+	// Revoke the service account once the command returns, successful or not.
+	defer func() {
+		e := client.DeleteServiceAccount(context.Background(), accessKey)
+		errorIf(probe.NewError(e).Trace(accessKey), "Unable to remove the temporary service account `"+accessKey+"`.")
+	}()
+
+	hostEnv, err := runAsHostEnv(hostCfg.URL, accessKey, secretKey)
+	fatalIf(err, "Unable to prepare the environment of the command.")
+
+	cmd := exec.CommandContext(globalContext, os.Args[0], command...)
+	cmd.Env = append(os.Environ(), mcEnvHostPrefix+alias+"="+hostEnv)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	if e = cmd.Run(); e != nil {
+		var exitErr *exec.ExitError
+		if errors.As(e, &exitErr) {
+			return exitStatus(exitErr.ExitCode())
+		}
+		errorIf(probe.NewError(e).Trace(command...), "Unable to run the command.")
+		return exitStatus(globalErrorExitStatus)
+	}
+	return nil
+}
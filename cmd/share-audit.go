@@ -0,0 +1,235 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	gojson "encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+// shareAuditEntry is one URL recorded in the share audit log. Unlike the
+// uploads and downloads databases, the log keeps expired URLs.
+type shareAuditEntry struct {
+	Type    string        `json:"type"`
+	URL     string        `json:"url"`
+	Share   string        `json:"share"`
+	Creator string        `json:"creator,omitempty"`
+	Date    time.Time     `json:"date"`
+	Expiry  time.Duration `json:"expiry"`
+}
+
+// Get share audit log file.
+func getShareAuditFile() string {
+	return filepath.Join(mustGetShareDir(), "audit.json")
+}
+
+// shareCreator returns the access key signing the URLs shared from alias.
+func shareCreator(alias string) string {
+	if hostCfg := mustGetHostConfig(alias); hostCfg != nil {
+		return hostCfg.AccessKey
+	}
+	return ""
+}
+
+// auditShare appends a shared URL to the audit log, one JSON document per line.
+func auditShare(shareType, objectURL, shareURL, creator string, expiry time.Duration) *probe.Error {
+	entryBytes, e := gojson.Marshal(shareAuditEntry{
+		Type:    shareType,
+		URL:     objectURL,
+		Share:   shareURL,
+		Creator: creator,
+		Date:    UTCNow(),
+		Expiry:  expiry,
+	})
+	if e != nil {
+		return probe.NewError(e)
+	}
+
+	auditFile := getShareAuditFile()
+	f, e := os.OpenFile(auditFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if e != nil {
+		return probe.NewError(e).Trace(auditFile)
+	}
+	defer f.Close()
+
+	if _, e = f.Write(append(entryBytes, '\n')); e != nil {
+		return probe.NewError(e).Trace(auditFile)
+	}
+	return nil
+}
+
+// readShareAudit reads the entries of the audit log of the given types.
+func readShareAudit(r io.Reader, shareTypes ...string) ([]shareAuditEntry, *probe.Error) {
+	var entries []shareAuditEntry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var entry shareAuditEntry
+		if e := gojson.Unmarshal(scanner.Bytes(), &entry); e != nil {
+			return nil, probe.NewError(e)
+		}
+		for _, shareType := range shareTypes {
+			if entry.Type == shareType {
+				entries = append(entries, entry)
+				break
+			}
+		}
+	}
+	if e := scanner.Err(); e != nil {
+		return nil, probe.NewError(e)
+	}
+	return entries, nil
+}
+
+// shareAuditMessage is a URL of the audit log.
+type shareAuditMessage struct {
+	Status    string        `json:"status"`
+	Type      string        `json:"type"`
+	ObjectURL string        `json:"url"`
+	ShareURL  string        `json:"share"`
+	Creator   string        `json:"creator,omitempty"`
+	Date      time.Time     `json:"date"`
+	Expiry    time.Time     `json:"expiry"`
+	TimeLeft  time.Duration `json:"timeLeft"`
+	Expired   bool          `json:"expired"`
+}
+
+func (s shareAuditMessage) String() string {
+	creator := s.Creator
+	if creator == "" {
+		creator = "unknown"
+	}
+	state := console.Colorize("Expire", "expires in "+timeDurationToHumanizedDuration(s.TimeLeft).StringShort())
+	if s.Expired {
+		state = console.Colorize("Expired", "expired "+s.Expiry.Local().Format(printDate))
+	}
+	return fmt.Sprintf("[%s] %-8s %s by %s, %s", s.Date.Local().Format(printDate),
+		s.Type, console.Colorize("URL", s.ObjectURL), creator, state)
+}
+
+func (s shareAuditMessage) JSON() string {
+	s.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// shareAuditSummaryMessage reminds of the URLs which are still valid.
+// A presigned URL cannot be revoked by itself, only by removing or
+// rotating the access key which signed it.
+type shareAuditSummaryMessage struct {
+	Status     string   `json:"status"`
+	Total      int      `json:"total"`
+	Active     int      `json:"active"`
+	AccessKeys []string `json:"accessKeys,omitempty"`
+}
+
+func (s shareAuditSummaryMessage) String() string {
+	msg := fmt.Sprintf("%d shared URL(s), %d still valid.", s.Total, s.Active)
+	if len(s.AccessKeys) > 0 {
+		msg += "\nTo revoke them before they expire, remove or rotate the access key(s) " +
+			strings.Join(s.AccessKeys, ", ") + " which signed them."
+	}
+	return console.Colorize("ShareAuditSummary", msg)
+}
+
+func (s shareAuditSummaryMessage) JSON() string {
+	s.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// shareAudit returns the messages of the audit log entries at now,
+// oldest first, and the summary of the URLs still valid.
+func shareAudit(entries []shareAuditEntry, now time.Time) ([]shareAuditMessage, shareAuditSummaryMessage) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Date.Before(entries[j].Date)
+	})
+
+	summary := shareAuditSummaryMessage{Total: len(entries)}
+	accessKeys := make(map[string]bool)
+	msgs := make([]shareAuditMessage, 0, len(entries))
+	for _, entry := range entries {
+		expiry := entry.Date.Add(entry.Expiry)
+		msg := shareAuditMessage{
+			Type:      entry.Type,
+			ObjectURL: entry.URL,
+			ShareURL:  entry.Share,
+			Creator:   entry.Creator,
+			Date:      entry.Date,
+			Expiry:    expiry,
+			TimeLeft:  expiry.Sub(now),
+			Expired:   !expiry.After(now),
+		}
+		if !msg.Expired {
+			summary.Active++
+			if entry.Creator != "" && !accessKeys[entry.Creator] {
+				accessKeys[entry.Creator] = true
+				summary.AccessKeys = append(summary.AccessKeys, entry.Creator)
+			}
+		} else {
+			msg.TimeLeft = 0
+		}
+		msgs = append(msgs, msg)
+	}
+	sort.Strings(summary.AccessKeys)
+	return msgs, summary
+}
+
+// doShareAudit lists every URL of the given types shared from this
+// machine, expired or not.
+func doShareAudit(shareTypes ...string) *probe.Error {
+	auditFile := getShareAuditFile()
+	f, e := os.Open(auditFile)
+	if e != nil && !os.IsNotExist(e) {
+		return probe.NewError(e).Trace(auditFile)
+	}
+
+	var entries []shareAuditEntry
+	if e == nil {
+		defer f.Close()
+		var err *probe.Error
+		if entries, err = readShareAudit(f, shareTypes...); err != nil {
+			return err.Trace(auditFile)
+		}
+	}
+
+	msgs, summary := shareAudit(entries, UTCNow())
+	for _, msg := range msgs {
+		printMsg(msg)
+	}
+	printMsg(summary)
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShareAudit(t *testing.T) {
+	log := `{"type":"download","url":"https://play/b/old","share":"s1","creator":"key1","date":"2023-01-01T00:00:00Z","expiry":3600000000000}
+
+{"type":"upload","url":"https://play/b/up","share":"s2","creator":"key2","date":"2023-01-09T00:00:00Z","expiry":604800000000000}
+{"type":"download","url":"https://play/b/new","share":"s3","creator":"key1","date":"2023-01-10T00:00:00Z","expiry":86400000000000}
+`
+	entries, err := readShareAudit(strings.NewReader(log), "download")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 downloads, got %d", len(entries))
+	}
+
+	entries, err = readShareAudit(strings.NewReader(log), "upload", "download")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2023, 1, 10, 12, 0, 0, 0, time.UTC)
+	msgs, summary := shareAudit(entries, now)
+
+	testCases := []struct {
+		url      string
+		expired  bool
+		timeLeft time.Duration
+	}{
+		{"https://play/b/old", true, 0},
+		{"https://play/b/up", false, 6*24*time.Hour - 12*time.Hour},
+		{"https://play/b/new", false, 12 * time.Hour},
+	}
+	for i, testCase := range testCases {
+		if msgs[i].ObjectURL != testCase.url || msgs[i].Expired != testCase.expired || msgs[i].TimeLeft != testCase.timeLeft {
+			t.Errorf("Test %d: expected %s expired %v with %s left, got %s expired %v with %s left", i+1,
+				testCase.url, testCase.expired, testCase.timeLeft, msgs[i].ObjectURL, msgs[i].Expired, msgs[i].TimeLeft)
+		}
+	}
+
+	expected := shareAuditSummaryMessage{Total: 3, Active: 2, AccessKeys: []string{"key1", "key2"}}
+	if !reflect.DeepEqual(summary, expected) {
+		t.Errorf("expected summary %v, got %v", expected, summary)
+	}
+
+	if _, err = readShareAudit(strings.NewReader("not json\n"), "upload"); err == nil {
+		t.Errorf("expected an error reading a corrupted log")
+	}
+}
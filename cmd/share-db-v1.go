@@ -33,6 +33,7 @@ type shareEntryV1 struct {
 	Date        time.Time     `json:"date"`
 	Expiry      time.Duration `json:"expiry"`
 	ContentType string        `json:"contentType,omitempty"` // Only used by upload cmd.
+	Creator     string        `json:"creator,omitempty"`     // Access key signing the URL.
 }
 
 // JSON file to persist previously shared uploads.
@@ -55,7 +56,7 @@ func newShareDBV1() *shareDBV1 {
 }
 
 // Set upload info for each share.
-func (s *shareDBV1) Set(objectURL, shareURL string, expiry time.Duration, contentType string) {
+func (s *shareDBV1) Set(objectURL, shareURL string, expiry time.Duration, contentType, creator string) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
@@ -64,6 +65,7 @@ func (s *shareDBV1) Set(objectURL, shareURL string, expiry time.Duration, conten
 		Date:        UTCNow(),
 		Expiry:      expiry,
 		ContentType: contentType,
+		Creator:     creator,
 	}
 }
 
@@ -131,6 +131,8 @@ func doShareDownloadURL(ctx context.Context, targetURL, versionID string, isRecu
 		return err.Trace(shareDownloadsFile)
 	}
 
+	creator := shareCreator(targetAlias)
+
 	// Channel which will receive objects whose URLs need to be shared
 	objectsCh := make(chan *ClientContent)
 
@@ -186,7 +188,10 @@ func doShareDownloadURL(ctx context.Context, targetURL, versionID string, isRecu
 
 		// Make new entries to shareDB.
 		contentType := "" // Not useful for download shares.
-		shareDB.Set(objectURL, shareURL, expiry, contentType)
+		shareDB.Set(objectURL, shareURL, expiry, contentType, creator)
+		if err = auditShare("download", objectURL, shareURL, creator, expiry); err != nil {
+			return err.Trace(objectURL)
+		}
 		printMsg(shareMesssage{
 			ObjectURL:   objectURL,
 			ShareURL:    shareURL,
@@ -25,7 +25,16 @@ import (
 	"github.com/trinet2005/oss-mc/pkg/probe"
 )
 
-var shareListFlags = []cli.Flag{}
+var shareListFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "all",
+		Usage: "list both shared uploads and downloads",
+	},
+	cli.BoolFlag{
+		Name:  "audit",
+		Usage: "list every URL shared from this machine, including expired ones, with the access key which signed it",
+	},
+}
 
 // Share documents via URL.
 var shareList = cli.Command{
@@ -40,24 +49,45 @@ var shareList = cli.Command{
   {{.HelpName}} COMMAND - {{.Usage}}
 
 USAGE:
-  {{.HelpName}} COMMAND
+  {{.HelpName}} [FLAGS] COMMAND
+  {{.HelpName}} [FLAGS] --all
 
 COMMAND:
   upload:   list previously shared access to uploads.
   download: list previously shared access to downloads.
 
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  With '--audit', every URL shared from this machine is listed, expired or not, with its creation date,
+  its expiry and the access key which signed it, followed by a reminder of the URLs still valid. A shared
+  URL cannot be revoked by itself: only removing or rotating the access key which signed it revokes it.
+
 EXAMPLES:
   1. List previously shared downloads, that haven't expired yet.
       {{.Prompt}} {{.HelpName}} download
 
   2. List previously shared uploads, that haven't expired yet.
       {{.Prompt}} {{.HelpName}} upload
+
+  3. List previously shared uploads and downloads, that haven't expired yet.
+      {{.Prompt}} {{.HelpName}} --all
+
+  4. Review every URL ever shared from this machine, and the access keys to rotate to revoke the valid ones.
+      {{.Prompt}} {{.HelpName}} --all --audit
 `,
 }
 
 // validate command-line args.
 func checkShareListSyntax(ctx *cli.Context) {
 	args := ctx.Args()
+	if ctx.Bool("all") {
+		if args.Present() {
+			fatalIf(errInvalidArgument().Trace(args...), "You cannot specify --all with a COMMAND.")
+		}
+		return
+	}
 	if !args.Present() || (args.First() != "upload" && args.First() != "download") {
 		showCommandHelpAndExit(ctx, 1) // last argument is exit code.
 	}
@@ -113,7 +143,19 @@ func mainShareList(ctx *cli.Context) error {
 	// Initialize share config folder.
 	initShareConfig()
 
+	cmds := []string{ctx.Args().First()}
+	if ctx.Bool("all") {
+		cmds = []string{"upload", "download"}
+	}
+
+	if ctx.Bool("audit") {
+		fatalIf(doShareAudit(cmds...).Trace(cmds...), "Unable to audit previously shared URLs.")
+		return nil
+	}
+
 	// List shares.
-	fatalIf(doShareList(ctx.Args().First()).Trace(), "Unable to list previously shared URLs.")
+	for _, cmd := range cmds {
+		fatalIf(doShareList(cmd).Trace(cmd), "Unable to list previously shared URLs.")
+	}
 	return nil
 }
@@ -135,7 +135,7 @@ func makeCurlCmd(key, postURL string, isRecursive bool, uploadInfo map[string]st
 }
 
 // save shared URL to disk.
-func saveSharedURL(objectURL, shareURL string, expiry time.Duration, contentType string) *probe.Error {
+func saveSharedURL(objectURL, shareURL string, expiry time.Duration, contentType, creator string) *probe.Error {
 	// Load previously saved upload-shares.
 	shareDB := newShareDBV1()
 	if err := shareDB.Load(getShareUploadsFile()); err != nil {
@@ -143,10 +143,10 @@ func saveSharedURL(objectURL, shareURL string, expiry time.Duration, contentType
 	}
 
 	// Make new entries to uploadsDB.
-	shareDB.Set(objectURL, shareURL, expiry, contentType)
+	shareDB.Set(objectURL, shareURL, expiry, contentType, creator)
 	shareDB.Save(getShareUploadsFile())
 
-	return nil
+	return auditShare("upload", objectURL, shareURL, creator, expiry)
 }
 
 // doShareUploadURL uploads files to the target.
@@ -163,6 +163,7 @@ func doShareUploadURL(ctx context.Context, objectURL string, isRecursive bool, e
 	}
 
 	// Get the new expanded url.
+	alias, _ := url2Alias(objectURL)
 	objectURL = clnt.GetURL().String()
 
 	// Generate curl command.
@@ -179,7 +180,7 @@ func doShareUploadURL(ctx context.Context, objectURL string, isRecursive bool, e
 	})
 
 	// save shared URL to disk.
-	return saveSharedURL(objectURL, curlCmd, expiry, contentType)
+	return saveSharedURL(objectURL, curlCmd, expiry, contentType, shareCreator(alias))
 }
 
 // main for share upload command.
@@ -101,6 +101,8 @@ func shareSetColor() {
 	console.SetColor("Content-type", color.New(color.FgBlue))
 	console.SetColor("Share", color.New(color.FgGreen))
 	console.SetColor("File", color.New(color.FgRed, color.Bold))
+	console.SetColor("Expired", color.New(color.FgRed))
+	console.SetColor("ShareAuditSummary", color.New(color.FgYellow, color.Bold))
 }
 
 // Get share dir name.
@@ -0,0 +1,170 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+// errTransferStalled is returned for a transfer aborted by the
+// stall watchdog, it is retried like any transient error.
+var errTransferStalled = errors.New("transfer stalled")
+
+// Number of transfers aborted by the stall watchdog.
+var stalledTransfers int64
+
+// stallWatch forwards the transferred bytes to a progress
+// hook, recording the time of the last transferred byte.
+type stallWatch struct {
+	progress io.Reader
+	last     int64 // unix nano time of the last transferred byte
+}
+
+func (w *stallWatch) Read(b []byte) (int, error) {
+	atomic.StoreInt64(&w.last, time.Now().UnixNano())
+	return w.progress.Read(b)
+}
+
+// idle returns the time elapsed since the last transferred byte.
+func (w *stallWatch) idle() time.Duration {
+	return time.Since(time.Unix(0, atomic.LoadInt64(&w.last)))
+}
+
+// minStallTimeout is the shortest duration accepted by --stall-timeout.
+const minStallTimeout = time.Second
+
+// checkStallTimeout validates the duration set with --stall-timeout.
+func checkStallTimeout(timeout time.Duration) error {
+	if timeout != 0 && timeout < minStallTimeout {
+		return fmt.Errorf("--stall-timeout should be 0 or at least %s", minStallTimeout)
+	}
+	return nil
+}
+
+// stallTimeout returns the stall timeout of the transfer of urls, 0 for
+// a server-side copy: the server reports no progress until an object,
+// or a part of it, is copied, so a large copy would be seen as stalled.
+func stallTimeout(urls URLs, isZip bool) time.Duration {
+	if isServerSideCopy(urls, isZip) {
+		return 0
+	}
+	return globalStallTimeout
+}
+
+// withStallWatchdog runs a transfer reporting its bytes to progress,
+// aborting it with errTransferStalled if no byte is transferred for
+// timeout, 0 disabling the watchdog.
+func withStallWatchdog(ctx context.Context, url string, timeout time.Duration, progress io.Reader, fn func(ctx context.Context, progress io.Reader) *probe.Error) *probe.Error {
+	if timeout <= 0 {
+		return fn(ctx, progress)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	w := &stallWatch{progress: progress, last: time.Now().UnixNano()}
+	var stalled int32
+	doneCh := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(timeout / 4)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-doneCh:
+				return
+			case <-ticker.C:
+				if w.idle() >= timeout {
+					atomic.StoreInt32(&stalled, 1)
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	err := fn(ctx, w)
+	close(doneCh)
+	if atomic.LoadInt32(&stalled) == 0 {
+		return err
+	}
+
+	atomic.AddInt64(&stalledTransfers, 1)
+	if globalJSON {
+		printMsg(stallMessage{URL: url, Timeout: timeout.String()})
+	}
+	return probe.NewError(fmt.Errorf("%w: no data transferred for %s", errTransferStalled, timeout)).Trace(url)
+}
+
+// stallMessage is printed in JSON mode for every aborted transfer.
+type stallMessage struct {
+	Status  string `json:"status"`
+	URL     string `json:"url"`
+	Timeout string `json:"timeout"`
+}
+
+// String colorized stall message
+func (s stallMessage) String() string {
+	return console.Colorize("Stall", fmt.Sprintf("Transfer of `%s` stalled for %s, aborted.", s.URL, s.Timeout))
+}
+
+// JSON jsonified stall message
+func (s stallMessage) JSON() string {
+	s.Status = "stall"
+	stallMessageBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(stallMessageBytes)
+}
+
+// stallSummaryMessage reports the number of stalled
+// transfers at the end of a command.
+type stallSummaryMessage struct {
+	Status  string `json:"status"`
+	Stalled int64  `json:"stalledTransfers"`
+	Timeout string `json:"timeout"`
+}
+
+// String colorized stall summary message
+func (s stallSummaryMessage) String() string {
+	return console.Colorize("Stall", fmt.Sprintf("%d transfer(s) stalled for %s and were aborted.", s.Stalled, s.Timeout))
+}
+
+// JSON jsonified stall summary message
+func (s stallSummaryMessage) JSON() string {
+	s.Status = "success"
+	summaryMessageBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(summaryMessageBytes)
+}
+
+// printStallSummary prints the number of stalled transfers, if any.
+func printStallSummary() {
+	if n := atomic.LoadInt64(&stalledTransfers); n > 0 {
+		printMsg(stallSummaryMessage{Stalled: n, Timeout: globalStallTimeout.String()})
+	}
+}
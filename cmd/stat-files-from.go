@@ -0,0 +1,79 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+// statFilesFromWorkers is the number of objects of a --files-from
+// list which are stated concurrently.
+const statFilesFromWorkers = 16
+
+// statFilesFrom stats every object of the --files-from list at listPath,
+// relative to targetURL, and prints them in the order they complete.
+// Objects which cannot be stated are reported and do not stop the others.
+func statFilesFrom(ctx context.Context, targetURL, listPath string, encKeyDB map[string][]prefixSSEPair) error {
+	entryCh := make(chan fileListEntry, statFilesFromWorkers)
+
+	var failed bool
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < statFilesFromWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for entry := range entryCh {
+				url := urlJoinPath(targetURL, entry.Key)
+				clnt, stat, err := url2Stat(ctx, url, entry.VersionID, true, encKeyDB, time.Time{}, false)
+				if err != nil {
+					errorIf(err.Trace(url), "Unable to stat `"+url+"`.")
+					mu.Lock()
+					failed = true
+					mu.Unlock()
+					continue
+				}
+				msg := parseStat(stat)
+				msg.Key = entry.Key
+				addStatChecksums(ctx, clnt, url, &msg, encKeyDB)
+				printMsg(msg)
+			}
+		}()
+	}
+
+	err := readFileList(listPath, func(entry fileListEntry) *probe.Error {
+		select {
+		case entryCh <- entry:
+			return nil
+		case <-ctx.Done():
+			return probe.NewError(ctx.Err())
+		}
+	})
+	close(entryCh)
+	wg.Wait()
+
+	fatalIf(err, "Unable to read list of objects `"+listPath+"`.")
+	if failed {
+		return exitStatus(globalErrorExitStatus)
+	}
+	return nil
+}
@@ -46,6 +46,14 @@ var (
 			Name:  "recursive, r",
 			Usage: "stat all objects recursively",
 		},
+		cli.BoolFlag{
+			Name:  "config",
+			Usage: "show the full configuration of a bucket: quota, locking, replication, ILM, notification and policy",
+		},
+		cli.StringFlag{
+			Name:  "files-from",
+			Usage: "stat the keys listed in a file, one per line or JSON with version IDs, relative to TARGET ('-' reads STDIN)",
+		},
 	}
 )
 
@@ -67,7 +75,8 @@ FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}
 ENVIRONMENT VARIABLES:
-  MC_ENCRYPT_KEY:  list of comma delimited prefix=secret values
+  MC_ENCRYPT_KEY:       list of comma delimited prefix=secret values
+  MC_ENCRYPT_KEY_FILE:  file of prefix=secret lines, same as --encrypt-key-file
 
 EXAMPLES:
   1. Stat all contents of mybucket on Amazon S3 cloud storage.
@@ -91,6 +100,15 @@ EXAMPLES:
 
   7. Stat all objects versions recursively created before 1st January 2020.
      {{.Prompt}} {{.HelpName}} --versions --rewind 2020.01.01T00:00 s3/personal-docs/
+
+  8. Show the versioning, locking, quota, encryption, replication, ILM, notification, tags and policy of a bucket.
+     {{.Prompt}} {{.HelpName}} --config s3/personal-docs
+
+  9. Stat objects encrypted with distinct SSE-C keys per prefix, the keys being read from a file.
+     {{.Prompt}} {{.HelpName}} --recursive --encrypt-key-file ~/.mc/sse-keys s3/personal-docs/
+
+  10. Stat thousands of objects listed in a file concurrently, printing one JSON document per object.
+      {{.Prompt}} {{.HelpName}} --json --files-from keys.txt s3/personal-docs/
 `,
 }
 
@@ -123,6 +141,14 @@ func parseAndCheckStatSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB
 		fatalIf(errInvalidArgument().Trace(args...), "You cannot specify --version-id with either --rewind, --versions or --recursive.")
 	}
 
+	if cliCtx.String("files-from") != "" {
+		if len(args) != 1 || recursive || withVersions || versionID != "" || !rewind.IsZero() || cliCtx.Bool("config") {
+			fatalIf(errInvalidArgument().Trace(args...),
+				"You cannot specify --files-from with more than one TARGET or with any of --recursive, --versions, --version-id, --rewind and --config flags.")
+		}
+		return URLs, recursive, versionID, rewind, withVersions
+	}
+
 	for _, url := range URLs {
 		_, _, err := url2Stat(ctx, url, versionID, false, encKeyDB, rewind, false)
 		if err != nil {
@@ -164,8 +190,12 @@ func mainStat(cliCtx *cli.Context) error {
 		args = []string{"."}
 	}
 
+	if filesFrom := cliCtx.String("files-from"); filesFrom != "" {
+		return statFilesFrom(ctx, args[0], filesFrom, encKeyDB)
+	}
+
 	for _, targetURL := range args {
-		fatalIf(statURL(ctx, targetURL, versionID, rewind, withVersions, false, isRecursive, encKeyDB), "Unable to stat `"+targetURL+"`.")
+		fatalIf(statURL(ctx, targetURL, versionID, rewind, withVersions, false, isRecursive, cliCtx.Bool("config"), encKeyDB), "Unable to stat `"+targetURL+"`.")
 	}
 
 	return nil
@@ -21,6 +21,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
 	"sort"
@@ -54,6 +55,16 @@ type statMessage struct {
 	VersionID         string             `json:"versionID,omitempty"`
 	DeleteMarker      bool               `json:"deleteMarker,omitempty"`
 	Restore           *minio.RestoreInfo `json:"restore,omitempty"`
+	StorageClass      string             `json:"storageClass,omitempty"`
+	ObjectLock        *statObjectLock    `json:"objectLock,omitempty"`
+	Checksums         map[string]string  `json:"checksums,omitempty"`
+}
+
+// statObjectLock is the retention and legal hold of an object.
+type statObjectLock struct {
+	Mode        string     `json:"mode,omitempty"`
+	RetainUntil *time.Time `json:"retainUntil,omitempty"`
+	LegalHold   string     `json:"legalHold,omitempty"`
 }
 
 func (stat statMessage) String() (msg string) {
@@ -93,6 +104,32 @@ func (stat statMessage) String() (msg string) {
 		msgBuilder.WriteString(fmt.Sprintf("  %-10s: %t", "Ongoing",
 			stat.Restore.OngoingRestore) + "\n")
 	}
+	if stat.StorageClass != "" {
+		msgBuilder.WriteString(fmt.Sprintf("%-10s: %s ", "Tier", stat.StorageClass) + "\n")
+	}
+	if stat.ObjectLock != nil {
+		if stat.ObjectLock.Mode != "" {
+			retention := stat.ObjectLock.Mode
+			if stat.ObjectLock.RetainUntil != nil {
+				retention += " until " + stat.ObjectLock.RetainUntil.Local().Format(printDate)
+			}
+			msgBuilder.WriteString(fmt.Sprintf("%-10s: %s ", "Retention", retention) + "\n")
+		}
+		if stat.ObjectLock.LegalHold != "" {
+			msgBuilder.WriteString(fmt.Sprintf("%-10s: %s ", "LegalHold", stat.ObjectLock.LegalHold) + "\n")
+		}
+	}
+	if len(stat.Checksums) > 0 {
+		algorithms := make([]string, 0, len(stat.Checksums))
+		for algorithm := range stat.Checksums {
+			algorithms = append(algorithms, algorithm)
+		}
+		sort.Strings(algorithms)
+		msgBuilder.WriteString(fmt.Sprintf("%-10s:", "Checksums") + "\n")
+		for _, algorithm := range algorithms {
+			msgBuilder.WriteString(fmt.Sprintf("  %-10s: %s", algorithm, stat.Checksums[algorithm]) + "\n")
+		}
+	}
 	maxKeyMetadata := 0
 	maxKeyEncrypted := 0
 	for k := range stat.Metadata {
@@ -161,6 +198,8 @@ func parseStat(c *ClientContent) statMessage {
 	content.VersionID = c.VersionID
 	content.Key = getKey(c)
 	content.Metadata = c.Metadata
+	content.StorageClass = c.StorageClass
+	content.ObjectLock, content.Metadata = parseStatObjectLock(c.Metadata)
 	content.ETag = strings.TrimPrefix(c.ETag, "\"")
 	content.ETag = strings.TrimSuffix(content.ETag, "\"")
 	if !c.Expires.IsZero() {
@@ -175,6 +214,48 @@ func parseStat(c *ClientContent) statMessage {
 	return content
 }
 
+// parseStatObjectLock returns the object lock of an object from its
+// metadata, and the metadata without the object lock headers.
+func parseStatObjectLock(metadata map[string]string) (*statObjectLock, map[string]string) {
+	if metadata == nil {
+		return nil, nil
+	}
+	var lock statObjectLock
+	others := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		switch http.CanonicalHeaderKey(k) {
+		case AmzObjectLockMode:
+			lock.Mode = v
+		case AmzObjectLockRetainUntilDate:
+			if t, e := time.Parse(time.RFC3339, v); e == nil {
+				lock.RetainUntil = &t
+			}
+		case AmzObjectLockLegalHold:
+			lock.LegalHold = v
+		default:
+			others[k] = v
+		}
+	}
+	if lock == (statObjectLock{}) {
+		return nil, others
+	}
+	return &lock, others
+}
+
+// addStatChecksums adds the checksums of an object stored on S3 to its
+// stat message. They are only returned by GetObjectAttributes, which not
+// all servers implement, so the object is shown without them on error.
+func addStatChecksums(ctx context.Context, clnt Client, urlStr string, msg *statMessage, encKeyDB map[string][]prefixSSEPair) {
+	s3Clnt, ok := clnt.(*S3Client)
+	if !ok || msg.Type == "folder" || msg.DeleteMarker {
+		return
+	}
+	alias, _ := url2Alias(urlStr)
+	if checksums, err := s3Clnt.GetObjectChecksums(ctx, msg.VersionID, getSSE(urlStr, encKeyDB[alias])); err == nil {
+		msg.Checksums = checksums
+	}
+}
+
 // Return standardized URL to be used to compare later.
 func getStandardizedURL(targetURL string) string {
 	return filepath.FromSlash(targetURL)
@@ -183,7 +264,7 @@ func getStandardizedURL(targetURL string) string {
 // statURL - uses combination of GET listing and HEAD to fetch information of one or more objects
 // HEAD can fail with 400 with an SSE-C encrypted object but we still return information gathered
 // from GET listing.
-func statURL(ctx context.Context, targetURL, versionID string, timeRef time.Time, includeOlderVersions, isIncomplete, isRecursive bool, encKeyDB map[string][]prefixSSEPair) *probe.Error {
+func statURL(ctx context.Context, targetURL, versionID string, timeRef time.Time, includeOlderVersions, isIncomplete, isRecursive, showConfig bool, encKeyDB map[string][]prefixSSEPair) *probe.Error {
 	clnt, err := newClient(targetURL)
 	if err != nil {
 		return err
@@ -215,6 +296,7 @@ func statURL(ctx context.Context, targetURL, versionID string, timeRef time.Time
 			}
 
 			var bu madmin.BucketUsageInfo
+			var quota *madmin.BucketQuota
 
 			adminClient, _ := newAdminClient(targetURL)
 			if adminClient != nil {
@@ -223,6 +305,11 @@ func statURL(ctx context.Context, targetURL, versionID string, timeRef time.Time
 				if e == nil {
 					bu = duinfo.BucketsUsage[bstat.Key]
 				}
+				if showConfig {
+					if qCfg, e := adminClient.GetBucketQuota(ctx, bstat.Key); e == nil && qCfg.Quota > 0 {
+						quota = &qCfg
+					}
+				}
 			}
 
 			if prefixPath != "/" {
@@ -233,6 +320,8 @@ func statURL(ctx context.Context, targetURL, versionID string, timeRef time.Time
 				Status:     "success",
 				BucketInfo: bstat,
 				Usage:      bu,
+				Quota:      quota,
+				showConfig: showConfig,
 			})
 
 			return nil
@@ -289,7 +378,7 @@ func statURL(ctx context.Context, targetURL, versionID string, timeRef time.Time
 				continue
 			}
 		}
-		_, stat, err := url2Stat(ctx, url, content.VersionID, true, encKeyDB, timeRef, false)
+		statClnt, stat, err := url2Stat(ctx, url, content.VersionID, true, encKeyDB, timeRef, false)
 		if err != nil {
 			continue
 		}
@@ -301,7 +390,9 @@ func statURL(ctx context.Context, targetURL, versionID string, timeRef time.Time
 		contentURL = strings.TrimPrefix(contentURL, prefixPath)
 		stat.URL.Path = contentURL
 
-		printMsg(parseStat(stat))
+		msg := parseStat(stat)
+		addStatChecksums(ctx, statClnt, url, &msg, encKeyDB)
+		printMsg(msg)
 	}
 
 	return probe.NewError(e)
@@ -369,6 +460,9 @@ type bucketInfoMessage struct {
 	Status string `json:"status"`
 	BucketInfo
 	Usage madmin.BucketUsageInfo
+	Quota *madmin.BucketQuota `json:"quota,omitempty"`
+
+	showConfig bool
 }
 
 func (v bucketInfoMessage) JSON() string {
@@ -449,6 +543,12 @@ func (v bucketInfoMessage) String() string {
 		fmt.Fprintf(&b, "\n")
 	}
 
+	if !v.Prefix && v.showConfig {
+		fmt.Fprint(&b, console.Colorize("Title", "Configuration:\n"))
+		fmt.Fprint(&b, prettyPrintBucketConfig(v.BucketInfo, v.Quota))
+		fmt.Fprintf(&b, "\n")
+	}
+
 	fmt.Fprint(&b, console.Colorize("Title", "Usage:\n"))
 
 	fmt.Fprintf(&b, "%16s: %s\n", "Total size", console.Colorize("Count", humanize.IBytes(v.Usage.Size)))
@@ -547,3 +647,110 @@ func prettyPrintBucketMetadata(info BucketInfo) string {
 
 	return b.String()
 }
+
+// Pretty print the details of the bucket configuration, used by 'stat --config'
+func prettyPrintBucketConfig(info BucketInfo, quota *madmin.BucketQuota) string {
+	var b strings.Builder
+	placeHolder := ""
+
+	fmt.Fprintf(&b, "%2s%s", placeHolder, "Quota: ")
+	if quota != nil {
+		fmt.Fprint(&b, console.Colorize("Value", fmt.Sprintf("%s (%s)", humanize.IBytes(quota.Quota), quota.Type)))
+	} else {
+		fmt.Fprint(&b, console.Colorize("Unset", "Unset"))
+	}
+	fmt.Fprintln(&b)
+
+	fmt.Fprintf(&b, "%2s%s", placeHolder, "Object Lock: ")
+	switch {
+	case info.Locking.Enabled == "" || info.Locking.Enabled == "Disabled":
+		fmt.Fprint(&b, console.Colorize("Unset", "Disabled"))
+	case info.Locking.Mode != "":
+		fmt.Fprint(&b, console.Colorize("Set", "Enabled"))
+		fmt.Fprint(&b, console.Colorize("Value", fmt.Sprintf(" (default %s for %s)", info.Locking.Mode, info.Locking.Validity)))
+	default:
+		fmt.Fprint(&b, console.Colorize("Set", "Enabled"))
+	}
+	fmt.Fprintln(&b)
+
+	fmt.Fprintf(&b, "%2s%s", placeHolder, "Replication Rules: ")
+	fmt.Fprint(&b, console.Colorize("Count", len(info.Replication.Config.Rules)))
+	fmt.Fprintln(&b)
+	for _, rule := range info.Replication.Config.Rules {
+		fmt.Fprintf(&b, "%4s%s %s", placeHolder, console.Colorize("Key", rule.ID+":"), console.Colorize("Value", rule.Status))
+		fmt.Fprintf(&b, " priority=%d destination=%s", rule.Priority, rule.Destination.Bucket)
+		if rule.Filter.And.Prefix != "" {
+			fmt.Fprintf(&b, " prefix=%s", rule.Filter.And.Prefix)
+		} else if rule.Filter.Prefix != "" {
+			fmt.Fprintf(&b, " prefix=%s", rule.Filter.Prefix)
+		}
+		fmt.Fprintln(&b)
+	}
+
+	var ilmRules []lifecycle.Rule
+	if info.ILM.Config != nil {
+		ilmRules = info.ILM.Config.Rules
+	}
+	fmt.Fprintf(&b, "%2s%s", placeHolder, "ILM Rules: ")
+	fmt.Fprint(&b, console.Colorize("Count", len(ilmRules)))
+	fmt.Fprintln(&b)
+	for _, rule := range ilmRules {
+		fmt.Fprintf(&b, "%4s%s %s", placeHolder, console.Colorize("Key", rule.ID+":"), console.Colorize("Value", rule.Status))
+		if prefix := rule.RuleFilter.Prefix; prefix != "" {
+			fmt.Fprintf(&b, " prefix=%s", prefix)
+		} else if rule.Prefix != "" {
+			fmt.Fprintf(&b, " prefix=%s", rule.Prefix)
+		}
+		if rule.Expiration.Days > 0 {
+			fmt.Fprintf(&b, " expire=%dd", rule.Expiration.Days)
+		}
+		if rule.Transition.StorageClass != "" {
+			fmt.Fprintf(&b, " transition=%dd:%s", rule.Transition.Days, rule.Transition.StorageClass)
+		}
+		fmt.Fprintln(&b)
+	}
+
+	var targets [][2]string
+	for _, config := range info.Notification.Config.QueueConfigs {
+		targets = append(targets, [2]string{config.Queue, notificationEvents(config.Events)})
+	}
+	for _, config := range info.Notification.Config.TopicConfigs {
+		targets = append(targets, [2]string{config.Topic, notificationEvents(config.Events)})
+	}
+	for _, config := range info.Notification.Config.LambdaConfigs {
+		targets = append(targets, [2]string{config.Lambda, notificationEvents(config.Events)})
+	}
+	fmt.Fprintf(&b, "%2s%s", placeHolder, "Notification Targets: ")
+	fmt.Fprint(&b, console.Colorize("Count", len(targets)))
+	fmt.Fprintln(&b)
+	for _, target := range targets {
+		fmt.Fprintf(&b, "%4s%s %s\n", placeHolder, console.Colorize("Key", target[0]+":"), console.Colorize("Value", target[1]))
+	}
+
+	fmt.Fprintf(&b, "%2s%s", placeHolder, "Policy: ")
+	switch {
+	case info.Policy.Type == "" || info.Policy.Type == "none":
+		fmt.Fprint(&b, console.Colorize("Unset", "none"))
+	case info.Policy.Text != "":
+		var policy struct {
+			Statement []interface{} `json:"Statement"`
+		}
+		json.Unmarshal([]byte(info.Policy.Text), &policy)
+		fmt.Fprint(&b, console.Colorize("Set", info.Policy.Type))
+		fmt.Fprint(&b, console.Colorize("Value", fmt.Sprintf(" (%d statement(s))", len(policy.Statement))))
+	default:
+		fmt.Fprint(&b, console.Colorize("Set", info.Policy.Type))
+	}
+	fmt.Fprintln(&b)
+
+	return b.String()
+}
+
+// notificationEvents returns a comma separated list of event names.
+func notificationEvents(events []notification.EventType) string {
+	names := make([]string, 0, len(events))
+	for _, event := range events {
+		names = append(names, string(event))
+	}
+	return strings.Join(names, ",")
+}
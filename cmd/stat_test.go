@@ -68,3 +68,34 @@ func TestParseStat(t *testing.T) {
 		})
 	}
 }
+
+func TestParseStatObjectLock(t *testing.T) {
+	retainUntil := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	testCases := []struct {
+		metadata         map[string]string
+		expectedLock     *statObjectLock
+		expectedMetadata map[string]string
+	}{
+		{nil, nil, nil},
+		{map[string]string{"Content-Type": "text/plain"}, nil, map[string]string{"Content-Type": "text/plain"}},
+		{
+			map[string]string{
+				"Content-Type":                        "text/plain",
+				"X-Amz-Object-Lock-Mode":              "COMPLIANCE",
+				"X-Amz-Object-Lock-Retain-Until-Date": "2030-01-02T03:04:05Z",
+				"x-amz-object-lock-legal-hold":        "ON",
+			},
+			&statObjectLock{Mode: "COMPLIANCE", RetainUntil: &retainUntil, LegalHold: "ON"},
+			map[string]string{"Content-Type": "text/plain"},
+		},
+	}
+	for i, testCase := range testCases {
+		lock, metadata := parseStatObjectLock(testCase.metadata)
+		if !reflect.DeepEqual(lock, testCase.expectedLock) {
+			t.Errorf("Test %d: expected lock %v, got %v", i+1, testCase.expectedLock, lock)
+		}
+		if !reflect.DeepEqual(metadata, testCase.expectedMetadata) {
+			t.Errorf("Test %d: expected metadata %v, got %v", i+1, testCase.expectedMetadata, metadata)
+		}
+	}
+}
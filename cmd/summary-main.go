@@ -0,0 +1,249 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+// summaryTopBuckets is the number of largest buckets listed.
+const summaryTopBuckets = 10
+
+var summaryFlags = []cli.Flag{
+	parallelBucketsFlag,
+}
+
+var summaryCmd = cli.Command{
+	Name:         "summary",
+	Usage:        "show an overview of the buckets of an alias",
+	Action:       mainSummary,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(summaryFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] ALIAS
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Summary counts the buckets of ALIAS, how many are versioned and locked, lists the buckets with
+  anonymous access and without lifecycle rules, and the largest buckets. Objects and sizes come from
+  the data usage of the server, a MinIO server extension, which is updated periodically.
+
+EXAMPLES:
+  1. Show an overview of the buckets of 'myminio'.
+     {{.Prompt}} {{.HelpName}} myminio
+
+  2. Show an overview of the buckets of 'myminio' as JSON.
+     {{.Prompt}} {{.HelpName}} --json myminio
+`,
+}
+
+// summaryBucket holds the facts of one bucket summarized.
+type summaryBucket struct {
+	Name      string `json:"name"`
+	Objects   uint64 `json:"objects"`
+	Size      uint64 `json:"size"`
+	Versioned bool   `json:"versioned"`
+	Locked    bool   `json:"locked"`
+	Access    string `json:"access"`
+	ILM       bool   `json:"ilm"`
+}
+
+// accountSummaryMessage is the overview of the buckets of an alias.
+type accountSummaryMessage struct {
+	Status      string          `json:"status"`
+	Alias       string          `json:"alias"`
+	Buckets     int             `json:"buckets"`
+	Objects     uint64          `json:"objects"`
+	Size        uint64          `json:"size"`
+	Usage       bool            `json:"usageAvailable"`
+	Versioned   int             `json:"versioned"`
+	Unversioned int             `json:"unversioned"`
+	Locked      int             `json:"locked"`
+	Public      []string        `json:"public"`
+	WithoutILM  []string        `json:"withoutILM"`
+	Largest     []summaryBucket `json:"largest,omitempty"`
+}
+
+func (s accountSummaryMessage) String() string {
+	var b strings.Builder
+	field := func(name, value string) {
+		fmt.Fprintf(&b, "%s %s\n", console.Colorize("SummaryField", fmt.Sprintf("%-12s", name+":")), value)
+	}
+	list := func(names []string) string {
+		if len(names) == 0 {
+			return "none"
+		}
+		return fmt.Sprintf("%d (%s)", len(names), strings.Join(names, ", "))
+	}
+
+	field("Alias", s.Alias)
+	field("Buckets", fmt.Sprintf("%d, %d versioned, %d unversioned, %d locked", s.Buckets, s.Versioned, s.Unversioned, s.Locked))
+	if s.Usage {
+		field("Objects", humanize.Comma(int64(s.Objects)))
+		field("Size", humanize.IBytes(s.Size))
+	} else {
+		field("Objects", "unavailable, data usage requires a MinIO server")
+	}
+	field("Public", console.Colorize("SummaryWarning", list(s.Public)))
+	field("Without ILM", list(s.WithoutILM))
+	if len(s.Largest) > 0 {
+		b.WriteString(console.Colorize("SummaryField", "Largest:") + "\n")
+		for _, bucket := range s.Largest {
+			fmt.Fprintf(&b, "  %10s %15s objects  %s\n", humanize.IBytes(bucket.Size), humanize.Comma(int64(bucket.Objects)), bucket.Name)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (s accountSummaryMessage) JSON() string {
+	s.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// summarize returns the overview of buckets, with the top largest ones
+// when their usage is known.
+func summarize(alias string, buckets []summaryBucket, usage bool, top int) accountSummaryMessage {
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].Name < buckets[j].Name
+	})
+
+	s := accountSummaryMessage{
+		Alias:      alias,
+		Buckets:    len(buckets),
+		Usage:      usage,
+		Public:     []string{},
+		WithoutILM: []string{},
+	}
+	for _, bucket := range buckets {
+		s.Objects += bucket.Objects
+		s.Size += bucket.Size
+		if bucket.Versioned {
+			s.Versioned++
+		} else {
+			s.Unversioned++
+		}
+		if bucket.Locked {
+			s.Locked++
+		}
+		if bucket.Access != "" && bucket.Access != "none" {
+			s.Public = append(s.Public, bucket.Name)
+		}
+		if !bucket.ILM {
+			s.WithoutILM = append(s.WithoutILM, bucket.Name)
+		}
+	}
+
+	if usage {
+		largest := append([]summaryBucket(nil), buckets...)
+		sort.SliceStable(largest, func(i, j int) bool {
+			return largest[i].Size > largest[j].Size
+		})
+		if len(largest) > top {
+			largest = largest[:top]
+		}
+		s.Largest = largest
+	}
+	return s
+}
+
+// mainSummary is the handle for "mc summary" command.
+func mainSummary(cliCtx *cli.Context) error {
+	ctx, cancelSummary := context.WithCancel(globalContext)
+	defer cancelSummary()
+
+	if len(cliCtx.Args()) != 1 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+
+	console.SetColor("SummaryField", color.New(color.Bold))
+	console.SetColor("SummaryWarning", color.New(color.FgYellow))
+
+	aliasedURL := cliCtx.Args().Get(0)
+	clnt, err := newClient(aliasedURL)
+	fatalIf(err.Trace(aliasedURL), "Unable to initialize target `"+aliasedURL+"`.")
+	if !isAliasRoot(clnt) {
+		fatalIf(errInvalidArgument().Trace(aliasedURL), "Target `"+aliasedURL+"` should be an alias.")
+	}
+	alias, _ := url2Alias(aliasedURL)
+
+	// Objects and sizes are only known from the data usage of MinIO.
+	usage := false
+	var usageInfo map[string]summaryBucket
+	if admClnt, err := newAdminClient(aliasedURL); err == nil {
+		if duinfo, e := admClnt.DataUsageInfo(ctx); e == nil {
+			usage = true
+			usageInfo = make(map[string]summaryBucket, len(duinfo.BucketsUsage))
+			for name, bu := range duinfo.BucketsUsage {
+				usageInfo[name] = summaryBucket{Objects: bu.ObjectsCount, Size: bu.Size}
+			}
+		}
+	}
+
+	var mu sync.Mutex
+	var buckets []summaryBucket
+	var summaryErr error
+	err = forEachBucket(ctx, clnt, aliasedURL, cliCtx.Int("parallel-buckets"), func(bucketURL string) {
+		bucketClnt, err := newClient(bucketURL)
+		if err == nil {
+			var info BucketInfo
+			if info, err = bucketClnt.GetBucketInfo(ctx); err == nil {
+				bucket := summaryBucket{
+					Name:      info.Key,
+					Objects:   usageInfo[info.Key].Objects,
+					Size:      usageInfo[info.Key].Size,
+					Versioned: info.Versioning.Status == "Enabled",
+					Locked:    info.Locking.Enabled == "Enabled",
+					Access:    info.Policy.Type,
+					ILM:       info.ILM.Config != nil && len(info.ILM.Config.Rules) > 0,
+				}
+				mu.Lock()
+				buckets = append(buckets, bucket)
+				mu.Unlock()
+				return
+			}
+		}
+		errorIf(err.Trace(bucketURL), "Unable to get the configuration of `"+bucketURL+"`.")
+		mu.Lock()
+		summaryErr = exitStatus(globalErrorExitStatus)
+		mu.Unlock()
+	})
+	fatalIf(err, "Unable to list the buckets of `"+aliasedURL+"`.")
+
+	printMsg(summarize(alias, buckets, usage, summaryTopBuckets))
+	return summaryErr
+}
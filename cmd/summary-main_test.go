@@ -0,0 +1,64 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	buckets := []summaryBucket{
+		{Name: "logs", Objects: 10, Size: 300, Access: "none", ILM: true},
+		{Name: "archive", Objects: 5, Size: 900, Versioned: true, Locked: true, Access: "none"},
+		{Name: "web", Objects: 2, Size: 100, Access: "readonly"},
+	}
+
+	testCases := []struct {
+		usage   bool
+		top     int
+		largest []string
+	}{
+		{true, 2, []string{"archive", "logs"}},
+		{true, 10, []string{"archive", "logs", "web"}},
+		{false, 10, nil},
+	}
+
+	for i, testCase := range testCases {
+		s := summarize("myminio", append([]summaryBucket(nil), buckets...), testCase.usage, testCase.top)
+		if s.Buckets != 3 || s.Objects != 17 || s.Size != 1300 {
+			t.Fatalf("Test %d: expected 3 buckets, 17 objects, 1300 bytes, got %d, %d, %d", i+1, s.Buckets, s.Objects, s.Size)
+		}
+		if s.Versioned != 1 || s.Unversioned != 2 || s.Locked != 1 {
+			t.Fatalf("Test %d: expected 1 versioned, 2 unversioned, 1 locked, got %d, %d, %d", i+1, s.Versioned, s.Unversioned, s.Locked)
+		}
+		if !reflect.DeepEqual(s.Public, []string{"web"}) {
+			t.Fatalf("Test %d: expected public [web], got %v", i+1, s.Public)
+		}
+		if !reflect.DeepEqual(s.WithoutILM, []string{"archive", "web"}) {
+			t.Fatalf("Test %d: expected without ILM [archive web], got %v", i+1, s.WithoutILM)
+		}
+		var largest []string
+		for _, bucket := range s.Largest {
+			largest = append(largest, bucket.Name)
+		}
+		if !reflect.DeepEqual(largest, testCase.largest) {
+			t.Fatalf("Test %d: expected largest %v, got %v", i+1, testCase.largest, largest)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	gojson "encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/fatih/color"
+	"github.com/klauspost/compress/gzip"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+var supportDiffFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "all",
+		Usage: "also compare usage and runtime statistics, such as uptime and free space",
+	},
+}
+
+var supportDiffCmd = cli.Command{
+	Name:            "diff",
+	Usage:           "list what changed between two diagnostics reports",
+	Action:          mainSupportDiff,
+	OnUsageError:    onUsageError,
+	Before:          setGlobalsFromContext,
+	Flags:           append(supportDiffFlags, globalFlags...),
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] REPORT1 REPORT2
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Compare two reports saved by 'mc support diag --airgap', such as the configuration, the server
+  versions, the drive inventory and the system settings. Servers and drives are matched by their
+  endpoint. Usage and runtime statistics, which change all the time, are ignored unless '--all' is set.
+
+EXAMPLES:
+  1. List what changed on cluster 'myminio' since the report taken when it last worked.
+     {{.Prompt}} {{.HelpName}} myminio-health_20230101120000.json.gz myminio-health_20230301120000.json.gz
+
+  2. List every change between two reports, including usage statistics, as JSON.
+     {{.Prompt}} {{.HelpName}} --all --json myminio-health_20230101120000.json.gz myminio-health_20230301120000.json.gz
+`,
+}
+
+// supportDiffVolatileKeys are the fields of a report which change
+// between any two reports, they are only compared with '--all'.
+var supportDiffVolatileKeys = map[string]bool{
+	"timestamp":   true,
+	"uptime":      true,
+	"usage":       true,
+	"objects":     true,
+	"versions":    true,
+	"buckets":     true,
+	"usedspace":   true,
+	"availspace":  true,
+	"usedinodes":  true,
+	"freeinodes":  true,
+	"utilization": true,
+	"mem_stats":   true,
+	"gc_stats":    true,
+	"procinfo":    true,
+	"mem":         true,
+	"cpu_load":    true,
+	"metrics":     true,
+}
+
+// supportDiffIDKeys identify the elements of a list in a report, such as
+// servers and drives, so that they are matched regardless of their order.
+var supportDiffIDKeys = []string{"endpoint", "addr", "path", "uuid", "name"}
+
+// Kinds of changes between two reports.
+const (
+	supportDiffAdded   = "added"
+	supportDiffRemoved = "removed"
+	supportDiffChanged = "changed"
+)
+
+// supportDiffMessage is a field which differs between two reports.
+type supportDiffMessage struct {
+	Status string `json:"status"`
+	Path   string `json:"path"`
+	Change string `json:"change"`
+	Old    string `json:"old,omitempty"`
+	New    string `json:"new,omitempty"`
+}
+
+func (s supportDiffMessage) String() string {
+	switch s.Change {
+	case supportDiffAdded:
+		return console.Colorize("SupportDiffAdded", "+ "+s.Path+": "+s.New)
+	case supportDiffRemoved:
+		return console.Colorize("SupportDiffRemoved", "- "+s.Path+": "+s.Old)
+	}
+	return console.Colorize("SupportDiffChanged", "~ "+s.Path+": "+s.Old+" -> "+s.New)
+}
+
+func (s supportDiffMessage) JSON() string {
+	s.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// supportDiffSummaryMessage is printed after the changes.
+type supportDiffSummaryMessage struct {
+	Status  string `json:"status"`
+	Changes int    `json:"changes"`
+}
+
+func (s supportDiffSummaryMessage) String() string {
+	if s.Changes == 0 {
+		return console.Colorize(supportSuccessMsgTag, "No changes found.")
+	}
+	return console.Colorize(supportSuccessMsgTag, fmt.Sprintf("%d change(s) found.", s.Changes))
+}
+
+func (s supportDiffSummaryMessage) JSON() string {
+	s.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// readDiagReport reads a report saved by 'mc support diag', gzipped or
+// not, and returns its fields flattened by path. The version of the
+// report format, written before the report itself, is kept as 'format'.
+func readDiagReport(r io.Reader, all bool) (map[string]string, error) {
+	br := bufio.NewReader(r)
+	if magic, e := br.Peek(2); e == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gzReader, e := gzip.NewReader(br)
+		if e != nil {
+			return nil, e
+		}
+		defer gzReader.Close()
+		r = gzReader
+	} else {
+		r = br
+	}
+
+	decoder := gojson.NewDecoder(r)
+	decoder.UseNumber()
+
+	fields := make(map[string]string)
+	var report interface{}
+	if e := decoder.Decode(&report); e != nil {
+		return nil, e
+	}
+	if header, ok := report.(map[string]interface{}); ok && len(header) == 1 && header["version"] != nil {
+		fields["format"] = fmt.Sprint(header["version"])
+		report = nil
+		if e := decoder.Decode(&report); e != nil {
+			return nil, e
+		}
+	}
+	flattenDiagReport("", report, all, fields)
+	return fields, nil
+}
+
+// flattenDiagReport adds the leaves of v to fields, keyed by their path.
+func flattenDiagReport(path string, v interface{}, all bool, fields map[string]string) {
+	join := func(key string) string {
+		if path == "" {
+			return key
+		}
+		return path + "." + key
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			fields[path] = "{}"
+		}
+		for key, value := range v {
+			if !all && supportDiffVolatileKeys[key] {
+				continue
+			}
+			flattenDiagReport(join(key), value, all, fields)
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			fields[path] = "[]"
+		}
+		for i, value := range v {
+			flattenDiagReport(path+"["+diagElementID(value, i)+"]", value, all, fields)
+		}
+	case string:
+		fields[path] = v
+	case nil:
+		fields[path] = "null"
+	default:
+		fields[path] = fmt.Sprint(v)
+	}
+}
+
+// diagElementID returns the identity of a list element, its position
+// when it has none.
+func diagElementID(v interface{}, i int) string {
+	if m, ok := v.(map[string]interface{}); ok {
+		for _, key := range supportDiffIDKeys {
+			if id, ok := m[key].(string); ok && id != "" {
+				return id
+			}
+		}
+	}
+	return strconv.Itoa(i)
+}
+
+// diffDiagReports returns the changes from the first report to the
+// second one, sorted by path.
+func diffDiagReports(first, second map[string]string) []supportDiffMessage {
+	var changes []supportDiffMessage
+	for path, old := range first {
+		value, ok := second[path]
+		switch {
+		case !ok:
+			changes = append(changes, supportDiffMessage{Path: path, Change: supportDiffRemoved, Old: old})
+		case value != old:
+			changes = append(changes, supportDiffMessage{Path: path, Change: supportDiffChanged, Old: old, New: value})
+		}
+	}
+	for path, value := range second {
+		if _, ok := first[path]; !ok {
+			changes = append(changes, supportDiffMessage{Path: path, Change: supportDiffAdded, New: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+func readDiagReportFile(filename string, all bool) map[string]string {
+	f, e := os.Open(filename)
+	fatalIf(probe.NewError(e), "Unable to open the diagnostics report `"+filename+"`.")
+	defer f.Close()
+
+	fields, e := readDiagReport(f, all)
+	fatalIf(probe.NewError(e), "Unable to parse the diagnostics report `"+filename+"`.")
+	return fields
+}
+
+func mainSupportDiff(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+
+	console.SetColor("SupportDiffAdded", color.New(color.FgGreen))
+	console.SetColor("SupportDiffRemoved", color.New(color.FgRed))
+	console.SetColor("SupportDiffChanged", color.New(color.FgYellow))
+	setSuccessMessageColor()
+
+	all := ctx.Bool("all")
+	first := readDiagReportFile(ctx.Args().Get(0), all)
+	second := readDiagReportFile(ctx.Args().Get(1), all)
+
+	changes := diffDiagReports(first, second)
+	for _, change := range changes {
+		printMsg(change)
+	}
+	printMsg(supportDiffSummaryMessage{Changes: len(changes)})
+	return nil
+}
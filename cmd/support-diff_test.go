@@ -0,0 +1,77 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/gzip"
+)
+
+func TestDiffDiagReports(t *testing.T) {
+	first := `{"version":"3"}
+{"timestamp":"2023-01-01T00:00:00Z","minio":{"info":{"servers":[
+{"endpoint":"node1:9000","version":"2023-01-01","uptime":10,"drives":[{"endpoint":"/d1","state":"ok"}]},
+{"endpoint":"node2:9000","version":"2023-01-01","uptime":10,"drives":[{"endpoint":"/d1","state":"ok"}]}]},
+"config":{"api":"requests_max=100"}}}`
+	// Servers are listed in another order, node2 lost a drive and was upgraded.
+	second := `{"version":"3"}
+{"timestamp":"2023-03-01T00:00:00Z","minio":{"info":{"servers":[
+{"endpoint":"node2:9000","version":"2023-03-01","uptime":20,"drives":[]},
+{"endpoint":"node1:9000","version":"2023-01-01","uptime":20,"drives":[{"endpoint":"/d1","state":"ok"}]}]},
+"config":{"api":"requests_max=100","scanner":"speed=slow"}}}`
+
+	var gzipped bytes.Buffer
+	gzWriter := gzip.NewWriter(&gzipped)
+	gzWriter.Write([]byte(second))
+	gzWriter.Close()
+
+	firstFields, err := readDiagReport(strings.NewReader(first), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondFields, err := readDiagReport(&gzipped, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []supportDiffMessage{
+		{Path: "minio.config.scanner", Change: supportDiffAdded, New: "speed=slow"},
+		{Path: "minio.info.servers[node2:9000].drives", Change: supportDiffAdded, New: "[]"},
+		{Path: "minio.info.servers[node2:9000].drives[/d1].endpoint", Change: supportDiffRemoved, Old: "/d1"},
+		{Path: "minio.info.servers[node2:9000].drives[/d1].state", Change: supportDiffRemoved, Old: "ok"},
+		{Path: "minio.info.servers[node2:9000].version", Change: supportDiffChanged, Old: "2023-01-01", New: "2023-03-01"},
+	}
+	if got := diffDiagReports(firstFields, secondFields); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	allFields, err := readDiagReport(strings.NewReader(first), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if allFields["minio.info.servers[node1:9000].uptime"] != "10" {
+		t.Errorf("expected uptime to be compared with all, got %v", allFields)
+	}
+	if allFields["format"] != "3" {
+		t.Errorf("expected format 3, got %s", allFields["format"])
+	}
+}
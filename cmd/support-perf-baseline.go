@@ -0,0 +1,331 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+const (
+	// perfBaselineDir is the folder of the configuration folder
+	// where the named baselines of 'mc support perf' are saved.
+	perfBaselineDir = "perf-baselines"
+
+	// perfRegressionExitStatus is the exit status of a run
+	// regressing from its baseline.
+	perfRegressionExitStatus = 2
+)
+
+var perfBaselineFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "save-baseline",
+		Usage: "save the results as the named baseline instead of uploading them to SUBNET",
+	},
+	cli.StringFlag{
+		Name:  "baseline",
+		Usage: "compare the results to the named baseline, exiting with status 2 on a regression",
+	},
+	cli.Float64Flag{
+		Name:  "threshold",
+		Value: 10,
+		Usage: "drop in percent from the baseline reported as a regression",
+	},
+}
+
+var perfBaselineNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9._-]*$`)
+
+// perfBaseline is a saved run of the perf tests.
+type perfBaseline struct {
+	Name    string         `json:"name"`
+	Alias   string         `json:"alias"`
+	Time    time.Time      `json:"time"`
+	Results PerfTestOutput `json:"results"`
+}
+
+// perfMetric is a figure of the perf tests compared to baselines,
+// higher values being better.
+type perfMetric struct {
+	Name  string
+	Value float64
+	Bytes bool // a throughput, in bytes per second
+}
+
+// perfMetrics returns the figures of the results compared to baselines.
+func perfMetrics(out PerfTestOutput) []perfMetric {
+	var metrics []perfMetric
+	if r := out.ObjectResults; r != nil {
+		metrics = append(metrics,
+			perfMetric{"object PUT throughput", float64(r.PUTResults.Perf.Throughput), true},
+			perfMetric{"object PUT objects/s", float64(r.PUTResults.Perf.ObjectsPerSec), false},
+			perfMetric{"object GET throughput", float64(r.GETResults.Perf.Throughput), true},
+			perfMetric{"object GET objects/s", float64(r.GETResults.Perf.ObjectsPerSec), false},
+		)
+	}
+	if r := out.NetResults; r != nil {
+		var tx, rx uint64
+		for _, server := range r.Results {
+			tx += server.Perf.TX
+			rx += server.Perf.RX
+		}
+		metrics = append(metrics,
+			perfMetric{"network TX", float64(tx), true},
+			perfMetric{"network RX", float64(rx), true},
+		)
+	}
+	if r := out.DriveResults; r != nil {
+		var read, write uint64
+		for _, server := range r.Results {
+			for _, drive := range server.Perf {
+				read += drive.ReadThroughput
+				write += drive.WriteThroughput
+			}
+		}
+		metrics = append(metrics,
+			perfMetric{"drive read throughput", float64(read), true},
+			perfMetric{"drive write throughput", float64(write), true},
+		)
+	}
+	if r := out.ClientResults; r != nil && r.TimeSpent > 0 {
+		metrics = append(metrics,
+			perfMetric{"client throughput", float64(r.BytesSent) / time.Duration(r.TimeSpent).Seconds(), true},
+		)
+	}
+	return metrics
+}
+
+// perfComparison compares a figure to its baseline.
+type perfComparison struct {
+	Metric    string  `json:"metric"`
+	Baseline  float64 `json:"baseline"`
+	Current   float64 `json:"current"`
+	Change    float64 `json:"change"` // in percent
+	Regressed bool    `json:"regressed"`
+
+	bytes bool
+}
+
+// comparePerfResults compares the figures found in both the baseline
+// and the current results. A figure regresses when it drops by more
+// than threshold percent.
+func comparePerfResults(baseline, current PerfTestOutput, threshold float64) []perfComparison {
+	baseMetrics := make(map[string]float64)
+	for _, metric := range perfMetrics(baseline) {
+		baseMetrics[metric.Name] = metric.Value
+	}
+	var comparisons []perfComparison
+	for _, metric := range perfMetrics(current) {
+		base, ok := baseMetrics[metric.Name]
+		if !ok || base <= 0 {
+			continue
+		}
+		change := (metric.Value - base) / base * 100
+		comparisons = append(comparisons, perfComparison{
+			Metric:    metric.Name,
+			Baseline:  base,
+			Current:   metric.Value,
+			Change:    change,
+			Regressed: -change > threshold,
+			bytes:     metric.Bytes,
+		})
+	}
+	return comparisons
+}
+
+// perfBaselineMessage reports a saved baseline.
+type perfBaselineMessage struct {
+	Status string `json:"status"`
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+}
+
+// String colorized perf baseline message
+func (p perfBaselineMessage) String() string {
+	return console.Colorize("PerfBaseline", "Saved baseline `"+p.Name+"` at "+p.Path)
+}
+
+// JSON jsonified perf baseline message
+func (p perfBaselineMessage) JSON() string {
+	p.Status = "success"
+	msgBytes, e := json.MarshalIndent(p, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(msgBytes)
+}
+
+// perfComparisonMessage reports the comparison of a run to a baseline.
+type perfComparisonMessage struct {
+	Status      string           `json:"status"`
+	Baseline    string           `json:"baseline"`
+	Alias       string           `json:"alias"`
+	Time        time.Time        `json:"time"`
+	Threshold   float64          `json:"threshold"`
+	Metrics     []perfComparison `json:"metrics"`
+	Regressions int              `json:"regressions"`
+}
+
+// String colorized perf comparison message
+func (p perfComparisonMessage) String() string {
+	var s strings.Builder
+	fmt.Fprintf(&s, "Compared to baseline `%s` of `%s` (%s):\n", p.Baseline, p.Alias, humanize.Time(p.Time))
+	format := func(value float64, bytes bool) string {
+		if bytes {
+			return humanize.IBytes(uint64(value)) + "/s"
+		}
+		return humanize.Comma(int64(value)) + "/s"
+	}
+	for _, c := range p.Metrics {
+		line := fmt.Sprintf("   * %-24s %12s -> %-12s %+6.1f%%", c.Metric+":", format(c.Baseline, c.bytes), format(c.Current, c.bytes), c.Change)
+		if c.Regressed {
+			line = console.Colorize("PerfRegression", line+" REGRESSION")
+		}
+		s.WriteString(line + "\n")
+	}
+	if p.Regressions > 0 {
+		s.WriteString(console.Colorize("PerfRegression", fmt.Sprintf("%d regression(s) beyond %g%%", p.Regressions, p.Threshold)))
+	} else {
+		s.WriteString(console.Colorize("PerfBaseline", fmt.Sprintf("No regression beyond %g%%", p.Threshold)))
+	}
+	return s.String()
+}
+
+// JSON jsonified perf comparison message
+func (p perfComparisonMessage) JSON() string {
+	p.Status = "success"
+	if p.Regressions > 0 {
+		p.Status = "error"
+	}
+	msgBytes, e := json.MarshalIndent(p, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(msgBytes)
+}
+
+// checkPerfBaselineSyntax validates the baseline flags of 'mc support perf'.
+func checkPerfBaselineSyntax(ctx *cli.Context) {
+	for _, flag := range []string{"save-baseline", "baseline"} {
+		if name := ctx.String(flag); ctx.IsSet(flag) && !perfBaselineNameRegexp.MatchString(name) {
+			fatalIf(errInvalidArgument().Trace(name), "Invalid baseline name `"+name+"`.")
+		}
+	}
+	if ctx.Float64("threshold") < 0 {
+		fatalIf(errInvalidArgument().Trace(ctx.String("threshold")), "--threshold should not be negative.")
+	}
+}
+
+// perfBaselinePath returns the file of the named baseline.
+func perfBaselinePath(name string) string {
+	return filepath.Join(mustGetMcConfigDir(), perfBaselineDir, name+".json")
+}
+
+// loadPerfBaseline reads the named baseline.
+func loadPerfBaseline(name string) (perfBaseline, *probe.Error) {
+	var baseline perfBaseline
+	path := perfBaselinePath(name)
+	data, e := os.ReadFile(path)
+	if e != nil {
+		return baseline, probe.NewError(e).Trace(path)
+	}
+	if e = json.Unmarshal(data, &baseline); e != nil {
+		return baseline, probe.NewError(e).Trace(path)
+	}
+	return baseline, nil
+}
+
+// savePerfBaseline writes the named baseline, returning its path.
+func savePerfBaseline(baseline perfBaseline) (string, *probe.Error) {
+	path := perfBaselinePath(baseline.Name)
+	if e := os.MkdirAll(filepath.Dir(path), 0o700); e != nil {
+		return "", probe.NewError(e).Trace(path)
+	}
+	data, e := json.MarshalIndent(baseline, "", " ")
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	if e = os.WriteFile(path, data, 0o600); e != nil {
+		return "", probe.NewError(e).Trace(path)
+	}
+	return path, nil
+}
+
+// execPerfBaseline saves or compares the results of the perf tests
+// against baselines, exiting with perfRegressionExitStatus on a
+// regression.
+func execPerfBaseline(ctx *cli.Context, alias string, results []PerfTestResult) {
+	// Additional command specific theme customization.
+	console.SetColor("PerfBaseline", color.New(color.FgGreen, color.Bold))
+	console.SetColor("PerfRegression", color.New(color.FgRed, color.Bold))
+
+	if len(results) == 0 {
+		fatalIf(errDummy().Trace(alias), "No performance results were captured.")
+	}
+	current := convertPerfResults(results)
+
+	var comparison *perfComparisonMessage
+	if name := ctx.String("baseline"); name != "" {
+		baseline, err := loadPerfBaseline(name)
+		fatalIf(err, "Unable to load baseline `"+name+"`.")
+
+		threshold := ctx.Float64("threshold")
+		metrics := comparePerfResults(baseline.Results, current, threshold)
+		if len(metrics) == 0 {
+			fatalIf(errInvalidArgument().Trace(name), "Baseline `"+name+"` has no results of the tests run.")
+		}
+		comparison = &perfComparisonMessage{
+			Baseline:  name,
+			Alias:     baseline.Alias,
+			Time:      baseline.Time,
+			Threshold: threshold,
+			Metrics:   metrics,
+		}
+		for _, metric := range metrics {
+			if metric.Regressed {
+				comparison.Regressions++
+			}
+		}
+	}
+
+	if name := ctx.String("save-baseline"); name != "" {
+		path, err := savePerfBaseline(perfBaseline{
+			Name:    name,
+			Alias:   alias,
+			Time:    UTCNow(),
+			Results: current,
+		})
+		fatalIf(err, "Unable to save baseline `"+name+"`.")
+		printMsg(perfBaselineMessage{Name: name, Path: path})
+	}
+
+	if comparison != nil {
+		printMsg(*comparison)
+		if comparison.Regressions > 0 {
+			os.Exit(perfRegressionExitStatus)
+		}
+	}
+}
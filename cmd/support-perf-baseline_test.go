@@ -0,0 +1,69 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import "testing"
+
+func TestComparePerfResults(t *testing.T) {
+	objectResults := func(put, get uint64) *ObjTestResults {
+		r := &ObjTestResults{}
+		r.PUTResults.Perf.Throughput = put
+		r.PUTResults.Perf.ObjectsPerSec = put / 100
+		r.GETResults.Perf.Throughput = get
+		r.GETResults.Perf.ObjectsPerSec = get / 100
+		return r
+	}
+	netResults := func(tx ...uint64) *NetTestResults {
+		r := &NetTestResults{}
+		for _, v := range tx {
+			r.Results = append(r.Results, NetTestResult{Perf: NetStats{TX: v, RX: v}})
+		}
+		return r
+	}
+
+	testCases := []struct {
+		baseline    PerfTestOutput
+		current     PerfTestOutput
+		threshold   float64
+		metrics     int
+		regressions int
+	}{
+		{PerfTestOutput{ObjectResults: objectResults(1000, 2000)}, PerfTestOutput{ObjectResults: objectResults(1000, 2000)}, 10, 4, 0},
+		{PerfTestOutput{ObjectResults: objectResults(1000, 2000)}, PerfTestOutput{ObjectResults: objectResults(950, 3000)}, 10, 4, 0},
+		{PerfTestOutput{ObjectResults: objectResults(1000, 2000)}, PerfTestOutput{ObjectResults: objectResults(800, 2000)}, 10, 4, 2},
+		{PerfTestOutput{ObjectResults: objectResults(1000, 2000)}, PerfTestOutput{ObjectResults: objectResults(800, 2000)}, 25, 4, 0},
+		// Only the tests run in both are compared.
+		{PerfTestOutput{ObjectResults: objectResults(1000, 2000)}, PerfTestOutput{NetResults: netResults(100, 100)}, 10, 0, 0},
+		{PerfTestOutput{NetResults: netResults(100, 100)}, PerfTestOutput{NetResults: netResults(100, 50), ObjectResults: objectResults(1, 1)}, 10, 2, 2},
+	}
+	for i, testCase := range testCases {
+		metrics := comparePerfResults(testCase.baseline, testCase.current, testCase.threshold)
+		if len(metrics) != testCase.metrics {
+			t.Fatalf("Test %d: expected %d metrics, got %d", i+1, testCase.metrics, len(metrics))
+		}
+		regressions := 0
+		for _, metric := range metrics {
+			if metric.Regressed {
+				regressions++
+			}
+		}
+		if regressions != testCase.regressions {
+			t.Fatalf("Test %d: expected %d regressions, got %d", i+1, testCase.regressions, regressions)
+		}
+	}
+}
@@ -22,7 +22,6 @@ import (
 	"os"
 	"time"
 
-	tea "github.com/charmbracelet/bubbletea"
 	"github.com/minio/cli"
 	"github.com/trinet2005/oss-admin-go"
 	"github.com/trinet2005/oss-mc/pkg/probe"
@@ -60,28 +59,26 @@ func mainAdminSpeedTestClientPerf(ctx *cli.Context, aliasedURL string, outCh cha
 		resultCh <- result
 	}()
 	if globalJSON {
-		var rsl PerfTestOutput
 		select {
 		case e := <-errorCh:
-			rsl = convertPerfResult(PerfTestResult{
+			sendPerfJSONResult(outCh, PerfTestResult{
 				Type:  ClientPerfTest,
 				Err:   e.Error(),
 				Final: true,
 			})
 		case result := <-resultCh:
-			rsl = convertPerfResult(PerfTestResult{
+			sendPerfJSONResult(outCh, PerfTestResult{
 				Type:         ClientPerfTest,
 				ClientResult: &result,
 				Final:        true,
 			})
 		}
-		printMsg(rsl)
 		return nil
 	}
 
 	done := make(chan struct{})
 
-	p := tea.NewProgram(initSpeedTestUI())
+	p := newUIProgram(initSpeedTestUI())
 	go func() {
 		if _, e := p.Run(); e != nil {
 			os.Exit(1)
@@ -21,7 +21,6 @@ import (
 	"context"
 	"os"
 
-	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dustin/go-humanize"
 	"github.com/minio/cli"
 	"github.com/trinet2005/oss-admin-go"
@@ -68,11 +67,11 @@ func mainAdminSpeedTestDrive(ctx *cli.Context, aliasedURL string, outCh chan<- P
 
 	if globalJSON {
 		if e != nil {
-			printMsg(convertPerfResult(PerfTestResult{
+			sendPerfJSONResult(outCh, PerfTestResult{
 				Type:  DrivePerfTest,
 				Err:   e.Error(),
 				Final: true,
-			}))
+			})
 
 			return nil
 		}
@@ -83,18 +82,18 @@ func mainAdminSpeedTestDrive(ctx *cli.Context, aliasedURL string, outCh chan<- P
 				results = append(results, result)
 			}
 		}
-		printMsg(convertPerfResult(PerfTestResult{
+		sendPerfJSONResult(outCh, PerfTestResult{
 			Type:        DrivePerfTest,
 			DriveResult: results,
 			Final:       true,
-		}))
+		})
 
 		return nil
 	}
 
 	done := make(chan struct{})
 
-	p := tea.NewProgram(initSpeedTestUI())
+	p := newUIProgram(initSpeedTestUI())
 	go func() {
 		if _, e := p.Run(); e != nil {
 			os.Exit(1)
@@ -22,7 +22,6 @@ import (
 	"os"
 	"time"
 
-	tea "github.com/charmbracelet/bubbletea"
 	"github.com/minio/cli"
 	"github.com/trinet2005/oss-admin-go"
 	"github.com/trinet2005/oss-mc/pkg/probe"
@@ -64,24 +63,24 @@ func mainAdminSpeedTestNetperf(ctx *cli.Context, aliasedURL string, outCh chan<-
 	if globalJSON {
 		select {
 		case e := <-errorCh:
-			printMsg(convertPerfResult(PerfTestResult{
+			sendPerfJSONResult(outCh, PerfTestResult{
 				Type:  NetPerfTest,
 				Err:   e.Error(),
 				Final: true,
-			}))
+			})
 		case result := <-resultCh:
-			printMsg(convertPerfResult(PerfTestResult{
+			sendPerfJSONResult(outCh, PerfTestResult{
 				Type:      NetPerfTest,
 				NetResult: &result,
 				Final:     true,
-			}))
+			})
 		}
 		return nil
 	}
 
 	done := make(chan struct{})
 
-	p := tea.NewProgram(initSpeedTestUI())
+	p := newUIProgram(initSpeedTestUI())
 	go func() {
 		if _, e := p.Run(); e != nil {
 			os.Exit(1)
@@ -22,7 +22,6 @@ import (
 	"os"
 	"time"
 
-	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dustin/go-humanize"
 	"github.com/minio/cli"
 	"github.com/trinet2005/oss-admin-go"
@@ -95,11 +94,11 @@ func mainAdminSpeedTestObject(ctx *cli.Context, aliasedURL string, outCh chan<-
 
 	if globalJSON {
 		if e != nil {
-			printMsg(convertPerfResult(PerfTestResult{
+			sendPerfJSONResult(outCh, PerfTestResult{
 				Type:  ObjectPerfTest,
 				Err:   e.Error(),
 				Final: true,
-			}))
+			})
 			return nil
 		}
 
@@ -110,18 +109,18 @@ func mainAdminSpeedTestObject(ctx *cli.Context, aliasedURL string, outCh chan<-
 			}
 		}
 
-		printMsg(convertPerfResult(PerfTestResult{
+		sendPerfJSONResult(outCh, PerfTestResult{
 			Type:         ObjectPerfTest,
 			ObjectResult: &result,
 			Final:        true,
-		}))
+		})
 
 		return nil
 	}
 
 	done := make(chan struct{})
 
-	p := tea.NewProgram(initSpeedTestUI())
+	p := newUIProgram(initSpeedTestUI())
 	go func() {
 		if _, e := p.Run(); e != nil {
 			os.Exit(1)
@@ -83,7 +83,7 @@ var supportPerfFlags = append([]cli.Flag{
 		Usage:  "run tests on drive(s) one-by-one",
 		Hidden: true,
 	},
-}, subnetCommonFlags...)
+}, append(perfBaselineFlags, subnetCommonFlags...)...)
 
 var supportPerfCmd = cli.Command{
 	Name:            "perf",
@@ -108,6 +108,12 @@ EXAMPLES:
 
   2. Run object storage, network, and drive performance tests on cluster with alias 'myminio', save and upload to SUBNET manually
      {{.Prompt}} {{.HelpName}} myminio --airgap
+
+  3. Save the object storage performance of cluster with alias 'myminio' as the baseline 'pre-upgrade'
+     {{.Prompt}} {{.HelpName}} object myminio --save-baseline pre-upgrade
+
+  4. Compare the object storage performance to the baseline 'pre-upgrade', failing on a drop of more than 15%
+     {{.Prompt}} {{.HelpName}} object myminio --baseline pre-upgrade --threshold 15
 `,
 }
 
@@ -452,6 +458,15 @@ func convertPerfResult(r PerfTestResult) PerfTestOutput {
 	return out
 }
 
+// sendPerfJSONResult prints the result of a test in JSON mode and
+// sends it to outCh, if not nil.
+func sendPerfJSONResult(outCh chan<- PerfTestResult, r PerfTestResult) {
+	printMsg(convertPerfResult(r))
+	if outCh != nil {
+		outCh <- r
+	}
+}
+
 func convertPerfResults(results []PerfTestResult) PerfTestOutput {
 	out := PerfTestOutput{}
 	for _, r := range results {
@@ -461,6 +476,14 @@ func convertPerfResults(results []PerfTestResult) PerfTestOutput {
 }
 
 func execSupportPerf(ctx *cli.Context, aliasedURL, perfType string) {
+	if ctx.String("baseline") != "" || ctx.String("save-baseline") != "" {
+		// Baselines stay local, nothing is uploaded to SUBNET.
+		checkPerfBaselineSyntax(ctx)
+		alias, _ := url2Alias(aliasedURL)
+		execPerfBaseline(ctx, alias, runPerfTests(ctx, aliasedURL, perfType))
+		return
+	}
+
 	alias, apiKey := initSubnetConnectivity(ctx, aliasedURL, true)
 	if len(apiKey) == 0 {
 		// api key not passed as flag. Check that the cluster is registered.
@@ -512,7 +535,8 @@ func savePerfResultFile(tmpFileName, resultFileNamePfx string) {
 }
 
 func runPerfTests(ctx *cli.Context, aliasedURL, perfType string) []PerfTestResult {
-	resultCh := make(chan PerfTestResult)
+	// Results of tests run in JSON mode are sent before they return.
+	resultCh := make(chan PerfTestResult, 1)
 	results := []PerfTestResult{}
 	defer close(resultCh)
 
@@ -538,9 +562,7 @@ func runPerfTests(ctx *cli.Context, aliasedURL, perfType string) []PerfTestResul
 			showCommandHelpAndExit(ctx, 1) // last argument is exit code
 		}
 
-		if !globalJSON {
-			results = append(results, <-resultCh)
-		}
+		results = append(results, <-resultCh)
 	}
 
 	return results
@@ -20,7 +20,6 @@ package cmd
 import (
 	"context"
 
-	tea "github.com/charmbracelet/bubbletea"
 	"github.com/minio/cli"
 	"github.com/trinet2005/oss-admin-go"
 	"github.com/trinet2005/oss-mc/pkg/probe"
@@ -103,7 +102,7 @@ func mainSupportTopAPI(ctx *cli.Context) error {
 	// Start listening on all trace activity.
 	traceCh := client.ServiceTrace(ctxt, opts)
 
-	p := tea.NewProgram(initTraceUI())
+	p := newUIProgram(initTraceUI())
 	go func() {
 		for apiCallInfo := range traceCh {
 			if apiCallInfo.Err != nil {
@@ -21,7 +21,6 @@ import (
 	"context"
 	"time"
 
-	tea "github.com/charmbracelet/bubbletea"
 	"github.com/minio/cli"
 	"github.com/trinet2005/oss-admin-go"
 	"github.com/trinet2005/oss-mc/pkg/probe"
@@ -100,7 +99,7 @@ func mainSupportTopDrive(ctx *cli.Context) error {
 		N:        ctx.Int("count"),
 	}
 
-	p := tea.NewProgram(initTopDriveUI(disks, ctx.Int("count")))
+	p := newUIProgram(initTopDriveUI(disks, ctx.Int("count")))
 	go func() {
 		out := func(m madmin.RealtimeMetrics) {
 			for name, metric := range m.ByDisk {
@@ -22,7 +22,6 @@ import (
 	"errors"
 	"time"
 
-	tea "github.com/charmbracelet/bubbletea"
 	"github.com/minio/cli"
 	"github.com/trinet2005/oss-admin-go"
 	"github.com/trinet2005/oss-mc/pkg/probe"
@@ -98,7 +97,7 @@ func mainSupportTopNet(ctx *cli.Context) error {
 		Hosts:    hosts,
 	}
 
-	p := tea.NewProgram(initTopNetUI())
+	p := newUIProgram(initTopNetUI())
 	go func() {
 		if globalJSON {
 			e := client.Metrics(ctxt, opts, func(metrics madmin.RealtimeMetrics) {
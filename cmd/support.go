@@ -52,6 +52,7 @@ var supportSubcommands = []cli.Command{
 	supportProfileCmd,
 	supportTopCmd,
 	supportProxyCmd,
+	supportDiffCmd,
 }
 
 var supportCmd = cli.Command{
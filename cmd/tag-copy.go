@@ -0,0 +1,268 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cheggaaa/pb"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	minio "github.com/trinet2005/oss-go-sdk"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+var tagCopyFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "recursive, r",
+		Usage: "copy the tags of all objects below SOURCE to the same object names below TARGET",
+	},
+	cli.BoolFlag{
+		Name:  "merge",
+		Usage: "keep the existing tags of the target objects, the source tags override the ones with the same key",
+	},
+	cli.IntFlag{
+		Name:  "concurrent",
+		Usage: "number of objects tagged in parallel",
+		Value: 16,
+	},
+}
+
+var tagCopyCmd = cli.Command{
+	Name:         "copy",
+	Usage:        "copy the tags of objects to the same objects of another tree",
+	Action:       mainTagCopy,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(tagCopyFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [COMMAND FLAGS] SOURCE TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Read the tags of the SOURCE object and set them on the TARGET object, or with --recursive, set
+  the tags of every object below SOURCE on the object with the same name below TARGET. This
+  restores the tags after a migration with a tool which did not copy them. The tag set of a
+  target object is replaced, unless --merge is set. Source objects without tags are skipped.
+
+  Objects which could not be tagged, such as the ones missing below TARGET, are reported at the end.
+
+EXAMPLES:
+  1. Copy the tags of an object to its copy on another site.
+     {{.Prompt}} {{.HelpName}} old/mybucket/reports/q1.pdf new/mybucket/reports/q1.pdf
+
+  2. Copy the tags of all objects of a bucket to the migrated bucket.
+     {{.Prompt}} {{.HelpName}} --recursive old/mybucket new/mybucket
+
+  3. Add the tags of the objects below archive/ to the existing tags of the migrated objects.
+     {{.Prompt}} {{.HelpName}} --recursive --merge old/mybucket/archive/ new/archive-bucket/
+`,
+}
+
+// tagCopyFailure is an object whose tags could not be copied.
+type tagCopyFailure struct {
+	Key   string `json:"key"`
+	Error string `json:"error"`
+}
+
+// tagCopyMessage is the summary of a tag copy.
+type tagCopyMessage struct {
+	Status   string           `json:"status"`
+	Source   string           `json:"source"`
+	Target   string           `json:"target"`
+	Copied   int64            `json:"copied"`
+	Skipped  int64            `json:"skipped"`
+	Failures []tagCopyFailure `json:"failures,omitempty"`
+}
+
+// String colorized tag copy message.
+func (m tagCopyMessage) String() string {
+	var b strings.Builder
+	b.WriteString(console.Colorize("List", fmt.Sprintf("Tags copied from %s to %s on %d objects, %d objects without tags skipped.",
+		m.Source, m.Target, m.Copied, m.Skipped)))
+	if len(m.Failures) > 0 {
+		fmt.Fprintf(&b, "\n%s", console.Colorize("TagCopyFail", fmt.Sprintf("%d failures:", len(m.Failures))))
+		for _, f := range m.Failures {
+			fmt.Fprintf(&b, "\n  %s: %s", f.Key, f.Error)
+		}
+	}
+	return b.String()
+}
+
+// JSON jsonified tag copy message.
+func (m tagCopyMessage) JSON() string {
+	m.Status = "success"
+	if len(m.Failures) > 0 {
+		m.Status = "error"
+	}
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// tagCopyTargetURL returns the object receiving the tags of the source
+// object, the target itself or, if it ends with a separator, the object
+// of the same base name below it.
+func tagCopyTargetURL(sourceURL, targetURL string) string {
+	if strings.HasSuffix(targetURL, "/") {
+		return targetURL + path.Base(sourceURL)
+	}
+	return targetURL
+}
+
+// tagCopyObject copies the tags of an object of the source alias to an
+// object of the target alias. It returns false if the source object
+// has no tags.
+func tagCopyObject(ctx context.Context, sourceAlias, sourceURL, targetAlias, targetURL string, merge bool) (bool, *probe.Error) {
+	clnt, err := newClientFromAlias(sourceAlias, sourceURL)
+	if err != nil {
+		return false, err
+	}
+	tags, err := clnt.GetTags(ctx, "")
+	if err != nil {
+		if minio.ToErrorResponse(err.ToGoError()).Code != "NoSuchTagSet" {
+			return false, err
+		}
+		tags = nil
+	}
+	if len(tags) == 0 {
+		return false, nil
+	}
+	return true, tagImportObject(ctx, targetAlias, targetURL, tags, merge)
+}
+
+func mainTagCopy(cliCtx *cli.Context) error {
+	ctx, cancelTagCopy := context.WithCancel(globalContext)
+	defer cancelTagCopy()
+
+	if len(cliCtx.Args()) != 2 {
+		showCommandHelpAndExit(cliCtx, globalErrorExitStatus)
+	}
+	sourceURL := cliCtx.Args().Get(0)
+	targetURL := cliCtx.Args().Get(1)
+	recursive := cliCtx.Bool("recursive")
+	merge := cliCtx.Bool("merge")
+	concurrent := cliCtx.Int("concurrent")
+	if concurrent <= 0 {
+		fatalIf(errInvalidArgument().Trace(cliCtx.String("concurrent")), "--concurrent must be positive.")
+	}
+
+	console.SetColor("List", color.New(color.FgGreen))
+	console.SetColor("TagCopyFail", color.New(color.FgRed, color.Bold))
+
+	sourceAlias, sourceURLStr, _ := mustExpandAlias(sourceURL)
+	targetAlias, targetURLStr, _ := mustExpandAlias(targetURL)
+
+	var (
+		mu       sync.Mutex
+		failures []tagCopyFailure
+		copied   int64
+		skipped  int64
+	)
+	copyTags := func(key, source, target string) {
+		ok, err := tagCopyObject(ctx, sourceAlias, source, targetAlias, target, merge)
+		switch {
+		case err != nil:
+			mu.Lock()
+			failures = append(failures, tagCopyFailure{Key: key, Error: err.ToGoError().Error()})
+			mu.Unlock()
+		case ok:
+			atomic.AddInt64(&copied, 1)
+		default:
+			atomic.AddInt64(&skipped, 1)
+		}
+	}
+
+	if !recursive {
+		copyTags(sourceURL, sourceURLStr, tagCopyTargetURL(sourceURLStr, targetURLStr))
+	} else {
+		clnt, err := newClientFromAlias(sourceAlias, sourceURLStr)
+		fatalIf(err.Trace(sourceURL), "Unable to initialize source `"+sourceURL+"`.")
+		basePath := strings.TrimSuffix(clnt.GetURL().Path, "/") + "/"
+
+		var bar *pb.ProgressBar
+		if !globalQuiet && !globalJSON {
+			bar = newPB(0)
+			bar.SetUnits(pb.U_NO)
+			bar.ShowSpeed = false
+		}
+
+		keys := make(chan string)
+		var wg sync.WaitGroup
+		for i := 0; i < concurrent; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for key := range keys {
+					copyTags(key, urlJoinPath(sourceURLStr, key), urlJoinPath(targetURLStr, key))
+					if bar != nil {
+						bar.Increment()
+					}
+				}
+			}()
+		}
+
+		var total int64
+		for content := range clnt.List(ctx, ListOptions{Recursive: true, ShowDir: DirNone}) {
+			if content.Err != nil {
+				fatalIf(content.Err.Trace(sourceURL), "Unable to list source `"+sourceURL+"`.")
+			}
+			if content.IsDeleteMarker || content.Type.IsDir() {
+				continue
+			}
+			total++
+			if bar != nil {
+				bar.SetTotal64(total)
+			}
+			keys <- strings.TrimPrefix(content.URL.Path, basePath)
+		}
+		close(keys)
+		wg.Wait()
+		if bar != nil {
+			bar.Finish()
+		}
+	}
+
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].Key < failures[j].Key
+	})
+	printMsg(tagCopyMessage{
+		Source:   sourceURL,
+		Target:   targetURL,
+		Copied:   copied,
+		Skipped:  skipped,
+		Failures: failures,
+	})
+	if len(failures) > 0 {
+		return exitStatus(globalErrorExitStatus)
+	}
+	return nil
+}
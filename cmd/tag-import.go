@@ -0,0 +1,457 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	gojson "encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cheggaaa/pb"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+	"github.com/trinet2005/oss-pkg/wildcard"
+)
+
+var tagImportFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "file",
+		Usage: "CSV or JSON file mapping object names or globs to tag sets",
+	},
+	cli.StringFlag{
+		Name:  "format",
+		Usage: "format of the file, 'csv' or 'json', guessed from its extension by default",
+	},
+	cli.BoolFlag{
+		Name:  "merge",
+		Usage: "keep the existing tags of the objects, the tags of the file override the ones with the same key",
+	},
+	cli.IntFlag{
+		Name:  "concurrent",
+		Usage: "number of objects tagged in parallel",
+		Value: 16,
+	},
+}
+
+var tagImportCmd = cli.Command{
+	Name:         "import",
+	Usage:        "set tags on many objects from a CSV or JSON mapping",
+	Action:       mainTagImport,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(tagImportFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [COMMAND FLAGS] TARGET --file FILE
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Set tags on the objects of TARGET, a bucket or a prefix, from a mapping of object names to tag
+  sets. Object names are relative to TARGET and may be globs with '*' and '?', an object name
+  takes precedence over the globs and the first matching glob wins. The tag set of an object is
+  replaced, unless --merge is set.
+
+  In CSV, every row is an object name followed by its tags, either in one 'key1=value1&key2=value2'
+  field or one 'key=value' field per tag. A first row 'key,tags' is skipped, and so are the rows
+  starting with '#'.
+  In JSON, every object is {"key": "NAME", "tags": {"key1": "value1"}}, either in an array or
+  one per line.
+
+  Failed rows are reported at the end, with their row number in the file.
+
+EXAMPLES:
+  1. Set the tags of the objects of mybucket listed in tags.csv.
+     {{.Prompt}} cat tags.csv
+     logs/2023/*.gz,retention=1y&team=infra
+     reports/q1.pdf,confidential=true
+     {{.Prompt}} {{.HelpName}} myminio/mybucket --file tags.csv
+
+  2. Add the tags of tags.json to the existing tags of the objects below the prefix archive/.
+     {{.Prompt}} {{.HelpName}} --merge myminio/mybucket/archive --file tags.json
+
+  3. Tag 64 objects at a time.
+     {{.Prompt}} {{.HelpName}} --concurrent 64 myminio/mybucket --file tags.csv
+`,
+}
+
+// tagImportRow maps the objects matching Key, an object name or a
+// glob, to a tag set.
+type tagImportRow struct {
+	Row  int               `json:"row"`
+	Key  string            `json:"key"`
+	Tags map[string]string `json:"tags"`
+}
+
+// isGlob returns true if the key of the row is a glob.
+func (r tagImportRow) isGlob() bool {
+	return strings.ContainsAny(r.Key, "*?")
+}
+
+// parseTagSet parses tags of the form 'key1=value1&key2=value2', as
+// accepted by 'mc tag set'.
+func parseTagSet(s string, tags map[string]string) error {
+	values, e := url.ParseQuery(s)
+	if e != nil {
+		return e
+	}
+	for k, v := range values {
+		if k == "" {
+			return errors.New("tag key cannot be empty")
+		}
+		if _, ok := tags[k]; ok || len(v) > 1 {
+			return fmt.Errorf("duplicate tag key %q", k)
+		}
+		tags[k] = v[0]
+	}
+	return nil
+}
+
+// encodeTagSet returns tags in the form accepted by SetTags.
+func encodeTagSet(tags map[string]string) string {
+	values := make(url.Values, len(tags))
+	for k, v := range tags {
+		values.Set(k, v)
+	}
+	return values.Encode()
+}
+
+// parseTagImportCSV reads the rows of a CSV mapping.
+func parseTagImportCSV(r io.Reader) ([]tagImportRow, *probe.Error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+	var rows []tagImportRow
+	for {
+		record, e := reader.Read()
+		if e == io.EOF {
+			return rows, nil
+		}
+		if e != nil {
+			return nil, probe.NewError(e)
+		}
+		line, _ := reader.FieldPos(0)
+		if len(rows) == 0 && len(record) == 2 && strings.EqualFold(record[0], "key") && strings.EqualFold(record[1], "tags") {
+			continue
+		}
+		if record[0] == "" {
+			return nil, probe.NewError(errors.New("object name cannot be empty")).Trace(fmt.Sprintf("row %d", line))
+		}
+		row := tagImportRow{Row: line, Key: record[0], Tags: make(map[string]string)}
+		for _, field := range record[1:] {
+			if e = parseTagSet(field, row.Tags); e != nil {
+				return nil, probe.NewError(e).Trace(fmt.Sprintf("row %d", line))
+			}
+		}
+		rows = append(rows, row)
+	}
+}
+
+// parseTagImportJSON reads the rows of a JSON mapping, an array of
+// objects or one object per line.
+func parseTagImportJSON(r io.Reader) ([]tagImportRow, *probe.Error) {
+	br := bufio.NewReader(r)
+	for {
+		b, e := br.Peek(1)
+		if e != nil || (b[0] != ' ' && b[0] != '\t' && b[0] != '\r' && b[0] != '\n') {
+			break
+		}
+		br.ReadByte()
+	}
+	decoder := gojson.NewDecoder(br)
+	inArray := false
+	if b, e := br.Peek(1); e == nil && b[0] == '[' {
+		if _, e = decoder.Token(); e != nil {
+			return nil, probe.NewError(e)
+		}
+		inArray = true
+	}
+	var rows []tagImportRow
+	for decoder.More() {
+		var row tagImportRow
+		if e := decoder.Decode(&row); e != nil {
+			return nil, probe.NewError(e).Trace(fmt.Sprintf("row %d", len(rows)+1))
+		}
+		row.Row = len(rows) + 1
+		if row.Key == "" {
+			return nil, probe.NewError(errors.New("object name cannot be empty")).Trace(fmt.Sprintf("row %d", row.Row))
+		}
+		if row.Tags == nil {
+			row.Tags = make(map[string]string)
+		}
+		rows = append(rows, row)
+	}
+	if inArray {
+		if _, e := decoder.Token(); e != nil {
+			return nil, probe.NewError(e)
+		}
+	}
+	return rows, nil
+}
+
+// matchTagImportGlob returns the first glob row matching key.
+func matchTagImportGlob(globs []tagImportRow, key string) (int, bool) {
+	for i, row := range globs {
+		if wildcard.Match(row.Key, key) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// tagImportFailure is a row which could not be applied to an object.
+type tagImportFailure struct {
+	Row   int    `json:"row"`
+	Key   string `json:"key"`
+	Error string `json:"error"`
+}
+
+// tagImportMessage is the summary of a tag import.
+type tagImportMessage struct {
+	Status   string             `json:"status"`
+	Target   string             `json:"target"`
+	Rows     int                `json:"rows"`
+	Tagged   int64              `json:"tagged"`
+	Failures []tagImportFailure `json:"failures,omitempty"`
+}
+
+// String colorized tag import message.
+func (m tagImportMessage) String() string {
+	var b strings.Builder
+	b.WriteString(console.Colorize("List", fmt.Sprintf("Tags set on %d objects of %s from %d rows.", m.Tagged, m.Target, m.Rows)))
+	if len(m.Failures) > 0 {
+		fmt.Fprintf(&b, "\n%s", console.Colorize("TagImportFail", fmt.Sprintf("%d failures:", len(m.Failures))))
+		for _, f := range m.Failures {
+			fmt.Fprintf(&b, "\n  row %d %s: %s", f.Row, f.Key, f.Error)
+		}
+	}
+	return b.String()
+}
+
+// JSON jsonified tag import message.
+func (m tagImportMessage) JSON() string {
+	m.Status = "success"
+	if len(m.Failures) > 0 {
+		m.Status = "error"
+	}
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// tagImportJob sets the tags of a row on one object.
+type tagImportJob struct {
+	row *tagImportRow
+	key string
+}
+
+// tagImportObject sets the tags of an object, merged with its
+// existing tags if merge is set.
+func tagImportObject(ctx context.Context, alias, urlStr string, tags map[string]string, merge bool) *probe.Error {
+	clnt, err := newClientFromAlias(alias, urlStr)
+	if err != nil {
+		return err
+	}
+	if merge {
+		existing, err := clnt.GetTags(ctx, "")
+		if err != nil {
+			return err
+		}
+		merged := make(map[string]string, len(existing)+len(tags))
+		for k, v := range existing {
+			merged[k] = v
+		}
+		for k, v := range tags {
+			merged[k] = v
+		}
+		tags = merged
+	}
+	return clnt.SetTags(ctx, "", encodeTagSet(tags))
+}
+
+func mainTagImport(cliCtx *cli.Context) error {
+	ctx, cancelTagImport := context.WithCancel(globalContext)
+	defer cancelTagImport()
+
+	if len(cliCtx.Args()) != 1 || cliCtx.String("file") == "" {
+		showCommandHelpAndExit(cliCtx, globalErrorExitStatus)
+	}
+	targetURL := cliCtx.Args().Get(0)
+	file := cliCtx.String("file")
+	merge := cliCtx.Bool("merge")
+	concurrent := cliCtx.Int("concurrent")
+	if concurrent <= 0 {
+		fatalIf(errInvalidArgument().Trace(cliCtx.String("concurrent")), "--concurrent must be positive.")
+	}
+	format := strings.ToLower(cliCtx.String("format"))
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(file)), ".")
+	}
+
+	console.SetColor("List", color.New(color.FgGreen))
+	console.SetColor("TagImportFail", color.New(color.FgRed, color.Bold))
+
+	f, e := os.Open(file)
+	fatalIf(probe.NewError(e).Trace(file), "Unable to open the tag mapping.")
+	var rows []tagImportRow
+	var err *probe.Error
+	switch format {
+	case "csv":
+		rows, err = parseTagImportCSV(f)
+	case "json", "jsonl":
+		rows, err = parseTagImportJSON(f)
+	default:
+		err = errInvalidArgument().Trace(format)
+	}
+	f.Close()
+	fatalIf(err.Trace(file), "Unable to read the tag mapping, the format must be 'csv' or 'json'.")
+
+	alias, urlStr, _ := mustExpandAlias(targetURL)
+	clnt, err := newClientFromAlias(alias, urlStr)
+	fatalIf(err.Trace(targetURL), "Unable to initialize target `"+targetURL+"`.")
+	basePath := strings.TrimSuffix(clnt.GetURL().Path, "/") + "/"
+
+	var exact, globs []tagImportRow
+	exactKeys := make(map[string]bool)
+	for _, row := range rows {
+		switch {
+		case row.isGlob():
+			globs = append(globs, row)
+		case !exactKeys[row.Key]:
+			exactKeys[row.Key] = true
+			exact = append(exact, row)
+		}
+	}
+
+	var bar *pb.ProgressBar
+	if !globalQuiet && !globalJSON {
+		bar = newPB(0)
+		bar.SetUnits(pb.U_NO)
+		bar.ShowSpeed = false
+	}
+
+	var (
+		mu       sync.Mutex
+		failures []tagImportFailure
+		tagged   int64
+		total    int64
+	)
+	fail := func(row *tagImportRow, key string, err *probe.Error) {
+		mu.Lock()
+		failures = append(failures, tagImportFailure{Row: row.Row, Key: key, Error: err.ToGoError().Error()})
+		mu.Unlock()
+	}
+
+	jobs := make(chan tagImportJob)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrent; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				if err := tagImportObject(ctx, alias, urlJoinPath(urlStr, job.key), job.row.Tags, merge); err != nil {
+					fail(job.row, job.key, err.Trace(job.key))
+				} else {
+					atomic.AddInt64(&tagged, 1)
+				}
+				if bar != nil {
+					bar.Increment()
+				}
+			}
+		}()
+	}
+	queue := func(job tagImportJob) {
+		n := atomic.AddInt64(&total, 1)
+		if bar != nil {
+			bar.SetTotal64(n)
+		}
+		jobs <- job
+	}
+
+	for i := range exact {
+		queue(tagImportJob{row: &exact[i], key: exact[i].Key})
+	}
+	if len(globs) > 0 {
+		matched := make([]bool, len(globs))
+		for content := range clnt.List(ctx, ListOptions{Recursive: true, ShowDir: DirNone}) {
+			if content.Err != nil {
+				fatalIf(content.Err.Trace(targetURL), "Unable to list target `"+targetURL+"`.")
+			}
+			if content.IsDeleteMarker || content.Type.IsDir() {
+				continue
+			}
+			key := strings.TrimPrefix(content.URL.Path, basePath)
+			if exactKeys[key] {
+				continue
+			}
+			if i, ok := matchTagImportGlob(globs, key); ok {
+				matched[i] = true
+				queue(tagImportJob{row: &globs[i], key: key})
+			}
+		}
+		for i := range globs {
+			if !matched[i] {
+				fail(&globs[i], globs[i].Key, probe.NewError(errors.New("no object matches")))
+			}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if bar != nil {
+		bar.Finish()
+	}
+
+	sortTagImportFailures(failures)
+	printMsg(tagImportMessage{
+		Target:   targetURL,
+		Rows:     len(rows),
+		Tagged:   tagged,
+		Failures: failures,
+	})
+	if len(failures) > 0 {
+		return exitStatus(globalErrorExitStatus)
+	}
+	return nil
+}
+
+// sortTagImportFailures sorts failures by row, then by object name.
+func sortTagImportFailures(failures []tagImportFailure) {
+	sort.Slice(failures, func(i, j int) bool {
+		if failures[i].Row != failures[j].Row {
+			return failures[i].Row < failures[j].Row
+		}
+		return failures[i].Key < failures[j].Key
+	})
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTagImport(t *testing.T) {
+	testCases := []struct {
+		format  string
+		input   string
+		rows    []tagImportRow
+		success bool
+	}{
+		{"csv", "", nil, true},
+		{
+			"csv",
+			"key,tags\n# comment\nlogs/*.gz,retention=1y&team=infra\n\"a,b.txt\",k=v,k2=hello+world\n",
+			[]tagImportRow{
+				{Row: 3, Key: "logs/*.gz", Tags: map[string]string{"retention": "1y", "team": "infra"}},
+				{Row: 4, Key: "a,b.txt", Tags: map[string]string{"k": "v", "k2": "hello world"}},
+			},
+			true,
+		},
+		{"csv", "a.txt\n", []tagImportRow{{Row: 1, Key: "a.txt", Tags: map[string]string{}}}, true},
+		{"csv", "a.txt,k=1&k=2\n", nil, false},
+		{"csv", "a.txt,k=1,k=2\n", nil, false},
+		{"csv", ",k=1\n", nil, false},
+		{
+			"json",
+			`{"key": "a.txt", "tags": {"k": "v"}}
+			 {"key": "b/*", "row": 42}`,
+			[]tagImportRow{
+				{Row: 1, Key: "a.txt", Tags: map[string]string{"k": "v"}},
+				{Row: 2, Key: "b/*", Tags: map[string]string{}},
+			},
+			true,
+		},
+		{"json", ` [{"key": "a.txt", "tags": {"k": "v"}}]`, []tagImportRow{{Row: 1, Key: "a.txt", Tags: map[string]string{"k": "v"}}}, true},
+		{"json", `[{"tags": {"k": "v"}}]`, nil, false},
+		{"json", `[{"key": "a.txt"}`, nil, false},
+	}
+	for i, testCase := range testCases {
+		parse := parseTagImportCSV
+		if testCase.format == "json" {
+			parse = parseTagImportJSON
+		}
+		rows, err := parse(strings.NewReader(testCase.input))
+		if (err == nil) != testCase.success {
+			t.Fatalf("Test %d: expected success %v, got %v", i+1, testCase.success, err)
+		}
+		if err == nil && !reflect.DeepEqual(rows, testCase.rows) {
+			t.Fatalf("Test %d: expected %+v, got %+v", i+1, testCase.rows, rows)
+		}
+	}
+}
+
+func TestEncodeTagSet(t *testing.T) {
+	tags := map[string]string{"b": "hello world", "a": "x&y=z"}
+	encoded := encodeTagSet(tags)
+	if encoded != "a=x%26y%3Dz&b=hello+world" {
+		t.Fatalf("unexpected encoding %q", encoded)
+	}
+	decoded := make(map[string]string)
+	if e := parseTagSet(encoded, decoded); e != nil || !reflect.DeepEqual(decoded, tags) {
+		t.Fatalf("expected %v, got %v (%v)", tags, decoded, e)
+	}
+}
+
+func TestMatchTagImportGlob(t *testing.T) {
+	globs := []tagImportRow{{Key: "logs/*.gz"}, {Key: "logs/*"}, {Key: "data/?.csv"}}
+	testCases := []struct {
+		key     string
+		index   int
+		matched bool
+	}{
+		{"logs/2023/a.gz", 0, true},
+		{"logs/2023/a.txt", 1, true},
+		{"data/a.csv", 2, true},
+		{"data/ab.csv", 0, false},
+		{"other", 0, false},
+	}
+	for i, testCase := range testCases {
+		index, matched := matchTagImportGlob(globs, testCase.key)
+		if matched != testCase.matched || index != testCase.index {
+			t.Fatalf("Test %d: expected %d %v, got %d %v", i+1, testCase.index, testCase.matched, index, matched)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	gojson "encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	json "github.com/minio/colorjson"
+	minio "github.com/trinet2005/oss-go-sdk"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+// tagValueUsage is the usage of one value of a tag key.
+type tagValueUsage struct {
+	Value   string `json:"value"`
+	Objects int64  `json:"objects"`
+	Size    int64  `json:"size"`
+}
+
+// tagKeyUsage is the usage of a tag key and of each of its values.
+type tagKeyUsage struct {
+	Key     string          `json:"key"`
+	Objects int64           `json:"objects"`
+	Size    int64           `json:"size"`
+	Values  []tagValueUsage `json:"values"`
+}
+
+// tagSummary aggregates the tags of the listed objects.
+type tagSummary struct {
+	objects      int64
+	size         int64
+	untagged     int64
+	untaggedSize int64
+	keys         map[string]*tagKeyUsage
+	values       map[string]map[string]*tagValueUsage
+}
+
+func newTagSummary() *tagSummary {
+	return &tagSummary{
+		keys:   make(map[string]*tagKeyUsage),
+		values: make(map[string]map[string]*tagValueUsage),
+	}
+}
+
+// add accounts for an object of the given size carrying tags.
+func (s *tagSummary) add(tags map[string]string, size int64) {
+	s.objects++
+	s.size += size
+	if len(tags) == 0 {
+		s.untagged++
+		s.untaggedSize += size
+		return
+	}
+	for k, v := range tags {
+		key := s.keys[k]
+		if key == nil {
+			key = &tagKeyUsage{Key: k}
+			s.keys[k] = key
+			s.values[k] = make(map[string]*tagValueUsage)
+		}
+		key.Objects++
+		key.Size += size
+
+		value := s.values[k][v]
+		if value == nil {
+			value = &tagValueUsage{Value: v}
+			s.values[k][v] = value
+		}
+		value.Objects++
+		value.Size += size
+	}
+}
+
+// message returns the summary, the most used keys and values first.
+func (s *tagSummary) message(url string) tagSummaryMessage {
+	m := tagSummaryMessage{
+		URL:          url,
+		Objects:      s.objects,
+		Size:         s.size,
+		Untagged:     s.untagged,
+		UntaggedSize: s.untaggedSize,
+	}
+	for k, key := range s.keys {
+		usage := *key
+		for _, value := range s.values[k] {
+			usage.Values = append(usage.Values, *value)
+		}
+		sort.Slice(usage.Values, func(i, j int) bool {
+			if usage.Values[i].Objects != usage.Values[j].Objects {
+				return usage.Values[i].Objects > usage.Values[j].Objects
+			}
+			return usage.Values[i].Value < usage.Values[j].Value
+		})
+		m.Keys = append(m.Keys, usage)
+	}
+	sort.Slice(m.Keys, func(i, j int) bool {
+		if m.Keys[i].Objects != m.Keys[j].Objects {
+			return m.Keys[i].Objects > m.Keys[j].Objects
+		}
+		return m.Keys[i].Key < m.Keys[j].Key
+	})
+	return m
+}
+
+// tagSummaryMessage container for the tag usage under a prefix.
+type tagSummaryMessage struct {
+	Status       string        `json:"status"`
+	URL          string        `json:"url"`
+	Objects      int64         `json:"objects"`
+	Size         int64         `json:"size"`
+	Untagged     int64         `json:"untagged"`
+	UntaggedSize int64         `json:"untaggedSize"`
+	Keys         []tagKeyUsage `json:"keys"`
+}
+
+// String colorized tag summary message.
+func (m tagSummaryMessage) String() string {
+	var s strings.Builder
+	fmt.Fprintf(&s, "%s %s\n", console.Colorize("Name", "Tags of"), console.Colorize("Name", m.URL))
+	fmt.Fprintf(&s, "Objects: %d (%s), untagged: %d (%s)\n", m.Objects, humanize.IBytes(uint64(m.Size)),
+		m.Untagged, humanize.IBytes(uint64(m.UntaggedSize)))
+	if len(m.Keys) == 0 {
+		s.WriteString(console.Colorize("NoTags", "No tags found"))
+		return s.String()
+	}
+
+	s.WriteString("\n")
+	w := tabwriter.NewWriter(&s, 1, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tVALUE\tOBJECTS\tSIZE")
+	for _, key := range m.Keys {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", key.Key, "*", key.Objects, humanize.IBytes(uint64(key.Size)))
+		for _, value := range key.Values {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", "", value.Value, value.Objects, humanize.IBytes(uint64(value.Size)))
+		}
+	}
+	w.Flush()
+	return strings.TrimSuffix(s.String(), "\n")
+}
+
+// JSON jsonified tag summary message.
+func (m tagSummaryMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// tagInventoryEntry is the tags of one object in a tag inventory.
+type tagInventoryEntry struct {
+	Key          string            `json:"key"`
+	VersionID    string            `json:"versionID,omitempty"`
+	Size         int64             `json:"size"`
+	LastModified time.Time         `json:"lastModified"`
+	Tags         map[string]string `json:"tags"`
+}
+
+// tagInventoryWriter writes a tag inventory as CSV, in the format read
+// by 'mc tag import', or as JSON lines.
+type tagInventoryWriter struct {
+	csv  *csv.Writer
+	json *gojson.Encoder
+}
+
+func newTagInventoryWriter(w io.Writer, format string) (*tagInventoryWriter, *probe.Error) {
+	switch format {
+	case "csv":
+		iw := &tagInventoryWriter{csv: csv.NewWriter(w)}
+		if e := iw.csv.Write([]string{"key", "tags"}); e != nil {
+			return nil, probe.NewError(e)
+		}
+		return iw, nil
+	case "jsonl", "json":
+		return &tagInventoryWriter{json: gojson.NewEncoder(w)}, nil
+	}
+	return nil, errInvalidArgument().Trace(format)
+}
+
+// write adds an entry to the inventory.
+func (w *tagInventoryWriter) write(entry tagInventoryEntry) *probe.Error {
+	var e error
+	if w.csv != nil {
+		e = w.csv.Write([]string{entry.Key, encodeTagSet(entry.Tags)})
+	} else {
+		e = w.json.Encode(entry)
+	}
+	return probe.NewError(e)
+}
+
+// flush flushes the buffered CSV records.
+func (w *tagInventoryWriter) flush() *probe.Error {
+	if w.csv == nil {
+		return nil
+	}
+	w.csv.Flush()
+	return probe.NewError(w.csv.Error())
+}
+
+// tagExportMessage container for an exported tag inventory.
+type tagExportMessage struct {
+	Status  string `json:"status"`
+	URL     string `json:"url"`
+	File    string `json:"file"`
+	Objects int64  `json:"objects"`
+}
+
+// String colorized tag export message.
+func (m tagExportMessage) String() string {
+	return console.Colorize("TagExport", fmt.Sprintf("Exported the tags of %d objects under `%s` to `%s`.", m.Objects, m.URL, m.File))
+}
+
+// JSON jsonified tag export message.
+func (m tagExportMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// summarizeTags fetches the tags of every object under targetURL to
+// summarize them, export them to exportFile, or both.
+func summarizeTags(ctx context.Context, clnt Client, alias, targetURL string, timeRef time.Time, withVersions, summary bool, exportFile, format string) error {
+	var inventory *tagInventoryWriter
+	var f *os.File
+	if exportFile != "" {
+		if withVersions && format == "csv" {
+			fatalIf(errInvalidArgument().Trace(format), "Exporting the tags of all versions requires --format jsonl.")
+		}
+		var e error
+		f, e = os.Create(exportFile)
+		fatalIf(probe.NewError(e).Trace(exportFile), "Unable to create the tag inventory.")
+		defer f.Close()
+		var err *probe.Error
+		inventory, err = newTagInventoryWriter(f, format)
+		fatalIf(err.Trace(exportFile), "Unable to write the tag inventory, the format must be 'csv' or 'jsonl'.")
+	}
+
+	var cErr error
+	stats := newTagSummary()
+	basePath := strings.TrimSuffix(clnt.GetURL().Path, "/") + "/"
+	for content := range clnt.List(ctx, ListOptions{TimeRef: timeRef, WithOlderVersions: withVersions, Recursive: true, ShowDir: DirNone}) {
+		if content.Err != nil {
+			fatalIf(content.Err.Trace(), "Unable to list target "+targetURL)
+		}
+		if content.IsDeleteMarker || content.Type.IsDir() {
+			continue
+		}
+
+		objClnt, err := newClientFromAlias(alias, content.URL.String())
+		if err == nil {
+			var tags map[string]string
+			tags, err = objClnt.GetTags(ctx, content.VersionID)
+			if err != nil && minio.ToErrorResponse(err.ToGoError()).Code == "NoSuchTagSet" {
+				err = nil
+			}
+			if err == nil {
+				stats.add(tags, content.Size)
+				if inventory != nil {
+					err = inventory.write(tagInventoryEntry{
+						Key:          strings.TrimPrefix(content.URL.Path, basePath),
+						VersionID:    content.VersionID,
+						Size:         content.Size,
+						LastModified: content.Time,
+						Tags:         tags,
+					})
+					fatalIf(err.Trace(exportFile), "Unable to write the tag inventory.")
+				}
+				continue
+			}
+		}
+		errorIf(err.Trace(content.URL.String()), "Unable to fetch tags for "+content.URL.String())
+		cErr = exitStatus(globalErrorExitStatus)
+	}
+
+	if inventory != nil {
+		fatalIf(inventory.flush().Trace(exportFile), "Unable to write the tag inventory.")
+		fatalIf(probe.NewError(f.Close()).Trace(exportFile), "Unable to write the tag inventory.")
+		if !summary {
+			printMsg(tagExportMessage{URL: targetURL, File: exportFile, Objects: stats.objects})
+		}
+	}
+	if summary {
+		printMsg(stats.message(targetURL))
+	}
+	return cErr
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestTagSummary(t *testing.T) {
+	s := newTagSummary()
+	s.add(map[string]string{"env": "prod", "team": "a"}, 100)
+	s.add(map[string]string{"env": "prod"}, 200)
+	s.add(map[string]string{"env": "dev"}, 50)
+	s.add(nil, 10)
+
+	m := s.message("myminio/bucket")
+	if m.Objects != 4 || m.Size != 360 || m.Untagged != 1 || m.UntaggedSize != 10 {
+		t.Fatalf("unexpected totals: %+v", m)
+	}
+	expected := []tagKeyUsage{
+		{Key: "env", Objects: 3, Size: 350, Values: []tagValueUsage{
+			{Value: "prod", Objects: 2, Size: 300},
+			{Value: "dev", Objects: 1, Size: 50},
+		}},
+		{Key: "team", Objects: 1, Size: 100, Values: []tagValueUsage{
+			{Value: "a", Objects: 1, Size: 100},
+		}},
+	}
+	if !reflect.DeepEqual(m.Keys, expected) {
+		t.Errorf("expected %+v, got %+v", expected, m.Keys)
+	}
+}
+
+func TestTagInventoryWriter(t *testing.T) {
+	entries := []tagInventoryEntry{
+		{Key: "a/b.txt", Size: 10, Tags: map[string]string{"env": "prod", "owner": "x y"}},
+		{Key: "c,d.txt", Size: 20, Tags: map[string]string{}},
+	}
+
+	var buf bytes.Buffer
+	w, err := newTagInventoryWriter(&buf, "csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if err = w.write(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.flush(); err != nil {
+		t.Fatal(err)
+	}
+	expected := "key,tags\na/b.txt,env=prod&owner=x+y\n\"c,d.txt\",\n"
+	if buf.String() != expected {
+		t.Errorf("expected CSV %q, got %q", expected, buf.String())
+	}
+	// The CSV inventory is read back by tag import.
+	rows, err := parseTagImportCSV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, row := range rows {
+		if row.Key != entries[i].Key || !reflect.DeepEqual(row.Tags, entries[i].Tags) {
+			t.Errorf("Test %d: expected %+v, got %+v", i+1, entries[i], row)
+		}
+	}
+
+	buf.Reset()
+	if w, err = newTagInventoryWriter(&buf, "jsonl"); err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if err = w.write(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if rows, err = parseTagImportJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != len(entries) || rows[0].Tags["owner"] != "x y" {
+		t.Errorf("unexpected JSON lines rows %+v", rows)
+	}
+
+	if _, err = newTagInventoryWriter(&buf, "xml"); err == nil {
+		t.Errorf("expected an error for an unknown format")
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"sort"
 	"strings"
 	"time"
@@ -50,6 +51,18 @@ var tagListFlags = []cli.Flag{
 		Name:  "recursive, r",
 		Usage: "recursivley show tags for all objects",
 	},
+	cli.BoolFlag{
+		Name:  "summary",
+		Usage: "summarize the objects and bytes per tag key and value, requires --recursive",
+	},
+	cli.StringFlag{
+		Name:  "export",
+		Usage: "write the tags of every object to a file, requires --recursive",
+	},
+	cli.StringFlag{
+		Name:  "format",
+		Usage: "format of the exported file, 'csv' or 'jsonl', guessed from its extension by default",
+	},
 }
 
 var tagListCmd = cli.Command{
@@ -95,6 +108,15 @@ EXAMPLES:
 
   8. Show the tags recursively for all versions of all objects of subdirs of bucket.
      {{.Prompt}} {{.HelpName}} --recursive --versions myminio/testbucket
+
+  9. Summarize the number of objects and bytes per tag key and value under a prefix.
+     {{.Prompt}} {{.HelpName}} --recursive --summary myminio/testbucket/logs/
+
+  10. Export the tags of all objects under a prefix to a CSV file, which 'mc tag import' reads back.
+      {{.Prompt}} {{.HelpName}} --recursive --export tags.csv myminio/testbucket/logs/
+
+  11. Export the tags of all versions of all objects as JSON lines and summarize them.
+      {{.Prompt}} {{.HelpName}} --recursive --versions --summary --export tags.jsonl myminio/testbucket
 `,
 }
 
@@ -162,6 +184,13 @@ func parseTagListSyntax(ctx *cli.Context) (targetURL, versionID string, timeRef
 		fatalIf(errDummy().Trace(), "You cannot specify both --version-id and --rewind flags at the same time")
 	}
 
+	if (ctx.Bool("summary") || ctx.String("export") != "") && !recursive {
+		fatalIf(errDummy().Trace(), "--summary and --export require --recursive.")
+	}
+	if ctx.String("format") != "" && ctx.String("export") == "" {
+		fatalIf(errDummy().Trace(), "--format requires --export.")
+	}
+
 	timeRef = parseRewindFlag(rewind)
 	return
 }
@@ -218,6 +247,14 @@ func mainListTag(cliCtx *cli.Context) error {
 	fatalIf(err, "Unable to initialize target "+targetURL)
 
 	alias, urlStr, _ := mustExpandAlias(targetURL)
+	if summary, exportFile := cliCtx.Bool("summary"), cliCtx.String("export"); summary || exportFile != "" {
+		console.SetColor("TagExport", color.New(color.FgGreen))
+		format := strings.ToLower(cliCtx.String("format"))
+		if format == "" && exportFile != "" {
+			format = strings.TrimPrefix(strings.ToLower(filepath.Ext(exportFile)), ".")
+		}
+		return summarizeTags(ctx, clnt, alias, targetURL, timeRef, withVersions, summary, exportFile, format)
+	}
 	if timeRef.IsZero() && !withVersions && !recursive {
 		err := showTagsSingle(ctx, alias, urlStr, versionID)
 		fatalIf(err.Trace(), "Unable to show tags on `%s`", targetURL)
@@ -22,7 +22,9 @@ import (
 )
 
 var tagSubcommands = []cli.Command{
+	tagCopyCmd,
 	tagListCmd,
+	tagImportCmd,
 	tagRemoveCmd,
 	tagSetCmd,
 }
@@ -46,6 +46,10 @@ var tagRemoveFlags = []cli.Flag{
 		Name:  "recursive, r",
 		Usage: "recursivley remove tags for all objects",
 	},
+	cli.StringFlag{
+		Name:  "keys",
+		Usage: "comma separated keys of the tags to remove, the other tags are kept",
+	},
 }
 
 var tagRemoveCmd = cli.Command{
@@ -65,7 +69,8 @@ FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}
 DESCRIPTION:
-  Remove tags assigned to a bucket or an object.
+  Remove tags assigned to a bucket or an object. With --keys, only the tags with the
+  given keys are removed and the other tags are kept.
 
 EXAMPLES:
   1. Remove the tags assigned to an object.
@@ -85,20 +90,28 @@ EXAMPLES:
 
   6. Remove the tags recursively for all versions of all objects of subdirs of bucket.
      {{.Prompt}} {{.HelpName}} --recursive --versions myminio/testbucket
+
+  7. Remove the tags 'env' and 'team' of an object, keeping its other tags.
+     {{.Prompt}} {{.HelpName}} --keys env,team myminio/testbucket/testobject
 `,
 }
 
 // tagSetTagMessage structure will show message depending on the type of console.
 type tagRemoveMessage struct {
-	Status    string `json:"status"`
-	Name      string `json:"name"`
-	VersionID string `json:"versionID"`
+	Status    string   `json:"status"`
+	Name      string   `json:"name"`
+	VersionID string   `json:"versionID"`
+	Keys      []string `json:"keys,omitempty"`
 }
 
 // tagRemoveMessage console colorized output.
 func (t tagRemoveMessage) String() string {
 	var msg string
-	msg += "Tags removed for " + t.Name
+	if len(t.Keys) > 0 {
+		msg += "Tags " + strings.Join(t.Keys, ", ") + " removed for " + t.Name
+	} else {
+		msg += "Tags removed for " + t.Name
+	}
 	if strings.TrimSpace(t.VersionID) != "" {
 		msg += " (" + t.VersionID + ")"
 	}
@@ -113,7 +126,7 @@ func (t tagRemoveMessage) JSON() string {
 	return string(msgBytes)
 }
 
-func parseRemoveTagSyntax(ctx *cli.Context) (targetURL, versionID string, timeRef time.Time, withVersions, recursive bool) {
+func parseRemoveTagSyntax(ctx *cli.Context) (targetURL, versionID string, timeRef time.Time, withVersions, recursive bool, keys []string) {
 	if len(ctx.Args()) != 1 {
 		showCommandHelpAndExit(ctx, globalErrorExitStatus)
 	}
@@ -123,6 +136,12 @@ func parseRemoveTagSyntax(ctx *cli.Context) (targetURL, versionID string, timeRe
 	withVersions = ctx.Bool("versions")
 	rewind := ctx.String("rewind")
 	recursive = ctx.Bool("recursive")
+	if ctx.IsSet("keys") {
+		keys = parseTagKeys(ctx.String("keys"))
+		if len(keys) == 0 {
+			fatalIf(errInvalidArgument().Trace(ctx.String("keys")), "--keys requires at least one tag key.")
+		}
+	}
 
 	if versionID != "" && (rewind != "" || withVersions) {
 		fatalIf(errDummy().Trace(), "You cannot specify both --version-id and --rewind or --versions flags at the same time")
@@ -132,33 +151,91 @@ func parseRemoveTagSyntax(ctx *cli.Context) (targetURL, versionID string, timeRe
 	return
 }
 
-// Delete tags of a bucket or a specified object/version
-func deleteTags(ctx context.Context, clnt Client, versionID string) {
+// parseTagKeys parses the comma separated tag keys of --keys.
+func parseTagKeys(s string) (keys []string) {
+	for _, key := range strings.Split(s, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// removeTagKeys returns tags without the given keys, and the keys
+// which were removed.
+func removeTagKeys(tags map[string]string, keys []string) (map[string]string, []string) {
+	kept := make(map[string]string, len(tags))
+	for k, v := range tags {
+		kept[k] = v
+	}
+	var removed []string
+	for _, key := range keys {
+		if _, ok := kept[key]; ok {
+			delete(kept, key)
+			removed = append(removed, key)
+		}
+	}
+	return kept, removed
+}
+
+// deleteTagKeys removes the tags with the given keys of a bucket or an
+// object/version and keeps its other tags, it returns the keys removed.
+func deleteTagKeys(ctx context.Context, clnt Client, versionID string, keys []string) ([]string, *probe.Error) {
+	tags, err := clnt.GetTags(ctx, versionID)
+	if err != nil {
+		return nil, err
+	}
+	kept, removed := removeTagKeys(tags, keys)
+	switch {
+	case len(removed) == 0:
+		return nil, nil
+	case len(kept) == 0:
+		err = clnt.DeleteTags(ctx, versionID)
+	default:
+		err = clnt.SetTags(ctx, versionID, encodeTagSet(kept))
+	}
+	return removed, err
+}
+
+// Delete tags of a bucket or a specified object/version, only the tags
+// with the given keys if any.
+func deleteTags(ctx context.Context, clnt Client, versionID string, keys []string) {
 	targetName := clnt.GetURL().String()
 	if versionID != "" {
 		targetName += " (" + versionID + ")"
 	}
 
-	err := clnt.DeleteTags(ctx, versionID)
+	var removed []string
+	var err *probe.Error
+	if len(keys) > 0 {
+		removed, err = deleteTagKeys(ctx, clnt, versionID, keys)
+	} else {
+		err = clnt.DeleteTags(ctx, versionID)
+	}
 	if err != nil {
 		fatalIf(err, "Unable to remove tags for "+targetName)
 		return
 	}
+	if len(keys) > 0 && len(removed) == 0 {
+		// None of the tags to remove is set.
+		return
+	}
 
 	printMsg(tagRemoveMessage{
 		Status:    "success",
 		Name:      clnt.GetURL().String(),
 		VersionID: versionID,
+		Keys:      removed,
 	})
 }
 
-func deleteTagsSingle(ctx context.Context, alias, url, versionID string) *probe.Error {
+func deleteTagsSingle(ctx context.Context, alias, url, versionID string, keys []string) *probe.Error {
 	newClnt, err := newClientFromAlias(alias, url)
 	if err != nil {
 		return err
 	}
 
-	deleteTags(ctx, newClnt, versionID)
+	deleteTags(ctx, newClnt, versionID, keys)
 	return nil
 }
 
@@ -168,7 +245,7 @@ func mainRemoveTag(cliCtx *cli.Context) error {
 
 	console.SetColor("Remove", color.New(color.FgGreen))
 
-	targetURL, versionID, timeRef, withVersions, recursive := parseRemoveTagSyntax(cliCtx)
+	targetURL, versionID, timeRef, withVersions, recursive, keys := parseRemoveTagSyntax(cliCtx)
 	if timeRef.IsZero() && withVersions {
 		timeRef = time.Now().UTC()
 	}
@@ -178,7 +255,7 @@ func mainRemoveTag(cliCtx *cli.Context) error {
 
 	alias, urlStr, _ := mustExpandAlias(targetURL)
 	if timeRef.IsZero() && !withVersions && !recursive {
-		err := deleteTagsSingle(ctx, alias, urlStr, versionID)
+		err := deleteTagsSingle(ctx, alias, urlStr, versionID, keys)
 		fatalIf(err.Trace(), "Unable to remove tags on `%s`", targetURL)
 		return nil
 	}
@@ -196,7 +273,7 @@ func mainRemoveTag(cliCtx *cli.Context) error {
 			break
 		}
 
-		err := deleteTagsSingle(ctx, alias, content.URL.String(), content.VersionID)
+		err := deleteTagsSingle(ctx, alias, content.URL.String(), content.VersionID, keys)
 		if err != nil {
 			errorIf(err.Trace(clnt.GetURL().String()), "Invalid URL")
 			continue
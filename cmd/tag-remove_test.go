@@ -0,0 +1,49 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRemoveTagKeys(t *testing.T) {
+	tags := map[string]string{"env": "prod", "team": "infra", "owner": "ops"}
+	testCases := []struct {
+		keys    string
+		kept    map[string]string
+		removed []string
+	}{
+		{"env", map[string]string{"team": "infra", "owner": "ops"}, []string{"env"}},
+		{" team , env,", map[string]string{"owner": "ops"}, []string{"team", "env"}},
+		{"missing", tags, nil},
+		{"env,team,owner", map[string]string{}, []string{"env", "team", "owner"}},
+	}
+	for i, testCase := range testCases {
+		kept, removed := removeTagKeys(tags, parseTagKeys(testCase.keys))
+		if !reflect.DeepEqual(kept, testCase.kept) {
+			t.Fatalf("Test %d: expected kept %v, got %v", i+1, testCase.kept, kept)
+		}
+		if !reflect.DeepEqual(removed, testCase.removed) {
+			t.Fatalf("Test %d: expected removed %v, got %v", i+1, testCase.removed, removed)
+		}
+	}
+	if len(tags) != 3 {
+		t.Fatalf("expected the tags to be left unchanged, got %v", tags)
+	}
+}
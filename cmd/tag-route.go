@@ -0,0 +1,129 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+var tagRouteFlag = cli.StringSliceFlag{
+	Name:  "route-by-tag",
+	Usage: "send objects tagged TAG=VALUE below PREFIX of the target and/or to STORAGE-CLASS, as 'TAG=VALUE:PREFIX[:STORAGE-CLASS]'",
+}
+
+// tagRoute sends the objects carrying a tag to another
+// prefix of the target and/or another storage class.
+type tagRoute struct {
+	key, value   string
+	prefix       string
+	storageClass string
+}
+
+// rename returns the target name of an object routed
+// by r, name being relative to the target folder.
+func (r *tagRoute) rename(name string) string {
+	if r.prefix == "" {
+		return name
+	}
+	return strings.TrimSuffix(r.prefix, "/") + "/" + strings.TrimPrefix(name, "/")
+}
+
+// tagRoutes are the routes of --route-by-tag, the first matching one wins.
+type tagRoutes []tagRoute
+
+// parseTagRoutes parses the routes of --route-by-tag, each of the
+// form TAG=VALUE:PREFIX[:STORAGE-CLASS]. PREFIX may be empty to only
+// change the storage class.
+func parseTagRoutes(rules []string) (tagRoutes, *probe.Error) {
+	var routes tagRoutes
+	for _, rule := range rules {
+		match, dest, ok := strings.Cut(rule, ":")
+		key, value, hasValue := strings.Cut(match, "=")
+		if !ok || !hasValue || key == "" {
+			return nil, probe.NewError(fmt.Errorf("route should be of the form TAG=VALUE:PREFIX[:STORAGE-CLASS]")).Trace(rule)
+		}
+		prefix, storageClass, _ := strings.Cut(dest, ":")
+		prefix = strings.TrimPrefix(prefix, "/")
+		if prefix == "" && storageClass == "" {
+			return nil, probe.NewError(fmt.Errorf("route `%s` has neither a prefix nor a storage class", rule))
+		}
+		routes = append(routes, tagRoute{
+			key:          key,
+			value:        value,
+			prefix:       prefix,
+			storageClass: storageClass,
+		})
+	}
+	return routes, nil
+}
+
+// match returns the first route matching tags, nil if none does.
+func (routes tagRoutes) match(tags map[string]string) *tagRoute {
+	for i := range routes {
+		if value, ok := tags[routes[i].key]; ok && value == routes[i].value {
+			return &routes[i]
+		}
+	}
+	return nil
+}
+
+// lookup returns the route of the source object content, fetching
+// its tags when the listing did not carry them. Local files have
+// no tags and are never routed.
+func (routes tagRoutes) lookup(ctx context.Context, alias string, content *ClientContent) (*tagRoute, *probe.Error) {
+	if content.URL.Type != objectStorage {
+		return nil, nil
+	}
+	tags := content.Tags
+	if len(tags) == 0 {
+		clnt, err := newClientFromAlias(alias, content.URL.String())
+		if err != nil {
+			return nil, err.Trace(alias, content.URL.String())
+		}
+		if tags, err = clnt.GetTags(ctx, content.VersionID); err != nil {
+			return nil, err.Trace(content.URL.String())
+		}
+	}
+	return routes.match(tags), nil
+}
+
+// routeTargetByTag moves the target of cpURLs below targetURL to the
+// prefix and storage class of the route matching the source tags.
+func routeTargetByTag(ctx context.Context, cpURLs URLs, targetURL string, routes tagRoutes) URLs {
+	route, err := routes.lookup(ctx, cpURLs.SourceAlias, cpURLs.SourceContent)
+	if err != nil {
+		return cpURLs.WithError(err)
+	}
+	if route == nil {
+		return cpURLs
+	}
+	cpURLs = rewriteTargetName(cpURLs, targetURL, func(name string, _ *ClientContent) (string, *probe.Error) {
+		return route.rename(name), nil
+	})
+	if cpURLs.Error == nil && route.storageClass != "" {
+		targetContent := *cpURLs.TargetContent
+		targetContent.StorageClass = route.storageClass
+		cpURLs.TargetContent = &targetContent
+	}
+	return cpURLs
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+var targetTemplateFlag = cli.StringFlag{
+	Name:  "target-template",
+	Usage: "name targets below TARGET with a template of the source, e.g. '{{.Year}}/{{.Month}}/{{.SourceBase}}'",
+}
+
+// targetTemplateData holds the fields available to --target-template.
+type targetTemplateData struct {
+	Year, Month, Day, Hour string // UTC modification time of the source
+	Key                    string // source name relative to the source folder
+	SourceBase             string // last element of Key
+	SourceDir              string // Key without its last element
+	SourceExt              string // extension of SourceBase, with its dot
+
+	metadata func() (map[string]string, *probe.Error)
+}
+
+// Metadata returns the metadata and user metadata of the source. They
+// are fetched only when the template uses them and the listing of the
+// source did not carry them.
+func (d targetTemplateData) Metadata() (map[string]string, error) {
+	metadata, err := d.metadata()
+	if err != nil {
+		return nil, err.ToGoError()
+	}
+	return metadata, nil
+}
+
+// targetTemplate renders the names of targets of --target-template.
+type targetTemplate struct {
+	tmpl *template.Template
+}
+
+// parseTargetTemplate parses and validates a --target-template value.
+func parseTargetTemplate(text string) (*targetTemplate, *probe.Error) {
+	tmpl, e := template.New("target").Option("missingkey=zero").Parse(text)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(text)
+	}
+	t := &targetTemplate{tmpl: tmpl}
+	// Catch references to unknown fields before any transfer starts.
+	src := &ClientContent{URL: ClientURL{Type: fileSystem}, Time: time.Now()}
+	if _, err := t.render(context.Background(), "", "dir/object.ext", src); err != nil {
+		return nil, err.Trace(text)
+	}
+	return t, nil
+}
+
+// sourceMetadata returns the metadata and user metadata of the source
+// object src of alias, fetching them when the listing did not carry
+// them. Local files have no metadata but the listed one.
+func sourceMetadata(ctx context.Context, alias string, src *ClientContent) (map[string]string, *probe.Error) {
+	if len(src.Metadata) == 0 && len(src.UserMetadata) == 0 && src.URL.Type == objectStorage {
+		clnt, err := newClientFromAlias(alias, src.URL.String())
+		if err != nil {
+			return nil, err.Trace(alias, src.URL.String())
+		}
+		if src, err = clnt.Stat(ctx, StatOptions{versionID: src.VersionID}); err != nil {
+			return nil, err.Trace(src.URL.String())
+		}
+	}
+	metadata := make(map[string]string, len(src.Metadata)+len(src.UserMetadata))
+	for k, v := range src.Metadata {
+		metadata[k] = v
+	}
+	for k, v := range src.UserMetadata {
+		metadata[k] = v
+	}
+	return metadata, nil
+}
+
+// render returns the target name of the source object src of alias,
+// whose name relative to the source folder is key.
+func (t *targetTemplate) render(ctx context.Context, alias, key string, src *ClientContent) (string, *probe.Error) {
+	key = strings.TrimPrefix(key, "/")
+	var (
+		metadata map[string]string
+		err      *probe.Error
+		fetched  bool
+	)
+	data := targetTemplateData{
+		Key:        key,
+		SourceBase: path.Base(key),
+		SourceExt:  path.Ext(key),
+		metadata: func() (map[string]string, *probe.Error) {
+			if !fetched {
+				metadata, err = sourceMetadata(ctx, alias, src)
+				fetched = true
+			}
+			return metadata, err
+		},
+	}
+	data.Year, data.Month, data.Day, data.Hour = modTimeFields(src.Time)
+	if dir := path.Dir(key); dir != "." {
+		data.SourceDir = dir
+	}
+
+	var buf bytes.Buffer
+	if e := t.tmpl.Execute(&buf, data); e != nil {
+		return "", probe.NewError(e).Trace(key)
+	}
+	name := strings.TrimPrefix(buf.String(), "/")
+	if name == "" || strings.HasSuffix(name, "/") {
+		return "", probe.NewError(fmt.Errorf("target template gives `%s` for `%s`, which is not an object name", name, key))
+	}
+	return name, nil
+}
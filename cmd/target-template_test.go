@@ -0,0 +1,97 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+func TestTargetTemplateRender(t *testing.T) {
+	modTime := time.Date(2023, time.March, 4, 5, 6, 7, 0, time.UTC)
+	listed := &ClientContent{
+		URL:          ClientURL{Type: objectStorage},
+		Time:         modTime,
+		Metadata:     map[string]string{"Content-Type": "image/png"},
+		UserMetadata: map[string]string{"X-Amz-Meta-Camera": "x100"},
+	}
+	local := &ClientContent{URL: ClientURL{Type: fileSystem}, Time: modTime}
+
+	testCases := []struct {
+		text     string
+		key      string
+		src      *ClientContent
+		expected string
+	}{
+		{"{{.Year}}/{{.Month}}/{{.Day}}/{{.Hour}}/{{.SourceBase}}", "dir/a.jpg", listed, "2023/03/04/05/a.jpg"},
+		{"{{.SourceDir}}/{{.SourceExt}}/{{.Key}}", "/dir/sub/a.jpg", listed, "dir/sub/.jpg/dir/sub/a.jpg"},
+		{`{{index .Metadata "X-Amz-Meta-Camera"}}/{{index .Metadata "Content-Type"}}/{{.Key}}`, "a.jpg", listed, "x100/image/png/a.jpg"},
+		{`{{with index .Metadata "X-Amz-Meta-Camera"}}{{.}}{{else}}unknown{{end}}/{{.Key}}`, "a.jpg", local, "unknown/a.jpg"},
+	}
+
+	for i, testCase := range testCases {
+		tmpl, err := parseTargetTemplate(testCase.text)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		got, err := tmpl.render(context.Background(), "", testCase.key, testCase.src)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		if got != testCase.expected {
+			t.Fatalf("Test %d: expected %s, got %s", i+1, testCase.expected, got)
+		}
+	}
+}
+
+func TestTargetTemplateMetadataFetch(t *testing.T) {
+	testCases := []struct {
+		text    string
+		fetched bool
+	}{
+		{"{{.Year}}/{{.Key}}", false},
+		{`{{index .Metadata "Content-Type"}}/{{.Key}}`, true},
+	}
+
+	for i, testCase := range testCases {
+		tmpl, err := parseTargetTemplate(testCase.text)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		fetched := false
+		data := targetTemplateData{
+			Year: "2023",
+			Key:  "a.jpg",
+			metadata: func() (map[string]string, *probe.Error) {
+				fetched = true
+				return map[string]string{"Content-Type": "image/jpeg"}, nil
+			},
+		}
+		var buf bytes.Buffer
+		if e := tmpl.tmpl.Execute(&buf, data); e != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, e)
+		}
+		if fetched != testCase.fetched {
+			t.Fatalf("Test %d: expected metadata fetched to be %v, got %v", i+1, testCase.fetched, fetched)
+		}
+	}
+}
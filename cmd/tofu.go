@@ -40,6 +40,7 @@ import (
 	"strings"
 
 	"github.com/fatih/color"
+	"github.com/trinet2005/oss-mc/pkg/i18n"
 	"github.com/trinet2005/oss-mc/pkg/probe"
 )
 
@@ -139,7 +140,7 @@ func promptTrustSelfSignedCert(ctx context.Context, endpoint, alias string) (*x5
 	}
 
 	fingerprint := sha256.Sum256(peerCert.RawSubjectPublicKeyInfo)
-	fmt.Printf("Fingerprint of %s public key: %s\nConfirm public key y/N: ", color.GreenString(alias), color.YellowString(hex.EncodeToString(fingerprint[:])))
+	fmt.Printf(i18n.T("Fingerprint of %s public key: %s\nConfirm public key y/N: "), color.GreenString(alias), color.YellowString(hex.EncodeToString(fingerprint[:])))
 	answer, e := bufio.NewReader(os.Stdin).ReadString('\n')
 	if e != nil {
 		return nil, probe.NewError(e)
@@ -30,6 +30,7 @@ import (
 	"github.com/dustin/go-humanize"
 	"github.com/olekukonko/tablewriter"
 	"github.com/trinet2005/oss-admin-go"
+	"github.com/trinet2005/oss-mc/pkg/i18n"
 )
 
 // TODO: Add ART (Average Response Time) latency
@@ -198,13 +199,13 @@ func (m *traceUI) View() string {
 			totalCalls += stats.loadAPICall()
 		}
 
-		msg := fmt.Sprintf("\nSummary:\n\nTotal: %d CALLS, %s RX, %s TX",
+		msg := fmt.Sprintf(i18n.T("\nSummary:\n\nTotal: %d CALLS, %s RX, %s TX"),
 			totalCalls,
 			humanize.IBytes(totalRX),
 			humanize.IBytes(totalTX),
 		)
 		if !m.startTime.IsZero() {
-			msg += fmt.Sprintf(" - in %.02fs", lastReqTime.Sub(m.startTime).Seconds())
+			msg += fmt.Sprintf(i18n.T(" - in %.02fs"), lastReqTime.Sub(m.startTime).Seconds())
 		}
 
 		s.WriteString(msg)
@@ -25,6 +25,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/fatih/color"
 	"github.com/minio/cli"
 	"github.com/trinet2005/oss-mc/pkg/probe"
@@ -43,6 +44,7 @@ type treeMessage struct {
 	Entry        string
 	IsDir        bool
 	BranchString string
+	Size         *int64
 }
 
 // Colorized message for console printing.
@@ -51,11 +53,15 @@ func (t treeMessage) String() string {
 	if t.IsDir {
 		entryType = "Dir"
 	}
-	return fmt.Sprintf("%s%s", t.BranchString, console.Colorize(entryType, t.Entry))
+	msg := fmt.Sprintf("%s%s", t.BranchString, console.Colorize(entryType, t.Entry))
+	if t.Size != nil {
+		msg += " " + console.Colorize("Size", "["+strings.Join(strings.Fields(humanize.IBytes(uint64(*t.Size))), "")+"]")
+	}
+	return msg
 }
 
 // JSON'ified message for scripting.
-// Does No-op. JSON requests are redirected to `ls -r --json`
+// Does No-op. JSON requests are served by treeNodeMessage.
 func (t treeMessage) JSON() string {
 	fatalIf(probe.NewError(errors.New("JSON() should never be called here")), "Unable to list in tree format. Please report this issue at https://github.com/trinet2005/oss-mc/issues")
 	return ""
@@ -71,6 +77,10 @@ var treeFlags = []cli.Flag{
 		Usage: "sets the depth threshold",
 		Value: -1,
 	},
+	cli.BoolFlag{
+		Name:  "size, s",
+		Usage: "print the size of files and the total size of folders",
+	},
 	cli.StringFlag{
 		Name:  "rewind",
 		Usage: "display tree no later than specified date",
@@ -109,6 +119,12 @@ EXAMPLES:
 
    5. List all directories upto depth level '2' in tree format.
       {{.Prompt}} {{.HelpName}} --depth 2 myminio/mybucket/
+
+   6. List all directories upto depth level '1' with the total size of each one.
+      {{.Prompt}} {{.HelpName}} --depth 1 --size myminio/mybucket/
+
+   7. Print the tree of "mybucket" with its files and sizes as a JSON document.
+      {{.Prompt}} {{.HelpName}} --files --json myminio/mybucket/
 `,
 }
 
@@ -261,9 +277,11 @@ func mainTree(cliCtx *cli.Context) error {
 
 	console.SetColor("File", color.New(color.Bold))
 	console.SetColor("Dir", color.New(color.FgCyan, color.Bold))
+	console.SetColor("Size", color.New(color.FgYellow))
 
 	// parse 'tree' cliCtx arguments.
 	args, depth, includeFiles, timeRef := parseTreeSyntax(ctx, cliCtx)
+	withSize := cliCtx.Bool("size")
 
 	// mimic operating system tool behavior.
 	if len(args) == 0 {
@@ -272,29 +290,14 @@ func mainTree(cliCtx *cli.Context) error {
 
 	var cErr error
 	for _, targetURL := range args {
-		if !globalJSON {
-			if e := doTree(ctx, targetURL, timeRef, 1, "", depth, includeFiles); e != nil {
-				cErr = e
-			}
-		} else {
-			targetAlias, targetURL, _ := mustExpandAlias(targetURL)
-			if !strings.HasSuffix(targetURL, "/") {
-				targetURL += "/"
-			}
-			clnt, err := newClientFromAlias(targetAlias, targetURL)
-			fatalIf(err.Trace(targetURL), "Unable to initialize target `"+targetURL+"`.")
-			opts := doListOptions{
-				timeRef:           timeRef,
-				isRecursive:       true,
-				isIncomplete:      false,
-				isSummary:         false,
-				withOlderVersions: false,
-				listZip:           false,
-				filter:            "*",
-			}
-			if e := doList(ctx, clnt, opts); e != nil {
+		if withSize || globalJSON {
+			if e := doTreeSize(ctx, targetURL, timeRef, depth, includeFiles, withSize); e != nil {
 				cErr = e
 			}
+			continue
+		}
+		if e := doTree(ctx, targetURL, timeRef, 1, "", depth, includeFiles); e != nil {
+			cErr = e
 		}
 	}
 	return cErr
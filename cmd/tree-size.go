@@ -0,0 +1,194 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+)
+
+// treeNode is a folder or a file of a tree built from a recursive
+// listing, folders holding the total size of the files below them.
+type treeNode struct {
+	Name     string      `json:"name"`
+	IsDir    bool        `json:"isDir"`
+	Size     int64       `json:"size"`
+	Objects  int64       `json:"objects,omitempty"`
+	Children []*treeNode `json:"children,omitempty"`
+
+	children map[string]*treeNode
+}
+
+func newTreeFolder(name string) *treeNode {
+	return &treeNode{Name: name, IsDir: true, children: make(map[string]*treeNode)}
+}
+
+// add adds a file, or a folder, at the path split in parts. Nodes
+// deeper than maxLevel are not created, their size is only added to
+// their ancestors; -1 means no limit.
+func (n *treeNode) add(parts []string, isDir bool, size int64, maxLevel int, includeFiles bool) {
+	folders := parts
+	if !isDir {
+		folders = parts[:len(parts)-1]
+		n.Size += size
+		n.Objects++
+	}
+
+	node := n
+	for i, name := range folders {
+		if maxLevel != -1 && i+1 > maxLevel {
+			return
+		}
+		child, ok := node.children[name]
+		if !ok {
+			child = newTreeFolder(name)
+			node.children[name] = child
+		}
+		if !isDir {
+			child.Size += size
+			child.Objects++
+		}
+		node = child
+	}
+
+	if !isDir && includeFiles && (maxLevel == -1 || len(parts) <= maxLevel) {
+		name := parts[len(parts)-1]
+		node.children[name] = &treeNode{Name: name, Size: size}
+	}
+}
+
+// sort fills the children of every folder, sorted by name.
+func (n *treeNode) sort() {
+	n.Children = n.Children[:0]
+	for _, child := range n.children {
+		child.sort()
+		n.Children = append(n.Children, child)
+	}
+	sort.Slice(n.Children, func(i, j int) bool {
+		return n.Children[i].Name < n.Children[j].Name
+	})
+}
+
+// treeNodeMessage is the tree of a target in JSON.
+type treeNodeMessage struct {
+	Status string `json:"status"`
+	URL    string `json:"url"`
+	*treeNode
+}
+
+// String is not used, the text tree is printed line by line.
+func (t treeNodeMessage) String() string {
+	return t.URL
+}
+
+// JSON jsonified tree message
+func (t treeNodeMessage) JSON() string {
+	t.Status = "success"
+	msgBytes, e := json.MarshalIndent(t, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(msgBytes)
+}
+
+// buildTree builds the tree of url from a single recursive listing,
+// up to depth levels below the levels printed by doTree.
+func buildTree(ctx context.Context, url string, timeRef time.Time, depth int, includeFiles bool) (*treeNode, *probe.Error) {
+	targetAlias, targetURL, _ := mustExpandAlias(url)
+	if !strings.HasSuffix(targetURL, "/") {
+		targetURL += "/"
+	}
+
+	clnt, err := newClientFromAlias(targetAlias, targetURL)
+	if err != nil {
+		return nil, err.Trace(url)
+	}
+
+	// Same levels as the listings of doTree.
+	maxLevel := -1
+	if depth != -1 {
+		maxLevel = depth + 1
+	}
+
+	prefixPath := strings.TrimPrefix(filepath.ToSlash(clnt.GetURL().Path), "./")
+	root := newTreeFolder(url)
+	for content := range clnt.List(ctx, ListOptions{Recursive: true, TimeRef: timeRef, ShowDir: DirFirst}) {
+		if content.Err != nil {
+			errorIf(content.Err.Trace(clnt.GetURL().String()), "Unable to tree.")
+			continue
+		}
+		name := strings.TrimPrefix(strings.TrimPrefix(filepath.ToSlash(content.URL.Path), "./"), prefixPath)
+		name = strings.Trim(name, "/")
+		if name == "" {
+			continue
+		}
+		root.add(strings.Split(name, "/"), content.Type.IsDir(), content.Size, maxLevel, includeFiles)
+	}
+	root.sort()
+	return root, nil
+}
+
+// printTree prints the children of node below branchString, with
+// their size if withSize is set.
+func printTree(node *treeNode, branchString string, withSize bool) {
+	for i, child := range node.Children {
+		last := i == len(node.Children)-1
+		entry, next := treeEntry, treeNext+treeLevel
+		if last {
+			entry, next = treeLastEntry, " "+treeLevel
+		}
+		msg := treeMessage{
+			Entry:        child.Name,
+			IsDir:        child.IsDir,
+			BranchString: branchString + entry,
+		}
+		if withSize {
+			msg.Size = &child.Size
+		}
+		printMsg(msg)
+		printTree(child, branchString+next, withSize)
+	}
+}
+
+// doTreeSize prints the tree of url, annotated with the sizes of the
+// folders and files, or the tree in JSON.
+func doTreeSize(ctx context.Context, url string, timeRef time.Time, depth int, includeFiles, withSize bool) error {
+	root, err := buildTree(ctx, url, timeRef, depth, includeFiles)
+	if err != nil {
+		errorIf(err, "Unable to tree `"+url+"`.")
+		return exitStatus(globalErrorExitStatus)
+	}
+
+	if globalJSON {
+		printMsg(treeNodeMessage{URL: url, treeNode: root})
+		return nil
+	}
+
+	msg := treeMessage{Entry: url, IsDir: true}
+	if withSize {
+		msg.Size = &root.Size
+	}
+	printMsg(msg)
+	printTree(root, "", withSize)
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// treeString renders a tree as "name:size(child ...)".
+func treeString(n *treeNode) string {
+	s := n.Name + ":" + strconv.FormatInt(n.Size, 10)
+	if len(n.Children) == 0 {
+		return s
+	}
+	var children []string
+	for _, child := range n.Children {
+		children = append(children, treeString(child))
+	}
+	return s + "(" + strings.Join(children, " ") + ")"
+}
+
+func TestTreeNodeAdd(t *testing.T) {
+	files := []struct {
+		name string
+		size int64
+	}{
+		{"a/b/c/file1", 1},
+		{"a/b/file2", 2},
+		{"a/file3", 4},
+		{"d/file4", 8},
+		{"file5", 16},
+	}
+
+	testCases := []struct {
+		maxLevel     int
+		includeFiles bool
+		expected     string
+	}{
+		{-1, false, "root:31(a:7(b:3(c:1)) d:8)"},
+		{-1, true, "root:31(a:7(b:3(c:1(file1:1) file2:2) file3:4) d:8(file4:8) file5:16)"},
+		{1, true, "root:31(a:7 d:8 file5:16)"},
+		{2, true, "root:31(a:7(b:3 file3:4) d:8(file4:8) file5:16)"},
+		{2, false, "root:31(a:7(b:3) d:8)"},
+	}
+
+	for i, testCase := range testCases {
+		root := newTreeFolder("root")
+		for _, file := range files {
+			root.add(strings.Split(file.name, "/"), false, file.size, testCase.maxLevel, testCase.includeFiles)
+		}
+		root.sort()
+		if s := treeString(root); s != testCase.expected {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, s)
+		}
+	}
+}
@@ -126,3 +126,10 @@ var errConflictSSE = func(sseServer, sseKeys string) *probe.Error {
 	err := fmt.Errorf("SSE alias '%s' overlaps with SSE-C aliases '%s'", sseServer, sseKeys)
 	return probe.NewError(conflictSSEErr(err)).Untrace()
 }
+
+type tagsNotPreservedErr error
+
+var errTagsNotPreserved = func() *probe.Error {
+	msg := "object tags are not supported by the filesystem"
+	return probe.NewError(tagsNotPreservedErr(errors.New(msg))).Untrace()
+}
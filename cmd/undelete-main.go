@@ -0,0 +1,277 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+var undeleteFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "recursive, r",
+		Usage: "undelete all objects under the prefix",
+	},
+	cli.StringFlag{
+		Name:  "older-than",
+		Usage: "undelete objects deleted earlier than value in duration string (e.g. 7d10h31s)",
+	},
+	cli.StringFlag{
+		Name:  "newer-than",
+		Usage: "undelete objects deleted later than value in duration string (e.g. 7d10h31s)",
+	},
+	cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "list the delete markers that would be removed, without removing them",
+	},
+}
+
+var undeleteCmd = cli.Command{
+	Name:         "undelete",
+	Usage:        "restore deleted objects by removing their delete markers",
+	Action:       mainUndelete,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(undeleteFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Undelete removes the delete markers which are the latest version of objects in a versioned
+  bucket, so that their previous version becomes the latest version again. Objects whose previous
+  version is also a delete marker stay deleted.
+
+EXAMPLES:
+  1. Restore a deleted object.
+     {{.Prompt}} {{.HelpName}} s3/docs/money.xls
+
+  2. Restore all objects deleted under a prefix.
+     {{.Prompt}} {{.HelpName}} --recursive s3/docs/2023/
+
+  3. Restore all objects deleted during the last 2 hours.
+     {{.Prompt}} {{.HelpName}} --recursive --newer-than 2h s3/docs/
+
+  4. List the objects that would be restored, without restoring them.
+     {{.Prompt}} {{.HelpName}} --recursive --dry-run s3/docs/
+`,
+}
+
+// undeleteMessage container for undelete message structure.
+type undeleteMessage struct {
+	Status            string    `json:"status"`
+	Key               string    `json:"key"`
+	VersionID         string    `json:"versionId"`
+	DeletedAt         time.Time `json:"deletedAt"`
+	RestoredVersionID string    `json:"restoredVersionId,omitempty"`
+	DryRun            bool      `json:"dryRun,omitempty"`
+}
+
+// String colorized string message.
+func (u undeleteMessage) String() string {
+	msg := "Restored "
+	if u.DryRun {
+		msg = "DRYRUN: Restoring "
+	}
+	msg += console.Colorize("Undeleted", fmt.Sprintf("`%s`", u.Key))
+	if u.RestoredVersionID != "" {
+		msg += fmt.Sprintf(" (versionId=%s)", u.RestoredVersionID)
+	} else {
+		msg += " (no previous version)"
+	}
+	msg += fmt.Sprintf(", deleted at %s.", u.DeletedAt.Format(printDate))
+	return msg
+}
+
+// JSON jsonified undelete message.
+func (u undeleteMessage) JSON() string {
+	u.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(u, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// undeleteFinder finds the latest delete markers in a listing of object
+// versions, sorted newest first per object, with the version that each
+// delete marker hides.
+type undeleteFinder struct {
+	marker *ClientContent
+}
+
+// next consumes content, it returns a delete marker found earlier and the
+// version it hides, nil if there is none or if it is a delete marker too.
+func (f *undeleteFinder) next(content *ClientContent) (marker, restored *ClientContent) {
+	if f.marker != nil {
+		marker = f.marker
+		f.marker = nil
+		if content.URL.Path == marker.URL.Path {
+			if !content.IsDeleteMarker {
+				restored = content
+			}
+			return marker, restored
+		}
+	}
+	if content.IsLatest && content.IsDeleteMarker {
+		f.marker = content
+	}
+	return marker, nil
+}
+
+// flush returns the last delete marker found, if any, at the end of the listing.
+func (f *undeleteFinder) flush() (marker *ClientContent) {
+	marker, f.marker = f.marker, nil
+	return marker
+}
+
+func checkUndeleteSyntax(cliCtx *cli.Context) {
+	if len(cliCtx.Args()) != 1 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	for _, flag := range []string{"older-than", "newer-than"} {
+		if v := cliCtx.String(flag); v != "" {
+			_, e := ParseDuration(v)
+			fatalIf(probe.NewError(e).Trace(v), "Unable to parse --"+flag+".")
+		}
+	}
+}
+
+// mainUndelete is the main entry point for undelete command.
+func mainUndelete(cliCtx *cli.Context) error {
+	ctx, cancelUndelete := context.WithCancel(globalContext)
+	defer cancelUndelete()
+
+	checkUndeleteSyntax(cliCtx)
+
+	console.SetColor("Undeleted", color.New(color.FgGreen, color.Bold))
+
+	aliasedURL := cliCtx.Args().Get(0)
+	isRecursive := cliCtx.Bool("recursive")
+	olderThan := cliCtx.String("older-than")
+	newerThan := cliCtx.String("newer-than")
+	isFake := cliCtx.Bool("dry-run")
+
+	clnt, err := newClient(aliasedURL)
+	fatalIf(err.Trace(aliasedURL), "Unable to initialize target `"+aliasedURL+"`.")
+	if clnt.GetURL().Type != objectStorage {
+		fatalIf(errInvalidArgument().Trace(aliasedURL), "Undelete works only with versioned buckets.")
+	}
+	alias, _, _ := mustExpandAlias(aliasedURL)
+
+	// Messages of the delete markers being removed, by version ID,
+	// printed once they are removed.
+	var mu sync.Mutex
+	pending := make(map[string]undeleteMessage)
+
+	contentCh := make(chan *ClientContent)
+	resultCh := clnt.Remove(ctx, false, false, false, false, contentCh)
+
+	var listErr error
+	found := 0
+	go func() {
+		defer close(contentCh)
+
+		undelete := func(marker, restored *ClientContent) {
+			// Skip delete markers outside of --older-than and --newer-than.
+			if isOlder(marker.Time, olderThan) || isNewer(marker.Time, newerThan) {
+				return
+			}
+			found++
+			msg := undeleteMessage{
+				Key:       alias + getKey(marker),
+				VersionID: marker.VersionID,
+				DeletedAt: marker.Time,
+				DryRun:    isFake,
+			}
+			if restored != nil {
+				msg.RestoredVersionID = restored.VersionID
+			}
+			if isFake {
+				printMsg(msg)
+				return
+			}
+			mu.Lock()
+			pending[marker.VersionID] = msg
+			mu.Unlock()
+			contentCh <- marker
+		}
+
+		var finder undeleteFinder
+		for content := range clnt.List(ctx, ListOptions{
+			Recursive:         isRecursive,
+			WithOlderVersions: true,
+			WithDeleteMarkers: true,
+			ShowDir:           DirNone,
+		}) {
+			if content.Err != nil {
+				errorIf(content.Err.Trace(aliasedURL), "Unable to list `"+aliasedURL+"`.")
+				listErr = exitStatus(globalErrorExitStatus)
+				return
+			}
+			if !isRecursive && alias+getKey(content) != getStandardizedURL(aliasedURL) {
+				break
+			}
+			if marker, restored := finder.next(content); marker != nil {
+				undelete(marker, restored)
+			}
+		}
+		if marker := finder.flush(); marker != nil {
+			undelete(marker, nil)
+		}
+	}()
+
+	var undeleteErr error
+	for result := range resultCh {
+		key := path.Join(alias, result.BucketName, result.ObjectName)
+		if result.Err != nil {
+			errorIf(result.Err.Trace(key), "Unable to undelete `"+key+"`.")
+			undeleteErr = exitStatus(globalErrorExitStatus)
+			continue
+		}
+		mu.Lock()
+		msg, ok := pending[result.ObjectVersionID]
+		delete(pending, result.ObjectVersionID)
+		mu.Unlock()
+		if ok {
+			printMsg(msg)
+		}
+	}
+
+	if listErr != nil {
+		return listErr
+	}
+	if found == 0 {
+		errorIf(errDummy().Trace(aliasedURL), "No deleted object found in `"+aliasedURL+"`.")
+		return exitStatus(globalErrorExitStatus)
+	}
+	return undeleteErr
+}
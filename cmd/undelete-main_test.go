@@ -0,0 +1,78 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import "testing"
+
+func TestUndeleteFinder(t *testing.T) {
+	version := func(path, versionID string, isLatest, isDeleteMarker bool) *ClientContent {
+		return &ClientContent{
+			URL:            ClientURL{Path: path},
+			VersionID:      versionID,
+			IsLatest:       isLatest,
+			IsDeleteMarker: isDeleteMarker,
+		}
+	}
+
+	listing := []*ClientContent{
+		// Deleted, restores v2.
+		version("/b/a", "dm1", true, true),
+		version("/b/a", "v2", false, false),
+		version("/b/a", "v1", false, false),
+		// Not deleted.
+		version("/b/b", "v3", true, false),
+		version("/b/b", "dm2", false, true),
+		// Deleted twice, stays deleted.
+		version("/b/c", "dm3", true, true),
+		version("/b/c", "dm4", false, true),
+		version("/b/c", "v4", false, false),
+		// Deleted, with no previous version.
+		version("/b/d", "dm5", true, true),
+		// Deleted, restores v5, last of the listing.
+		version("/b/e", "dm6", true, true),
+		version("/b/e", "v5", false, false),
+	}
+	expected := [][2]string{{"dm1", "v2"}, {"dm3", ""}, {"dm5", ""}, {"dm6", "v5"}}
+
+	var found [][2]string
+	add := func(marker, restored *ClientContent) {
+		if marker == nil {
+			return
+		}
+		r := [2]string{marker.VersionID, ""}
+		if restored != nil {
+			r[1] = restored.VersionID
+		}
+		found = append(found, r)
+	}
+
+	var finder undeleteFinder
+	for _, content := range listing {
+		add(finder.next(content))
+	}
+	add(finder.flush(), nil)
+
+	if len(found) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, found)
+	}
+	for i := range expected {
+		if found[i] != expected[i] {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, expected[i], found[i])
+		}
+	}
+}
@@ -31,9 +31,20 @@ type URLs struct {
 	TotalSize        int64
 	MD5              bool
 	DisableMultipart bool
+	MultipartSize    uint64
+	MultipartThreads uint
+	IfNotExists      bool
+	ParallelRanges   uint
+	RangeSize        int64
 	encKeyDB         map[string][]prefixSSEPair
-	Error            *probe.Error `json:"-"`
-	ErrorCond        differType   `json:"-"`
+	cseKey           *cseKey        // key wrapping the data key of client-side encrypted uploads
+	conflictContent  *ClientContent // existing target to be renamed before overwrite
+	diff             differType     // difference which caused the transfer
+	serverSideCopy   bool           // copied by the server, the data did not go through the client
+	skipped          bool           // not copied, the target exists and IfNotExists is set
+	refresh          bool           // only the attributes of the existing target are refreshed
+	Error            *probe.Error   `json:"-"`
+	ErrorCond        differType     `json:"-"`
 }
 
 // WithError sets the error and returns object
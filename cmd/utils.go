@@ -20,6 +20,7 @@ package cmd
 import (
 	"bytes"
 	"crypto/tls"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"math"
@@ -209,6 +210,7 @@ func getLookupType(l string) minio.BucketLookupType {
 type prefixSSEPair struct {
 	Prefix string
 	SSE    encrypt.ServerSide
+	NewSSE encrypt.ServerSide // SSE of written objects, when rotating keys
 }
 
 // parse and validate encryption keys entered on command line
@@ -288,6 +290,86 @@ func parseEncryptionKeys(sseKeys string) (encMap map[string][]prefixSSEPair, err
 	return encMap, nil
 }
 
+// parseEncryptionKeyFile parses a file of SSE-C keys, one alias/prefix=key
+// per line. A line alias/prefix=oldkey=newkey rotates the key of the
+// prefix: objects are read with the old key and written with the new one.
+// Keys are 32 bytes plain text or 44 bytes base64 encoded, empty lines and
+// lines starting with # are ignored.
+func parseEncryptionKeyFile(keyFile string) (encMap map[string][]prefixSSEPair, err *probe.Error) {
+	data, e := os.ReadFile(keyFile)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(keyFile)
+	}
+
+	encMap = make(map[string][]prefixSSEPair)
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		prefix, keys, ok := strings.Cut(line, "=")
+		if !ok || prefix == "" {
+			return nil, probe.NewError(fmt.Errorf("line %d: SSE-C keys should be of the form prefix=key or prefix=oldkey=newkey", i+1)).Trace(keyFile)
+		}
+		pair, err := parseSSECKeys(prefix, keys)
+		if err != nil {
+			return nil, err.Trace(keyFile, strconv.Itoa(i+1))
+		}
+		alias, _ := url2Alias(prefix)
+		encMap[alias] = append(encMap[alias], pair)
+	}
+
+	for _, encKeys := range encMap {
+		sort.Sort(byPrefixLength(encKeys))
+	}
+	return encMap, nil
+}
+
+// parseSSECKeys parses a key or an oldkey=newkey rotation of prefix.
+func parseSSECKeys(prefix, keys string) (prefixSSEPair, *probe.Error) {
+	pair := prefixSSEPair{Prefix: prefix}
+
+	// A base64 encoded key may end with '=', try both key lengths.
+	for _, n := range []int{32, 44} {
+		if len(keys) != n && (len(keys) <= n || keys[n] != '=') {
+			continue
+		}
+		oldKey, e := decodeSSECKey(keys[:n])
+		if e != nil {
+			continue
+		}
+		pair.SSE, e = encrypt.NewSSEC(oldKey)
+		if e != nil {
+			return pair, probe.NewError(e)
+		}
+		if len(keys) == n {
+			return pair, nil
+		}
+		newKey, e := decodeSSECKey(keys[n+1:])
+		if e != nil {
+			return pair, probe.NewError(e)
+		}
+		pair.NewSSE, e = encrypt.NewSSEC(newKey)
+		if e != nil {
+			return pair, probe.NewError(e)
+		}
+		return pair, nil
+	}
+	return pair, probe.NewError(errors.New("Encryption key should be 32 bytes plain text key or 44 bytes base64 encoded key"))
+}
+
+// decodeSSECKey returns a 32 bytes plain text or 44 bytes base64 encoded key.
+func decodeSSECKey(key string) ([]byte, error) {
+	if len(key) == 32 {
+		return []byte(key), nil
+	}
+	decoded, e := base64.StdEncoding.DecodeString(key)
+	if e != nil || len(decoded) != 32 {
+		return nil, errors.New("Encryption key should be 32 bytes plain text key or 44 bytes base64 encoded key")
+	}
+	return decoded, nil
+}
+
 // byPrefixLength implements sort.Interface.
 type byPrefixLength []prefixSSEPair
 
@@ -307,6 +389,35 @@ func getSSE(resource string, encKeys []prefixSSEPair) encrypt.ServerSide {
 	return nil
 }
 
+// getTargetSSE returns the SSE Key to write an object matching the given
+// resource, the new key of a rotated prefix.
+func getTargetSSE(resource string, encKeys []prefixSSEPair) encrypt.ServerSide {
+	for _, k := range encKeys {
+		if strings.HasPrefix(resource, k.Prefix) {
+			if k.NewSSE != nil {
+				return k.NewSSE
+			}
+			return k.SSE
+		}
+	}
+	return nil
+}
+
+// getReadSSEs returns the SSE keys to try, in order, to read an existing
+// object matching the given resource: the new key of a rotated prefix,
+// for the objects already rotated, then the old one.
+func getReadSSEs(resource string, encKeys []prefixSSEPair) []encrypt.ServerSide {
+	for _, k := range encKeys {
+		if strings.HasPrefix(resource, k.Prefix) {
+			if k.NewSSE != nil {
+				return []encrypt.ServerSide{k.NewSSE, k.SSE}
+			}
+			return []encrypt.ServerSide{k.SSE}
+		}
+	}
+	return []encrypt.ServerSide{nil}
+}
+
 // Return true if target url is a part of a source url such as:
 // alias/bucket/ and alias/bucket/dir/, however
 func isURLContains(srcURL, tgtURL, sep string) bool {
@@ -101,6 +101,75 @@ func TestParseEncryptionKeys(t *testing.T) {
 	}
 }
 
+func TestParseSSECKeys(t *testing.T) {
+	sseKey1, err := encrypt.NewSSEC([]byte("32byteslongsecretkeymustbegiven1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sseKey2, err := encrypt.NewSSEC([]byte("32byteslongsecretkeymustbegiven2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		keys         string
+		expectedPair prefixSSEPair
+		success      bool
+	}{
+		{"32byteslongsecretkeymustbegiven1", prefixSSEPair{Prefix: "myminio1/test", SSE: sseKey1}, true},
+		{"MzJieXRlc2xvbmdzZWNyZXRrZXltdXN0YmVnaXZlbjI=", prefixSSEPair{Prefix: "myminio1/test", SSE: sseKey2}, true},
+		{"32byteslongsecretkeymustbegiven1=32byteslongsecretkeymustbegiven2", prefixSSEPair{Prefix: "myminio1/test", SSE: sseKey1, NewSSE: sseKey2}, true},
+		{"MzJieXRlc2xvbmdzZWNyZXRrZXltdXN0YmVnaXZlbjI==32byteslongsecretkeymustbegiven1", prefixSSEPair{Prefix: "myminio1/test", SSE: sseKey2, NewSSE: sseKey1}, true},
+		{"32byteslongsecretkeymustbegiven", prefixSSEPair{}, false},
+		{"32byteslongsecretkeymustbegiven1=tooshort", prefixSSEPair{}, false},
+	}
+	for i, testCase := range testCases {
+		pair, err := parseSSECKeys("myminio1/test", testCase.keys)
+		if err != nil && testCase.success {
+			t.Fatalf("Test %d: Expected success, got %s", i+1, err)
+		}
+		if err == nil && !testCase.success {
+			t.Fatalf("Test %d: Expected error, got success", i+1)
+		}
+		if testCase.success && !reflect.DeepEqual(pair, testCase.expectedPair) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expectedPair, pair)
+		}
+	}
+}
+
+func TestGetReadSSEs(t *testing.T) {
+	oldKey, err := encrypt.NewSSEC([]byte("32byteslongsecretkeymustbegiven1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	newKey, err := encrypt.NewSSEC([]byte("32byteslongsecretkeymustbegiven2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	encKeys := []prefixSSEPair{
+		{Prefix: "myminio/rotated/", SSE: oldKey, NewSSE: newKey},
+		{Prefix: "myminio/fixed/", SSE: oldKey},
+	}
+	testCases := []struct {
+		resource    string
+		expected    []encrypt.ServerSide
+		expectedTgt encrypt.ServerSide
+	}{
+		// Objects of a rotated prefix are written with the new key, and
+		// read with the new key first then with the old one.
+		{"myminio/rotated/object", []encrypt.ServerSide{newKey, oldKey}, newKey},
+		{"myminio/fixed/object", []encrypt.ServerSide{oldKey}, oldKey},
+		{"myminio/other/object", []encrypt.ServerSide{nil}, nil},
+	}
+	for i, testCase := range testCases {
+		if sses := getReadSSEs(testCase.resource, encKeys); !reflect.DeepEqual(sses, testCase.expected) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expected, sses)
+		}
+		if sse := getTargetSSE(testCase.resource, encKeys); !reflect.DeepEqual(sse, testCase.expectedTgt) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expectedTgt, sse)
+		}
+	}
+}
+
 func TestParseAttribute(t *testing.T) {
 	metaDataCases := []struct {
 		input  string
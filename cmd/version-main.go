@@ -23,6 +23,7 @@ var versionSubcommands = []cli.Command{
 	versionEnableCmd,
 	versionSuspendCmd,
 	versionInfoCmd,
+	versionRestoreCmd,
 }
 
 var versionCmd = cli.Command{
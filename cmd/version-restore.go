@@ -0,0 +1,235 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+var versionRestoreFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "version-id, vid",
+		Usage: "restore a specific version of an object",
+	},
+	cli.StringFlag{
+		Name:  "rewind",
+		Usage: "restore the version of object(s) which was the latest at the specified time",
+	},
+	cli.BoolFlag{
+		Name:  "recursive, r",
+		Usage: "restore all objects under the prefix, with --rewind",
+	},
+}
+
+var versionRestoreCmd = cli.Command{
+	Name:         "restore",
+	Usage:        "promote a noncurrent version of object(s) to the latest version",
+	Action:       mainVersionRestore,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(versionRestoreFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Restore copies a noncurrent version of an object on top of it, as its new latest version.
+  The versions in between are kept. Objects whose version to restore is already the latest
+  version, or was a delete marker, are left untouched.
+
+EXAMPLES:
+  1. Restore a specific version of an object.
+     {{.Prompt}} {{.HelpName}} --version-id "f20f3792-4bd4-4288-8d3c-b9d05b3b62f6" myminio/mybucket/money.xls
+
+  2. Restore an object as it was one day ago.
+     {{.Prompt}} {{.HelpName}} --rewind 1d myminio/mybucket/money.xls
+
+  3. Restore all objects under a prefix as they were on a given date.
+     {{.Prompt}} {{.HelpName}} --recursive --rewind "2023.01.31" myminio/mybucket/docs/
+`,
+}
+
+// versionRestoreMessage container for version restore message structure.
+type versionRestoreMessage struct {
+	Status    string    `json:"status"`
+	Key       string    `json:"key"`
+	VersionID string    `json:"versionId"`
+	ModTime   time.Time `json:"modTime"`
+}
+
+func (v versionRestoreMessage) String() string {
+	return console.Colorize("versionRestoreMessage",
+		fmt.Sprintf("Restored `%s` (versionId=%s, modTime=%s).", v.Key, v.VersionID, v.ModTime.Format(printDate)))
+}
+
+func (v versionRestoreMessage) JSON() string {
+	v.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(v, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// checkVersionRestoreSyntax - validate all the passed arguments
+func checkVersionRestoreSyntax(cliCtx *cli.Context) {
+	if len(cliCtx.Args()) != 1 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	versionID := cliCtx.String("version-id")
+	rewind := cliCtx.String("rewind")
+	if (versionID == "") == (rewind == "") {
+		fatalIf(errInvalidArgument().Trace(), "Exactly one of --version-id and --rewind should be specified.")
+	}
+	if versionID != "" && cliCtx.Bool("recursive") {
+		fatalIf(errInvalidArgument().Trace(), "--version-id cannot be used with --recursive, use --rewind instead.")
+	}
+}
+
+// versionToRestore returns the version of an object, among its versions
+// sorted newest first, that was the latest at timeRef. It returns nil if
+// the object did not exist or was deleted at that time, or if that version
+// is still the latest.
+func versionToRestore(versions []*ClientContent, timeRef time.Time) *ClientContent {
+	for i, version := range versions {
+		if version.Time.After(timeRef) {
+			continue
+		}
+		if i == 0 || version.IsDeleteMarker {
+			return nil
+		}
+		return version
+	}
+	return nil
+}
+
+// restoreVersion copies version on top of its object.
+func restoreVersion(ctx context.Context, alias string, version *ClientContent) *probe.Error {
+	clnt, err := newClientFromAlias(alias, version.URL.String())
+	if err != nil {
+		return err
+	}
+	err = clnt.Copy(ctx, version.URL.Path, CopyOptions{
+		versionID: version.VersionID,
+		size:      version.Size,
+	}, nil)
+	if err != nil {
+		return err
+	}
+	printMsg(versionRestoreMessage{
+		Key:       alias + getKey(version),
+		VersionID: version.VersionID,
+		ModTime:   version.Time,
+	})
+	return nil
+}
+
+// restoreVersionID restores a specific version of a single object.
+func restoreVersionID(ctx context.Context, aliasedURL, versionID string) error {
+	clnt, err := newClient(aliasedURL)
+	fatalIf(err.Trace(aliasedURL), "Unable to initialize target `"+aliasedURL+"`.")
+	alias, _, _ := mustExpandAlias(aliasedURL)
+
+	version, err := clnt.Stat(ctx, StatOptions{versionID: versionID})
+	fatalIf(err.Trace(aliasedURL, versionID), "Unable to stat `"+aliasedURL+"` (versionId="+versionID+").")
+
+	// The latest version may be a delete marker, which cannot be stat'ed.
+	if latest, err := clnt.Stat(ctx, StatOptions{}); err == nil && latest.VersionID == version.VersionID {
+		fatalIf(errDummy().Trace(aliasedURL, versionID), "Version `"+versionID+"` of `"+aliasedURL+"` is already the latest version.")
+	}
+
+	if err = restoreVersion(ctx, alias, version); err != nil {
+		errorIf(err.Trace(aliasedURL, versionID), "Unable to restore `"+aliasedURL+"` (versionId="+versionID+").")
+		return exitStatus(globalErrorExitStatus)
+	}
+	return nil
+}
+
+// restoreRewind restores the object(s) as they were at timeRef.
+func restoreRewind(ctx context.Context, aliasedURL string, timeRef time.Time, isRecursive bool) error {
+	clnt, err := newClient(aliasedURL)
+	fatalIf(err.Trace(aliasedURL), "Unable to initialize target `"+aliasedURL+"`.")
+	alias, _, _ := mustExpandAlias(aliasedURL)
+
+	var restoreErr error
+	restore := func(versions []*ClientContent) {
+		version := versionToRestore(versions, timeRef)
+		if version == nil {
+			return
+		}
+		if err := restoreVersion(ctx, alias, version); err != nil {
+			key := alias + getKey(version)
+			errorIf(err.Trace(key, version.VersionID), "Unable to restore `"+key+"` (versionId="+version.VersionID+").")
+			restoreErr = exitStatus(globalErrorExitStatus)
+		}
+	}
+
+	var (
+		lastPath          string
+		perObjectVersions []*ClientContent
+	)
+	for content := range clnt.List(ctx, ListOptions{
+		Recursive:         isRecursive,
+		WithOlderVersions: true,
+		WithDeleteMarkers: true,
+		ShowDir:           DirNone,
+	}) {
+		if content.Err != nil {
+			errorIf(content.Err.Trace(aliasedURL), "Unable to list `"+aliasedURL+"`.")
+			return exitStatus(globalErrorExitStatus)
+		}
+		if !isRecursive && alias+getKey(content) != getStandardizedURL(aliasedURL) {
+			break
+		}
+		if content.URL.Path != lastPath {
+			restore(perObjectVersions)
+			lastPath = content.URL.Path
+			perObjectVersions = nil
+		}
+		perObjectVersions = append(perObjectVersions, content)
+	}
+	restore(perObjectVersions)
+
+	return restoreErr
+}
+
+func mainVersionRestore(cliCtx *cli.Context) error {
+	ctx, cancelVersionRestore := context.WithCancel(globalContext)
+	defer cancelVersionRestore()
+
+	console.SetColor("versionRestoreMessage", color.New(color.FgGreen))
+
+	checkVersionRestoreSyntax(cliCtx)
+
+	aliasedURL := cliCtx.Args().Get(0)
+	if versionID := cliCtx.String("version-id"); versionID != "" {
+		return restoreVersionID(ctx, aliasedURL, versionID)
+	}
+	return restoreRewind(ctx, aliasedURL, parseRewindFlag(cliCtx.String("rewind")), cliCtx.Bool("recursive"))
+}
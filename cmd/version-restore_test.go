@@ -0,0 +1,68 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVersionToRestore(t *testing.T) {
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	version := func(versionID string, daysAgo int, isDeleteMarker bool) *ClientContent {
+		return &ClientContent{
+			VersionID:      versionID,
+			Time:           now.AddDate(0, 0, -daysAgo),
+			IsDeleteMarker: isDeleteMarker,
+		}
+	}
+	versions := []*ClientContent{
+		version("v4", 1, false),
+		version("dm", 3, true),
+		version("v2", 5, false),
+		version("v1", 10, false),
+	}
+
+	testCases := []struct {
+		versions []*ClientContent
+		daysAgo  int
+		expected string
+	}{
+		// v4 is still the latest.
+		{versions, 0, ""},
+		// The object was deleted.
+		{versions, 2, ""},
+		{versions, 4, "v2"},
+		{versions, 5, "v2"},
+		{versions, 7, "v1"},
+		{versions, 10, "v1"},
+		// The object did not exist.
+		{versions, 11, ""},
+		{nil, 1, ""},
+	}
+
+	for i, testCase := range testCases {
+		var versionID string
+		if v := versionToRestore(testCase.versions, now.AddDate(0, 0, -testCase.daysAgo)); v != nil {
+			versionID = v.VersionID
+		}
+		if versionID != testCase.expected {
+			t.Fatalf("Test %d: expected %q, got %q", i+1, testCase.expected, versionID)
+		}
+	}
+}
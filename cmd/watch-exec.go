@@ -0,0 +1,213 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	json "github.com/minio/colorjson"
+	"github.com/trinet2005/oss-mc/pkg/probe"
+	"github.com/trinet2005/oss-pkg/console"
+)
+
+// watchExecEvent is the data available to the --exec-template
+// command line for every event.
+type watchExecEvent struct {
+	Bucket   string
+	Key      string
+	Path     string
+	Type     string
+	Size     int64
+	Time     string
+	Metadata map[string]string
+}
+
+// newWatchExecEvent returns the template data of an event. For object
+// storage the key is the object name, for local directories the path.
+func newWatchExecEvent(event EventInfo) watchExecEvent {
+	ev := watchExecEvent{
+		Key:      event.Path,
+		Path:     event.Path,
+		Type:     string(event.Type),
+		Size:     event.Size,
+		Time:     event.Time,
+		Metadata: event.UserMetadata,
+	}
+	if u := newClientURL(event.Path); u.Type == objectStorage {
+		ev.Bucket, ev.Key = url2BucketAndObject(u)
+	}
+	return ev
+}
+
+// watchExecArgv returns the command line run for an event.
+func watchExecArgv(args []findExecArg, ev watchExecEvent) ([]string, error) {
+	argv := make([]string, len(args))
+	for i, arg := range args {
+		argv[i] = arg.text
+		if arg.tmpl == nil {
+			continue
+		}
+		var buf bytes.Buffer
+		if e := arg.tmpl.Execute(&buf, ev); e != nil {
+			return nil, e
+		}
+		argv[i] = buf.String()
+	}
+	return argv, nil
+}
+
+// watchExecMessage reports the command run for an event.
+type watchExecMessage struct {
+	Status     string   `json:"status"`
+	Path       string   `json:"path"`
+	Type       string   `json:"type"`
+	Command    []string `json:"command,omitempty"`
+	Output     string   `json:"output,omitempty"`
+	ExitStatus int      `json:"exitStatus"`
+	TimedOut   bool     `json:"timedOut,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// String colorized watch exec message
+func (m watchExecMessage) String() string {
+	if m.Error == "" {
+		return strings.TrimSuffix(m.Output, "\n")
+	}
+	msg := fmt.Sprintf("Command for `%s` (%s) failed: %s", m.Path, m.Type, m.Error)
+	if m.Output != "" {
+		msg = strings.TrimSuffix(m.Output, "\n") + "\n" + msg
+	}
+	return console.Colorize("WatchExecErr", msg)
+}
+
+// JSON jsonified watch exec message
+func (m watchExecMessage) JSON() string {
+	m.Status = "success"
+	if m.Error != "" {
+		m.Status = "error"
+	}
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(msgBytes)
+}
+
+// watchExecutor runs the --exec-template command line for every event
+// with a bounded pool of workers. Events are not read from the server
+// while all the workers are busy.
+type watchExecutor struct {
+	args    []findExecArg
+	timeout time.Duration
+
+	jobs chan EventInfo
+	wg   sync.WaitGroup
+
+	mu     sync.Mutex // serializes the output of the commands
+	failed int64
+}
+
+// newWatchExecutor starts workers running cmdline for every submitted event.
+func newWatchExecutor(ctx context.Context, cmdline string, workers int, timeout time.Duration) (*watchExecutor, *probe.Error) {
+	args, err := parseFindExec(cmdline)
+	if err != nil {
+		return nil, err
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	x := &watchExecutor{
+		args:    args,
+		timeout: timeout,
+		jobs:    make(chan EventInfo),
+	}
+	for i := 0; i < workers; i++ {
+		x.wg.Add(1)
+		go func() {
+			defer x.wg.Done()
+			for event := range x.jobs {
+				x.run(ctx, event)
+			}
+		}()
+	}
+	return x, nil
+}
+
+// submit queues the command for an event, blocking while all workers are busy.
+func (x *watchExecutor) submit(event EventInfo) {
+	x.jobs <- event
+}
+
+// run executes the command line for an event.
+func (x *watchExecutor) run(ctx context.Context, event EventInfo) {
+	msg := watchExecMessage{Path: event.Path, Type: string(event.Type)}
+	argv, e := watchExecArgv(x.args, newWatchExecEvent(event))
+	if e != nil {
+		msg.Error = e.Error()
+		x.done(msg)
+		return
+	}
+	msg.Command = argv
+
+	if x.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, x.timeout)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	var out, stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	e = cmd.Run()
+	msg.Output = out.String()
+	if e != nil {
+		msg.ExitStatus = getExitStatus(e)
+		msg.Error = e.Error()
+		if ctx.Err() == context.DeadlineExceeded {
+			msg.TimedOut = true
+			msg.Error = "timed out after " + x.timeout.String()
+		} else if stderr.Len() > 0 {
+			msg.Error = strings.TrimSpace(stderr.String())
+		}
+	}
+	x.done(msg)
+}
+
+// done prints the outcome of a command and records it.
+func (x *watchExecutor) done(msg watchExecMessage) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if msg.Error != "" {
+		x.failed++
+	} else if msg.Output == "" && !globalJSON {
+		return
+	}
+	printMsg(msg)
+}
+
+// wait waits for the running commands and reports whether any failed.
+func (x *watchExecutor) wait() bool {
+	close(x.jobs)
+	x.wg.Wait()
+	return x.failed == 0
+}
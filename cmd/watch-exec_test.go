@@ -0,0 +1,55 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWatchExecArgv(t *testing.T) {
+	ev := watchExecEvent{
+		Bucket: "photos",
+		Key:    "2023/cat.jpg",
+		Path:   "https://play.min.io/photos/2023/cat.jpg",
+		Type:   "s3:ObjectCreated:Put",
+		Size:   1024,
+	}
+	testCases := []struct {
+		cmdline  string
+		expected []string
+	}{
+		{"echo done", []string{"echo", "done"}},
+		{"./thumbnail.sh {{.Bucket}} {{.Key}}", []string{"./thumbnail.sh", "photos", "2023/cat.jpg"}},
+		{`logger "{{.Type}} {{.Size}}" {{.Path}}`, []string{"logger", "s3:ObjectCreated:Put 1024", "https://play.min.io/photos/2023/cat.jpg"}},
+		{"echo {{.Metadata.missing}}", []string{"echo", ""}},
+	}
+	for i, testCase := range testCases {
+		args, err := parseFindExec(testCase.cmdline)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error: %v", i+1, err)
+		}
+		argv, e := watchExecArgv(args, ev)
+		if e != nil {
+			t.Fatalf("Test %d: unexpected error: %v", i+1, e)
+		}
+		if !reflect.DeepEqual(argv, testCase.expected) {
+			t.Fatalf("Test %d: expected %q, got %q", i+1, testCase.expected, argv)
+		}
+	}
+}
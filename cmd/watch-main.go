@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	humanize "github.com/dustin/go-humanize"
 	"github.com/fatih/color"
@@ -50,6 +51,20 @@ var watchFlags = []cli.Flag{
 		Name:  "recursive",
 		Usage: "recursively watch for events",
 	},
+	cli.StringFlag{
+		Name:  "exec-template",
+		Usage: "run a local command for every event, {{.Key}}, {{.Bucket}}, {{.Path}}, {{.Type}}, {{.Size}} and {{.Time}} are replaced",
+	},
+	cli.IntFlag{
+		Name:  "exec-workers",
+		Value: 4,
+		Usage: "number of --exec-template commands run in parallel",
+	},
+	cli.DurationFlag{
+		Name:  "exec-timeout",
+		Value: time.Minute,
+		Usage: "kill an --exec-template command running longer than this duration, 0 to disable",
+	},
 }
 
 var watchCmd = cli.Command{
@@ -86,6 +101,12 @@ EXAMPLES:
 
   6. Watch for events on local directory.
      {{.Prompt}} {{.HelpName}} /usr/share
+
+  7. Generate a thumbnail for every new ".jpg" object, running at most 8 commands in parallel.
+     {{.Prompt}} {{.HelpName}} --events put --suffix ".jpg" --exec-workers 8 --exec-template "./thumbnail.sh {{"{{.Bucket}}"}} {{"{{.Key}}"}}" play/photos
+
+  8. Log the type and size of every event, killing the command after 5 seconds.
+     {{.Prompt}} {{.HelpName}} --exec-timeout 5s --exec-template "logger {{"{{.Type}}"}} {{"{{.Key}}"}} {{"{{.Size}}"}}" play/testbucket
 `,
 }
 
@@ -94,6 +115,12 @@ func checkWatchSyntax(ctx *cli.Context) {
 	if len(ctx.Args()) != 1 {
 		showCommandHelpAndExit(ctx, 1) // last argument is exit code
 	}
+	if ctx.IsSet("exec-workers") && ctx.Int("exec-workers") < 1 {
+		fatalIf(errInvalidArgument().Trace(ctx.String("exec-workers")), "--exec-workers must be at least 1.")
+	}
+	if ctx.Duration("exec-timeout") < 0 {
+		fatalIf(errInvalidArgument().Trace(ctx.String("exec-timeout")), "--exec-timeout cannot be negative.")
+	}
 }
 
 // watchMessage container to hold one event notification
@@ -136,6 +163,7 @@ func mainWatch(cliCtx *cli.Context) error {
 	console.SetColor("Size", color.New(color.FgYellow))
 	console.SetColor("EventType", color.New(color.FgCyan, color.Bold))
 	console.SetColor("ObjectName", color.New(color.Bold))
+	console.SetColor("WatchExecErr", color.New(color.FgRed, color.Bold))
 
 	checkWatchSyntax(cliCtx)
 
@@ -162,6 +190,12 @@ func mainWatch(cliCtx *cli.Context) error {
 	ctx, cancelWatch := context.WithCancel(globalContext)
 	defer cancelWatch()
 
+	var executor *watchExecutor
+	if cmdline := cliCtx.String("exec-template"); cmdline != "" {
+		executor, pErr = newWatchExecutor(ctx, cmdline, cliCtx.Int("exec-workers"), cliCtx.Duration("exec-timeout"))
+		fatalIf(pErr, "Unable to parse --exec-template.")
+	}
+
 	// Start watching on events
 	wo, err := s3Client.Watch(ctx, options)
 	fatalIf(err, "Unable to watch on the specified bucket.")
@@ -188,6 +222,10 @@ func mainWatch(cliCtx *cli.Context) error {
 					return
 				}
 				for _, event := range events {
+					if executor != nil {
+						executor.submit(event)
+						continue
+					}
 					msg := watchMessage{}
 					msg.Event.Path = event.Path
 					msg.Event.Size = event.Size
@@ -213,5 +251,8 @@ func mainWatch(cliCtx *cli.Context) error {
 	// Wait on the routine to be finished or exit.
 	wg.Wait()
 
+	if executor != nil && !executor.wait() {
+		return exitStatus(globalErrorExitStatus)
+	}
 	return nil
 }
@@ -3,6 +3,7 @@ module github.com/trinet2005/oss-mc
 go 1.19
 
 require (
+	filippo.io/age v1.0.0
 	github.com/charmbracelet/bubbletea v0.24.2
 	github.com/cheggaaa/pb v1.0.29
 	github.com/dustin/go-humanize v1.0.1
@@ -29,9 +30,11 @@ require (
 	github.com/prometheus/prom2json v1.3.3
 	github.com/rjeczalik/notify v0.9.3
 	github.com/rs/xid v1.5.0
+	github.com/secure-io/sio-go v0.3.1
 	github.com/shirou/gopsutil/v3 v3.23.8
 	github.com/tidwall/gjson v1.16.0
-	golang.org/x/crypto v0.13.0 // indirect
+	go.etcd.io/bbolt v1.3.8
+	golang.org/x/crypto v0.13.0
 	golang.org/x/net v0.15.0
 	golang.org/x/text v0.13.0
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c
@@ -107,7 +110,6 @@ require (
 	github.com/prometheus/common v0.44.0 // indirect
 	github.com/prometheus/procfs v0.11.1
 	github.com/rivo/uniseg v0.4.4 // indirect
-	github.com/secure-io/sio-go v0.3.1 // indirect
 	github.com/sirupsen/logrus v1.9.3 // indirect
 	github.com/tidwall/match v1.1.1 // indirect
 	github.com/tidwall/pretty v1.2.1 // indirect
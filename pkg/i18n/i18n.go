@@ -0,0 +1,190 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package i18n translates the user-facing strings of mc with message
+// catalogs. A catalog maps the English string, format verbs included,
+// to its translation, strings missing from the catalog of the selected
+// locale are printed in English. So are strings whose translation does
+// not have the format verbs of the English string, as formatting them
+// would print garbage or mismatch the arguments.
+package i18n
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"io"
+	"path"
+	"strings"
+	"sync"
+)
+
+//go:embed locales/*.json
+var builtinLocales embed.FS
+
+// Catalog maps English strings to their translation in one locale.
+type Catalog map[string]string
+
+var (
+	mu       sync.RWMutex
+	catalogs = make(map[string]Catalog)
+	current  Catalog
+	locale   string
+)
+
+func init() {
+	entries, e := builtinLocales.ReadDir("locales")
+	if e != nil {
+		panic(e)
+	}
+	for _, entry := range entries {
+		data, e := builtinLocales.ReadFile(path.Join("locales", entry.Name()))
+		if e != nil {
+			panic(e)
+		}
+		if e = Load(strings.TrimSuffix(entry.Name(), ".json"), bytes.NewReader(data)); e != nil {
+			panic(e)
+		}
+	}
+}
+
+// Normalize returns the canonical form of a locale name, e.g. "pt-br" for
+// "pt_BR.UTF-8", or "" for the "C" and "POSIX" locales.
+func Normalize(name string) string {
+	name, _, _ = strings.Cut(name, ".")
+	name, _, _ = strings.Cut(name, "@")
+	name = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), "_", "-"))
+	if name == "c" || name == "posix" {
+		return ""
+	}
+	return name
+}
+
+// FromEnv returns the locale selected by the environment, MC_LANG first
+// then the POSIX locale variables.
+func FromEnv(getenv func(string) string) string {
+	for _, env := range []string{"MC_LANG", "LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := getenv(env); v != "" {
+			return Normalize(v)
+		}
+	}
+	return ""
+}
+
+// formatVerbs returns the format verbs of s, flags, width, precision
+// and argument indexes included, in the order they appear.
+func formatVerbs(s string) (verbs []string) {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			continue
+		}
+		j := i + 1
+		for j < len(s) && strings.IndexByte("+-# 0123456789.*[]", s[j]) >= 0 {
+			j++
+		}
+		if j == len(s) {
+			return append(verbs, s[i:])
+		}
+		if s[j] != '%' || j > i+1 {
+			verbs = append(verbs, s[i:j+1])
+		}
+		i = j
+	}
+	return verbs
+}
+
+// sameFormatVerbs returns true if the translation has the format verbs
+// of msg, in the same order.
+func sameFormatVerbs(msg, translation string) bool {
+	msgVerbs, translationVerbs := formatVerbs(msg), formatVerbs(translation)
+	if len(msgVerbs) != len(translationVerbs) {
+		return false
+	}
+	for i := range msgVerbs {
+		if msgVerbs[i] != translationVerbs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Register adds the translations of c to the catalog of a locale,
+// replacing the existing translations of the same strings. Translations
+// which do not keep the format verbs of their English string are
+// ignored, the English string is printed instead.
+func Register(name string, c Catalog) {
+	name = Normalize(name)
+	mu.Lock()
+	defer mu.Unlock()
+	catalog, ok := catalogs[name]
+	if !ok {
+		catalog = make(Catalog, len(c))
+		catalogs[name] = catalog
+	}
+	for msg, translation := range c {
+		if !sameFormatVerbs(msg, translation) {
+			delete(catalog, msg)
+			continue
+		}
+		catalog[msg] = translation
+	}
+}
+
+// Load registers the catalog of a locale read from a JSON object.
+func Load(name string, r io.Reader) error {
+	var c Catalog
+	if e := json.NewDecoder(r).Decode(&c); e != nil {
+		return e
+	}
+	Register(name, c)
+	return nil
+}
+
+// SetLocale selects the catalog used by T, falling back from a regional
+// locale such as "pt-br" to its language "pt". It returns the locale
+// selected, "" if no catalog matches and strings are printed in English.
+func SetLocale(name string) string {
+	name = Normalize(name)
+	mu.Lock()
+	defer mu.Unlock()
+	current, locale = nil, ""
+	for _, candidate := range []string{name, strings.SplitN(name, "-", 2)[0]} {
+		if c, ok := catalogs[candidate]; ok && candidate != "" {
+			current, locale = c, candidate
+			break
+		}
+	}
+	return locale
+}
+
+// Locale returns the selected locale, "" for English.
+func Locale() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return locale
+}
+
+// T returns the translation of msg in the selected locale, or msg when
+// it has no translation.
+func T(msg string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	if translation, ok := current[msg]; ok && translation != "" {
+		return translation
+	}
+	return msg
+}
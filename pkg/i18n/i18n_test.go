@@ -0,0 +1,140 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package i18n
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	testCases := []struct {
+		name   string
+		expect string
+	}{
+		{"", ""},
+		{"C", ""},
+		{"POSIX", ""},
+		{"de", "de"},
+		{"pt_BR.UTF-8", "pt-br"},
+		{"sr_RS@latin", "sr-rs"},
+		{" en-US ", "en-us"},
+	}
+	for i, testCase := range testCases {
+		if name := Normalize(testCase.name); name != testCase.expect {
+			t.Fatalf("Test %d: expected %q, got %q", i+1, testCase.expect, name)
+		}
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	testCases := []struct {
+		env    map[string]string
+		expect string
+	}{
+		{map[string]string{}, ""},
+		{map[string]string{"LANG": "es_ES.UTF-8"}, "es-es"},
+		{map[string]string{"LANG": "es_ES.UTF-8", "LC_ALL": "fr_FR"}, "fr-fr"},
+		{map[string]string{"LANG": "es_ES.UTF-8", "MC_LANG": "de"}, "de"},
+	}
+	for i, testCase := range testCases {
+		getenv := func(key string) string { return testCase.env[key] }
+		if name := FromEnv(getenv); name != testCase.expect {
+			t.Fatalf("Test %d: expected %q, got %q", i+1, testCase.expect, name)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	defer SetLocale("")
+	if e := Load("xx", strings.NewReader(`{"Hello": "Hallo", "Empty": "", "Hello %s": "Hallo %d", "%d of %s": "%d von %s"}`)); e != nil {
+		t.Fatal(e)
+	}
+	Register("xx_YY", Catalog{"Hello": "Servus"})
+
+	testCases := []struct {
+		locale       string
+		msg          string
+		expectLocale string
+		expect       string
+	}{
+		{"", "Hello", "", "Hello"},
+		{"zz", "Hello", "", "Hello"},
+		{"xx", "Hello", "xx", "Hallo"},
+		{"xx", "Empty", "xx", "Empty"},
+		{"xx", "Missing", "xx", "Missing"},
+		{"xx", "Hello %s", "xx", "Hello %s"},
+		{"xx", "%d of %s", "xx", "%d von %s"},
+		{"xx_ZZ.UTF-8", "Hello", "xx", "Hallo"},
+		{"xx-YY", "Hello", "xx-yy", "Servus"},
+		{"de", "Upgrade aborted!", "de", "Aktualisierung abgebrochen!"},
+	}
+	for i, testCase := range testCases {
+		if locale := SetLocale(testCase.locale); locale != testCase.expectLocale {
+			t.Fatalf("Test %d: expected locale %q, got %q", i+1, testCase.expectLocale, locale)
+		}
+		if msg := T(testCase.msg); msg != testCase.expect {
+			t.Fatalf("Test %d: expected %q, got %q", i+1, testCase.expect, msg)
+		}
+	}
+}
+
+func TestFormatVerbs(t *testing.T) {
+	testCases := []struct {
+		s      string
+		expect []string
+	}{
+		{"", nil},
+		{"100%% done", nil},
+		{"%s of %d", []string{"%s", "%d"}},
+		{"%-10s|%5.2f%%", []string{"%-10s", "%5.2f"}},
+		{"%[2]s %[1]s", []string{"%[2]s", "%[1]s"}},
+		{"trailing %", []string{"%"}},
+	}
+	for i, testCase := range testCases {
+		verbs := formatVerbs(testCase.s)
+		if strings.Join(verbs, ",") != strings.Join(testCase.expect, ",") || len(verbs) != len(testCase.expect) {
+			t.Fatalf("Test %d: expected %q, got %q", i+1, testCase.expect, verbs)
+		}
+	}
+}
+
+// TestBuiltinCatalogs checks the translations keep the format verbs of
+// the English strings, the ones which do not would be ignored.
+func TestBuiltinCatalogs(t *testing.T) {
+	entries, e := builtinLocales.ReadDir("locales")
+	if e != nil {
+		t.Fatal(e)
+	}
+	for _, entry := range entries {
+		data, e := builtinLocales.ReadFile("locales/" + entry.Name())
+		if e != nil {
+			t.Fatal(e)
+		}
+		var catalog Catalog
+		if e = json.Unmarshal(data, &catalog); e != nil {
+			t.Fatal(e)
+		}
+		for msg, translation := range catalog {
+			if !sameFormatVerbs(msg, translation) {
+				t.Fatalf("%s: %q does not keep the format verbs of %q", entry.Name(), translation, msg)
+			}
+		}
+	}
+}